@@ -22,26 +22,11 @@ type CryptoProvider struct {
 
 // GetToken retrieves the registry JWT token using cryptographic authentication
 func (c *CryptoProvider) GetToken(ctx context.Context) (string, error) {
-	if c.domain == "" {
-		return "", fmt.Errorf("%s domain is required", c.authMethod)
-	}
-
-	if c.hexSeed == "" {
-		return "", fmt.Errorf("%s private key (hex seed) is required", c.authMethod)
-	}
-
-	// Decode hex seed to private key
-	seedBytes, err := hex.DecodeString(c.hexSeed)
+	privateKey, err := c.loadPrivateKey()
 	if err != nil {
-		return "", fmt.Errorf("invalid hex seed format: %w", err)
-	}
-
-	if len(seedBytes) != ed25519.SeedSize {
-		return "", fmt.Errorf("invalid seed length: expected %d bytes, got %d", ed25519.SeedSize, len(seedBytes))
+		return "", err
 	}
 
-	privateKey := ed25519.NewKeyFromSeed(seedBytes)
-
 	// Generate current timestamp
 	timestamp := time.Now().UTC().Format(time.RFC3339)
 
@@ -50,7 +35,11 @@ func (c *CryptoProvider) GetToken(ctx context.Context) (string, error) {
 	signedTimestamp := hex.EncodeToString(signature)
 
 	// Exchange signature for registry token
-	registryToken, err := c.exchangeTokenForRegistry(ctx, c.domain, timestamp, signedTimestamp)
+	registryToken, err := c.exchangeTokenForRegistry(ctx, map[string]string{
+		"domain":           c.domain,
+		"timestamp":        timestamp,
+		"signed_timestamp": signedTimestamp,
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to exchange %s signature: %w", c.authMethod, err)
 	}
@@ -58,6 +47,29 @@ func (c *CryptoProvider) GetToken(ctx context.Context) (string, error) {
 	return registryToken, nil
 }
 
+// loadPrivateKey validates the configured domain and decodes the hex seed into an Ed25519 private key
+func (c *CryptoProvider) loadPrivateKey() (ed25519.PrivateKey, error) {
+	if c.domain == "" {
+		return nil, fmt.Errorf("%s domain is required", c.authMethod)
+	}
+
+	if c.hexSeed == "" {
+		return nil, fmt.Errorf("%s private key (hex seed) is required", c.authMethod)
+	}
+
+	// Decode hex seed to private key
+	seedBytes, err := hex.DecodeString(c.hexSeed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex seed format: %w", err)
+	}
+
+	if len(seedBytes) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid seed length: expected %d bytes, got %d", ed25519.SeedSize, len(seedBytes))
+	}
+
+	return ed25519.NewKeyFromSeed(seedBytes), nil
+}
+
 // NeedsLogin always returns false for cryptographic auth since no interactive login is needed
 func (c *CryptoProvider) NeedsLogin() bool {
 	return false
@@ -69,28 +81,29 @@ func (c *CryptoProvider) Login(_ context.Context) error {
 }
 
 // exchangeTokenForRegistry exchanges signature for a registry JWT token
-func (c *CryptoProvider) exchangeTokenForRegistry(ctx context.Context, domain, timestamp, signedTimestamp string) (string, error) {
-	if c.registryURL == "" {
-		return "", fmt.Errorf("registry URL is required for token exchange")
+func (c *CryptoProvider) exchangeTokenForRegistry(ctx context.Context, payload map[string]string) (string, error) {
+	var tokenResp RegistryTokenResponse
+	if err := c.postJSON(ctx, fmt.Sprintf("/v0/auth/%s", c.authMethod), payload, &tokenResp); err != nil {
+		return "", fmt.Errorf("token exchange failed: %w", err)
 	}
 
-	// Prepare the request body
-	payload := map[string]string{
-		"domain":           domain,
-		"timestamp":        timestamp,
-		"signed_timestamp": signedTimestamp,
+	return tokenResp.RegistryToken, nil
+}
+
+// postJSON sends a JSON payload to the given registry path and decodes the JSON response into out
+func (c *CryptoProvider) postJSON(ctx context.Context, path string, payload any, out any) error {
+	if c.registryURL == "" {
+		return fmt.Errorf("registry URL is required for token exchange")
 	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Make the token exchange request
-	exchangeURL := fmt.Sprintf("%s/v0/auth/%s", c.registryURL, c.authMethod)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, exchangeURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.registryURL+path, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
@@ -98,24 +111,22 @@ func (c *CryptoProvider) exchangeTokenForRegistry(ctx context.Context, domain, t
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("token exchange failed with status %d: %s", resp.StatusCode, body)
+		return fmt.Errorf("status %d: %s", resp.StatusCode, body)
 	}
 
-	var tokenResp RegistryTokenResponse
-	err = json.Unmarshal(body, &tokenResp)
-	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	return tokenResp.RegistryToken, nil
+	return nil
 }
@@ -1,9 +1,22 @@
 package auth
 
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+)
+
 type DNSProvider struct {
 	*CryptoProvider
 }
 
+// dnsChallengeResponse is the server-issued challenge returned by /v0/auth/dns/challenge
+type dnsChallengeResponse struct {
+	Nonce     string `json:"nonce"`
+	Challenge string `json:"challenge"`
+}
+
 // NewDNSProvider creates a new DNS-based auth provider
 func NewDNSProvider(registryURL, domain, hexSeed string) Provider {
 	return &DNSProvider{
@@ -16,6 +29,32 @@ func NewDNSProvider(registryURL, domain, hexSeed string) Provider {
 	}
 }
 
+// GetToken requests a single-use challenge from the registry, signs it, and exchanges it for a registry JWT token
+func (d *DNSProvider) GetToken(ctx context.Context) (string, error) {
+	privateKey, err := d.loadPrivateKey()
+	if err != nil {
+		return "", err
+	}
+
+	var challenge dnsChallengeResponse
+	if err := d.postJSON(ctx, "/v0/auth/dns/challenge", map[string]string{"domain": d.domain}, &challenge); err != nil {
+		return "", fmt.Errorf("failed to request dns challenge: %w", err)
+	}
+
+	signature := ed25519.Sign(privateKey, []byte(challenge.Challenge))
+
+	registryToken, err := d.exchangeTokenForRegistry(ctx, map[string]string{
+		"domain":           d.domain,
+		"nonce":            challenge.Nonce,
+		"signed_challenge": hex.EncodeToString(signature),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange dns signature: %w", err)
+	}
+
+	return registryToken, nil
+}
+
 // Name returns the name of this auth provider
 func (d *DNSProvider) Name() string {
 	return "dns"
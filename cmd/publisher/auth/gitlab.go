@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+type GitLabProvider struct {
+	registryURL string
+	token       string
+}
+
+// NewGitLabProvider creates a new GitLab provider. An empty token falls back to the
+// GITLAB_TOKEN environment variable, then to CI_JOB_TOKEN when running in GitLab CI
+func NewGitLabProvider(registryURL, token string) Provider {
+	return &GitLabProvider{
+		registryURL: registryURL,
+		token:       token,
+	}
+}
+
+// GetToken exchanges the GitLab token for a registry JWT token
+func (g *GitLabProvider) GetToken(ctx context.Context) (string, error) {
+	token, tokenType := g.token, "personal"
+	if token == "" {
+		token = os.Getenv("GITLAB_TOKEN")
+	}
+	if token == "" {
+		token, tokenType = os.Getenv("CI_JOB_TOKEN"), "job"
+	}
+	if token == "" {
+		return "", fmt.Errorf("GitLab token required: pass --token, set GITLAB_TOKEN, or run in GitLab CI where CI_JOB_TOKEN is set")
+	}
+
+	return g.exchangeTokenForRegistry(ctx, token, tokenType)
+}
+
+// NeedsLogin always returns false since the GitLab token is supplied up front
+func (g *GitLabProvider) NeedsLogin() bool {
+	return false
+}
+
+// Login is not needed since the GitLab token is supplied up front
+func (g *GitLabProvider) Login(_ context.Context) error {
+	return nil
+}
+
+// Name returns the name of this auth provider
+func (g *GitLabProvider) Name() string {
+	return "gitlab"
+}
+
+// exchangeTokenForRegistry exchanges a GitLab token for a registry JWT token
+func (g *GitLabProvider) exchangeTokenForRegistry(ctx context.Context, gitlabToken, tokenType string) (string, error) {
+	if g.registryURL == "" {
+		return "", fmt.Errorf("registry URL is required for token exchange")
+	}
+
+	jsonData, err := json.Marshal(map[string]string{
+		"gitlab_token": gitlabToken,
+		"token_type":   tokenType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	exchangeURL := g.registryURL + "/v0/auth/gitlab-at"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, exchangeURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token exchange failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	var tokenResp RegistryTokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return tokenResp.RegistryToken, nil
+}
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/modelcontextprotocol/registry/pkg/configschema"
+)
+
+// ConfigSchemaCommand prints the JSON Schema for the configuration of a local server.json, the
+// same schema the registry serves at /v0/servers/{id}/config-schema once it is published
+func ConfigSchemaCommand(args []string) error {
+	serverFile := "server.json"
+	if len(args) > 0 {
+		serverFile = args[0]
+	}
+
+	_, serverJSON, err := readServerJSON(serverFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(configschema.Generate(*serverJSON), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error rendering schema: %w", err)
+	}
+	_, _ = fmt.Fprintln(os.Stdout, string(data))
+	return nil
+}
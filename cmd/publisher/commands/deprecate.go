@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// replacedByKey is the publisher-provided _meta key pointing at a deprecated server's replacement
+const replacedByKey = "replaced_by"
+
+// statusChange describes a deprecate or undeprecate run
+type statusChange struct {
+	name       string
+	version    string // empty for all versions
+	status     model.Status
+	replacedBy string
+	yes        bool
+}
+
+// DeprecateCommand marks versions of a server as deprecated
+func DeprecateCommand(args []string) error {
+	change, err := parseStatusArgs("deprecate", model.StatusDeprecated, args)
+	if err != nil {
+		return err
+	}
+	return runStatusChange(change, os.Stdin, os.Stdout)
+}
+
+// UndeprecateCommand marks deprecated versions of a server as active again
+func UndeprecateCommand(args []string) error {
+	change, err := parseStatusArgs("undeprecate", model.StatusActive, args)
+	if err != nil {
+		return err
+	}
+	return runStatusChange(change, os.Stdin, os.Stdout)
+}
+
+// parseStatusArgs parses `<server-name> [flags]` for the deprecate and undeprecate commands
+func parseStatusArgs(command string, status model.Status, args []string) (statusChange, error) {
+	change := statusChange{status: status}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		change.name = args[0]
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.StringVar(&change.version, "version", "", "Only change this version (default: all versions)")
+	flags.BoolVar(&change.yes, "yes", false, "Skip the confirmation prompt")
+	if status == model.StatusDeprecated {
+		flags.StringVar(&change.replacedBy, "replaced-by", "", "Name of the server that replaces this one")
+	}
+	if err := flags.Parse(args); err != nil {
+		return change, err
+	}
+	if change.name == "" && flags.NArg() > 0 {
+		change.name = flags.Arg(0)
+	}
+
+	if change.name == "" {
+		return change, fmt.Errorf("server name required\n\nUsage: mcp-publisher %s <server-name> [--version=VERSION] [--yes]", command)
+	}
+	return change, nil
+}
+
+func runStatusChange(change statusChange, in io.Reader, out io.Writer) error {
+	token, registryURL, err := loadSavedToken()
+	if err != nil {
+		return err
+	}
+	return changeStatus(registryURL, token, change, in, out)
+}
+
+// changeStatus sets the status of the selected server versions through the edit endpoint
+func changeStatus(registryURL, token string, change statusChange, in io.Reader, out io.Writer) error {
+	registryURL = strings.TrimSuffix(registryURL, "/")
+
+	versions, err := fetchServerVersions(registryURL, change.name, change.version)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		if change.version != "" {
+			return fmt.Errorf("server %s has no version %s", change.name, change.version)
+		}
+		return fmt.Errorf("server %s not found", change.name)
+	}
+
+	// Deleted versions cannot change status, and versions already in the target status need no change
+	var pending []apiv0.ServerJSON
+	for _, server := range versions {
+		current := server.Status
+		if current == "" {
+			current = model.StatusActive
+		}
+		if current == model.StatusDeleted || (current == change.status && replacedBy(&server) == change.replacedBy) {
+			continue
+		}
+		pending = append(pending, server)
+	}
+	if len(pending) == 0 {
+		_, _ = fmt.Fprintf(out, "Nothing to do: no versions of %s need to be %s\n", change.name, change.status)
+		return nil
+	}
+
+	if !change.yes {
+		confirmed, err := confirmStatusChange(change, pending, in, out)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			_, _ = fmt.Fprintln(out, "Aborted")
+			return nil
+		}
+	}
+
+	for i := range pending {
+		updated, err := putServerStatus(registryURL, token, &pending[i], change)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "✓ %s %s is now %s\n", updated.Name, updated.Version, updated.Status)
+	}
+	return nil
+}
+
+// confirmStatusChange lists the versions that will change and asks the user to confirm
+func confirmStatusChange(change statusChange, pending []apiv0.ServerJSON, in io.Reader, out io.Writer) (bool, error) {
+	_, _ = fmt.Fprintf(out, "The following versions of %s will be marked %s:\n", change.name, change.status)
+	for _, server := range pending {
+		_, _ = fmt.Fprintf(out, "  %s (currently %s)\n", server.Version, server.Status)
+	}
+	if change.replacedBy != "" {
+		_, _ = fmt.Fprintf(out, "Replaced by: %s\n", change.replacedBy)
+	}
+	_, _ = fmt.Fprint(out, "Continue? [y/N]: ")
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// fetchServerVersions lists every version of the named server, or only the given version
+func fetchServerVersions(registryURL, name, version string) ([]apiv0.ServerJSON, error) {
+	var versions []apiv0.ServerJSON
+	cursor := ""
+	for {
+		query := url.Values{"search": {name}, "limit": {"100"}}
+		if version != "" {
+			query.Set("version", version)
+		}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, registryURL+"/v0/servers?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+		resp, err := registryClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching server versions: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, registryError(resp.StatusCode, body)
+		}
+
+		var page apiv0.ServerListResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("invalid server list response: %w", err)
+		}
+		// search is a substring match, so keep only this server's versions
+		for _, server := range page.Servers {
+			if server.Name == name {
+				versions = append(versions, server)
+			}
+		}
+
+		if page.Metadata.NextCursor == "" {
+			return versions, nil
+		}
+		cursor = page.Metadata.NextCursor
+	}
+}
+
+// putServerStatus sends a server version back to the edit endpoint with its new status
+func putServerStatus(registryURL, token string, server *apiv0.ServerJSON, change statusChange) (*apiv0.ServerJSON, error) {
+	id := server.GetID()
+	edited := *server
+	edited.Status = change.status
+	edited.Meta = editableMeta(server.Meta, change)
+
+	jsonData, err := json.Marshal(edited)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, registryURL+"/v0/servers/"+url.PathEscape(id), bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := registryClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, errors.New("registry token is invalid or expired. Run 'mcp-publisher login <method>' again")
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("your token does not have edit permission for %s: changing a server's status needs an edit permission covering the %s/* namespace",
+			server.Name, serverNamespace(server.Name))
+	default:
+		return nil, fmt.Errorf("failed to update %s %s: %w", server.Name, server.Version, registryError(resp.StatusCode, body))
+	}
+
+	var updated apiv0.ServerJSON
+	if err := json.Unmarshal(body, &updated); err != nil {
+		return nil, fmt.Errorf("invalid edit response: %w", err)
+	}
+	return &updated, nil
+}
+
+// editableMeta returns the _meta to send with an edit: registry metadata is dropped, since the
+// registry maintains it, and the publisher-provided replacement pointer is set or cleared
+func editableMeta(meta *apiv0.ServerMeta, change statusChange) *apiv0.ServerMeta {
+	provided := make(map[string]interface{})
+	if meta != nil {
+		for key, value := range meta.PublisherProvided {
+			provided[key] = value
+		}
+	}
+
+	delete(provided, replacedByKey)
+	if change.status == model.StatusDeprecated && change.replacedBy != "" {
+		provided[replacedByKey] = change.replacedBy
+	}
+
+	if len(provided) == 0 {
+		return nil
+	}
+	return &apiv0.ServerMeta{PublisherProvided: provided}
+}
+
+// replacedBy returns the replacement recorded on a server version, if any
+func replacedBy(server *apiv0.ServerJSON) string {
+	if server.Meta == nil {
+		return ""
+	}
+	name, _ := server.Meta.PublisherProvided[replacedByKey].(string)
+	return name
+}
+
+// serverNamespace returns the namespace part of a server name, e.g. io.github.user for io.github.user/server
+func serverNamespace(name string) string {
+	namespace, _, _ := strings.Cut(name, "/")
+	return namespace
+}
@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// stubRegistry serves the list and edit endpoints over a fixed set of server versions
+type stubRegistry struct {
+	mu       sync.Mutex
+	servers  []apiv0.ServerJSON
+	edits    map[string]apiv0.ServerJSON // request bodies by server ID
+	editCode int                         // status returned by the edit endpoint, 200 when zero
+}
+
+func newStubRegistry(t *testing.T, servers ...apiv0.ServerJSON) (*stubRegistry, *httptest.Server) {
+	t.Helper()
+
+	stub := &stubRegistry{servers: servers, edits: map[string]apiv0.ServerJSON{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v0/servers", func(w http.ResponseWriter, r *http.Request) {
+		response := apiv0.ServerListResponse{Servers: []apiv0.ServerJSON{}}
+		for _, server := range stub.servers {
+			if !strings.Contains(server.Name, r.URL.Query().Get("search")) {
+				continue
+			}
+			if version := r.URL.Query().Get("version"); version != "" && server.Version != version {
+				continue
+			}
+			response.Servers = append(response.Servers, server)
+		}
+		response.Metadata.Count = len(response.Servers)
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	mux.HandleFunc("PUT /v0/servers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		if stub.editCode != 0 {
+			w.WriteHeader(stub.editCode)
+			_, _ = w.Write([]byte(`{"title":"Forbidden","status":403,"detail":"You do not have edit permissions for this server"}`))
+			return
+		}
+
+		var body apiv0.ServerJSON
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		stub.mu.Lock()
+		stub.edits[r.PathValue("id")] = body
+		stub.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(body)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return stub, server
+}
+
+func publishedServer(name, version, id string, status model.Status) apiv0.ServerJSON {
+	return apiv0.ServerJSON{
+		Name:        name,
+		Description: "Test server",
+		Version:     version,
+		Status:      status,
+		Meta: &apiv0.ServerMeta{
+			Official:          &apiv0.RegistryExtensions{ID: id},
+			PublisherProvided: map[string]interface{}{"tool": "test"},
+		},
+	}
+}
+
+func TestChangeStatus_Deprecate(t *testing.T) {
+	stub, server := newStubRegistry(t,
+		publishedServer("io.github.example/weather", "1.0.0", "id-1", model.StatusActive),
+		publishedServer("io.github.example/weather", "1.1.0", "id-2", model.StatusActive),
+		publishedServer("io.github.example/weather", "0.9.0", "id-3", model.StatusDeleted),
+		publishedServer("io.github.example/weather-pro", "1.0.0", "id-4", model.StatusActive),
+	)
+
+	var out bytes.Buffer
+	change := statusChange{name: "io.github.example/weather", status: model.StatusDeprecated, replacedBy: "io.github.example/forecast", yes: true}
+	require.NoError(t, changeStatus(server.URL, "test-token", change, strings.NewReader(""), &out))
+
+	// Every non-deleted version of exactly this server is edited
+	require.Len(t, stub.edits, 2)
+	for _, id := range []string{"id-1", "id-2"} {
+		body := stub.edits[id]
+		assert.Equal(t, model.StatusDeprecated, body.Status)
+		require.NotNil(t, body.Meta)
+		assert.Nil(t, body.Meta.Official, "registry metadata is not sent back")
+		assert.Equal(t, "io.github.example/forecast", body.Meta.PublisherProvided["replaced_by"])
+		assert.Equal(t, "test", body.Meta.PublisherProvided["tool"])
+	}
+	assert.Contains(t, out.String(), "✓ io.github.example/weather 1.0.0 is now deprecated")
+	assert.Contains(t, out.String(), "✓ io.github.example/weather 1.1.0 is now deprecated")
+}
+
+func TestChangeStatus_UndeprecateSingleVersion(t *testing.T) {
+	deprecated := publishedServer("io.github.example/weather", "1.0.0", "id-1", model.StatusDeprecated)
+	deprecated.Meta.PublisherProvided["replaced_by"] = "io.github.example/forecast"
+	stub, server := newStubRegistry(t,
+		deprecated,
+		publishedServer("io.github.example/weather", "1.1.0", "id-2", model.StatusDeprecated),
+	)
+
+	var out bytes.Buffer
+	change := statusChange{name: "io.github.example/weather", version: "1.0.0", status: model.StatusActive, yes: true}
+	require.NoError(t, changeStatus(server.URL, "test-token", change, strings.NewReader(""), &out))
+
+	require.Len(t, stub.edits, 1)
+	body := stub.edits["id-1"]
+	assert.Equal(t, model.StatusActive, body.Status)
+	assert.NotContains(t, body.Meta.PublisherProvided, "replaced_by")
+	assert.Contains(t, out.String(), "is now active")
+}
+
+func TestChangeStatus_Confirmation(t *testing.T) {
+	tests := []struct {
+		name       string
+		answer     string
+		wantEdited bool
+	}{
+		{name: "confirmed", answer: "y\n", wantEdited: true},
+		{name: "confirmed in full", answer: "YES\n", wantEdited: true},
+		{name: "declined", answer: "n\n"},
+		{name: "empty answer declines", answer: "\n"},
+		{name: "no input declines", answer: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub, server := newStubRegistry(t, publishedServer("io.github.example/weather", "1.0.0", "id-1", model.StatusActive))
+
+			var out bytes.Buffer
+			change := statusChange{name: "io.github.example/weather", status: model.StatusDeprecated}
+			require.NoError(t, changeStatus(server.URL, "test-token", change, strings.NewReader(tt.answer), &out))
+
+			assert.Contains(t, out.String(), "will be marked deprecated")
+			assert.Contains(t, out.String(), "Continue? [y/N]")
+			if tt.wantEdited {
+				assert.Len(t, stub.edits, 1)
+			} else {
+				assert.Empty(t, stub.edits)
+				assert.Contains(t, out.String(), "Aborted")
+			}
+		})
+	}
+}
+
+func TestChangeStatus_Errors(t *testing.T) {
+	t.Run("permission error names the namespace", func(t *testing.T) {
+		stub, server := newStubRegistry(t, publishedServer("io.github.example/weather", "1.0.0", "id-1", model.StatusActive))
+		stub.editCode = http.StatusForbidden
+
+		change := statusChange{name: "io.github.example/weather", status: model.StatusDeprecated, yes: true}
+		err := changeStatus(server.URL, "test-token", change, strings.NewReader(""), &bytes.Buffer{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "io.github.example/* namespace")
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		_, server := newStubRegistry(t)
+		change := statusChange{name: "io.github.example/missing", status: model.StatusDeprecated, yes: true}
+		err := changeStatus(server.URL, "test-token", change, strings.NewReader(""), &bytes.Buffer{})
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("nothing to change", func(t *testing.T) {
+		stub, server := newStubRegistry(t, publishedServer("io.github.example/weather", "1.0.0", "id-1", model.StatusDeprecated))
+		var out bytes.Buffer
+		change := statusChange{name: "io.github.example/weather", status: model.StatusDeprecated, yes: true}
+		require.NoError(t, changeStatus(server.URL, "test-token", change, strings.NewReader(""), &out))
+		assert.Empty(t, stub.edits)
+		assert.Contains(t, out.String(), "Nothing to do")
+	})
+}
+
+func TestParseStatusArgs(t *testing.T) {
+	change, err := parseStatusArgs("deprecate", model.StatusDeprecated, []string{"io.github.example/weather", "--version=1.0.0", "--replaced-by", "io.github.example/forecast", "--yes"})
+	require.NoError(t, err)
+	assert.Equal(t, statusChange{
+		name:       "io.github.example/weather",
+		version:    "1.0.0",
+		status:     model.StatusDeprecated,
+		replacedBy: "io.github.example/forecast",
+		yes:        true,
+	}, change)
+
+	_, err = parseStatusArgs("undeprecate", model.StatusActive, []string{"--yes"})
+	assert.ErrorContains(t, err, "server name required")
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,49 +17,68 @@ import (
 	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
-func InitCommand() error {
+// initOptions describes an init run
+type initOptions struct {
+	from        string // server.json to seed the template from, when forking a server
+	interactive bool
+}
+
+// InitCommand creates a server.json template in the current directory
+func InitCommand(args []string) error {
+	var opts initOptions
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	flags.StringVar(&opts.from, "from", "", "Seed the template from an existing server.json, such as one printed by 'mcp-publisher show --raw'")
+	flags.BoolVar(&opts.interactive, "interactive", false, "Prompt for arguments and environment variables one at a time")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	return runInit(opts, os.Stdin, os.Stdout)
+}
+
+// runInit writes server.json, reading prompt answers from in. When inputs are entered or
+// seeded, it also writes server.json.md, which explains each of them.
+func runInit(opts initOptions, in io.Reader, out io.Writer) error {
 	// Check if server.json already exists
 	if _, err := os.Stat("server.json"); err == nil {
 		return errors.New("server.json already exists")
 	}
 
-	// Try to detect values from environment
-	name := detectServerName()
-	description := detectDescription()
-	version := "1.0.0"
-	repoURL := detectRepoURL()
-	repoSource := "github"
-	if repoURL != "" && !strings.Contains(repoURL, "github.com") {
-		if strings.Contains(repoURL, "gitlab.com") {
-			repoSource = "gitlab"
-		} else {
-			repoSource = "git"
+	var server apiv0.ServerJSON
+	if opts.from != "" {
+		seeded, err := seedServerJSON(opts.from)
+		if err != nil {
+			return err
 		}
-	}
-
-	packageType := detectPackageType()
-	packageIdentifier := detectPackageIdentifier(name, packageType)
-
-	// Create example environment variables
-	envVars := []model.KeyValueInput{
-		{
-			Name: "YOUR_API_KEY",
-			InputWithVariables: model.InputWithVariables{
-				Input: model.Input{
-					Description: "Your API key for the service",
-					IsRequired:  true,
-					IsSecret:    true,
-					Format:      model.FormatString,
+		server = seeded
+	} else {
+		// Create an example environment variable, unless the user is about to enter their own
+		var envVars []model.KeyValueInput
+		if !opts.interactive {
+			envVars = []model.KeyValueInput{
+				{
+					Name: "YOUR_API_KEY",
+					InputWithVariables: model.InputWithVariables{
+						Input: model.Input{
+							Description: "Your API key for the service",
+							IsRequired:  true,
+							IsSecret:    true,
+							Format:      model.FormatString,
+						},
+					},
 				},
-			},
-		},
+			}
+		}
+		server = detectServerJSON(envVars)
 	}
 
-	// Create the server structure
-	server := createServerJSON(
-		name, description, version, repoURL, repoSource,
-		packageType, packageIdentifier, version, envVars,
-	)
+	if opts.interactive {
+		if len(server.Packages) == 0 {
+			server.Packages = detectServerJSON(nil).Packages
+		}
+		if err := promptInputs(&server.Packages[0], newPrompter(in, out)); err != nil {
+			return err
+		}
+	}
 
 	// Write to file
 	jsonData, err := json.MarshalIndent(server, "", "  ")
@@ -69,19 +90,77 @@ func InitCommand() error {
 	if err != nil {
 		return fmt.Errorf("error writing file: %w", err)
 	}
+	_, _ = fmt.Fprintln(out, "Created server.json")
+
+	if opts.interactive || opts.from != "" {
+		preview, err := renderInputsPreview(&server)
+		if err != nil {
+			return err
+		}
+		if preview != "" {
+			if err := os.WriteFile("server.json.md", []byte(preview), 0600); err != nil {
+				return fmt.Errorf("error writing file: %w", err)
+			}
+			_, _ = fmt.Fprintln(out, "Created server.json.md, which explains each argument and environment variable")
+		}
+	}
 
-	_, _ = fmt.Fprintln(os.Stdout, "Created server.json")
-	_, _ = fmt.Fprintln(os.Stdout, "\nEdit server.json to update:")
-	_, _ = fmt.Fprintln(os.Stdout, "  • Server name and description")
-	_, _ = fmt.Fprintln(os.Stdout, "  • Package details")
-	_, _ = fmt.Fprintln(os.Stdout, "  • Environment variables")
-	_, _ = fmt.Fprintln(os.Stdout, "\nThen publish with:")
-	_, _ = fmt.Fprintln(os.Stdout, "  mcp-publisher login github  # or your preferred auth method")
-	_, _ = fmt.Fprintln(os.Stdout, "  mcp-publisher publish")
+	_, _ = fmt.Fprintln(out, "\nEdit server.json to update:")
+	_, _ = fmt.Fprintln(out, "  • Server name and description")
+	_, _ = fmt.Fprintln(out, "  • Package details")
+	_, _ = fmt.Fprintln(out, "  • Environment variables")
+	_, _ = fmt.Fprintln(out, "\nThen publish with:")
+	_, _ = fmt.Fprintln(out, "  mcp-publisher login github  # or your preferred auth method")
+	_, _ = fmt.Fprintln(out, "  mcp-publisher publish")
 
 	return nil
 }
 
+// detectServerJSON builds a template from what can be detected in the current directory
+func detectServerJSON(envVars []model.KeyValueInput) apiv0.ServerJSON {
+	name := detectServerName()
+	packageType := detectPackageType()
+	repository := detectRepository()
+	return createServerJSON(
+		name, detectDescription(), "1.0.0", repository.URL, repository.Source,
+		packageType, detectPackageIdentifier(name, packageType), "1.0.0", envVars,
+	)
+}
+
+// seedServerJSON starts a template from an existing server.json. The packages, remotes and
+// their inputs are kept, while the name, repository and version are detected afresh, since
+// a fork is published as a new server.
+func seedServerJSON(path string) (apiv0.ServerJSON, error) {
+	_, server, err := readServerJSON(path)
+	if err != nil {
+		return apiv0.ServerJSON{}, err
+	}
+
+	server.Name = detectServerName()
+	server.Repository = detectRepository()
+	server.Version = "1.0.0"
+	server.Status = model.StatusActive
+	server.Meta = nil
+	for i := range server.Packages {
+		server.Packages[i].Version = server.Version
+	}
+	return *server, nil
+}
+
+// detectRepository finds the repository the server is published from
+func detectRepository() model.Repository {
+	repoURL := detectRepoURL()
+	repoSource := "github"
+	if repoURL != "" && !strings.Contains(repoURL, "github.com") {
+		if strings.Contains(repoURL, "gitlab.com") {
+			repoSource = "gitlab"
+		} else {
+			repoSource = "git"
+		}
+	}
+	return model.Repository{URL: repoURL, Source: repoSource}
+}
+
 func getNameFromPackageJSON() string {
 	data, err := os.ReadFile("package.json")
 	if err != nil {
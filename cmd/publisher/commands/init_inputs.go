@@ -0,0 +1,313 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+var (
+	// envVarNameRegex matches the names shells accept for environment variables
+	envVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// placeholderRegex matches the {placeholders} in an input's value
+	placeholderRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+)
+
+// prompter asks questions on out and reads the answers from in, one per line
+type prompter struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{scanner: bufio.NewScanner(in), out: out}
+}
+
+// ask returns the trimmed answer, or fallback for an empty one. It returns io.EOF once the
+// input is exhausted.
+func (p *prompter) ask(question, fallback string) (string, error) {
+	if fallback != "" {
+		_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, fallback)
+	} else {
+		_, _ = fmt.Fprintf(p.out, "%s: ", question)
+	}
+	if !p.scanner.Scan() {
+		if err := p.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	if answer := strings.TrimSpace(p.scanner.Text()); answer != "" {
+		return answer, nil
+	}
+	return fallback, nil
+}
+
+// confirm asks a yes/no question, defaulting to no
+func (p *prompter) confirm(question string) (bool, error) {
+	answer, err := p.ask(question+" (y/N)", "")
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// choose asks until the answer is one of choices, defaulting to the first
+func (p *prompter) choose(question string, choices ...string) (string, error) {
+	for {
+		answer, err := p.ask(fmt.Sprintf("%s (%s)", question, strings.Join(choices, "/")), choices[0])
+		if err != nil {
+			return "", err
+		}
+		if slices.Contains(choices, answer) {
+			return answer, nil
+		}
+		_, _ = fmt.Fprintf(p.out, "Please answer one of: %s\n", strings.Join(choices, ", "))
+	}
+}
+
+// promptInputs adds arguments and environment variables to pkg until the user is done. Each
+// entry is validated as soon as it is complete; an invalid one is reported and not added.
+func promptInputs(pkg *model.Package, p *prompter) error {
+	_, _ = fmt.Fprintf(p.out, "Add the arguments and environment variables %s takes.\n", pkg.Identifier)
+	for {
+		kind, err := p.choose("Add an input", "done", "argument", "env")
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch kind {
+		case "done":
+			return nil
+		case "argument":
+			target, err := p.choose("Passed to the package, or to its runtime (such as node or docker)", "package", "runtime")
+			if err != nil {
+				return err
+			}
+			arg, err := promptArgument(p)
+			if err != nil {
+				return err
+			}
+			if err := validators.ValidateArgument(&arg); err != nil {
+				_, _ = fmt.Fprintf(p.out, "✗ Argument not added: %v\n", err)
+				continue
+			}
+			if target == "runtime" {
+				pkg.RuntimeArguments = append(pkg.RuntimeArguments, arg)
+			} else {
+				pkg.PackageArguments = append(pkg.PackageArguments, arg)
+			}
+			_, _ = fmt.Fprintf(p.out, "✓ Added %s argument %s\n", target, argumentLabel(&arg))
+		case "env":
+			env, err := promptEnvironmentVariable(p)
+			if err != nil {
+				return err
+			}
+			if err := validateEnvironmentVariable(pkg, &env); err != nil {
+				_, _ = fmt.Fprintf(p.out, "✗ Environment variable not added: %v\n", err)
+				continue
+			}
+			pkg.EnvironmentVariables = append(pkg.EnvironmentVariables, env)
+			_, _ = fmt.Fprintf(p.out, "✓ Added environment variable %s\n", env.Name)
+		}
+	}
+}
+
+// promptArgument asks for a named or positional argument
+func promptArgument(p *prompter) (model.Argument, error) {
+	var arg model.Argument
+	argType, err := p.choose("Argument type", string(model.ArgumentTypeNamed), string(model.ArgumentTypePositional))
+	if err != nil {
+		return arg, err
+	}
+	arg.Type = model.ArgumentType(argType)
+
+	if arg.Type == model.ArgumentTypeNamed {
+		if arg.Name, err = p.ask("Flag name, without a value (such as --port)", ""); err != nil {
+			return arg, err
+		}
+	} else {
+		if arg.ValueHint, err = p.ask("Value hint, a label for the value (such as target_dir)", ""); err != nil {
+			return arg, err
+		}
+	}
+
+	if arg.InputWithVariables, err = promptInputWithVariables(p); err != nil {
+		return arg, err
+	}
+	if arg.IsRepeated, err = p.confirm("Can it be given more than once?"); err != nil {
+		return arg, err
+	}
+	return arg, nil
+}
+
+// promptEnvironmentVariable asks for an environment variable
+func promptEnvironmentVariable(p *prompter) (model.KeyValueInput, error) {
+	var env model.KeyValueInput
+	var err error
+	if env.Name, err = p.ask("Variable name (such as API_KEY)", ""); err != nil {
+		return env, err
+	}
+	if env.InputWithVariables, err = promptInputWithVariables(p); err != nil {
+		return env, err
+	}
+	return env, nil
+}
+
+// promptInputWithVariables asks for the fields arguments and environment variables share,
+// then for each {placeholder} in the value
+func promptInputWithVariables(p *prompter) (model.InputWithVariables, error) {
+	var input model.InputWithVariables
+	var err error
+	if input.Description, err = p.ask("Description", ""); err != nil {
+		return input, err
+	}
+	if input.Value, err = p.ask("Fixed value, which may use {placeholders} (leave empty to let the user choose)", ""); err != nil {
+		return input, err
+	}
+	if input.Value == "" {
+		if input.Input, err = promptInputSettings(p, input.Input); err != nil {
+			return input, err
+		}
+		return input, nil
+	}
+
+	for _, match := range placeholderRegex.FindAllStringSubmatch(input.Value, -1) {
+		name := match[1]
+		if _, ok := input.Variables[name]; ok {
+			continue
+		}
+		_, _ = fmt.Fprintf(p.out, "Variable {%s}:\n", name)
+		var variable model.Input
+		if variable.Description, err = p.ask("  Description", ""); err != nil {
+			return input, err
+		}
+		if variable, err = promptInputSettings(p, variable); err != nil {
+			return input, err
+		}
+		if input.Variables == nil {
+			input.Variables = map[string]model.Input{}
+		}
+		input.Variables[name] = variable
+	}
+	return input, nil
+}
+
+// promptInputSettings asks for the default and flags of a value the user supplies
+func promptInputSettings(p *prompter, input model.Input) (model.Input, error) {
+	var err error
+	if input.Default, err = p.ask("  Default (optional)", ""); err != nil {
+		return input, err
+	}
+	if input.IsRequired, err = p.confirm("  Required?"); err != nil {
+		return input, err
+	}
+	if input.IsSecret, err = p.confirm("  Secret?"); err != nil {
+		return input, err
+	}
+	return input, nil
+}
+
+// validateEnvironmentVariable checks an environment variable before it is added to pkg
+func validateEnvironmentVariable(pkg *model.Package, env *model.KeyValueInput) error {
+	if !envVarNameRegex.MatchString(env.Name) {
+		return fmt.Errorf("name must start with a letter or underscore and contain only letters, digits and underscores: %q", env.Name)
+	}
+	for _, existing := range pkg.EnvironmentVariables {
+		if existing.Name == env.Name {
+			return fmt.Errorf("%s is already defined", env.Name)
+		}
+	}
+	return nil
+}
+
+// argumentLabel names an argument in messages
+func argumentLabel(arg *model.Argument) string {
+	if arg.Type == model.ArgumentTypeNamed {
+		return arg.Name
+	}
+	return "<" + arg.ValueHint + ">"
+}
+
+// inputComments explain the fields of arguments and environment variables in the preview
+var inputComments = map[string]string{
+	"type":        `"named" is passed as name then value, "positional" as the value alone`,
+	"name":        "the flag alone for a named argument, without its value; the variable name for env",
+	"value_hint":  "a label for the value, shown to users",
+	"value":       "sent exactly as written, with {placeholders} filled from variables",
+	"default":     "suggested to the user, and used when they leave it empty",
+	"is_required": "clients ask for it before starting the server",
+	"is_secret":   "clients mask it and keep it out of logs",
+	"is_repeated": "may be given more than once",
+	"variables":   "one entry per {placeholder} in value, each asked of the user",
+}
+
+// renderInputsPreview explains every argument and environment variable in server, as
+// commented JSON5 in Markdown, since server.json itself cannot hold comments. It returns ""
+// when there are no inputs to explain.
+func renderInputsPreview(server *apiv0.ServerJSON) (string, error) {
+	var sections strings.Builder
+	for i, pkg := range server.Packages {
+		field := fmt.Sprintf("packages[%d]", i)
+		for j, arg := range pkg.RuntimeArguments {
+			if err := writePreviewSection(&sections, fmt.Sprintf("%s.runtime_arguments[%d]", field, j), "Runtime argument "+argumentLabel(&arg), arg); err != nil {
+				return "", err
+			}
+		}
+		for j, arg := range pkg.PackageArguments {
+			if err := writePreviewSection(&sections, fmt.Sprintf("%s.package_arguments[%d]", field, j), "Package argument "+argumentLabel(&arg), arg); err != nil {
+				return "", err
+			}
+		}
+		for j, env := range pkg.EnvironmentVariables {
+			if err := writePreviewSection(&sections, fmt.Sprintf("%s.environment_variables[%d]", field, j), "Environment variable "+env.Name, env); err != nil {
+				return "", err
+			}
+		}
+	}
+	if sections.Len() == 0 {
+		return "", nil
+	}
+
+	var preview strings.Builder
+	preview.WriteString("# Inputs in server.json\n\n")
+	preview.WriteString("Written by `mcp-publisher init`. JSON cannot hold comments, so this file explains each\n")
+	preview.WriteString("argument and environment variable in server.json. It is not published; delete it once\n")
+	preview.WriteString("the template is ready.\n")
+	preview.WriteString(sections.String())
+	return preview.String(), nil
+}
+
+// writePreviewSection writes one input as commented JSON5
+func writePreviewSection(b *strings.Builder, field, title string, input any) error {
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(b, "\n## %s\n\nAt `%s`:\n\n```json5\n", title, field)
+	for _, line := range strings.Split(string(data), "\n") {
+		b.WriteString(line)
+		key, _, found := strings.Cut(strings.TrimSpace(line), `":`)
+		if comment, ok := inputComments[strings.TrimPrefix(key, `"`)]; ok && found {
+			b.WriteString(" // " + comment)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("```\n")
+	return nil
+}
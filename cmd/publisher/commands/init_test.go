@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// readInitOutput reads the server.json init wrote to the current directory
+func readInitOutput(t *testing.T) apiv0.ServerJSON {
+	t.Helper()
+	data, err := os.ReadFile("server.json")
+	require.NoError(t, err)
+	var server apiv0.ServerJSON
+	require.NoError(t, json.Unmarshal(data, &server))
+	return server
+}
+
+func TestInitInteractive(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("package.json", []byte(`{"name": "@octocat/weather", "description": "Weather lookups"}`), 0600))
+
+	script := strings.Join([]string{
+		// A named argument whose value embeds its name is rejected, then entered again
+		"argument", "package", "named", "--port", "Port to listen on", "--port=8080", "n",
+		"argument", "package", "named", "--port", "Port to listen on", "", "8080", "n", "n", "n",
+		// A positional runtime argument with a fixed value built from a variable
+		"argument", "runtime", "positional", "image_tag", "Image tag", "weather:{tag}", "Tag to run", "latest", "y", "n", "n",
+		// An invalid environment variable name, then a secret variable
+		"env", "API KEY", "", "", "", "n", "n",
+		"env", "API_KEY", "Weather service key", "", "", "y", "y",
+		"done",
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	require.NoError(t, runInit(initOptions{interactive: true}, strings.NewReader(script), &out))
+	assert.Contains(t, out.String(), "✗ Argument not added")
+	assert.Contains(t, out.String(), "✗ Environment variable not added")
+
+	server := readInitOutput(t)
+	require.Len(t, server.Packages, 1)
+	pkg := server.Packages[0]
+	assert.Equal(t, "@octocat/weather", pkg.Identifier)
+
+	require.Len(t, pkg.PackageArguments, 1)
+	port := pkg.PackageArguments[0]
+	assert.Equal(t, model.ArgumentTypeNamed, port.Type)
+	assert.Equal(t, "--port", port.Name)
+	assert.Equal(t, "8080", port.Default)
+	assert.Empty(t, port.Value)
+
+	require.Len(t, pkg.RuntimeArguments, 1)
+	image := pkg.RuntimeArguments[0]
+	assert.Equal(t, model.ArgumentTypePositional, image.Type)
+	assert.Equal(t, "image_tag", image.ValueHint)
+	assert.Equal(t, "weather:{tag}", image.Value)
+	assert.Equal(t, map[string]model.Input{
+		"tag": {Description: "Tag to run", Default: "latest", IsRequired: true},
+	}, image.Variables)
+
+	require.Len(t, pkg.EnvironmentVariables, 1, "the placeholder API key is not added in interactive mode")
+	assert.Equal(t, "API_KEY", pkg.EnvironmentVariables[0].Name)
+	assert.True(t, pkg.EnvironmentVariables[0].IsSecret)
+
+	preview, err := os.ReadFile("server.json.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(preview), "## Package argument --port")
+	assert.Contains(t, string(preview), "At `packages[0].runtime_arguments[0]`")
+	assert.Contains(t, string(preview), `"value": "weather:{tag}", // sent exactly as written`)
+	assert.Contains(t, string(preview), `"variables": { // one entry per {placeholder}`)
+}
+
+func TestInitInteractiveEndOfInput(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	// Running out of answers between entries finishes the template
+	var out bytes.Buffer
+	require.NoError(t, runInit(initOptions{interactive: true}, strings.NewReader("env\nTOKEN\n\n\n\nn\nn\n"), &out))
+	server := readInitOutput(t)
+	require.Len(t, server.Packages[0].EnvironmentVariables, 1)
+	assert.Equal(t, "TOKEN", server.Packages[0].EnvironmentVariables[0].Name)
+
+	// Running out in the middle of an entry is an error
+	require.NoError(t, os.Remove("server.json"))
+	assert.Error(t, runInit(initOptions{interactive: true}, strings.NewReader("env\nTOKEN\n"), &out))
+}
+
+func TestInitFrom(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "upstream.json")
+	require.NoError(t, os.WriteFile(source, []byte(`{
+  "name": "io.github.upstream/weather",
+  "description": "Weather lookups",
+  "version": "3.2.1",
+  "repository": {"url": "https://github.com/upstream/weather", "source": "github"},
+  "packages": [{
+    "registry_type": "npm",
+    "identifier": "@upstream/weather",
+    "version": "3.2.1",
+    "transport": {"type": "stdio"},
+    "environment_variables": [{"name": "WEATHER_KEY", "description": "API key", "is_secret": true}]
+  }],
+  "_meta": {"io.modelcontextprotocol.registry/official": {"id": "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90"}}
+}`), 0600))
+
+	t.Chdir(t.TempDir())
+
+	var out bytes.Buffer
+	require.NoError(t, runInit(initOptions{from: source}, strings.NewReader(""), &out))
+
+	server := readInitOutput(t)
+	assert.NotEqual(t, "io.github.upstream/weather", server.Name)
+	assert.NotEqual(t, "https://github.com/upstream/weather", server.Repository.URL)
+	assert.Equal(t, "1.0.0", server.Version)
+	assert.Nil(t, server.Meta)
+	require.Len(t, server.Packages, 1)
+	assert.Equal(t, "1.0.0", server.Packages[0].Version)
+	assert.Equal(t, "WEATHER_KEY", server.Packages[0].EnvironmentVariables[0].Name)
+
+	preview, err := os.ReadFile("server.json.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(preview), "## Environment variable WEATHER_KEY")
+}
@@ -19,7 +19,7 @@ const (
 
 func LoginCommand(args []string) error {
 	if len(args) < 1 {
-		return errors.New("authentication method required\n\nUsage: mcp-publisher login <method>\n\nMethods:\n  github        Interactive GitHub authentication\n  github-oidc   GitHub Actions OIDC authentication\n  dns           DNS-based authentication (requires --domain and --private-key)\n  http          HTTP-based authentication (requires --domain and --private-key)\n  none          Anonymous authentication (for testing)")
+		return errors.New("authentication method required\n\nUsage: mcp-publisher login <method>\n\nMethods:\n  github        Interactive GitHub authentication\n  github-oidc   GitHub Actions OIDC authentication\n  gitlab        GitLab authentication (requires --token, GITLAB_TOKEN, or CI_JOB_TOKEN)\n  dns           DNS-based authentication (requires --domain and --private-key)\n  http          HTTP-based authentication (requires --domain and --private-key)\n  none          Anonymous authentication (for testing)")
 	}
 
 	method := args[0]
@@ -29,9 +29,14 @@ func LoginCommand(args []string) error {
 	var domain string
 	var privateKey string
 	var registryURL string
+	var token string
 
 	loginFlags.StringVar(&registryURL, "registry", DefaultRegistryURL, "Registry URL")
 
+	if method == "gitlab" {
+		loginFlags.StringVar(&token, "token", "", "GitLab personal access token")
+	}
+
 	if method == "dns" || method == "http" {
 		loginFlags.StringVar(&domain, "domain", "", "Domain name")
 		loginFlags.StringVar(&privateKey, "private-key", "", "Private key (64-char hex)")
@@ -48,6 +53,8 @@ func LoginCommand(args []string) error {
 		authProvider = auth.NewGitHubATProvider(true, registryURL)
 	case "github-oidc":
 		authProvider = auth.NewGitHubOIDCProvider(registryURL)
+	case "gitlab":
+		authProvider = auth.NewGitLabProvider(registryURL, token)
 	case "dns":
 		if domain == "" || privateKey == "" {
 			return errors.New("dns authentication requires --domain and --private-key")
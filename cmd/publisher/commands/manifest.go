@@ -0,0 +1,245 @@
+package commands
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	"github.com/modelcontextprotocol/registry/internal/validators/registries"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// errNoManifest is returned when no local manifest declares a version for a package
+var errNoManifest = errors.New("no local manifest found")
+
+// manifestVersionOptions controls how package versions are compared with local manifests
+type manifestVersionOptions struct {
+	// strict turns version mismatches into errors instead of warnings
+	strict bool
+	// defaultDir is the directory searched for manifests when a package has no specific entry
+	defaultDir string
+	// packageDirs maps a package identifier to the directory holding its manifest (for monorepos)
+	packageDirs map[string]string
+}
+
+// manifestDirFlag collects repeated --manifest-dir flags, either DIR or IDENTIFIER=DIR
+type manifestDirFlag struct {
+	opts *manifestVersionOptions
+}
+
+func (f manifestDirFlag) String() string {
+	if f.opts == nil {
+		return ""
+	}
+	return f.opts.defaultDir
+}
+
+func (f manifestDirFlag) Set(value string) error {
+	identifier, dir, found := strings.Cut(value, "=")
+	if !found {
+		f.opts.defaultDir = value
+		return nil
+	}
+	if identifier == "" || dir == "" {
+		return fmt.Errorf("invalid --manifest-dir %q: expected DIR or IDENTIFIER=DIR", value)
+	}
+	f.opts.packageDirs[identifier] = dir
+	return nil
+}
+
+// checkUnknownFields checks server.json for fields the format does not define, the way the
+// registry does: each one is named with its JSON pointer and, for a likely typo, the field that
+// was meant. They fail the command unless allowed, when they are only written to out as
+// warnings; the publish request then leaves them out.
+func checkUnknownFields(serverData []byte, allow bool, out io.Writer) error {
+	err := validators.CheckUnknownFields(serverData)
+	if err == nil {
+		return nil
+	}
+
+	var problems []string
+	for _, fieldErr := range validators.FieldErrors(err) {
+		problems = append(problems, fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Error()))
+	}
+	if !allow {
+		return fmt.Errorf("server.json has fields the format does not define:\n  %s\nFix or remove them; --allow-unknown-fields leaves them out instead, for one more release", strings.Join(problems, "\n  "))
+	}
+	for _, problem := range problems {
+		_, _ = fmt.Fprintf(out, "Warning: %s\n", problem)
+	}
+	_, _ = fmt.Fprintln(out, "Warning: these fields are left out of the published server (--allow-unknown-fields will be removed in a future release)")
+	return nil
+}
+
+// checkManifestVersions compares each package version in server.json against the version
+// declared in its local manifest (package.json, pyproject.toml or *.csproj). Mismatches are
+// written to out as warnings, or returned as an error in strict mode. Packages without a
+// discoverable manifest are skipped.
+func checkManifestVersions(serverJSON *apiv0.ServerJSON, opts manifestVersionOptions, out io.Writer) error {
+	var mismatches []string
+	for _, pkg := range serverJSON.Packages {
+		if pkg.Version == "" {
+			continue
+		}
+
+		dir := opts.defaultDir
+		if packageDir, ok := opts.packageDirs[pkg.Identifier]; ok {
+			dir = packageDir
+		}
+
+		manifestVersion, manifestPath, err := readManifestVersion(pkg.RegistryType, dir)
+		if errors.Is(err, errNoManifest) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read manifest for package %s: %w", pkg.Identifier, err)
+		}
+
+		if manifestVersion != pkg.Version {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"package %s has version %s in server.json but %s declares %s",
+				pkg.Identifier, pkg.Version, manifestPath, manifestVersion))
+		}
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+	if opts.strict {
+		return fmt.Errorf("server.json package versions do not match local manifests:\n  %s", strings.Join(mismatches, "\n  "))
+	}
+	for _, mismatch := range mismatches {
+		_, _ = fmt.Fprintf(out, "Warning: %s\n", mismatch)
+	}
+	_, _ = fmt.Fprintln(out, "Warning: the registry may point at a version that cannot be installed (use --strict-versions to fail instead)")
+	return nil
+}
+
+// checkReleaseTags checks that each MCPB package URL points at the release of the package
+// version, as the registry does on publish
+func checkReleaseTags(serverJSON *apiv0.ServerJSON) error {
+	for _, pkg := range serverJSON.Packages {
+		if err := registries.ValidateMCPBReleaseTag(pkg); err != nil {
+			return fmt.Errorf("package %s: %w", pkg.Identifier, err)
+		}
+	}
+	return nil
+}
+
+// readManifestVersion returns the version declared by the manifest for registryType in dir
+func readManifestVersion(registryType, dir string) (string, string, error) {
+	switch registryType {
+	case model.RegistryTypeNPM:
+		path := filepath.Join(dir, "package.json")
+		version, err := readPackageJSONVersion(path)
+		return version, path, err
+	case model.RegistryTypePyPI:
+		path := filepath.Join(dir, "pyproject.toml")
+		version, err := readPyprojectVersion(path)
+		return version, path, err
+	case model.RegistryTypeNuGet:
+		paths, err := filepath.Glob(filepath.Join(dir, "*.csproj"))
+		if err != nil {
+			return "", "", err
+		}
+		if len(paths) != 1 {
+			// None, or ambiguous: point at the right one with --manifest-dir
+			return "", "", errNoManifest
+		}
+		version, err := readCsprojVersion(paths[0])
+		return version, paths[0], err
+	default:
+		return "", "", errNoManifest
+	}
+}
+
+func readManifestFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, errNoManifest
+	}
+	return data, err
+}
+
+func readPackageJSONVersion(path string) (string, error) {
+	data, err := readManifestFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var manifest struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if manifest.Version == "" {
+		return "", errNoManifest
+	}
+	return manifest.Version, nil
+}
+
+// readPyprojectVersion reads the static version from the [project] or [tool.poetry] table.
+// Projects using a dynamic version are treated as having no manifest version.
+func readPyprojectVersion(path string) (string, error) {
+	data, err := readManifestFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	table := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			table = strings.Trim(line, "[] ")
+			continue
+		}
+		if table != "project" && table != "tool.poetry" {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) != "version" {
+			continue
+		}
+		value, _, _ = strings.Cut(value, "#")
+		if version := strings.Trim(strings.TrimSpace(value), "\"'"); version != "" {
+			return version, nil
+		}
+	}
+	return "", errNoManifest
+}
+
+func readCsprojVersion(path string) (string, error) {
+	data, err := readManifestFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var project struct {
+		PropertyGroups []struct {
+			Version        string `xml:"Version"`
+			PackageVersion string `xml:"PackageVersion"`
+		} `xml:"PropertyGroup"`
+	}
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", path, err)
+	}
+
+	for _, group := range project.PropertyGroups {
+		if group.PackageVersion != "" {
+			return strings.TrimSpace(group.PackageVersion), nil
+		}
+		if group.Version != "" {
+			return strings.TrimSpace(group.Version), nil
+		}
+	}
+	return "", errNoManifest
+}
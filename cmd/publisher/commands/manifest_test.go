@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func manifestDir(name string) string {
+	return filepath.Join("testdata", "manifests", name)
+}
+
+func TestCheckManifestVersions(t *testing.T) {
+	tests := []struct {
+		name          string
+		registryType  string
+		version       string
+		dir           string
+		strict        bool
+		expectWarning string
+		expectError   string
+	}{
+		{
+			name:         "npm version matches",
+			registryType: model.RegistryTypeNPM,
+			version:      "1.4.0",
+			dir:          manifestDir("npm"),
+		},
+		{
+			name:          "npm version mismatch warns",
+			registryType:  model.RegistryTypeNPM,
+			version:       "1.3.2",
+			dir:           manifestDir("npm"),
+			expectWarning: "has version 1.3.2 in server.json but " + filepath.Join(manifestDir("npm"), "package.json") + " declares 1.4.0",
+		},
+		{
+			name:         "npm version mismatch fails in strict mode",
+			registryType: model.RegistryTypeNPM,
+			version:      "1.3.2",
+			dir:          manifestDir("npm"),
+			strict:       true,
+			expectError:  "declares 1.4.0",
+		},
+		{
+			name:         "npm without manifest is skipped",
+			registryType: model.RegistryTypeNPM,
+			version:      "1.3.2",
+			dir:          manifestDir("empty"),
+			strict:       true,
+		},
+		{
+			name:         "pypi version matches",
+			registryType: model.RegistryTypePyPI,
+			version:      "2.1.0",
+			dir:          manifestDir("pypi"),
+		},
+		{
+			name:          "pypi version mismatch warns",
+			registryType:  model.RegistryTypePyPI,
+			version:       "2.0.0",
+			dir:           manifestDir("pypi"),
+			expectWarning: "declares 2.1.0",
+		},
+		{
+			name:         "pypi version mismatch fails in strict mode",
+			registryType: model.RegistryTypePyPI,
+			version:      "2.0.0",
+			dir:          manifestDir("pypi"),
+			strict:       true,
+			expectError:  "declares 2.1.0",
+		},
+		{
+			name:         "pypi without manifest is skipped",
+			registryType: model.RegistryTypePyPI,
+			version:      "2.0.0",
+			dir:          manifestDir("empty"),
+			strict:       true,
+		},
+		{
+			name:         "nuget version mismatch fails in strict mode",
+			registryType: model.RegistryTypeNuGet,
+			version:      "0.2.0",
+			dir:          manifestDir("nuget"),
+			strict:       true,
+			expectError:  "declares 0.3.0",
+		},
+		{
+			name:         "registry types without manifests are skipped",
+			registryType: model.RegistryTypeOCI,
+			version:      "9.9.9",
+			dir:          manifestDir("npm"),
+			strict:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverJSON := &apiv0.ServerJSON{
+				Packages: []model.Package{
+					{RegistryType: tt.registryType, Identifier: "example", Version: tt.version},
+				},
+			}
+			opts := manifestVersionOptions{strict: tt.strict, defaultDir: tt.dir, packageDirs: map[string]string{}}
+
+			var out bytes.Buffer
+			err := checkManifestVersions(serverJSON, opts, &out)
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectWarning != "" {
+				assert.Contains(t, out.String(), tt.expectWarning)
+			} else {
+				assert.Empty(t, out.String())
+			}
+		})
+	}
+}
+
+func TestCheckManifestVersions_Monorepo(t *testing.T) {
+	serverJSON := &apiv0.ServerJSON{
+		Packages: []model.Package{
+			{RegistryType: model.RegistryTypeNPM, Identifier: "@example/weather-server", Version: "1.4.0"},
+			{RegistryType: model.RegistryTypePyPI, Identifier: "weather-server", Version: "2.0.0"},
+		},
+	}
+
+	opts := manifestVersionOptions{strict: true, defaultDir: manifestDir("empty"), packageDirs: map[string]string{}}
+	flag := manifestDirFlag{opts: &opts}
+	require.NoError(t, flag.Set("@example/weather-server="+manifestDir("npm")))
+	require.NoError(t, flag.Set("weather-server="+manifestDir("pypi")))
+
+	err := checkManifestVersions(serverJSON, opts, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "package weather-server has version 2.0.0")
+	assert.NotContains(t, err.Error(), "@example/weather-server")
+}
+
+func TestCheckReleaseTags(t *testing.T) {
+	mcpb := func(identifier string) *apiv0.ServerJSON {
+		return &apiv0.ServerJSON{
+			Packages: []model.Package{
+				{RegistryType: model.RegistryTypeNPM, Identifier: "@example/weather-server", Version: "1.8.0"},
+				{RegistryType: model.RegistryTypeMCPB, Identifier: identifier, Version: "1.8.0"},
+			},
+		}
+	}
+
+	require.NoError(t, checkReleaseTags(mcpb("https://github.com/example/weather/releases/download/v1.8.0/weather.mcpb")))
+	require.NoError(t, checkReleaseTags(mcpb("https://gitlab.com/example/weather/-/releases/1.8.0/downloads/weather.mcpb")))
+
+	err := checkReleaseTags(mcpb("https://github.com/example/weather/releases/download/v1.7.2/weather.mcpb"))
+	assert.ErrorContains(t, err, `the URL is tagged "v1.7.2" but the package version is "1.8.0"`)
+	err = checkReleaseTags(mcpb("https://gitlab.com/example/weather/-/releases/v1.7.2/downloads/weather.mcpb"))
+	assert.ErrorContains(t, err, "package https://gitlab.com/example/weather")
+}
+
+func TestManifestDirFlag(t *testing.T) {
+	opts := manifestVersionOptions{defaultDir: ".", packageDirs: map[string]string{}}
+	flag := manifestDirFlag{opts: &opts}
+
+	require.NoError(t, flag.Set("packages/server"))
+	require.NoError(t, flag.Set("weather-server=python"))
+	assert.Equal(t, "packages/server", opts.defaultDir)
+	assert.Equal(t, map[string]string{"weather-server": "python"}, opts.packageDirs)
+
+	assert.Error(t, flag.Set("=python"))
+	assert.Error(t, flag.Set("weather-server="))
+}
+
+func TestCheckUnknownFields(t *testing.T) {
+	serverData := []byte(`{
+		"name": "io.github.example/weather",
+		"verison": "1.0.0",
+		"packages": [{"registryType": "npm", "identifier": "@example/weather"}]
+	}`)
+
+	t.Run("fails by default", func(t *testing.T) {
+		var out bytes.Buffer
+		err := checkUnknownFields(serverData, false, &out)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `/packages/0/registryType: unknown field "registryType"; did you mean "registry_type"?`)
+		assert.Contains(t, err.Error(), `/verison: unknown field "verison"; did you mean "version"?`)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("warns when allowed", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, checkUnknownFields(serverData, true, &out))
+		assert.Contains(t, out.String(), `Warning: /verison: unknown field "verison"; did you mean "version"?`)
+	})
+
+	t.Run("passes without unknown fields", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, checkUnknownFields([]byte(`{"name": "io.github.example/weather", "version": "1.0.0"}`), false, &out))
+		assert.Empty(t, out.String())
+	})
+}
@@ -5,127 +5,428 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
+// defaultReviewTimeout is how long publish waits for a version held for review before returning
+const defaultReviewTimeout = 5 * time.Minute
+
+// clockSkewAllowance is how far the registry's clock may be behind when checking whether a
+// version was published by an earlier attempt of this run
+const clockSkewAllowance = time.Minute
+
+// metaTimeout bounds the registry version check, so an unresponsive GET /v0/meta does not hold up publishing
+const metaTimeout = 5 * time.Second
+
+// reviewPollInterval is how often publish checks on a version held for review
+var reviewPollInterval = 15 * time.Second
+
+// errVersionNotNewer is returned by publishToRegistry when the registry skipped a conditional
+// publish because the version is not newer than the latest
+var errVersionNotNewer = errors.New("not newer than the latest version")
+
+// UserAgent identifies the publisher in registry requests; main appends the release version
+var UserAgent = "mcp-publisher"
+
+// publishOptions control how the publish request is sent
+type publishOptions struct {
+	retryOptions
+	skipIfNotNewer bool // send If-Version-Newer, so the registry skips versions that are not newer
+}
+
 func PublishCommand(args []string) error {
-	// Check for server.json file
-	serverFile := "server.json"
-	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
-		serverFile = args[0]
+	var reviewTimeout time.Duration
+	var opts publishOptions
+	var notesFromRelease, warningsAsErrors bool
+	serverFile, fileOpts, err := parseServerFileArgs("publish", args, func(flags *flag.FlagSet) {
+		flags.DurationVar(&reviewTimeout, "review-timeout", defaultReviewTimeout, "How long to wait for a version held for admin review to be approved (0 to not wait)")
+		flags.IntVar(&opts.maxAttempts, "max-attempts", defaultMaxAttempts, "How many times to send the publish request when the registry fails transiently (1 to not retry)")
+		flags.DurationVar(&opts.deadline, "retry-deadline", defaultRetryDeadline, "How long to keep retrying the publish request in total (0 for no limit)")
+		flags.BoolVar(&notesFromRelease, "notes-from-release", false, "Fill in releaseNotes from the GitHub release for the version, when server.json has none")
+		flags.BoolVar(&warningsAsErrors, "warnings-as-errors", false, "Exit with an error when the registry returns warnings; the version is still published")
+		flags.BoolVar(&opts.skipIfNotNewer, "skip-if-not-newer", false, "Skip publishing, and exit successfully, when the version is not newer than the latest version in the registry")
+	})
+	if err != nil {
+		return err
+	}
+	if opts.maxAttempts < 1 {
+		return errors.New("--max-attempts must be at least 1")
 	}
 
-	// Read server.json
-	serverData, err := os.ReadFile(serverFile)
+	serverData, serverJSON, err := readServerJSON(serverFile)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("server.json not found. Run 'mcp-publisher init' to create one")
-		}
-		return fmt.Errorf("failed to read server.json: %w", err)
+		return err
 	}
 
-	// Validate JSON
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(serverData, &serverJSON); err != nil {
-		return fmt.Errorf("invalid server.json: %w", err)
+	// Catch typos, which the registry rejects, before anything else
+	if err := checkUnknownFields(serverData, fileOpts.allowUnknownFields, os.Stderr); err != nil {
+		return err
+	}
+	// Catch server.json versions that were not bumped along with the package manifest or release URL
+	if err := checkManifestVersions(serverJSON, fileOpts.versions, os.Stderr); err != nil {
+		return err
+	}
+	if err := checkReleaseTags(serverJSON); err != nil {
+		return err
+	}
+
+	if notesFromRelease {
+		serverData = fillReleaseNotes(serverData, serverJSON, os.Stdout)
 	}
 
 	// Load saved token
+	token, registryURL, err := loadSavedToken()
+	if err != nil {
+		return err
+	}
+
+	checkRegistryVersion(registryURL, os.Stderr)
+
+	// Publish to registry
+	_, _ = fmt.Fprintf(os.Stdout, "Publishing to %s...\n", registryURL)
+	response, warnings, err := publishToRegistry(registryURL, serverData, token, opts, os.Stdout)
+	if errors.Is(err, errVersionNotNewer) {
+		_, _ = fmt.Fprintf(os.Stdout, "✓ Skipped: %v\n", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("publish failed: %w", err)
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, "✓ Successfully published")
+	if serverID := response.GetID(); serverID != "" {
+		_, _ = fmt.Fprintf(os.Stdout, "✓ Server Id %s\n", serverID)
+	}
+	if traceID := TraceID(); traceID != "" {
+		_, _ = fmt.Fprintf(os.Stdout, "✓ Trace ID %s\n", traceID)
+	}
+
+	printWarnings(warnings, os.Stdout)
+
+	if response.Status == model.StatusPending {
+		if err := awaitReview(registryURL, token, response.GetID(), reviewTimeout, os.Stdout); err != nil {
+			return err
+		}
+	}
+	if warningsAsErrors && len(warnings) > 0 {
+		return fmt.Errorf("published with %d warning(s), failing because of --warnings-as-errors", len(warnings))
+	}
+	return nil
+}
+
+// printWarnings lists the registry's warnings about a published server, so they stand out in CI logs
+func printWarnings(warnings []apiv0.Warning, out io.Writer) {
+	if len(warnings) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(out, "\n⚠ The registry returned %d warning(s):\n", len(warnings))
+	for _, warning := range warnings {
+		if warning.Path != "" {
+			_, _ = fmt.Fprintf(out, "  - [%s] %s: %s\n", warning.Code, warning.Path, warning.Message)
+		} else {
+			_, _ = fmt.Fprintf(out, "  - [%s] %s\n", warning.Code, warning.Message)
+		}
+	}
+}
+
+// registryError describes a request the registry failed. Requests rejected as invalid list each
+// problem the way warnings are listed, whether the API's schema or the registry's own checks of
+// the server.json found it.
+func registryError(status int, body []byte) error {
+	problem := apiv0.ParseErrorResponse(status, body)
+	if len(problem.Errors) == 0 || problem.Errors[0].Code == "" {
+		return fmt.Errorf("server returned status %d: %s", status, body)
+	}
+	var message strings.Builder
+	_, _ = fmt.Fprintf(&message, "server returned status %d: %s", status, problem.Message)
+	for _, detail := range problem.Errors {
+		if detail.Location != "" {
+			_, _ = fmt.Fprintf(&message, "\n  - [%s] %s: %s", detail.Code, detail.Location, detail.Message)
+		} else {
+			_, _ = fmt.Fprintf(&message, "\n  - [%s] %s", detail.Code, detail.Message)
+		}
+	}
+	return errors.New(message.String())
+}
+
+// reviewStatus is the registry's answer to GET /v0/servers/{id}/review
+type reviewStatus struct {
+	Status          model.Status `json:"status"`
+	RejectionReason string       `json:"rejection_reason,omitempty"`
+}
+
+// awaitReview reports on a version the registry held for admin review, polling until an admin
+// decides or timeout passes. A rejection is returned as an error so that CI runs fail.
+func awaitReview(registryURL, token, id string, timeout time.Duration, out io.Writer) error {
+	_, _ = fmt.Fprintln(out, "⏳ This version is pending review by a registry admin and is not publicly listed yet")
+	if timeout <= 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "Waiting up to %s for review...\n", timeout)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := fetchReviewStatus(registryURL, token, id)
+		if err != nil {
+			return err
+		}
+		switch status.Status {
+		case model.StatusPending:
+		case model.StatusRejected:
+			return fmt.Errorf("version was rejected by a registry admin: %s", status.RejectionReason)
+		default:
+			_, _ = fmt.Fprintf(out, "✓ Approved, now %s\n", status.Status)
+			return nil
+		}
+
+		if !time.Now().Add(reviewPollInterval).Before(deadline) {
+			_, _ = fmt.Fprintln(out, "Still pending review. It will be listed once an admin approves it.")
+			return nil
+		}
+		time.Sleep(reviewPollInterval)
+	}
+}
+
+// fetchReviewStatus checks whether a held version has been approved or rejected
+func fetchReviewStatus(registryURL, token, id string) (*reviewStatus, error) {
+	reviewURL := strings.TrimSuffix(registryURL, "/") + "/v0/servers/" + url.PathEscape(id) + "/review"
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reviewURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := registryClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error checking review status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, registryError(resp.StatusCode, body)
+	}
+
+	var status reviewStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("invalid review status: %w", err)
+	}
+	return &status, nil
+}
+
+// checkRegistryVersion warns when the registry serves a different API version than this publisher
+// was built for. Registries without GET /v0/meta, and any failure to reach it, are ignored: the
+// publish request reports real problems.
+func checkRegistryVersion(registryURL string, out io.Writer) {
+	ctx, cancel := context.WithTimeout(context.Background(), metaTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(registryURL, "/")+"/v0/meta", nil)
+	if err != nil {
+		return
+	}
+	resp, err := registryClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var meta apiv0.RegistryMeta
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil || meta.APIVersion == "" {
+		return
+	}
+	if meta.APIVersion != apiv0.APIVersion {
+		_, _ = fmt.Fprintf(out, "⚠ %s serves API version %s, but this mcp-publisher expects %s. Publishing may fail; consider updating mcp-publisher.\n",
+			registryURL, meta.APIVersion, apiv0.APIVersion)
+	}
+}
+
+// loadSavedToken returns the registry token and registry URL saved by 'mcp-publisher login'
+func loadSavedToken() (string, string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return "", "", fmt.Errorf("failed to get home directory: %w", err)
 	}
 
 	tokenPath := filepath.Join(homeDir, TokenFileName)
 	tokenData, err := os.ReadFile(tokenPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return errors.New("not authenticated. Run 'mcp-publisher login <method>' first")
+			return "", "", errors.New("not authenticated. Run 'mcp-publisher login <method>' first")
 		}
-		return fmt.Errorf("failed to read token: %w", err)
+		return "", "", fmt.Errorf("failed to read token: %w", err)
 	}
 
 	var tokenInfo map[string]string
 	if err := json.Unmarshal(tokenData, &tokenInfo); err != nil {
-		return fmt.Errorf("invalid token data: %w", err)
+		return "", "", fmt.Errorf("invalid token data: %w", err)
 	}
 
-	token := tokenInfo["token"]
 	registryURL := tokenInfo["registry"]
 	if registryURL == "" {
 		registryURL = DefaultRegistryURL
 	}
+	return tokenInfo["token"], registryURL, nil
+}
 
-	// Publish to registry
-	_, _ = fmt.Fprintf(os.Stdout, "Publishing to %s...\n", registryURL)
-	response, err := publishToRegistry(registryURL, serverData, token)
+// serverFileOptions are the flags shared by the publish and validate commands
+type serverFileOptions struct {
+	versions           manifestVersionOptions
+	allowUnknownFields bool // warn about server.json fields the format does not define instead of failing
+}
+
+// parseServerFileArgs parses `[server.json] [flags]` shared by the publish and validate commands,
+// registering any command-specific flags with extra
+func parseServerFileArgs(command string, args []string, extra func(*flag.FlagSet)) (string, serverFileOptions, error) {
+	serverFile := "server.json"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		serverFile = args[0]
+		args = args[1:]
+	}
+
+	opts := serverFileOptions{versions: manifestVersionOptions{defaultDir: ".", packageDirs: map[string]string{}}}
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.BoolVar(&opts.versions.strict, "strict-versions", false, "Fail instead of warning when package versions differ from local manifests")
+	flags.Var(manifestDirFlag{opts: &opts.versions}, "manifest-dir", "Directory containing package manifests, or IDENTIFIER=DIR for a single package (repeatable)")
+	flags.BoolVar(&opts.allowUnknownFields, "allow-unknown-fields", false, "Warn about server.json fields the format does not define, and leave them out, instead of failing (deprecated)")
+	if extra != nil {
+		extra(flags)
+	}
+	if err := flags.Parse(args); err != nil {
+		return "", opts, err
+	}
+	if flags.NArg() > 0 {
+		serverFile = flags.Arg(0)
+	}
+
+	return serverFile, opts, nil
+}
+
+// readServerJSON reads and parses a server.json file
+func readServerJSON(serverFile string) ([]byte, *apiv0.ServerJSON, error) {
+	serverData, err := os.ReadFile(serverFile)
 	if err != nil {
-		return fmt.Errorf("publish failed: %w", err)
+		if os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("server.json not found. Run 'mcp-publisher init' to create one")
+		}
+		return nil, nil, fmt.Errorf("failed to read server.json: %w", err)
 	}
 
-	_, _ = fmt.Fprintln(os.Stdout, "✓ Successfully published")
-	if serverID := response.GetID(); serverID != "" {
-		_, _ = fmt.Fprintf(os.Stdout, "✓ Server Id %s", serverID)
+	var serverJSON apiv0.ServerJSON
+	if err := json.Unmarshal(serverData, &serverJSON); err != nil {
+		return nil, nil, fmt.Errorf("invalid server.json: %w", err)
 	}
 
-	return nil
+	return serverData, &serverJSON, nil
 }
 
-func publishToRegistry(registryURL string, serverData []byte, token string) (*apiv0.ServerJSON, error) {
+// publishToRegistry publishes serverData, retrying transient failures as opts allows, and returns
+// the published server with the registry's warnings about it. Every attempt carries the same
+// Idempotency-Key, so the registry can recognize a retried request. A retry answered with a
+// conflict is checked against the published version, since an earlier attempt may have been
+// saved even though its response was lost. A conditional publish the registry skipped returns
+// errVersionNotNewer.
+func publishToRegistry(registryURL string, serverData []byte, token string, opts publishOptions, out io.Writer) (*apiv0.ServerJSON, []apiv0.Warning, error) {
 	// Parse the server JSON data
 	var serverJSON apiv0.ServerJSON
 	err := json.Unmarshal(serverData, &serverJSON)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing server.json file: %w", err)
+		return nil, nil, fmt.Errorf("error parsing server.json file: %w", err)
 	}
 
 	// Convert to JSON
 	jsonData, err := json.Marshal(serverJSON)
 	if err != nil {
-		return nil, fmt.Errorf("error serializing request: %w", err)
+		return nil, nil, fmt.Errorf("error serializing request: %w", err)
 	}
 
 	// Ensure URL ends with the publish endpoint
-	if !strings.HasSuffix(registryURL, "/") {
-		registryURL += "/"
-	}
-	publishURL := registryURL + "v0/publish"
+	registryURL = strings.TrimSuffix(registryURL, "/")
+	publishURL := registryURL + "/v0/publish"
 
-	// Create and send request
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, publishURL, bytes.NewBuffer(jsonData))
+	// Send the request, retrying transient failures
+	started := time.Now()
+	idempotencyKey := uuid.NewString()
+	result, err := sendWithRetry(opts.retryOptions, out, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, publishURL, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		// Ask for the full published server; without a profile, the registry answers publisher
+		// releases from before it with the legacy message and id
+		req.Header.Set("Accept", `application/json; profile="`+apiv0.PublishProfileServer+`"`)
+		req.Header.Set("User-Agent", UserAgent)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+		if opts.skipIfNotNewer {
+			req.Header.Set("If-Version-Newer", "true")
+		}
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+	if result.status == http.StatusConflict && result.attempts > 1 {
+		if saved := findEarlierAttempt(registryURL, &serverJSON, started); saved != nil {
+			_, _ = fmt.Fprintln(out, "An earlier attempt was saved, though its response was lost")
+			return saved, nil, nil
+		}
 	}
-	defer resp.Body.Close()
-
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
+	if result.status != http.StatusCreated && result.status != http.StatusOK {
+		return nil, nil, registryError(result.status, result.body)
 	}
 
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned status %d: %s", resp.StatusCode, body)
+	sent := serverJSON.Version
+	if err := json.Unmarshal(result.body, &serverJSON); err != nil {
+		return nil, nil, err
 	}
 
-	if err := json.Unmarshal(body, &serverJSON); err != nil {
-		return nil, err
+	// The registry reports problems it published the server despite alongside it, and answers a
+	// skipped conditional publish with the latest version instead
+	var response struct {
+		Warnings []apiv0.Warning `json:"warnings"`
+		Skipped  bool            `json:"skipped"`
+	}
+	if err := json.Unmarshal(result.body, &response); err != nil {
+		return nil, nil, err
+	}
+	if response.Skipped {
+		return nil, nil, fmt.Errorf("version %s is %w %s", sent, errVersionNotNewer, serverJSON.Version)
 	}
 
-	return &serverJSON, nil
+	return &serverJSON, response.Warnings, nil
+}
+
+// findEarlierAttempt returns the published version of sent if it was published after started,
+// allowing for clock skew, or nil if it was published before this run or cannot be found
+func findEarlierAttempt(registryURL string, sent *apiv0.ServerJSON, started time.Time) *apiv0.ServerJSON {
+	versions, err := fetchServerVersions(registryURL, sent.Name, sent.Version)
+	if err != nil || len(versions) != 1 {
+		return nil
+	}
+	meta := versions[0].Meta
+	if meta == nil || meta.Official == nil || meta.Official.PublishedAt.Before(started.Add(-clockSkewAllowance)) {
+		return nil
+	}
+	return &versions[0]
 }
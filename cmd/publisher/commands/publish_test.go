@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestPublishWarnings(t *testing.T) {
+	warning := apiv0.Warning{
+		Code:    apiv0.WarningSuspiciousHeaderValue,
+		Path:    "remotes[0].headers[0].value",
+		Message: "header X-Client contains 9f86...d015",
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v0/publish", func(w http.ResponseWriter, r *http.Request) {
+		var server apiv0.ServerJSON
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&server)) {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(struct {
+			apiv0.ServerJSON
+			Warnings []apiv0.Warning `json:"warnings"`
+		}{server, []apiv0.Warning{warning}})
+	})
+	registry := httptest.NewServer(mux)
+	defer registry.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tokenData, err := json.Marshal(map[string]string{"token": "test-token", "registry": registry.URL})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(home, TokenFileName), tokenData, 0600))
+	serverFile := filepath.Join(home, "server.json")
+	require.NoError(t, os.WriteFile(serverFile, []byte(retryServerJSON), 0600))
+
+	t.Run("returned with the published server", func(t *testing.T) {
+		var out bytes.Buffer
+		server, warnings, err := publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", publishOptions{retryOptions: retryOptions{maxAttempts: 1}}, &out)
+		require.NoError(t, err)
+		assert.Equal(t, "io.github.example/weather", server.Name)
+		assert.Equal(t, []apiv0.Warning{warning}, warnings)
+
+		printWarnings(warnings, &out)
+		assert.Contains(t, out.String(), "⚠ The registry returned 1 warning(s):")
+		assert.Contains(t, out.String(), "[suspicious_header_value] remotes[0].headers[0].value: header X-Client contains 9f86...d015")
+	})
+
+	t.Run("do not fail the publish by default", func(t *testing.T) {
+		assert.NoError(t, PublishCommand([]string{serverFile}))
+	})
+
+	t.Run("fail the publish with --warnings-as-errors", func(t *testing.T) {
+		err := PublishCommand([]string{serverFile, "--warnings-as-errors"})
+		assert.EqualError(t, err, "published with 1 warning(s), failing because of --warnings-as-errors")
+	})
+}
+
+func TestPublishSkipIfNotNewer(t *testing.T) {
+	var conditional, accept []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v0/publish", func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-Version-Newer"))
+		accept = append(accept, r.Header.Get("Accept"))
+		latest := apiv0.ServerJSON{Name: "io.github.example/weather", Version: "1.2.0"}
+		_ = json.NewEncoder(w).Encode(struct {
+			apiv0.ServerJSON
+			Skipped bool `json:"skipped"`
+		}{latest, true})
+	})
+	registry := httptest.NewServer(mux)
+	defer registry.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tokenData, err := json.Marshal(map[string]string{"token": "test-token", "registry": registry.URL})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(home, TokenFileName), tokenData, 0600))
+	serverFile := filepath.Join(home, "server.json")
+	require.NoError(t, os.WriteFile(serverFile, []byte(retryServerJSON), 0600))
+
+	t.Run("a skipped publish is reported", func(t *testing.T) {
+		_, _, err := publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", publishOptions{retryOptions: retryOptions{maxAttempts: 1}, skipIfNotNewer: true}, &bytes.Buffer{})
+		require.ErrorIs(t, err, errVersionNotNewer)
+		assert.EqualError(t, err, "version 1.0.0 is not newer than the latest version 1.2.0")
+		assert.Equal(t, "true", conditional[len(conditional)-1])
+		assert.Equal(t, `application/json; profile="server"`, accept[len(accept)-1], "the full response is asked for explicitly")
+	})
+
+	t.Run("the publish command exits successfully", func(t *testing.T) {
+		require.NoError(t, PublishCommand([]string{serverFile, "--skip-if-not-newer"}))
+		assert.Equal(t, "true", conditional[len(conditional)-1])
+	})
+
+	t.Run("the header is only sent with the flag", func(t *testing.T) {
+		_, _, _ = publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", publishOptions{retryOptions: retryOptions{maxAttempts: 1}}, &bytes.Buffer{})
+		assert.Empty(t, conditional[len(conditional)-1])
+	})
+}
+
+func TestCheckRegistryVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		warning string
+	}{
+		{
+			name: "matching API version",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_ = json.NewEncoder(w).Encode(apiv0.RegistryMeta{APIVersion: apiv0.APIVersion})
+			},
+		},
+		{
+			name: "different API version",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_ = json.NewEncoder(w).Encode(apiv0.RegistryMeta{APIVersion: "v1"})
+			},
+			warning: "serves API version v1, but this mcp-publisher expects v0",
+		},
+		{
+			name:    "registry without the meta endpoint",
+			handler: http.NotFound,
+		},
+		{
+			name: "unexpected response",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("<html>"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /v0/meta", tt.handler)
+			registry := httptest.NewServer(mux)
+			defer registry.Close()
+
+			var out bytes.Buffer
+			checkRegistryVersion(registry.URL, &out)
+			if tt.warning == "" {
+				assert.Empty(t, out.String())
+			} else {
+				assert.Contains(t, out.String(), tt.warning)
+			}
+		})
+	}
+}
+
+func TestAwaitReview(t *testing.T) {
+	const id = "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90"
+	reviewPollInterval = time.Millisecond
+	defer func() { reviewPollInterval = 15 * time.Second }()
+
+	// stubReview answers review status polls with pending until polls run out, then with final
+	stubReview := func(t *testing.T, pendingPolls int, final reviewStatus) (*httptest.Server, *int) {
+		t.Helper()
+		polls := 0
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /v0/servers/{id}/review", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, id, r.PathValue("id"))
+			assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+			polls++
+			status := final
+			if polls <= pendingPolls {
+				status = reviewStatus{Status: model.StatusPending}
+			}
+			_ = json.NewEncoder(w).Encode(status)
+		})
+		registry := httptest.NewServer(mux)
+		t.Cleanup(registry.Close)
+		return registry, &polls
+	}
+
+	t.Run("reports approval", func(t *testing.T) {
+		registry, polls := stubReview(t, 2, reviewStatus{Status: model.StatusActive})
+		var out bytes.Buffer
+		require.NoError(t, awaitReview(registry.URL+"/", "test-token", id, time.Minute, &out))
+		assert.Equal(t, 3, *polls)
+		assert.Contains(t, out.String(), "pending review")
+		assert.Contains(t, out.String(), "✓ Approved, now active")
+	})
+
+	t.Run("fails on rejection with the reason", func(t *testing.T) {
+		registry, _ := stubReview(t, 0, reviewStatus{Status: model.StatusRejected, RejectionReason: "Spam"})
+		err := awaitReview(registry.URL, "test-token", id, time.Minute, &bytes.Buffer{})
+		assert.EqualError(t, err, "version was rejected by a registry admin: Spam")
+	})
+
+	t.Run("gives up while still pending", func(t *testing.T) {
+		registry, polls := stubReview(t, 1000, reviewStatus{})
+		var out bytes.Buffer
+		require.NoError(t, awaitReview(registry.URL, "test-token", id, 20*time.Millisecond, &out))
+		assert.Positive(t, *polls)
+		assert.Contains(t, out.String(), "Still pending review")
+	})
+
+	t.Run("does not wait without a timeout", func(t *testing.T) {
+		registry, polls := stubReview(t, 0, reviewStatus{Status: model.StatusActive})
+		var out bytes.Buffer
+		require.NoError(t, awaitReview(registry.URL, "test-token", id, 0, &out))
+		assert.Zero(t, *polls)
+		assert.Contains(t, out.String(), "pending review")
+	})
+}
+
+func TestRegistryError(t *testing.T) {
+	// Schema validation failures and the registry's own checks are rendered alike
+	schema := `{"title": "Unprocessable Entity", "status": 422, "detail": "validation failed",
+		"errors": [{"code": "required", "location": "/description", "message": "expected required property description to be present"}]}`
+	assert.EqualError(t, registryError(http.StatusUnprocessableEntity, []byte(schema)),
+		"server returned status 422: validation failed\n  - [required] /description: expected required property description to be present")
+
+	check := `{"title": "Bad Request", "status": 400, "detail": "Failed to publish server",
+		"errors": [{"code": "invalid_title", "location": "/title", "message": "invalid title: title cannot be blank"}]}`
+	assert.EqualError(t, registryError(http.StatusBadRequest, []byte(check)),
+		"server returned status 400: Failed to publish server\n  - [invalid_title] /title: invalid title: title cannot be blank")
+
+	// Other responses are shown as they are
+	assert.EqualError(t, registryError(http.StatusInternalServerError, []byte(`{"detail": "Failed to publish server"}`)),
+		`server returned status 500: {"detail": "Failed to publish server"}`)
+}
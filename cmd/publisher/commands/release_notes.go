@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// releaseNotesClient fetches release notes from GitHub. They are optional, so it gives up quickly.
+var releaseNotesClient = &http.Client{Timeout: 10 * time.Second}
+
+// errNoRelease is returned for a tag without a GitHub release
+var errNoRelease = errors.New("no release")
+
+// githubRelease is the part of GitHub's release response that publish uses
+type githubRelease struct {
+	Body string `json:"body"`
+}
+
+// fillReleaseNotes sets the release notes of serverJSON to the body of the GitHub release for
+// its version, tagged either v<version> or <version>, returning the server.json to publish.
+// Notes already in server.json are kept. Publishing doesn't depend on the notes, so when they
+// cannot be fetched a warning is printed on out and serverData is returned unchanged.
+func fillReleaseNotes(serverData []byte, serverJSON *apiv0.ServerJSON, out io.Writer) []byte {
+	if serverJSON.ReleaseNotes != "" {
+		return serverData
+	}
+	notes, err := fetchReleaseNotes(context.Background(), serverJSON)
+	if err == nil && len(notes) > validators.MaxReleaseNotesBytes {
+		err = fmt.Errorf("release notes are %d bytes, at most %d allowed", len(notes), validators.MaxReleaseNotesBytes)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Warning: publishing without release notes: %v\n", err)
+		return serverData
+	}
+
+	// Re-encode the server.json, which publishToRegistry does anyway
+	serverJSON.ReleaseNotes = notes
+	filled, err := json.Marshal(serverJSON)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Warning: publishing without release notes: %v\n", err)
+		serverJSON.ReleaseNotes = ""
+		return serverData
+	}
+	_, _ = fmt.Fprintln(out, "✓ Using the GitHub release notes for", serverJSON.Version)
+	return filled
+}
+
+// fetchReleaseNotes looks up the body of the GitHub release for the version of serverJSON
+func fetchReleaseNotes(ctx context.Context, serverJSON *apiv0.ServerJSON) (string, error) {
+	if serverJSON.Repository.Source != string(validators.SourceGitHub) || serverJSON.Repository.URL == "" {
+		return "", errors.New("--notes-from-release needs a GitHub repository in server.json")
+	}
+	parsed, err := url.Parse(serverJSON.Repository.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository URL: %w", err)
+	}
+	repo := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+
+	for _, tag := range []string{"v" + serverJSON.Version, serverJSON.Version} {
+		release, err := fetchGitHubRelease(ctx, repo, tag)
+		if errors.Is(err, errNoRelease) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(release.Body) == "" {
+			return "", fmt.Errorf("the GitHub release %s has no notes", tag)
+		}
+		return release.Body, nil
+	}
+	return "", fmt.Errorf("no GitHub release tagged v%s or %s in %s", serverJSON.Version, serverJSON.Version, repo)
+}
+
+// fetchGitHubRelease fetches the release of repo tagged tag, or returns errNoRelease.
+// GITHUB_TOKEN is sent when set, for private repositories and GitHub's higher rate limit.
+func fetchGitHubRelease(ctx context.Context, repo, tag string) (*githubRelease, error) {
+	releaseURL := validators.GitHubAPIBaseURL + "/repos/" + repo + "/releases/tags/" + url.PathEscape(tag)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releaseURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "mcp-publisher")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := releaseNotesClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching the GitHub release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errNoRelease
+	default:
+		return nil, fmt.Errorf("GitHub returned status %d for the release %s", resp.StatusCode, tag)
+	}
+
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("invalid GitHub release: %w", err)
+	}
+	return &release, nil
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestFillReleaseNotes(t *testing.T) {
+	releases := map[string]string{
+		"/repos/example/weather/releases/tags/v1.2.0": `{"tag_name": "v1.2.0", "body": "## Fixes\n\n- Retry timeouts"}`,
+		"/repos/example/weather/releases/tags/2.0.0":  `{"tag_name": "2.0.0", "body": "Unprefixed tag"}`,
+		"/repos/example/weather/releases/tags/v3.0.0": `{"tag_name": "v3.0.0", "body": ""}`,
+		"/repos/example/weather/releases/tags/v4.0.0": `{"tag_name": "v4.0.0", "body": "` + strings.Repeat("a", validators.MaxReleaseNotesBytes+1) + `"}`,
+	}
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"))
+		if r.URL.Path == "/repos/example/weather/releases/tags/v5.0.0" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		release, ok := releases[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(release))
+	}))
+	defer github.Close()
+
+	originalBaseURL := validators.GitHubAPIBaseURL
+	validators.GitHubAPIBaseURL = github.URL
+	defer func() { validators.GitHubAPIBaseURL = originalBaseURL }()
+
+	tests := []struct {
+		name            string
+		version         string
+		releaseNotes    string
+		repository      model.Repository
+		expectedNotes   string
+		expectedWarning string
+	}{
+		{name: "v-prefixed tag", version: "1.2.0", expectedNotes: "## Fixes\n\n- Retry timeouts"},
+		{name: "unprefixed tag", version: "2.0.0", expectedNotes: "Unprefixed tag"},
+		{name: "notes in server.json are kept", version: "1.2.0", releaseNotes: "Written by hand", expectedNotes: "Written by hand"},
+		{name: "no release", version: "9.9.9", expectedWarning: "no GitHub release tagged v9.9.9 or 9.9.9 in example/weather"},
+		{name: "release without notes", version: "3.0.0", expectedWarning: "the GitHub release v3.0.0 has no notes"},
+		{name: "notes over the size cap", version: "4.0.0", expectedWarning: "at most 16384 allowed"},
+		{name: "GitHub error", version: "5.0.0", expectedWarning: "GitHub returned status 403"},
+		{
+			name:            "not a GitHub repository",
+			version:         "1.2.0",
+			repository:      model.Repository{URL: "https://gitlab.com/example/weather", Source: "gitlab"},
+			expectedWarning: "needs a GitHub repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := tt.repository
+			if repository.URL == "" {
+				repository = model.Repository{URL: "https://github.com/example/weather.git", Source: "github"}
+			}
+			serverJSON := apiv0.ServerJSON{
+				Name:         "io.github.example/weather",
+				Description:  "Weather lookups",
+				Version:      tt.version,
+				Repository:   repository,
+				ReleaseNotes: tt.releaseNotes,
+			}
+			serverData, err := json.Marshal(serverJSON)
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			filled := fillReleaseNotes(serverData, &serverJSON, &out)
+
+			var published apiv0.ServerJSON
+			require.NoError(t, json.Unmarshal(filled, &published))
+			assert.Equal(t, tt.expectedNotes, published.ReleaseNotes)
+			if tt.expectedWarning != "" {
+				assert.Contains(t, out.String(), "Warning: publishing without release notes: ")
+				assert.Contains(t, out.String(), tt.expectedWarning)
+				assert.Equal(t, serverData, filled, "server.json is published as it was")
+			} else {
+				assert.NotContains(t, out.String(), "Warning")
+			}
+		})
+	}
+}
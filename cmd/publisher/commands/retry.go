@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+const (
+	// defaultMaxAttempts is how many times publish sends a request that fails transiently
+	defaultMaxAttempts = 5
+
+	// defaultRetryDeadline is how long publish keeps retrying in total
+	defaultRetryDeadline = 2 * time.Minute
+)
+
+var (
+	// retryBaseDelay is the base of the jittered exponential backoff between attempts
+	retryBaseDelay = time.Second
+
+	// retryMaxDelay caps the backoff between attempts, but not a longer Retry-After
+	retryMaxDelay = 30 * time.Second
+)
+
+// retryOptions limit how often a request is retried
+type retryOptions struct {
+	maxAttempts int           // attempts in total, including the first
+	deadline    time.Duration // time for all attempts together; 0 for no limit
+}
+
+// retryResult is the last response to a retried request
+type retryResult struct {
+	status   int
+	body     []byte
+	attempts int
+}
+
+// sendWithRetry sends the request built by newRequest, sending it again after connection
+// errors and retryable statuses until opts runs out. Attempts are spaced by jittered
+// exponential backoff, or by the response's Retry-After when that is longer, and each retry
+// is reported on out with its reason. Any other response, such as a validation failure, is
+// returned as is. When the attempts run out the last response is returned, or the last
+// error if there was none.
+func sendWithRetry(opts retryOptions, out io.Writer, newRequest func(context.Context) (*http.Request, error)) (*retryResult, error) {
+	ctx := context.Background()
+	if opts.deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.deadline)
+		defer cancel()
+	}
+	maxAttempts := max(opts.maxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		var last *retryResult
+		var reason string
+		var retryAfter time.Duration
+		resp, err := registryClient.Do(req)
+		if err == nil {
+			var body []byte
+			body, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err == nil {
+				last = &retryResult{status: resp.StatusCode, body: body, attempts: attempt}
+				if resp.StatusCode < http.StatusBadRequest || !apiv0.ParseErrorResponse(resp.StatusCode, body).Retryable {
+					return last, nil
+				}
+				reason = fmt.Sprintf("server returned status %d", resp.StatusCode)
+				retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("error sending request: gave up after %s: %w", opts.deadline, err)
+			}
+			reason = err.Error()
+		}
+
+		delay := max(rand.N(min(retryBaseDelay<<min(attempt-1, 16), retryMaxDelay)), retryAfter)
+		deadline, hasDeadline := ctx.Deadline()
+		if attempt >= maxAttempts || (hasDeadline && time.Now().Add(delay).After(deadline)) {
+			if last != nil {
+				return last, nil
+			}
+			return nil, fmt.Errorf("error sending request: %w", err)
+		}
+
+		_, _ = fmt.Fprintf(out, "Attempt %d of %d failed (%s), retrying in %s...\n", attempt, maxAttempts, reason, delay.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error sending request: gave up after %s: %w", opts.deadline, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date, returning
+// 0 when it is missing or invalid
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+const retryServerJSON = `{"name": "io.github.example/weather", "description": "Weather lookups", "version": "1.0.0"}`
+
+// flakyRegistry answers publishes with the given statuses in turn, then with success. The
+// first publish is saved whatever its status, as when a response is lost on the way back.
+type flakyRegistry struct {
+	mu              sync.Mutex
+	failures        []int
+	retryAfter      string
+	idempotencyKeys []string
+	saved           *apiv0.ServerJSON
+}
+
+func (f *flakyRegistry) start(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v0/publish", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.idempotencyKeys = append(f.idempotencyKeys, r.Header.Get("Idempotency-Key"))
+
+		var server apiv0.ServerJSON
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&server)) {
+			return
+		}
+		if len(f.failures) > 0 && f.failures[0] == 0 {
+			// Drop the connection without answering
+			f.failures = f.failures[1:]
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return
+		}
+		if len(f.failures) > 0 {
+			status := f.failures[0]
+			f.failures = f.failures[1:]
+			if status == http.StatusBadGateway {
+				// The registry saved it, but the load balancer lost the response
+				f.save(server)
+			}
+			// A fresh connection for the next attempt, which net/http would otherwise resend
+			// itself if the connection was dropped
+			w.Header().Set("Connection", "close")
+			if f.retryAfter != "" {
+				w.Header().Set("Retry-After", f.retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		if f.saved != nil {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"detail": "invalid version: cannot publish duplicate version"}`))
+			return
+		}
+		f.save(server)
+		_ = json.NewEncoder(w).Encode(f.saved)
+	})
+	mux.HandleFunc("GET /v0/servers", func(w http.ResponseWriter, _ *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		response := apiv0.ServerListResponse{Servers: []apiv0.ServerJSON{}}
+		if f.saved != nil {
+			response.Servers = append(response.Servers, *f.saved)
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	registry := httptest.NewServer(mux)
+	t.Cleanup(registry.Close)
+	return registry
+}
+
+func (f *flakyRegistry) save(server apiv0.ServerJSON) {
+	server.Meta = &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{ID: "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90", PublishedAt: time.Now()}}
+	f.saved = &server
+}
+
+func TestPublishRetries(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = time.Second }()
+	opts := publishOptions{retryOptions: retryOptions{maxAttempts: 5, deadline: time.Minute}}
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		stub := &flakyRegistry{failures: []int{http.StatusServiceUnavailable, 0, http.StatusGatewayTimeout}}
+		registry := stub.start(t)
+
+		var out bytes.Buffer
+		server, _, err := publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", opts, &out)
+		require.NoError(t, err)
+		assert.Equal(t, "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90", server.GetID())
+
+		assert.Contains(t, out.String(), "Attempt 1 of 5 failed (server returned status 503), retrying in")
+		assert.Contains(t, out.String(), "Attempt 2 of 5 failed (Post ")
+		assert.Contains(t, out.String(), "Attempt 3 of 5 failed (server returned status 504), retrying in")
+		require.Len(t, stub.idempotencyKeys, 4)
+		assert.NotEmpty(t, stub.idempotencyKeys[0])
+		for _, key := range stub.idempotencyKeys {
+			assert.Equal(t, stub.idempotencyKeys[0], key, "every attempt sends the same idempotency key")
+		}
+	})
+
+	t.Run("does not retry validation failures", func(t *testing.T) {
+		stub := &flakyRegistry{failures: []int{http.StatusBadRequest}}
+		registry := stub.start(t)
+
+		var out bytes.Buffer
+		_, _, err := publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", opts, &out)
+		require.ErrorContains(t, err, "server returned status 400")
+		assert.Len(t, stub.idempotencyKeys, 1)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		stub := &flakyRegistry{failures: []int{429, 429, 429, 429, 429, 429}}
+		registry := stub.start(t)
+
+		var out bytes.Buffer
+		_, _, err := publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", publishOptions{retryOptions: retryOptions{maxAttempts: 3}}, &out)
+		require.ErrorContains(t, err, "server returned status 429")
+		assert.Len(t, stub.idempotencyKeys, 3)
+		assert.Contains(t, out.String(), "Attempt 2 of 3 failed")
+		assert.NotContains(t, out.String(), "Attempt 3 of 3 failed")
+	})
+
+	t.Run("gives up when Retry-After is past the deadline", func(t *testing.T) {
+		stub := &flakyRegistry{failures: []int{http.StatusServiceUnavailable}, retryAfter: "120"}
+		registry := stub.start(t)
+
+		_, _, err := publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", publishOptions{retryOptions: retryOptions{maxAttempts: 5, deadline: time.Minute}}, &bytes.Buffer{})
+		require.ErrorContains(t, err, "server returned status 503")
+		assert.Len(t, stub.idempotencyKeys, 1)
+	})
+
+	t.Run("recognizes an earlier attempt that was saved", func(t *testing.T) {
+		stub := &flakyRegistry{failures: []int{http.StatusBadGateway}}
+		registry := stub.start(t)
+
+		var out bytes.Buffer
+		server, _, err := publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", opts, &out)
+		require.NoError(t, err)
+		assert.Equal(t, "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90", server.GetID())
+		assert.Contains(t, out.String(), "An earlier attempt was saved")
+	})
+
+	t.Run("reports a conflict with a version published before", func(t *testing.T) {
+		stub := &flakyRegistry{failures: []int{http.StatusServiceUnavailable}}
+		stub.save(apiv0.ServerJSON{Name: "io.github.example/weather", Version: "1.0.0"})
+		stub.saved.Meta.Official.PublishedAt = time.Now().Add(-time.Hour)
+		registry := stub.start(t)
+
+		_, _, err := publishToRegistry(registry.URL, []byte(retryServerJSON), "test-token", opts, &bytes.Buffer{})
+		require.ErrorContains(t, err, "server returned status 409")
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter("Wed, 01 Oct 2025 12:01:30 GMT", now))
+	assert.Zero(t, parseRetryAfter("Wed, 01 Oct 2025 11:00:00 GMT", now))
+	assert.Zero(t, parseRetryAfter("-5", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+	assert.Zero(t, parseRetryAfter("", now))
+}
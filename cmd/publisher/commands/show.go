@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// showOptions describes a show run
+type showOptions struct {
+	name     string
+	version  string // empty for the latest version
+	raw      bool
+	registry string
+}
+
+// ShowCommand prints a published server version
+func ShowCommand(args []string) error {
+	opts, err := parseShowArgs(args)
+	if err != nil {
+		return err
+	}
+	if opts.registry == "" {
+		// Reading from the registry needs no token, but use the one the user logged in to
+		opts.registry = DefaultRegistryURL
+		if _, registryURL, err := loadSavedToken(); err == nil {
+			opts.registry = registryURL
+		}
+	}
+	return showServer(opts, os.Stdout)
+}
+
+// parseShowArgs parses `<server-name> [flags]` for the show command
+func parseShowArgs(args []string) (showOptions, error) {
+	var opts showOptions
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		opts.name = args[0]
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet("show", flag.ExitOnError)
+	flags.StringVar(&opts.version, "version", "", "Show this version (default: the latest version)")
+	flags.BoolVar(&opts.raw, "raw", false, "Print exactly the server.json that was published, without registry metadata")
+	flags.StringVar(&opts.registry, "registry", "", "Registry URL (default: the registry you logged in to, or "+DefaultRegistryURL+")")
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+	if opts.name == "" && flags.NArg() > 0 {
+		opts.name = flags.Arg(0)
+	}
+
+	if opts.name == "" {
+		return opts, errors.New("server name required\n\nUsage: mcp-publisher show <server-name> [--version=VERSION] [--raw]")
+	}
+	return opts, nil
+}
+
+// showServer prints a server version as the registry returns it, or with raw, as it was published
+func showServer(opts showOptions, out io.Writer) error {
+	registryURL := strings.TrimSuffix(opts.registry, "/")
+
+	version := opts.version
+	if version == "" {
+		version = "latest"
+	}
+	versions, err := fetchServerVersions(registryURL, opts.name, version)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		if opts.version != "" {
+			return fmt.Errorf("server %s has no version %s", opts.name, opts.version)
+		}
+		return fmt.Errorf("server %s not found", opts.name)
+	}
+	server := versions[0]
+
+	if !opts.raw {
+		data, err := json.MarshalIndent(server, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error serializing server: %w", err)
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	}
+
+	document, err := fetchServerDocument(registryURL, server.GetID())
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(document)
+	return err
+}
+
+// fetchServerDocument downloads the server.json a server version was published with
+func fetchServerDocument(registryURL, id string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, registryURL+"/v0/servers/"+url.PathEscape(id)+"/server.json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := registryClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching server.json: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, registryError(resp.StatusCode, body)
+	}
+	return body, nil
+}
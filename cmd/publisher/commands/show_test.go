@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestShowServer(t *testing.T) {
+	const id = "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90"
+	server := apiv0.ServerJSON{
+		Name:        "io.github.example/weather",
+		Description: "Weather lookups",
+		Version:     "2.0.0",
+		Meta: &apiv0.ServerMeta{
+			Official: &apiv0.RegistryExtensions{ID: id, PublishedAt: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), IsLatest: true},
+		},
+	}
+	// The document is served verbatim, so show --raw must not reformat it
+	document := []byte("{\n  \"name\": \"io.github.example/weather\",\n  \"description\": \"Weather lookups\",\n  \"version\": \"2.0.0\"\n}\n")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v0/servers", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "latest", r.URL.Query().Get("version"))
+		_ = json.NewEncoder(w).Encode(apiv0.ServerListResponse{Servers: []apiv0.ServerJSON{server}})
+	})
+	mux.HandleFunc("GET /v0/servers/{id}/server.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != id {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(document)
+	})
+	registry := httptest.NewServer(mux)
+	defer registry.Close()
+
+	t.Run("raw prints the published document unchanged", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, showServer(showOptions{name: server.Name, raw: true, registry: registry.URL}, &out))
+		assert.Equal(t, document, out.Bytes())
+	})
+
+	t.Run("default includes registry metadata", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, showServer(showOptions{name: server.Name, registry: registry.URL}, &out))
+		assert.Contains(t, out.String(), "io.modelcontextprotocol.registry/official")
+		assert.Contains(t, out.String(), id)
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		var out bytes.Buffer
+		err := showServer(showOptions{name: "io.github.example/missing", registry: registry.URL}, &out)
+		assert.ErrorContains(t, err, "not found")
+	})
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NoTraceEnv disables trace propagation when set to any non-empty value
+const NoTraceEnv = "MCP_PUBLISHER_NO_TRACE"
+
+// activeTrace is the span every registry request of this run belongs to. It is invalid,
+// and no trace headers are sent, until StartTrace is called.
+var activeTrace trace.SpanContext
+
+// registryClient sends requests to the registry with the run's W3C trace context
+var registryClient = &http.Client{Transport: traceTransport{base: http.DefaultTransport}}
+
+// StartTrace begins the trace for this run. A trace context in the TRACEPARENT and TRACESTATE
+// environment variables, as set by some CI systems, is continued; otherwise a new trace is
+// started. Setting MCP_PUBLISHER_NO_TRACE disables tracing.
+func StartTrace() {
+	activeTrace = newSpanContext(os.Getenv)
+}
+
+// TraceID returns the ID of the run's trace, or "" when there is none
+func TraceID() string {
+	if !activeTrace.IsValid() {
+		return ""
+	}
+	return activeTrace.TraceID().String()
+}
+
+// newSpanContext returns a new span in the trace named by the environment, or in a new trace
+func newSpanContext(getenv func(string) string) trace.SpanContext {
+	if getenv(NoTraceEnv) != "" {
+		return trace.SpanContext{}
+	}
+
+	parent := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{
+		"traceparent": getenv("TRACEPARENT"),
+		"tracestate":  getenv("TRACESTATE"),
+	}))
+
+	config := trace.SpanContextConfig{TraceFlags: trace.FlagsSampled}
+	if parent.IsValid() {
+		config.TraceID = parent.TraceID()
+		config.TraceFlags = parent.TraceFlags()
+		config.TraceState = parent.TraceState()
+	} else {
+		_, _ = rand.Read(config.TraceID[:])
+	}
+	_, _ = rand.Read(config.SpanID[:])
+	return trace.NewSpanContext(config)
+}
+
+// traceTransport adds the traceparent and tracestate headers of the run's trace to each request
+type traceTransport struct {
+	base http.RoundTripper
+}
+
+func (t traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !activeTrace.IsValid() {
+		return t.base.RoundTrip(req)
+	}
+	ctx := trace.ContextWithSpanContext(req.Context(), activeTrace)
+	req = req.Clone(ctx)
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestTracePropagation(t *testing.T) {
+	saved := activeTrace
+	defer func() { activeTrace = saved }()
+
+	// The stub registry records the traceparent of every request it receives
+	var mu sync.Mutex
+	var traceparents []string
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(apiv0.ServerJSON{Name: "io.github.example/traced", Version: "1.0.0"})
+	}))
+	defer registry.Close()
+
+	run := func(t *testing.T, env map[string]string) []string {
+		t.Helper()
+		activeTrace = newSpanContext(func(key string) string { return env[key] })
+		mu.Lock()
+		traceparents = nil
+		mu.Unlock()
+
+		_, _, err := publishToRegistry(registry.URL, []byte(`{"name": "io.github.example/traced", "version": "1.0.0"}`), "test-token", publishOptions{retryOptions: retryOptions{maxAttempts: 1}}, io.Discard)
+		require.NoError(t, err)
+		_, err = fetchServerDocument(registry.URL, "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90")
+		require.NoError(t, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return traceparents
+	}
+
+	t.Run("continues the CI trace", func(t *testing.T) {
+		const ciTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+		headers := run(t, map[string]string{"TRACEPARENT": "00-" + ciTrace + "-00f067aa0ba902b7-01"})
+		require.Len(t, headers, 2)
+		for _, header := range headers {
+			parts := strings.Split(header, "-")
+			require.Len(t, parts, 4, header)
+			assert.Equal(t, ciTrace, parts[1])
+			assert.NotEqual(t, "00f067aa0ba902b7", parts[2], "requests are sent from the publisher's own span")
+			assert.Equal(t, "01", parts[3])
+		}
+		assert.Equal(t, headers[0], headers[1], "every request of a run belongs to the same span")
+		assert.Equal(t, ciTrace, TraceID())
+	})
+
+	t.Run("starts a new trace", func(t *testing.T) {
+		headers := run(t, map[string]string{"TRACEPARENT": "not a traceparent"})
+		require.Len(t, headers, 2)
+		assert.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`, headers[0])
+		assert.Equal(t, strings.Split(headers[0], "-")[1], TraceID())
+		assert.NotEqual(t, "00000000000000000000000000000000", TraceID())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		headers := run(t, map[string]string{NoTraceEnv: "1", "TRACEPARENT": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"})
+		assert.Equal(t, []string{"", ""}, headers)
+		assert.Empty(t, TraceID())
+	})
+}
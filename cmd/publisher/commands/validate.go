@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+)
+
+// ValidateCommand checks server.json locally without publishing it
+func ValidateCommand(args []string) error {
+	serverFile, fileOpts, err := parseServerFileArgs("validate", args, nil)
+	if err != nil {
+		return err
+	}
+
+	serverData, serverJSON, err := readServerJSON(serverFile)
+	if err != nil {
+		return err
+	}
+
+	if err := checkUnknownFields(serverData, fileOpts.allowUnknownFields, os.Stderr); err != nil {
+		return err
+	}
+	if err := checkManifestVersions(serverJSON, fileOpts.versions, os.Stderr); err != nil {
+		return err
+	}
+	if err := checkReleaseTags(serverJSON); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "✓ %s is valid\n", serverFile)
+	return nil
+}
@@ -27,16 +27,30 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Registry requests carry a trace context so a publish can be followed into the registry
+	commands.StartTrace()
+	commands.UserAgent = "mcp-publisher/" + Version
+
 	var err error
 	switch os.Args[1] {
 	case "init":
-		err = commands.InitCommand()
+		err = commands.InitCommand(os.Args[2:])
 	case "login":
 		err = commands.LoginCommand(os.Args[2:])
 	case "logout":
 		err = commands.LogoutCommand()
 	case "publish":
 		err = commands.PublishCommand(os.Args[2:])
+	case "validate":
+		err = commands.ValidateCommand(os.Args[2:])
+	case "deprecate":
+		err = commands.DeprecateCommand(os.Args[2:])
+	case "undeprecate":
+		err = commands.UndeprecateCommand(os.Args[2:])
+	case "show":
+		err = commands.ShowCommand(os.Args[2:])
+	case "config-schema":
+		err = commands.ConfigSchemaCommand(os.Args[2:])
 	case "--version", "-v", "version":
 		log.Printf("mcp-publisher %s (commit: %s, built: %s)", Version, GitCommit, BuildTime)
 		return
@@ -65,6 +79,11 @@ func printUsage() {
 	_, _ = fmt.Fprintln(os.Stdout, "  login         Authenticate with the registry")
 	_, _ = fmt.Fprintln(os.Stdout, "  logout        Clear saved authentication")
 	_, _ = fmt.Fprintln(os.Stdout, "  publish       Publish server.json to the registry")
+	_, _ = fmt.Fprintln(os.Stdout, "  validate      Check server.json locally without publishing")
+	_, _ = fmt.Fprintln(os.Stdout, "  deprecate     Mark versions of a published server as deprecated")
+	_, _ = fmt.Fprintln(os.Stdout, "  undeprecate   Mark deprecated versions of a server as active again")
+	_, _ = fmt.Fprintln(os.Stdout, "  show          Print a published server version")
+	_, _ = fmt.Fprintln(os.Stdout, "  config-schema Print the JSON Schema for configuring server.json's packages and remotes")
 	_, _ = fmt.Fprintln(os.Stdout)
 	_, _ = fmt.Fprintln(os.Stdout, "Use 'mcp-publisher <command> --help' for more information about a command.")
 }
\ No newline at end of file
@@ -8,15 +8,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/modelcontextprotocol/registry/internal/api"
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
-	"github.com/modelcontextprotocol/registry/internal/importer"
-	"github.com/modelcontextprotocol/registry/internal/service"
-	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	"github.com/modelcontextprotocol/registry/pkg/registry"
 )
 
 // Version info for the MCP Registry application
@@ -35,6 +34,17 @@ var (
 func main() {
 	// Parse command line flags
 	showVersion := flag.Bool("version", false, "Display version information")
+	migrateOnly := flag.Bool("migrate-only", false, "Apply pending database migrations and exit without serving")
+	var seedOnly, seedExclude []string
+	flag.Func("only", "Only import seed servers whose names match this glob, such as io.github.acme/* (repeatable, overrides MCP_REGISTRY_SEED_ONLY)", func(value string) error {
+		seedOnly = append(seedOnly, strings.Split(value, ",")...)
+		return nil
+	})
+	flag.Func("exclude", "Skip seed servers whose names match this glob (repeatable, overrides MCP_REGISTRY_SEED_EXCLUDE)", func(value string) error {
+		seedExclude = append(seedExclude, strings.Split(value, ",")...)
+		return nil
+	})
+	seedLimit := flag.Int("limit", 0, "Import at most this many seed servers (overrides MCP_REGISTRY_SEED_LIMIT)")
 	flag.Parse()
 
 	// Show version information if requested
@@ -47,75 +57,40 @@ func main() {
 
 	log.Printf("Starting MCP Registry Application v%s (commit: %s)", Version, GitCommit)
 
-	var (
-		registryService service.RegistryService
-		db              database.Database
-		err             error
-	)
-
-	// Initialize configuration
+	// Initialize and validate configuration before constructing anything that depends on it
 	cfg := config.NewConfig()
-
-	// Initialize services based on environment
-	switch cfg.DatabaseType {
-	case config.DatabaseTypeMemory:
-		db = database.NewMemoryDB()
-	case config.DatabaseTypePostgreSQL:
-		// Use PostgreSQL for real registry service
-		// Create a context with timeout for PostgreSQL connection
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		// Connect to PostgreSQL
-		db, err = database.NewPostgreSQL(ctx, cfg.DatabaseURL)
-		if err != nil {
-			log.Printf("Failed to connect to PostgreSQL: %v", err)
-			return
+	cfg.Build = config.BuildInfo{Version: Version, GitCommit: GitCommit, BuildTime: BuildTime}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "only":
+			cfg.SeedOnly = seedOnly
+		case "exclude":
+			cfg.SeedExclude = seedExclude
+		case "limit":
+			cfg.SeedLimit = *seedLimit
 		}
-
-		// Store the PostgreSQL instance for later cleanup
-		defer func() {
-			if err := db.Close(); err != nil {
-				log.Printf("Error closing PostgreSQL connection: %v", err)
-			} else {
-				log.Println("PostgreSQL connection closed successfully")
-			}
-		}()
-	default:
-		log.Printf("Invalid database type: %s; supported types: %s, %s", cfg.DatabaseType, config.DatabaseTypeMemory, config.DatabaseTypePostgreSQL)
+	})
+	if err := cfg.Validate(); err != nil {
+		log.Printf("%v", err)
 		return
 	}
 
-	registryService = service.NewRegistryService(db, cfg)
-
-	// Import seed data if seed source is provided
-	if cfg.SeedFrom != "" {
-		log.Printf("Importing data from %s...", cfg.SeedFrom)
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		defer cancel()
-
-		importerService := importer.NewService(db)
-		if err := importerService.ImportFromPath(ctx, cfg.SeedFrom); err != nil {
-			log.Printf("Failed to import seed data: %v", err)
-		} else {
-			log.Println("Data import completed successfully")
-		}
+	// Migrations are applied when connecting, so there is nothing left to do
+	if *migrateOnly {
+		migrate(cfg)
+		return
 	}
 
-	shutdownTelemetry, metrics, err := telemetry.InitMetrics(cfg.Version)
+	// Assemble the registry: database, seed data, telemetry, services and routes
+	reg, err := registry.New(context.Background(), registry.WithConfig(cfg))
 	if err != nil {
-		log.Printf("Failed to initialize metrics: %v", err)
+		log.Printf("Failed to initialize registry: %v", err)
 		return
 	}
-
-	defer func() {
-		if err := shutdownTelemetry(context.Background()); err != nil {
-			log.Printf("Failed to shutdown telemetry: %v", err)
-		}
-	}()
+	reg.Start()
 
 	// Initialize HTTP server
-	server := api.NewServer(cfg, registryService, metrics)
+	server := api.NewServer(cfg, reg)
 
 	// Start server in a goroutine so it doesn't block signal handling
 	go func() {
@@ -132,14 +107,50 @@ func main() {
 	<-quit
 	log.Println("Shutting down server...")
 
-	// Create context with timeout for shutdown
-	sctx, scancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// In-flight requests, then background jobs and notification delivery, share one deadline
+	sctx, scancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer scancel()
 
-	// Gracefully shutdown the server
+	// Stop taking requests and let the ones being served finish
 	if err := server.Shutdown(sctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop the background jobs, then close the database and telemetry
+	if err := reg.Shutdown(sctx); err != nil {
+		log.Printf("Error shutting down registry: %v", err)
+	}
+
 	log.Println("Server exiting")
 }
+
+// migrate connects to the configured database, which applies any pending migrations, and disconnects
+func migrate(cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch cfg.DatabaseType {
+	case config.DatabaseTypePostgreSQL:
+		db, err := database.NewPostgreSQL(ctx, cfg.DatabaseURL, database.PoolOptionsFromConfig(cfg))
+		if err != nil {
+			log.Printf("Failed to connect to PostgreSQL: %v", err)
+			return
+		}
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing PostgreSQL connection: %v", err)
+		}
+	case config.DatabaseTypeSQLite:
+		db, err := database.NewSQLite(ctx, cfg.SQLitePath)
+		if err != nil {
+			log.Printf("Failed to open SQLite database: %v", err)
+			return
+		}
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing SQLite database: %v", err)
+		}
+	default:
+		log.Printf("--migrate-only has no effect with the %s database", cfg.DatabaseType)
+		return
+	}
+	log.Println("Database migrations are up to date, exiting (--migrate-only)")
+}
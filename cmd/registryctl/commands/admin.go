@@ -0,0 +1,522 @@
+// Package commands implements the registryctl operator commands
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const (
+	// DefaultRegistryURL is the registry admin commands run against unless --registry or REGISTRY_URL is set
+	DefaultRegistryURL = "https://registry.modelcontextprotocol.io"
+
+	// TokenEnv holds the admin Registry JWT, as printed by tools/admin/auth.sh
+	TokenEnv = "REGISTRY_TOKEN"
+)
+
+const adminUsage = `Usage: registryctl admin <command> [arguments] [flags]
+
+Commands:
+  pending list               List server versions held for approval
+  pending approve <id>       Approve a held server version, making it active
+  pending reject <id>        Reject a held server version (--reason is shown to its publisher)
+  retention preview          Show which versions the retention policy would remove
+  pin <id>                   Exempt a server version from retention
+  unpin <id>                 Remove a retention exemption
+  takedown <id>              Soft delete a server version
+  jwks                       List the keys accepted for Registry JWTs
+  export -o PATH             Export public server versions as a seed file, with its signature at PATH.sig
+
+Flags for every command:
+  --registry URL             Registry URL (default: $REGISTRY_URL, or ` + DefaultRegistryURL + `)
+  --token-file PATH          Read the admin Registry JWT from a file (default: $` + TokenEnv + `)
+  --json                     Print JSON instead of a table
+`
+
+// adminOptions are the flags shared by every admin command
+type adminOptions struct {
+	registry  string
+	tokenFile string
+	json      bool
+}
+
+// adminClient sends authenticated requests to a registry's admin API
+type adminClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// AdminCommand runs `registryctl admin <command>`, writing results to out
+func AdminCommand(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" || args[0] == "-h" {
+		_, err := io.WriteString(out, adminUsage)
+		return err
+	}
+
+	command := args[0]
+	args = args[1:]
+	if command == "pending" || command == "retention" {
+		if len(args) == 0 {
+			return fmt.Errorf("%s requires a subcommand\n\n%s", command, adminUsage)
+		}
+		command += " " + args[0]
+		args = args[1:]
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "pending list":
+		opts, _, err := parseAdminArgs(command, args, nil, 0)
+		if err != nil {
+			return err
+		}
+		return withClient(opts, func(c *adminClient) error { return listPending(ctx, c, opts, out) })
+	case "pending approve":
+		opts, id, err := parseAdminArgs(command, args, nil, 1)
+		if err != nil {
+			return err
+		}
+		return withClient(opts, func(c *adminClient) error { return approvePending(ctx, c, id, opts, out) })
+	case "pending reject":
+		var reason string
+		opts, id, err := parseAdminArgs(command, args, func(flags *flag.FlagSet) {
+			flags.StringVar(&reason, "reason", "", "Why the version was rejected, shown to its publisher")
+		}, 1)
+		if err != nil {
+			return err
+		}
+		if reason == "" {
+			return fmt.Errorf("--reason is required")
+		}
+		return withClient(opts, func(c *adminClient) error { return rejectPending(ctx, c, id, reason, opts, out) })
+	case "retention preview":
+		var keepVersions, keepDays int
+		opts, _, err := parseAdminArgs(command, args, func(flags *flag.FlagSet) {
+			flags.IntVar(&keepVersions, "keep-versions", 0, "Override the number of newest versions kept per server")
+			flags.IntVar(&keepDays, "keep-days", 0, "Override the age in days under which versions are always kept")
+		}, 0)
+		if err != nil {
+			return err
+		}
+		return withClient(opts, func(c *adminClient) error {
+			return previewRetention(ctx, c, keepVersions, keepDays, opts, out)
+		})
+	case "pin", "unpin":
+		opts, id, err := parseAdminArgs(command, args, nil, 1)
+		if err != nil {
+			return err
+		}
+		return withClient(opts, func(c *adminClient) error { return setPinned(ctx, c, id, command == "pin", opts, out) })
+	case "takedown":
+		opts, id, err := parseAdminArgs(command, args, nil, 1)
+		if err != nil {
+			return err
+		}
+		return withClient(opts, func(c *adminClient) error { return takedown(ctx, c, id, opts, out) })
+	case "jwks":
+		opts, _, err := parseAdminArgs(command, args, nil, 0)
+		if err != nil {
+			return err
+		}
+		// The key set is public, so no token is needed
+		client := &adminClient{baseURL: opts.registry, client: &http.Client{Timeout: 30 * time.Second}}
+		return listKeys(ctx, client, opts, out)
+	case "export":
+		var output string
+		opts, _, err := parseAdminArgs(command, args, func(flags *flag.FlagSet) {
+			flags.StringVar(&output, "o", "", "File to write the seed to; its signature is written to <file>.sig")
+		}, 0)
+		if err != nil {
+			return err
+		}
+		if output == "" {
+			return fmt.Errorf("-o is required")
+		}
+		return withClient(opts, func(c *adminClient) error { return exportSeed(ctx, c, output, out) })
+	default:
+		return fmt.Errorf("unknown admin command: %s\n\n%s", command, adminUsage)
+	}
+}
+
+// parseAdminArgs parses the shared flags, any command-specific ones, and a server ID when
+// the command takes one. The ID may come before or after the flags.
+func parseAdminArgs(command string, args []string, extra func(*flag.FlagSet), positional int) (adminOptions, string, error) {
+	var opts adminOptions
+	var id string
+	if positional > 0 && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		id = args[0]
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.StringVar(&opts.registry, "registry", "", "Registry URL (default: $REGISTRY_URL, or "+DefaultRegistryURL+")")
+	flags.StringVar(&opts.tokenFile, "token-file", "", "Read the admin Registry JWT from a file (default: $"+TokenEnv+")")
+	flags.BoolVar(&opts.json, "json", false, "Print JSON instead of a table")
+	if extra != nil {
+		extra(flags)
+	}
+	if err := flags.Parse(args); err != nil {
+		return opts, "", err
+	}
+	if id == "" && flags.NArg() > 0 {
+		id = flags.Arg(0)
+	}
+	if positional > 0 && id == "" {
+		return opts, "", fmt.Errorf("server ID required\n\nUsage: registryctl admin %s <id> [flags]", command)
+	}
+
+	if opts.registry == "" {
+		opts.registry = os.Getenv("REGISTRY_URL")
+	}
+	if opts.registry == "" {
+		opts.registry = DefaultRegistryURL
+	}
+	opts.registry = strings.TrimSuffix(opts.registry, "/")
+	return opts, id, nil
+}
+
+// withClient resolves the admin token and runs fn with a client for the registry
+func withClient(opts adminOptions, fn func(*adminClient) error) error {
+	token, err := adminToken(opts)
+	if err != nil {
+		return err
+	}
+	return fn(&adminClient{baseURL: opts.registry, token: token, client: &http.Client{Timeout: 30 * time.Second}})
+}
+
+// adminToken reads the admin Registry JWT from --token-file, or from the environment
+func adminToken(opts adminOptions) (string, error) {
+	if opts.tokenFile != "" {
+		data, err := os.ReadFile(opts.tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", opts.tokenFile)
+		}
+		return token, nil
+	}
+	if token := strings.TrimSpace(os.Getenv(TokenEnv)); token != "" {
+		return token, nil
+	}
+	return "", fmt.Errorf("no admin token: set %s (see tools/admin/auth.sh) or pass --token-file", TokenEnv)
+}
+
+// do sends a request to the registry and decodes a successful JSON response into result.
+// Error responses are reported with the problem detail the registry returned.
+func (c *adminClient) do(ctx context.Context, method, path string, body, result any) error {
+	data, _, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("invalid response from %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// send sends a request to the registry and returns the body and headers of a successful response
+func (c *adminClient) send(ctx context.Context, method, path string, body any) ([]byte, http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error serializing request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var problem struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		}
+		message := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &problem) == nil && (problem.Detail != "" || problem.Title != "") {
+			message = problem.Detail
+			if message == "" {
+				message = problem.Title
+			}
+		}
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			return nil, nil, fmt.Errorf("registry token is invalid or expired (%s)", message)
+		case http.StatusForbidden:
+			return nil, nil, fmt.Errorf("registry token is not an admin token (%s)", message)
+		default:
+			return nil, nil, fmt.Errorf("%s %s: registry returned status %d: %s", method, path, resp.StatusCode, message)
+		}
+	}
+	return data, resp.Header, nil
+}
+
+// listPending prints every server version held for approval
+func listPending(ctx context.Context, c *adminClient, opts adminOptions, out io.Writer) error {
+	servers := []apiv0.ServerJSON{}
+	cursor := ""
+	for {
+		query := url.Values{"limit": {"100"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		var page apiv0.ServerListResponse
+		if err := c.do(ctx, http.MethodGet, "/v0/admin/pending?"+query.Encode(), nil, &page); err != nil {
+			return err
+		}
+		servers = append(servers, page.Servers...)
+		if page.Metadata.NextCursor == "" {
+			break
+		}
+		cursor = page.Metadata.NextCursor
+	}
+
+	if opts.json {
+		return writeJSON(out, servers)
+	}
+	rows := make([][]string, 0, len(servers))
+	for i := range servers {
+		rows = append(rows, []string{servers[i].GetID(), servers[i].Name, servers[i].Version, publishedAt(&servers[i])})
+	}
+	return writeTable(out, []string{"ID", "NAME", "VERSION", "PUBLISHED"}, rows)
+}
+
+// approvePending releases a held server version
+func approvePending(ctx context.Context, c *adminClient, id string, opts adminOptions, out io.Writer) error {
+	var server apiv0.ServerJSON
+	if err := c.do(ctx, http.MethodPost, "/v0/admin/servers/"+url.PathEscape(id)+"/approve", nil, &server); err != nil {
+		return err
+	}
+	if opts.json {
+		return writeJSON(out, server)
+	}
+	_, err := fmt.Fprintf(out, "Approved %s %s (%s)\n", server.Name, server.Version, id)
+	return err
+}
+
+// rejectPending declines a held server version, recording the reason for its publisher
+func rejectPending(ctx context.Context, c *adminClient, id, reason string, opts adminOptions, out io.Writer) error {
+	var server apiv0.ServerJSON
+	body := map[string]string{"reason": reason}
+	if err := c.do(ctx, http.MethodPost, "/v0/admin/servers/"+url.PathEscape(id)+"/reject", body, &server); err != nil {
+		return err
+	}
+	if opts.json {
+		return writeJSON(out, server)
+	}
+	_, err := fmt.Fprintf(out, "Rejected %s %s (%s)\n", server.Name, server.Version, id)
+	return err
+}
+
+// retentionPreview is the registry's dry-run report of the retention policy
+type retentionPreview struct {
+	KeepVersions int `json:"keep_versions"`
+	KeepDays     int `json:"keep_days"`
+	Count        int `json:"count"`
+	Versions     []struct {
+		ID          string    `json:"id"`
+		Name        string    `json:"name"`
+		Version     string    `json:"version"`
+		PublishedAt time.Time `json:"published_at"`
+	} `json:"versions"`
+}
+
+// previewRetention prints the versions the retention policy would soft delete, without deleting them
+func previewRetention(ctx context.Context, c *adminClient, keepVersions, keepDays int, opts adminOptions, out io.Writer) error {
+	query := url.Values{}
+	if keepVersions > 0 {
+		query.Set("keep_versions", strconv.Itoa(keepVersions))
+	}
+	if keepDays > 0 {
+		query.Set("keep_days", strconv.Itoa(keepDays))
+	}
+	path := "/v0/admin/retention"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var preview retentionPreview
+	if err := c.do(ctx, http.MethodGet, path, nil, &preview); err != nil {
+		return err
+	}
+	if opts.json {
+		return writeJSON(out, preview)
+	}
+
+	rows := make([][]string, 0, len(preview.Versions))
+	for _, version := range preview.Versions {
+		rows = append(rows, []string{version.ID, version.Name, version.Version, version.PublishedAt.UTC().Format(time.RFC3339)})
+	}
+	if err := writeTable(out, []string{"ID", "NAME", "VERSION", "PUBLISHED"}, rows); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\n%d versions would be removed (keep_versions=%d, keep_days=%d)\n", preview.Count, preview.KeepVersions, preview.KeepDays)
+	return err
+}
+
+// setPinned pins or unpins a server version
+func setPinned(ctx context.Context, c *adminClient, id string, pinned bool, opts adminOptions, out io.Writer) error {
+	var server apiv0.ServerJSON
+	body := map[string]bool{"pinned": pinned}
+	if err := c.do(ctx, http.MethodPut, "/v0/servers/"+url.PathEscape(id)+"/pin", body, &server); err != nil {
+		return err
+	}
+	if opts.json {
+		return writeJSON(out, server)
+	}
+	action := "Pinned"
+	if !pinned {
+		action = "Unpinned"
+	}
+	_, err := fmt.Fprintf(out, "%s %s %s (%s)\n", action, server.Name, server.Version, id)
+	return err
+}
+
+// takedown soft deletes a server version by setting its status to deleted with the edit endpoint
+func takedown(ctx context.Context, c *adminClient, id string, opts adminOptions, out io.Writer) error {
+	path := "/v0/servers/" + url.PathEscape(id)
+	var server apiv0.ServerJSON
+	if err := c.do(ctx, http.MethodGet, path, nil, &server); err != nil {
+		return err
+	}
+
+	// Registry metadata is maintained by the registry, so only send back what the publisher provided
+	edited := server
+	edited.Status = model.StatusDeleted
+	edited.Meta = nil
+	if server.Meta != nil && server.Meta.PublisherProvided != nil {
+		edited.Meta = &apiv0.ServerMeta{PublisherProvided: server.Meta.PublisherProvided}
+	}
+
+	var updated apiv0.ServerJSON
+	if err := c.do(ctx, http.MethodPut, path, edited, &updated); err != nil {
+		return err
+	}
+	if opts.json {
+		return writeJSON(out, updated)
+	}
+	_, err := fmt.Fprintf(out, "Took down %s %s (%s)\n", updated.Name, updated.Version, id)
+	return err
+}
+
+// jsonWebKeySet is the registry's JWKS document
+type jsonWebKeySet struct {
+	Keys []struct {
+		KeyType   string `json:"kty"`
+		Curve     string `json:"crv"`
+		KeyID     string `json:"kid"`
+		Algorithm string `json:"alg"`
+		Use       string `json:"use"`
+	} `json:"keys"`
+}
+
+// listKeys prints the keys the registry accepts for Registry JWTs, the signing key first
+func listKeys(ctx context.Context, c *adminClient, opts adminOptions, out io.Writer) error {
+	var keys jsonWebKeySet
+	if err := c.do(ctx, http.MethodGet, "/v0/admin/jwks", nil, &keys); err != nil {
+		return err
+	}
+	if opts.json {
+		return writeJSON(out, keys)
+	}
+	rows := make([][]string, 0, len(keys.Keys))
+	for i, key := range keys.Keys {
+		role := "rotating out"
+		if i == 0 {
+			role = "signing"
+		}
+		rows = append(rows, []string{key.KeyID, key.Algorithm, key.Curve, role})
+	}
+	return writeTable(out, []string{"KID", "ALG", "CURVE", "ROLE"}, rows)
+}
+
+// exportSeed downloads a signed export of the registry, writing the seed to path and its detached
+// signature to path.sig, ready for MCP_REGISTRY_SEED_FROM and MCP_REGISTRY_SEED_SIGNATURE_FROM
+func exportSeed(ctx context.Context, c *adminClient, path string, out io.Writer) error {
+	seed, header, err := c.send(ctx, http.MethodGet, "/v0/admin/export", nil)
+	if err != nil {
+		return err
+	}
+	signature := header.Get("Seed-Signature")
+	if signature == "" {
+		return fmt.Errorf("registry did not sign the export")
+	}
+	if err := os.WriteFile(path, seed, 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".sig", []byte(signature+"\n"), 0o600); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Exported %d bytes to %s, signature in %s.sig\n", len(seed), path, path)
+	return err
+}
+
+func publishedAt(server *apiv0.ServerJSON) string {
+	if server.Meta == nil || server.Meta.Official == nil {
+		return ""
+	}
+	return server.Meta.Official.PublishedAt.UTC().Format(time.RFC3339)
+}
+
+// writeTable prints rows as aligned columns under a header
+func writeTable(out io.Writer, header []string, rows [][]string) error {
+	if len(rows) == 0 {
+		_, err := io.WriteString(out, "No results\n")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func writeJSON(out io.Writer, v any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("error serializing output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const testToken = "admin-token"
+
+// stubAdminAPI records requests and answers them with the handler registered for the route
+type stubAdminAPI struct {
+	server   *httptest.Server
+	requests []*http.Request
+	bodies   []string
+}
+
+func newStubAdminAPI(t *testing.T, routes map[string]http.HandlerFunc) *stubAdminAPI {
+	t.Helper()
+	stub := &stubAdminAPI{}
+	mux := http.NewServeMux()
+	for pattern, handler := range routes {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			stub.requests = append(stub.requests, r)
+			stub.bodies = append(stub.bodies, string(body))
+			handler(w, r)
+		})
+	}
+	stub.server = httptest.NewServer(mux)
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func respondJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func testServer(id, name, version string, status model.Status) apiv0.ServerJSON {
+	return apiv0.ServerJSON{
+		Name:        name,
+		Description: "A test server",
+		Version:     version,
+		Status:      status,
+		Meta: &apiv0.ServerMeta{
+			PublisherProvided: map[string]any{"tool": "ci"},
+			Official: &apiv0.RegistryExtensions{
+				ID:          id,
+				PublishedAt: time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func runAdmin(t *testing.T, stub *stubAdminAPI, args ...string) (string, error) {
+	t.Helper()
+	t.Setenv(TokenEnv, testToken)
+	var out bytes.Buffer
+	err := AdminCommand(append(args, "--registry", stub.server.URL+"/"), &out)
+	return out.String(), err
+}
+
+func TestAdminPendingList(t *testing.T) {
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"GET /v0/admin/pending": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("cursor") == "" {
+				respondJSON(w, http.StatusOK, apiv0.ServerListResponse{
+					Servers:  []apiv0.ServerJSON{testServer("id-1", "io.github.acme/foo", "1.0.0", model.StatusPending)},
+					Metadata: apiv0.Metadata{NextCursor: "id-1", Count: 1},
+				})
+				return
+			}
+			respondJSON(w, http.StatusOK, apiv0.ServerListResponse{
+				Servers:  []apiv0.ServerJSON{testServer("id-2", "io.github.acme/a-much-longer-name", "2.0.0", model.StatusPending)},
+				Metadata: apiv0.Metadata{Count: 1},
+			})
+		},
+	})
+
+	out, err := runAdmin(t, stub, "pending", "list")
+	require.NoError(t, err)
+
+	require.Len(t, stub.requests, 2, "pages through every pending version")
+	for _, r := range stub.requests {
+		assert.Equal(t, "Bearer "+testToken, r.Header.Get("Authorization"))
+		assert.Equal(t, "100", r.URL.Query().Get("limit"))
+	}
+	assert.Equal(t, "id-1", stub.requests[1].URL.Query().Get("cursor"))
+
+	assert.Equal(t, ""+
+		"ID    NAME                               VERSION  PUBLISHED\n"+
+		"id-1  io.github.acme/foo                 1.0.0    2025-09-01T12:00:00Z\n"+
+		"id-2  io.github.acme/a-much-longer-name  2.0.0    2025-09-01T12:00:00Z\n", out)
+
+	out, err = runAdmin(t, stub, "pending", "list", "--json")
+	require.NoError(t, err)
+	var servers []apiv0.ServerJSON
+	require.NoError(t, json.Unmarshal([]byte(out), &servers))
+	assert.Len(t, servers, 2)
+}
+
+func TestAdminPendingApprove(t *testing.T) {
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"POST /v0/admin/servers/{id}/approve": func(w http.ResponseWriter, r *http.Request) {
+			respondJSON(w, http.StatusOK, testServer(r.PathValue("id"), "io.github.acme/foo", "1.0.0", model.StatusActive))
+		},
+	})
+
+	out, err := runAdmin(t, stub, "pending", "approve", "id-1")
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, "/v0/admin/servers/id-1/approve", stub.requests[0].URL.Path)
+	assert.Equal(t, "Approved io.github.acme/foo 1.0.0 (id-1)\n", out)
+
+	_, err = runAdmin(t, stub, "pending", "approve")
+	assert.ErrorContains(t, err, "server ID required")
+}
+
+func TestAdminPendingReject(t *testing.T) {
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"POST /v0/admin/servers/{id}/reject": func(w http.ResponseWriter, r *http.Request) {
+			respondJSON(w, http.StatusOK, testServer(r.PathValue("id"), "io.github.acme/foo", "1.0.0", model.StatusRejected))
+		},
+	})
+
+	out, err := runAdmin(t, stub, "pending", "reject", "id-1", "--reason", "Spam")
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 1)
+	assert.JSONEq(t, `{"reason": "Spam"}`, stub.bodies[0])
+	assert.Equal(t, "Rejected io.github.acme/foo 1.0.0 (id-1)\n", out)
+
+	_, err = runAdmin(t, stub, "pending", "reject", "id-1")
+	assert.ErrorContains(t, err, "--reason is required")
+}
+
+func TestAdminRetentionPreview(t *testing.T) {
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"GET /v0/admin/retention": func(w http.ResponseWriter, _ *http.Request) {
+			respondJSON(w, http.StatusOK, map[string]any{
+				"keep_versions": 3,
+				"keep_days":     7,
+				"count":         1,
+				"versions": []map[string]any{
+					{"id": "id-1", "name": "io.github.acme/foo", "version": "0.1.0", "published_at": "2025-01-02T03:04:05Z"},
+				},
+			})
+		},
+	})
+
+	out, err := runAdmin(t, stub, "retention", "preview", "--keep-versions", "3", "--keep-days", "7")
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, "keep_days=7&keep_versions=3", stub.requests[0].URL.RawQuery)
+	assert.Equal(t, ""+
+		"ID    NAME                VERSION  PUBLISHED\n"+
+		"id-1  io.github.acme/foo  0.1.0    2025-01-02T03:04:05Z\n"+
+		"\n1 versions would be removed (keep_versions=3, keep_days=7)\n", out)
+
+	_, err = runAdmin(t, stub, "retention", "preview")
+	require.NoError(t, err)
+	assert.Empty(t, stub.requests[1].URL.RawQuery, "the registry's policy applies without overrides")
+}
+
+func TestAdminPin(t *testing.T) {
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"PUT /v0/servers/{id}/pin": func(w http.ResponseWriter, r *http.Request) {
+			respondJSON(w, http.StatusOK, testServer(r.PathValue("id"), "io.github.acme/foo", "1.0.0", model.StatusActive))
+		},
+	})
+
+	out, err := runAdmin(t, stub, "pin", "id-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Pinned io.github.acme/foo 1.0.0 (id-1)\n", out)
+	assert.JSONEq(t, `{"pinned": true}`, stub.bodies[0])
+	assert.Equal(t, "application/json", stub.requests[0].Header.Get("Content-Type"))
+
+	out, err = runAdmin(t, stub, "unpin", "id-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Unpinned io.github.acme/foo 1.0.0 (id-1)\n", out)
+	assert.JSONEq(t, `{"pinned": false}`, stub.bodies[1])
+}
+
+func TestAdminTakedown(t *testing.T) {
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"GET /v0/servers/{id}": func(w http.ResponseWriter, r *http.Request) {
+			respondJSON(w, http.StatusOK, testServer(r.PathValue("id"), "io.github.acme/foo", "1.0.0", model.StatusActive))
+		},
+		"PUT /v0/servers/{id}": func(w http.ResponseWriter, r *http.Request) {
+			respondJSON(w, http.StatusOK, testServer(r.PathValue("id"), "io.github.acme/foo", "1.0.0", model.StatusDeleted))
+		},
+	})
+
+	out, err := runAdmin(t, stub, "takedown", "id-1")
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, http.MethodPut, stub.requests[1].Method)
+
+	var edited apiv0.ServerJSON
+	require.NoError(t, json.Unmarshal([]byte(stub.bodies[1]), &edited))
+	assert.Equal(t, model.StatusDeleted, edited.Status)
+	assert.Equal(t, "1.0.0", edited.Version)
+	require.NotNil(t, edited.Meta)
+	assert.Nil(t, edited.Meta.Official, "registry metadata is not sent back")
+	assert.Equal(t, map[string]any{"tool": "ci"}, edited.Meta.PublisherProvided)
+	assert.Equal(t, "Took down io.github.acme/foo 1.0.0 (id-1)\n", out)
+}
+
+func TestAdminJWKS(t *testing.T) {
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"GET /v0/admin/jwks": func(w http.ResponseWriter, _ *http.Request) {
+			respondJSON(w, http.StatusOK, map[string]any{
+				"keys": []map[string]string{
+					{"kty": "OKP", "crv": "Ed25519", "kid": "new", "alg": "EdDSA", "use": "sig", "x": "AAAA"},
+					{"kty": "OKP", "crv": "Ed25519", "kid": "old", "alg": "EdDSA", "use": "sig", "x": "BBBB"},
+				},
+			})
+		},
+	})
+
+	t.Setenv(TokenEnv, "")
+	var out bytes.Buffer
+	require.NoError(t, AdminCommand([]string{"jwks", "--registry", stub.server.URL}, &out), "the key set needs no token")
+	assert.Empty(t, stub.requests[0].Header.Get("Authorization"))
+	assert.Equal(t, ""+
+		"KID  ALG    CURVE    ROLE\n"+
+		"new  EdDSA  Ed25519  signing\n"+
+		"old  EdDSA  Ed25519  rotating out\n", out.String())
+}
+
+func TestAdminToken(t *testing.T) {
+	var authorization string
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"GET /v0/admin/pending": func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			respondJSON(w, http.StatusOK, apiv0.ServerListResponse{Metadata: apiv0.Metadata{}})
+		},
+	})
+
+	t.Run("reads the token from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(path, []byte("file-token\n"), 0600))
+		t.Setenv(TokenEnv, "env-token")
+
+		var out bytes.Buffer
+		require.NoError(t, AdminCommand([]string{"pending", "list", "--registry", stub.server.URL, "--token-file", path}, &out))
+		assert.Equal(t, "Bearer file-token", authorization)
+		assert.Equal(t, "No results\n", out.String())
+	})
+
+	t.Run("requires a token", func(t *testing.T) {
+		t.Setenv(TokenEnv, "")
+		err := AdminCommand([]string{"pending", "list", "--registry", stub.server.URL}, io.Discard)
+		assert.ErrorContains(t, err, "no admin token")
+	})
+}
+
+func TestAdminErrors(t *testing.T) {
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"POST /v0/admin/servers/{id}/approve": func(w http.ResponseWriter, r *http.Request) {
+			if r.PathValue("id") == "forbidden" {
+				respondJSON(w, http.StatusForbidden, map[string]string{"title": "Forbidden", "detail": "Global edit permission required"})
+				return
+			}
+			respondJSON(w, http.StatusNotFound, map[string]string{"title": "Not Found", "detail": "Server not found"})
+		},
+	})
+
+	_, err := runAdmin(t, stub, "pending", "approve", "missing")
+	assert.EqualError(t, err, "POST /v0/admin/servers/missing/approve: registry returned status 404: Server not found")
+
+	_, err = runAdmin(t, stub, "pending", "approve", "forbidden")
+	assert.EqualError(t, err, "registry token is not an admin token (Global edit permission required)")
+
+	_, err = runAdmin(t, stub, "latest", "recompute")
+	assert.ErrorContains(t, err, "unknown admin command: latest")
+}
+
+func TestAdminExport(t *testing.T) {
+	signature := `{"alg":"EdDSA","kid":"seed-key","digest":"sha256:00","signature":"AAAA"}`
+	stub := newStubAdminAPI(t, map[string]http.HandlerFunc{
+		"GET /v0/admin/export": func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Seed-Signature", signature)
+			_, _ = io.WriteString(w, "{\"name\":\"io.github.acme/foo\"}\n")
+		},
+	})
+
+	path := filepath.Join(t.TempDir(), "seed.ndjson")
+	out, err := runAdmin(t, stub, "export", "-o", path)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+testToken, stub.requests[0].Header.Get("Authorization"))
+	assert.Contains(t, out, "signature in "+path+".sig")
+
+	seed, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"name\":\"io.github.acme/foo\"}\n", string(seed))
+	sig, err := os.ReadFile(path + ".sig")
+	require.NoError(t, err)
+	assert.Equal(t, signature+"\n", string(sig))
+
+	_, err = runAdmin(t, stub, "export")
+	require.ErrorContains(t, err, "-o is required")
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/modelcontextprotocol/registry/cmd/registryctl/commands"
+)
+
+// Version info for the registry operator tool
+// These variables are injected at build time via ldflags
+var (
+	// Version is the current version of registryctl
+	Version = "dev"
+
+	// BuildTime is the time at which the binary was built
+	BuildTime = "unknown"
+
+	// GitCommit is the git commit that was compiled
+	GitCommit = "unknown"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "admin":
+		err = commands.AdminCommand(os.Args[2:], os.Stdout)
+	case "--version", "-v", "version":
+		log.Printf("registryctl %s (commit: %s, built: %s)", Version, GitCommit, BuildTime)
+		return
+	case "--help", "-h", "help":
+		printUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
+		printUsage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	_, _ = fmt.Fprintln(os.Stdout, "MCP Registry operator tool")
+	_, _ = fmt.Fprintln(os.Stdout)
+	_, _ = fmt.Fprintln(os.Stdout, "Usage:")
+	_, _ = fmt.Fprintln(os.Stdout, "  registryctl <command> [arguments]")
+	_, _ = fmt.Fprintln(os.Stdout)
+	_, _ = fmt.Fprintln(os.Stdout, "Commands:")
+	_, _ = fmt.Fprintln(os.Stdout, "  admin         Run admin operations against a registry")
+	_, _ = fmt.Fprintln(os.Stdout, "  version       Show version information")
+	_, _ = fmt.Fprintln(os.Stdout, "  help          Show this help message")
+	_, _ = fmt.Fprintln(os.Stdout)
+	_, _ = fmt.Fprintln(os.Stdout, "Run 'registryctl admin help' for the admin commands.")
+}
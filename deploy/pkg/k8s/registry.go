@@ -92,6 +92,10 @@ func DeployMCPRegistry(ctx *pulumi.Context, cluster *providers.ProviderInfo, env
 								},
 							},
 							Env: corev1.EnvVarArray{
+								&corev1.EnvVarArgs{
+									Name:  pulumi.String("MCP_REGISTRY_ENVIRONMENT"),
+									Value: pulumi.String(environment),
+								},
 								&corev1.EnvVarArgs{
 									Name: pulumi.String("MCP_REGISTRY_DATABASE_URL"),
 									ValueFrom: &corev1.EnvVarSourceArgs{
@@ -153,6 +157,15 @@ func DeployMCPRegistry(ctx *pulumi.Context, cluster *providers.ProviderInfo, env
 									Name:  pulumi.String("MCP_REGISTRY_OIDC_PUBLISH_PERMISSIONS"),
 									Value: pulumi.String("*"),
 								},
+								// Share latest-version cache invalidations between replicas
+								&corev1.EnvVarArgs{
+									Name:  pulumi.String("MCP_REGISTRY_REPLICAS"),
+									Value: pulumi.String("2"),
+								},
+								&corev1.EnvVarArgs{
+									Name:  pulumi.String("MCP_REGISTRY_CACHE_INVALIDATION_CHANNEL"),
+									Value: pulumi.String("registry_latest_cache"),
+								},
 							},
 							LivenessProbe: &corev1.ProbeArgs{
 								HttpGet: &corev1.HTTPGetActionArgs{
@@ -0,0 +1,470 @@
+// Package admin serves the optional admin UI for browsing and moderating registry servers
+package admin
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const (
+	// sessionCookie holds the operator's registry JWT, scoped to the admin pages
+	sessionCookie = "mcp_registry_admin"
+	// listPageSize is the number of servers shown per list page
+	listPageSize = 50
+	// historyLimit caps the number of versions shown on a server's detail page
+	historyLimit = 100
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed static
+var staticFS embed.FS
+
+// pages are parsed once, each together with the shared layout
+var pages = map[string]*template.Template{
+	"login":  parsePage("login.html"),
+	"list":   parsePage("list.html"),
+	"detail": parsePage("detail.html"),
+	"error":  parsePage("error.html"),
+}
+
+func parsePage(name string) *template.Template {
+	funcs := template.FuncMap{
+		"timestamp": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.UTC().Format(time.RFC3339)
+		},
+	}
+	return template.Must(template.New("layout.html").Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+name))
+}
+
+// page is the data passed to every template
+type page struct {
+	Title   string
+	Subject string // the signed-in operator, empty on the login page
+	Data    any
+}
+
+type listData struct {
+	Query      string
+	Servers    []apiv0.ServerJSON
+	Total      int
+	NextCursor string
+	CanEditAll bool
+	// NeedsAttention holds the IDs of the listed versions whose reports reached the report
+	// threshold, only looked up for operators who may see every server's reports
+	NeedsAttention map[string]bool
+}
+
+type detailData struct {
+	Server   *apiv0.ServerJSON
+	Versions []apiv0.ServerJSON
+	JSON     string
+	CanEdit  bool
+	Reports  *service.ServerReports // nil when the version was never reported or the operator is not an admin
+}
+
+type errorData struct {
+	Status  int
+	Message string
+	Back    string
+}
+
+// handler serves the admin pages. Moderation actions are forwarded to the registry's own
+// admin API with the operator's token, so the API's permission checks and validation apply.
+type handler struct {
+	registry   service.RegistryService
+	jwtManager *auth.JWTManager
+	api        http.Handler
+	// tenancy scopes every page to a tenant, as TenancyMiddleware does for the API
+	tenancy bool
+}
+
+// RegisterRoutes adds the admin UI to mux under /admin. Moderation requests are sent
+// through mux to the v0 admin API, which must be registered on the same mux.
+func RegisterRoutes(mux *http.ServeMux, registry service.RegistryService, cfg *config.Config) {
+	h := &handler{
+		registry:   registry,
+		jwtManager: auth.NewJWTManager(cfg),
+		api:        mux,
+		tenancy:    cfg.TenancyEnabled,
+	}
+
+	static, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	mux.Handle("GET /admin/static/", http.StripPrefix("/admin/static/", http.FileServerFS(static)))
+	mux.HandleFunc("GET /admin", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/admin/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("GET /admin/login", h.loginForm)
+	mux.HandleFunc("POST /admin/login", h.login)
+	mux.HandleFunc("POST /admin/logout", h.logout)
+	mux.HandleFunc("GET /admin/{$}", h.withSession(h.list))
+	mux.HandleFunc("GET /admin/servers/{id}", h.withSession(h.detail))
+	mux.HandleFunc("POST /admin/servers/{id}/status", h.withSession(h.setStatus))
+}
+
+// sessionHandler is an admin page handler for a signed-in operator
+type sessionHandler func(w http.ResponseWriter, r *http.Request, token string, claims *auth.JWTClaims)
+
+// withSession sends requests without a valid registry JWT to the login page, and rejects
+// cross-site form posts. On a multi-tenant registry the request is scoped to a tenant the
+// same way the API scopes it.
+func (h *handler) withSession(next sessionHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && !sameOrigin(r) {
+			h.renderError(w, http.StatusForbidden, "Cross-origin requests are not allowed", "/admin/")
+			return
+		}
+
+		cookie, err := r.Cookie(sessionCookie)
+		if err != nil {
+			http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+			return
+		}
+		claims, err := h.jwtManager.ValidateToken(r.Context(), cookie.Value)
+		if err != nil {
+			clearSession(w)
+			http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+			return
+		}
+		if h.tenancy {
+			ctx, status, message := h.tenantContext(r, claims)
+			if status != 0 {
+				h.renderError(w, status, message, "/admin/login")
+				return
+			}
+			r = r.WithContext(ctx)
+		}
+
+		next(w, r, cookie.Value, claims)
+	}
+}
+
+// tenantContext scopes the request to the token's tenant. Admin tokens, holding edit permission
+// for every server, may carry no tenant and then act for the tenant named in the
+// MCP-Registry-Tenant header, or across all tenants without one. It returns a non-zero status
+// when the request may not be served.
+func (h *handler) tenantContext(r *http.Request, claims *auth.JWTClaims) (context.Context, int, string) {
+	requested := r.Header.Get(tenancy.Header)
+	switch {
+	case claims.Tenant != "":
+		if requested != "" && requested != claims.Tenant {
+			return nil, http.StatusForbidden, "This token is not valid for tenant " + requested
+		}
+		return tenancy.WithTenant(r.Context(), claims.Tenant), 0, ""
+	case !h.jwtManager.HasPermission("*", auth.PermissionActionEdit, claims.Permissions):
+		return nil, http.StatusForbidden, "This token was not issued for any tenant of this registry"
+	case requested != "":
+		if !config.TenantRegex.MatchString(requested) {
+			return nil, http.StatusBadRequest, "Invalid " + tenancy.Header + " header"
+		}
+		return tenancy.WithTenant(r.Context(), requested), 0, ""
+	default:
+		return r.Context(), 0, ""
+	}
+}
+
+func (h *handler) loginForm(w http.ResponseWriter, _ *http.Request) {
+	h.render(w, http.StatusOK, "login", page{Title: "Sign in"})
+}
+
+func (h *handler) login(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		h.renderError(w, http.StatusForbidden, "Cross-origin requests are not allowed", "/admin/login")
+		return
+	}
+
+	token := strings.TrimSpace(r.PostFormValue("token"))
+	token = strings.TrimPrefix(token, "Bearer ")
+
+	claims, err := h.jwtManager.ValidateToken(r.Context(), token)
+	if err != nil {
+		h.render(w, http.StatusUnauthorized, "login", page{Title: "Sign in", Data: "Invalid or expired registry JWT"})
+		return
+	}
+
+	cookie := &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/admin",
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteStrictMode,
+	}
+	if claims.ExpiresAt != nil {
+		cookie.Expires = claims.ExpiresAt.Time
+	}
+	http.SetCookie(w, cookie)
+	http.Redirect(w, r, "/admin/", http.StatusSeeOther)
+}
+
+func (h *handler) logout(w http.ResponseWriter, r *http.Request) {
+	clearSession(w)
+	http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request, _ string, claims *auth.JWTClaims) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	isLatest := true
+	filter := &database.ServerFilter{IsLatest: &isLatest, ExcludeHidden: true}
+	if query != "" {
+		filter.SubstringName = &query
+	}
+
+	servers, nextCursor, err := h.registry.List(r.Context(), filter, r.URL.Query().Get("cursor"), listPageSize)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to list servers: "+err.Error(), "/admin/")
+		return
+	}
+	total, err := h.registry.Count(r.Context(), filter)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to count servers: "+err.Error(), "/admin/")
+		return
+	}
+
+	canEditAll := h.jwtManager.HasPermission("*", auth.PermissionActionEdit, claims.Permissions)
+	var needsAttention map[string]bool
+	if canEditAll {
+		reports, err := h.registry.ServerReportSummaries(r.Context(), database.ServerReportFilter{})
+		if err != nil {
+			h.renderError(w, http.StatusInternalServerError, "Failed to count reports: "+err.Error(), "/admin/")
+			return
+		}
+		needsAttention = make(map[string]bool)
+		for _, report := range reports {
+			if report.NeedsAttention {
+				needsAttention[report.ID] = true
+			}
+		}
+	}
+
+	h.render(w, http.StatusOK, "list", page{
+		Title:   "Servers",
+		Subject: claims.AuthMethodSubject,
+		Data: listData{
+			Query:          query,
+			Servers:        servers,
+			Total:          total,
+			NextCursor:     nextCursor,
+			CanEditAll:     canEditAll,
+			NeedsAttention: needsAttention,
+		},
+	})
+}
+
+func (h *handler) detail(w http.ResponseWriter, r *http.Request, _ string, claims *auth.JWTClaims) {
+	server, err := h.registry.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			h.renderError(w, http.StatusNotFound, "Server not found", "/admin/")
+			return
+		}
+		h.renderError(w, http.StatusInternalServerError, "Failed to get server: "+err.Error(), "/admin/")
+		return
+	}
+	canSeeHidden := h.canSeeHidden(server.Name, claims)
+	if server.Status.Hidden() && !canSeeHidden {
+		h.renderError(w, http.StatusNotFound, "Server not found", "/admin/")
+		return
+	}
+
+	versions, _, err := h.registry.List(r.Context(), &database.ServerFilter{Name: &server.Name, ExcludeHidden: !canSeeHidden}, "", historyLimit)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to list versions: "+err.Error(), "/admin/")
+		return
+	}
+
+	document, err := json.MarshalIndent(server, "", "  ")
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to encode server: "+err.Error(), "/admin/")
+		return
+	}
+
+	var reports *service.ServerReports
+	if h.jwtManager.HasPermission("*", auth.PermissionActionEdit, claims.Permissions) {
+		summaries, err := h.registry.ServerReportSummaries(r.Context(), database.ServerReportFilter{ServerID: server.Meta.Official.ID})
+		if err != nil {
+			h.renderError(w, http.StatusInternalServerError, "Failed to count reports: "+err.Error(), "/admin/")
+			return
+		}
+		if len(summaries) > 0 {
+			reports = &summaries[0]
+		}
+	}
+
+	h.render(w, http.StatusOK, "detail", page{
+		Title:   server.Name,
+		Subject: claims.AuthMethodSubject,
+		Data: detailData{
+			Server:   server,
+			Versions: versions,
+			JSON:     string(document),
+			CanEdit:  h.jwtManager.HasPermission(server.Name, auth.PermissionActionEdit, claims.Permissions),
+			Reports:  reports,
+		},
+	})
+}
+
+// setStatus deprecates or deletes a server version through the edit-server API
+func (h *handler) setStatus(w http.ResponseWriter, r *http.Request, token string, claims *auth.JWTClaims) {
+	id := r.PathValue("id")
+	back := "/admin/servers/" + url.PathEscape(id)
+
+	status := model.Status(r.PostFormValue("status"))
+	if status != model.StatusDeprecated && status != model.StatusDeleted {
+		h.renderError(w, http.StatusBadRequest, "Status must be deprecated or deleted", back)
+		return
+	}
+
+	server, err := h.registry.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			h.renderError(w, http.StatusNotFound, "Server not found", "/admin/")
+			return
+		}
+		h.renderError(w, http.StatusInternalServerError, "Failed to get server: "+err.Error(), back)
+		return
+	}
+	if server.Status.Hidden() && !h.canSeeHidden(server.Name, claims) {
+		h.renderError(w, http.StatusNotFound, "Server not found", "/admin/")
+		return
+	}
+
+	// Send the server back unchanged apart from its status; registry metadata is not editable
+	edited := *server
+	edited.Status = status
+	if edited.Meta != nil {
+		meta := *edited.Meta
+		meta.Official = nil
+		edited.Meta = &meta
+	}
+	body, err := json.Marshal(edited)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to encode server: "+err.Error(), back)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPut, "/v0/servers/"+url.PathEscape(id), bytes.NewReader(body))
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to create request: "+err.Error(), back)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	if requested := r.Header.Get(tenancy.Header); requested != "" {
+		req.Header.Set(tenancy.Header, requested)
+	}
+
+	resp := &capturedResponse{header: make(http.Header), status: http.StatusOK}
+	h.api.ServeHTTP(resp, req)
+	if resp.status != http.StatusOK {
+		h.renderError(w, resp.status, apiErrorMessage(resp.body.Bytes()), back)
+		return
+	}
+
+	log.Printf("audit: admin UI set status of server %s version %s to %s (id=%s, auth_method=%s, subject=%s)",
+		server.Name, server.Version, status, id, claims.AuthMethod, claims.AuthMethodSubject)
+	http.Redirect(w, r, back, http.StatusSeeOther)
+}
+
+// canSeeHidden reports whether claims may see versions of the named server that are held for
+// review or rejected: like the review status API, only its publishers and admins may
+func (h *handler) canSeeHidden(name string, claims *auth.JWTClaims) bool {
+	return h.jwtManager.HasPermission(name, auth.PermissionActionPublish, claims.Permissions) ||
+		h.jwtManager.HasPermission("*", auth.PermissionActionEdit, claims.Permissions)
+}
+
+func (h *handler) render(w http.ResponseWriter, status int, name string, data page) {
+	var buf bytes.Buffer
+	if err := pages[name].Execute(&buf, data); err != nil {
+		log.Printf("admin: failed to render %s page: %v", name, err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	w.WriteHeader(status)
+	_, _ = buf.WriteTo(w)
+}
+
+func (h *handler) renderError(w http.ResponseWriter, status int, message, back string) {
+	h.render(w, status, "error", page{
+		Title: http.StatusText(status),
+		Data:  errorData{Status: status, Message: message, Back: back},
+	})
+}
+
+func clearSession(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/admin", MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteStrictMode})
+}
+
+// sameOrigin reports whether a form post came from this host. Browsers send Origin on
+// POST requests; its absence means a non-browser client, which carries no ambient cookie risk.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// apiErrorMessage extracts the detail from an API error response
+func apiErrorMessage(body []byte) string {
+	var apiErr struct {
+		Detail string `json:"detail"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Detail == "" {
+		return strings.TrimSpace(string(body))
+	}
+
+	message := apiErr.Detail
+	for _, detail := range apiErr.Errors {
+		message += ": " + detail.Message
+	}
+	return message
+}
+
+// capturedResponse records the response of an in-process API request
+type capturedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (c *capturedResponse) Header() http.Header { return c.header }
+
+func (c *capturedResponse) Write(b []byte) (int, error) { return c.body.Write(b) }
+
+func (c *capturedResponse) WriteHeader(status int) { c.status = status }
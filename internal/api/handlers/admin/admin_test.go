@@ -0,0 +1,303 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const testJWTKey = "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"
+
+type adminTestEnv struct {
+	cfg      *config.Config
+	registry service.RegistryService
+	mux      *http.ServeMux
+}
+
+func newAdminTestEnv(t *testing.T, enabled bool) *adminTestEnv {
+	t.Helper()
+
+	cfg := config.NewConfig()
+	cfg.JWTPrivateKey = testJWTKey
+	cfg.EnableRegistryValidation = false
+	cfg.ListCacheMaxBytes = 0
+	cfg.EnableAdminUI = enabled
+
+	registry := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	shutdownTelemetry, metrics, err := telemetry.InitMetrics("test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = shutdownTelemetry(context.Background()) })
+
+	mux := http.NewServeMux()
+	_, err = router.NewHumaAPI(cfg, registry, database.NewMemoryDB(), mux, metrics)
+	require.NoError(t, err)
+
+	return &adminTestEnv{cfg: cfg, registry: registry, mux: mux}
+}
+
+func (e *adminTestEnv) publish(t *testing.T, name, version string) string {
+	t.Helper()
+
+	published, err := e.registry.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        name,
+		Description: "Test server " + name,
+		Status:      model.StatusActive,
+		Version:     version,
+	})
+	require.NoError(t, err)
+	return published.Meta.Official.ID
+}
+
+func (e *adminTestEnv) token(t *testing.T, permissions ...auth.Permission) string {
+	t.Helper()
+
+	response, err := auth.NewJWTManager(e.cfg).GenerateTokenResponse(context.Background(), auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: "operator",
+		Permissions:       permissions,
+	})
+	require.NoError(t, err)
+	return response.RegistryToken
+}
+
+func (e *adminTestEnv) get(path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.AddCookie(&http.Cookie{Name: "mcp_registry_admin", Value: token})
+	}
+	w := httptest.NewRecorder()
+	e.mux.ServeHTTP(w, req)
+	return w
+}
+
+func (e *adminTestEnv) post(path, token string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if token != "" {
+		req.AddCookie(&http.Cookie{Name: "mcp_registry_admin", Value: token})
+	}
+	w := httptest.NewRecorder()
+	e.mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminUI_DisabledByDefault(t *testing.T) {
+	env := newAdminTestEnv(t, false)
+
+	for _, path := range []string{"/admin/", "/admin/login", "/admin/static/admin.css"} {
+		w := env.get(path, "")
+		assert.NotContains(t, w.Body.String(), "MCP Registry Admin", path)
+		assert.NotEqual(t, http.StatusSeeOther, w.Code, path)
+	}
+	w := env.post("/admin/login", "", url.Values{"token": {"anything"}})
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestAdminUI_AuthGating(t *testing.T) {
+	env := newAdminTestEnv(t, true)
+	id := env.publish(t, "io.github.example/server", "1.0.0")
+
+	t.Run("pages redirect to login without a session", func(t *testing.T) {
+		for _, path := range []string{"/admin/", "/admin/servers/" + id} {
+			w := env.get(path, "")
+			assert.Equal(t, http.StatusSeeOther, w.Code, path)
+			assert.Equal(t, "/admin/login", w.Header().Get("Location"), path)
+		}
+	})
+
+	t.Run("invalid session token is cleared", func(t *testing.T) {
+		w := env.get("/admin/", "not-a-jwt")
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		require.Len(t, w.Result().Cookies(), 1)
+		assert.Negative(t, w.Result().Cookies()[0].MaxAge)
+	})
+
+	t.Run("login page renders", func(t *testing.T) {
+		w := env.get("/admin/login", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `name="token"`)
+	})
+
+	t.Run("login rejects an invalid token", func(t *testing.T) {
+		w := env.post("/admin/login", "", url.Values{"token": {"not-a-jwt"}})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid or expired registry JWT")
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("login sets a scoped session cookie", func(t *testing.T) {
+		token := env.token(t)
+		w := env.post("/admin/login", "", url.Values{"token": {"Bearer " + token}})
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/admin/", w.Header().Get("Location"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, token, cookies[0].Value)
+		assert.Equal(t, "/admin", cookies[0].Path)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+	})
+
+	t.Run("cross-origin posts are rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/servers/"+id+"/status", strings.NewReader("status=deleted"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Origin", "https://attacker.example")
+		req.AddCookie(&http.Cookie{Name: "mcp_registry_admin", Value: env.token(t, auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "*"})})
+		w := httptest.NewRecorder()
+		env.mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		server, err := env.registry.GetByID(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusActive, server.Status)
+	})
+}
+
+func TestAdminUI_ListAndDetail(t *testing.T) {
+	env := newAdminTestEnv(t, true)
+	env.publish(t, "io.github.example/weather", "1.0.0")
+	weatherID := env.publish(t, "io.github.example/weather", "1.1.0")
+	env.publish(t, "io.github.other/calendar", "2.0.0")
+
+	readOnly := env.token(t)
+	admin := env.token(t, auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "*"})
+
+	t.Run("list shows latest versions", func(t *testing.T) {
+		w := env.get("/admin/", readOnly)
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, "io.github.example/weather")
+		assert.Contains(t, body, "io.github.other/calendar")
+		assert.Contains(t, body, "2 servers")
+		assert.Contains(t, body, "read-only")
+		assert.Contains(t, body, "Signed in as <strong>operator</strong>")
+	})
+
+	t.Run("list search filters by name", func(t *testing.T) {
+		w := env.get("/admin/?q=weather", admin)
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, "io.github.example/weather")
+		assert.NotContains(t, body, "io.github.other/calendar")
+		assert.Contains(t, body, "1 servers")
+		assert.Contains(t, body, `class="badge admin"`)
+	})
+
+	t.Run("detail is read-only without edit permission", func(t *testing.T) {
+		w := env.get("/admin/servers/"+weatherID, readOnly)
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, weatherID)
+		assert.Contains(t, body, "1.0.0", "version history lists older versions")
+		assert.Contains(t, body, "Read-only")
+		assert.NotContains(t, body, `value="deprecated"`)
+	})
+
+	t.Run("detail shows moderation buttons with edit permission", func(t *testing.T) {
+		w := env.get("/admin/servers/"+weatherID, admin)
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `value="deprecated"`)
+		assert.Contains(t, body, `value="deleted"`)
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		w := env.get("/admin/servers/00000000-0000-0000-0000-000000000000", readOnly)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAdminUI_SetStatus(t *testing.T) {
+	env := newAdminTestEnv(t, true)
+	id := env.publish(t, "io.github.example/server", "1.0.0")
+	status := func() model.Status {
+		t.Helper()
+		server, err := env.registry.GetByID(context.Background(), id)
+		require.NoError(t, err)
+		return server.Status
+	}
+
+	// The edit API enforces permissions, so a read-only operator cannot moderate
+	readOnly := env.token(t, auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "io.github.other/*"})
+	w := env.post("/admin/servers/"+id+"/status", readOnly, url.Values{"status": {"deprecated"}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "You do not have edit permissions for this server")
+	assert.Equal(t, model.StatusActive, status())
+
+	admin := env.token(t, auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "io.github.example/*"})
+
+	w = env.post("/admin/servers/"+id+"/status", admin, url.Values{"status": {"active"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = env.post("/admin/servers/"+id+"/status", admin, url.Values{"status": {"deprecated"}})
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/admin/servers/"+id, w.Header().Get("Location"))
+	assert.Equal(t, model.StatusDeprecated, status())
+
+	w = env.post("/admin/servers/"+id+"/status", admin, url.Values{"status": {"deleted"}})
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, model.StatusDeleted, status())
+
+	// Registry metadata survives the edit
+	server, err := env.registry.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, server.Meta)
+	require.NotNil(t, server.Meta.Official)
+	assert.Equal(t, id, server.Meta.Official.ID)
+}
+
+func TestAdminUI_Reports(t *testing.T) {
+	env := newAdminTestEnv(t, true)
+	env.cfg.ReportThreshold = 2
+	weatherID := env.publish(t, "io.github.example/weather", "1.0.0")
+	calendarID := env.publish(t, "io.github.other/calendar", "2.0.0")
+
+	for range 2 {
+		_, err := env.registry.ReportServer(context.Background(), weatherID, service.ReportCategoryDeadRemote, "502 Bad Gateway")
+		require.NoError(t, err)
+	}
+	_, err := env.registry.ReportServer(context.Background(), calendarID, service.ReportCategorySpam, "")
+	require.NoError(t, err)
+
+	readOnly := env.token(t, auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "io.github.example/*"})
+	admin := env.token(t, auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "*"})
+
+	t.Run("list flags versions that need attention", func(t *testing.T) {
+		body := env.get("/admin/", admin).Body.String()
+		assert.Equal(t, 1, strings.Count(body, "needs attention"))
+		assert.Contains(t, env.get("/admin/?q=weather", admin).Body.String(), "needs attention")
+		assert.NotContains(t, env.get("/admin/?q=calendar", admin).Body.String(), "needs attention")
+	})
+
+	t.Run("detail shows report counts without text", func(t *testing.T) {
+		w := env.get("/admin/servers/"+weatherID, admin)
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, "<h2>Reports")
+		assert.Contains(t, body, "<td>dead-remote</td><td>2</td>")
+		assert.Contains(t, body, "needs attention")
+		assert.NotContains(t, body, "502 Bad Gateway")
+	})
+
+	t.Run("reports are only shown to admins", func(t *testing.T) {
+		assert.NotContains(t, env.get("/admin/", readOnly).Body.String(), "needs attention")
+		assert.NotContains(t, env.get("/admin/servers/"+weatherID, readOnly).Body.String(), "<h2>Reports")
+	})
+}
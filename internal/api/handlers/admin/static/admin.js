@@ -0,0 +1,7 @@
+// Ask for confirmation before submitting moderation forms
+document.addEventListener("submit", (event) => {
+  const message = event.target.dataset.confirm;
+  if (message && !window.confirm(message)) {
+    event.preventDefault();
+  }
+});
@@ -0,0 +1,91 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// defaultActivityWindow is how far back recent activity goes when no since parameter is given
+const defaultActivityWindow = 30 * 24 * time.Hour
+
+// NamespaceActivityInput represents the input for a namespace's activity overview
+type NamespaceActivityInput struct {
+	Namespace string `path:"namespace" doc:"Namespace, the part of server names before the slash" pattern:"^[a-zA-Z0-9.-]+$" example:"io.github.octocat"`
+	Since     string `query:"since" doc:"Start of the recent activity window (RFC3339 datetime); defaults to 30 days ago" required:"false" example:"2025-08-07T13:15:04.280Z"`
+	Cursor    string `query:"cursor" doc:"Pagination cursor for recent activity (UUID)" format:"uuid" required:"false"`
+	Limit     int    `query:"limit" doc:"Number of recent activity entries per page, at most 100; larger values are clamped" default:"30" minimum:"1" example:"50"`
+}
+
+// Resolve clamps the page size to MaxPageSize
+func (i *NamespaceActivityInput) Resolve(ctx huma.Context) []error {
+	clampPageSize(ctx, &i.Limit)
+	return nil
+}
+
+// NamespaceActivityBody is a publisher's overview of a namespace
+type NamespaceActivityBody struct {
+	Namespace     string                 `json:"namespace"`
+	Since         time.Time              `json:"since" doc:"Start of the recent activity window"`
+	Recent        []apiv0.ServerSummary  `json:"recent" doc:"Versions published or changed since the start of the window, oldest change first, including deleted and pending versions"`
+	Latest        []apiv0.ServerSummary  `json:"latest" doc:"The latest version of every server under the namespace"`
+	Usage         service.NamespaceUsage `json:"usage"`
+	Notifications int                    `json:"notifications" doc:"Number of publish notification registrations for the namespace"`
+	Metadata      NamespaceActivityPage  `json:"metadata"`
+}
+
+// NamespaceActivityPage paginates the recent activity of a namespace
+type NamespaceActivityPage struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	Count      int    `json:"count"`
+}
+
+// RegisterActivityEndpoints registers the publisher-facing namespace activity endpoint
+func RegisterActivityEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// Pending and deleted versions are included, so only owners of the whole namespace may see it
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "get-namespace-activity",
+		Method:      http.MethodGet,
+		Path:        "/v0/namespaces/{namespace}/activity",
+		Summary:     "Get namespace activity",
+		Description: "Overview of a namespace for its publishers: recently published and changed versions, the latest version of each server, usage against the registry's limits, and notification registrations. Requires publish permission for every server in the namespace.",
+		Tags:        []string{"namespaces"},
+	}, Permission{Action: auth.PermissionActionPublish, Resource: "{namespace}/*"}), func(ctx context.Context, input *NamespaceActivityInput) (*Response[NamespaceActivityBody], error) {
+		since := time.Now().Add(-defaultActivityWindow)
+		if input.Since != "" {
+			parsed, err := time.Parse(time.RFC3339, input.Since)
+			if err != nil {
+				return nil, huma.Error400BadRequest("Invalid since format: expected RFC3339 timestamp (e.g., 2025-08-07T13:15:04.280Z)")
+			}
+			since = parsed
+		}
+
+		activity, err := registry.NamespaceActivity(ctx, input.Namespace, since, input.Cursor, input.Limit)
+		if err != nil {
+			return nil, serviceError(err, "Namespace", http.StatusInternalServerError, "Failed to get namespace activity")
+		}
+
+		return &Response[NamespaceActivityBody]{
+			Body: NamespaceActivityBody{
+				Namespace:     input.Namespace,
+				Since:         since,
+				Recent:        activity.Recent,
+				Latest:        activity.Latest,
+				Usage:         activity.Usage,
+				Notifications: activity.Notifications,
+				Metadata: NamespaceActivityPage{
+					NextCursor: activity.NextCursor,
+					Count:      len(activity.Recent),
+				},
+			},
+		}, nil
+	})
+}
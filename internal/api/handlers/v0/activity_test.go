@@ -0,0 +1,173 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestNamespaceActivityEndpoint(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, cfg)
+
+	now := time.Now()
+	seeded := 0
+	seed := func(name, version string, status model.Status, isLatest bool, changedAt time.Time) {
+		seeded++
+		_, err := db.CreateServer(ctx, &apiv0.ServerJSON{
+			Name:        name,
+			Description: "A test server",
+			Version:     version,
+			Status:      status,
+			Meta: &apiv0.ServerMeta{
+				Official: &apiv0.RegistryExtensions{
+					ID:          fmt.Sprintf("00000000-0000-0000-0000-%012d", seeded),
+					PublishedAt: changedAt,
+					UpdatedAt:   changedAt,
+					IsLatest:    isLatest,
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// Activity across features: publishes, a deletion and a version held for approval,
+	// some of it before the activity window, and a server in another namespace
+	seed("io.github.octocat/weather", "1.0.0", model.StatusActive, false, now.Add(-90*24*time.Hour))
+	seed("io.github.octocat/weather", "1.1.0", model.StatusActive, true, now.Add(-3*time.Hour))
+	seed("io.github.octocat/weather", "1.2.0", model.StatusPending, false, now.Add(-2*time.Hour))
+	seed("io.github.octocat/old-tool", "0.1.0", model.StatusDeleted, true, now.Add(-1*time.Hour))
+	seed("io.github.octocat/quiet", "2.0.0", model.StatusActive, true, now.Add(-60*24*time.Hour))
+	seed("io.github.octocat-fan/weather", "1.0.0", model.StatusActive, true, now.Add(-1*time.Hour))
+	require.NoError(t, db.CreateNamespaceNotification(ctx, &database.NamespaceNotification{
+		ID:         "00000000-0000-0000-0000-0000000000aa",
+		Namespace:  "io.github.octocat",
+		WebhookURL: "https://hooks.example.com/mcp",
+		CreatedAt:  now,
+	}))
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterActivityEndpoints(api, registryService, cfg)
+
+	tokenFor := func(pattern string) string {
+		token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "octocat",
+			Permissions:       []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: pattern}},
+		})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	get := func(query, authHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v0/namespaces/io.github.octocat/activity"+query, nil)
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	versions := func(servers []apiv0.ServerSummary) []string {
+		names := []string{}
+		for _, server := range servers {
+			names = append(names, server.Name+"@"+server.Version)
+		}
+		return names
+	}
+
+	t.Run("requires a token", func(t *testing.T) {
+		assert.NotEqual(t, http.StatusOK, get("", "").Code)
+	})
+
+	t.Run("rejects tokens for a single server in the namespace", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, get("", tokenFor("io.github.octocat/weather")).Code)
+	})
+
+	t.Run("rejects an invalid since", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("?since=yesterday", tokenFor("io.github.octocat/*")).Code)
+	})
+
+	t.Run("composes the namespace overview", func(t *testing.T) {
+		w := get("", tokenFor("io.github.octocat/*"))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var body v0.NamespaceActivityBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "io.github.octocat", body.Namespace)
+		assert.WithinDuration(t, now.Add(-30*24*time.Hour), body.Since, time.Minute)
+
+		assert.Equal(t, []string{
+			"io.github.octocat/weather@1.1.0",
+			"io.github.octocat/weather@1.2.0",
+			"io.github.octocat/old-tool@0.1.0",
+		}, versions(body.Recent), "changes in the last 30 days, oldest first")
+		assert.Equal(t, model.StatusPending, body.Recent[1].Status)
+		assert.Equal(t, model.StatusDeleted, body.Recent[2].Status)
+
+		assert.ElementsMatch(t, []string{
+			"io.github.octocat/weather@1.1.0",
+			"io.github.octocat/old-tool@0.1.0",
+			"io.github.octocat/quiet@2.0.0",
+		}, versions(body.Latest))
+
+		assert.Equal(t, service.NamespaceUsage{
+			Servers:              3,
+			Versions:             5,
+			PendingVersions:      1,
+			MaxVersionsPerServer: 10000,
+		}, body.Usage)
+		assert.Equal(t, 1, body.Notifications)
+		assert.Equal(t, 3, body.Metadata.Count)
+		assert.Empty(t, body.Metadata.NextCursor)
+	})
+
+	t.Run("paginates recent activity", func(t *testing.T) {
+		since := url.QueryEscape(now.Add(-100 * 24 * time.Hour).Format(time.RFC3339))
+		var pages [][]string
+		cursor := ""
+		for {
+			query := "?limit=2&since=" + since
+			if cursor != "" {
+				query += "&cursor=" + cursor
+			}
+			w := get(query, tokenFor("io.github.octocat/*"))
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var body v0.NamespaceActivityBody
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Len(t, body.Latest, 3, "the overview is repeated on every page")
+			pages = append(pages, versions(body.Recent))
+			if body.Metadata.NextCursor == "" {
+				break
+			}
+			cursor = body.Metadata.NextCursor
+		}
+
+		assert.Equal(t, [][]string{
+			{"io.github.octocat/weather@1.0.0", "io.github.octocat/quiet@2.0.0"},
+			{"io.github.octocat/weather@1.1.0", "io.github.octocat/weather@1.2.0"},
+			{"io.github.octocat/old-tool@0.1.0"},
+		}, pages)
+	})
+}
@@ -3,12 +3,16 @@ package auth
 import (
 	"context"
 	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,17 +20,51 @@ import (
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 )
 
+const (
+	// dnsChallengeTTL is how long a server-issued DNS challenge remains valid
+	dnsChallengeTTL = 2 * time.Minute
+
+	// dnsChallengeNonceBytes is the size of a challenge nonce before hex encoding
+	dnsChallengeNonceBytes = 32
+)
+
+// challengeNoncePattern matches the nonces CreateChallenge issues
+var challengeNoncePattern = regexp.MustCompile(fmt.Sprintf(`^[0-9a-f]{%d}$`, 2*dnsChallengeNonceBytes))
+
 // DNSTokenExchangeInput represents the input for DNS-based authentication
 type DNSTokenExchangeInput struct {
 	Body struct {
 		Domain          string `json:"domain" doc:"Domain name" example:"example.com" required:"true"`
-		Timestamp       string `json:"timestamp" doc:"RFC3339 timestamp" example:"2023-01-01T00:00:00Z" required:"true"`
-		SignedTimestamp string `json:"signed_timestamp" doc:"Hex-encoded Ed25519 signature of timestamp" example:"abcdef1234567890" required:"true"`
+		Nonce           string `json:"nonce,omitempty" doc:"Nonce from /v0/auth/dns/challenge" required:"false"`
+		SignedChallenge string `json:"signed_challenge,omitempty" doc:"Hex-encoded Ed25519 signature of the challenge string" required:"false"`
+		Timestamp       string `json:"timestamp,omitempty" doc:"RFC3339 timestamp (deprecated: use nonce and signed_challenge)" example:"2023-01-01T00:00:00Z" required:"false"`
+		SignedTimestamp string `json:"signed_timestamp,omitempty" doc:"Hex-encoded Ed25519 signature of timestamp (deprecated: use nonce and signed_challenge)" example:"abcdef1234567890" required:"false"`
+	}
+}
+
+// DNSChallengeInput represents the input for requesting a DNS authentication challenge
+type DNSChallengeInput struct {
+	Body struct {
+		Domain string `json:"domain" doc:"Domain name" example:"example.com" required:"true"`
 	}
 }
 
+// DNSChallengeResponse is a server-issued challenge the client must sign with its DNS-published key
+type DNSChallengeResponse struct {
+	Nonce     string    `json:"nonce" doc:"Single-use nonce identifying this challenge"`
+	Challenge string    `json:"challenge" doc:"Exact string to sign with the Ed25519 private key"`
+	ExpiresAt time.Time `json:"expires_at" doc:"Time after which the challenge can no longer be used"`
+}
+
+// ChallengeStore persists single-use authentication challenges
+type ChallengeStore interface {
+	CreateAuthChallenge(ctx context.Context, challenge *database.AuthChallenge) error
+	ConsumeAuthChallenge(ctx context.Context, nonce string) (*database.AuthChallenge, error)
+}
+
 // DNSResolver defines the interface for DNS resolution
 type DNSResolver interface {
 	LookupTXT(ctx context.Context, name string) ([]string, error)
@@ -45,14 +83,16 @@ type DNSAuthHandler struct {
 	config     *config.Config
 	jwtManager *auth.JWTManager
 	resolver   DNSResolver
+	challenges ChallengeStore
 }
 
 // NewDNSAuthHandler creates a new DNS authentication handler
-func NewDNSAuthHandler(cfg *config.Config) *DNSAuthHandler {
+func NewDNSAuthHandler(cfg *config.Config, challenges ChallengeStore) *DNSAuthHandler {
 	return &DNSAuthHandler{
 		config:     cfg,
 		jwtManager: auth.NewJWTManager(cfg),
 		resolver:   &DefaultDNSResolver{},
+		challenges: challenges,
 	}
 }
 
@@ -61,20 +101,45 @@ func (h *DNSAuthHandler) SetResolver(resolver DNSResolver) {
 	h.resolver = resolver
 }
 
-// RegisterDNSEndpoint registers the DNS authentication endpoint
-func RegisterDNSEndpoint(api huma.API, cfg *config.Config) {
-	handler := NewDNSAuthHandler(cfg)
+// RegisterDNSEndpoint registers the DNS authentication endpoints
+func RegisterDNSEndpoint(api huma.API, cfg *config.Config, challenges ChallengeStore) {
+	handler := NewDNSAuthHandler(cfg, challenges)
+
+	// DNS challenge endpoint
+	huma.Register(api, v0.Public(huma.Operation{
+		OperationID: "create-dns-challenge",
+		Method:      http.MethodPost,
+		Path:        "/v0/auth/dns/challenge",
+		Summary:     "Request a DNS authentication challenge",
+		Description: "Get a single-use, short-lived challenge to sign with the private key published in the domain's DNS TXT record",
+		Tags:        []string{"auth"},
+	}), func(ctx context.Context, input *DNSChallengeInput) (*v0.Response[DNSChallengeResponse], error) {
+		response, err := handler.CreateChallenge(ctx, input.Body.Domain)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Failed to create DNS challenge", err)
+		}
+
+		return &v0.Response[DNSChallengeResponse]{
+			Body: *response,
+		}, nil
+	})
 
 	// DNS authentication endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, v0.Public(huma.Operation{
 		OperationID: "exchange-dns-token",
 		Method:      http.MethodPost,
 		Path:        "/v0/auth/dns",
 		Summary:     "Exchange DNS signature for Registry JWT",
-		Description: "Authenticate using DNS TXT record public key and signed timestamp",
+		Description: "Authenticate using DNS TXT record public key and a signed challenge from /v0/auth/dns/challenge",
 		Tags:        []string{"auth"},
-	}, func(ctx context.Context, input *DNSTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
-		response, err := handler.ExchangeToken(ctx, input.Body.Domain, input.Body.Timestamp, input.Body.SignedTimestamp)
+	}), func(ctx context.Context, input *DNSTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
+		var response *auth.TokenResponse
+		var err error
+		if input.Body.Nonce != "" {
+			response, err = handler.ExchangeChallenge(ctx, input.Body.Domain, input.Body.Nonce, input.Body.SignedChallenge)
+		} else {
+			response, err = handler.ExchangeToken(ctx, input.Body.Domain, input.Body.Timestamp, input.Body.SignedTimestamp)
+		}
 		if err != nil {
 			return nil, huma.Error401Unauthorized("DNS authentication failed", err)
 		}
@@ -85,13 +150,96 @@ func RegisterDNSEndpoint(api huma.API, cfg *config.Config) {
 	})
 }
 
-// ExchangeToken exchanges DNS signature for a Registry JWT token
+// CreateChallenge issues a new single-use challenge bound to the given domain
+func (h *DNSAuthHandler) CreateChallenge(ctx context.Context, domain string) (*DNSChallengeResponse, error) {
+	if !isValidDomain(domain) {
+		return nil, fmt.Errorf("invalid domain format")
+	}
+
+	nonceBytes := make([]byte, dnsChallengeNonceBytes)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	issuedAt := time.Now().UTC().Truncate(time.Second)
+	challenge := &database.AuthChallenge{
+		Nonce:     hex.EncodeToString(nonceBytes),
+		Domain:    domain,
+		IssuedAt:  issuedAt,
+		ExpiresAt: issuedAt.Add(dnsChallengeTTL),
+	}
+
+	if err := h.challenges.CreateAuthChallenge(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("failed to store challenge: %w", err)
+	}
+
+	return &DNSChallengeResponse{
+		Nonce:     challenge.Nonce,
+		Challenge: BuildDNSChallengeMessage(challenge.Domain, challenge.Nonce, challenge.IssuedAt),
+		ExpiresAt: challenge.ExpiresAt,
+	}, nil
+}
+
+// BuildDNSChallengeMessage builds the exact string a client must sign for a DNS challenge
+func BuildDNSChallengeMessage(domain, nonce string, issuedAt time.Time) string {
+	return fmt.Sprintf("v=MCPv1; domain=%s; nonce=%s; timestamp=%s", domain, nonce, issuedAt.UTC().Format(time.RFC3339))
+}
+
+// ExchangeChallenge exchanges a signed server-issued challenge for a Registry JWT token
+func (h *DNSAuthHandler) ExchangeChallenge(ctx context.Context, domain, nonce, signedChallenge string) (*auth.TokenResponse, error) {
+	// Validate domain format
+	if !isValidDomain(domain) {
+		return nil, fmt.Errorf("invalid domain format")
+	}
+
+	// Reject malformed nonces before touching the challenge store
+	if !challengeNoncePattern.MatchString(nonce) {
+		return nil, fmt.Errorf("invalid challenge nonce format: expected %d hex characters", 2*dnsChallengeNonceBytes)
+	}
+
+	// Consume the challenge first: whatever happens next, this nonce cannot be used again
+	challenge, err := h.challenges.ConsumeAuthChallenge(ctx, nonce)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("unknown or already used challenge nonce %s", redactToken(nonce))
+		}
+		return nil, fmt.Errorf("failed to retrieve challenge: %w", err)
+	}
+
+	// The store looks the nonce up for us; compare it again without leaking timing,
+	// in case the store matched loosely (e.g. a case-insensitive collation)
+	if subtle.ConstantTimeCompare([]byte(challenge.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("unknown or already used challenge nonce %s", redactToken(nonce))
+	}
+
+	if time.Now().After(challenge.ExpiresAt) {
+		return nil, fmt.Errorf("challenge expired")
+	}
+
+	if !strings.EqualFold(challenge.Domain, domain) {
+		return nil, fmt.Errorf("challenge was issued for a different domain")
+	}
+
+	message := BuildDNSChallengeMessage(challenge.Domain, challenge.Nonce, challenge.IssuedAt)
+	return h.verifyAndIssueToken(ctx, domain, message, signedChallenge)
+}
+
+// ExchangeToken exchanges a DNS signature over a bare timestamp for a Registry JWT token.
+//
+// Deprecated: signed timestamps can be replayed within their validity window. Clients should
+// use CreateChallenge and ExchangeChallenge instead. This flow is rejected once the configured
+// DNSAuthLegacyDeadline has passed.
 func (h *DNSAuthHandler) ExchangeToken(ctx context.Context, domain, timestamp, signedTimestamp string) (*auth.TokenResponse, error) {
 	// Validate domain format
 	if !isValidDomain(domain) {
 		return nil, fmt.Errorf("invalid domain format")
 	}
 
+	// Reject legacy challenges once the deprecation window has closed
+	if deadline := h.config.DNSAuthLegacyDeadline; !deadline.IsZero() && time.Now().After(deadline) {
+		return nil, fmt.Errorf("signed timestamp challenges are no longer supported: request a challenge from /v0/auth/dns/challenge")
+	}
+
 	// Parse and validate timestamp
 	ts, err := time.Parse(time.RFC3339, timestamp)
 	if err != nil {
@@ -104,8 +252,13 @@ func (h *DNSAuthHandler) ExchangeToken(ctx context.Context, domain, timestamp, s
 		return nil, fmt.Errorf("timestamp outside valid window (±15 seconds)")
 	}
 
+	return h.verifyAndIssueToken(ctx, domain, timestamp, signedTimestamp)
+}
+
+// verifyAndIssueToken checks the signature of message against the domain's DNS keys and issues a Registry JWT
+func (h *DNSAuthHandler) verifyAndIssueToken(ctx context.Context, domain, message, signedMessage string) (*auth.TokenResponse, error) {
 	// Decode signature
-	signature, err := hex.DecodeString(signedTimestamp)
+	signature, err := hex.DecodeString(signedMessage)
 	if err != nil {
 		return nil, fmt.Errorf("invalid signature format, must be hex: %w", err)
 	}
@@ -128,7 +281,7 @@ func (h *DNSAuthHandler) ExchangeToken(ctx context.Context, domain, timestamp, s
 	}
 
 	// Verify signature with any of the public keys
-	messageBytes := []byte(timestamp)
+	messageBytes := []byte(message)
 	signatureValid := false
 	for _, publicKey := range publicKeys {
 		if ed25519.Verify(publicKey, messageBytes, signature) {
@@ -225,3 +378,13 @@ func isValidDomain(domain string) bool {
 	domainPattern := regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
 	return domainPattern.MatchString(domain)
 }
+
+// redactToken shortens a client-supplied token and escapes it for use in errors and logs,
+// so a crafted value can neither leak a whole secret nor inject text
+func redactToken(token string) string {
+	const visible = 8
+	if len(token) > visible {
+		token = token[:visible] + "..."
+	}
+	return strconv.Quote(token)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/modelcontextprotocol/registry/internal/api/handlers/v0/auth"
 	intauth "github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 )
 
 // MockDNSResolver for testing
@@ -35,7 +37,7 @@ func TestDNSAuthHandler_ExchangeToken(t *testing.T) {
 	cfg := &config.Config{
 		JWTPrivateKey: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
 	}
-	handler := auth.NewDNSAuthHandler(cfg)
+	handler := auth.NewDNSAuthHandler(cfg, database.NewMemoryDB())
 
 	// Generate a test key pair
 	publicKey, privateKey, err := ed25519.GenerateKey(nil)
@@ -194,3 +196,183 @@ func TestDNSAuthHandler_ExchangeToken(t *testing.T) {
 		})
 	}
 }
+
+func TestDNSAuthHandler_ExchangeChallenge(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	}
+	store := database.NewMemoryDB()
+	handler := auth.NewDNSAuthHandler(cfg, store)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	handler.SetResolver(&MockDNSResolver{
+		txtRecords: map[string][]string{
+			"example.com": {
+				fmt.Sprintf("v=MCPv1; k=ed25519; p=%s", base64.StdEncoding.EncodeToString(publicKey)),
+			},
+		},
+	})
+
+	sign := func(challenge string) string {
+		return hex.EncodeToString(ed25519.Sign(privateKey, []byte(challenge)))
+	}
+
+	t.Run("successful authentication with challenge", func(t *testing.T) {
+		challenge, err := handler.CreateChallenge(context.Background(), "example.com")
+		require.NoError(t, err)
+		assert.Contains(t, challenge.Challenge, challenge.Nonce)
+
+		result, err := handler.ExchangeChallenge(context.Background(), "example.com", challenge.Nonce, sign(challenge.Challenge))
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.RegistryToken)
+	})
+
+	t.Run("replayed nonce is rejected", func(t *testing.T) {
+		challenge, err := handler.CreateChallenge(context.Background(), "example.com")
+		require.NoError(t, err)
+		signature := sign(challenge.Challenge)
+
+		_, err = handler.ExchangeChallenge(context.Background(), "example.com", challenge.Nonce, signature)
+		require.NoError(t, err)
+
+		_, err = handler.ExchangeChallenge(context.Background(), "example.com", challenge.Nonce, signature)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown or already used challenge nonce")
+	})
+
+	t.Run("nonce is consumed even when signature is invalid", func(t *testing.T) {
+		challenge, err := handler.CreateChallenge(context.Background(), "example.com")
+		require.NoError(t, err)
+
+		_, err = handler.ExchangeChallenge(context.Background(), "example.com", challenge.Nonce, sign("something else"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "signature verification failed")
+
+		_, err = handler.ExchangeChallenge(context.Background(), "example.com", challenge.Nonce, sign(challenge.Challenge))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown or already used challenge nonce")
+	})
+
+	t.Run("expired nonce is rejected", func(t *testing.T) {
+		issuedAt := time.Now().Add(-10 * time.Minute).UTC().Truncate(time.Second)
+		expired := &database.AuthChallenge{
+			Nonce:     strings.Repeat("0e", 32),
+			Domain:    "example.com",
+			IssuedAt:  issuedAt,
+			ExpiresAt: issuedAt.Add(2 * time.Minute),
+		}
+		require.NoError(t, store.CreateAuthChallenge(context.Background(), expired))
+
+		message := auth.BuildDNSChallengeMessage(expired.Domain, expired.Nonce, expired.IssuedAt)
+		_, err := handler.ExchangeChallenge(context.Background(), "example.com", expired.Nonce, sign(message))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "challenge expired")
+	})
+
+	t.Run("challenge for a different domain is rejected", func(t *testing.T) {
+		challenge, err := handler.CreateChallenge(context.Background(), "other.com")
+		require.NoError(t, err)
+
+		_, err = handler.ExchangeChallenge(context.Background(), "example.com", challenge.Nonce, sign(challenge.Challenge))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "different domain")
+	})
+
+	t.Run("malformed nonces are rejected before lookup", func(t *testing.T) {
+		challenge, err := handler.CreateChallenge(context.Background(), "example.com")
+		require.NoError(t, err)
+		signature := sign(challenge.Challenge)
+
+		for _, nonce := range []string{
+			"",
+			challenge.Nonce[:63],
+			challenge.Nonce + "0",
+			strings.ToUpper(challenge.Nonce),
+			challenge.Nonce[:62] + "\n",
+			"../../" + challenge.Nonce[6:],
+		} {
+			_, err := handler.ExchangeChallenge(context.Background(), "example.com", nonce, signature)
+			require.Error(t, err, nonce)
+			assert.Contains(t, err.Error(), "invalid challenge nonce format", nonce)
+		}
+
+		// None of the attempts consumed the real challenge
+		_, err = handler.ExchangeChallenge(context.Background(), "example.com", challenge.Nonce, signature)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown nonces are truncated in errors", func(t *testing.T) {
+		nonce := strings.Repeat("ab", 32)
+		_, err := handler.ExchangeChallenge(context.Background(), "example.com", nonce, sign("anything"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"abababab..."`)
+		assert.NotContains(t, err.Error(), nonce)
+	})
+
+	t.Run("concurrent challenge requests get distinct nonces", func(t *testing.T) {
+		const workers = 20
+		var wg sync.WaitGroup
+		nonces := make(chan string, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				challenge, err := handler.CreateChallenge(context.Background(), "example.com")
+				if assert.NoError(t, err) {
+					nonces <- challenge.Nonce
+				}
+			}()
+		}
+		wg.Wait()
+		close(nonces)
+
+		seen := make(map[string]bool)
+		for nonce := range nonces {
+			assert.False(t, seen[nonce], "nonce issued twice")
+			seen[nonce] = true
+		}
+		assert.Len(t, seen, workers)
+	})
+
+	t.Run("concurrent use of one nonce succeeds at most once", func(t *testing.T) {
+		challenge, err := handler.CreateChallenge(context.Background(), "example.com")
+		require.NoError(t, err)
+		signature := sign(challenge.Challenge)
+
+		const workers = 10
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		successes := 0
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := handler.ExchangeChallenge(context.Background(), "example.com", challenge.Nonce, signature); err == nil {
+					mu.Lock()
+					successes++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 1, successes)
+	})
+}
+
+func TestDNSAuthHandler_LegacyDeadline(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	signedTimestamp := hex.EncodeToString(ed25519.Sign(privateKey, []byte(timestamp)))
+
+	cfg := &config.Config{
+		JWTPrivateKey:         "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		DNSAuthLegacyDeadline: time.Now().Add(-time.Hour),
+	}
+	handler := auth.NewDNSAuthHandler(cfg, database.NewMemoryDB())
+
+	_, err = handler.ExchangeToken(context.Background(), "example.com", timestamp, signedTimestamp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no longer supported")
+}
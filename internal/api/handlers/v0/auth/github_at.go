@@ -17,7 +17,8 @@ import (
 // GitHubTokenExchangeInput represents the input for GitHub token exchange
 type GitHubTokenExchangeInput struct {
 	Body struct {
-		GitHubToken string `json:"github_token" doc:"GitHub OAuth token" required:"true"`
+		GitHubToken string                `json:"github_token" doc:"GitHub OAuth token" required:"true"`
+		Scope       auth.PermissionAction `json:"scope,omitempty" doc:"Publish scope to grant: 'publish' for new servers and new versions, 'publish_version' for new versions of existing servers only" enum:"publish,publish_version" default:"publish"`
 	}
 }
 
@@ -29,12 +30,17 @@ type GitHubHandler struct {
 }
 
 // NewGitHubHandler creates a new GitHub handler
-func NewGitHubHandler(cfg *config.Config) *GitHubHandler {
+func NewGitHubHandler(cfg *config.Config) (*GitHubHandler, error) {
+	jwtManager, err := auth.LoadJWTManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &GitHubHandler{
 		config:     cfg,
-		jwtManager: auth.NewJWTManager(cfg),
+		jwtManager: jwtManager,
 		baseURL:    "https://api.github.com",
-	}
+	}, nil
 }
 
 // SetBaseURL sets the base URL for GitHub API (used for testing)
@@ -43,19 +49,22 @@ func (h *GitHubHandler) SetBaseURL(url string) {
 }
 
 // RegisterGitHubATEndpoint registers the GitHub access token authentication endpoint
-func RegisterGitHubATEndpoint(api huma.API, cfg *config.Config) {
-	handler := NewGitHubHandler(cfg)
+func RegisterGitHubATEndpoint(api huma.API, cfg *config.Config) error {
+	handler, err := NewGitHubHandler(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub handler: %w", err)
+	}
 
 	// GitHub token exchange endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, v0.Public(huma.Operation{
 		OperationID: "exchange-github-token",
 		Method:      http.MethodPost,
 		Path:        "/v0/auth/github-at",
 		Summary:     "Exchange GitHub OAuth access token for Registry JWT",
 		Description: "Exchange a GitHub OAuth access token for a short-lived Registry JWT token",
 		Tags:        []string{"auth"},
-	}, func(ctx context.Context, input *GitHubTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
-		response, err := handler.ExchangeToken(ctx, input.Body.GitHubToken)
+	}), func(ctx context.Context, input *GitHubTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
+		response, err := handler.ExchangeTokenWithScope(ctx, input.Body.GitHubToken, input.Body.Scope)
 		if err != nil {
 			return nil, huma.Error401Unauthorized("Token exchange failed", err)
 		}
@@ -64,10 +73,17 @@ func RegisterGitHubATEndpoint(api huma.API, cfg *config.Config) {
 			Body: *response,
 		}, nil
 	})
+
+	return nil
 }
 
 // ExchangeToken exchanges a GitHub OAuth token for a Registry JWT token
 func (h *GitHubHandler) ExchangeToken(ctx context.Context, githubToken string) (*auth.TokenResponse, error) {
+	return h.ExchangeTokenWithScope(ctx, githubToken, auth.PermissionActionPublish)
+}
+
+// ExchangeTokenWithScope exchanges a GitHub OAuth token for a Registry JWT token limited to the given publish scope
+func (h *GitHubHandler) ExchangeTokenWithScope(ctx context.Context, githubToken string, scope auth.PermissionAction) (*auth.TokenResponse, error) {
 	// Get GitHub user information
 	user, err := h.getGitHubUser(ctx, githubToken)
 	if err != nil {
@@ -81,7 +97,10 @@ func (h *GitHubHandler) ExchangeToken(ctx context.Context, githubToken string) (
 	}
 
 	// Build permissions based on user and organizations
-	permissions := h.buildPermissions(user.Login, orgs)
+	permissions, err := auth.RestrictPublishScope(h.buildPermissions(user.Login, orgs), scope)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create JWT claims with GitHub user info
 	claims := auth.JWTClaims{
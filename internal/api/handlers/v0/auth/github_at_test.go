@@ -61,7 +61,8 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		defer mockServer.Close()
 
 		// Create handler and set mock server URL
-		handler := v0auth.NewGitHubHandler(cfg)
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
 		handler.SetBaseURL(mockServer.URL)
 
 		// Test token exchange
@@ -109,7 +110,8 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		defer mockServer.Close()
 
 		// Create handler and set mock server URL
-		handler := v0auth.NewGitHubHandler(cfg)
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
 		handler.SetBaseURL(mockServer.URL)
 
 		// Test token exchange
@@ -138,6 +140,42 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		}
 	})
 
+	t.Run("publish_version scope narrows publish permissions", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case githubUserEndpoint:
+				json.NewEncoder(w).Encode(v0auth.GitHubUserOrOrg{Login: "testuser", ID: 12345}) //nolint:errcheck
+			case githubOrgsEndpoint:
+				json.NewEncoder(w).Encode([]v0auth.GitHubUserOrOrg{{Login: "test-org", ID: 1}}) //nolint:errcheck
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer mockServer.Close()
+
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
+		handler.SetBaseURL(mockServer.URL)
+
+		ctx := context.Background()
+		response, err := handler.ExchangeTokenWithScope(ctx, "valid-github-token", auth.PermissionActionPublishVersion)
+		require.NoError(t, err)
+
+		jwtManager := auth.NewJWTManager(cfg)
+		claims, err := jwtManager.ValidateToken(ctx, response.RegistryToken)
+		require.NoError(t, err)
+		require.Len(t, claims.Permissions, 2)
+		for _, perm := range claims.Permissions {
+			assert.Equal(t, auth.PermissionActionPublishVersion, perm.Action)
+		}
+		assert.False(t, jwtManager.HasPermission("io.github.test-org/new-server", auth.PermissionActionPublish, claims.Permissions))
+		assert.True(t, jwtManager.HasPermission("io.github.test-org/new-server", auth.PermissionActionPublishVersion, claims.Permissions))
+
+		_, err = handler.ExchangeTokenWithScope(ctx, "valid-github-token", auth.PermissionActionEdit)
+		assert.ErrorContains(t, err, "unsupported publish scope")
+	})
+
 	t.Run("invalid token returns error", func(t *testing.T) {
 		// Create mock GitHub API server that returns 401
 		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -147,7 +185,8 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		defer mockServer.Close()
 
 		// Create handler and set mock server URL
-		handler := v0auth.NewGitHubHandler(cfg)
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
 		handler.SetBaseURL(mockServer.URL)
 
 		// Test token exchange
@@ -171,7 +210,8 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		defer mockServer.Close()
 
 		// Create handler and set mock server URL
-		handler := v0auth.NewGitHubHandler(cfg)
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
 		handler.SetBaseURL(mockServer.URL)
 
 		// Test token exchange
@@ -202,7 +242,8 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		defer mockServer.Close()
 
 		// Create handler and set mock server URL
-		handler := v0auth.NewGitHubHandler(cfg)
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
 		handler.SetBaseURL(mockServer.URL)
 
 		// Test token exchange
@@ -234,7 +275,8 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		defer mockServer.Close()
 
 		// Create handler and set mock server URL
-		handler := v0auth.NewGitHubHandler(cfg)
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
 		handler.SetBaseURL(mockServer.URL)
 
 		// Test token exchange
@@ -275,7 +317,8 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		defer mockServer.Close()
 
 		// Create handler and set mock server URL
-		handler := v0auth.NewGitHubHandler(cfg)
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
 		handler.SetBaseURL(mockServer.URL)
 
 		// Test token exchange
@@ -304,7 +347,8 @@ func TestGitHubHandler_ExchangeToken(t *testing.T) {
 		defer mockServer.Close()
 
 		// Create handler and set mock server URL
-		handler := v0auth.NewGitHubHandler(cfg)
+		handler, err := v0auth.NewGitHubHandler(cfg)
+		require.NoError(t, err)
 		handler.SetBaseURL(mockServer.URL)
 
 		// Test token exchange
@@ -545,7 +589,8 @@ func TestValidGitHubNames(t *testing.T) {
 			defer mockServer.Close()
 
 			// Create handler and set mock server URL
-			handler := v0auth.NewGitHubHandler(cfg)
+			handler, err := v0auth.NewGitHubHandler(cfg)
+			require.NoError(t, err)
 			handler.SetBaseURL(mockServer.URL)
 
 			// Test token exchange
@@ -571,7 +616,8 @@ func TestGitHubHandler_Creation(t *testing.T) {
 		JWTPrivateKey: hex.EncodeToString(testSeed),
 	}
 
-	handler := v0auth.NewGitHubHandler(cfg)
+	handler, err := v0auth.NewGitHubHandler(cfg)
+	require.NoError(t, err)
 	assert.NotNil(t, handler, "handler should not be nil")
 }
 
@@ -603,7 +649,8 @@ func TestConcurrentTokenExchange(t *testing.T) {
 	}))
 	defer mockServer.Close()
 
-	handler := v0auth.NewGitHubHandler(cfg)
+	handler, err := v0auth.NewGitHubHandler(cfg)
+	require.NoError(t, err)
 	handler.SetBaseURL(mockServer.URL)
 
 	// Run multiple concurrent exchanges
@@ -624,3 +671,8 @@ func TestConcurrentTokenExchange(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestNewGitHubHandler_InvalidKey(t *testing.T) {
+	_, err := v0auth.NewGitHubHandler(&config.Config{JWTPrivateKey: "not-hex"})
+	assert.ErrorContains(t, err, "JWTPrivateKey must be a valid hex-encoded string")
+}
@@ -20,7 +20,8 @@ import (
 // GitHubOIDCTokenExchangeInput represents the input for GitHub OIDC token exchange
 type GitHubOIDCTokenExchangeInput struct {
 	Body struct {
-		OIDCToken string `json:"oidc_token" doc:"GitHub Actions OIDC token" required:"true"`
+		OIDCToken string                `json:"oidc_token" doc:"GitHub Actions OIDC token" required:"true"`
+		Scope     auth.PermissionAction `json:"scope,omitempty" doc:"Publish scope to grant: 'publish' for new servers and new versions, 'publish_version' for new versions of existing servers only" enum:"publish,publish_version" default:"publish"`
 	}
 }
 
@@ -230,15 +231,15 @@ func RegisterGitHubOIDCEndpoint(api huma.API, cfg *config.Config) {
 	handler := NewGitHubOIDCHandler(cfg)
 
 	// GitHub OIDC token exchange endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, v0.Public(huma.Operation{
 		OperationID: "exchange-github-oidc-token",
 		Method:      http.MethodPost,
 		Path:        "/v0/auth/github-oidc",
 		Summary:     "Exchange GitHub OIDC token for Registry JWT",
 		Description: "Exchange a GitHub Actions OIDC token for a short-lived Registry JWT token",
 		Tags:        []string{"auth"},
-	}, func(ctx context.Context, input *GitHubOIDCTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
-		response, err := handler.ExchangeToken(ctx, input.Body.OIDCToken)
+	}), func(ctx context.Context, input *GitHubOIDCTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
+		response, err := handler.ExchangeTokenWithScope(ctx, input.Body.OIDCToken, input.Body.Scope)
 		if err != nil {
 			return nil, huma.Error401Unauthorized("Token exchange failed", err)
 		}
@@ -251,6 +252,11 @@ func RegisterGitHubOIDCEndpoint(api huma.API, cfg *config.Config) {
 
 // ExchangeToken exchanges a GitHub OIDC token for a Registry JWT token
 func (h *GitHubOIDCHandler) ExchangeToken(ctx context.Context, oidcToken string) (*auth.TokenResponse, error) {
+	return h.ExchangeTokenWithScope(ctx, oidcToken, auth.PermissionActionPublish)
+}
+
+// ExchangeTokenWithScope exchanges a GitHub OIDC token for a Registry JWT token limited to the given publish scope
+func (h *GitHubOIDCHandler) ExchangeTokenWithScope(ctx context.Context, oidcToken string, scope auth.PermissionAction) (*auth.TokenResponse, error) {
 	// Validate OIDC token with audience "mcp-registry"
 	claims, err := h.validator.ValidateToken(ctx, oidcToken, "mcp-registry")
 	if err != nil {
@@ -258,7 +264,10 @@ func (h *GitHubOIDCHandler) ExchangeToken(ctx context.Context, oidcToken string)
 	}
 
 	// Extract repository information and build permissions
-	permissions := h.buildPermissions(claims)
+	permissions, err := auth.RestrictPublishScope(h.buildPermissions(claims), scope)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create JWT claims with GitHub OIDC info
 	jwtClaims := auth.JWTClaims{
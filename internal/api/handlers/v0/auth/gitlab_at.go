@@ -0,0 +1,306 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// GitLab token types accepted by the token exchange
+const (
+	GitLabTokenTypePersonal = "personal"
+	GitLabTokenTypeJob      = "job"
+)
+
+// gitLabDeveloperAccess is the minimum group access level that grants publish permissions
+const gitLabDeveloperAccess = 30
+
+// GitLabTokenExchangeInput represents the input for GitLab token exchange
+type GitLabTokenExchangeInput struct {
+	Body struct {
+		GitLabToken string `json:"gitlab_token" doc:"GitLab personal access token, or CI job token when token_type is 'job'" required:"true"`
+		TokenType   string `json:"token_type,omitempty" doc:"Kind of token: 'personal' for a personal access token, 'job' for a CI_JOB_TOKEN" enum:"personal,job" default:"personal"`
+	}
+}
+
+// GitLabHandler handles GitLab authentication
+type GitLabHandler struct {
+	config     *config.Config
+	jwtManager *auth.JWTManager
+	baseURL    string // Configurable for testing
+}
+
+// NewGitLabHandler creates a new GitLab handler
+func NewGitLabHandler(cfg *config.Config) *GitLabHandler {
+	return &GitLabHandler{
+		config:     cfg,
+		jwtManager: auth.NewJWTManager(cfg),
+		baseURL:    "https://gitlab.com/api/v4",
+	}
+}
+
+// SetBaseURL sets the base URL for GitLab API (used for testing)
+func (h *GitLabHandler) SetBaseURL(url string) {
+	h.baseURL = url
+}
+
+// RegisterGitLabATEndpoint registers the GitLab access token authentication endpoint
+func RegisterGitLabATEndpoint(api huma.API, cfg *config.Config) {
+	handler := NewGitLabHandler(cfg)
+
+	// GitLab token exchange endpoint
+	huma.Register(api, v0.Public(huma.Operation{
+		OperationID: "exchange-gitlab-token",
+		Method:      http.MethodPost,
+		Path:        "/v0/auth/gitlab-at",
+		Summary:     "Exchange GitLab access token for Registry JWT",
+		Description: "Exchange a GitLab personal access token or CI job token for a short-lived Registry JWT token with publish permissions for io.gitlab.* namespaces",
+		Tags:        []string{"auth"},
+	}), func(ctx context.Context, input *GitLabTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
+		response, err := handler.ExchangeToken(ctx, input.Body.GitLabToken, input.Body.TokenType)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("Token exchange failed", err)
+		}
+
+		return &v0.Response[auth.TokenResponse]{
+			Body: *response,
+		}, nil
+	})
+}
+
+// GitLabUser is a GitLab user as returned by the users and jobs APIs
+type GitLabUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// GitLabGroup is a GitLab group as returned by the groups API
+type GitLabGroup struct {
+	ID       int    `json:"id"`
+	FullPath string `json:"full_path"`
+}
+
+// GitLabJob is the job a CI job token belongs to
+type GitLabJob struct {
+	ID     int        `json:"id"`
+	User   GitLabUser `json:"user"`
+	WebURL string     `json:"web_url"`
+}
+
+// ExchangeToken exchanges a GitLab token for a Registry JWT token
+func (h *GitLabHandler) ExchangeToken(ctx context.Context, gitlabToken, tokenType string) (*auth.TokenResponse, error) {
+	var username string
+	var namespaces []string
+
+	switch tokenType {
+	case GitLabTokenTypePersonal, "":
+		user, err := h.getGitLabUser(ctx, gitlabToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GitLab user: %w", err)
+		}
+		groups, err := h.getGitLabGroups(ctx, gitlabToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GitLab groups: %w", err)
+		}
+		username = user.Username
+		for _, group := range groups {
+			namespaces = append(namespaces, group.FullPath)
+		}
+	case GitLabTokenTypeJob:
+		// Job tokens can't list groups; they grant the triggering user and the namespace of the job's project
+		job, err := h.getGitLabJob(ctx, gitlabToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GitLab job: %w", err)
+		}
+		namespace, err := projectNamespaceFromJobURL(job.WebURL)
+		if err != nil {
+			return nil, err
+		}
+		username = job.User.Username
+		namespaces = append(namespaces, namespace)
+	default:
+		return nil, fmt.Errorf("unsupported GitLab token type: %s", tokenType)
+	}
+
+	// Build permissions based on user and groups
+	permissions, err := h.buildPermissions(username, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create JWT claims with GitLab user info
+	claims := auth.JWTClaims{
+		AuthMethod:        auth.MethodGitLabAT,
+		AuthMethodSubject: username,
+		Permissions:       permissions,
+	}
+
+	// Generate Registry JWT token
+	tokenResponse, err := h.jwtManager.GenerateTokenResponse(ctx, claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT token: %w", err)
+	}
+
+	return tokenResponse, nil
+}
+
+// getGitLabUser gets the authenticated user's information
+func (h *GitLabHandler) getGitLabUser(ctx context.Context, token string) (*GitLabUser, error) {
+	resp, err := h.get(ctx, h.baseURL+"/user", "PRIVATE-TOKEN", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var user GitLabUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode user response: %w", err)
+	}
+
+	return &user, nil
+}
+
+// getGitLabGroups lists every group, including nested subgroups, where the user has at least developer access
+func (h *GitLabHandler) getGitLabGroups(ctx context.Context, token string) ([]GitLabGroup, error) {
+	var groups []GitLabGroup
+
+	page := "1"
+	for page != "" {
+		query := url.Values{
+			"min_access_level": {fmt.Sprint(gitLabDeveloperAccess)},
+			"per_page":         {"100"},
+			"page":             {page},
+		}
+		resp, err := h.get(ctx, h.baseURL+"/groups?"+query.Encode(), "PRIVATE-TOKEN", token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user groups: %w", err)
+		}
+
+		var pageGroups []GitLabGroup
+		err = json.NewDecoder(resp.Body).Decode(&pageGroups)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode groups response: %w", err)
+		}
+
+		groups = append(groups, pageGroups...)
+		page = resp.Header.Get("X-Next-Page")
+	}
+
+	return groups, nil
+}
+
+// getGitLabJob gets the job that a CI job token was issued for
+func (h *GitLabHandler) getGitLabJob(ctx context.Context, token string) (*GitLabJob, error) {
+	resp, err := h.get(ctx, h.baseURL+"/job", "JOB-TOKEN", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var job GitLabJob
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("failed to decode job response: %w", err)
+	}
+
+	return &job, nil
+}
+
+// get performs an authenticated GET against the GitLab API, returning the response only if it succeeded
+func (h *GitLabHandler) get(ctx context.Context, requestURL, tokenHeader, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set(tokenHeader, token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, body)
+	}
+
+	return resp, nil
+}
+
+// projectNamespaceFromJobURL extracts the project's namespace path from a job URL
+// such as https://gitlab.com/group/subgroup/project/-/jobs/123
+func projectNamespaceFromJobURL(jobURL string) (string, error) {
+	parsed, err := url.Parse(jobURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid GitLab job URL: %w", err)
+	}
+
+	projectPath, _, found := strings.Cut(strings.TrimPrefix(parsed.Path, "/"), "/-/")
+	slash := strings.LastIndex(projectPath, "/")
+	if !found || slash <= 0 {
+		return "", fmt.Errorf("invalid GitLab job URL: %s", jobURL)
+	}
+
+	return projectPath[:slash], nil
+}
+
+// buildPermissions builds permissions based on GitLab user and their group namespaces.
+// Nested group paths map to dotted namespaces: group/subgroup becomes io.gitlab.group.subgroup
+func (h *GitLabHandler) buildPermissions(username string, groupPaths []string) ([]auth.Permission, error) {
+	// Assert names match expected regex, to harden against people doing weird things in names.
+	// Dots are rejected because they would make user and nested group namespaces ambiguous
+	if !isValidGitLabPath(username) {
+		return nil, errors.New("GitLab username contains characters not supported in registry namespaces")
+	}
+
+	seen := map[string]bool{}
+	permissions := []auth.Permission{}
+	for _, path := range append([]string{username}, groupPaths...) {
+		segments := strings.Split(path, "/")
+		if !allValidGitLabPaths(segments) {
+			continue
+		}
+
+		pattern := fmt.Sprintf("io.gitlab.%s/*", strings.Join(segments, "."))
+		if seen[pattern] {
+			continue
+		}
+		seen[pattern] = true
+
+		permissions = append(permissions, auth.Permission{
+			Action:          auth.PermissionActionPublish,
+			ResourcePattern: pattern,
+		})
+	}
+
+	return permissions, nil
+}
+
+var gitLabPathRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+func isValidGitLabPath(name string) bool {
+	return gitLabPathRegex.MatchString(name)
+}
+
+func allValidGitLabPaths(names []string) bool {
+	for _, name := range names {
+		if !isValidGitLabPath(name) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,189 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v0auth "github.com/modelcontextprotocol/registry/internal/api/handlers/v0/auth"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGitLabAPI serves the user, groups and job endpoints for a single valid personal and job token
+func mockGitLabAPI(t *testing.T, user v0auth.GitLabUser, groupPages [][]v0auth.GitLabGroup, job v0auth.GitLabJob) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			if r.Header.Get("PRIVATE-TOKEN") != "valid-personal-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"message":"401 Unauthorized"}`))
+				return
+			}
+			json.NewEncoder(w).Encode(user) //nolint:errcheck
+		case "/groups":
+			if r.Header.Get("PRIVATE-TOKEN") != "valid-personal-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "30", r.URL.Query().Get("min_access_level"))
+			page := 1
+			if p := r.URL.Query().Get("page"); p == "2" {
+				page = 2
+			}
+			if page < len(groupPages) {
+				w.Header().Set("X-Next-Page", "2")
+			}
+			json.NewEncoder(w).Encode(groupPages[page-1]) //nolint:errcheck
+		case "/job":
+			if r.Header.Get("JOB-TOKEN") != "valid-job-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(job) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGitLabHandler_ExchangeToken(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{JWTPrivateKey: hex.EncodeToString(testSeed)}
+	jwtManager := auth.NewJWTManager(cfg)
+
+	job := v0auth.GitLabJob{
+		ID:     123,
+		User:   v0auth.GitLabUser{ID: 1, Username: "testuser"},
+		WebURL: "https://gitlab.com/parent-group/child_group/project/-/jobs/123",
+	}
+
+	exchange := func(t *testing.T, server *httptest.Server, token, tokenType string) (*auth.JWTClaims, error) {
+		t.Helper()
+		handler := v0auth.NewGitLabHandler(cfg)
+		handler.SetBaseURL(server.URL)
+
+		response, err := handler.ExchangeToken(context.Background(), token, tokenType)
+		if err != nil {
+			return nil, err
+		}
+		claims, err := jwtManager.ValidateToken(context.Background(), response.RegistryToken)
+		require.NoError(t, err)
+		assert.Equal(t, auth.MethodGitLabAT, claims.AuthMethod)
+		return claims, nil
+	}
+
+	patterns := func(claims *auth.JWTClaims) []string {
+		var result []string
+		for _, perm := range claims.Permissions {
+			assert.Equal(t, auth.PermissionActionPublish, perm.Action)
+			result = append(result, perm.ResourcePattern)
+		}
+		return result
+	}
+
+	t.Run("personal token grants user and group namespaces, including nested groups", func(t *testing.T) {
+		server := mockGitLabAPI(t,
+			v0auth.GitLabUser{ID: 1, Username: "testuser"},
+			[][]v0auth.GitLabGroup{
+				{{ID: 10, FullPath: "parent-group"}, {ID: 11, FullPath: "parent-group/child_group"}},
+				{{ID: 12, FullPath: "other"}},
+			},
+			job,
+		)
+		defer server.Close()
+
+		claims, err := exchange(t, server, "valid-personal-token", v0auth.GitLabTokenTypePersonal)
+		require.NoError(t, err)
+		assert.Equal(t, "testuser", claims.AuthMethodSubject)
+		assert.Equal(t, []string{
+			"io.gitlab.testuser/*",
+			"io.gitlab.parent-group/*",
+			"io.gitlab.parent-group.child_group/*",
+			"io.gitlab.other/*",
+		}, patterns(claims))
+
+		assert.True(t, jwtManager.HasPermission("io.gitlab.parent-group.child_group/server", auth.PermissionActionPublish, claims.Permissions))
+		assert.False(t, jwtManager.HasPermission("io.gitlab.testuser-other/server", auth.PermissionActionPublish, claims.Permissions))
+	})
+
+	t.Run("token type defaults to personal", func(t *testing.T) {
+		server := mockGitLabAPI(t, v0auth.GitLabUser{ID: 1, Username: "testuser"}, [][]v0auth.GitLabGroup{{}}, job)
+		defer server.Close()
+
+		claims, err := exchange(t, server, "valid-personal-token", "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"io.gitlab.testuser/*"}, patterns(claims))
+	})
+
+	t.Run("groups with unsupported characters are skipped", func(t *testing.T) {
+		server := mockGitLabAPI(t,
+			v0auth.GitLabUser{ID: 1, Username: "testuser"},
+			[][]v0auth.GitLabGroup{{{ID: 10, FullPath: "dotted.group"}, {ID: 11, FullPath: "good/sub.group"}, {ID: 12, FullPath: "good"}}},
+			job,
+		)
+		defer server.Close()
+
+		claims, err := exchange(t, server, "valid-personal-token", v0auth.GitLabTokenTypePersonal)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"io.gitlab.testuser/*", "io.gitlab.good/*"}, patterns(claims))
+	})
+
+	t.Run("username with unsupported characters is rejected", func(t *testing.T) {
+		server := mockGitLabAPI(t, v0auth.GitLabUser{ID: 1, Username: "test.user"}, [][]v0auth.GitLabGroup{{}}, job)
+		defer server.Close()
+
+		_, err := exchange(t, server, "valid-personal-token", v0auth.GitLabTokenTypePersonal)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GitLab username")
+	})
+
+	t.Run("job token grants the user and the project namespace", func(t *testing.T) {
+		server := mockGitLabAPI(t, v0auth.GitLabUser{}, nil, job)
+		defer server.Close()
+
+		claims, err := exchange(t, server, "valid-job-token", v0auth.GitLabTokenTypeJob)
+		require.NoError(t, err)
+		assert.Equal(t, "testuser", claims.AuthMethodSubject)
+		assert.Equal(t, []string{"io.gitlab.testuser/*", "io.gitlab.parent-group.child_group/*"}, patterns(claims))
+	})
+
+	t.Run("job in the user's own namespace is not duplicated", func(t *testing.T) {
+		personalJob := job
+		personalJob.WebURL = "https://gitlab.com/testuser/project/-/jobs/456"
+		server := mockGitLabAPI(t, v0auth.GitLabUser{}, nil, personalJob)
+		defer server.Close()
+
+		claims, err := exchange(t, server, "valid-job-token", v0auth.GitLabTokenTypeJob)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"io.gitlab.testuser/*"}, patterns(claims))
+	})
+
+	t.Run("invalid tokens are rejected", func(t *testing.T) {
+		server := mockGitLabAPI(t, v0auth.GitLabUser{ID: 1, Username: "testuser"}, [][]v0auth.GitLabGroup{{}}, job)
+		defer server.Close()
+
+		_, err := exchange(t, server, "invalid-token", v0auth.GitLabTokenTypePersonal)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 401")
+
+		_, err = exchange(t, server, "invalid-token", v0auth.GitLabTokenTypeJob)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 401")
+
+		_, err = exchange(t, server, "valid-personal-token", "deploy")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported GitLab token type")
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -51,11 +52,23 @@ func NewDefaultHTTPKeyFetcher() *DefaultHTTPKeyFetcher {
 	}
 }
 
+// wellKnownAuthURL builds the URL of a domain's key endpoint. The domain is validated here
+// rather than trusted from callers, so it can never add a port, path, query or userinfo.
+func wellKnownAuthURL(domain string) (string, error) {
+	if !isValidDomain(domain) {
+		return "", fmt.Errorf("invalid domain format")
+	}
+	return (&url.URL{Scheme: "https", Host: domain, Path: "/.well-known/mcp-registry-auth"}).String(), nil
+}
+
 // FetchKey fetches the public key from the well-known HTTP endpoint
 func (f *DefaultHTTPKeyFetcher) FetchKey(ctx context.Context, domain string) (string, error) {
-	url := fmt.Sprintf("https://%s/.well-known/mcp-registry-auth", domain)
+	keyURL, err := wellKnownAuthURL(domain)
+	if err != nil {
+		return "", err
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keyURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -70,7 +83,7 @@ func (f *DefaultHTTPKeyFetcher) FetchKey(ctx context.Context, domain string) (st
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP %d: failed to fetch key from %s", resp.StatusCode, url)
+		return "", fmt.Errorf("HTTP %d: failed to fetch key from %s", resp.StatusCode, keyURL)
 	}
 
 	// Limit response size to prevent DoS attacks
@@ -110,14 +123,14 @@ func RegisterHTTPEndpoint(api huma.API, cfg *config.Config) {
 	handler := NewHTTPAuthHandler(cfg)
 
 	// HTTP authentication endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, v0.Public(huma.Operation{
 		OperationID: "exchange-http-token",
 		Method:      http.MethodPost,
 		Path:        "/v0/auth/http",
 		Summary:     "Exchange HTTP signature for Registry JWT",
 		Description: "Authenticate using HTTP-hosted public key and signed timestamp",
 		Tags:        []string{"auth"},
-	}, func(ctx context.Context, input *HTTPTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
+	}), func(ctx context.Context, input *HTTPTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
 		response, err := handler.ExchangeToken(ctx, input.Body.Domain, input.Body.Timestamp, input.Body.SignedTimestamp)
 		if err != nil {
 			return nil, huma.Error401Unauthorized("HTTP authentication failed", err)
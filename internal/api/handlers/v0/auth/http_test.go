@@ -241,3 +241,24 @@ func TestDefaultHTTPKeyFetcher_FetchKey(t *testing.T) {
 	_, err := fetcher.FetchKey(context.Background(), "nonexistent-test-domain-12345.com")
 	assert.Error(t, err)
 }
+
+func TestDefaultHTTPKeyFetcher_RejectsUnsafeDomains(t *testing.T) {
+	fetcher := auth.NewDefaultHTTPKeyFetcher()
+
+	// Each of these would change the fetched URL's host, port, path or query if interpolated
+	for _, domain := range []string{
+		"example.com/../admin",
+		"example.com/",
+		"example.com?x=",
+		"example.com#",
+		"example.com:8443",
+		"user@example.com",
+		"internal\\example.com",
+		"example.com\n",
+		"",
+	} {
+		_, err := fetcher.FetchKey(context.Background(), domain)
+		require.Error(t, err, domain)
+		assert.Equal(t, "invalid domain format", err.Error(), domain)
+	}
+}
@@ -3,25 +3,36 @@ package auth
 import (
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 )
 
-// RegisterAuthEndpoints registers all authentication endpoints
-func RegisterAuthEndpoints(api huma.API, cfg *config.Config) {
+// RegisterAuthEndpoints registers all authentication endpoints, including any supplied by an embedding program
+func RegisterAuthEndpoints(api huma.API, cfg *config.Config, db database.Database, providers ...Provider) error {
 	// Register GitHub access token authentication endpoint
-	RegisterGitHubATEndpoint(api, cfg)
+	if err := RegisterGitHubATEndpoint(api, cfg); err != nil {
+		return err
+	}
 
 	// Register GitHub OIDC authentication endpoint
 	RegisterGitHubOIDCEndpoint(api, cfg)
 
+	// Register GitLab access token authentication endpoint
+	RegisterGitLabATEndpoint(api, cfg)
+
 	// Register configurable OIDC authentication endpoints
-	RegisterOIDCEndpoints(api, cfg)
+	if err := RegisterOIDCEndpoints(api, cfg); err != nil {
+		return err
+	}
 
 	// Register DNS-based authentication endpoint
-	RegisterDNSEndpoint(api, cfg)
+	RegisterDNSEndpoint(api, cfg, db)
 
 	// Register HTTP-based authentication endpoint
 	RegisterHTTPEndpoint(api, cfg)
 
 	// Register anonymous authentication endpoint
 	RegisterNoneEndpoint(api, cfg)
+
+	// Register embedder-supplied authentication endpoints
+	return RegisterProviderEndpoints(api, cfg, providers)
 }
@@ -11,6 +11,9 @@ import (
 	"github.com/modelcontextprotocol/registry/internal/config"
 )
 
+// anonymousNamespacePattern is the only resource anonymous tokens may publish to
+const anonymousNamespacePattern = "io.modelcontextprotocol.anonymous/*"
+
 // NoneHandler handles anonymous authentication
 type NoneHandler struct {
 	config     *config.Config
@@ -25,7 +28,8 @@ func NewNoneHandler(cfg *config.Config) *NoneHandler {
 	}
 }
 
-// RegisterNoneEndpoint registers the anonymous authentication endpoint
+// RegisterNoneEndpoint registers the anonymous authentication endpoint.
+// It must be explicitly enabled, and configuration validation refuses to enable it in production.
 func RegisterNoneEndpoint(api huma.API, cfg *config.Config) {
 	if !cfg.EnableAnonymousAuth {
 		return
@@ -34,14 +38,14 @@ func RegisterNoneEndpoint(api huma.API, cfg *config.Config) {
 	handler := NewNoneHandler(cfg)
 
 	// Anonymous token endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, v0.Public(huma.Operation{
 		OperationID: "get-anonymous-token",
 		Method:      http.MethodPost,
 		Path:        "/v0/auth/none",
 		Summary:     "Get anonymous Registry JWT",
 		Description: "Get a short-lived Registry JWT token for publishing to io.modelcontextprotocol.anonymous/* namespace",
 		Tags:        []string{"auth"},
-	}, func(ctx context.Context, _ *struct{}) (*v0.Response[auth.TokenResponse], error) {
+	}), func(ctx context.Context, _ *struct{}) (*v0.Response[auth.TokenResponse], error) {
 		response, err := handler.GetAnonymousToken(ctx)
 		if err != nil {
 			return nil, huma.Error500InternalServerError("Failed to generate token", err)
@@ -55,11 +59,12 @@ func RegisterNoneEndpoint(api huma.API, cfg *config.Config) {
 
 // GetAnonymousToken generates an anonymous Registry JWT token
 func (h *NoneHandler) GetAnonymousToken(ctx context.Context) (*auth.TokenResponse, error) {
-	// Build permissions for anonymous namespace only
+	// Build permissions for anonymous namespace only. These are fixed rather than derived
+	// from configuration, so a misconfigured registry cannot widen what anonymous callers get.
 	permissions := []auth.Permission{
 		{
 			Action:          auth.PermissionActionPublish,
-			ResourcePattern: "io.modelcontextprotocol.anonymous/*",
+			ResourcePattern: anonymousNamespacePattern,
 		},
 	}
 
@@ -5,8 +5,12 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
 	v0auth "github.com/modelcontextprotocol/registry/internal/api/handlers/v0/auth"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
@@ -48,3 +52,50 @@ func TestNoneHandler_GetAnonymousToken(t *testing.T) {
 	assert.Equal(t, auth.PermissionActionPublish, claims.Permissions[0].Action)
 	assert.Equal(t, "io.modelcontextprotocol.anonymous/*", claims.Permissions[0].ResourcePattern)
 }
+
+func TestNoneHandler_PermissionsIgnoreConfiguration(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+
+	// Settings that widen other auth methods' permissions do not apply to anonymous tokens
+	cfg := &config.Config{
+		JWTPrivateKey:       hex.EncodeToString(testSeed),
+		EnableAnonymousAuth: true,
+		OIDCPublishPerms:    "*",
+		OIDCEditPerms:       "*",
+	}
+
+	tokenResponse, err := v0auth.NewNoneHandler(cfg).GetAnonymousToken(context.Background())
+	require.NoError(t, err)
+
+	jwtManager := auth.NewJWTManager(cfg)
+	claims, err := jwtManager.ValidateToken(context.Background(), tokenResponse.RegistryToken)
+	require.NoError(t, err)
+	assert.Equal(t, []auth.Permission{
+		{Action: auth.PermissionActionPublish, ResourcePattern: "io.modelcontextprotocol.anonymous/*"},
+	}, claims.Permissions)
+	assert.True(t, jwtManager.HasPermission("io.modelcontextprotocol.anonymous/test", auth.PermissionActionPublish, claims.Permissions))
+	assert.False(t, jwtManager.HasPermission("io.github.someone/server", auth.PermissionActionPublish, claims.Permissions))
+	assert.False(t, jwtManager.HasPermission("io.modelcontextprotocol.anonymous/test", auth.PermissionActionEdit, claims.Permissions))
+}
+
+func TestRegisterNoneEndpoint_DisabledByDefault(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+
+	for _, enabled := range []bool{false, true} {
+		mux := http.NewServeMux()
+		api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+		v0auth.RegisterNoneEndpoint(api, &config.Config{JWTPrivateKey: hex.EncodeToString(testSeed), EnableAnonymousAuth: enabled})
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v0/auth/none", nil))
+		if enabled {
+			assert.Equal(t, http.StatusOK, w.Code)
+		} else {
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -21,7 +22,8 @@ import (
 // OIDCTokenExchangeInput represents the input for OIDC token exchange
 type OIDCTokenExchangeInput struct {
 	Body struct {
-		OIDCToken string `json:"oidc_token" doc:"OIDC ID token from any provider" required:"true"`
+		OIDCToken string                `json:"oidc_token" doc:"OIDC ID token from any provider" required:"true"`
+		Scope     auth.PermissionAction `json:"scope,omitempty" doc:"Publish scope to grant: 'publish' for new servers and new versions, 'publish_version' for new versions of existing servers only" enum:"publish,publish_version" default:"publish"`
 	}
 }
 
@@ -187,25 +189,30 @@ type OIDCSession struct {
 }
 
 // NewOIDCHandler creates a new OIDC handler
-func NewOIDCHandler(cfg *config.Config) *OIDCHandler {
+func NewOIDCHandler(cfg *config.Config) (*OIDCHandler, error) {
 	if !cfg.OIDCEnabled {
-		panic("OIDC is not enabled - should not create OIDC handler")
+		return nil, errors.New("OIDC is not enabled")
 	}
 	if cfg.OIDCIssuer == "" {
-		panic("OIDC issuer is required when OIDC is enabled")
+		return nil, errors.New("OIDC issuer is required when OIDC is enabled")
+	}
+
+	jwtManager, err := auth.LoadJWTManager(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	validator, err := NewStandardOIDCValidator(cfg.OIDCIssuer, cfg.OIDCClientID, cfg.OIDCClientSecret)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to initialize OIDC validator: %v", err))
+		return nil, fmt.Errorf("failed to initialize OIDC validator: %w", err)
 	}
 
 	return &OIDCHandler{
 		config:     cfg,
-		jwtManager: auth.NewJWTManager(cfg),
+		jwtManager: jwtManager,
 		validator:  validator,
 		sessions:   make(map[string]OIDCSession),
-	}
+	}, nil
 }
 
 // SetValidator sets a custom OIDC validator (used for testing)
@@ -214,23 +221,26 @@ func (h *OIDCHandler) SetValidator(validator GenericOIDCValidator) {
 }
 
 // RegisterOIDCEndpoints registers all OIDC authentication endpoints
-func RegisterOIDCEndpoints(api huma.API, cfg *config.Config) {
+func RegisterOIDCEndpoints(api huma.API, cfg *config.Config) error {
 	if !cfg.OIDCEnabled {
-		return // Skip registration if OIDC is not enabled
+		return nil // Skip registration if OIDC is not enabled
 	}
 
-	handler := NewOIDCHandler(cfg)
+	handler, err := NewOIDCHandler(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create OIDC handler: %w", err)
+	}
 
 	// Direct token exchange endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, v0.Public(huma.Operation{
 		OperationID: "exchange-oidc-token",
 		Method:      http.MethodPost,
 		Path:        "/v0/auth/oidc",
 		Summary:     "Exchange OIDC ID token for Registry JWT",
 		Description: "Exchange an OIDC ID token from any configured provider for a short-lived Registry JWT token",
 		Tags:        []string{"auth"},
-	}, func(ctx context.Context, input *OIDCTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
-		response, err := handler.ExchangeToken(ctx, input.Body.OIDCToken)
+	}), func(ctx context.Context, input *OIDCTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
+		response, err := handler.ExchangeTokenWithScope(ctx, input.Body.OIDCToken, input.Body.Scope)
 		if err != nil {
 			return nil, huma.Error401Unauthorized("Token exchange failed", err)
 		}
@@ -241,14 +251,14 @@ func RegisterOIDCEndpoints(api huma.API, cfg *config.Config) {
 	})
 
 	// Authorization start endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, v0.Public(huma.Operation{
 		OperationID: "oidc-auth-start",
 		Method:      http.MethodGet,
 		Path:        "/v0/auth/oidc/start",
 		Summary:     "Start OIDC authorization flow",
 		Description: "Redirects user to OIDC provider for authentication",
 		Tags:        []string{"auth"},
-	}, func(ctx context.Context, input *OIDCStartInput) (*v0.Response[map[string]string], error) {
+	}), func(ctx context.Context, input *OIDCStartInput) (*v0.Response[map[string]string], error) {
 		authURL, err := handler.StartAuth(ctx, input.RedirectURI)
 		if err != nil {
 			return nil, huma.Error400BadRequest("Failed to start OIDC flow", err)
@@ -263,14 +273,14 @@ func RegisterOIDCEndpoints(api huma.API, cfg *config.Config) {
 	})
 
 	// Authorization callback endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, v0.Public(huma.Operation{
 		OperationID: "oidc-auth-callback",
 		Method:      http.MethodGet,
 		Path:        "/v0/auth/oidc/callback",
 		Summary:     "Handle OIDC authorization callback",
 		Description: "Handles the callback from OIDC provider after user authorization",
 		Tags:        []string{"auth"},
-	}, func(ctx context.Context, input *OIDCCallbackInput) (*v0.Response[auth.TokenResponse], error) {
+	}), func(ctx context.Context, input *OIDCCallbackInput) (*v0.Response[auth.TokenResponse], error) {
 		response, err := handler.HandleCallback(ctx, input.Code, input.State)
 		if err != nil {
 			return nil, huma.Error400BadRequest("Failed to handle OIDC callback", err)
@@ -280,10 +290,17 @@ func RegisterOIDCEndpoints(api huma.API, cfg *config.Config) {
 			Body: *response,
 		}, nil
 	})
+
+	return nil
 }
 
 // ExchangeToken exchanges an OIDC ID token for a Registry JWT token
 func (h *OIDCHandler) ExchangeToken(ctx context.Context, oidcToken string) (*auth.TokenResponse, error) {
+	return h.ExchangeTokenWithScope(ctx, oidcToken, auth.PermissionActionPublish)
+}
+
+// ExchangeTokenWithScope exchanges an OIDC ID token for a Registry JWT token limited to the given publish scope
+func (h *OIDCHandler) ExchangeTokenWithScope(ctx context.Context, oidcToken string, scope auth.PermissionAction) (*auth.TokenResponse, error) {
 	// Validate OIDC token
 	claims, err := h.validator.ValidateToken(ctx, oidcToken)
 	if err != nil {
@@ -296,13 +313,17 @@ func (h *OIDCHandler) ExchangeToken(ctx context.Context, oidcToken string) (*aut
 	}
 
 	// Build permissions based on claims and configuration
-	permissions := h.buildPermissions(claims)
+	permissions, err := auth.RestrictPublishScope(h.buildPermissions(claims), scope)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create JWT claims
 	jwtClaims := auth.JWTClaims{
 		AuthMethod:        auth.MethodOIDC,
 		AuthMethodSubject: claims.Subject,
 		Permissions:       permissions,
+		Tenant:            h.tenant(claims),
 	}
 
 	// Generate Registry JWT token
@@ -400,6 +421,33 @@ func (h *OIDCHandler) validateExtraClaims(claims *OIDCClaims) error {
 	return nil
 }
 
+// tenant reads the token's tenant from the configured ID token claim, such as groups. A list
+// claim gives the first value with the configured prefix, which is removed. Values that are not
+// valid tenant identifiers are ignored, leaving the tenant to TENANT_SUBJECTS.
+func (h *OIDCHandler) tenant(claims *OIDCClaims) string {
+	if !h.config.TenancyEnabled || h.config.TenantOIDCClaim == "" {
+		return ""
+	}
+
+	var values []any
+	switch value := claims.ExtraClaims[h.config.TenantOIDCClaim].(type) {
+	case string:
+		values = []any{value}
+	case []any:
+		values = value
+	}
+	for _, value := range values {
+		group, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if tenant, ok := strings.CutPrefix(group, h.config.TenantGroupPrefix); ok && config.TenantRegex.MatchString(tenant) {
+			return tenant
+		}
+	}
+	return ""
+}
+
 // buildPermissions builds permissions based on OIDC claims and configuration
 func (h *OIDCHandler) buildPermissions(_ *OIDCClaims) []auth.Permission {
 	var permissions []auth.Permission
@@ -101,7 +101,8 @@ func TestOIDCHandler_ExchangeToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := auth.NewOIDCHandler(tt.config)
+			handler, err := auth.NewOIDCHandler(tt.config)
+			require.NoError(t, err)
 			if tt.mockValidator != nil {
 				handler.SetValidator(tt.mockValidator)
 			}
@@ -138,7 +139,8 @@ func TestOIDCHandler_StartAuth(t *testing.T) {
 		},
 	}
 
-	handler := auth.NewOIDCHandler(config)
+	handler, err := auth.NewOIDCHandler(config)
+	require.NoError(t, err)
 	handler.SetValidator(mockValidator)
 
 	ctx := context.Background()
@@ -151,4 +153,19 @@ func TestOIDCHandler_StartAuth(t *testing.T) {
 	assert.Contains(t, authURL, "nonce=")
 }
 
+func TestNewOIDCHandler_InvalidConfig(t *testing.T) {
+	_, err := auth.NewOIDCHandler(&config.Config{OIDCEnabled: false})
+	assert.ErrorContains(t, err, "OIDC is not enabled")
+
+	_, err = auth.NewOIDCHandler(&config.Config{OIDCEnabled: true})
+	assert.ErrorContains(t, err, "OIDC issuer is required")
+
+	_, err = auth.NewOIDCHandler(&config.Config{
+		OIDCEnabled:   true,
+		OIDCIssuer:    "https://accounts.google.com",
+		JWTPrivateKey: "deadbeef",
+	})
+	assert.ErrorContains(t, err, "JWTPrivateKey")
+}
+
 // Note: validateExtraClaims and buildPermissions are tested through ExchangeToken integration tests
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/danielgtaylor/huma/v2"
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// builtinMethods are the auth methods served by this package's own endpoints
+var builtinMethods = map[auth.Method]bool{
+	auth.MethodGitHubAT:   true,
+	auth.MethodGitHubOIDC: true,
+	auth.MethodGitLabAT:   true,
+	auth.MethodOIDC:       true,
+	auth.MethodDNS:        true,
+	auth.MethodHTTP:       true,
+	auth.MethodNone:       true,
+}
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Provider is an auth method supplied by a program embedding the registry
+type Provider interface {
+	// Name identifies the method; the provider is served at POST /v0/auth/{name}
+	Name() string
+	// Authenticate checks a credential and returns who it belongs to and what they may do
+	Authenticate(ctx context.Context, credential string) (subject string, permissions []auth.Permission, err error)
+}
+
+// ProviderTokenExchangeInput represents the input for an embedder-supplied auth method
+type ProviderTokenExchangeInput struct {
+	Body struct {
+		Credential string `json:"credential" doc:"Credential understood by this auth method" required:"true"`
+	}
+}
+
+// ValidateProviders checks that provider names are well formed, unique and do not shadow a built-in method
+func ValidateProviders(providers []Provider) error {
+	seen := make(map[string]bool)
+	for _, provider := range providers {
+		name := provider.Name()
+		switch {
+		case !providerNamePattern.MatchString(name):
+			return fmt.Errorf("invalid auth provider name %q: use lowercase letters, digits and hyphens", name)
+		case builtinMethods[auth.Method(name)]:
+			return fmt.Errorf("auth provider name %q is reserved for a built-in method", name)
+		case seen[name]:
+			return fmt.Errorf("auth provider name %q is used more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// RegisterProviderEndpoints registers an endpoint for each embedder-supplied auth method
+func RegisterProviderEndpoints(api huma.API, cfg *config.Config, providers []Provider) error {
+	if err := ValidateProviders(providers); err != nil {
+		return err
+	}
+
+	jwtManager := auth.NewJWTManager(cfg)
+	for _, provider := range providers {
+		name := provider.Name()
+		huma.Register(api, v0.Public(huma.Operation{
+			OperationID: "exchange-" + name + "-token",
+			Method:      http.MethodPost,
+			Path:        "/v0/auth/" + name,
+			Summary:     "Exchange " + name + " credential for Registry JWT",
+			Description: "Authenticate with the " + name + " method provided by this registry's operator",
+			Tags:        []string{"auth"},
+		}), func(ctx context.Context, input *ProviderTokenExchangeInput) (*v0.Response[auth.TokenResponse], error) {
+			response, err := exchangeProviderToken(ctx, jwtManager, provider, input.Body.Credential)
+			if err != nil {
+				return nil, huma.Error401Unauthorized("Authentication failed", err)
+			}
+
+			return &v0.Response[auth.TokenResponse]{
+				Body: *response,
+			}, nil
+		})
+	}
+	return nil
+}
+
+func exchangeProviderToken(ctx context.Context, jwtManager *auth.JWTManager, provider Provider, credential string) (*auth.TokenResponse, error) {
+	subject, permissions, err := provider.Authenticate(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+	if subject == "" {
+		return nil, errors.New("auth provider returned no subject")
+	}
+
+	// Create JWT claims
+	jwtClaims := auth.JWTClaims{
+		AuthMethod:        auth.Method(provider.Name()),
+		AuthMethodSubject: subject,
+		Permissions:       permissions,
+	}
+
+	// Generate Registry JWT token
+	tokenResponse, err := jwtManager.GenerateTokenResponse(ctx, jwtClaims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT token: %w", err)
+	}
+
+	return tokenResponse, nil
+}
@@ -0,0 +1,70 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/configschema"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestServerConfigSchemaEndpoint(t *testing.T) {
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{})
+	published, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        "com.example/weather",
+		Description: "Weather lookups",
+		Version:     "1.0.0",
+		Packages: []model.Package{{
+			RegistryType: "npm",
+			Identifier:   "@example/weather",
+			Version:      "1.0.0",
+			Transport:    model.Transport{Type: "stdio"},
+			EnvironmentVariables: []model.KeyValueInput{
+				{Name: "WEATHER_API_KEY", InputWithVariables: model.InputWithVariables{Input: model.Input{IsRequired: true, IsSecret: true}}},
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	t.Run("describes the server's inputs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+published.Meta.Official.ID+"/config-schema", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+		assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+
+		var schema configschema.Schema
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schema))
+		assert.Equal(t, configschema.Draft, schema.Schema)
+		assert.Equal(t, []string{"environment_variables"}, schema.Required)
+		apiKey := schema.Properties["environment_variables"].Properties["WEATHER_API_KEY"]
+		require.NotNil(t, apiKey)
+		assert.True(t, apiKey.WriteOnly)
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers/00000000-0000-0000-0000-000000000000/config-schema", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
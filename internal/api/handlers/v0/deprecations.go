@@ -0,0 +1,45 @@
+package v0
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ServerWithWarnings is a server JSON response, with warnings about deprecated features the request
+// used and problems the registry accepted it despite
+type ServerWithWarnings struct {
+	apiv0.ServerJSON
+	Warnings []apiv0.Warning `json:"warnings,omitempty" doc:"Deprecated request features that will stop working in a future release, and problems with the server that did not prevent publishing, such as a remote URL another server already declares"`
+	Skipped  bool            `json:"skipped,omitempty" doc:"Set when a publish with If-Version-Newer was skipped because the version is not newer than the latest; the server is then the current latest version"`
+}
+
+type legacyExtensionsKey struct{}
+
+// WithLegacyExtensions marks ctx as serving a request whose body used the legacy x-publisher extension format
+func WithLegacyExtensions(ctx context.Context) context.Context {
+	return context.WithValue(ctx, legacyExtensionsKey{}, true)
+}
+
+type ignoredUnknownFieldsKey struct{}
+
+// WithIgnoredUnknownFields marks ctx as serving a request whose body had fields the server JSON
+// format does not define, dropped because it set apiv0.AllowUnknownFieldsHeader
+func WithIgnoredUnknownFields(ctx context.Context, warnings []apiv0.Warning) context.Context {
+	return context.WithValue(ctx, ignoredUnknownFieldsKey{}, warnings)
+}
+
+// withWarnings wraps a server response with the deprecation warnings for the request ctx serves
+// and the warnings validation and the service added to it
+func withWarnings(ctx context.Context, server *apiv0.ServerJSON) ServerWithWarnings {
+	response := ServerWithWarnings{ServerJSON: *server}
+	if legacy, _ := ctx.Value(legacyExtensionsKey{}).(bool); legacy {
+		response.Warnings = append(response.Warnings, apiv0.Warning{Code: apiv0.WarningLegacyExtensions, Message: apiv0.LegacyExtensionsWarning})
+	}
+	if ignored, _ := ctx.Value(ignoredUnknownFieldsKey{}).([]apiv0.Warning); len(ignored) > 0 {
+		response.Warnings = append(response.Warnings, ignored...)
+	}
+	response.Warnings = append(response.Warnings, validators.WarningsFrom(ctx)...)
+	return response
+}
@@ -0,0 +1,327 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestLegacyExtensionFormat(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:            "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		EnableRegistryValidation: false,
+	}
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+			{Action: auth.PermissionActionEdit, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	// publish sends body to a fresh registry and returns the response and the stored record
+	publish := func(t *testing.T, body string) (*httptest.ResponseRecorder, *apiv0.ServerJSON) {
+		t.Helper()
+		db := database.NewMemoryDB()
+		registryService := service.NewRegistryService(db, cfg)
+		mux := http.NewServeMux()
+		api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+		api.UseMiddleware(router.LegacyExtensionsMiddleware(api, nil))
+		v0.RegisterPublishEndpoint(api, registryService, cfg)
+
+		req := httptest.NewRequest(http.MethodPost, "/v0/publish", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		stored, _, err := db.List(context.Background(), nil, "", 10)
+		require.NoError(t, err)
+		if len(stored) == 0 {
+			return w, nil
+		}
+		return w, stored[0]
+	}
+
+	// comparable strips the registry metadata, which differs on every publish
+	comparable := func(server *apiv0.ServerJSON) apiv0.ServerJSON {
+		copied := *server
+		meta := *server.Meta
+		meta.Official = nil
+		copied.Meta = &meta
+		return copied
+	}
+
+	canonical := `{
+		"name": "io.github.example/legacy",
+		"description": "A server published in both formats",
+		"version": "1.0.0",
+		"_meta": {"io.modelcontextprotocol.registry/publisher-provided": {"tool": "ci", "build": 42}}
+	}`
+	canonicalResp, canonicalRecord := publish(t, canonical)
+	require.Equal(t, http.StatusOK, canonicalResp.Code, canonicalResp.Body.String())
+	require.NotNil(t, canonicalRecord)
+	var canonicalBody v0.ServerWithWarnings
+	require.NoError(t, json.Unmarshal(canonicalResp.Body.Bytes(), &canonicalBody))
+	assert.Empty(t, canonicalBody.Warnings)
+
+	for name, body := range map[string]string{
+		"publish request wrapper": `{
+			"server": {
+				"name": "io.github.example/legacy",
+				"description": "A server published in both formats",
+				"version": "1.0.0"
+			},
+			"x-publisher": {"tool": "ci", "build": 42}
+		}`,
+		"top-level x-publisher": `{
+			"name": "io.github.example/legacy",
+			"description": "A server published in both formats",
+			"version": "1.0.0",
+			"x-publisher": {"tool": "ci", "build": 42}
+		}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, record := publish(t, body)
+			require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+			require.NotNil(t, record)
+			assert.Equal(t, comparable(canonicalRecord), comparable(record))
+
+			var responseBody v0.ServerWithWarnings
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &responseBody))
+			assert.Equal(t, []apiv0.Warning{{Code: apiv0.WarningLegacyExtensions, Message: apiv0.LegacyExtensionsWarning}}, responseBody.Warnings)
+			assert.Equal(t, "io.github.example/legacy", responseBody.Name)
+		})
+	}
+
+	t.Run("legacy registry metadata is rejected like _meta", func(t *testing.T) {
+		resp, record := publish(t, `{
+			"name": "io.github.example/legacy",
+			"description": "A server published in both formats",
+			"version": "1.0.0",
+			"x-io.modelcontextprotocol.registry": {"id": "00000000-0000-0000-0000-000000000000"}
+		}`)
+		assert.GreaterOrEqual(t, resp.Code, http.StatusBadRequest)
+		assert.Contains(t, resp.Body.String(), "io.modelcontextprotocol.registry/official")
+		assert.Nil(t, record)
+	})
+
+	t.Run("both formats at once are rejected", func(t *testing.T) {
+		resp, record := publish(t, `{
+			"name": "io.github.example/legacy",
+			"description": "A server published in both formats",
+			"version": "1.0.0",
+			"x-publisher": {"tool": "ci"},
+			"_meta": {"io.modelcontextprotocol.registry/publisher-provided": {"tool": "ci"}}
+		}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Nil(t, record)
+	})
+}
+
+func TestLegacyPublishResponse(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:            "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		EnableRegistryValidation: false,
+	}
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod:  auth.MethodNone,
+		Permissions: []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "*"}},
+	})
+	require.NoError(t, err)
+
+	reader := sdkmetric.NewManualReader()
+	metrics, err := telemetry.NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+	// legacyResponses sums the legacy publish response counter by reason
+	legacyResponses := func(t *testing.T) map[string]int64 {
+		t.Helper()
+		var collected metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &collected))
+		counts := map[string]int64{}
+		for _, scope := range collected.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				if m.Name != telemetry.Namespace+".legacy_publish.responses" {
+					continue
+				}
+				for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					reason, _ := point.Attributes.Value("reason")
+					counts[reason.AsString()] += point.Value
+				}
+			}
+		}
+		return counts
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(router.LegacyPublishResponseMiddleware(metrics))
+	v0.RegisterPublishEndpoint(api, service.NewRegistryService(database.NewMemoryDB(), cfg), cfg)
+
+	version := 0
+	publish := func(t *testing.T, accept, userAgent string) *httptest.ResponseRecorder {
+		t.Helper()
+		version++
+		body := fmt.Sprintf(`{"name": "io.github.example/shapes", "description": "A server", "version": "1.0.%d"}`, version)
+		req := httptest.NewRequest(http.MethodPost, "/v0/publish", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if userAgent != "" {
+			req.Header.Set("User-Agent", userAgent)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	requireLegacy := func(t *testing.T, w *httptest.ResponseRecorder) {
+		t.Helper()
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, apiv0.LegacyPublishMessage, body["message"])
+		assert.NotEmpty(t, body["id"])
+		assert.Len(t, body, 2, "only the legacy fields are returned")
+	}
+	requireFull := func(t *testing.T, w *httptest.ResponseRecorder) {
+		t.Helper()
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body v0.ServerWithWarnings
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "io.github.example/shapes", body.Name)
+		require.NotNil(t, body.Meta)
+		require.NotNil(t, body.Meta.Official)
+		assert.NotEmpty(t, body.Meta.Official.ID)
+	}
+
+	t.Run("new clients get the full document", func(t *testing.T) {
+		requireFull(t, publish(t, "", ""))
+		requireFull(t, publish(t, "", "Go-http-client/1.1"))
+		requireFull(t, publish(t, `application/json; profile="server"`, "mcp-publisher/1.4.0"))
+		assert.Empty(t, legacyResponses(t))
+	})
+
+	t.Run("old publisher releases get the legacy shape", func(t *testing.T) {
+		requireLegacy(t, publish(t, "application/json", "mcp-publisher/1.0.0"))
+		assert.Equal(t, map[string]int64{"user_agent": 1}, legacyResponses(t))
+	})
+
+	t.Run("the legacy shape can be asked for explicitly", func(t *testing.T) {
+		requireLegacy(t, publish(t, `application/json; profile="legacy"`, ""))
+		assert.Equal(t, map[string]int64{"user_agent": 1, "accept": 1}, legacyResponses(t))
+	})
+
+	t.Run("errors keep their shape", func(t *testing.T) {
+		version-- // republish the last version, a duplicate
+		w := publish(t, `application/json; profile="legacy"`, "")
+		assert.GreaterOrEqual(t, w.Code, http.StatusBadRequest)
+		assert.Contains(t, w.Body.String(), `"detail"`)
+		assert.Equal(t, map[string]int64{"user_agent": 1, "accept": 1}, legacyResponses(t))
+	})
+}
+
+func TestUnknownFields(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:            "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		EnableRegistryValidation: false,
+	}
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod:  auth.MethodNone,
+		Permissions: []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "*"}},
+	})
+	require.NoError(t, err)
+
+	// publish sends body to a fresh registry and returns the response and the stored record
+	publish := func(t *testing.T, body string, allowUnknown bool) (*httptest.ResponseRecorder, *apiv0.ServerJSON) {
+		t.Helper()
+		db := database.NewMemoryDB()
+		mux := http.NewServeMux()
+		humaConfig := huma.DefaultConfig("Test API", "1.0.0")
+		humaConfig.Transformers = append(humaConfig.Transformers, v0.TransformValidationErrors)
+		api := humago.New(mux, humaConfig)
+		api.UseMiddleware(router.LegacyExtensionsMiddleware(api, nil))
+		api.UseMiddleware(router.UnknownFieldsMiddleware(api))
+		v0.RegisterPublishEndpoint(api, service.NewRegistryService(db, cfg), cfg)
+
+		req := httptest.NewRequest(http.MethodPost, "/v0/publish", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if allowUnknown {
+			req.Header.Set(apiv0.AllowUnknownFieldsHeader, "true")
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		stored, _, err := db.List(context.Background(), nil, "", 10)
+		require.NoError(t, err)
+		if len(stored) == 0 {
+			return w, nil
+		}
+		return w, stored[0]
+	}
+
+	body := `{
+		"name": "io.github.example/typos",
+		"description": "A server with typos",
+		"version": "1.0.0",
+		"respository": {"url": "https://github.com/example/typos", "source": "github"},
+		"packages": [{"registry_type": "npm", "identifier": "@example/typos", "version": "1.0.0", "transport": {"type": "stdio"}, "runtimeHint": "npx"}]
+	}`
+
+	t.Run("unknown fields are rejected by default", func(t *testing.T) {
+		resp, record := publish(t, body, false)
+		require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+		assert.Nil(t, record)
+		assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+
+		var problem apiv0.ValidationError
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &problem))
+		assert.Equal(t, []apiv0.ErrorDetail{
+			{Code: apiv0.ErrorCodeUnexpectedProperty, Location: "/packages/0/runtimeHint", Message: `unknown field "runtimeHint"; did you mean "runtime_hint"?`},
+			{Code: apiv0.ErrorCodeUnexpectedProperty, Location: "/respository", Message: `unknown field "respository"; did you mean "repository"?`},
+		}, problem.Errors)
+	})
+
+	t.Run("unknown fields are dropped with a warning when allowed", func(t *testing.T) {
+		resp, record := publish(t, body, true)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.NotNil(t, record)
+		assert.Empty(t, record.Repository.URL)
+
+		var responseBody v0.ServerWithWarnings
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &responseBody))
+		assert.Equal(t, []apiv0.Warning{
+			{Code: apiv0.WarningUnknownField, Path: "packages[0].runtimeHint", Message: `ignored unknown field "runtimeHint"; did you mean "runtime_hint"?`},
+			{Code: apiv0.WarningUnknownField, Path: "respository", Message: `ignored unknown field "respository"; did you mean "repository"?`},
+		}, responseBody.Warnings)
+	})
+
+	t.Run("known fields are accepted", func(t *testing.T) {
+		resp, record := publish(t, `{"name": "io.github.example/typos", "description": "A server without typos", "version": "1.0.0"}`, false)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.NotNil(t, record)
+	})
+}
@@ -2,24 +2,21 @@ package v0
 
 import (
 	"context"
-	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
-	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
 // EditServerInput represents the input for editing a server
 type EditServerInput struct {
-	Authorization string           `header:"Authorization" doc:"Registry JWT token with edit permissions" required:"true"`
-	ID            string           `path:"id" doc:"Server ID (UUID)" format:"uuid"`
-	Body          apiv0.ServerJSON `body:""`
+	ID   string           `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+	Body apiv0.ServerJSON `body:""`
 }
 
 // RegisterEditEndpoints registers the edit endpoint
@@ -27,42 +24,22 @@ func RegisterEditEndpoints(api huma.API, registry service.RegistryService, cfg *
 	jwtManager := auth.NewJWTManager(cfg)
 
 	// Edit server endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
 		OperationID: "edit-server",
 		Method:      http.MethodPut,
 		Path:        "/v0/servers/{id}",
 		Summary:     "Edit MCP server",
 		Description: "Update an existing MCP server (admin only)",
 		Tags:        []string{"admin"},
-		Security: []map[string][]string{
-			{"bearer": {}},
-		},
-	}, func(ctx context.Context, input *EditServerInput) (*Response[apiv0.ServerJSON], error) {
-		// Extract bearer token
-		const bearerPrefix = "Bearer "
-		authHeader := input.Authorization
-		if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
-			return nil, huma.Error401Unauthorized("Invalid Authorization header format. Expected 'Bearer <token>'")
-		}
-		token := authHeader[len(bearerPrefix):]
-
-		// Validate Registry JWT token
-		claims, err := jwtManager.ValidateToken(ctx, token)
-		if err != nil {
-			return nil, huma.Error401Unauthorized("Invalid or expired Registry JWT token", err)
-		}
-
+	}, Permission{Action: auth.PermissionActionEdit}), func(ctx context.Context, input *EditServerInput) (*Response[ServerWithWarnings], error) {
 		// Get current server to check permissions against existing name
-		currentServer, err := registry.GetByID(input.ID)
+		currentServer, err := registry.GetByID(ctx, input.ID)
 		if err != nil {
-			if errors.Is(err, database.ErrNotFound) {
-				return nil, huma.Error404NotFound("Server not found")
-			}
-			return nil, huma.Error500InternalServerError("Failed to get current server", err)
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get current server")
 		}
 
 		// Verify edit permissions for this server using the existing server name
-		if !jwtManager.HasPermission(currentServer.Name, auth.PermissionActionEdit, claims.Permissions) {
+		if !jwtManager.HasPermission(currentServer.Name, auth.PermissionActionEdit, ClaimsFromContext(ctx).Permissions) {
 			return nil, huma.Error403Forbidden("You do not have edit permissions for this server")
 		}
 
@@ -76,17 +53,20 @@ func RegisterEditEndpoints(api huma.API, registry service.RegistryService, cfg *
 			return nil, huma.Error400BadRequest("Cannot change status of deleted server. Deleted servers cannot be undeleted.")
 		}
 
+		// A rejected version must not become visible, nor count towards its namespace being approved
+		if currentServer.Status == model.StatusRejected {
+			return nil, huma.Error400BadRequest("Cannot edit rejected server. Publish a new version for review instead.")
+		}
+
 		// Edit the server
-		updatedServer, err := registry.EditServer(input.ID, input.Body)
+		ctx = validators.WithWarnings(ctx)
+		updatedServer, err := registry.EditServer(ctx, input.ID, input.Body)
 		if err != nil {
-			if errors.Is(err, database.ErrNotFound) {
-				return nil, huma.Error404NotFound("Server not found")
-			}
-			return nil, huma.Error400BadRequest("Failed to edit server", err)
+			return nil, serviceError(err, "Server", http.StatusBadRequest, "Failed to edit server")
 		}
 
-		return &Response[apiv0.ServerJSON]{
-			Body: *updatedServer,
+		return &Response[ServerWithWarnings]{
+			Body: withWarnings(ctx, updatedServer),
 		}, nil
 	})
 }
@@ -0,0 +1,205 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// editMutation changes one part of a server.json document sent to the edit endpoint
+type editMutation struct {
+	name    string
+	allowed bool // whether an edit may make the change
+	apply   func(doc map[string]any, n int)
+}
+
+// officialMeta returns the registry metadata of doc, adding a complete block if the document has
+// none, so smuggled metadata passes schema validation and reaches the edit
+func officialMeta(doc map[string]any) map[string]any {
+	meta, _ := doc["_meta"].(map[string]any)
+	if meta == nil {
+		meta = map[string]any{}
+		doc["_meta"] = meta
+	}
+	official, _ := meta[apiv0.OfficialMetaKey].(map[string]any)
+	if official == nil {
+		official = map[string]any{"id": "", "published_at": "2025-01-01T00:00:00Z", "is_latest": true}
+		meta[apiv0.OfficialMetaKey] = official
+	}
+	return official
+}
+
+var editMutations = []editMutation{
+	{"description", true, func(doc map[string]any, n int) { doc["description"] = fmt.Sprintf("Edited description %d", n) }},
+	{"status", true, func(doc map[string]any, n int) {
+		doc["status"] = []string{string(model.StatusActive), string(model.StatusDeprecated)}[n%2]
+	}},
+	{"title", true, func(doc map[string]any, n int) { doc["title"] = fmt.Sprintf("Weather %d", n) }},
+	{"icons", true, func(doc map[string]any, n int) {
+		doc["icons"] = []any{map[string]any{"src": fmt.Sprintf("https://example.com/icon-%d.png", n), "mimeType": "image/png"}}
+	}},
+	{"categories", true, func(doc map[string]any, n int) { doc["categories"] = []any{fmt.Sprintf("category-%d", n)} }},
+	{"documentationUrl", true, func(doc map[string]any, n int) {
+		doc["documentationUrl"] = fmt.Sprintf("https://example.com/docs/%d", n)
+	}},
+	{"websiteUrl", true, func(doc map[string]any, n int) { doc["websiteUrl"] = fmt.Sprintf("https://example.com/%d", n) }},
+	{"readme", true, func(doc map[string]any, n int) { doc["readme"] = fmt.Sprintf("# Weather\n\nRevision %d", n) }},
+	{"releaseNotes", true, func(doc map[string]any, n int) { doc["releaseNotes"] = fmt.Sprintf("Fixes %d", n) }},
+	{"repository", true, func(doc map[string]any, n int) {
+		doc["repository"] = map[string]any{"url": fmt.Sprintf("https://github.com/example/weather-%d", n), "source": "github"}
+	}},
+	{"publisher-provided _meta", true, func(doc map[string]any, n int) {
+		meta, _ := doc["_meta"].(map[string]any)
+		if meta == nil {
+			meta = map[string]any{}
+			doc["_meta"] = meta
+		}
+		meta[apiv0.PublisherProvidedMetaKey] = map[string]any{"build": n}
+	}},
+
+	{"name", false, func(doc map[string]any, n int) { doc["name"] = fmt.Sprintf("io.github.example/other-%d", n) }},
+	{"version", false, func(doc map[string]any, n int) { doc["version"] = fmt.Sprintf("9.%d.0", n) }},
+	{"$schema", false, func(doc map[string]any, _ int) { doc["$schema"] = "https://example.com/other.schema.json" }},
+	{"license", false, func(doc map[string]any, _ int) { doc["license"] = "Apache-2.0" }},
+	{"contact", false, func(doc map[string]any, n int) { doc["contact"] = fmt.Sprintf("someone-%d@example.com", n) }},
+	{"packages", false, func(doc map[string]any, n int) {
+		doc["packages"] = []any{map[string]any{
+			"registry_type": "npm", "identifier": fmt.Sprintf("@attacker/weather-%d", n), "version": "1.0.0",
+			"transport": map[string]any{"type": "stdio"},
+		}}
+	}},
+	{"remotes", false, func(doc map[string]any, n int) {
+		doc["remotes"] = []any{map[string]any{"type": "streamable-http", "url": fmt.Sprintf("https://attacker.example.com/%d", n)}}
+	}},
+	{"removed packages", false, func(doc map[string]any, _ int) { delete(doc, "packages") }},
+	{"is_latest", false, func(doc map[string]any, _ int) { officialMeta(doc)["is_latest"] = false }},
+	{"published_at", false, func(doc map[string]any, _ int) { officialMeta(doc)["published_at"] = "2020-01-01T00:00:00Z" }},
+	{"registry id", false, func(doc map[string]any, _ int) {
+		officialMeta(doc)["id"] = "00000000-0000-0000-0000-000000000000"
+	}},
+	{"pinned", false, func(doc map[string]any, _ int) { officialMeta(doc)["pinned"] = true }},
+}
+
+// editableKeys are the top-level server.json fields an edit may change
+var editableKeys = map[string]bool{
+	"description": true, "status": true, "title": true, "icons": true, "categories": true,
+	"documentationUrl": true, "websiteUrl": true, "readme": true, "releaseNotes": true, "repository": true,
+}
+
+func TestEditServerEndpoint_OnlyAllowlistedFieldsChange(t *testing.T) {
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+	published, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+		Schema:      "https://static.modelcontextprotocol.io/schemas/2025-09-29/server.schema.json",
+		Name:        "io.github.example/weather",
+		Description: "Weather lookups",
+		Version:     "1.0.0",
+		License:     "MIT",
+		Contact:     "weather@example.com",
+		Repository:  model.Repository{URL: "https://github.com/example/weather", Source: "github"},
+		Packages: []model.Package{{
+			RegistryType: model.RegistryTypeNPM,
+			Identifier:   "@example/weather",
+			Version:      "1.0.0",
+			Transport:    model.Transport{Type: model.TransportTypeStdio},
+		}},
+		Remotes: []model.Transport{{Type: "streamable-http", URL: "https://example.github.io/weather"}},
+	})
+	require.NoError(t, err)
+	id := published.Meta.Official.ID
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterEditEndpoints(api, registryService, cfg)
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: "admin",
+		Permissions:       []auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "*"}},
+	})
+	require.NoError(t, err)
+
+	// stored returns the stored version as a JSON document
+	stored := func() map[string]any {
+		t.Helper()
+		server, err := registryService.GetByID(context.Background(), id)
+		require.NoError(t, err)
+		encoded, err := json.Marshal(server)
+		require.NoError(t, err)
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(encoded, &doc))
+		return doc
+	}
+	// registryMetadata returns the stored registry metadata an edit must not change
+	registryMetadata := func(doc map[string]any) map[string]any {
+		official := officialMeta(doc)
+		kept := map[string]any{}
+		for key, value := range official {
+			if key != "updated_at" && key != "has_readme" {
+				kept[key] = value
+			}
+		}
+		return kept
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for n := range 300 {
+		before := stored()
+
+		// Start from the stored version as clients send it back, without registry metadata
+		doc := stored()
+		delete(doc["_meta"].(map[string]any), apiv0.OfficialMetaKey)
+
+		var applied []string
+		allowed := true
+		for range 1 + rng.IntN(3) {
+			mutation := editMutations[rng.IntN(len(editMutations))]
+			mutation.apply(doc, n)
+			applied = append(applied, mutation.name)
+			allowed = allowed && mutation.allowed
+		}
+
+		body, err := json.Marshal(doc)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/v0/servers/"+id, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		after := stored()
+		if !allowed {
+			require.Equal(t, http.StatusBadRequest, w.Code, "mutations %v: %s", applied, w.Body.String())
+			require.Equal(t, before, after, "rejected edit with mutations %v changed the stored version", applied)
+			continue
+		}
+
+		require.Equal(t, http.StatusOK, w.Code, "mutations %v: %s", applied, w.Body.String())
+		for _, doc := range []map[string]any{before, after} {
+			for key := range doc {
+				if editableKeys[key] || key == "_meta" {
+					continue
+				}
+				assert.Equal(t, before[key], after[key], "mutations %v changed %s", applied, key)
+			}
+		}
+		assert.Equal(t, registryMetadata(before), registryMetadata(after), "mutations %v changed registry metadata", applied)
+	}
+}
@@ -2,6 +2,7 @@ package v0_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -32,11 +33,11 @@ func TestEditServerEndpoint(t *testing.T) {
 		Repository: model.Repository{
 			URL:    "https://github.com/domdomegg/test-server",
 			Source: "github",
-			ID:     "domdomegg/test-server",
+			ID:     "100000014",
 		},
 		Version: "1.0.0",
 	}
-	published, err := registryService.Publish(testServer)
+	published, err := registryService.Publish(context.Background(), testServer)
 	assert.NoError(t, err)
 	assert.NotNil(t, published)
 	assert.NotNil(t, published.Meta)
@@ -52,11 +53,11 @@ func TestEditServerEndpoint(t *testing.T) {
 		Repository: model.Repository{
 			URL:    "https://github.com/other/test-server",
 			Source: "github",
-			ID:     "other/test-server",
+			ID:     "100000015",
 		},
 		Version: "1.0.0",
 	}
-	otherPublished, err := registryService.Publish(otherServer)
+	otherPublished, err := registryService.Publish(context.Background(), otherServer)
 	assert.NoError(t, err)
 	assert.NotNil(t, otherPublished)
 	assert.NotNil(t, otherPublished.Meta)
@@ -72,11 +73,11 @@ func TestEditServerEndpoint(t *testing.T) {
 		Repository: model.Repository{
 			URL:    "https://github.com/domdomegg/deleted-server",
 			Source: "github",
-			ID:     "domdomegg/deleted-server",
+			ID:     "100000016",
 		},
 		Version: "1.0.0",
 	}
-	deletedPublished, err := registryService.Publish(deletedServer)
+	deletedPublished, err := registryService.Publish(context.Background(), deletedServer)
 	assert.NoError(t, err)
 	assert.NotNil(t, deletedPublished)
 	assert.NotNil(t, deletedPublished.Meta)
@@ -112,20 +113,47 @@ func TestEditServerEndpoint(t *testing.T) {
 				Repository: model.Repository{
 					URL:    "https://github.com/domdomegg/test-server",
 					Source: "github",
-					ID:     "domdomegg/test-server",
+					ID:     "100000014",
 				},
-				Version: "1.0.1",
+				Version: "1.0.0",
 			},
 			serverID:       testServerID,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "cannot change the version",
+			authHeader: func() string {
+				cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+				token, _ := generateTestJWTToken(cfg, auth.JWTClaims{
+					AuthMethod:        auth.MethodGitHubAT,
+					AuthMethodSubject: "domdomegg",
+					Permissions: []auth.Permission{
+						{Action: auth.PermissionActionEdit, ResourcePattern: "io.github.domdomegg/*"},
+					},
+				})
+				return "Bearer " + token
+			}(),
+			requestBody: apiv0.ServerJSON{
+				Name:        "io.github.domdomegg/test-server",
+				Description: "Updated test server",
+				Repository: model.Repository{
+					URL:    "https://github.com/domdomegg/test-server",
+					Source: "github",
+					ID:     "100000014",
+				},
+				Version: "2.0.0",
+			},
+			serverID:       testServerID,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "immutable_field",
+		},
 		{
 			name:           "missing authorization header",
 			authHeader:     "",
 			requestBody:    apiv0.ServerJSON{},
 			serverID:       testServerID,
-			expectedStatus: 422,
-			expectedError:  "required header parameter is missing",
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Missing Authorization header",
 		},
 		{
 			name:       "invalid authorization header format",
@@ -259,7 +287,7 @@ func TestEditServerEndpoint(t *testing.T) {
 				Repository: model.Repository{
 					URL:    "https://github.com/domdomegg/deleted-server",
 					Source: "github",
-					ID:     "domdomegg/deleted-server",
+					ID:     "100000016",
 				},
 				Version: "1.0.1",
 			},
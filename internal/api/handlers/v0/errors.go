@@ -0,0 +1,183 @@
+package v0
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/validation"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// transientRetryAfter is the Retry-After, in seconds, sent with transient storage failures
+const transientRetryAfter = "2"
+
+// CodedError is a problem details response with a machine-readable code, for failures that
+// clients handle programmatically
+type CodedError struct {
+	huma.ErrorModel
+	Code string `json:"code" doc:"Machine-readable error code" example:"TRANSIENT_STORAGE"`
+
+	headers http.Header
+}
+
+// GetHeaders returns the headers sent with the error response
+func (e *CodedError) GetHeaders() http.Header {
+	return e.headers
+}
+
+// ValidationError is the response to requests rejected as invalid. Schema validation failures,
+// converted by TransformValidationErrors, and the failures of the registry's own checks of a
+// server.json are reported alike: each problem with a code, the JSON pointer to its field and a
+// message.
+type ValidationError struct {
+	apiv0.ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	return e.Detail
+}
+
+// GetStatus returns the HTTP status of the response
+func (e *ValidationError) GetStatus() int {
+	return e.Status
+}
+
+// ContentType sends the response as problem details, like huma's own errors
+func (e *ValidationError) ContentType(ct string) string {
+	if ct == "application/json" {
+		return "application/problem+json"
+	}
+	return ct
+}
+
+// TransformValidationErrors is a huma transformer rewriting the 400 and 422 responses huma sends
+// for requests its schema rejects into ValidationErrors: huma's locations, as in
+// body.packages[0].identifier, become JSON pointers, as in /packages/0/identifier, and each
+// problem gets a code. Missing required properties are located at the property rather than at
+// the object lacking it.
+func TransformValidationErrors(_ huma.Context, status string, v any) (any, error) {
+	model, ok := v.(*huma.ErrorModel)
+	if !ok || len(model.Errors) == 0 || (status != strconv.Itoa(http.StatusBadRequest) && status != strconv.Itoa(http.StatusUnprocessableEntity)) {
+		return v, nil
+	}
+
+	details := make([]apiv0.ErrorDetail, 0, len(model.Errors))
+	for _, detail := range model.Errors {
+		if detail == nil {
+			continue
+		}
+		converted := apiv0.ErrorDetail{
+			Code:     apiv0.ErrorCodeInvalidValue,
+			Location: bodyPointer(detail.Location),
+			Message:  detail.Message,
+			Value:    detail.Value,
+		}
+		switch {
+		case strings.HasPrefix(detail.Message, "expected required property "):
+			converted.Code = apiv0.ErrorCodeRequired
+			name := strings.TrimSuffix(strings.TrimPrefix(detail.Message, "expected required property "), " to be present")
+			converted.Location += validators.JSONPointer(name)
+			converted.Value = nil
+		case detail.Message == validation.MsgUnexpectedProperty:
+			converted.Code = apiv0.ErrorCodeUnexpectedProperty
+		case detail.Message == validation.MsgExpectedBoolean, detail.Message == validation.MsgExpectedNumber,
+			detail.Message == validation.MsgExpectedInteger, detail.Message == validation.MsgExpectedString,
+			detail.Message == validation.MsgExpectedArray, detail.Message == validation.MsgExpectedObject:
+			converted.Code = apiv0.ErrorCodeInvalidType
+		}
+		details = append(details, converted)
+	}
+	return &ValidationError{apiv0.ValidationError{
+		Title:  model.Title,
+		Status: model.Status,
+		Detail: model.Detail,
+		Errors: details,
+	}}, nil
+}
+
+// bodyPointer converts a huma location in the request body, as in body.packages[0].identifier,
+// to a JSON pointer, as in /packages/0/identifier. Locations of parameters, as in query.limit,
+// are returned unchanged.
+func bodyPointer(location string) string {
+	if location != "body" && !strings.HasPrefix(location, "body.") && !strings.HasPrefix(location, "body[") {
+		return location
+	}
+	var path []any
+	for _, segment := range strings.Split(strings.TrimPrefix(location, "body"), ".") {
+		for segment != "" {
+			name, index, found := strings.Cut(segment, "[")
+			if name != "" {
+				path = append(path, name)
+			}
+			if !found {
+				break
+			}
+			index, segment, _ = strings.Cut(index, "]")
+			path = append(path, index)
+		}
+	}
+	return validators.JSONPointer(path...)
+}
+
+// NewFieldValidationError is the 400 ValidationError reporting server.json fields that failed
+// validation, one problem for each FieldError in errs
+func NewFieldValidationError(message string, errs ...*validators.FieldError) *ValidationError {
+	details := make([]apiv0.ErrorDetail, 0, len(errs))
+	for _, fieldErr := range errs {
+		details = append(details, apiv0.ErrorDetail{Code: fieldErr.Code, Location: fieldErr.Field, Message: fieldErr.Error()})
+	}
+	return &ValidationError{apiv0.ValidationError{
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: message,
+		Errors: details,
+	}}
+}
+
+// serviceError translates an error from the registry service into an HTTP error. Conditions the
+// database package classifies get the same status from every endpoint: 404 for ErrNotFound, 409
+// for ErrAlreadyExists and duplicate versions, 400 for ErrInvalidCursor, ErrInvalidInput and the
+// version limit, 400 ValidationErrors for server.json fields failing validation, 503 with
+// Retry-After for ErrTransient and 503 for ErrQueryTimeout. what names the resource for the 404,
+// as in "Server not found"; any other error gets fallbackStatus and message.
+func serviceError(err error, what string, fallbackStatus int, message string) huma.StatusError {
+	var fieldErr *validators.FieldError
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return huma.Error404NotFound(what + " not found")
+	case errors.Is(err, database.ErrAlreadyExists), errors.Is(err, database.ErrInvalidVersion):
+		return huma.Error409Conflict(message, err)
+	case errors.Is(err, database.ErrInvalidCursor):
+		return huma.Error400BadRequest("Invalid cursor parameter")
+	case errors.Is(err, database.ErrInvalidInput), errors.Is(err, database.ErrMaxServersReached):
+		return huma.Error400BadRequest(message, err)
+	case errors.As(err, &fieldErr):
+		return NewFieldValidationError(message, fieldErr)
+	case errors.Is(err, database.ErrTransient):
+		return &CodedError{
+			ErrorModel: huma.ErrorModel{
+				Title:  http.StatusText(http.StatusServiceUnavailable),
+				Status: http.StatusServiceUnavailable,
+				Detail: message + ": the registry's database is temporarily unavailable, retry the request",
+			},
+			Code:    apiv0.ErrorCodeTransientStorage,
+			headers: http.Header{"Retry-After": {transientRetryAfter}},
+		}
+	case errors.Is(err, database.ErrQueryTimeout):
+		return &CodedError{
+			ErrorModel: huma.ErrorModel{
+				Title:  http.StatusText(http.StatusServiceUnavailable),
+				Status: http.StatusServiceUnavailable,
+				Detail: message + ": the query took too long, narrow the search or filters",
+			},
+			Code: apiv0.ErrorCodeQueryTimeout,
+		}
+	default:
+		return huma.NewError(fallbackStatus, message, err)
+	}
+}
@@ -0,0 +1,295 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// failingRegistry fails the operation under test with err. GetByID finds the server unless
+// getErr is set, so endpoints that look a server up before changing it reach the change.
+type failingRegistry struct {
+	service.RegistryService
+	server apiv0.ServerJSON
+	getErr error
+	err    error
+}
+
+func (r *failingRegistry) GetByID(context.Context, string) (*apiv0.ServerJSON, error) {
+	if r.getErr != nil {
+		return nil, r.getErr
+	}
+	return &r.server, nil
+}
+
+func (r *failingRegistry) List(context.Context, *database.ServerFilter, string, int) ([]apiv0.ServerJSON, string, error) {
+	return nil, "", r.err
+}
+
+func (r *failingRegistry) Publish(context.Context, apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	return nil, r.err
+}
+
+func (r *failingRegistry) EditServer(context.Context, string, apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	return nil, r.err
+}
+
+func (r *failingRegistry) SetPinned(context.Context, string, bool) (*apiv0.ServerJSON, error) {
+	return nil, r.err
+}
+
+func (r *failingRegistry) ApprovePending(context.Context, string) (*apiv0.ServerJSON, error) {
+	return nil, r.err
+}
+
+func (r *failingRegistry) RejectPending(context.Context, string, string) (*apiv0.ServerJSON, error) {
+	return nil, r.err
+}
+
+func (r *failingRegistry) RepairText(context.Context, bool) ([]service.TextRepair, error) {
+	return nil, r.err
+}
+
+func (r *failingRegistry) Unsubscribe(context.Context, string, string) error {
+	return r.err
+}
+
+func (r *failingRegistry) CheckNamespaceReservation(context.Context, string, service.Publisher) error {
+	return nil
+}
+
+func (r *failingRegistry) ListNamespaceReservations(context.Context) ([]*database.NamespaceReservation, error) {
+	return nil, r.err
+}
+
+func (r *failingRegistry) ReserveNamespace(context.Context, string, []string, string, string) (*database.NamespaceReservation, error) {
+	return nil, r.err
+}
+
+func (r *failingRegistry) UnreserveNamespace(context.Context, string) error {
+	return r.err
+}
+
+func (r *failingRegistry) NamespaceOwnership(context.Context, string) (*database.NamespaceVerification, error) {
+	return nil, r.err
+}
+
+func TestServiceErrorStatuses(t *testing.T) {
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+			{Action: auth.PermissionActionEdit, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(t, err)
+
+	const id = "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90"
+	server := apiv0.ServerJSON{Name: "com.example/server", Description: "A test server", Version: "1.0.0"}
+	body, err := json.Marshal(server)
+	require.NoError(t, err)
+
+	// Every classified error gets the same status from every endpoint; others get the endpoint's fallback
+	classes := []struct {
+		name string
+		err  error
+		want int // 0 means the endpoint's fallback status
+	}{
+		{"not found", database.ErrNotFound, http.StatusNotFound},
+		{"already exists", fmt.Errorf("failed to create server: %w", database.ErrAlreadyExists), http.StatusConflict},
+		{"duplicate version", database.ErrInvalidVersion, http.StatusConflict},
+		{"invalid cursor", database.ErrInvalidCursor, http.StatusBadRequest},
+		{"invalid input", fmt.Errorf("%w: bad field", database.ErrInvalidInput), http.StatusBadRequest},
+		{"version limit", database.ErrMaxServersReached, http.StatusBadRequest},
+		{"transient", fmt.Errorf("failed to insert server: %w: connection reset", database.ErrTransient), http.StatusServiceUnavailable},
+		{"query timeout", fmt.Errorf("failed to query servers: %w", database.ErrQueryTimeout), http.StatusServiceUnavailable},
+		{"unclassified", errors.New("connection reset"), 0},
+	}
+	endpoints := []struct {
+		name      string
+		method    string
+		path      string
+		body      []byte
+		lookup    bool // the error comes from looking the server up
+		fallback  int
+		overrides map[string]int
+	}{
+		{name: "list servers", method: http.MethodGet, path: "/v0/servers", fallback: http.StatusInternalServerError},
+		{name: "get server", method: http.MethodGet, path: "/v0/servers/" + id, lookup: true, fallback: http.StatusInternalServerError},
+		{name: "get readme", method: http.MethodGet, path: "/v0/servers/" + id + "/readme", lookup: true, fallback: http.StatusInternalServerError},
+		{name: "get server.json", method: http.MethodGet, path: "/v0/servers/" + id + "/server.json", lookup: true, fallback: http.StatusInternalServerError},
+		{name: "get config schema", method: http.MethodGet, path: "/v0/servers/" + id + "/config-schema", lookup: true, fallback: http.StatusInternalServerError},
+		{name: "publish", method: http.MethodPost, path: "/v0/publish", body: body, fallback: http.StatusBadRequest},
+		{name: "edit lookup", method: http.MethodPut, path: "/v0/servers/" + id, body: body, lookup: true, fallback: http.StatusInternalServerError},
+		{name: "edit", method: http.MethodPut, path: "/v0/servers/" + id, body: body, fallback: http.StatusBadRequest},
+		{name: "pin", method: http.MethodPut, path: "/v0/servers/" + id + "/pin", body: []byte(`{"pinned": true}`), fallback: http.StatusInternalServerError},
+		{name: "list pending", method: http.MethodGet, path: "/v0/admin/pending", fallback: http.StatusInternalServerError},
+		{
+			name: "approve", method: http.MethodPost, path: "/v0/admin/servers/" + id + "/approve", fallback: http.StatusInternalServerError,
+			overrides: map[string]int{"invalid input": http.StatusConflict}, // the version isn't pending
+		},
+		{
+			name: "reject", method: http.MethodPost, path: "/v0/admin/servers/" + id + "/reject", body: []byte(`{"reason": "Spam"}`), fallback: http.StatusInternalServerError,
+			overrides: map[string]int{"invalid input": http.StatusConflict},
+		},
+		{name: "repair text", method: http.MethodPost, path: "/v0/admin/repair-text?dry_run=true", fallback: http.StatusInternalServerError},
+		{name: "list reservations", method: http.MethodGet, path: "/v0/admin/namespace-reservations", fallback: http.StatusInternalServerError},
+		{name: "reserve namespace", method: http.MethodPut, path: "/v0/admin/namespace-reservations/com.example", body: []byte(`{}`), fallback: http.StatusInternalServerError},
+		{name: "unreserve namespace", method: http.MethodDelete, path: "/v0/admin/namespace-reservations/com.example", fallback: http.StatusInternalServerError},
+		{
+			name: "namespace ownership", method: http.MethodGet, path: "/v0/namespaces/com.example/ownership", fallback: http.StatusInternalServerError,
+			overrides: map[string]int{"not found": http.StatusOK}, // an unverified namespace
+		},
+		{name: "unsubscribe", method: http.MethodGet, path: "/v0/notifications/" + id + "/unsubscribe?token=x", fallback: http.StatusInternalServerError},
+	}
+
+	for _, endpoint := range endpoints {
+		for _, class := range classes {
+			t.Run(endpoint.name+"/"+class.name, func(t *testing.T) {
+				registry := &failingRegistry{server: server}
+				if endpoint.lookup {
+					registry.getErr = class.err
+				} else {
+					registry.err = class.err
+				}
+
+				mux := http.NewServeMux()
+				api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+				v0.RegisterServersEndpoints(api, registry)
+				v0.RegisterPublishEndpoint(api, registry, cfg)
+				v0.RegisterEditEndpoints(api, registry, cfg)
+				v0.RegisterRetentionEndpoints(api, registry, cfg)
+				v0.RegisterPendingEndpoints(api, registry, cfg)
+				v0.RegisterRepairEndpoints(api, registry, cfg)
+				v0.RegisterNotificationEndpoints(api, registry, cfg)
+				v0.RegisterReservationEndpoints(api, registry, cfg)
+				v0.RegisterOwnershipEndpoint(api, registry, cfg)
+
+				req := httptest.NewRequest(endpoint.method, endpoint.path, bytes.NewReader(endpoint.body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+token)
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, req)
+
+				want := class.want
+				if override, ok := endpoint.overrides[class.name]; ok {
+					want = override
+				} else if want == 0 {
+					want = endpoint.fallback
+				}
+				assert.Equal(t, want, w.Code, w.Body.String())
+
+				// Transient failures tell clients when and that they can retry; timed out queries
+				// are told apart from them
+				if want == http.StatusServiceUnavailable {
+					var problem map[string]any
+					require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+					if class.name == "query timeout" {
+						assert.Empty(t, w.Header().Get("Retry-After"))
+						assert.Equal(t, apiv0.ErrorCodeQueryTimeout, problem["code"])
+					} else {
+						assert.Equal(t, "2", w.Header().Get("Retry-After"))
+						assert.Equal(t, apiv0.ErrorCodeTransientStorage, problem["code"])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.JWTPrivateKey = "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"
+	cfg.EnableRegistryValidation = false
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod:  auth.MethodNone,
+		Permissions: []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "*"}},
+	})
+	require.NoError(t, err)
+
+	humaConfig := huma.DefaultConfig("Test API", "1.0.0")
+	humaConfig.Transformers = append(humaConfig.Transformers, v0.TransformValidationErrors)
+	mux := http.NewServeMux()
+	api := humago.New(mux, humaConfig)
+	v0.RegisterPublishEndpoint(api, service.NewRegistryService(database.NewMemoryDB(), cfg), cfg)
+
+	publish := func(body string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/v0/publish", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		var problem map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem), w.Body.String())
+		return w.Code, problem
+	}
+	// structure lists the keys of a problem and of each of its errors
+	structure := func(problem map[string]any) []string {
+		keys := []string{}
+		for key := range problem {
+			keys = append(keys, key)
+		}
+		for _, detail := range problem["errors"].([]any) {
+			for key := range detail.(map[string]any) {
+				keys = append(keys, "errors."+key)
+			}
+		}
+		slices.Sort(keys)
+		return slices.Compact(keys)
+	}
+	errorsOf := func(problem map[string]any) []apiv0.ErrorDetail {
+		t.Helper()
+		encoded, err := json.Marshal(problem["errors"])
+		require.NoError(t, err)
+		var details []apiv0.ErrorDetail
+		require.NoError(t, json.Unmarshal(encoded, &details))
+		return details
+	}
+
+	// The schema rejects a missing description
+	schemaStatus, schemaProblem := publish(`{"name": "com.example/server", "version": "1.0.0"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, schemaStatus)
+	assert.Equal(t, []apiv0.ErrorDetail{{
+		Code: apiv0.ErrorCodeRequired, Location: "/description", Message: "expected required property description to be present",
+	}}, errorsOf(schemaProblem))
+
+	// The registry's own checks reject a blank title
+	checkStatus, checkProblem := publish(`{"name": "com.example/server", "description": "A server", "version": "1.0.0", "title": "   "}`)
+	assert.Equal(t, http.StatusBadRequest, checkStatus)
+	assert.Equal(t, []apiv0.ErrorDetail{{
+		Code: "invalid_title", Location: "/title", Message: "invalid title: title cannot be blank",
+	}}, errorsOf(checkProblem))
+
+	assert.Equal(t, structure(schemaProblem), structure(checkProblem))
+
+	// Nested fields are located the same way by both
+	_, schemaProblem = publish(`{"name": "com.example/server", "description": "A server", "version": "1.0.0",
+		"icons": [{"src": "https://example.com/icon.png", "mimeType": "image/png", "sizes": [48]}]}`)
+	assert.Contains(t, errorsOf(schemaProblem), apiv0.ErrorDetail{
+		Code: apiv0.ErrorCodeInvalidType, Location: "/icons/0/sizes/0", Message: "expected string", Value: float64(48),
+	})
+	_, checkProblem = publish(`{"name": "com.example/server", "description": "A server", "version": "1.0.0",
+		"icons": [{"src": "https://example.com/icon.png", "mimeType": "image/png", "sizes": ["huge"]}]}`)
+	assert.Equal(t, "/icons/0/sizes/0", errorsOf(checkProblem)[0].Location)
+}
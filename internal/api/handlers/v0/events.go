@@ -0,0 +1,242 @@
+package v0
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// EventsPath streams the change feed; requests to it stay open, so they are exempt from the request timeout
+const EventsPath = "/v0/events"
+
+// eventWriteTimeout bounds each write to an event stream, so a client that stops reading is dropped
+const eventWriteTimeout = 10 * time.Second
+
+// EventsInput represents the input for the change feed stream
+type EventsInput struct {
+	LastEventID string `header:"Last-Event-ID" doc:"ID of the last event received, to resume after it; sent by EventSource clients when they reconnect" required:"false" example:"42"`
+
+	remoteAddr   string
+	forwardedFor string
+}
+
+// Resolve keeps the addresses the client is identified by for the connection limit
+func (i *EventsInput) Resolve(ctx huma.Context) []error {
+	i.remoteAddr = ctx.RemoteAddr()
+	i.forwardedFor = ctx.Header("X-Forwarded-For")
+	return nil
+}
+
+// ChangeFeedReset is sent when events after the client's Last-Event-ID are no longer buffered
+type ChangeFeedReset struct {
+	LastEventID uint64 `json:"last_event_id" doc:"ID of the newest event when the stream started; events up to it were missed"`
+}
+
+// RegisterEventsEndpoint registers the public change feed, streamed as server-sent events
+func RegisterEventsEndpoint(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	limiter := newStreamLimiter(cfg.EventsMaxConnections, cfg.EventsMaxConnectionsPerIP)
+
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "stream-events",
+		Method:      http.MethodGet,
+		Path:        EventsPath,
+		Summary:     "Stream registry changes",
+		Description: "Stream changes to public server versions as server-sent events, as they happen on the replica serving the stream. " +
+			"Each `change` event carries the server name, version, action (published, updated or deleted) and timestamp, with its position as the event ID. " +
+			"Clients reconnecting with Last-Event-ID get the recent events they missed first; when those are no longer buffered, or the ID comes from another replica or before a restart, " +
+			"a `reset` event tells them to refetch what they need with GET /v0/servers?updated_since. Streams are pinged with comments to keep them open, and limited per client address.",
+		Tags:   []string{"servers"},
+		Errors: []int{http.StatusNotFound, http.StatusTooManyRequests},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Server-sent events: `change` events with a ChangeEvent as data, and `reset` events with a ChangeFeedReset",
+				Content:     map[string]*huma.MediaType{"text/event-stream": {Schema: &huma.Schema{Type: huma.TypeString}}},
+			},
+		},
+	}), func(ctx context.Context, input *EventsInput) (*huma.StreamResponse, error) {
+		if cfg.EventsMaxConnections == 0 {
+			return nil, huma.Error404NotFound("The change feed is not enabled on this registry")
+		}
+
+		var after uint64
+		if input.LastEventID != "" {
+			parsed, err := strconv.ParseUint(input.LastEventID, 10, 64)
+			if err != nil {
+				return nil, huma.Error400BadRequest("Invalid Last-Event-ID: expected the ID of a change event")
+			}
+			after = parsed
+		}
+
+		ip := clientIP(input.remoteAddr, input.forwardedFor, cfg.TrustForwardedFor)
+		if !limiter.acquire(ip) {
+			return nil, &CodedError{
+				ErrorModel: huma.ErrorModel{
+					Title:  http.StatusText(http.StatusTooManyRequests),
+					Status: http.StatusTooManyRequests,
+					Detail: "Too many event streams open, from this address or in all; try again later",
+				},
+				Code:    apiv0.ErrorCodeRateLimited,
+				headers: http.Header{"Retry-After": {strconv.Itoa(int(cfg.EventsHeartbeatInterval.Seconds()))}},
+			}
+		}
+
+		sub, backlog, missed, err := registry.SubscribeChanges(ctx, after)
+		if err != nil {
+			limiter.release(ip)
+			if errors.Is(err, service.ErrChangeFeedDisabled) {
+				return nil, huma.Error404NotFound("The change feed is not enabled on this registry")
+			}
+			return nil, huma.Error503ServiceUnavailable("The change feed is closed", err)
+		}
+
+		return &huma.StreamResponse{
+			Body: func(hctx huma.Context) {
+				defer limiter.release(ip)
+				defer sub.Close()
+
+				hctx.SetHeader("Content-Type", "text/event-stream")
+				hctx.SetHeader("Cache-Control", "no-cache")
+				// Stop nginx and similar proxies from buffering the stream
+				hctx.SetHeader("X-Accel-Buffering", "no")
+				hctx.SetStatus(http.StatusOK)
+				stream := newEventStream(hctx.BodyWriter())
+
+				if missed {
+					if stream.send("reset", 0, ChangeFeedReset{LastEventID: newestID(after, backlog)}) != nil {
+						return
+					}
+				}
+				for _, event := range backlog {
+					if stream.send("change", event.ID, event) != nil {
+						return
+					}
+				}
+				if stream.ping() != nil {
+					return
+				}
+
+				heartbeat := time.NewTicker(cfg.EventsHeartbeatInterval)
+				defer heartbeat.Stop()
+				for {
+					select {
+					case event := <-sub.Events():
+						if stream.send("change", event.ID, event) != nil {
+							return
+						}
+					case <-heartbeat.C:
+						if stream.ping() != nil {
+							return
+						}
+					case <-sub.Done():
+						// Dropped for falling behind, or the registry is shutting down; the client
+						// reconnects and resumes from its Last-Event-ID
+						return
+					case <-hctx.Context().Done():
+						return
+					}
+				}
+			},
+		}, nil
+	})
+}
+
+// newestID is the ID a reset event reports: the newest event the client will not be sent
+func newestID(after uint64, backlog []service.ChangeEvent) uint64 {
+	if len(backlog) > 0 {
+		return backlog[0].ID - 1
+	}
+	return after
+}
+
+// eventStream writes server-sent events, flushing each one to the client
+type eventStream struct {
+	w          io.Writer
+	controller *http.ResponseController
+}
+
+func newEventStream(w io.Writer) *eventStream {
+	stream := &eventStream{w: w}
+	if rw, ok := w.(http.ResponseWriter); ok {
+		stream.controller = http.NewResponseController(rw)
+	}
+	return stream
+}
+
+// send writes an event with data encoded as JSON, and id if it is not 0
+func (s *eventStream) send(event string, id uint64, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id > 0 {
+		return s.write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", id, event, encoded))
+	}
+	return s.write(fmt.Sprintf("event: %s\ndata: %s\n\n", event, encoded))
+}
+
+// ping writes a comment, which clients ignore, so that proxies see the stream is alive
+func (s *eventStream) ping() error {
+	return s.write(": ping\n\n")
+}
+
+func (s *eventStream) write(message string) error {
+	if s.controller != nil {
+		// Not every writer supports deadlines; the stream still works without one
+		_ = s.controller.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+	}
+	if _, err := io.WriteString(s.w, message); err != nil {
+		return err
+	}
+	if s.controller != nil {
+		return s.controller.Flush()
+	}
+	return nil
+}
+
+// streamLimiter bounds how many event streams are open at once, in all and from each client
+// address. Counts are kept in memory, so each replica limits separately.
+type streamLimiter struct {
+	limit, perClient int
+
+	mu      sync.Mutex
+	total   int
+	clients map[string]int
+}
+
+// newStreamLimiter creates a limiter; a perClient of 0 only applies the overall limit
+func newStreamLimiter(limit, perClient int) *streamLimiter {
+	return &streamLimiter{limit: limit, perClient: perClient, clients: make(map[string]int)}
+}
+
+// acquire counts a stream opened by ip, reporting whether it is within the limits
+func (l *streamLimiter) acquire(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.total >= l.limit || (l.perClient > 0 && l.clients[ip] >= l.perClient) {
+		return false
+	}
+	l.total++
+	l.clients[ip]++
+	return true
+}
+
+// release counts a stream from ip as closed
+func (l *streamLimiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.total--
+	if l.clients[ip]--; l.clients[ip] <= 0 {
+		delete(l.clients, ip)
+	}
+}
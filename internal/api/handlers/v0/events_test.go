@@ -0,0 +1,221 @@
+package v0_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// sseEvent is one server-sent event read from a stream
+type sseEvent struct {
+	id, event, data string
+}
+
+// sseClient reads the events of a GET /v0/events stream, skipping pings
+type sseClient struct {
+	resp   *http.Response
+	events chan sseEvent
+}
+
+// eventsHTTPClient returns a client with its own connections, closed when the test ends, so
+// streams never share a connection with other requests
+func eventsHTTPClient(t *testing.T) *http.Client {
+	transport := &http.Transport{}
+	t.Cleanup(transport.CloseIdleConnections)
+	return &http.Client{Transport: transport}
+}
+
+// connectEvents opens a stream as the client at address forwardedFor
+func connectEvents(t *testing.T, url, forwardedFor, lastEventID string) *sseClient {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url+"/v0/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := eventsHTTPClient(t).Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	client := &sseClient{resp: resp, events: make(chan sseEvent, 16)}
+	go func() {
+		defer close(client.events)
+		scanner := bufio.NewScanner(resp.Body)
+		var event sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if event.event != "" {
+					client.events <- event
+				}
+				event = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return client
+}
+
+func (c *sseClient) next(t *testing.T) sseEvent {
+	t.Helper()
+	select {
+	case event, ok := <-c.events:
+		require.True(t, ok, "the stream ended")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+		return sseEvent{}
+	}
+}
+
+func (c *sseClient) nextChange(t *testing.T) (string, service.ChangeEvent) {
+	t.Helper()
+	event := c.next(t)
+	require.Equal(t, "change", event.event)
+	var change service.ChangeEvent
+	require.NoError(t, json.Unmarshal([]byte(event.data), &change))
+	return event.id, change
+}
+
+func TestEventsEndpoint(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:             "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		EventsMaxConnections:      10,
+		EventsMaxConnectionsPerIP: 2,
+		EventsBufferSize:          2,
+		EventsHeartbeatInterval:   time.Hour,
+		// Each subtest connects from its own address, so streams the previous ones are still closing don't count
+		TrustForwardedFor: true,
+	}
+	feed := service.NewChangeFeed(cfg.EventsBufferSize)
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg, service.WithChangeFeed(feed))
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterEventsEndpoint(api, registryService, cfg)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	publish := func(version string) {
+		t.Helper()
+		_, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+			Name: "io.github.octocat/weather", Description: "Weather lookups", Version: version,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("publishes are streamed", func(t *testing.T) {
+		client := connectEvents(t, server.URL, "192.0.2.1", "")
+		publish("1.0.0")
+
+		id, change := client.nextChange(t)
+		assert.Equal(t, "1", id)
+		assert.Equal(t, "io.github.octocat/weather", change.Name)
+		assert.Equal(t, "1.0.0", change.Version)
+		assert.Equal(t, service.ChangePublished, change.Action)
+	})
+
+	t.Run("reconnecting resumes after the last event", func(t *testing.T) {
+		publish("1.0.1")
+		publish("1.0.2")
+
+		client := connectEvents(t, server.URL, "192.0.2.2", "1")
+		_, change := client.nextChange(t)
+		assert.Equal(t, "1.0.1", change.Version)
+		_, change = client.nextChange(t)
+		assert.Equal(t, "1.0.2", change.Version)
+
+		publish("1.0.3")
+		id, change := client.nextChange(t)
+		assert.Equal(t, "4", id)
+		assert.Equal(t, "1.0.3", change.Version)
+	})
+
+	t.Run("missed events are reported", func(t *testing.T) {
+		// Only the last two events are buffered
+		client := connectEvents(t, server.URL, "192.0.2.3", "1")
+		event := client.next(t)
+		assert.Equal(t, "reset", event.event)
+		assert.JSONEq(t, `{"last_event_id": 2}`, event.data)
+
+		_, change := client.nextChange(t)
+		assert.Equal(t, "1.0.2", change.Version)
+	})
+
+	t.Run("rejects invalid event IDs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/events", nil)
+		req.Header.Set("Last-Event-ID", "yesterday")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("limits streams per address", func(t *testing.T) {
+		connectEvents(t, server.URL, "192.0.2.4", "")
+		connectEvents(t, server.URL, "192.0.2.4", "")
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/v0/events", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Forwarded-For", "192.0.2.4")
+		resp, err := eventsHTTPClient(t).Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+
+		connectEvents(t, server.URL, "192.0.2.5", "")
+	})
+
+	t.Run("closing the feed ends streams", func(t *testing.T) {
+		client := connectEvents(t, server.URL, "192.0.2.6", "")
+		feed.Close()
+		select {
+		case _, ok := <-client.events:
+			assert.False(t, ok, "no events are sent once the feed is closed")
+		case <-time.After(5 * time.Second):
+			t.Fatal("the stream was not ended")
+		}
+
+		resp, err := eventsHTTPClient(t).Get(server.URL + "/v0/events")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+}
+
+func TestEventsEndpoint_Disabled(t *testing.T) {
+	cfg := &config.Config{}
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterEventsEndpoint(api, service.NewRegistryService(database.NewMemoryDB(), cfg), cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/v0/events", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,80 @@
+package v0
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/importer"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// exportPageSize is how many server versions are read per page while building an export
+const exportPageSize = 100
+
+// ExportOutput is a seed file of every public server version, with its detached signature
+type ExportOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	SeedSignature      string `header:"Seed-Signature" doc:"Detached signature of the export as compact JSON, to be saved next to it as <seed>.sig and passed to MCP_REGISTRY_SEED_SIGNATURE_FROM"`
+	Body               []byte
+}
+
+// RegisterExportEndpoint registers the admin endpoint exporting the registry as a signed seed file
+func RegisterExportEndpoint(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "export-servers",
+		Method:      http.MethodGet,
+		Path:        "/v0/admin/export",
+		Summary:     "Export servers as a signed seed",
+		Description: "Export every public server version as newline-delimited JSON in the seed format MCP_REGISTRY_SEED_FROM imports, signed with the seed signing key listed in GET /v0/admin/jwks (admin only)",
+		Tags:        []string{"admin"},
+	}, Permission{Action: auth.PermissionActionEdit, Resource: "*"}), func(ctx context.Context, _ *struct{}) (*ExportOutput, error) {
+		var seed bytes.Buffer
+		enc := json.NewEncoder(&seed)
+		filter := &database.ServerFilter{ExcludeHidden: true}
+		cursor := ""
+		for {
+			page, nextCursor, err := registry.List(ctx, filter, cursor, exportPageSize)
+			if err != nil {
+				return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to export servers")
+			}
+			for i := range page {
+				if err := enc.Encode(&page[i]); err != nil {
+					return nil, huma.Error500InternalServerError("Failed to export servers", err)
+				}
+			}
+			if nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+
+		if seed.Len() == 0 {
+			return nil, huma.Error404NotFound("No servers to export")
+		}
+
+		signature, err := importer.SignSeed(bytes.NewReader(seed.Bytes()), jwtManager.SeedSigningKey())
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to sign export", err)
+		}
+		header, err := json.Marshal(signature)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to sign export", err)
+		}
+
+		return &ExportOutput{
+			ContentType:        "application/x-ndjson",
+			ContentDisposition: `attachment; filename="seed.ndjson"`,
+			SeedSignature:      string(header),
+			Body:               seed.Bytes(),
+		}, nil
+	})
+}
@@ -0,0 +1,78 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/importer"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestExportEndpoint(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:  "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		SeedSigningKey: "0000000000000000000000000000000000000000000000000000000000000002",
+	}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		_, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+			Name:        "io.github.example/exported",
+			Description: "An exported server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterExportEndpoint(api, registryService, cfg)
+
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod:  auth.MethodNone,
+		Permissions: []auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "*"}},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/v0/admin/export", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(w.Body.String()), "\n"), 2)
+
+	// The signature verifies with the seed signing key as published in the JWKS
+	var signature importer.SeedSignature
+	require.NoError(t, json.Unmarshal([]byte(w.Header().Get("Seed-Signature")), &signature))
+	jwks := auth.NewJWTManager(cfg).JWKS()
+	require.Len(t, jwks.Keys, 3)
+	seedKey := jwks.Keys[1]
+	assert.Equal(t, seedKey.KeyID, signature.KeyID)
+	publicKey, err := base64.RawURLEncoding.DecodeString(seedKey.X)
+	require.NoError(t, err)
+	require.NoError(t, importer.VerifySeed(bytes.NewReader(w.Body.Bytes()), signature, []ed25519.PublicKey{publicKey}))
+
+	t.Run("requires admin permission", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/admin/export", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
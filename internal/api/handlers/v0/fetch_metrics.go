@@ -0,0 +1,86 @@
+package v0
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+const (
+	// defaultFetchMetricsDays is how many days the fetch metrics cover when no from parameter is given
+	defaultFetchMetricsDays = 30
+	// maxFetchMetricsDays is the longest window of fetch metrics served in one request
+	maxFetchMetricsDays = 90
+)
+
+// NamespaceMetricsInput represents the input for a namespace's fetch metrics
+type NamespaceMetricsInput struct {
+	Namespace string `path:"namespace" doc:"Namespace, the part of server names before the slash" pattern:"^[a-zA-Z0-9.-]+$" example:"io.github.octocat"`
+	From      string `query:"from" doc:"First day of the window in UTC (YYYY-MM-DD); defaults to 29 days before to" required:"false" example:"2025-08-01"`
+	To        string `query:"to" doc:"Last day of the window in UTC (YYYY-MM-DD); defaults to today" required:"false" example:"2025-08-30"`
+}
+
+// NamespaceMetricsBody is how often the servers under a namespace were fetched
+type NamespaceMetricsBody struct {
+	Namespace string                  `json:"namespace"`
+	From      string                  `json:"from" doc:"First day of the window in UTC" format:"date"`
+	To        string                  `json:"to" doc:"Last day of the window in UTC" format:"date"`
+	Servers   []service.ServerFetches `json:"servers" doc:"Servers fetched during the window, by name"`
+}
+
+// RegisterNamespaceMetricsEndpoint registers the publisher-facing namespace fetch metrics endpoint
+func RegisterNamespaceMetricsEndpoint(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "get-namespace-metrics",
+		Method:      http.MethodGet,
+		Path:        "/v0/namespaces/{namespace}/metrics",
+		Summary:     "Get namespace fetch metrics",
+		Description: fmt.Sprintf("How often the details of each server under a namespace were fetched, per day in UTC, over a window of at most %d days. Fetches of a version's details, README, server.json and config schema are counted; they are added in batches, so the current day lags slightly behind. Requires publish permission for every server in the namespace.", maxFetchMetricsDays),
+		Tags:        []string{"namespaces"},
+	}, Permission{Action: auth.PermissionActionPublish, Resource: "{namespace}/*"}), func(ctx context.Context, input *NamespaceMetricsInput) (*Response[NamespaceMetricsBody], error) {
+		to := time.Now().UTC().Truncate(24 * time.Hour)
+		if input.To != "" {
+			parsed, err := time.Parse(time.DateOnly, input.To)
+			if err != nil {
+				return nil, huma.Error400BadRequest("Invalid to format: expected a date (e.g., 2025-08-30)")
+			}
+			to = parsed
+		}
+		from := to.AddDate(0, 0, 1-defaultFetchMetricsDays)
+		if input.From != "" {
+			parsed, err := time.Parse(time.DateOnly, input.From)
+			if err != nil {
+				return nil, huma.Error400BadRequest("Invalid from format: expected a date (e.g., 2025-08-01)")
+			}
+			from = parsed
+		}
+		if from.After(to) {
+			return nil, huma.Error400BadRequest("from must not be after to")
+		}
+		if to.Sub(from) >= maxFetchMetricsDays*24*time.Hour {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("The window must not be longer than %d days", maxFetchMetricsDays))
+		}
+
+		servers, err := registry.NamespaceFetches(ctx, input.Namespace, from, to)
+		if err != nil {
+			return nil, serviceError(err, "Namespace", http.StatusInternalServerError, "Failed to get namespace metrics")
+		}
+
+		return &Response[NamespaceMetricsBody]{
+			Body: NamespaceMetricsBody{
+				Namespace: input.Namespace,
+				From:      from.Format(time.DateOnly),
+				To:        to.Format(time.DateOnly),
+				Servers:   servers,
+			},
+		}, nil
+	})
+}
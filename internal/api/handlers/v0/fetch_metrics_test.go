@@ -0,0 +1,140 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestNamespaceMetricsEndpoint(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	db := database.NewMemoryDB()
+	recorder := service.NewFetchRecorder(db, time.Hour, nil)
+	registryService := service.NewRegistryService(db, cfg, service.WithFetchRecorder(recorder))
+
+	publish := func(name string) string {
+		server, err := registryService.Publish(ctx, apiv0.ServerJSON{
+			Name:        name,
+			Description: "A test server",
+			Version:     "1.0.0",
+			Repository:  model.Repository{URL: "https://github.com/octocat/tools", Source: "github"},
+		})
+		require.NoError(t, err)
+		return server.Meta.Official.ID
+	}
+	weather := publish("io.github.octocat/weather")
+	docs := publish("io.github.octocat/docs")
+	other := publish("io.github.octocat-fan/weather")
+
+	metrics, err := telemetry.NewMetrics(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(router.MetricTelemetryMiddleware(metrics, router.WithFetchCounting(registryService)))
+	v0.RegisterServersEndpoints(api, registryService)
+	v0.RegisterNamespaceMetricsEndpoint(api, registryService, cfg)
+
+	serve := func(method, path, authHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// Successful GETs of a version's details count, whichever detail endpoint served them
+	for _, path := range []string{
+		"/v0/servers/" + weather,
+		"/v0/servers/" + weather + "/server.json",
+		"/v0/servers/" + docs,
+		"/v0/servers/" + other,
+	} {
+		require.Equal(t, http.StatusOK, serve(http.MethodGet, path, "").Code, path)
+	}
+	// Lists, HEAD requests and failed fetches do not
+	serve(http.MethodGet, "/v0/servers", "")
+	serve(http.MethodHead, "/v0/servers/"+weather, "")
+	serve(http.MethodGet, "/v0/servers/00000000-0000-0000-0000-000000000000", "")
+
+	stopped, stop := context.WithCancel(ctx)
+	stop()
+	recorder.Run(stopped)
+
+	tokenFor := func(pattern string) string {
+		token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "octocat",
+			Permissions:       []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: pattern}},
+		})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	get := func(query, authHeader string) *httptest.ResponseRecorder {
+		return serve(http.MethodGet, "/v0/namespaces/io.github.octocat/metrics"+query, authHeader)
+	}
+
+	t.Run("requires a token", func(t *testing.T) {
+		assert.NotEqual(t, http.StatusOK, get("", "").Code)
+	})
+
+	t.Run("rejects tokens for a single server in the namespace", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, get("", tokenFor("io.github.octocat/weather")).Code)
+	})
+
+	t.Run("rejects tokens for another namespace", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, get("", tokenFor("io.github.octocat-fan/*")).Code)
+	})
+
+	t.Run("rejects invalid windows", func(t *testing.T) {
+		token := tokenFor("io.github.octocat/*")
+		assert.Equal(t, http.StatusBadRequest, get("?from=yesterday", token).Code)
+		assert.Equal(t, http.StatusBadRequest, get("?from=2025-03-02&to=2025-03-01", token).Code)
+		assert.Equal(t, http.StatusBadRequest, get("?from=2025-01-01&to=2025-06-01", token).Code)
+	})
+
+	t.Run("counts fetches per server and day", func(t *testing.T) {
+		w := get("", tokenFor("io.github.octocat/*"))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var body v0.NamespaceMetricsBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		today := time.Now().UTC().Format(time.DateOnly)
+		assert.Equal(t, "io.github.octocat", body.Namespace)
+		assert.Equal(t, today, body.To)
+		assert.Equal(t, time.Now().UTC().AddDate(0, 0, -29).Format(time.DateOnly), body.From)
+		assert.Equal(t, []service.ServerFetches{
+			{Name: "io.github.octocat/docs", Total: 1, Days: []service.DailyFetches{{Date: today, Fetches: 1}}},
+			{Name: "io.github.octocat/weather", Total: 2, Days: []service.DailyFetches{{Date: today, Fetches: 2}}},
+		}, body.Servers)
+	})
+
+	t.Run("returns no servers for a window without fetches", func(t *testing.T) {
+		w := get("?from=2025-01-01&to=2025-01-31", tokenFor("io.github.octocat/*"))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body v0.NamespaceMetricsBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Empty(t, body.Servers)
+	})
+}
@@ -0,0 +1,42 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// FieldUsageInput represents the input for the field usage report
+type FieldUsageInput struct {
+	Refresh bool `query:"refresh" doc:"Walk the servers now instead of returning the report of the last field usage job run" required:"false"`
+}
+
+// RegisterFieldUsageEndpoints registers the admin endpoint reporting how often server.json fields are used
+func RegisterFieldUsageEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "get-field-usage",
+		Method:      http.MethodGet,
+		Path:        "/v0/admin/field-usage",
+		Summary:     "Get server.json field usage",
+		Description: "Count how many of the latest server versions set each server.json field, including those nested in packages, arguments, environment variables and remotes (admin only)",
+		Tags:        []string{"admin"},
+	}, Permission{Action: auth.PermissionActionEdit, Resource: "*"}), func(ctx context.Context, input *FieldUsageInput) (*Response[service.FieldUsage], error) {
+		// The job's last report stays fresh until shortly after its next run is due; without
+		// the job, every request walks the servers
+		maxAge := 2 * cfg.FieldUsageInterval
+		if input.Refresh {
+			maxAge = 0
+		}
+		report, err := registry.FieldUsage(ctx, maxAge)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to count field usage")
+		}
+		return &Response[service.FieldUsage]{Body: *report}, nil
+	})
+}
@@ -0,0 +1,54 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListForksInput represents the input for listing the forks of a server
+type ListForksInput struct {
+	Name   string `query:"name" doc:"Name of the original server" required:"true" minLength:"1" example:"io.github.acme/weather"`
+	Cursor string `query:"cursor" doc:"Pagination cursor: next_cursor of the previous page" required:"false"`
+	Limit  int    `query:"limit" doc:"Number of forks per page, at most 100; larger values are clamped" default:"30" minimum:"1" example:"50"`
+}
+
+// Resolve clamps the page size to MaxPageSize
+func (i *ListForksInput) Resolve(ctx huma.Context) []error {
+	clampPageSize(ctx, &i.Limit)
+	return nil
+}
+
+// ListForksBody lists the servers declaring themselves forks of a server
+type ListForksBody struct {
+	Name     string                `json:"name"`
+	Forks    []apiv0.ServerSummary `json:"forks" doc:"Latest version of each fork, including deleted ones, which have the deleted status"`
+	Metadata apiv0.Metadata        `json:"metadata"`
+}
+
+// RegisterForksEndpoint registers the server forks listing
+func RegisterForksEndpoint(api huma.API, registry service.RegistryService) {
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "list-server-forks",
+		Method:      http.MethodGet,
+		Path:        "/v0/servers/forks",
+		Summary:     "List server forks",
+		Description: "List the servers whose latest version declares, with forkOf, that it was derived from a server",
+		Tags:        []string{"servers"},
+	}), func(ctx context.Context, input *ListForksInput) (*Response[ListForksBody], error) {
+		list, err := registry.ListForks(ctx, input.Name, input.Cursor, input.Limit)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to list server forks")
+		}
+
+		body := ListForksBody{
+			Name:     input.Name,
+			Forks:    list.Forks,
+			Metadata: apiv0.Metadata{NextCursor: list.NextCursor, Count: len(list.Forks), Total: list.Total},
+		}
+		return &Response[ListForksBody]{Body: body}, nil
+	})
+}
@@ -0,0 +1,69 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestForksEndpoints(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+	original, err := registryService.Publish(ctx, apiv0.ServerJSON{Name: "io.github.acme/weather", Description: "Weather tools", Version: "1.0.0"})
+	require.NoError(t, err)
+	fork, err := registryService.Publish(ctx, apiv0.ServerJSON{Name: "io.github.other/weather", Description: "Weather tools, forked", Version: "1.0.0", ForkOf: "io.github.acme/weather"})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+	v0.RegisterForksEndpoint(api, registryService)
+
+	get := func(target string, body any) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), body))
+		}
+		return w.Code
+	}
+
+	t.Run("detail", func(t *testing.T) {
+		var server apiv0.ServerJSON
+		require.Equal(t, http.StatusOK, get("/v0/servers/"+fork.Meta.Official.ID, &server))
+		assert.Equal(t, "io.github.acme/weather", server.ForkOf)
+		assert.Equal(t, &apiv0.ForkOrigin{Name: "io.github.acme/weather", ID: original.Meta.Official.ID, Status: apiv0.ForkOriginAvailable}, server.Meta.Official.ForkOrigin)
+		assert.Zero(t, server.Meta.Official.Forks)
+
+		var originalServer apiv0.ServerJSON
+		require.Equal(t, http.StatusOK, get("/v0/servers/"+original.Meta.Official.ID, &originalServer))
+		assert.Nil(t, originalServer.Meta.Official.ForkOrigin)
+		assert.Equal(t, 1, originalServer.Meta.Official.Forks)
+	})
+
+	t.Run("listing", func(t *testing.T) {
+		var body v0.ListForksBody
+		require.Equal(t, http.StatusOK, get("/v0/servers/forks?name=io.github.acme/weather", &body))
+		require.Len(t, body.Forks, 1)
+		assert.Equal(t, "io.github.other/weather", body.Forks[0].Name)
+		assert.Equal(t, 1, body.Metadata.Count)
+		assert.Equal(t, 1, body.Metadata.Total)
+
+		assert.Equal(t, http.StatusNotFound, get("/v0/servers/forks?name=io.github.acme/missing", &body))
+		assert.Equal(t, http.StatusBadRequest, get("/v0/servers/forks?name=io.github.acme/weather&cursor=unknown", &body))
+	})
+}
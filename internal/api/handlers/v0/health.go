@@ -20,14 +20,14 @@ type HealthBody struct {
 
 // RegisterHealthEndpoint registers the health check endpoint
 func RegisterHealthEndpoint(api huma.API, cfg *config.Config, metrics *telemetry.Metrics) {
-	huma.Register(api, huma.Operation{
+	huma.Register(api, Public(huma.Operation{
 		OperationID: "get-health",
 		Method:      http.MethodGet,
 		Path:        "/v0/health",
 		Summary:     "Health check",
 		Description: "Check the health status of the API",
 		Tags:        []string{"health"},
-	}, func(ctx context.Context, _ *struct{}) (*Response[HealthBody], error) {
+	}), func(ctx context.Context, _ *struct{}) (*Response[HealthBody], error) {
 		// Record the health check metrics
 		recordHealthMetrics(ctx, metrics, "/v0/health", cfg.Version)
 
@@ -0,0 +1,29 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// RegisterJWKSEndpoint registers the endpoint publishing the registry's JWT public keys
+func RegisterJWKSEndpoint(api huma.API, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "get-jwks",
+		Method:      http.MethodGet,
+		Path:        "/v0/admin/jwks",
+		Summary:     "Get JWT signing keys",
+		Description: "Public keys accepted for Registry JWT validation, as a JWKS document. The key used for new tokens is listed first; the rest are being rotated out, except a separate seed signing key, which is listed last and only verifies signed exports.",
+		Tags:        []string{"admin"},
+	}), func(_ context.Context, _ *struct{}) (*Response[auth.JSONWebKeySet], error) {
+		return &Response[auth.JSONWebKeySet]{
+			Body: jwtManager.JWKS(),
+		}, nil
+	})
+}
@@ -0,0 +1,38 @@
+package v0_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+func TestJWKSEndpoint(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:   "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		JWTAcceptedKeys: []string{"0000000000000000000000000000000000000000000000000000000000000001"},
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterJWKSEndpoint(api, cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/v0/admin/jwks", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var jwks auth.JSONWebKeySet
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&jwks))
+	assert.Equal(t, auth.NewJWTManager(cfg).JWKS(), jwks)
+	assert.Len(t, jwks.Keys, 3)
+}
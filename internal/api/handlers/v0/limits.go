@@ -0,0 +1,48 @@
+package v0
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MaxPageSize is the largest page a list endpoint returns. Larger limits are clamped to it
+// rather than rejected, and the response says so in PageSizeClampedHeader.
+const MaxPageSize = 100
+
+// PageSizeClampedHeader is set, to the page size used, on list responses whose requested
+// limit was above MaxPageSize
+const PageSizeClampedHeader = "X-Page-Size-Clamped"
+
+// MinSearchLength is the fewest characters a search term may have, not counting surrounding
+// whitespace. Shorter terms match most of the registry and are expensive to rank.
+const MinSearchLength = 2
+
+// searchWildcards are the characters that match anything in a name search
+const searchWildcards = "%_*"
+
+// clampPageSize lowers a limit above MaxPageSize to it, noting the clamp in the response
+func clampPageSize(ctx huma.Context, limit *int) {
+	if *limit > MaxPageSize {
+		*limit = MaxPageSize
+		ctx.SetHeader(PageSizeClampedHeader, strconv.Itoa(MaxPageSize))
+	}
+}
+
+// validateSearch rejects search terms too short or too unspecific to search for cheaply
+func validateSearch(search string) error {
+	term := strings.TrimSpace(search)
+	if utf8.RuneCountInString(term) < MinSearchLength {
+		return huma.Error400BadRequest("Invalid search parameter: must be at least " + strconv.Itoa(MinSearchLength) + " characters")
+	}
+	onlyWildcards := strings.TrimFunc(term, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(searchWildcards, r)
+	}) == ""
+	if onlyWildcards {
+		return huma.Error400BadRequest("Invalid search parameter: must contain more than wildcards")
+	}
+	return nil
+}
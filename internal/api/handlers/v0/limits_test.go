@@ -0,0 +1,74 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestQueryCostLimits(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+	for i := range v0.MaxPageSize + 5 {
+		_, err := registryService.Publish(ctx, apiv0.ServerJSON{Name: fmt.Sprintf("com.example/server-%03d", i), Description: "A server", Version: "1.0.0"})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+	v0.RegisterVersionsEndpoint(api, registryService)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	t.Run("page sizes above the maximum are clamped", func(t *testing.T) {
+		w := get("/v0/servers?limit=500")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "100", w.Header().Get(v0.PageSizeClampedHeader))
+		var body apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Len(t, body.Servers, v0.MaxPageSize)
+		assert.Contains(t, w.Header().Get("Link"), "limit=100", "pagination links use the page size applied")
+
+		w = get("/v0/servers/versions?name=com.example/server-000&limit=1000")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "100", w.Header().Get(v0.PageSizeClampedHeader))
+	})
+
+	t.Run("page sizes within the maximum are kept", func(t *testing.T) {
+		w := get("/v0/servers?limit=100")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(v0.PageSizeClampedHeader))
+	})
+
+	t.Run("short and wildcard-only searches are rejected", func(t *testing.T) {
+		for _, search := range []string{"a", " a ", "%%", "_*", "% _"} {
+			w := get("/v0/servers?search=" + url.QueryEscape(search))
+			assert.Equal(t, http.StatusBadRequest, w.Code, search)
+		}
+		for _, search := range []string{"se", "server-01", "%server"} {
+			w := get("/v0/servers?search=" + url.QueryEscape(search))
+			assert.Equal(t, http.StatusOK, w.Code, search)
+		}
+	})
+}
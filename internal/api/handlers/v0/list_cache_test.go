@@ -0,0 +1,215 @@
+package v0_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func newListCacheTestServer(registryService service.RegistryService, cacheBytes int) *http.ServeMux {
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	if cacheBytes > 0 {
+		api.UseMiddleware(router.ListCacheMiddleware(router.NewListCache(cacheBytes), registryService, nil))
+	}
+	v0.RegisterServersEndpoints(api, registryService)
+	return mux
+}
+
+func listServers(t testing.TB, mux *http.ServeMux, query string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v0/servers"+query, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func testServer(name string) apiv0.ServerJSON {
+	return apiv0.ServerJSON{
+		Name:        name,
+		Description: "List cache test server",
+		Version:     "1.0.0",
+	}
+}
+
+func TestListCacheMiddleware(t *testing.T) {
+	memDB := database.NewMemoryDB()
+	registryService := service.NewRegistryService(memDB, &config.Config{EnableRegistryValidation: false})
+	mux := newListCacheTestServer(registryService, 1<<20)
+
+	_, err := registryService.Publish(context.Background(), testServer("com.example/first"))
+	require.NoError(t, err)
+
+	first := listServers(t, mux, "?limit=10", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Contains(t, first.Body.String(), "com.example/first")
+
+	// Write behind the service's back: the registry generation does not change,
+	// so a cache hit must return the original page unchanged
+	_, err = memDB.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Name:        "com.example/direct",
+		Description: "Inserted without publishing",
+		Version:     "1.0.0",
+		Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{
+			ID: uuid.New().String(), PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true,
+		}},
+	})
+	require.NoError(t, err)
+
+	cached := listServers(t, mux, "?limit=10", nil)
+	assert.Equal(t, first.Code, cached.Code)
+	assert.Equal(t, first.Header(), cached.Header())
+	assert.Equal(t, first.Body.String(), cached.Body.String())
+
+	t.Run("equivalent query strings share an entry", func(t *testing.T) {
+		reordered := listServers(t, mux, "?limit=10&", nil)
+		assert.Equal(t, first.Body.String(), reordered.Body.String())
+	})
+
+	t.Run("authenticated requests bypass the cache", func(t *testing.T) {
+		authed := listServers(t, mux, "?limit=10", http.Header{"Authorization": {"Bearer token"}})
+		assert.Equal(t, http.StatusOK, authed.Code)
+		assert.Contains(t, authed.Body.String(), "com.example/direct")
+	})
+
+	t.Run("publishing invalidates the cache", func(t *testing.T) {
+		_, err := registryService.Publish(context.Background(), testServer("com.example/second"))
+		require.NoError(t, err)
+
+		fresh := listServers(t, mux, "?limit=10", nil)
+		assert.Equal(t, http.StatusOK, fresh.Code)
+		assert.Contains(t, fresh.Body.String(), "com.example/direct")
+		assert.Contains(t, fresh.Body.String(), "com.example/second")
+		assert.Equal(t, first.Header().Get("Content-Type"), fresh.Header().Get("Content-Type"))
+	})
+
+	t.Run("error responses are not cached", func(t *testing.T) {
+		bad := listServers(t, mux, "?updated_since=yesterday", nil)
+		assert.Equal(t, http.StatusBadRequest, bad.Code)
+		again := listServers(t, mux, "?updated_since=yesterday", nil)
+		assert.Equal(t, bad.Body.String(), again.Body.String())
+	})
+}
+
+// invalidationBus delivers invalidations to every replica listening on it, like a shared
+// channel. When down, Listen fails without ever becoming ready.
+type invalidationBus struct {
+	mu        sync.Mutex
+	down      bool
+	listeners []func(name string)
+}
+
+func (b *invalidationBus) Invalidate(_ context.Context, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, fn := range b.listeners {
+		fn(name)
+	}
+	return nil
+}
+
+func (b *invalidationBus) Listen(ctx context.Context, ready func(), fn func(name string)) error {
+	b.mu.Lock()
+	if b.down {
+		b.mu.Unlock()
+		return fmt.Errorf("channel unavailable")
+	}
+	b.listeners = append(b.listeners, fn)
+	b.mu.Unlock()
+	ready()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestListCacheMiddleware_AcrossReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := &config.Config{Replicas: 2}
+
+	t.Run("writes on another replica invalidate the cache", func(t *testing.T) {
+		memDB := database.NewMemoryDB()
+		bus := &invalidationBus{}
+		replicaA := service.NewRegistryService(memDB, cfg, service.WithCacheInvalidator(ctx, bus))
+		replicaB := service.NewRegistryService(memDB, cfg, service.WithCacheInvalidator(ctx, bus))
+		// Each replica's generation stops moving once its channel is listening
+		for _, replica := range []service.RegistryService{replicaA, replicaB} {
+			require.Eventually(t, func() bool { return replica.Generation() == replica.Generation() }, time.Second, 10*time.Millisecond)
+		}
+		mux := newListCacheTestServer(replicaA, 1<<20)
+
+		_, err := replicaA.Publish(context.Background(), testServer("com.example/first"))
+		require.NoError(t, err)
+		first := listServers(t, mux, "?limit=10", nil)
+		require.Equal(t, http.StatusOK, first.Code)
+		assert.NotContains(t, first.Body.String(), "com.example/second")
+
+		_, err = replicaB.Publish(context.Background(), testServer("com.example/second"))
+		require.NoError(t, err)
+		fresh := listServers(t, mux, "?limit=10", nil)
+		assert.Contains(t, fresh.Body.String(), "com.example/second", "replica A must see replica B's publish")
+	})
+
+	t.Run("pages are not reused while the channel is down", func(t *testing.T) {
+		memDB := database.NewMemoryDB()
+		replica := service.NewRegistryService(memDB, cfg, service.WithCacheInvalidator(ctx, &invalidationBus{down: true}))
+		mux := newListCacheTestServer(replica, 1<<20)
+
+		_, err := replica.Publish(context.Background(), testServer("com.example/first"))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, listServers(t, mux, "?limit=10", nil).Code)
+
+		// Another replica's write, which this one cannot hear about
+		other := service.NewRegistryService(memDB, &config.Config{})
+		_, err = other.Publish(context.Background(), testServer("com.example/second"))
+		require.NoError(t, err)
+		assert.Contains(t, listServers(t, mux, "?limit=10", nil).Body.String(), "com.example/second")
+	})
+}
+
+func BenchmarkListServers(b *testing.B) {
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+	for i := 0; i < 100; i++ {
+		if _, err := registryService.Publish(context.Background(), testServer(fmt.Sprintf("com.example/server-%d", i))); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for _, bc := range []struct {
+		name       string
+		cacheBytes int
+	}{
+		{name: "uncached", cacheBytes: 0},
+		{name: "cached", cacheBytes: 1 << 20},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			mux := newListCacheTestServer(registryService, bc.cacheBytes)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if w := listServers(b, mux, "?limit=100", nil); w.Code != http.StatusOK {
+					b.Fatalf("unexpected status %d", w.Code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package v0
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// metaMaxAge is how long clients and caches may reuse the meta response, which only changes on redeploy
+const metaMaxAge = 300
+
+// MetaInput represents the input for getting registry metadata
+type MetaInput struct {
+	IfNoneMatch string `header:"If-None-Match" doc:"ETag of a copy the client already has" required:"false"`
+}
+
+// MetaOutput is the registry's build and feature metadata
+type MetaOutput struct {
+	Status       int
+	CacheControl string `header:"Cache-Control"`
+	ETag         string `header:"ETag" doc:"Changes when the registry is redeployed with a different build or configuration"`
+	Body         *apiv0.RegistryMeta
+}
+
+// RegistryMeta describes the registry build and which optional features cfg enables. Features are
+// booleans derived from the configuration, so the response never carries secrets or URLs.
+func RegistryMeta(cfg *config.Config) apiv0.RegistryMeta {
+	version := cfg.Build.Version
+	if version == "" {
+		version = cfg.Version
+	}
+	return apiv0.RegistryMeta{
+		APIVersion: apiv0.APIVersion,
+		Version:    version,
+		GitCommit:  cfg.Build.GitCommit,
+		BuildTime:  cfg.Build.BuildTime,
+		Database:   string(cfg.DatabaseType),
+		Features: map[string]bool{
+			"github_oauth":                 cfg.GithubClientID != "",
+			"oidc":                         cfg.OIDCEnabled,
+			"anonymous_auth":               cfg.EnableAnonymousAuth,
+			"registry_validation":          cfg.EnableRegistryValidation,
+			"strict_package_versions":      cfg.StrictPackageVersions,
+			"reject_duplicate_remote_urls": cfg.RejectDuplicateRemoteURLs,
+			"review_new_namespaces":        cfg.ReviewNewNamespaces,
+			"typosquat_protection":         cfg.TyposquatMaxDistance > 0,
+			"webhook_notifications":        true,
+			"email_notifications":          cfg.SMTPAddress != "",
+			"retention":                    cfg.RetentionKeepVersions > 0,
+			"remote_health_checks":         cfg.RemoteHealthInterval > 0,
+			"link_checks":                  cfg.LinkCheckInterval > 0,
+			"admin_ui":                     cfg.EnableAdminUI,
+			"change_feed":                  cfg.EventsMaxConnections > 0,
+			"multi_tenant":                 cfg.TenancyEnabled,
+		},
+	}
+}
+
+// RegisterMetaEndpoint registers the endpoint describing the registry build and its features
+func RegisterMetaEndpoint(api huma.API, cfg *config.Config) {
+	// The configuration is fixed for the life of the process, so the response is built once
+	meta := RegistryMeta(cfg)
+	document, _ := json.Marshal(meta) // strings and booleans always encode
+	hash := sha256.Sum256(document)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "get-meta",
+		Method:      http.MethodGet,
+		Path:        "/v0/meta",
+		Summary:     "Get registry metadata",
+		Description: "Get the registry's API version, build version, git commit and build time, its database type and which optional features are enabled. Clients can check api_version to detect a registry they are not compatible with.",
+		Tags:        []string{"health"},
+	}), func(_ context.Context, input *MetaInput) (*MetaOutput, error) {
+		output := &MetaOutput{
+			Status:       http.StatusOK,
+			CacheControl: fmt.Sprintf("public, max-age=%d", metaMaxAge),
+			ETag:         etag,
+			Body:         &meta,
+		}
+		if etagMatches(input.IfNoneMatch, etag) {
+			output.Status = http.StatusNotModified
+			output.Body = nil
+		}
+		return output, nil
+	})
+}
+
+// ValidationRulesOutput is the validation rules response
+type ValidationRulesOutput struct {
+	CacheControl string `header:"Cache-Control"`
+	Body         apiv0.ValidationRules
+}
+
+// RegisterValidationRulesEndpoint registers the endpoint serving the rules server.json documents
+// are validated against
+func RegisterValidationRulesEndpoint(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "get-validation-rules",
+		Method:      http.MethodGet,
+		Path:        "/v0/meta/validation-rules",
+		Summary:     "Get validation rules",
+		Description: "Get the limits and allowed values the registry validates server.json documents against: the name pattern, field lengths and sizes, allowed registry types, transports, repository sources and categories, forbidden headers and reserved namespaces. They are the values the registry's checks read, so forms can validate before publishing without hardcoding them.",
+		Tags:        []string{"health"},
+	}), func(ctx context.Context, _ *struct{}) (*ValidationRulesOutput, error) {
+		reservations, err := registry.ListNamespaceReservations(ctx)
+		if err != nil {
+			return nil, serviceError(err, "Namespace reservation", http.StatusInternalServerError, "Failed to list namespace reservations")
+		}
+		reserved := make([]string, 0, len(reservations))
+		for _, reservation := range reservations {
+			reserved = append(reserved, reservation.Namespace)
+		}
+
+		return &ValidationRulesOutput{
+			CacheControl: fmt.Sprintf("public, max-age=%d", metaMaxAge),
+			Body:         validators.CurrentRules(cfg.ServerCategories, reserved),
+		}, nil
+	})
+}
@@ -0,0 +1,106 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// NamespaceSettingsInput represents the input for a namespace's settings
+type NamespaceSettingsInput struct {
+	Namespace string `path:"namespace" doc:"Namespace, the part of server names before the slash" pattern:"^[a-zA-Z0-9.-]+$" example:"com.example"`
+}
+
+// PutNamespaceSettingsInput represents the input for replacing a namespace's settings
+type PutNamespaceSettingsInput struct {
+	Namespace string `path:"namespace" doc:"Namespace, the part of server names before the slash" pattern:"^[a-zA-Z0-9.-]+$" example:"com.example"`
+	Body      NamespaceDefaults
+}
+
+// NamespaceDefaults are the values new versions published under a namespace take for the
+// optional server.json fields they leave unset. Empty fields have no default.
+type NamespaceDefaults struct {
+	License    string   `json:"license,omitempty" doc:"SPDX license expression for versions without a license" maxLength:"200" example:"MIT"`
+	Categories []string `json:"categories,omitempty" doc:"Categories for versions listing none" maxItems:"5" example:"[\"weather\"]"`
+	WebsiteURL string   `json:"website_url,omitempty" doc:"Website for versions without a websiteUrl" format:"uri" example:"https://example.com"`
+	Contact    string   `json:"contact,omitempty" doc:"Contact for versions without one, such as an email address or support URL" maxLength:"200" example:"mcp@example.com"`
+}
+
+// NamespaceSettingsBody is a namespace's settings as returned by the API
+type NamespaceSettingsBody struct {
+	Namespace string `json:"namespace"`
+	NamespaceDefaults
+	UpdatedAt *time.Time `json:"updated_at,omitempty" doc:"When the settings were last changed; absent when they never were"`
+}
+
+// RegisterNamespaceSettingsEndpoints registers the endpoints for reading and replacing namespace settings
+func RegisterNamespaceSettingsEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "get-namespace-settings",
+		Method:      http.MethodGet,
+		Path:        "/v0/namespaces/{namespace}/settings",
+		Summary:     "Get namespace settings",
+		Description: "The defaults new versions published under a namespace take for the optional server.json fields they leave unset. Namespaces whose owners have set none have empty settings.",
+		Tags:        []string{"namespaces"},
+	}), func(ctx context.Context, input *NamespaceSettingsInput) (*Response[NamespaceSettingsBody], error) {
+		settings, err := registry.NamespaceSettings(ctx, input.Namespace)
+		if errors.Is(err, database.ErrNotFound) {
+			return &Response[NamespaceSettingsBody]{Body: NamespaceSettingsBody{Namespace: input.Namespace}}, nil
+		}
+		if err != nil {
+			return nil, serviceError(err, "Namespace", http.StatusInternalServerError, "Failed to get namespace settings")
+		}
+		return &Response[NamespaceSettingsBody]{Body: namespaceSettingsBody(settings)}, nil
+	})
+
+	// Only owners of the whole namespace may change the defaults every server in it inherits
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "put-namespace-settings",
+		Method:      http.MethodPut,
+		Path:        "/v0/namespaces/{namespace}/settings",
+		Summary:     "Replace namespace settings",
+		Description: "Replace the defaults new versions published under a namespace take for the optional server.json fields they leave unset: license, categories, websiteUrl and contact. Values a server.json sets always win, and each published version records which fields it inherited in inherited_fields. Versions already published are unchanged. Requires publish permission for every server in the namespace.",
+		Tags:        []string{"namespaces"},
+	}, Permission{Action: auth.PermissionActionPublish, Resource: "{namespace}/*"}), func(ctx context.Context, input *PutNamespaceSettingsInput) (*Response[NamespaceSettingsBody], error) {
+		settings, err := registry.PutNamespaceSettings(ctx, &database.NamespaceSettings{
+			Namespace:  input.Namespace,
+			License:    input.Body.License,
+			Categories: input.Body.Categories,
+			WebsiteURL: input.Body.WebsiteURL,
+			Contact:    input.Body.Contact,
+			UpdatedBy:  ClaimsFromContext(ctx).AuthMethodSubject,
+		})
+		if err != nil {
+			return nil, serviceError(err, "Namespace", http.StatusInternalServerError, "Failed to update namespace settings")
+		}
+		return &Response[NamespaceSettingsBody]{Body: namespaceSettingsBody(settings)}, nil
+	})
+}
+
+// namespaceSettingsBody converts stored namespace settings to their API representation
+func namespaceSettingsBody(settings *database.NamespaceSettings) NamespaceSettingsBody {
+	updatedAt := settings.UpdatedAt.UTC()
+	body := NamespaceSettingsBody{
+		Namespace: settings.Namespace,
+		NamespaceDefaults: NamespaceDefaults{
+			License:    settings.License,
+			WebsiteURL: settings.WebsiteURL,
+			Contact:    settings.Contact,
+		},
+		UpdatedAt: &updatedAt,
+	}
+	if len(settings.Categories) > 0 {
+		body.Categories = settings.Categories
+	}
+	return body
+}
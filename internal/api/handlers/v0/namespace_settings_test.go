@@ -0,0 +1,93 @@
+package v0_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+func TestNamespaceSettingsEndpoints(t *testing.T) {
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterNamespaceSettingsEndpoints(api, registryService, cfg)
+
+	tokenFor := func(pattern string) string {
+		token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "octocat",
+			Permissions:       []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: pattern}},
+		})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	serve := func(method, body, authHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v0/namespaces/io.github.octocat/settings", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	settings := `{"license": "MIT", "categories": ["weather"], "website_url": "https://octocat.dev", "contact": "mcp@octocat.dev"}`
+
+	t.Run("namespaces without settings have empty ones", func(t *testing.T) {
+		w := serve(http.MethodGet, "", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body v0.NamespaceSettingsBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, v0.NamespaceSettingsBody{Namespace: "io.github.octocat"}, body)
+	})
+
+	t.Run("requires a token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPut, settings, "").Code)
+	})
+
+	t.Run("rejects tokens for a single server in the namespace", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, settings, tokenFor("io.github.octocat/weather")).Code)
+	})
+
+	t.Run("rejects tokens for another namespace", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, settings, tokenFor("io.github.octocat-fan/*")).Code)
+	})
+
+	t.Run("rejects invalid defaults", func(t *testing.T) {
+		w := serve(http.MethodPut, `{"license": "MIT OR"}`, tokenFor("io.github.octocat/*"))
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "invalid_license")
+	})
+
+	t.Run("namespace owners replace the settings", func(t *testing.T) {
+		w := serve(http.MethodPut, settings, tokenFor("io.github.octocat/*"))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = serve(http.MethodGet, "", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body v0.NamespaceSettingsBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, v0.NamespaceDefaults{
+			License:    "MIT",
+			Categories: []string{"weather"},
+			WebsiteURL: "https://octocat.dev",
+			Contact:    "mcp@octocat.dev",
+		}, body.NamespaceDefaults)
+		assert.NotNil(t, body.UpdatedAt)
+	})
+}
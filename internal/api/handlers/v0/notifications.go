@@ -0,0 +1,99 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// RegisterNotificationInput represents the input for registering a publish notification
+type RegisterNotificationInput struct {
+	Namespace string `path:"namespace" doc:"Namespace to watch, the part of server names before the slash" pattern:"^[a-zA-Z0-9.-]+$" example:"io.github.octocat"`
+	Body      struct {
+		WebhookURL string `json:"webhook_url,omitempty" doc:"HTTPS URL to POST a JSON notification to on every publish" format:"uri"`
+		Email      string `json:"email,omitempty" doc:"Email address to notify on every publish" format:"email"`
+	}
+}
+
+// UnsubscribeInput represents the input for removing a publish notification
+type UnsubscribeInput struct {
+	ID    string `path:"id" doc:"Notification registration ID (UUID)" format:"uuid"`
+	Token string `query:"token" doc:"Token from the unsubscribe link" required:"true"`
+}
+
+// NotificationRegistration is a publish notification registration as returned by the API
+type NotificationRegistration struct {
+	ID             string    `json:"id"`
+	Namespace      string    `json:"namespace"`
+	WebhookURL     string    `json:"webhook_url,omitempty"`
+	Email          string    `json:"email,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UnsubscribeURL string    `json:"unsubscribe_url"`
+}
+
+// UnsubscribeBody is the response to a successful unsubscribe
+type UnsubscribeBody struct {
+	Message string `json:"message"`
+}
+
+// RegisterNotificationEndpoints registers the endpoints for namespace publish notifications
+func RegisterNotificationEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// Register notification endpoint. Only owners of the whole namespace may watch it; a grant
+	// for a single server is not enough
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "register-namespace-notification",
+		Method:      http.MethodPost,
+		Path:        "/v0/namespaces/{namespace}/notifications",
+		Summary:     "Register for namespace publish notifications",
+		Description: "Register a webhook URL or email address to be notified whenever a server version is published under the namespace. Requires publish permission for every server in the namespace.",
+		Tags:        []string{"notifications"},
+	}, Permission{Action: auth.PermissionActionPublish, Resource: "{namespace}/*"}), func(ctx context.Context, input *RegisterNotificationInput) (*Response[NotificationRegistration], error) {
+		registration, err := registry.RegisterNotification(ctx, input.Namespace, input.Body.WebhookURL, input.Body.Email, ClaimsFromContext(ctx).AuthMethodSubject)
+		if err != nil {
+			if errors.Is(err, service.ErrEmailNotificationsDisabled) {
+				return nil, huma.Error400BadRequest(err.Error())
+			}
+			return nil, serviceError(err, "Notification", http.StatusInternalServerError, "Failed to register notification")
+		}
+
+		return &Response[NotificationRegistration]{
+			Body: NotificationRegistration{
+				ID:             registration.ID,
+				Namespace:      registration.Namespace,
+				WebhookURL:     registration.WebhookURL,
+				Email:          registration.Email,
+				CreatedAt:      registration.CreatedAt,
+				UnsubscribeURL: registry.UnsubscribeURL(registration.ID),
+			},
+		}, nil
+	})
+
+	// Unsubscribe endpoint, linked from every notification so recipients can opt out without signing in
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "unsubscribe-notification",
+		Method:      http.MethodGet,
+		Path:        "/v0/notifications/{id}/unsubscribe",
+		Summary:     "Unsubscribe from publish notifications",
+		Description: "Remove a publish notification registration using the signed token from its unsubscribe link",
+		Tags:        []string{"notifications"},
+	}), func(ctx context.Context, input *UnsubscribeInput) (*Response[UnsubscribeBody], error) {
+		if err := registry.Unsubscribe(ctx, input.ID, input.Token); err != nil {
+			if errors.Is(err, service.ErrInvalidUnsubscribeToken) {
+				return nil, huma.Error403Forbidden("Invalid unsubscribe token")
+			}
+			return nil, serviceError(err, "Notification", http.StatusInternalServerError, "Failed to unsubscribe")
+		}
+
+		return &Response[UnsubscribeBody]{
+			Body: UnsubscribeBody{Message: "You will no longer receive these notifications"},
+		}, nil
+	})
+}
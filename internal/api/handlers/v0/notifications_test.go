@@ -0,0 +1,125 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+func TestNotificationEndpoints(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		PublicURL:     "https://registry.example.com",
+	}
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterNotificationEndpoints(api, registryService, cfg)
+
+	tokenFor := func(pattern string) string {
+		token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "octocat",
+			Permissions: []auth.Permission{
+				{Action: auth.PermissionActionPublish, ResourcePattern: pattern},
+			},
+		})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+
+	register := func(namespace, authHeader string, body map[string]string) *httptest.ResponseRecorder {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/v0/namespaces/"+namespace+"/notifications", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	webhook := map[string]string{"webhook_url": "https://hooks.example.com/mcp"}
+
+	t.Run("requires a token", func(t *testing.T) {
+		w := register("io.github.octocat", "", webhook)
+		assert.NotEqual(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rejects tokens for other namespaces", func(t *testing.T) {
+		w := register("io.github.octocat", tokenFor("io.github.someone-else/*"), webhook)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("rejects tokens for a single server in the namespace", func(t *testing.T) {
+		w := register("io.github.octocat", tokenFor("io.github.octocat/one-server"), webhook)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("rejects webhooks to private addresses", func(t *testing.T) {
+		w := register("io.github.octocat", tokenFor("io.github.octocat/*"), map[string]string{"webhook_url": "https://127.0.0.1/hook"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rejects email without SMTP configured", func(t *testing.T) {
+		w := register("io.github.octocat", tokenFor("io.github.octocat/*"), map[string]string{"email": "octocat@example.com"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	var registration v0.NotificationRegistration
+	t.Run("namespace owners can register", func(t *testing.T) {
+		w := register("io.github.octocat", tokenFor("io.github.octocat/*"), webhook)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &registration))
+		assert.Equal(t, "io.github.octocat", registration.Namespace)
+		assert.Equal(t, webhook["webhook_url"], registration.WebhookURL)
+		assert.Contains(t, registration.UnsubscribeURL, "https://registry.example.com/v0/notifications/"+registration.ID+"/unsubscribe?token=")
+
+		stored, err := db.ListNamespaceNotifications(context.Background(), "io.github.octocat")
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, "octocat", stored[0].CreatedBy)
+	})
+
+	t.Run("unsubscribe rejects a bad token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/notifications/"+registration.ID+"/unsubscribe?token=forged", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("unsubscribe link removes the registration", func(t *testing.T) {
+		link, err := url.Parse(registration.UnsubscribeURL)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, link.RequestURI(), nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		stored, err := db.ListNamespaceNotifications(context.Background(), "io.github.octocat")
+		require.NoError(t, err)
+		assert.Empty(t, stored)
+
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, link.RequestURI(), nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
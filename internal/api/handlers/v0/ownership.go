@@ -0,0 +1,96 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+const (
+	// OwnershipStatementIssuer is the iss claim of namespace ownership statements
+	OwnershipStatementIssuer = "mcp-registry"
+	// OwnershipStatementType is the typ header of namespace ownership statements
+	OwnershipStatementType = "ownership-statement+jwt"
+	// OwnershipStatementLifetime is how long an ownership statement is valid for; ownership can
+	// change with the next publish, so statements are evidence of the moment they were served
+	OwnershipStatementLifetime = 24 * time.Hour
+)
+
+// NamespaceOwnershipInput represents the input for a namespace's ownership proof
+type NamespaceOwnershipInput struct {
+	Namespace string `path:"namespace" doc:"Namespace, the part of server names before the slash" pattern:"^[a-zA-Z0-9.-]+$" example:"com.example"`
+}
+
+// NamespaceOwnershipBody is the registry's evidence that a publisher controls a namespace
+type NamespaceOwnershipBody struct {
+	Namespace  string     `json:"namespace"`
+	Verified   bool       `json:"verified" doc:"Whether anyone has published under the namespace with a verified identity"`
+	Method     string     `json:"method,omitempty" doc:"Authentication method of the latest verification, e.g. dns, http or github-oidc"`
+	Subject    string     `json:"subject,omitempty" doc:"What the method verified, e.g. the domain or the GitHub repository owner"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty" doc:"When the namespace was last verified, by publishing under it"`
+	Statement  string     `json:"statement" doc:"The same evidence as a JWS signed by the registry, verifiable against the keys at /v0/admin/jwks"`
+}
+
+// NamespaceOwnershipClaims are the claims of a signed ownership statement. The subject claim
+// is the namespace, and the statement is issued when it is served and expires
+// OwnershipStatementLifetime later.
+type NamespaceOwnershipClaims struct {
+	jwt.RegisteredClaims
+	Namespace  string           `json:"namespace"`
+	Verified   bool             `json:"verified"`
+	Method     string           `json:"method,omitempty"`
+	Subject    string           `json:"subject,omitempty"`
+	VerifiedAt *jwt.NumericDate `json:"verified_at,omitempty"`
+}
+
+// RegisterOwnershipEndpoint registers the public namespace ownership proof endpoint
+func RegisterOwnershipEndpoint(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "get-namespace-ownership",
+		Method:      http.MethodGet,
+		Path:        "/v0/namespaces/{namespace}/ownership",
+		Summary:     "Get namespace ownership proof",
+		Description: "How the registry last verified that a publisher controls a namespace, with a statement of it signed by the registry. Namespaces nobody has published under with a verified identity get a signed statement saying so.",
+		Tags:        []string{"namespaces"},
+	}), func(ctx context.Context, input *NamespaceOwnershipInput) (*Response[NamespaceOwnershipBody], error) {
+		body := NamespaceOwnershipBody{Namespace: input.Namespace}
+		now := time.Now()
+		claims := NamespaceOwnershipClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    OwnershipStatementIssuer,
+				Subject:   input.Namespace,
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(OwnershipStatementLifetime)),
+			},
+			Namespace: input.Namespace,
+		}
+
+		verification, err := registry.NamespaceOwnership(ctx, input.Namespace)
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+		case err != nil:
+			return nil, serviceError(err, "Namespace", http.StatusInternalServerError, "Failed to get namespace ownership")
+		default:
+			verifiedAt := verification.VerifiedAt.UTC()
+			body.Verified, body.Method, body.Subject, body.VerifiedAt = true, verification.Method, verification.Subject, &verifiedAt
+			claims.Verified, claims.Method, claims.Subject = true, verification.Method, verification.Subject
+			claims.VerifiedAt = jwt.NewNumericDate(verifiedAt)
+		}
+
+		if body.Statement, err = jwtManager.SignStatement(OwnershipStatementType, claims); err != nil {
+			return nil, huma.Error500InternalServerError("Failed to sign ownership statement", err)
+		}
+		return &Response[NamespaceOwnershipBody]{Body: body}, nil
+	})
+}
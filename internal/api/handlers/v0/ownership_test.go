@@ -0,0 +1,132 @@
+package v0_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestNamespaceOwnershipEndpoint(t *testing.T) {
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	publish := func(name string, publisher service.Publisher) {
+		ctx := service.WithPublisher(context.Background(), publisher)
+		_, err := registryService.Publish(ctx, apiv0.ServerJSON{Name: name, Description: "A test server", Version: "1.0.0"})
+		require.NoError(t, err)
+	}
+	publish("com.example/weather", service.Publisher{AuthMethod: string(auth.MethodDNS), Subject: "example.com"})
+	publish("io.modelcontextprotocol.anonymous/test", service.Publisher{AuthMethod: string(auth.MethodNone)})
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterOwnershipEndpoint(api, registryService, cfg)
+	v0.RegisterJWKSEndpoint(api, cfg)
+
+	get := func(t *testing.T, path string, body any) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), body))
+	}
+
+	// verify checks a statement against the published JWKS, as a third party would
+	verify := func(t *testing.T, statement string) (*v0.NamespaceOwnershipClaims, error) {
+		t.Helper()
+		var jwks auth.JSONWebKeySet
+		get(t, "/v0/admin/jwks", &jwks)
+
+		claims := &v0.NamespaceOwnershipClaims{}
+		_, err := jwt.ParseWithClaims(statement, claims, func(token *jwt.Token) (any, error) {
+			for _, key := range jwks.Keys {
+				if key.KeyID == token.Header["kid"] {
+					x, err := base64.RawURLEncoding.DecodeString(key.X)
+					return ed25519.PublicKey(x), err
+				}
+			}
+			return nil, jwt.ErrTokenUnverifiable
+		}, jwt.WithValidMethods([]string{"EdDSA"}), jwt.WithIssuer(v0.OwnershipStatementIssuer), jwt.WithExpirationRequired())
+		return claims, err
+	}
+
+	t.Run("verified namespace", func(t *testing.T) {
+		var body v0.NamespaceOwnershipBody
+		get(t, "/v0/namespaces/com.example/ownership", &body)
+		assert.True(t, body.Verified)
+		assert.Equal(t, "dns", body.Method)
+		assert.Equal(t, "example.com", body.Subject)
+		require.NotNil(t, body.VerifiedAt)
+		assert.WithinDuration(t, time.Now(), *body.VerifiedAt, time.Minute)
+
+		claims, err := verify(t, body.Statement)
+		require.NoError(t, err)
+		assert.Equal(t, "com.example", claims.RegisteredClaims.Subject)
+		assert.Equal(t, "com.example", claims.Namespace)
+		assert.True(t, claims.Verified)
+		assert.Equal(t, "dns", claims.Method)
+		assert.Equal(t, "example.com", claims.Subject)
+		require.NotNil(t, claims.VerifiedAt)
+		assert.Equal(t, body.VerifiedAt.Unix(), claims.VerifiedAt.Unix())
+		assert.Equal(t, v0.OwnershipStatementLifetime, claims.ExpiresAt.Sub(claims.IssuedAt.Time))
+	})
+
+	t.Run("unverified namespace", func(t *testing.T) {
+		for _, namespace := range []string{"org.unknown", "io.modelcontextprotocol.anonymous"} {
+			var body v0.NamespaceOwnershipBody
+			get(t, "/v0/namespaces/"+namespace+"/ownership", &body)
+			assert.False(t, body.Verified, namespace)
+			assert.Empty(t, body.Method, namespace)
+			assert.Nil(t, body.VerifiedAt, namespace)
+
+			claims, err := verify(t, body.Statement)
+			require.NoError(t, err, namespace)
+			assert.Equal(t, namespace, claims.Namespace)
+			assert.False(t, claims.Verified, namespace)
+			assert.Nil(t, claims.VerifiedAt, namespace)
+		}
+	})
+
+	t.Run("statements are not registry tokens", func(t *testing.T) {
+		var body v0.NamespaceOwnershipBody
+		get(t, "/v0/namespaces/com.example/ownership", &body)
+
+		token, _, err := jwt.NewParser().ParseUnverified(body.Statement, &v0.NamespaceOwnershipClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, v0.OwnershipStatementType, token.Header["typ"])
+
+		_, err = auth.NewJWTManager(cfg).ValidateToken(context.Background(), body.Statement)
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered statements fail verification", func(t *testing.T) {
+		var unverified, verified v0.NamespaceOwnershipBody
+		get(t, "/v0/namespaces/org.unknown/ownership", &unverified)
+		get(t, "/v0/namespaces/com.example/ownership", &verified)
+
+		// Claim another namespace's verification under the unverified statement's signature
+		parts := strings.Split(unverified.Statement, ".")
+		parts[1] = strings.Split(verified.Statement, ".")[1]
+		_, err := verify(t, strings.Join(parts, "."))
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+}
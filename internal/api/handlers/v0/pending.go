@@ -0,0 +1,170 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// ListPendingInput represents the input for listing server versions held for approval
+type ListPendingInput struct {
+	Cursor string `query:"cursor" doc:"Pagination cursor (UUID)" format:"uuid" required:"false"`
+	Limit  int    `query:"limit" doc:"Number of items per page, at most 100; larger values are clamped" default:"30" minimum:"1"`
+}
+
+// Resolve clamps the page size to MaxPageSize
+func (i *ListPendingInput) Resolve(ctx huma.Context) []error {
+	clampPageSize(ctx, &i.Limit)
+	return nil
+}
+
+// ApprovePendingInput represents the input for approving a held server version
+type ApprovePendingInput struct {
+	ID string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+}
+
+// RejectPendingInput represents the input for rejecting a held server version
+type RejectPendingInput struct {
+	ID   string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+	Body struct {
+		Reason string `json:"reason" doc:"Why the version was rejected, shown to its publisher" minLength:"1" maxLength:"1000" example:"The namespace impersonates another project"`
+	}
+}
+
+// ReviewStatusInput represents the input for a publisher checking on a held server version
+type ReviewStatusInput struct {
+	ID string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+}
+
+// ReviewStatusBody reports where a server version stands in admin review
+type ReviewStatusBody struct {
+	ID              string       `json:"id"`
+	Name            string       `json:"name"`
+	Version         string       `json:"version"`
+	Status          model.Status `json:"status" doc:"pending while held for review, rejected if an admin declined it, otherwise the version's public status"`
+	RejectionReason string       `json:"rejection_reason,omitempty"`
+}
+
+// RegisterPendingEndpoints registers the endpoints for reviewing server versions held for admin approval
+func RegisterPendingEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// Reviewing needs edit permission on every server, since a held version is judged against
+	// namespaces other than its own
+	globalEdit := Permission{Action: auth.PermissionActionEdit, Resource: "*"}
+
+	// List pending server versions endpoint
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "list-pending-servers",
+		Method:      http.MethodGet,
+		Path:        "/v0/admin/pending",
+		Summary:     "List MCP server versions pending approval",
+		Description: "List server versions held for admin approval, because their new namespace resembles an established one or has no approved servers yet (admin only)",
+		Tags:        []string{"admin"},
+	}, globalEdit), func(ctx context.Context, input *ListPendingInput) (*Response[apiv0.ServerListResponse], error) {
+		pending := model.StatusPending
+		servers, nextCursor, err := registry.List(ctx, &database.ServerFilter{Status: &pending}, input.Cursor, input.Limit)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to list pending servers")
+		}
+
+		return &Response[apiv0.ServerListResponse]{
+			Body: apiv0.ServerListResponse{
+				Servers: servers,
+				Metadata: apiv0.Metadata{
+					NextCursor: nextCursor,
+					Count:      len(servers),
+				},
+			},
+		}, nil
+	})
+
+	// Approve pending server version endpoint
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "approve-server",
+		Method:      http.MethodPost,
+		Path:        "/v0/admin/servers/{id}/approve",
+		Summary:     "Approve pending MCP server version",
+		Description: "Release a server version held for admin approval, making it active and publicly listed (admin only). Once approved, later publishes to its namespace are no longer held.",
+		Tags:        []string{"admin"},
+	}, globalEdit), func(ctx context.Context, input *ApprovePendingInput) (*Response[apiv0.ServerJSON], error) {
+
+		approved, err := registry.ApprovePending(ctx, input.ID)
+		if err != nil {
+			// Approving a version that isn't held is invalid input to the service, but a conflict with the server's state here
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error409Conflict("Server is not pending approval")
+			}
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to approve server")
+		}
+
+		return &Response[apiv0.ServerJSON]{
+			Body: *approved,
+		}, nil
+	})
+
+	// Reject pending server version endpoint
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "reject-server",
+		Method:      http.MethodPost,
+		Path:        "/v0/admin/servers/{id}/reject",
+		Summary:     "Reject pending MCP server version",
+		Description: "Decline a server version held for admin approval (admin only). It stays hidden from the public API, and the reason is shown to its publisher.",
+		Tags:        []string{"admin"},
+	}, globalEdit), func(ctx context.Context, input *RejectPendingInput) (*Response[apiv0.ServerJSON], error) {
+
+		rejected, err := registry.RejectPending(ctx, input.ID, input.Body.Reason)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error409Conflict("Server is not pending approval")
+			}
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to reject server")
+		}
+
+		return &Response[apiv0.ServerJSON]{
+			Body: *rejected,
+		}, nil
+	})
+
+	// Review status endpoint, for publishers polling a held version
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "get-server-review",
+		Method:      http.MethodGet,
+		Path:        "/v0/servers/{id}/review",
+		Summary:     "Get MCP server review status",
+		Description: "Check whether a server version is held for admin approval, and why it was rejected if it was. Unlike the public endpoints, this also finds held and rejected versions, so it requires publish permission for the server.",
+		Tags:        []string{"publish"},
+	}, Permission{Action: auth.PermissionActionPublish}, globalEdit), func(ctx context.Context, input *ReviewStatusInput) (*Response[ReviewStatusBody], error) {
+		server, err := registry.GetByID(ctx, input.ID)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get server details")
+		}
+		// Held versions are hidden from everyone else, so don't reveal that this one exists
+		claims := ClaimsFromContext(ctx)
+		if !jwtManager.HasPermission(server.Name, auth.PermissionActionPublish, claims.Permissions) &&
+			!jwtManager.HasPermission("*", auth.PermissionActionEdit, claims.Permissions) {
+			return nil, huma.Error404NotFound("Server not found")
+		}
+
+		body := ReviewStatusBody{
+			ID:      input.ID,
+			Name:    server.Name,
+			Version: server.Version,
+			Status:  server.Status,
+		}
+		if server.Meta != nil && server.Meta.Official != nil {
+			body.RejectionReason = server.Meta.Official.RejectionReason
+		}
+		return &Response[ReviewStatusBody]{
+			Body: body,
+		}, nil
+	})
+}
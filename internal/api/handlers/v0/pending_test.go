@@ -0,0 +1,269 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/api/handlers/admin"
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestPendingEndpoints(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:        "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		TyposquatMaxDistance: 1,
+		TyposquatMinServers:  2,
+	}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	publish := func(name string) *apiv0.ServerJSON {
+		published, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+			Name:        name,
+			Description: "A test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+		return published
+	}
+	var activeID string
+	for i := 0; i < 3; i++ {
+		activeID = publish(fmt.Sprintf("io.github.acme/server-%d", i)).Meta.Official.ID
+	}
+	held := publish("io.github.acrne/server")
+	require.Equal(t, model.StatusPending, held.Status)
+	heldID := held.Meta.Official.ID
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+	v0.RegisterPendingEndpoints(api, registryService, cfg)
+
+	tokenFor := func(pattern string) string {
+		token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "domdomegg",
+			Permissions: []auth.Permission{
+				{Action: auth.PermissionActionEdit, ResourcePattern: pattern},
+			},
+		})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	adminToken := tokenFor("*")
+	namespaceToken := tokenFor("io.github.acrne/*")
+
+	serve := func(method, path, authHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("held versions are hidden from the public API", func(t *testing.T) {
+		w := serve(http.MethodGet, "/v0/servers", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var list apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Len(t, list.Servers, 3)
+		for _, server := range list.Servers {
+			assert.NotEqual(t, "io.github.acrne/server", server.Name)
+		}
+
+		w = serve(http.MethodGet, "/v0/servers/"+heldID, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("listing requires edit permission on all servers", func(t *testing.T) {
+		w := serve(http.MethodGet, "/v0/admin/pending", namespaceToken)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = serve(http.MethodGet, "/v0/admin/pending", "")
+		assert.NotEqual(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("lists held versions", func(t *testing.T) {
+		w := serve(http.MethodGet, "/v0/admin/pending", adminToken)
+		require.Equal(t, http.StatusOK, w.Code)
+		var list apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list.Servers, 1)
+		assert.Equal(t, heldID, list.Servers[0].Meta.Official.ID)
+	})
+
+	t.Run("approval requires edit permission on all servers", func(t *testing.T) {
+		w := serve(http.MethodPost, "/v0/admin/servers/"+heldID+"/approve", namespaceToken)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("only pending versions can be approved", func(t *testing.T) {
+		w := serve(http.MethodPost, "/v0/admin/servers/"+activeID+"/approve", adminToken)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = serve(http.MethodPost, "/v0/admin/servers/00000000-0000-0000-0000-000000000000/approve", adminToken)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("approval makes the version public", func(t *testing.T) {
+		w := serve(http.MethodPost, "/v0/admin/servers/"+heldID+"/approve", adminToken)
+		require.Equal(t, http.StatusOK, w.Code)
+		var approved apiv0.ServerJSON
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &approved))
+		assert.Equal(t, model.StatusActive, approved.Status)
+
+		w = serve(http.MethodGet, "/v0/servers/"+heldID, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(http.MethodGet, "/v0/admin/pending", adminToken)
+		require.Equal(t, http.StatusOK, w.Code)
+		var list apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Empty(t, list.Servers)
+	})
+}
+
+func TestReviewEndpoints(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:       "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		ReviewNewNamespaces: true,
+	}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	publish := func(name string) string {
+		published, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+			Name:        name,
+			Description: "A test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+		require.Equal(t, model.StatusPending, published.Status)
+		return published.Meta.Official.ID
+	}
+	approveID := publish("io.github.octocat/weather")
+	rejectID := publish("io.github.spammer/tool")
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+	v0.RegisterPendingEndpoints(api, registryService, cfg)
+	admin.RegisterRoutes(mux, registryService, cfg)
+
+	tokenFor := func(action auth.PermissionAction, pattern string) string {
+		token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "octocat",
+			Permissions:       []auth.Permission{{Action: action, ResourcePattern: pattern}},
+		})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	adminToken := tokenFor(auth.PermissionActionEdit, "*")
+	publisherToken := tokenFor(auth.PermissionActionPublish, "io.github.spammer/*")
+	otherToken := tokenFor(auth.PermissionActionPublish, "io.github.octocat/*")
+
+	serve := func(method, path, authHeader, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	reviewStatus := func(id, authHeader string) (int, v0.ReviewStatusBody) {
+		w := serve(http.MethodGet, "/v0/servers/"+id+"/review", authHeader, "")
+		var body v0.ReviewStatusBody
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		}
+		return w.Code, body
+	}
+
+	t.Run("held versions are visible only to their publisher and admins", func(t *testing.T) {
+		code, body := reviewStatus(rejectID, publisherToken)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, model.StatusPending, body.Status)
+		assert.Equal(t, "io.github.spammer/tool", body.Name)
+
+		code, _ = reviewStatus(rejectID, adminToken)
+		assert.Equal(t, http.StatusOK, code)
+
+		code, _ = reviewStatus(rejectID, otherToken)
+		assert.Equal(t, http.StatusNotFound, code, "other publishers cannot tell it exists")
+
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/v0/servers/"+rejectID, publisherToken, "").Code)
+	})
+
+	t.Run("held versions in the admin UI are visible only to their publisher and admins", func(t *testing.T) {
+		adminPage := func(path, authHeader string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.AddCookie(&http.Cookie{Name: "mcp_registry_admin", Value: strings.TrimPrefix(authHeader, "Bearer ")})
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			return w
+		}
+
+		for _, token := range []string{publisherToken, adminToken} {
+			assert.Equal(t, http.StatusOK, adminPage("/admin/servers/"+rejectID, token).Code)
+		}
+		assert.Equal(t, http.StatusNotFound, adminPage("/admin/servers/"+rejectID, otherToken).Code, "other publishers cannot tell it exists")
+
+		// The server list, like the public one, only shows published versions
+		for _, token := range []string{publisherToken, adminToken, otherToken} {
+			w := adminPage("/admin/", token)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.NotContains(t, w.Body.String(), rejectID)
+		}
+	})
+
+	t.Run("rejection requires edit permission on all servers and a reason", func(t *testing.T) {
+		w := serve(http.MethodPost, "/v0/admin/servers/"+rejectID+"/reject", publisherToken, `{"reason": "Spam"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = serve(http.MethodPost, "/v0/admin/servers/"+rejectID+"/reject", adminToken, `{"reason": ""}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("rejection is reported to the publisher", func(t *testing.T) {
+		w := serve(http.MethodPost, "/v0/admin/servers/"+rejectID+"/reject", adminToken, `{"reason": "Spam"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		code, body := reviewStatus(rejectID, publisherToken)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, model.StatusRejected, body.Status)
+		assert.Equal(t, "Spam", body.RejectionReason)
+
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/v0/servers/"+rejectID, "", "").Code)
+		assert.Equal(t, http.StatusConflict, serve(http.MethodPost, "/v0/admin/servers/"+rejectID+"/approve", adminToken, "").Code)
+	})
+
+	t.Run("approval is reported to the publisher", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, serve(http.MethodPost, "/v0/admin/servers/"+approveID+"/approve", adminToken, "").Code)
+
+		code, body := reviewStatus(approveID, otherToken)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, model.StatusActive, body.Status)
+		assert.Empty(t, body.RejectionReason)
+
+		assert.Equal(t, http.StatusConflict, serve(http.MethodPost, "/v0/admin/servers/"+approveID+"/reject", adminToken, `{"reason": "Spam"}`).Code)
+	})
+}
@@ -14,14 +14,14 @@ type PingBody struct {
 
 // RegisterPingEndpoint registers the ping endpoint
 func RegisterPingEndpoint(api huma.API) {
-	huma.Register(api, huma.Operation{
+	huma.Register(api, Public(huma.Operation{
 		OperationID: "ping",
 		Method:      http.MethodGet,
 		Path:        "/v0/ping",
 		Summary:     "Ping",
 		Description: "Simple ping endpoint",
 		Tags:        []string{"ping"},
-	}, func(_ context.Context, _ *struct{}) (*Response[PingBody], error) {
+	}), func(_ context.Context, _ *struct{}) (*Response[PingBody], error) {
 		return &Response[PingBody]{
 			Body: PingBody{
 				Pong: true,
@@ -2,20 +2,22 @@ package v0
 
 import (
 	"context"
+	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 )
 
 // PublishServerInput represents the input for publishing a server
 type PublishServerInput struct {
-	Authorization string           `header:"Authorization" doc:"Registry JWT token (obtained from /v0/auth/token/github)" required:"true"`
-	Body          apiv0.ServerJSON `body:""`
+	IfVersionNewer bool             `header:"If-Version-Newer" doc:"When true, a version that is not newer than the server's latest version is skipped: the response is 200 with skipped set and the current latest version, instead of publishing it or rejecting a duplicate"`
+	Body           apiv0.ServerJSON `body:""`
 }
 
 // RegisterPublishEndpoint registers the publish endpoint
@@ -23,45 +25,63 @@ func RegisterPublishEndpoint(api huma.API, registry service.RegistryService, cfg
 	// Create JWT manager for token validation
 	jwtManager := auth.NewJWTManager(cfg)
 
-	huma.Register(api, huma.Operation{
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
 		OperationID: "publish-server",
 		Method:      http.MethodPost,
 		Path:        "/v0/publish",
 		Summary:     "Publish MCP server",
-		Description: "Publish a new MCP server to the registry or update an existing one",
+		Description: "Publish a new MCP server to the registry or update an existing one. Responds with the published server; send Accept: application/json; profile=\"legacy\" for the original {\"message\", \"id\"} response, which publisher releases predating the server profile also get",
 		Tags:        []string{"publish"},
-		Security: []map[string][]string{
-			{"bearer": {}},
-		},
-	}, func(ctx context.Context, input *PublishServerInput) (*Response[apiv0.ServerJSON], error) {
-		// Extract bearer token
-		const bearerPrefix = "Bearer "
-		authHeader := input.Authorization
-		if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
-			return nil, huma.Error401Unauthorized("Invalid Authorization header format. Expected 'Bearer <token>'")
-		}
-		token := authHeader[len(bearerPrefix):]
+	}, Permission{Action: auth.PermissionActionPublishVersion}), func(ctx context.Context, input *PublishServerInput) (*Response[ServerWithWarnings], error) {
+		claims := ClaimsFromContext(ctx)
 
-		// Validate Registry JWT token
-		claims, err := jwtManager.ValidateToken(ctx, token)
-		if err != nil {
-			return nil, huma.Error401Unauthorized("Invalid or expired Registry JWT token", err)
+		// Verify that the token has permission to publish the server. New versions of an existing
+		// server need publish_version, while creating a new server name needs the broader publish
+		if !jwtManager.HasPermission(input.Body.Name, auth.PermissionActionPublishVersion, claims.Permissions) {
+			return nil, huma.Error403Forbidden("You do not have permission to publish this server")
 		}
-
-		// Verify that the token has permission to publish the server
 		if !jwtManager.HasPermission(input.Body.Name, auth.PermissionActionPublish, claims.Permissions) {
-			return nil, huma.Error403Forbidden("You do not have permission to publish this server")
+			_, err := registry.GetLatestByName(ctx, input.Body.Name)
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error403Forbidden("You only have permission to publish new versions of existing servers, and this server does not exist yet")
+			}
+			if err != nil {
+				return nil, huma.Error500InternalServerError("Failed to look up server", err)
+			}
 		}
 
-		// Publish the server with extensions
-		publishedServer, err := registry.Publish(input.Body)
+		// Reserved namespaces only accept the identities their reservation allows, whatever the token's permissions
+		publisher := service.Publisher{AuthMethod: string(claims.AuthMethod), Subject: claims.AuthMethodSubject}
+		if err := registry.CheckNamespaceReservation(ctx, input.Body.Name, publisher); err != nil {
+			if errors.Is(err, service.ErrNamespaceReserved) {
+				return nil, huma.Error403Forbidden("This namespace is reserved and your identity is not allowed to publish under it", err)
+			}
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to check namespace reservations")
+		}
+
+		// Publish the server with extensions, recording who published it for namespace notifications
+		ctx = service.WithPublisher(ctx, publisher)
+		ctx = validators.WithWarnings(ctx)
+		if input.IfVersionNewer {
+			ctx = service.WithIfVersionNewer(ctx)
+		}
+		publishedServer, err := registry.Publish(ctx, input.Body)
+		if errors.Is(err, service.ErrVersionNotNewer) {
+			latest, err := registry.GetLatestByName(ctx, input.Body.Name)
+			if err != nil {
+				return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to look up server")
+			}
+			body := withWarnings(ctx, latest)
+			body.Skipped = true
+			return &Response[ServerWithWarnings]{Body: body}, nil
+		}
 		if err != nil {
-			return nil, huma.Error400BadRequest("Failed to publish server", err)
+			return nil, serviceError(err, "Server", http.StatusBadRequest, "Failed to publish server")
 		}
 
 		// Return the published server in flattened format
-		return &Response[apiv0.ServerJSON]{
-			Body: *publishedServer,
+		return &Response[ServerWithWarnings]{
+			Body: withWarnings(ctx, publishedServer),
 		}, nil
 	})
 }
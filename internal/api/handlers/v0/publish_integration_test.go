@@ -62,7 +62,7 @@ func TestPublishIntegration(t *testing.T) {
 			Repository: model.Repository{
 				URL:    "https://github.com/testuser/test-mcp-server",
 				Source: "github",
-				ID:     "testuser/test-mcp-server",
+				ID:     "100000012",
 			},
 			Version: "1.0.0",
 		}
@@ -105,7 +105,7 @@ func TestPublishIntegration(t *testing.T) {
 			Repository: model.Repository{
 				URL:    "https://github.com/example/test-server",
 				Source: "github",
-				ID:     "example/test-server",
+				ID:     "100000001",
 			},
 			Version: "1.0.0",
 		}
@@ -154,8 +154,8 @@ func TestPublishIntegration(t *testing.T) {
 		rr := httptest.NewRecorder()
 		mux.ServeHTTP(rr, req)
 
-		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
-		assert.Contains(t, rr.Body.String(), "required header parameter is missing")
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Missing Authorization header")
 	})
 
 	t.Run("publish fails with invalid token", func(t *testing.T) {
@@ -187,7 +187,7 @@ func TestPublishIntegration(t *testing.T) {
 			Repository: model.Repository{
 				URL:    "https://github.com/example/test-server",
 				Source: "github",
-				ID:     "example/test-server",
+				ID:     "100000001",
 			},
 		}
 
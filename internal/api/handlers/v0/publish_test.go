@@ -61,7 +61,7 @@ func TestPublishEndpoint(t *testing.T) {
 				Repository: model.Repository{
 					URL:    "https://github.com/example/test-server",
 					Source: "github",
-					ID:     "example/test-server",
+					ID:     "100000001",
 				},
 				Version: "1.0.0",
 			},
@@ -85,7 +85,7 @@ func TestPublishEndpoint(t *testing.T) {
 				Repository: model.Repository{
 					URL:    "https://github.com/example/test-server",
 					Source: "github",
-					ID:     "example/test-server",
+					ID:     "100000001",
 				},
 				Version: "1.0.0",
 			},
@@ -107,8 +107,8 @@ func TestPublishEndpoint(t *testing.T) {
 			setupRegistryService: func(_ service.RegistryService) {
 				// Empty registry - no setup needed
 			},
-			expectedStatus: http.StatusUnprocessableEntity,
-			expectedError:  "required header parameter is missing",
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Missing Authorization header",
 		},
 		{
 			name: "invalid authorization header format",
@@ -147,7 +147,7 @@ func TestPublishEndpoint(t *testing.T) {
 				Repository: model.Repository{
 					URL:    "https://github.com/example/test-server",
 					Source: "github",
-					ID:     "example/test-server",
+					ID:     "100000001",
 				},
 			},
 			tokenClaims: &auth.JWTClaims{
@@ -163,7 +163,7 @@ func TestPublishEndpoint(t *testing.T) {
 			expectedError:  "You do not have permission to publish this server",
 		},
 		{
-			name: "registry service error",
+			name: "duplicate version conflicts",
 			requestBody: apiv0.ServerJSON{
 				Name:        "example/test-server",
 				Description: "A test server",
@@ -171,7 +171,7 @@ func TestPublishEndpoint(t *testing.T) {
 				Repository: model.Repository{
 					URL:    "https://github.com/example/test-server",
 					Source: "github",
-					ID:     "example/test-server",
+					ID:     "100000001",
 				},
 			},
 			tokenClaims: &auth.JWTClaims{
@@ -189,14 +189,111 @@ func TestPublishEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/test-server-existing",
 						Source: "github",
-						ID:     "example/test-server-existing",
+						ID:     "100000013",
 					},
 				}
-				_, _ = registry.Publish(existingServer)
+				_, _ = registry.Publish(context.Background(), existingServer)
 			},
-			expectedStatus: http.StatusBadRequest,
+			expectedStatus: http.StatusConflict,
 			expectedError:  "invalid version: cannot publish duplicate version",
 		},
+		{
+			name: "publish_version token cannot create a new server",
+			requestBody: apiv0.ServerJSON{
+				Name:        "io.github.example/new-server",
+				Description: "A new server",
+				Version:     "1.0.0",
+			},
+			tokenClaims: &auth.JWTClaims{
+				AuthMethod: auth.MethodGitHubAT,
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionPublishVersion, ResourcePattern: "io.github.example/*"},
+				},
+			},
+			setupRegistryService: func(_ service.RegistryService) {},
+			expectedStatus:       http.StatusForbidden,
+			expectedError:        "does not exist yet",
+		},
+		{
+			name: "publish_version token can publish a new version of an existing server",
+			requestBody: apiv0.ServerJSON{
+				Name:        "io.github.example/existing-server",
+				Description: "An existing server",
+				Version:     "1.1.0",
+			},
+			tokenClaims: &auth.JWTClaims{
+				AuthMethod: auth.MethodGitHubAT,
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionPublishVersion, ResourcePattern: "io.github.example/*"},
+				},
+			},
+			setupRegistryService: func(registry service.RegistryService) {
+				_, _ = registry.Publish(context.Background(), apiv0.ServerJSON{
+					Name:        "io.github.example/existing-server",
+					Description: "An existing server",
+					Version:     "1.0.0",
+				})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "legacy publish token can publish a new version of an existing server",
+			requestBody: apiv0.ServerJSON{
+				Name:        "io.github.example/existing-server",
+				Description: "An existing server",
+				Version:     "1.1.0",
+			},
+			tokenClaims: &auth.JWTClaims{
+				AuthMethod: auth.MethodGitHubAT,
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.example/*"},
+				},
+			},
+			setupRegistryService: func(registry service.RegistryService) {
+				_, _ = registry.Publish(context.Background(), apiv0.ServerJSON{
+					Name:        "io.github.example/existing-server",
+					Description: "An existing server",
+					Version:     "1.0.0",
+				})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "publish_version token for another namespace is rejected",
+			requestBody: apiv0.ServerJSON{
+				Name:        "io.github.other/existing-server",
+				Description: "An existing server",
+				Version:     "1.1.0",
+			},
+			tokenClaims: &auth.JWTClaims{
+				AuthMethod: auth.MethodGitHubAT,
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionPublishVersion, ResourcePattern: "io.github.example/*"},
+				},
+			},
+			setupRegistryService: func(registry service.RegistryService) {
+				_, _ = registry.Publish(context.Background(), apiv0.ServerJSON{
+					Name:        "io.github.other/existing-server",
+					Description: "An existing server",
+					Version:     "1.0.0",
+				})
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "You do not have permission to publish this server",
+		},
+		{
+			name: "description with an unpaired surrogate is rejected",
+			requestBody: json.RawMessage(`{"name": "io.github.example/test-server", "description": "A test server \ud83d", "version": "1.0.0"}`),
+			tokenClaims: &auth.JWTClaims{
+				AuthMethod: auth.MethodNone,
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+				},
+			},
+			setupRegistryService: func(_ service.RegistryService) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedError:        "text is not valid UTF-8: description",
+		},
 		{
 			name: "package validation success - MCPB package",
 			requestBody: apiv0.ServerJSON{
@@ -279,3 +376,119 @@ func TestPublishEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestPublishEndpoint_DuplicateRemoteURLWarning(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:            "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		EnableRegistryValidation: false,
+	}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+	_, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        "com.example/weather",
+		Description: "Weather lookups",
+		Version:     "1.0.0",
+		Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://mcp.example.com/weather"}},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, registryService, cfg)
+
+	body, err := json.Marshal(apiv0.ServerJSON{
+		Name:        "com.example/forecast",
+		Description: "Weather forecasts",
+		Version:     "1.0.0",
+		Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://mcp.example.com:443/weather/"}},
+	})
+	require.NoError(t, err)
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod:  auth.MethodNone,
+		Permissions: []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "*"}},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v0/publish", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response v0.ServerWithWarnings
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Warnings, 1)
+	assert.Equal(t, apiv0.WarningDuplicateRemoteURL, response.Warnings[0].Code)
+	assert.Equal(t, "remotes[0].url", response.Warnings[0].Path)
+	assert.Contains(t, response.Warnings[0].Message, "is already used by server com.example/weather")
+}
+
+func TestPublishEndpoint_IfVersionNewer(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:            "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		EnableRegistryValidation: false,
+	}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, registryService, cfg)
+
+	token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+		AuthMethod:  auth.MethodNone,
+		Permissions: []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "*"}},
+	})
+	require.NoError(t, err)
+
+	publish := func(t *testing.T, version string) v0.ServerWithWarnings {
+		t.Helper()
+		body, err := json.Marshal(apiv0.ServerJSON{
+			Name:        "io.modelcontextprotocol.anonymous/nightly",
+			Description: "Rebuilt every night",
+			Version:     version,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/v0/publish", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("If-Version-Newer", "true")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response v0.ServerWithWarnings
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("first publish", func(t *testing.T) {
+		response := publish(t, "1.1.0")
+		assert.False(t, response.Skipped)
+		assert.True(t, response.Meta.Official.IsLatest)
+	})
+
+	t.Run("newer version", func(t *testing.T) {
+		response := publish(t, "1.2.0")
+		assert.False(t, response.Skipped)
+		assert.Equal(t, "1.2.0", response.Version)
+		assert.True(t, response.Meta.Official.IsLatest)
+	})
+
+	t.Run("equal version is skipped", func(t *testing.T) {
+		response := publish(t, "1.2.0")
+		assert.True(t, response.Skipped)
+		assert.Equal(t, "1.2.0", response.Version)
+	})
+
+	t.Run("older version is skipped", func(t *testing.T) {
+		response := publish(t, "1.0.0")
+		assert.True(t, response.Skipped)
+		assert.Equal(t, "1.2.0", response.Version, "the body is the current latest version")
+
+		name := "io.modelcontextprotocol.anonymous/nightly"
+		count, err := registryService.Count(context.Background(), &database.ServerFilter{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count, "nothing was published")
+	})
+}
@@ -0,0 +1,100 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestServerReadmeEndpoint(t *testing.T) {
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{})
+
+	withReadme, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        "com.example/with-readme",
+		Description: "A server with a README",
+		Version:     "1.0.0",
+		Readme:      "# Usage\n\nRun it <script>alert(1)</script>with **care**.",
+	})
+	require.NoError(t, err)
+	withoutReadme, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        "com.example/without-readme",
+		Description: "A server without a README",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	serve := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	readmePath := "/v0/servers/" + withReadme.Meta.Official.ID + "/readme"
+
+	t.Run("serves sanitized markdown by default", func(t *testing.T) {
+		w := serve(readmePath, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+		assert.Equal(t, "# Usage\n\nRun it with **care**.", w.Body.String())
+	})
+
+	t.Run("serves HTML when preferred", func(t *testing.T) {
+		w := serve(readmePath, "text/html,text/markdown;q=0.9")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Security-Policy"), "default-src 'none'")
+		assert.Equal(t, "<h1>Usage</h1>\n<p>Run it with <strong>care</strong>.</p>\n", w.Body.String())
+
+		w = serve(readmePath, "text/markdown,text/html;q=0.5")
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("not found without a README", func(t *testing.T) {
+		w := serve("/v0/servers/"+withoutReadme.Meta.Official.ID+"/readme", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serve("/v0/servers/00000000-0000-0000-0000-000000000000/readme", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("list flags READMEs without including them", func(t *testing.T) {
+		w := serve("/v0/servers", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var list apiv0.ServerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list.Servers, 2)
+		for _, server := range list.Servers {
+			assert.Empty(t, server.Readme)
+			assert.Equal(t, server.Name == "com.example/with-readme", server.Meta.Official.HasReadme, server.Name)
+		}
+	})
+
+	t.Run("detail includes the README", func(t *testing.T) {
+		w := serve("/v0/servers/"+withReadme.Meta.Official.ID, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var server apiv0.ServerJSON
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &server))
+		assert.Equal(t, "# Usage\n\nRun it with **care**.", server.Readme)
+	})
+}
@@ -0,0 +1,71 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// RepairTextInput represents the input for repairing stored text
+type RepairTextInput struct {
+	DryRun bool `query:"dry_run" doc:"Only report which versions would change" required:"false"`
+}
+
+// RepairTextBody reports the server versions whose text was repaired
+type RepairTextBody struct {
+	DryRun   bool                 `json:"dry_run" doc:"Whether the versions were only reported, not changed"`
+	Count    int                  `json:"count" doc:"Number of versions repaired, or that would be"`
+	Versions []service.TextRepair `json:"versions" doc:"Repaired versions and the fields that changed"`
+}
+
+// BackfillSearchKeywordsBody reports how many server versions got search keywords
+type BackfillSearchKeywordsBody struct {
+	Count int `json:"count" doc:"Number of server versions whose search keywords were stored"`
+}
+
+// RegisterRepairEndpoints registers the admin endpoints that repair stored servers: normalizing
+// their text and backfilling their search keywords
+func RegisterRepairEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "repair-text",
+		Method:      http.MethodPost,
+		Path:        "/v0/admin/repair-text",
+		Summary:     "Repair stored text",
+		Description: "Normalize the text of every stored server version the way publishing does: to NFC, without zero-width and bidirectional control characters in names, titles and descriptions, and without invalid UTF-8 (admin only)",
+		Tags:        []string{"admin"},
+	}, Permission{Action: auth.PermissionActionEdit, Resource: "*"}), func(ctx context.Context, input *RepairTextInput) (*Response[RepairTextBody], error) {
+		repairs, err := registry.RepairText(ctx, input.DryRun)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to repair stored text")
+		}
+
+		return &Response[RepairTextBody]{
+			Body: RepairTextBody{
+				DryRun:   input.DryRun,
+				Count:    len(repairs),
+				Versions: repairs,
+			},
+		}, nil
+	})
+
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "backfill-search-keywords",
+		Method:      http.MethodPost,
+		Path:        "/v0/admin/search-keywords",
+		Summary:     "Backfill search keywords",
+		Description: "Store the search keywords of server versions published before keywords were, so search finds them by package identifier, repository and description as well as by name (admin only)",
+		Tags:        []string{"admin"},
+	}, Permission{Action: auth.PermissionActionEdit, Resource: "*"}), func(ctx context.Context, _ *struct{}) (*Response[BackfillSearchKeywordsBody], error) {
+		count, err := registry.BackfillSearchKeywords(ctx)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to backfill search keywords")
+		}
+		return &Response[BackfillSearchKeywordsBody]{Body: BackfillSearchKeywordsBody{Count: count}}, nil
+	})
+}
@@ -0,0 +1,265 @@
+package v0
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ReportServerInput represents the input for reporting a server version
+type ReportServerInput struct {
+	ID   string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+	Body struct {
+		Category string `json:"category" doc:"What is wrong with the server" enum:"broken-package,dead-remote,malware,spam,other" example:"dead-remote"`
+		Text     string `json:"text,omitempty" doc:"What you found, only shown to registry admins" maxLength:"2000" example:"The remote answers every request with 502 Bad Gateway"`
+	}
+
+	remoteAddr   string
+	forwardedFor string
+}
+
+// Resolve keeps the addresses the client is identified by for rate limiting
+func (i *ReportServerInput) Resolve(ctx huma.Context) []error {
+	i.remoteAddr = ctx.RemoteAddr()
+	i.forwardedFor = ctx.Header("X-Forwarded-For")
+	return nil
+}
+
+// ServerReportReceipt acknowledges a server report
+type ServerReportReceipt struct {
+	ID        string    `json:"id" doc:"Report ID"`
+	ServerID  string    `json:"server_id"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NamespaceReportsInput represents the input for the report counts of a namespace
+type NamespaceReportsInput struct {
+	Namespace string `path:"namespace" doc:"Namespace, the part of server names before the slash" pattern:"^[a-zA-Z0-9.-]+$" example:"com.example"`
+}
+
+// ServerReportsBody lists the report counts of server versions
+type ServerReportsBody struct {
+	Servers []service.ServerReports `json:"servers" doc:"Reported server versions, by name"`
+}
+
+// ListReportsInput represents the input for listing reported server versions
+type ListReportsInput struct {
+	NeedsAttention bool `query:"needs_attention" doc:"Only list versions whose reports in a category reached the report threshold" required:"false"`
+}
+
+// ServerReportsInput represents the input for the reports about a server version
+type ServerReportsInput struct {
+	ID string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+}
+
+// ServerReport is one report about a server version, as shown to admins
+type ServerReport struct {
+	ID        string    `json:"id"`
+	Category  string    `json:"category"`
+	Text      string    `json:"text,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ServerReportDetailsBody is the reports about a server version, as shown to admins
+type ServerReportDetailsBody struct {
+	service.ServerReports
+	Reports []ServerReport `json:"reports" doc:"Every report, newest first"`
+}
+
+// RegisterReportEndpoints registers the endpoints for reporting broken or abusive server
+// versions, and for namespace owners and admins to review the reports
+func RegisterReportEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+	limiter := newReportLimiter(cfg.ReportRateLimit, cfg.ReportRateWindow)
+
+	huma.Register(api, Public(huma.Operation{
+		OperationID:   "report-server",
+		Method:        http.MethodPost,
+		Path:          "/v0/servers/{id}/reports",
+		Summary:       "Report a server version",
+		Description:   "Report a server version as broken or abusive. The text is only shown to registry admins; the namespace owners see how many reports each category has, and are notified when a category reaches the registry's threshold. Reports are limited per client address.",
+		Tags:          []string{"servers"},
+		DefaultStatus: http.StatusCreated,
+		Errors:        []int{http.StatusTooManyRequests},
+	}), func(ctx context.Context, input *ReportServerInput) (*Response[ServerReportReceipt], error) {
+		if ok, retryAfter := limiter.allow(clientIP(input.remoteAddr, input.forwardedFor, cfg.TrustForwardedFor), time.Now()); !ok {
+			return nil, &CodedError{
+				ErrorModel: huma.ErrorModel{
+					Title:  http.StatusText(http.StatusTooManyRequests),
+					Status: http.StatusTooManyRequests,
+					Detail: "Too many reports from this address, try again later",
+				},
+				Code:    apiv0.ErrorCodeRateLimited,
+				headers: http.Header{"Retry-After": {strconv.Itoa(int(retryAfter.Round(time.Second).Seconds()))}},
+			}
+		}
+
+		report, err := registry.ReportServer(ctx, input.ID, input.Body.Category, input.Body.Text)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to report server")
+		}
+
+		return &Response[ServerReportReceipt]{
+			Body: ServerReportReceipt{
+				ID:        report.ID,
+				ServerID:  report.ServerID,
+				Category:  report.Category,
+				CreatedAt: report.CreatedAt,
+			},
+		}, nil
+	})
+
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "get-namespace-reports",
+		Method:      http.MethodGet,
+		Path:        "/v0/namespaces/{namespace}/reports",
+		Summary:     "Get namespace report counts",
+		Description: "How often users reported each server version under a namespace, per category. The text of the reports is only shown to admins. Requires publish permission for every server in the namespace.",
+		Tags:        []string{"namespaces"},
+	}, Permission{Action: auth.PermissionActionPublish, Resource: "{namespace}/*"}), func(ctx context.Context, input *NamespaceReportsInput) (*Response[ServerReportsBody], error) {
+		servers, err := registry.ServerReportSummaries(ctx, database.ServerReportFilter{Namespace: input.Namespace})
+		if err != nil {
+			return nil, serviceError(err, "Namespace", http.StatusInternalServerError, "Failed to get namespace reports")
+		}
+		return &Response[ServerReportsBody]{Body: ServerReportsBody{Servers: servers}}, nil
+	})
+
+	// Reports name servers of every namespace, and their text may say anything
+	globalEdit := Permission{Action: auth.PermissionActionEdit, Resource: "*"}
+
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "list-server-reports",
+		Method:      http.MethodGet,
+		Path:        "/v0/admin/reports",
+		Summary:     "List reported server versions",
+		Description: "List every reported server version with its report counts per category (admin only). Versions with needs_attention set have a category at the report threshold.",
+		Tags:        []string{"admin"},
+	}, globalEdit), func(ctx context.Context, input *ListReportsInput) (*Response[ServerReportsBody], error) {
+		servers, err := registry.ServerReportSummaries(ctx, database.ServerReportFilter{})
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to list server reports")
+		}
+		if input.NeedsAttention {
+			flagged := []service.ServerReports{}
+			for _, server := range servers {
+				if server.NeedsAttention {
+					flagged = append(flagged, server)
+				}
+			}
+			servers = flagged
+		}
+		return &Response[ServerReportsBody]{Body: ServerReportsBody{Servers: servers}}, nil
+	})
+
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "get-server-reports",
+		Method:      http.MethodGet,
+		Path:        "/v0/admin/servers/{id}/reports",
+		Summary:     "Get the reports about a server version",
+		Description: "Get every report about a server version, with its text (admin only)",
+		Tags:        []string{"admin"},
+	}, globalEdit), func(ctx context.Context, input *ServerReportsInput) (*Response[ServerReportDetailsBody], error) {
+		summaries, err := registry.ServerReportSummaries(ctx, database.ServerReportFilter{ServerID: input.ID})
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get server reports")
+		}
+		if len(summaries) == 0 {
+			return nil, huma.Error404NotFound("No reports about this server")
+		}
+		reports, err := registry.ListServerReports(ctx, input.ID)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get server reports")
+		}
+
+		body := ServerReportDetailsBody{ServerReports: summaries[0], Reports: make([]ServerReport, 0, len(reports))}
+		for _, report := range reports {
+			body.Reports = append(body.Reports, ServerReport{
+				ID:        report.ID,
+				Category:  report.Category,
+				Text:      report.Text,
+				CreatedAt: report.CreatedAt,
+			})
+		}
+		return &Response[ServerReportDetailsBody]{Body: body}, nil
+	})
+}
+
+// clientIP returns the address a request came from: the last X-Forwarded-For entry, added by
+// the proxy in front of the registry, when it is trusted, or else the connection's address
+func clientIP(remoteAddr, forwardedFor string, trustForwardedFor bool) string {
+	if trustForwardedFor && forwardedFor != "" {
+		entries := strings.Split(forwardedFor, ",")
+		return strings.TrimSpace(entries[len(entries)-1])
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// reportLimiter allows each client address limit reports per fixed window. Counts are kept in
+// memory, so each replica limits separately.
+type reportLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	clients   map[string]*reportWindow
+	nextSweep time.Time
+}
+
+// reportWindow counts the reports from one client address since start
+type reportWindow struct {
+	start time.Time
+	count int
+}
+
+// newReportLimiter creates a limiter; a limit of 0 allows every report
+func newReportLimiter(limit int, window time.Duration) *reportLimiter {
+	return &reportLimiter{limit: limit, window: window, clients: make(map[string]*reportWindow)}
+}
+
+// allow counts a report from ip at now, reporting whether it is within the limit, and if not,
+// how long until the client may report again
+func (l *reportLimiter) allow(ip string, now time.Time) (bool, time.Duration) {
+	if l.limit <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget clients whose window has passed, so the map does not grow without bound
+	if now.After(l.nextSweep) {
+		for client, window := range l.clients {
+			if now.Sub(window.start) >= l.window {
+				delete(l.clients, client)
+			}
+		}
+		l.nextSweep = now.Add(l.window)
+	}
+
+	window, ok := l.clients[ip]
+	if !ok || now.Sub(window.start) >= l.window {
+		window = &reportWindow{start: now}
+		l.clients[ip] = window
+	}
+	if window.count >= l.limit {
+		return false, window.start.Add(l.window).Sub(now)
+	}
+	window.count++
+	return true, 0
+}
@@ -0,0 +1,136 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestReportEndpoints(t *testing.T) {
+	cfg := &config.Config{
+		JWTPrivateKey:     "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		ReportRateLimit:   2,
+		ReportRateWindow:  time.Hour,
+		ReportThreshold:   2,
+		TrustForwardedFor: true,
+	}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+	published, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        "io.github.octocat/weather",
+		Description: "Weather lookups",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+	id := published.Meta.Official.ID
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterReportEndpoints(api, registryService, cfg)
+
+	report := func(body, forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v0/servers/"+id+"/reports", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	get := func(path string, permissions ...auth.Permission) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if permissions != nil {
+			token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+				AuthMethod:        auth.MethodGitHubAT,
+				AuthMethodSubject: "octocat",
+				Permissions:       permissions,
+			})
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	owner := auth.Permission{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.octocat/*"}
+	admin := auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "*"}
+
+	t.Run("rejects unknown categories", func(t *testing.T) {
+		w := report(`{"category": "boring"}`, "192.0.2.1")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	})
+
+	t.Run("limits reports per address", func(t *testing.T) {
+		for range 2 {
+			w := report(`{"category": "dead-remote", "text": "502 Bad Gateway"}`, "198.51.100.7, 203.0.113.5")
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+			var receipt v0.ServerReportReceipt
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &receipt))
+			assert.Equal(t, id, receipt.ServerID)
+			assert.NotContains(t, w.Body.String(), "502 Bad Gateway")
+		}
+
+		w := report(`{"category": "dead-remote"}`, "198.51.100.8, 203.0.113.5")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code, "the proxy's last entry identifies the client")
+		assert.Contains(t, w.Body.String(), apiv0.ErrorCodeRateLimited)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		w = report(`{"category": "spam"}`, "203.0.113.9")
+		assert.Equal(t, http.StatusCreated, w.Code, "other addresses have their own limit")
+	})
+
+	t.Run("report counts require a token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, get("/v0/namespaces/io.github.octocat/reports").Code)
+		assert.Equal(t, http.StatusUnauthorized, get("/v0/admin/reports").Code)
+	})
+
+	t.Run("namespace owners see counts without text", func(t *testing.T) {
+		w := get("/v0/namespaces/io.github.octocat/reports", owner)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body v0.ServerReportsBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Servers, 1)
+		assert.Equal(t, map[string]int{"dead-remote": 2, "spam": 1}, body.Servers[0].Categories)
+		assert.True(t, body.Servers[0].NeedsAttention)
+		assert.NotContains(t, w.Body.String(), "502 Bad Gateway")
+
+		assert.Equal(t, http.StatusForbidden, get("/v0/namespaces/io.github.octocat/reports", auth.Permission{
+			Action: auth.PermissionActionPublish, ResourcePattern: "io.github.octocat-fan/*",
+		}).Code)
+		assert.Equal(t, http.StatusForbidden, get("/v0/admin/reports", owner).Code)
+		assert.Equal(t, http.StatusForbidden, get("/v0/admin/servers/"+id+"/reports", owner).Code)
+	})
+
+	t.Run("admins see every report with its text", func(t *testing.T) {
+		w := get("/v0/admin/reports?needs_attention=true", admin)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var list v0.ServerReportsBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list.Servers, 1)
+		assert.Equal(t, id, list.Servers[0].ID)
+
+		w = get("/v0/admin/servers/"+id+"/reports", admin)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var details v0.ServerReportDetailsBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+		assert.Equal(t, 3, details.Total)
+		require.Len(t, details.Reports, 3)
+		assert.Contains(t, w.Body.String(), "502 Bad Gateway")
+
+		assert.Equal(t, http.StatusNotFound, get("/v0/admin/servers/00000000-0000-0000-0000-000000000000/reports", admin).Code)
+	})
+}
@@ -0,0 +1,122 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// ReserveNamespaceInput represents the input for reserving a namespace
+type ReserveNamespaceInput struct {
+	Namespace string `path:"namespace" doc:"Namespace to reserve, or a prefix ending in * to reserve every namespace starting with it" pattern:"^[a-zA-Z0-9.-]+\\*?$" example:"io.modelcontextprotocol.*"`
+	Body      struct {
+		AllowedSubjects []string `json:"allowed_subjects,omitempty" doc:"Identities that may still publish under the namespace, as <auth method>:<subject>, where a trailing * matches any suffix" example:"[\"github-oidc:repo:modelcontextprotocol/*\"]"`
+		Reason          string   `json:"reason,omitempty" doc:"Why the namespace is reserved, shown to publishers who are refused" maxLength:"1000"`
+	}
+}
+
+// UnreserveNamespaceInput represents the input for removing a namespace reservation
+type UnreserveNamespaceInput struct {
+	Namespace string `path:"namespace" doc:"Reserved namespace or prefix, as it was reserved"`
+}
+
+// NamespaceReservation is a namespace reservation as returned by the API
+type NamespaceReservation struct {
+	Namespace       string    `json:"namespace"`
+	AllowedSubjects []string  `json:"allowed_subjects"`
+	Reason          string    `json:"reason,omitempty"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// NamespaceReservationList is the response body for listing namespace reservations
+type NamespaceReservationList struct {
+	Reservations []NamespaceReservation `json:"reservations"`
+}
+
+// RegisterReservationEndpoints registers the admin endpoints for managing reserved namespaces
+func RegisterReservationEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// Reservations override the publish permissions of every token, so only global admins manage them
+	globalEdit := Permission{Action: auth.PermissionActionEdit, Resource: "*"}
+
+	// List namespace reservations endpoint
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "list-namespace-reservations",
+		Method:      http.MethodGet,
+		Path:        "/v0/admin/namespace-reservations",
+		Summary:     "List reserved namespaces",
+		Description: "List the reserved namespaces and the identities allowed to publish under each (admin only)",
+		Tags:        []string{"admin"},
+	}, globalEdit), func(ctx context.Context, _ *struct{}) (*Response[NamespaceReservationList], error) {
+		reservations, err := registry.ListNamespaceReservations(ctx)
+		if err != nil {
+			return nil, serviceError(err, "Reservation", http.StatusInternalServerError, "Failed to list namespace reservations")
+		}
+
+		body := NamespaceReservationList{Reservations: make([]NamespaceReservation, len(reservations))}
+		for i, reservation := range reservations {
+			body.Reservations[i] = namespaceReservation(reservation)
+		}
+		return &Response[NamespaceReservationList]{
+			Body: body,
+		}, nil
+	})
+
+	// Reserve namespace endpoint
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "reserve-namespace",
+		Method:      http.MethodPut,
+		Path:        "/v0/admin/namespace-reservations/{namespace}",
+		Summary:     "Reserve a namespace",
+		Description: "Reserve a namespace, or every namespace with a prefix, so that only the allowed identities can publish under it, whatever their token's permissions. Replaces any earlier reservation of the same namespace (admin only).",
+		Tags:        []string{"admin"},
+	}, globalEdit), func(ctx context.Context, input *ReserveNamespaceInput) (*Response[NamespaceReservation], error) {
+		reservation, err := registry.ReserveNamespace(ctx, input.Namespace, input.Body.AllowedSubjects, input.Body.Reason, ClaimsFromContext(ctx).AuthMethodSubject)
+		if err != nil {
+			return nil, serviceError(err, "Reservation", http.StatusInternalServerError, "Failed to reserve namespace")
+		}
+
+		return &Response[NamespaceReservation]{
+			Body: namespaceReservation(reservation),
+		}, nil
+	})
+
+	// Unreserve namespace endpoint
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID:   "unreserve-namespace",
+		Method:        http.MethodDelete,
+		Path:          "/v0/admin/namespace-reservations/{namespace}",
+		Summary:       "Remove a namespace reservation",
+		Description:   "Remove a namespace reservation, so that publishing under it only depends on token permissions again (admin only)",
+		Tags:          []string{"admin"},
+		DefaultStatus: http.StatusNoContent,
+	}, globalEdit), func(ctx context.Context, input *UnreserveNamespaceInput) (*struct{}, error) {
+		if err := registry.UnreserveNamespace(ctx, input.Namespace); err != nil {
+			return nil, serviceError(err, "Reservation", http.StatusInternalServerError, "Failed to remove namespace reservation")
+		}
+		return &struct{}{}, nil
+	})
+}
+
+// namespaceReservation converts a stored reservation to its API representation
+func namespaceReservation(reservation *database.NamespaceReservation) NamespaceReservation {
+	allowedSubjects := reservation.AllowedSubjects
+	if allowedSubjects == nil {
+		allowedSubjects = []string{}
+	}
+	return NamespaceReservation{
+		Namespace:       reservation.Namespace,
+		AllowedSubjects: allowedSubjects,
+		Reason:          reservation.Reason,
+		CreatedBy:       reservation.CreatedBy,
+		CreatedAt:       reservation.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package v0_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+func TestReservationEndpoints(t *testing.T) {
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterReservationEndpoints(api, registryService, cfg)
+	v0.RegisterPublishEndpoint(api, registryService, cfg)
+
+	tokenFor := func(subject string, permissions ...auth.Permission) string {
+		token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: subject,
+			Permissions:       permissions,
+		})
+		require.NoError(t, err)
+		return token
+	}
+	admin := tokenFor("admin", auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "*"})
+	publisher := auth.Permission{Action: auth.PermissionActionPublish, ResourcePattern: "*"}
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	publish := func(token string) *httptest.ResponseRecorder {
+		return do(http.MethodPost, "/v0/publish", token, `{"name": "com.acme/tools", "description": "Acme tools", "version": "1.0.0"}`)
+	}
+
+	t.Run("requires global edit permission", func(t *testing.T) {
+		w := do(http.MethodPut, "/v0/admin/namespace-reservations/com.acme", tokenFor("octocat", publisher), `{}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("reserve and list", func(t *testing.T) {
+		w := do(http.MethodPut, "/v0/admin/namespace-reservations/com.acme", admin,
+			`{"allowed_subjects": ["github-at:acme-*"], "reason": "Reserved for Acme teams"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = do(http.MethodGet, "/v0/admin/namespace-reservations", admin, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var list v0.NamespaceReservationList
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list.Reservations, 1)
+		assert.Equal(t, "com.acme", list.Reservations[0].Namespace)
+		assert.Equal(t, []string{"github-at:acme-*"}, list.Reservations[0].AllowedSubjects)
+		assert.Equal(t, "admin", list.Reservations[0].CreatedBy)
+	})
+
+	t.Run("publish is refused to other identities", func(t *testing.T) {
+		w := publish(tokenFor("octocat", publisher))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "Reserved for Acme teams")
+	})
+
+	t.Run("allowed subjects can publish", func(t *testing.T) {
+		w := publish(tokenFor("acme-bot", publisher))
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	t.Run("invalid allowed subject", func(t *testing.T) {
+		w := do(http.MethodPut, "/v0/admin/namespace-reservations/com.acme", admin, `{"allowed_subjects": ["acme-bot"]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unreserve", func(t *testing.T) {
+		w := do(http.MethodDelete, "/v0/admin/namespace-reservations/com.acme", admin, "")
+		assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+		w = do(http.MethodDelete, "/v0/admin/namespace-reservations/com.acme", admin, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = do(http.MethodPost, "/v0/publish", tokenFor("octocat", publisher), `{"name": "com.acme/other", "description": "Acme tools", "version": "1.0.0"}`)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,43 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/pkg/launch"
+)
+
+// ResolveServerInput represents the input for resolving a server into launch plans
+type ResolveServerInput struct {
+	ID string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+	// Body is optional: without it, any runtime and platform is assumed and remotes are included
+	Body *launch.Capabilities
+}
+
+// RegisterResolveEndpoint registers the endpoint turning a server's packages and remotes into
+// launch plans
+func RegisterResolveEndpoint(api huma.API, registry service.RegistryService) {
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "resolve-server",
+		Method:      http.MethodPost,
+		Path:        "/v0/servers/{id}/resolve",
+		Summary:     "Resolve MCP server launch plans",
+		Description: "Turn a server's packages and remotes into concrete launch plans for a client: the command and arguments to start a package, or the URL and headers of a remote, most preferred first. Packages needing a runtime the client lacks, and MCPB bundles built for another platform, are skipped. Values the user must supply are left as {name} placeholders and listed as inputs. Clients embedding Go can compute the same plans offline with the pkg/launch package.",
+		Tags:        []string{"servers"},
+	}), func(ctx context.Context, input *ResolveServerInput) (*Response[launch.Resolution], error) {
+		serverDetail, err := registry.GetByID(ctx, input.ID)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get server details")
+		}
+		if serverDetail.Status.Hidden() {
+			return nil, huma.Error404NotFound("Server not found")
+		}
+		var capabilities launch.Capabilities
+		if input.Body != nil {
+			capabilities = *input.Body
+		}
+		return &Response[launch.Resolution]{Body: launch.Resolve(*serverDetail, capabilities)}, nil
+	})
+}
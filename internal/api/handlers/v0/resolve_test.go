@@ -0,0 +1,87 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/launch"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestResolveEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+	published, err := registryService.Publish(ctx, apiv0.ServerJSON{
+		Name: "com.example/weather", Description: "Weather tools", Version: "1.0.0",
+		Packages: []model.Package{{
+			RegistryType: model.RegistryTypeNPM, Identifier: "@example/weather", Version: "1.0.0",
+			Transport: model.Transport{Type: model.TransportTypeStdio},
+			EnvironmentVariables: []model.KeyValueInput{{Name: "API_KEY", InputWithVariables: model.InputWithVariables{
+				Input: model.Input{Description: "Weather API key", IsRequired: true, IsSecret: true},
+			}}},
+		}},
+		Remotes: []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: "https://weather.example.com/mcp"}},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterResolveEndpoint(api, registryService)
+
+	resolve := func(id, body string) (int, launch.Resolution) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/v0/servers/"+id+"/resolve", strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var resolution launch.Resolution
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resolution))
+		}
+		return w.Code, resolution
+	}
+
+	t.Run("without capabilities", func(t *testing.T) {
+		status, resolution := resolve(published.Meta.Official.ID, "")
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, resolution.Plans, 2)
+		assert.Equal(t, launch.Plan{
+			Kind: launch.KindPackage, Option: "npm @example/weather", Transport: "stdio",
+			Command: "npx", Args: []string{"-y", "@example/weather@1.0.0"},
+			Env:    map[string]string{"API_KEY": "{API_KEY}"},
+			Inputs: []launch.PlanInput{{Name: "API_KEY", Location: "environment_variables", Description: "Weather API key", IsSecret: true}},
+		}, resolution.Plans[0])
+		assert.Equal(t, "https://weather.example.com/mcp", resolution.Plans[1].URL)
+	})
+
+	t.Run("with capabilities", func(t *testing.T) {
+		status, resolution := resolve(published.Meta.Official.ID, `{"runtimes": ["uvx"], "exclude_remotes": true}`)
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, resolution.Plans)
+		assert.Equal(t, []launch.Skipped{
+			{Option: "npm @example/weather", Reason: "runtime npx is not available"},
+			{Option: "streamable-http https://weather.example.com/mcp", Reason: "remotes are excluded"},
+		}, resolution.Skipped)
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		status, _ := resolve("6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90", "{}")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
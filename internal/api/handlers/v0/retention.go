@@ -0,0 +1,108 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// RetentionPreviewInput represents the input for previewing the retention policy
+type RetentionPreviewInput struct {
+	KeepVersions int `query:"keep_versions" doc:"Override the number of newest versions kept per server" required:"false" minimum:"0" example:"10"`
+	KeepDays     int `query:"keep_days" doc:"Override the age in days under which versions are always kept" required:"false" minimum:"0" example:"30"`
+}
+
+// RetentionPreviewBody is the dry-run report of the retention policy
+type RetentionPreviewBody struct {
+	KeepVersions int                          `json:"keep_versions" doc:"Newest versions kept per server"`
+	KeepDays     int                          `json:"keep_days" doc:"Versions newer than this many days are kept"`
+	Count        int                          `json:"count" doc:"Number of versions that would be removed"`
+	Versions     []service.RetentionCandidate `json:"versions" doc:"Versions that would be soft-deleted"`
+}
+
+// PinServerInput represents the input for pinning a server version
+type PinServerInput struct {
+	ID   string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+	Body struct {
+		Pinned bool `json:"pinned" doc:"Whether the version is exempt from retention"`
+	}
+}
+
+// RegisterRetentionEndpoints registers the retention dry-run and pin endpoints
+func RegisterRetentionEndpoints(api huma.API, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// Retention dry-run endpoint
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "preview-retention",
+		Method:      http.MethodGet,
+		Path:        "/v0/admin/retention",
+		Summary:     "Preview version retention",
+		Description: "Report which server versions the retention policy would soft-delete, without changing anything (admin only)",
+		Tags:        []string{"admin"},
+	}, Permission{Action: auth.PermissionActionEdit, Resource: "*"}), func(ctx context.Context, input *RetentionPreviewInput) (*Response[RetentionPreviewBody], error) {
+		keepVersions := cfg.RetentionKeepVersions
+		if input.KeepVersions > 0 {
+			keepVersions = input.KeepVersions
+		}
+		keepDays := cfg.RetentionKeepDays
+		if input.KeepDays > 0 {
+			keepDays = input.KeepDays
+		}
+		if keepVersions < 1 {
+			return nil, huma.Error400BadRequest("Retention is not configured; pass keep_versions to preview a policy")
+		}
+
+		policy := service.RetentionPolicy{
+			KeepVersions: keepVersions,
+			KeepWithin:   time.Duration(keepDays) * 24 * time.Hour,
+		}
+		candidates, err := registry.ApplyRetention(ctx, policy, true)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to evaluate retention policy", err)
+		}
+
+		return &Response[RetentionPreviewBody]{
+			Body: RetentionPreviewBody{
+				KeepVersions: keepVersions,
+				KeepDays:     keepDays,
+				Count:        len(candidates),
+				Versions:     candidates,
+			},
+		}, nil
+	})
+
+	// Pin server version endpoint
+	huma.Register(api, RequireAuth(api, jwtManager, huma.Operation{
+		OperationID: "pin-server",
+		Method:      http.MethodPut,
+		Path:        "/v0/servers/{id}/pin",
+		Summary:     "Pin MCP server version",
+		Description: "Exempt a server version from version retention, or remove the exemption (admin only)",
+		Tags:        []string{"admin"},
+	}, Permission{Action: auth.PermissionActionEdit}), func(ctx context.Context, input *PinServerInput) (*Response[apiv0.ServerJSON], error) {
+		currentServer, err := registry.GetByID(ctx, input.ID)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get current server")
+		}
+
+		if !jwtManager.HasPermission(currentServer.Name, auth.PermissionActionEdit, ClaimsFromContext(ctx).Permissions) {
+			return nil, huma.Error403Forbidden("You do not have edit permissions for this server")
+		}
+
+		updatedServer, err := registry.SetPinned(ctx, input.ID, input.Body.Pinned)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to update server")
+		}
+
+		return &Response[apiv0.ServerJSON]{
+			Body: *updatedServer,
+		}, nil
+	})
+}
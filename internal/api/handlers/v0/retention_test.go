@@ -0,0 +1,118 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestRetentionEndpoints(t *testing.T) {
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	var ids []string
+	for i := 0; i < 4; i++ {
+		published, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+			Name:        "io.github.domdomegg/nightly",
+			Description: "Nightly builds",
+			Version:     fmt.Sprintf("1.0.%d", i),
+		})
+		require.NoError(t, err)
+		ids = append(ids, published.Meta.Official.ID)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterRetentionEndpoints(api, registryService, cfg)
+
+	tokenFor := func(pattern string) string {
+		token, err := generateTestJWTToken(cfg, auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "domdomegg",
+			Permissions: []auth.Permission{
+				{Action: auth.PermissionActionEdit, ResourcePattern: pattern},
+			},
+		})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	adminToken := tokenFor("*")
+	namespaceToken := tokenFor("io.github.domdomegg/*")
+
+	serve := func(method, path, authHeader string, body any) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			var err error
+			data, err = json.Marshal(body)
+			require.NoError(t, err)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("preview requires edit permission on all servers", func(t *testing.T) {
+		w := serve(http.MethodGet, "/v0/admin/retention?keep_versions=1", namespaceToken, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("preview without a configured policy", func(t *testing.T) {
+		w := serve(http.MethodGet, "/v0/admin/retention", adminToken, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("pin requires edit permission on the server", func(t *testing.T) {
+		w := serve(http.MethodPut, "/v0/servers/"+ids[0]+"/pin", tokenFor("io.github.other/*"), map[string]bool{"pinned": true})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("pinned versions are excluded from the preview", func(t *testing.T) {
+		w := serve(http.MethodPut, "/v0/servers/"+ids[0]+"/pin", namespaceToken, map[string]bool{"pinned": true})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var pinned apiv0.ServerJSON
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&pinned))
+		assert.True(t, pinned.Meta.Official.Pinned)
+
+		w = serve(http.MethodGet, "/v0/admin/retention?keep_versions=1", adminToken, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var preview v0.RetentionPreviewBody
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&preview))
+		assert.Equal(t, 1, preview.KeepVersions)
+		assert.Equal(t, 2, preview.Count)
+		var versions []string
+		for _, candidate := range preview.Versions {
+			versions = append(versions, candidate.Version)
+		}
+		assert.ElementsMatch(t, []string{"1.0.1", "1.0.2"}, versions)
+
+		// Preview is a dry run
+		for _, id := range ids {
+			server, err := registryService.GetByID(context.Background(), id)
+			require.NoError(t, err)
+			assert.Empty(t, server.Status)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+)
+
+// SecurityScheme names the Registry JWT bearer scheme in the OpenAPI document
+const SecurityScheme = "bearer"
+
+// pathParamRegex matches the {param} references in a permission's resource
+var pathParamRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// claimsKey is the context key under which AuthMiddleware stores validated claims
+type claimsKey struct{}
+
+// Permission is what an operation requires of the caller's Registry JWT. Resource may refer to
+// path parameters, as in "{namespace}/*", and "*" means every server. An empty Resource only
+// requires the action on some server, for operations whose handler checks the server it acts
+// on once it has looked it up.
+type Permission struct {
+	Action   auth.PermissionAction
+	Resource string
+}
+
+// Scope names the permission in the operation's OpenAPI security requirement, such as
+// "edit:*" or "publish:{namespace}/*"
+func (p Permission) Scope() string {
+	if p.Resource == "" {
+		return string(p.Action)
+	}
+	return string(p.Action) + ":" + p.Resource
+}
+
+// resolve fills the path parameters of the permission's resource in from the request
+func (p Permission) resolve(ctx huma.Context) string {
+	return pathParamRegex.ReplaceAllStringFunc(p.Resource, func(param string) string {
+		return ctx.Param(param[1 : len(param)-1])
+	})
+}
+
+// grantedBy reports whether claims hold the permission for the request
+func (p Permission) grantedBy(ctx huma.Context, jwtManager *auth.JWTManager, claims *auth.JWTClaims) bool {
+	if p.Resource != "" {
+		return jwtManager.HasPermission(p.resolve(ctx), p.Action, claims.Permissions)
+	}
+	for _, perm := range claims.Permissions {
+		if perm.Action.Grants(p.Action) {
+			return true
+		}
+	}
+	return false
+}
+
+// denied is the 403 message for a token without the permission
+func (p Permission) denied(ctx huma.Context) string {
+	switch p.Resource {
+	case "":
+		return fmt.Sprintf("You do not have %s permission for any server", p.Action)
+	case "*":
+		return fmt.Sprintf("You do not have %s permissions for all servers", p.Action)
+	default:
+		return fmt.Sprintf("You do not have %s permission for %s", p.Action, p.resolve(ctx))
+	}
+}
+
+// RequireAuth declares that op needs a Registry JWT holding any one of permissions. The scopes
+// are listed in the operation's OpenAPI security requirement, and AuthMiddleware enforces them,
+// leaving the claims for the handler in ClaimsFromContext.
+func RequireAuth(api huma.API, jwtManager *auth.JWTManager, op huma.Operation, permissions ...Permission) huma.Operation {
+	scopes := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		scopes = append(scopes, permission.Scope())
+	}
+	op.Security = []map[string][]string{{SecurityScheme: scopes}}
+	op.Errors = append(op.Errors, http.StatusUnauthorized, http.StatusForbidden)
+	op.Middlewares = append(op.Middlewares, AuthMiddleware(api, jwtManager, permissions...))
+	return op
+}
+
+// Public declares that op needs no authentication, with an empty security requirement
+func Public(op huma.Operation) huma.Operation {
+	op.Security = []map[string][]string{{}}
+	return op
+}
+
+// AuthMiddleware validates the Registry JWT in the Authorization header and checks it holds
+// any one of permissions, answering 401 or 403 if not. Otherwise the claims are passed on in
+// the request context.
+func AuthMiddleware(api huma.API, jwtManager *auth.JWTManager, permissions ...Permission) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		const bearerPrefix = "Bearer "
+		authHeader := ctx.Header("Authorization")
+		if authHeader == "" {
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "Missing Authorization header. Expected 'Bearer <token>'")
+			return
+		}
+		if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "Invalid Authorization header format. Expected 'Bearer <token>'")
+			return
+		}
+		claims, err := jwtManager.ValidateToken(ctx.Context(), authHeader[len(bearerPrefix):])
+		switch {
+		case errors.Is(err, auth.ErrTokenExpired):
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "Registry JWT token has expired. Log in again for a new one")
+			return
+		case errors.Is(err, auth.ErrTokenNotYetValid):
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "Registry JWT token is not valid yet. Check that the clocks of the registry and this machine are correct")
+			return
+		case err != nil:
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "Invalid or expired Registry JWT token", err)
+			return
+		}
+
+		granted := len(permissions) == 0
+		for _, permission := range permissions {
+			if permission.grantedBy(ctx, jwtManager, claims) {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			_ = huma.WriteErr(api, ctx, http.StatusForbidden, permissions[0].denied(ctx))
+			return
+		}
+
+		next(huma.WithValue(ctx, claimsKey{}, claims))
+	}
+}
+
+// ClaimsFromContext returns the claims AuthMiddleware validated for the request, or nil for
+// operations that don't require authentication
+func ClaimsFromContext(ctx context.Context) *auth.JWTClaims {
+	claims, _ := ctx.Value(claimsKey{}).(*auth.JWTClaims)
+	return claims
+}
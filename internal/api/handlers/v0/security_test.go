@@ -0,0 +1,135 @@
+package v0_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	jwtManager := auth.NewJWTManager(cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+
+	// Each operation answers with the subject of the claims the middleware passed on
+	type subjectOutput struct {
+		Subject string `header:"X-Subject"`
+	}
+	handler := func(ctx context.Context, _ *struct{}) (*subjectOutput, error) {
+		return &subjectOutput{Subject: v0.ClaimsFromContext(ctx).AuthMethodSubject}, nil
+	}
+	register := func(operationID, path string, permissions ...v0.Permission) {
+		huma.Register(api, v0.RequireAuth(api, jwtManager, huma.Operation{
+			OperationID: operationID,
+			Method:      http.MethodGet,
+			Path:        path,
+		}, permissions...), handler)
+	}
+	register("global-edit", "/global", v0.Permission{Action: auth.PermissionActionEdit, Resource: "*"})
+	register("namespace-publish", "/namespaces/{namespace}", v0.Permission{Action: auth.PermissionActionPublish, Resource: "{namespace}/*"})
+	register("any-publish-version", "/publish", v0.Permission{Action: auth.PermissionActionPublishVersion})
+	register("publish-or-edit", "/either",
+		v0.Permission{Action: auth.PermissionActionPublish},
+		v0.Permission{Action: auth.PermissionActionEdit, Resource: "*"})
+	huma.Register(api, v0.Public(huma.Operation{
+		OperationID: "public",
+		Method:      http.MethodGet,
+		Path:        "/public",
+	}), func(ctx context.Context, _ *struct{}) (*subjectOutput, error) {
+		assert.Nil(t, v0.ClaimsFromContext(ctx))
+		return &subjectOutput{}, nil
+	})
+
+	adminPermission := auth.Permission{Action: auth.PermissionActionEdit, ResourcePattern: "*"}
+	tokenWithClaims := func(claims auth.JWTClaims) string {
+		claims.AuthMethod = auth.MethodGitHubAT
+		claims.AuthMethodSubject = "octocat"
+		token, err := generateTestJWTToken(cfg, claims)
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	tokenWith := func(permissions ...auth.Permission) string {
+		return tokenWithClaims(auth.JWTClaims{Permissions: permissions})
+	}
+	adminToken := tokenWith(adminPermission)
+	publisherToken := tokenWith(auth.Permission{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.octocat/*"})
+	versionToken := tokenWith(auth.Permission{Action: auth.PermissionActionPublishVersion, ResourcePattern: "io.github.octocat/weather"})
+	expiredToken := tokenWithClaims(auth.JWTClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour))},
+		Permissions:      []auth.Permission{adminPermission},
+	})
+	futureToken := tokenWithClaims(auth.JWTClaims{
+		RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		Permissions:      []auth.Permission{adminPermission},
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		authHeader     string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"missing header", "/global", "", http.StatusUnauthorized, "Missing Authorization header"},
+		{"not a bearer token", "/global", "Basic b2N0b2NhdDpzZWNyZXQ=", http.StatusUnauthorized, "Invalid Authorization header format"},
+		{"invalid token", "/global", "Bearer not-a-jwt", http.StatusUnauthorized, "Invalid or expired Registry JWT token"},
+		{"expired token", "/global", expiredToken, http.StatusUnauthorized, "Registry JWT token has expired"},
+		{"token from the future", "/global", futureToken, http.StatusUnauthorized, "Registry JWT token is not valid yet"},
+		{"bearer prefix is case-insensitive", "/global", "bearer" + adminToken[len("Bearer"):], http.StatusNoContent, ""},
+		{"global permission granted", "/global", adminToken, http.StatusNoContent, ""},
+		{"global permission denied", "/global", publisherToken, http.StatusForbidden, "You do not have edit permissions for all servers"},
+		{"path parameter granted", "/namespaces/io.github.octocat", publisherToken, http.StatusNoContent, ""},
+		{"path parameter denied", "/namespaces/io.github.hubot", publisherToken, http.StatusForbidden, "You do not have publish permission for io.github.hubot/*"},
+		{"single server grant is not the namespace", "/namespaces/io.github.octocat", versionToken, http.StatusForbidden, ""},
+		{"some server granted", "/publish", versionToken, http.StatusNoContent, ""},
+		{"broader action grants narrower", "/publish", publisherToken, http.StatusNoContent, ""},
+		{"some server denied", "/publish", adminToken, http.StatusForbidden, "You do not have publish_version permission for any server"},
+		{"first alternative", "/either", publisherToken, http.StatusNoContent, ""},
+		{"second alternative", "/either", adminToken, http.StatusNoContent, ""},
+		{"no alternative", "/either", versionToken, http.StatusForbidden, "You do not have publish permission for any server"},
+		{"public without token", "/public", "", http.StatusNoContent, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+			if tt.expectedStatus == http.StatusNoContent && tt.path != "/public" {
+				assert.Equal(t, "octocat", w.Header().Get("X-Subject"), "claims are passed to the handler")
+			}
+		})
+	}
+
+	t.Run("operations document their scopes", func(t *testing.T) {
+		paths := api.OpenAPI().Paths
+		assert.Equal(t, []map[string][]string{{"bearer": {"edit:*"}}}, paths["/global"].Get.Security)
+		assert.Equal(t, []map[string][]string{{"bearer": {"publish:{namespace}/*"}}}, paths["/namespaces/{namespace}"].Get.Security)
+		assert.Equal(t, []map[string][]string{{"bearer": {"publish", "edit:*"}}}, paths["/either"].Get.Security)
+		assert.Contains(t, paths["/global"].Get.Responses, "401")
+		assert.Contains(t, paths["/global"].Get.Responses, "403")
+		assert.Equal(t, []map[string][]string{{}}, paths["/public"].Get.Security)
+	})
+}
@@ -0,0 +1,109 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestServerDocumentEndpoint(t *testing.T) {
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+	published, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        "io.github.example/document",
+		Description: "Tools for <documents> & more",
+		Version:     "1.0.0",
+		Repository: model.Repository{
+			URL:    "https://github.com/example/document",
+			Source: "github",
+		},
+		Meta: &apiv0.ServerMeta{
+			PublisherProvided: map[string]interface{}{"tool": "ci", "build": "42"},
+		},
+	})
+	require.NoError(t, err)
+	id := published.Meta.Official.ID
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	fetch := func(id string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+id+"/server.json", nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	first := fetch(id, nil)
+	require.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	assert.Equal(t, "application/json", first.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="server.json"`, first.Header().Get("Content-Disposition"))
+	etag := first.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{64}"$`, etag)
+
+	t.Run("repeated fetches are byte-for-byte identical", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			again := fetch(id, nil)
+			require.Equal(t, http.StatusOK, again.Code)
+			assert.Equal(t, first.Body.Bytes(), again.Body.Bytes())
+			assert.Equal(t, etag, again.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("registry metadata does not leak into the document", func(t *testing.T) {
+		assert.NotContains(t, first.Body.String(), "io.modelcontextprotocol.registry/official")
+		assert.NotContains(t, first.Body.String(), id)
+
+		var document map[string]any
+		require.NoError(t, json.Unmarshal(first.Body.Bytes(), &document))
+		assert.Equal(t, map[string]any{
+			"io.modelcontextprotocol.registry/publisher-provided": map[string]any{"tool": "ci", "build": "42"},
+		}, document["_meta"])
+		assert.Equal(t, "Tools for <documents> & more", document["description"])
+		assert.Contains(t, first.Body.String(), "<documents> & more", "HTML characters are not escaped")
+	})
+
+	t.Run("matching If-None-Match is not modified", func(t *testing.T) {
+		w := fetch(id, http.Header{"If-None-Match": {etag}})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.Bytes())
+
+		w = fetch(id, http.Header{"If-None-Match": {`"stale"`}})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("editing the server changes the ETag", func(t *testing.T) {
+		edited := *published
+		edited.Description = "Edited description"
+		edited.Meta = &apiv0.ServerMeta{PublisherProvided: published.Meta.PublisherProvided}
+		_, err := registryService.EditServer(context.Background(), id, edited)
+		require.NoError(t, err)
+
+		w := fetch(id, http.Header{"If-None-Match": {etag}})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), "Edited description")
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		w := fetch("00000000-0000-0000-0000-000000000000", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,138 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestServerHeadAndExists(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	publish := func(version string) *apiv0.ServerJSON {
+		published, err := registryService.Publish(ctx, apiv0.ServerJSON{
+			Name:        "io.github.acme/foo",
+			Description: "Foo tools",
+			Version:     version,
+		})
+		require.NoError(t, err)
+		return published
+	}
+	older := publish("1.1.0")
+	latest := publish("1.2.0")
+
+	// A version held for admin approval is hidden from both
+	pending := publish("1.0.1")
+	held := *pending
+	meta := *pending.Meta
+	official := *meta.Official
+	meta.Official = &official
+	held.Meta = &meta
+	held.Status = model.StatusPending
+	_, err := db.UpdateServer(ctx, official.ID, &held)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	// A real server, since only it drops the body of a response to HEAD
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	type response struct {
+		Code   int
+		Header http.Header
+		Body   []byte
+	}
+	serve := func(method, target string) response {
+		req, err := http.NewRequestWithContext(ctx, method, server.URL+target, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return response{Code: resp.StatusCode, Header: resp.Header, Body: body}
+	}
+
+	t.Run("HEAD matches GET", func(t *testing.T) {
+		for name, id := range map[string]string{
+			"latest version":  latest.Meta.Official.ID,
+			"older version":   older.Meta.Official.ID,
+			"pending version": official.ID,
+			"unknown id":      "00000000-0000-0000-0000-000000000000",
+			"malformed id":    "not-a-uuid",
+		} {
+			t.Run(name, func(t *testing.T) {
+				get := serve(http.MethodGet, "/v0/servers/"+id)
+				head := serve(http.MethodHead, "/v0/servers/"+id)
+
+				assert.Equal(t, get.Code, head.Code)
+				assert.Empty(t, head.Body)
+				for _, header := range []string{"ETag", "Last-Modified"} {
+					assert.Equal(t, get.Header.Get(header), head.Header.Get(header), header)
+				}
+				if get.Code == http.StatusOK {
+					assert.NotEmpty(t, head.Header.Get("ETag"))
+					assert.NotEmpty(t, head.Header.Get("Last-Modified"))
+				}
+			})
+		}
+	})
+
+	t.Run("ETag changes when the server is edited", func(t *testing.T) {
+		before := serve(http.MethodHead, "/v0/servers/"+older.Meta.Official.ID).Header.Get("ETag")
+		edited := *older
+		edited.Description = "Edited"
+		edited.Meta = nil
+		_, err := registryService.EditServer(ctx, older.Meta.Official.ID, edited)
+		require.NoError(t, err)
+
+		after := serve(http.MethodHead, "/v0/servers/"+older.Meta.Official.ID)
+		assert.NotEqual(t, before, after.Header.Get("ETag"))
+		assert.Equal(t, serve(http.MethodGet, "/v0/servers/"+older.Meta.Official.ID).Header.Get("ETag"), after.Header.Get("ETag"))
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			query string
+			want  v0.ServerExistsBody
+		}{
+			"latest version":        {"name=io.github.acme/foo&version=1.2.0", v0.ServerExistsBody{Exists: true, IsLatest: true, ID: latest.Meta.Official.ID}},
+			"older version":         {"name=io.github.acme/foo&version=1.1.0", v0.ServerExistsBody{Exists: true, IsLatest: false, ID: older.Meta.Official.ID}},
+			"unknown version":       {"name=io.github.acme/foo&version=9.9.9", v0.ServerExistsBody{Exists: false}},
+			"pending version":       {"name=io.github.acme/foo&version=1.0.1", v0.ServerExistsBody{Exists: false}},
+			"unknown server":        {"name=io.github.acme/bar", v0.ServerExistsBody{Exists: false}},
+			"any version is latest": {"name=io.github.acme/foo", v0.ServerExistsBody{Exists: true, IsLatest: true, ID: latest.Meta.Official.ID}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				w := serve(http.MethodGet, "/v0/servers/exists?"+tc.query)
+				require.Equal(t, http.StatusOK, w.Code, string(w.Body))
+				var body v0.ServerExistsBody
+				require.NoError(t, json.Unmarshal(w.Body, &body))
+				assert.Equal(t, tc.want, body)
+			})
+		}
+
+		w := serve(http.MethodGet, "/v0/servers/exists")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, "name is required")
+	})
+}
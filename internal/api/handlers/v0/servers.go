@@ -1,24 +1,91 @@
 package v0
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/markdown"
 	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/configschema"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
 // ListServersInput represents the input for listing servers
 type ListServersInput struct {
-	Cursor       string `query:"cursor" doc:"Pagination cursor (UUID)" format:"uuid" required:"false" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Limit        int    `query:"limit" doc:"Number of items per page" default:"30" minimum:"1" maximum:"100" example:"50"`
-	UpdatedSince string `query:"updated_since" doc:"Filter servers updated since timestamp (RFC3339 datetime)" required:"false" example:"2025-08-07T13:15:04.280Z"`
-	Search       string `query:"search" doc:"Search servers by name (substring match)" required:"false" example:"filesystem"`
-	Version      string `query:"version" doc:"Filter by version ('latest' for latest version, or an exact version like '1.2.3')" required:"false" example:"latest"`
+	Cursor                   string `query:"cursor" doc:"Pagination cursor (UUID)" format:"uuid" required:"false" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Limit                    int    `query:"limit" doc:"Number of items per page, at most 100; larger values are clamped" default:"30" minimum:"1" example:"50"`
+	UpdatedSince             string `query:"updated_since" doc:"Incremental sync: return servers changed at or after this timestamp (RFC3339 datetime), oldest change first, with deleted versions as tombstones" required:"false" example:"2025-08-07T13:15:04.280Z"`
+	Search                   string `query:"search" doc:"Search servers by name (substring match), or by keywords from their name, package identifiers, repository path and description, which must all match. Servers whose name matches are listed first." required:"false" example:"filesystem"`
+	Version                  string `query:"version" doc:"Filter by version ('latest' for latest version, or an exact version like '1.2.3')" required:"false" example:"latest"`
+	RegistryType             string `query:"registry_type" doc:"Filter to servers with at least one package from this registry type" required:"false" example:"npm"`
+	RuntimeHint              string `query:"runtime_hint" doc:"Filter to servers with at least one package with this runtime hint; combined with registry_type, the same package must match both" required:"false" example:"npx"`
+	License                  string `query:"license" doc:"Filter to servers whose license expression includes this SPDX license identifier (case-insensitive)" required:"false" example:"MIT"`
+	CompatibleWith           string `query:"compatible_with" doc:"Filter to servers whose declared mcpVersion is no newer than this MCP protocol revision (YYYY-MM-DD). Servers that declare none are included unless exclude_unknown_mcp_version is set." required:"false" example:"2025-03-26"`
+	ExcludeUnknownMCPVersion bool   `query:"exclude_unknown_mcp_version" doc:"Leave out servers that declare no mcpVersion" required:"false"`
+	Fields                   string `query:"fields" doc:"Projection of each server: 'full' for complete server.json documents, 'summary' for name, description, version, status, title, repository URL, first icon and registry metadata" enum:"full,summary" default:"full" example:"summary"`
+}
+
+// Resolve clamps the page size to MaxPageSize
+func (i *ListServersInput) Resolve(ctx huma.Context) []error {
+	clampPageSize(ctx, &i.Limit)
+	return nil
+}
+
+// ListServersOutput is the server list response
+type ListServersOutput struct {
+	LastModified time.Time `header:"Last-Modified" doc:"Newest change among the returned servers"`
+	Link         string    `header:"Link" doc:"RFC 8288 pagination links: first, and next when there are more results"`
+	// Body is an apiv0.ServerListResponse, or an apiv0.ServerSummaryListResponse for fields=summary
+	Body any
+}
+
+// ServerDetailOutput is the server details response
+type ServerDetailOutput struct {
+	LastModified time.Time `header:"Last-Modified" doc:"When the server's registry metadata last changed"`
+	ETag         string    `header:"ETag" doc:"Changes whenever the server version changes"`
+	Body         apiv0.ServerJSON
+}
+
+// ServerExistsInput represents the input for checking whether a server version exists
+type ServerExistsInput struct {
+	Name    string `query:"name" doc:"Server name" required:"true" minLength:"1" example:"io.github.acme/weather"`
+	Version string `query:"version" doc:"Exact version to look for; omit to look for any version" required:"false" example:"1.2.0"`
+}
+
+// ServerExistsBody reports whether a server version exists
+type ServerExistsBody struct {
+	Exists   bool   `json:"exists"`
+	IsLatest bool   `json:"is_latest" doc:"Whether the version found is the server's latest"`
+	ID       string `json:"id,omitempty" doc:"Registry ID of the version found: the requested version, or the latest one if no version was given"`
+}
+
+// ServerExistsOutput is the existence check response
+type ServerExistsOutput struct {
+	Body ServerExistsBody
+}
+
+// serverETag identifies a server version as of its last change. It is derived from registry
+// metadata alone, so HEAD requests can answer without loading the document.
+func serverETag(id string, lastModified time.Time) string {
+	// Microseconds, the precision PostgreSQL stores timestamps with
+	return `W/"` + id + "-" + strconv.FormatInt(lastModified.UnixMicro(), 36) + `"`
 }
 
 // ServerDetailInput represents the input for getting server details
@@ -26,27 +93,145 @@ type ServerDetailInput struct {
 	ID string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
 }
 
+// ServerReadmeInput represents the input for getting a server's README
+type ServerReadmeInput struct {
+	ID     string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+	Accept string `header:"Accept" doc:"Send text/html (ranked above text/markdown) for the README rendered as HTML" required:"false"`
+}
+
+// ServerReadmeOutput is the README response, as markdown or rendered HTML
+type ServerReadmeOutput struct {
+	ContentType           string    `header:"Content-Type"`
+	ContentSecurityPolicy string    `header:"Content-Security-Policy"`
+	Vary                  string    `header:"Vary"`
+	LastModified          time.Time `header:"Last-Modified" doc:"When the server's registry metadata last changed"`
+	Body                  []byte
+}
+
+// ServerDocumentInput represents the input for downloading a server's published server.json
+type ServerDocumentInput struct {
+	ID          string `path:"id" doc:"Server ID (UUID)" format:"uuid"`
+	IfNoneMatch string `header:"If-None-Match" doc:"ETag of a copy the client already has" required:"false"`
+}
+
+// ServerDocumentOutput is the published server.json, without registry metadata
+type ServerDocumentOutput struct {
+	Status             int
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	ETag               string `header:"ETag" doc:"Hash of the document, stable for as long as the server version is unchanged"`
+	Body               []byte
+}
+
+// ServerConfigSchemaOutput is the JSON Schema for a server's configuration
+type ServerConfigSchemaOutput struct {
+	ContentType  string    `header:"Content-Type"`
+	LastModified time.Time `header:"Last-Modified" doc:"When the server's registry metadata last changed"`
+	Body         []byte
+}
+
+// publishedDocument renders a server as the server.json its publisher sent: registry metadata
+// is dropped, fields keep their schema order and the output is indented for diffing
+func publishedDocument(server *apiv0.ServerJSON) ([]byte, error) {
+	document := *server
+	if server.Meta != nil {
+		meta := *server.Meta
+		meta.Official = nil
+		document.Meta = &meta
+		if meta.PublisherProvided == nil {
+			document.Meta = nil
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// prefersHTML reports whether an Accept header ranks text/html above text/markdown
+func prefersHTML(accept string) bool {
+	quality := map[string]float64{}
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		quality[mediaType] = q
+	}
+	return quality["text/html"] > quality["text/markdown"]
+}
+
+// paginationLinks builds the RFC 8288 Link header for a list page, keeping the request's
+// filters and page size so generic HTTP clients can page without reading the body
+func paginationLinks(input *ListServersInput, nextCursor string) string {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(input.Limit))
+	for name, value := range map[string]string{
+		"updated_since":   input.UpdatedSince,
+		"search":          input.Search,
+		"version":         input.Version,
+		"registry_type":   input.RegistryType,
+		"runtime_hint":    input.RuntimeHint,
+		"license":         input.License,
+		"compatible_with": input.CompatibleWith,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if input.ExcludeUnknownMCPVersion {
+		query.Set("exclude_unknown_mcp_version", "true")
+	}
+
+	if input.Fields != "" && input.Fields != "full" {
+		query.Set("fields", input.Fields)
+	}
+
+	links := []string{fmt.Sprintf(`</v0/servers?%s>; rel="first"`, query.Encode())}
+	if nextCursor != "" {
+		query.Set("cursor", nextCursor)
+		links = append(links, fmt.Sprintf(`</v0/servers?%s>; rel="next"`, query.Encode()))
+	}
+	return strings.Join(links, ", ")
+}
+
 // RegisterServersEndpoints registers all server-related endpoints
 func RegisterServersEndpoints(api huma.API, registry service.RegistryService) {
+	// The list body depends on the fields parameter, so document both shapes
+	schemas := api.OpenAPI().Components.Schemas
+	listSchema := &huma.Schema{OneOf: []*huma.Schema{
+		schemas.Schema(reflect.TypeOf(apiv0.ServerListResponse{}), true, ""),
+		schemas.Schema(reflect.TypeOf(apiv0.ServerSummaryListResponse{}), true, ""),
+	}}
+
 	// List servers endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, Public(huma.Operation{
 		OperationID: "list-servers",
 		Method:      http.MethodGet,
 		Path:        "/v0/servers",
 		Summary:     "List MCP servers",
-		Description: "Get a paginated list of MCP servers from the registry",
+		Description: "Get a paginated list of MCP servers from the registry. Each entry includes its title and only its first icon; fetch server details for the full icon list. Use fields=summary for a compact ServerSummaryListResponse without packages or remotes.",
 		Tags:        []string{"servers"},
-	}, func(_ context.Context, input *ListServersInput) (*Response[apiv0.ServerListResponse], error) {
-		// Validate cursor if provided
-		if input.Cursor != "" {
-			_, err := uuid.Parse(input.Cursor)
-			if err != nil {
-				return nil, huma.Error400BadRequest("Invalid cursor parameter")
-			}
-		}
-
-		// Build filter from input parameters
-		filter := &database.ServerFilter{}
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "OK",
+				Content:     map[string]*huma.MediaType{"application/json": {Schema: listSchema}},
+			},
+		},
+	}), func(ctx context.Context, input *ListServersInput) (*ListServersOutput, error) {
+		// Build filter from input parameters; versions held for admin approval are never listed
+		filter := &database.ServerFilter{ExcludeHidden: true}
 
 		// Parse updated_since parameter
 		if input.UpdatedSince != "" {
@@ -60,7 +245,41 @@ func RegisterServersEndpoints(api huma.API, registry service.RegistryService) {
 
 		// Handle search parameter
 		if input.Search != "" {
-			filter.SubstringName = &input.Search
+			if err := validateSearch(input.Search); err != nil {
+				return nil, err
+			}
+			filter.Search = &input.Search
+		}
+
+		// Handle package filters
+		if input.RegistryType != "" {
+			filter.RegistryType = &input.RegistryType
+		}
+		if input.RuntimeHint != "" {
+			filter.RuntimeHint = &input.RuntimeHint
+		}
+
+		// Handle license filter, which takes a single identifier rather than an expression
+		if input.License != "" {
+			ids, err := validators.ParseLicenseExpression(input.License)
+			if err != nil || len(ids) != 1 || strings.ContainsAny(input.License, " ()") {
+				return nil, huma.Error400BadRequest("Invalid license parameter: expected a single SPDX license identifier (e.g., MIT)")
+			}
+			filter.License = &ids[0]
+		}
+
+		// Handle protocol compatibility filters; clients may know revisions the registry doesn't yet
+		if input.CompatibleWith != "" {
+			if _, err := time.Parse(time.DateOnly, input.CompatibleWith); err != nil {
+				return nil, huma.Error400BadRequest("Invalid compatible_with parameter: expected an MCP protocol revision date (e.g., 2025-03-26)")
+			}
+			filter.CompatibleWith = &input.CompatibleWith
+		}
+		filter.ExcludeUnknownMCPVersion = input.ExcludeUnknownMCPVersion
+
+		// Summaries only need a few fields from each server
+		if input.Fields == "summary" {
+			filter.Projection = database.ProjectionSummary
 		}
 
 		// Handle version parameter
@@ -76,42 +295,319 @@ func RegisterServersEndpoints(api huma.API, registry service.RegistryService) {
 		}
 
 		// Get paginated results with filtering
-		servers, nextCursor, err := registry.List(filter, input.Cursor, input.Limit)
+		servers, nextCursor, err := registry.List(ctx, filter, input.Cursor, input.Limit)
 		if err != nil {
-			return nil, huma.Error500InternalServerError("Failed to get registry list", err)
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get registry list")
+		}
+		total, err := registry.Count(ctx, filter)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to count registry entries")
 		}
 
-		return &Response[apiv0.ServerListResponse]{
-			Body: apiv0.ServerListResponse{
-				Servers: servers,
-				Metadata: apiv0.Metadata{
-					NextCursor: nextCursor,
-					Count:      len(servers),
+		// Incremental sync reports deleted versions as minimal tombstones
+		var tombstones []apiv0.ServerTombstone
+		if filter.UpdatedSince != nil {
+			live := servers[:0]
+			for _, server := range servers {
+				if server.Status == model.StatusDeleted {
+					tombstones = append(tombstones, apiv0.ServerTombstone{
+						Name:      server.Name,
+						Version:   server.Version,
+						DeletedAt: server.LastModified(),
+					})
+					continue
+				}
+				live = append(live, server)
+			}
+			servers = live
+		}
+
+		// List entries carry only the preferred icon and no README; both are on the detail endpoint.
+		// Release notes stay, so that listing a server's versions shows what changed in each.
+		var lastModified time.Time
+		for i := range servers {
+			if len(servers[i].Icons) > 1 {
+				servers[i].Icons = servers[i].Icons[:1]
+			}
+			servers[i].Readme = ""
+			if modified := servers[i].LastModified(); modified.After(lastModified) {
+				lastModified = modified
+			}
+		}
+		for _, tombstone := range tombstones {
+			if tombstone.DeletedAt.After(lastModified) {
+				lastModified = tombstone.DeletedAt
+			}
+		}
+
+		metadata := apiv0.Metadata{
+			NextCursor: nextCursor,
+			Count:      len(servers),
+			Total:      total,
+		}
+		if !lastModified.IsZero() {
+			metadata.MaxUpdatedAt = &lastModified
+		}
+
+		if filter.Projection == database.ProjectionSummary {
+			summaries := make([]apiv0.ServerSummary, len(servers))
+			for i := range servers {
+				summaries[i] = servers[i].Summary()
+			}
+			return &ListServersOutput{
+				LastModified: lastModified,
+				Link:         paginationLinks(input, nextCursor),
+				Body: apiv0.ServerSummaryListResponse{
+					Servers:    summaries,
+					Tombstones: tombstones,
+					Metadata:   metadata,
 				},
+			}, nil
+		}
+
+		for i := range servers {
+			servers[i] = withBadges(servers[i])
+		}
+		return &ListServersOutput{
+			LastModified: lastModified,
+			Link:         paginationLinks(input, nextCursor),
+			Body: apiv0.ServerListResponse{
+				Servers:    servers,
+				Tombstones: tombstones,
+				Metadata:   metadata,
 			},
 		}, nil
 	})
 
 	// Get server details endpoint
-	huma.Register(api, huma.Operation{
+	huma.Register(api, Public(huma.Operation{
 		OperationID: "get-server",
 		Method:      http.MethodGet,
 		Path:        "/v0/servers/{id}",
 		Summary:     "Get MCP server details",
-		Description: "Get detailed information about a specific MCP server",
+		Description: "Get detailed information about a specific MCP server. HEAD requests get the same status code and headers without loading the server document.",
 		Tags:        []string{"servers"},
-	}, func(_ context.Context, input *ServerDetailInput) (*Response[apiv0.ServerJSON], error) {
+		Middlewares: huma.Middlewares{headServerMiddleware(api, registry)},
+	}), func(ctx context.Context, input *ServerDetailInput) (*ServerDetailOutput, error) {
 		// Get the server details from the registry service
-		serverDetail, err := registry.GetByID(input.ID)
+		serverDetail, err := registry.GetByID(ctx, input.ID)
 		if err != nil {
-			if err.Error() == "record not found" {
-				return nil, huma.Error404NotFound("Server not found")
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get server details")
+		}
+		if serverDetail.Status.Hidden() {
+			return nil, huma.Error404NotFound("Server not found")
+		}
+
+		body := withBadges(*serverDetail)
+		if body.Meta != nil && body.Meta.Official != nil {
+			// withBadges copied the registry metadata, so it can be filled in
+			origin, forks, err := registry.ForkLineage(ctx, serverDetail)
+			if err != nil {
+				return nil, huma.Error500InternalServerError("Failed to get server details", err)
 			}
-			return nil, huma.Error500InternalServerError("Failed to get server details", err)
+			body.Meta.Official.ForkOrigin, body.Meta.Official.Forks = origin, forks
+		}
+
+		return &ServerDetailOutput{
+			LastModified: serverDetail.LastModified(),
+			ETag:         serverETag(input.ID, serverDetail.LastModified()),
+			Body:         body,
+		}, nil
+	})
+
+	// Server existence check endpoint
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "server-exists",
+		Method:      http.MethodGet,
+		Path:        "/v0/servers/exists",
+		Summary:     "Check whether a server version exists",
+		Description: "Check whether a server, or a specific version of it, has been published, without fetching its document",
+		Tags:        []string{"servers"},
+	}), func(ctx context.Context, input *ServerExistsInput) (*ServerExistsOutput, error) {
+		head, err := registry.FindHead(ctx, input.Name, input.Version)
+		if errors.Is(err, database.ErrNotFound) {
+			return &ServerExistsOutput{Body: ServerExistsBody{Exists: false}}, nil
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to check server", err)
+		}
+		return &ServerExistsOutput{Body: ServerExistsBody{Exists: true, IsLatest: head.IsLatest, ID: head.ID}}, nil
+	})
+
+	// Get server README endpoint
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "get-server-readme",
+		Method:      http.MethodGet,
+		Path:        "/v0/servers/{id}/readme",
+		Summary:     "Get MCP server README",
+		Description: "Get a server's sanitized README as markdown, or rendered as HTML when the Accept header prefers text/html. List responses only report whether a README exists, as has_readme in the registry metadata.",
+		Tags:        []string{"servers"},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "OK",
+				Content: map[string]*huma.MediaType{
+					"text/markdown": {Schema: &huma.Schema{Type: huma.TypeString}},
+					"text/html":     {Schema: &huma.Schema{Type: huma.TypeString}},
+				},
+			},
+		},
+	}), func(ctx context.Context, input *ServerReadmeInput) (*ServerReadmeOutput, error) {
+		serverDetail, err := registry.GetByID(ctx, input.ID)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get server details")
+		}
+		if serverDetail.Status.Hidden() {
+			return nil, huma.Error404NotFound("Server not found")
+		}
+		if serverDetail.Readme == "" {
+			return nil, huma.Error404NotFound("Server has no README")
+		}
+
+		output := &ServerReadmeOutput{
+			ContentType:  "text/markdown; charset=utf-8",
+			Vary:         "Accept",
+			LastModified: serverDetail.LastModified(),
+			Body:         []byte(serverDetail.Readme),
+		}
+		if prefersHTML(input.Accept) {
+			// The rendered HTML has no scripts or styles of its own, so forbid everything but images
+			output.ContentType = "text/html; charset=utf-8"
+			output.ContentSecurityPolicy = "default-src 'none'; img-src https: http:"
+			output.Body = []byte(markdown.ToHTML(serverDetail.Readme))
+		}
+		return output, nil
+	})
+
+	// Download published server.json endpoint
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "get-server-document",
+		Method:      http.MethodGet,
+		Path:        "/v0/servers/{id}/server.json",
+		Summary:     "Download published server.json",
+		Description: "Get exactly the server.json document that was published, without the registry metadata, for diffing against a local copy. Repeated fetches return identical bytes while the version is unchanged.",
+		Tags:        []string{"servers"},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "OK",
+				Content: map[string]*huma.MediaType{
+					"application/json": {Schema: api.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(apiv0.ServerJSON{}), true, "")},
+				},
+			},
+		},
+	}), func(ctx context.Context, input *ServerDocumentInput) (*ServerDocumentOutput, error) {
+		serverDetail, err := registry.GetByID(ctx, input.ID)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get server details")
+		}
+		if serverDetail.Status.Hidden() {
+			return nil, huma.Error404NotFound("Server not found")
+		}
+
+		document, err := publishedDocument(serverDetail)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to render server.json", err)
+		}
+		hash := sha256.Sum256(document)
+		etag := `"` + hex.EncodeToString(hash[:]) + `"`
+
+		output := &ServerDocumentOutput{
+			Status:             http.StatusOK,
+			ContentType:        "application/json",
+			ContentDisposition: `attachment; filename="server.json"`,
+			ETag:               etag,
+			Body:               document,
 		}
+		if etagMatches(input.IfNoneMatch, etag) {
+			output.Status = http.StatusNotModified
+			output.Body = nil
+		}
+		return output, nil
+	})
 
-		return &Response[apiv0.ServerJSON]{
-			Body: *serverDetail,
+	// Configuration JSON Schema endpoint
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "get-server-config-schema",
+		Method:      http.MethodGet,
+		Path:        "/v0/servers/{id}/config-schema",
+		Summary:     "Get MCP server configuration schema",
+		Description: "Get a JSON Schema describing what a user supplies to run the server: environment variables, arguments, header values and templated remote URL variables, grouped under their server.json field names. A server with several packages or remotes gets one alternative per package or remote under oneOf.",
+		Tags:        []string{"servers"},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "OK",
+				Content: map[string]*huma.MediaType{
+					"application/schema+json": {Schema: api.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(configschema.Schema{}), true, "")},
+				},
+			},
+		},
+	}), func(ctx context.Context, input *ServerDetailInput) (*ServerConfigSchemaOutput, error) {
+		serverDetail, err := registry.GetByID(ctx, input.ID)
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to get server details")
+		}
+		if serverDetail.Status.Hidden() {
+			return nil, huma.Error404NotFound("Server not found")
+		}
+
+		schema, err := json.Marshal(configschema.Generate(*serverDetail))
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to render configuration schema", err)
+		}
+		return &ServerConfigSchemaOutput{
+			ContentType:  "application/schema+json",
+			LastModified: serverDetail.LastModified(),
+			Body:         schema,
 		}, nil
 	})
 }
+
+// withBadges returns server with its badges in the registry metadata. The metadata is copied,
+// since the server may be shared with the service's caches.
+func withBadges(server apiv0.ServerJSON) apiv0.ServerJSON {
+	if server.Meta == nil || server.Meta.Official == nil {
+		return server
+	}
+	official := *server.Meta.Official
+	official.Badges = server.ComputeBadges()
+	server.Meta = &apiv0.ServerMeta{Official: &official, PublisherProvided: server.Meta.PublisherProvided}
+	return server
+}
+
+// headServerMiddleware answers HEAD requests for server details from registry metadata alone,
+// with the status code and headers a GET would have. GET requests, and HEAD requests whose ID
+// GET would reject before looking it up, go to the handler.
+func headServerMiddleware(api huma.API, registry service.RegistryService) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		id := ctx.Param("id")
+		if ctx.Method() != http.MethodHead || uuid.Validate(id) != nil {
+			next(ctx)
+			return
+		}
+
+		head, err := registry.GetHeadByID(ctx.Context(), id)
+		switch {
+		case errors.Is(err, database.ErrNotFound) || (err == nil && head.Status.Hidden()):
+			_ = huma.WriteErr(api, ctx, http.StatusNotFound, "Server not found")
+			return
+		case err != nil:
+			_ = huma.WriteErr(api, ctx, http.StatusInternalServerError, "Failed to get server details", err)
+			return
+		}
+
+		ctx.SetHeader("Content-Type", "application/json")
+		ctx.SetHeader("Last-Modified", head.LastModified.UTC().Format(http.TimeFormat))
+		ctx.SetHeader("ETag", serverETag(id, head.LastModified))
+		ctx.SetStatus(http.StatusOK)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, or is a wildcard
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -3,9 +3,12 @@ package v0_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
@@ -17,6 +20,7 @@ import (
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestServersListEndpoint(t *testing.T) {
@@ -39,7 +43,7 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/test-server-1",
 						Source: "github",
-						ID:     "example/test-server-1",
+						ID:     "100000002",
 					},
 					Version: "1.0.0",
 				}
@@ -49,12 +53,12 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/test-server-2",
 						Source: "github",
-						ID:     "example/test-server-2",
+						ID:     "100000003",
 					},
 					Version: "2.0.0",
 				}
-				_, _ = registry.Publish(server1)
-				_, _ = registry.Publish(server2)
+				_, _ = registry.Publish(context.Background(), server1)
+				_, _ = registry.Publish(context.Background(), server2)
 			},
 			expectedStatus:  http.StatusOK,
 			expectedServers: nil, // Will be verified differently since IDs are dynamic
@@ -69,11 +73,11 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/test-server-3",
 						Source: "github",
-						ID:     "example/test-server-3",
+						ID:     "100000004",
 					},
 					Version: "1.5.0",
 				}
-				_, _ = registry.Publish(server)
+				_, _ = registry.Publish(context.Background(), server)
 			},
 			expectedStatus:  http.StatusOK,
 			expectedServers: nil, // Will be verified differently since IDs are dynamic
@@ -82,8 +86,8 @@ func TestServersListEndpoint(t *testing.T) {
 			name:                 "successful list with limit capping at 100",
 			queryParams:          "?limit=150",
 			setupRegistryService: func(_ service.RegistryService) {},
-			expectedStatus:       http.StatusUnprocessableEntity, // Huma rejects values > maximum
-			expectedError:        "validation failed",
+			expectedStatus:       http.StatusOK, // Clamped to 100 rather than rejected
+			expectedServers:      []apiv0.ServerJSON{},
 		},
 		{
 			name:                 "invalid cursor parameter",
@@ -92,6 +96,13 @@ func TestServersListEndpoint(t *testing.T) {
 			expectedStatus:       http.StatusUnprocessableEntity, // Huma returns 422 for validation errors
 			expectedError:        "validation failed",
 		},
+		{
+			name:                 "unknown cursor",
+			queryParams:          "?cursor=6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90",
+			setupRegistryService: func(_ service.RegistryService) {},
+			expectedStatus:       http.StatusBadRequest,
+			expectedError:        "Invalid cursor parameter",
+		},
 		{
 			name:                 "invalid limit parameter - non-numeric",
 			queryParams:          "?limit=abc",
@@ -131,7 +142,7 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/test-matching",
 						Source: "github",
-						ID:     "example/test-matching",
+						ID:     "100000005",
 					},
 					Version: "1.0.0",
 				}
@@ -141,12 +152,12 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/other",
 						Source: "github",
-						ID:     "example/other",
+						ID:     "100000006",
 					},
 					Version: "1.0.0",
 				}
-				_, _ = registry.Publish(server1)
-				_, _ = registry.Publish(server2)
+				_, _ = registry.Publish(context.Background(), server1)
+				_, _ = registry.Publish(context.Background(), server2)
 			},
 			expectedStatus:  http.StatusOK,
 			expectedServers: nil, // Will verify in test that only matching server is returned
@@ -161,11 +172,11 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/recent",
 						Source: "github",
-						ID:     "example/recent",
+						ID:     "100000007",
 					},
 					Version: "1.0.0",
 				}
-				_, _ = registry.Publish(server)
+				_, _ = registry.Publish(context.Background(), server)
 			},
 			expectedStatus:  http.StatusOK,
 			expectedServers: nil, // Will verify server is returned since it was updated after 2020
@@ -180,7 +191,7 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/versioned",
 						Source: "github",
-						ID:     "example/versioned",
+						ID:     "100000008",
 					},
 					Version: "1.0.0",
 				}
@@ -190,12 +201,12 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/versioned",
 						Source: "github",
-						ID:     "example/versioned",
+						ID:     "100000008",
 					},
 					Version: "2.0.0",
 				}
-				_, _ = registry.Publish(server1)
-				_, _ = registry.Publish(server2) // This will be marked as latest
+				_, _ = registry.Publish(context.Background(), server1)
+				_, _ = registry.Publish(context.Background(), server2) // This will be marked as latest
 			},
 			expectedStatus:  http.StatusOK,
 			expectedServers: nil, // Will verify only latest server is returned
@@ -210,7 +221,7 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/combined",
 						Source: "github",
-						ID:     "example/combined",
+						ID:     "100000009",
 					},
 					Version: "1.0.0",
 				}
@@ -220,12 +231,12 @@ func TestServersListEndpoint(t *testing.T) {
 					Repository: model.Repository{
 						URL:    "https://github.com/example/nomatch",
 						Source: "github",
-						ID:     "example/nomatch",
+						ID:     "100000010",
 					},
 					Version: "1.0.0",
 				}
-				_, _ = registry.Publish(server1)
-				_, _ = registry.Publish(server2)
+				_, _ = registry.Publish(context.Background(), server1)
+				_, _ = registry.Publish(context.Background(), server2)
 			},
 			expectedStatus:  http.StatusOK,
 			expectedServers: nil, // Will verify only matching server is returned
@@ -328,7 +339,7 @@ func TestServersDetailEndpoint(t *testing.T) {
 	// Create mock registry service
 	registryService := service.NewRegistryService(database.NewMemoryDB(), config.NewConfig())
 
-	testServer, err := registryService.Publish(apiv0.ServerJSON{
+	testServer, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
 		Name:        "com.example/test-server",
 		Description: "A test server",
 		Version:     "1.0.0",
@@ -403,6 +414,245 @@ func TestServersDetailEndpoint(t *testing.T) {
 	}
 }
 
+func TestServersEndpoints_Badges(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.EnableRegistryValidation = false
+	registryService := service.NewRegistryService(database.NewMemoryDB(), cfg)
+
+	hashedPackage := model.Package{
+		RegistryType: model.RegistryTypeMCPB,
+		Identifier:   "https://github.com/octocat/weather/releases/download/v1.0.0/weather.mcpb",
+		Version:      "1.0.0",
+		FileSHA256:   "fe333e598595000ae021bd27117db32ec69af6987f507ba7a63c90638ff633ce",
+		Transport:    model.Transport{Type: model.TransportTypeStdio},
+	}
+	unhashedPackage := model.Package{
+		RegistryType: model.RegistryTypeNPM,
+		Identifier:   "@octocat/weather",
+		Version:      "1.0.0",
+		Transport:    model.Transport{Type: model.TransportTypeStdio},
+	}
+
+	testCases := []struct {
+		name     string
+		server   apiv0.ServerJSON
+		expected []apiv0.Badge
+	}{
+		{
+			name:     "domain namespace",
+			server:   apiv0.ServerJSON{Name: "com.example/weather"},
+			expected: []apiv0.Badge{apiv0.BadgeDomainVerified},
+		},
+		{
+			name:     "anonymous namespace",
+			server:   apiv0.ServerJSON{Name: "io.modelcontextprotocol.anonymous/weather"},
+			expected: nil,
+		},
+		{
+			name: "github namespace with its own repository and hashed packages",
+			server: apiv0.ServerJSON{
+				Name:       "io.github.octocat/weather",
+				Repository: model.Repository{URL: "https://github.com/Octocat/weather", Source: "github"},
+				Packages:   []model.Package{hashedPackage},
+			},
+			expected: []apiv0.Badge{apiv0.BadgeAccountVerified, apiv0.BadgeRepositoryMatchesNamespace, apiv0.BadgePackageHashes},
+		},
+		{
+			name: "github namespace with another account's repository",
+			server: apiv0.ServerJSON{
+				Name:       "io.github.octocat/fork",
+				Repository: model.Repository{URL: "https://github.com/octocat-fan/weather", Source: "github"},
+			},
+			expected: []apiv0.Badge{apiv0.BadgeAccountVerified},
+		},
+		{
+			name: "gitlab namespace with its repository",
+			server: apiv0.ServerJSON{
+				Name:       "io.gitlab.acme/weather",
+				Repository: model.Repository{URL: "https://gitlab.com/acme/weather", Source: "gitlab"},
+			},
+			expected: []apiv0.Badge{apiv0.BadgeAccountVerified, apiv0.BadgeRepositoryMatchesNamespace},
+		},
+		{
+			name: "a package without a hash",
+			server: apiv0.ServerJSON{
+				Name:     "com.example/mixed",
+				Packages: []model.Package{hashedPackage, unhashedPackage},
+			},
+			expected: []apiv0.Badge{apiv0.BadgeDomainVerified},
+		},
+	}
+
+	ids := map[string]string{}
+	for _, tc := range testCases {
+		tc.server.Description = "A test server"
+		tc.server.Version = "1.0.0"
+		published, err := registryService.Publish(context.Background(), tc.server)
+		require.NoError(t, err)
+		ids[tc.server.Name] = published.Meta.Official.ID
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+	get := func(path string) []byte {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return w.Body.Bytes()
+	}
+
+	var list apiv0.ServerListResponse
+	require.NoError(t, json.Unmarshal(get("/v0/servers"), &list))
+	listed := map[string][]apiv0.Badge{}
+	for _, server := range list.Servers {
+		listed[server.Name] = server.Meta.Official.Badges
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var detail apiv0.ServerJSON
+			require.NoError(t, json.Unmarshal(get("/v0/servers/"+ids[tc.server.Name]), &detail))
+			assert.Equal(t, tc.expected, detail.Meta.Official.Badges, "detail")
+			assert.Equal(t, tc.expected, listed[tc.server.Name], "list")
+		})
+	}
+
+	t.Run("badges are not stored", func(t *testing.T) {
+		stored, err := registryService.GetByID(context.Background(), ids["com.example/weather"])
+		require.NoError(t, err)
+		assert.Empty(t, stored.Meta.Official.Badges)
+	})
+}
+
+func TestServersListEndpoint_DisplayMetadata(t *testing.T) {
+	registryService := service.NewRegistryService(database.NewMemoryDB(), config.NewConfig())
+
+	icons := []model.Icon{
+		{Src: "https://example.com/icon-48.png", MimeType: "image/png", Sizes: []string{"48x48"}},
+		{Src: "https://example.com/icon.svg", MimeType: "image/svg+xml", Sizes: []string{"any"}},
+	}
+	published, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        "com.example/display-server",
+		Description: "A server with display metadata",
+		Version:     "1.0.0",
+		Title:       "Display Server",
+		Icons:       icons,
+		Categories:  []string{"productivity"},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	// List responses carry the title and only the first icon
+	req := httptest.NewRequest(http.MethodGet, "/v0/servers", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list apiv0.ServerListResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+	require.Len(t, list.Servers, 1)
+	assert.Equal(t, "Display Server", list.Servers[0].Title)
+	assert.Equal(t, icons[:1], list.Servers[0].Icons)
+	assert.Equal(t, []string{"productivity"}, list.Servers[0].Categories)
+
+	// The detail endpoint still returns every icon
+	req = httptest.NewRequest(http.MethodGet, "/v0/servers/"+published.Meta.Official.ID, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var detail apiv0.ServerJSON
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&detail))
+	assert.Equal(t, icons, detail.Icons)
+}
+
+func TestServersListEndpoint_UpdatedSince(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, config.NewConfig())
+
+	checkpoint := time.Date(2025, 8, 7, 13, 15, 4, 280000000, time.UTC)
+	seed := func(id, name string, publishedAt, updatedAt time.Time, status model.Status) {
+		_, err := db.CreateServer(ctx, &apiv0.ServerJSON{
+			Name:        name,
+			Description: "A test server",
+			Version:     "1.0.0",
+			Status:      status,
+			Meta: &apiv0.ServerMeta{
+				Official: &apiv0.RegistryExtensions{ID: id, PublishedAt: publishedAt, UpdatedAt: updatedAt},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// IDs sort in the opposite order to change times, to check ordering is by timestamp
+	seed("00000000-0000-0000-0000-000000000004", "com.example/before", checkpoint.Add(-time.Hour), checkpoint.Add(-time.Nanosecond), model.StatusActive)
+	seed("00000000-0000-0000-0000-000000000003", "com.example/at-boundary", checkpoint.Add(-time.Hour), checkpoint, model.StatusActive)
+	seed("00000000-0000-0000-0000-000000000002", "com.example/deleted", checkpoint.Add(-time.Hour), checkpoint.Add(time.Minute), model.StatusDeleted)
+	seed("00000000-0000-0000-0000-000000000001", "com.example/newest", checkpoint.Add(2*time.Minute), checkpoint.Add(2*time.Minute), model.StatusActive)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	list := func(query string) (*httptest.ResponseRecorder, apiv0.ServerListResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp apiv0.ServerListResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return w, resp
+	}
+
+	t.Run("boundary timestamp is inclusive", func(t *testing.T) {
+		w, resp := list("?updated_since=" + checkpoint.Format(time.RFC3339Nano))
+
+		var names []string
+		for _, server := range resp.Servers {
+			names = append(names, server.Name)
+		}
+		assert.Equal(t, []string{"com.example/at-boundary", "com.example/newest"}, names)
+
+		assert.Equal(t, []apiv0.ServerTombstone{
+			{Name: "com.example/deleted", Version: "1.0.0", DeletedAt: checkpoint.Add(time.Minute)},
+		}, resp.Tombstones)
+
+		require.NotNil(t, resp.Metadata.MaxUpdatedAt)
+		assert.True(t, checkpoint.Add(2*time.Minute).Equal(*resp.Metadata.MaxUpdatedAt))
+		assert.Equal(t, checkpoint.Add(2*time.Minute).Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+	})
+
+	t.Run("checkpointing on max_updated_at returns the newest change again", func(t *testing.T) {
+		_, resp := list("?updated_since=" + checkpoint.Add(2*time.Minute).Format(time.RFC3339Nano))
+		require.Len(t, resp.Servers, 1)
+		assert.Equal(t, "com.example/newest", resp.Servers[0].Name)
+		assert.Empty(t, resp.Tombstones)
+	})
+
+	t.Run("pages follow change order", func(t *testing.T) {
+		_, first := list("?limit=1&updated_since=" + checkpoint.Add(-time.Hour).Format(time.RFC3339Nano))
+		require.Len(t, first.Servers, 1)
+		assert.Equal(t, "com.example/before", first.Servers[0].Name)
+		require.NotEmpty(t, first.Metadata.NextCursor)
+
+		_, second := list("?limit=1&updated_since=" + checkpoint.Add(-time.Hour).Format(time.RFC3339Nano) + "&cursor=" + first.Metadata.NextCursor)
+		require.Len(t, second.Servers, 1)
+		assert.Equal(t, "com.example/at-boundary", second.Servers[0].Name)
+	})
+
+	t.Run("full listing keeps deleted servers and has no tombstones", func(t *testing.T) {
+		_, resp := list("")
+		assert.Len(t, resp.Servers, 4)
+		assert.Empty(t, resp.Tombstones)
+	})
+}
+
 // TestServersEndpointsIntegration tests the servers endpoints with actual HTTP requests
 func TestServersEndpointsIntegration(t *testing.T) {
 	// Create mock registry service
@@ -415,12 +665,12 @@ func TestServersEndpointsIntegration(t *testing.T) {
 		Repository: model.Repository{
 			URL:    "https://github.com/example/integration-test",
 			Source: "github",
-			ID:     "example/integration-test",
+			ID:     "100000011",
 		},
 		Version: "1.0.0",
 	}
 
-	published, err := registryService.Publish(testServer)
+	published, err := registryService.Publish(context.Background(), testServer)
 	assert.NoError(t, err)
 	assert.NotNil(t, published)
 
@@ -511,3 +761,563 @@ func TestServersEndpointsIntegration(t *testing.T) {
 	// Verify mock expectations
 	// No expectations to verify with real service
 }
+
+// catalogServer is a server with the package and remote details catalogs don't render
+func catalogServer(name string) apiv0.ServerJSON {
+	return apiv0.ServerJSON{
+		Name:        name,
+		Description: "A server with packages and remotes",
+		Version:     "1.0.0",
+		Title:       "Catalog Server",
+		Repository: model.Repository{
+			URL:    "https://github.com/example/catalog-server",
+			Source: "github",
+			ID:     "100000018",
+		},
+		Icons: []model.Icon{
+			{Src: "https://example.com/icon-48.png", MimeType: "image/png", Sizes: []string{"48x48"}},
+			{Src: "https://example.com/icon.svg", MimeType: "image/svg+xml", Sizes: []string{"any"}},
+		},
+		Packages: []model.Package{
+			{
+				RegistryType:    model.RegistryTypeNPM,
+				RegistryBaseURL: model.RegistryURLNPM,
+				Identifier:      "@example/catalog-server",
+				Version:         "1.0.0",
+				Transport:       model.Transport{Type: "stdio"},
+				EnvironmentVariables: []model.KeyValueInput{
+					{Name: "API_KEY", InputWithVariables: model.InputWithVariables{Input: model.Input{Description: "API key for the example service", IsRequired: true, IsSecret: true}}},
+				},
+			},
+		},
+		Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://example.com/mcp"},
+		},
+	}
+}
+
+func TestServersListEndpoint_SummaryFields(t *testing.T) {
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+	published, err := registryService.Publish(context.Background(), catalogServer("com.example/catalog-server"))
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("summary omits packages and remotes", func(t *testing.T) {
+		w := list("?fields=summary")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var raw struct {
+			Servers []map[string]json.RawMessage `json:"servers"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+		require.Len(t, raw.Servers, 1)
+		for _, field := range []string{"packages", "remotes", "repository", "icons", "categories"} {
+			assert.NotContains(t, raw.Servers[0], field)
+		}
+
+		var summaries apiv0.ServerSummaryListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summaries))
+		summary := summaries.Servers[0]
+		require.NotNil(t, summary.Meta)
+		require.NotNil(t, summary.Meta.Official)
+		assert.Equal(t, published.Meta.Official.ID, summary.Meta.Official.ID)
+		assert.True(t, summary.Meta.Official.IsLatest)
+
+		summary.Meta = nil
+		assert.Equal(t, apiv0.ServerSummary{
+			Name:          "com.example/catalog-server",
+			Description:   "A server with packages and remotes",
+			Version:       "1.0.0",
+			Title:         "Catalog Server",
+			RepositoryURL: "https://github.com/example/catalog-server",
+			Icon:          &model.Icon{Src: "https://example.com/icon-48.png", MimeType: "image/png", Sizes: []string{"48x48"}},
+		}, summary)
+		assert.Equal(t, 1, summaries.Metadata.Count)
+	})
+
+	t.Run("full is the default", func(t *testing.T) {
+		for _, query := range []string{"", "?fields=full"} {
+			w := list(query)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var full apiv0.ServerListResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &full))
+			require.Len(t, full.Servers, 1)
+			assert.Len(t, full.Servers[0].Packages, 1)
+			assert.Len(t, full.Servers[0].Remotes, 1)
+		}
+	})
+
+	t.Run("unknown projection is rejected", func(t *testing.T) {
+		w := list("?fields=packages")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("both shapes are documented", func(t *testing.T) {
+		response := api.OpenAPI().Paths["/v0/servers"].Get.Responses["200"]
+		schema := response.Content["application/json"].Schema
+		require.Len(t, schema.OneOf, 2)
+		assert.Equal(t, "#/components/schemas/ServerListResponse", schema.OneOf[0].Ref)
+		assert.Equal(t, "#/components/schemas/ServerSummaryListResponse", schema.OneOf[1].Ref)
+		assert.Contains(t, response.Headers, "Last-Modified")
+	})
+}
+
+func TestServersListEndpoint_PackageFilters(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, config.NewConfig())
+
+	seed := func(id, name string, isLatest bool, packages ...model.Package) {
+		_, err := db.CreateServer(ctx, &apiv0.ServerJSON{
+			Name:        name,
+			Description: "A test server",
+			Version:     "1.0.0",
+			Packages:    packages,
+			Meta: &apiv0.ServerMeta{
+				Official: &apiv0.RegistryExtensions{ID: id, PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: isLatest},
+			},
+		})
+		require.NoError(t, err)
+	}
+	npx := model.Package{RegistryType: model.RegistryTypeNPM, Identifier: "@example/npx", Version: "1.0.0", RunTimeHint: "npx"}
+	npm := model.Package{RegistryType: model.RegistryTypeNPM, Identifier: "@example/npm", Version: "1.0.0"}
+	uvx := model.Package{RegistryType: model.RegistryTypePyPI, Identifier: "example-uvx", Version: "1.0.0", RunTimeHint: "uvx"}
+	dockerNpx := model.Package{RegistryType: model.RegistryTypeOCI, Identifier: "example/npx", Version: "1.0.0", RunTimeHint: "npx"}
+
+	seed("00000000-0000-0000-0000-000000000001", "com.example/node-tools", true, npx)
+	seed("00000000-0000-0000-0000-000000000002", "com.example/node-plain", true, npm)
+	seed("00000000-0000-0000-0000-000000000003", "com.example/python-tools", true, uvx)
+	seed("00000000-0000-0000-0000-000000000004", "com.example/polyglot-tools", true, uvx, npm)
+	seed("00000000-0000-0000-0000-000000000005", "com.example/split-hints", true, npm, dockerNpx)
+	seed("00000000-0000-0000-0000-000000000006", "com.example/node-tools-old", false, npx)
+	seed("00000000-0000-0000-0000-000000000007", "com.example/remote-only", true)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	list := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp apiv0.ServerListResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		names := []string{}
+		for _, server := range resp.Servers {
+			names = append(names, server.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "registry type",
+			query: "?registry_type=npm",
+			want:  []string{"com.example/node-tools", "com.example/node-plain", "com.example/polyglot-tools", "com.example/split-hints", "com.example/node-tools-old"},
+		},
+		{
+			name:  "runtime hint",
+			query: "?runtime_hint=npx",
+			want:  []string{"com.example/node-tools", "com.example/split-hints", "com.example/node-tools-old"},
+		},
+		{
+			name:  "both must match the same package",
+			query: "?registry_type=npm&runtime_hint=npx",
+			want:  []string{"com.example/node-tools", "com.example/node-tools-old"},
+		},
+		{
+			name:  "combined with latest",
+			query: "?registry_type=npm&runtime_hint=npx&version=latest",
+			want:  []string{"com.example/node-tools"},
+		},
+		{
+			name:  "combined with search",
+			query: "?registry_type=pypi&search=polyglot",
+			want:  []string{"com.example/polyglot-tools"},
+		},
+		{
+			name:  "no match",
+			query: "?registry_type=nuget",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, list(tt.query))
+		})
+	}
+}
+
+func TestServersListEndpoint_LicenseFilter(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, config.NewConfig())
+
+	for i, license := range []string{"MIT", "MIT OR Apache-2.0", "(Apache-2.0 AND BSD-3-Clause)", "GPL-2.0+", "MIT-0", ""} {
+		_, err := db.CreateServer(ctx, &apiv0.ServerJSON{
+			Name:        fmt.Sprintf("com.example/server-%d", i),
+			Description: "A test server",
+			Version:     "1.0.0",
+			License:     license,
+			Meta: &apiv0.ServerMeta{
+				Official: &apiv0.RegistryExtensions{ID: fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i), PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       []string
+	}{
+		{name: "single identifier", query: "?license=MIT", wantStatus: http.StatusOK, want: []string{"com.example/server-0", "com.example/server-1"}},
+		{name: "case-insensitive", query: "?license=apache-2.0", wantStatus: http.StatusOK, want: []string{"com.example/server-1", "com.example/server-2"}},
+		{name: "inside parentheses", query: "?license=BSD-3-Clause", wantStatus: http.StatusOK, want: []string{"com.example/server-2"}},
+		{name: "or-later suffix", query: "?license=GPL-2.0", wantStatus: http.StatusOK, want: []string{"com.example/server-3"}},
+		{name: "no match", query: "?license=ISC", wantStatus: http.StatusOK, want: []string{}},
+		{name: "expression", query: "?license=MIT+OR+Apache-2.0", wantStatus: http.StatusBadRequest},
+		{name: "invalid identifier", query: "?license=MIT%2FApache", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers"+tt.query, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			names := []string{}
+			for _, server := range resp.Servers {
+				names = append(names, server.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestServersListEndpoint_CompatibleWithFilter(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, config.NewConfig())
+
+	for i, mcpVersion := range []string{"2024-11-05", "2025-03-26", "2025-06-18", ""} {
+		_, err := db.CreateServer(ctx, &apiv0.ServerJSON{
+			Name:        fmt.Sprintf("com.example/server-%d", i),
+			Description: "A test server",
+			Version:     "1.0.0",
+			MCPVersion:  mcpVersion,
+			Meta: &apiv0.ServerMeta{
+				Official: &apiv0.RegistryExtensions{ID: fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i), PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       []string
+	}{
+		{name: "older client", query: "?compatible_with=2025-03-26", wantStatus: http.StatusOK, want: []string{"com.example/server-0", "com.example/server-1", "com.example/server-3"}},
+		{name: "oldest client", query: "?compatible_with=2024-11-05", wantStatus: http.StatusOK, want: []string{"com.example/server-0", "com.example/server-3"}},
+		{name: "revision unknown to the registry", query: "?compatible_with=2099-01-01", wantStatus: http.StatusOK, want: []string{"com.example/server-0", "com.example/server-1", "com.example/server-2", "com.example/server-3"}},
+		{name: "excluding unknown", query: "?compatible_with=2025-03-26&exclude_unknown_mcp_version=true", wantStatus: http.StatusOK, want: []string{"com.example/server-0", "com.example/server-1"}},
+		{name: "too old for any", query: "?compatible_with=2024-01-01&exclude_unknown_mcp_version=true", wantStatus: http.StatusOK, want: []string{}},
+		{name: "not a date", query: "?compatible_with=latest", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers"+tt.query, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			names := []string{}
+			for _, server := range resp.Servers {
+				names = append(names, server.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestServersListEndpoint_SearchKeywords(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, config.NewConfig())
+
+	servers := []apiv0.ServerJSON{
+		{
+			Name:        "com.example/spreadsheets",
+			Description: "Read and write bases",
+			Packages:    []model.Package{{RegistryType: "npm", Identifier: "@airtable/mcp-server", Version: "1.0.0"}},
+		},
+		{
+			Name:        "io.github.octocat/weather",
+			Description: "Forecasts for any city",
+			Repository:  model.Repository{URL: "https://github.com/octocat/forecasts", Source: "github"},
+		},
+		{
+			Name:        "com.example/airtable-sync",
+			Description: "Keeps two bases in sync",
+		},
+		{
+			Name:        "com.example/notes",
+			Description: "Search the notes you keep in Airtable",
+		},
+	}
+	for i, server := range servers {
+		server.Version = "1.0.0"
+		server.Meta = &apiv0.ServerMeta{
+			Official: &apiv0.RegistryExtensions{ID: fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i), PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true},
+		}
+		_, err := db.CreateServer(ctx, &server)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+	list := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp apiv0.ServerListResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		names := []string{}
+		for _, server := range resp.Servers {
+			names = append(names, server.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "package identifier, with name matches first",
+			query: "?search=airtable",
+			want:  []string{"com.example/airtable-sync", "com.example/spreadsheets", "com.example/notes"},
+		},
+		{
+			name:  "namespace segment",
+			query: "?search=octocat",
+			want:  []string{"io.github.octocat/weather"},
+		},
+		{
+			name:  "repository path",
+			query: "?search=forecasts",
+			want:  []string{"io.github.octocat/weather"},
+		},
+		{
+			name:  "every keyword must match",
+			query: "?search=airtable+forecasts",
+			want:  []string{},
+		},
+		{
+			name:  "name substring",
+			query: "?search=table-sy",
+			want:  []string{"com.example/airtable-sync"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, list(tt.query))
+		})
+	}
+
+	t.Run("ranking holds across pages", func(t *testing.T) {
+		var names []string
+		cursor := ""
+		for {
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers?search=airtable&limit=1"+cursor, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			for _, server := range resp.Servers {
+				names = append(names, server.Name)
+			}
+			if resp.Metadata.NextCursor == "" {
+				break
+			}
+			cursor = "&cursor=" + resp.Metadata.NextCursor
+		}
+		assert.Equal(t, []string{"com.example/airtable-sync", "com.example/spreadsheets", "com.example/notes"}, names)
+	})
+}
+
+func TestServersListEndpoint_PaginationLinksAndTotal(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	registryService := service.NewRegistryService(db, config.NewConfig())
+
+	for i := 1; i <= 5; i++ {
+		server := apiv0.ServerJSON{
+			Name:        fmt.Sprintf("com.example/server-%d", i),
+			Description: "A test server",
+			Version:     "1.0.0",
+			Meta: &apiv0.ServerMeta{
+				Official: &apiv0.RegistryExtensions{
+					ID:          fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i),
+					PublishedAt: time.Now(),
+					UpdatedAt:   time.Now(),
+					IsLatest:    i != 5,
+				},
+			},
+		}
+		if i%2 == 0 {
+			server.Packages = []model.Package{{RegistryType: model.RegistryTypeNPM, Identifier: "@example/even", Version: "1.0.0"}}
+		}
+		_, err := db.CreateServer(ctx, &server)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+
+	list := func(target string) (http.Header, apiv0.ServerListResponse) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp apiv0.ServerListResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return w.Header(), resp
+	}
+
+	// links parses a Link header into targets keyed by relation
+	links := func(header http.Header) map[string]string {
+		parsed := map[string]string{}
+		for _, link := range strings.Split(header.Get("Link"), ", ") {
+			target, rel, ok := strings.Cut(link, "; rel=")
+			require.True(t, ok, link)
+			parsed[strings.Trim(rel, `"`)] = strings.Trim(target, "<>")
+		}
+		return parsed
+	}
+
+	t.Run("following next links visits every page", func(t *testing.T) {
+		var names []string
+		target := "/v0/servers?limit=2"
+		for pages := 0; target != ""; pages++ {
+			require.Less(t, pages, 5, "pagination did not terminate")
+			header, resp := list(target)
+			assert.Equal(t, 5, resp.Metadata.Total)
+			assert.Equal(t, len(resp.Servers), resp.Metadata.Count)
+			for _, server := range resp.Servers {
+				names = append(names, server.Name)
+			}
+
+			pageLinks := links(header)
+			assert.Equal(t, "/v0/servers?limit=2", pageLinks["first"])
+			if resp.Metadata.NextCursor != "" {
+				assert.Contains(t, pageLinks["next"], "cursor="+resp.Metadata.NextCursor)
+			}
+			target = pageLinks["next"]
+		}
+		assert.Len(t, names, 5)
+	})
+
+	t.Run("total reflects filters", func(t *testing.T) {
+		for query, want := range map[string]int{
+			"?version=latest":                   4,
+			"?registry_type=npm":                2,
+			"?registry_type=npm&version=latest": 2,
+			"?search=server-3":                  1,
+			"?search=missing":                   0,
+		} {
+			header, resp := list("/v0/servers" + query)
+			assert.Equal(t, want, resp.Metadata.Total, query)
+			assert.NotContains(t, links(header), "next", query)
+		}
+	})
+
+	t.Run("links keep the filters", func(t *testing.T) {
+		header, resp := list("/v0/servers?limit=1&registry_type=npm&fields=summary")
+		require.NotEmpty(t, resp.Metadata.NextCursor)
+
+		next := links(header)["next"]
+		assert.Contains(t, next, "registry_type=npm")
+		assert.Contains(t, next, "fields=summary")
+		assert.Contains(t, next, "limit=1")
+
+		_, nextPage := list(next)
+		require.Len(t, nextPage.Servers, 1)
+		assert.Equal(t, "com.example/server-4", nextPage.Servers[0].Name)
+		assert.Equal(t, 2, nextPage.Metadata.Total)
+	})
+}
+
+func BenchmarkListServersPayload(b *testing.B) {
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+	for i := 0; i < 100; i++ {
+		server := catalogServer(fmt.Sprintf("com.example/catalog-server-%d", i))
+		server.Remotes[0].URL = fmt.Sprintf("https://example.com/mcp/%d", i)
+		if _, err := registryService.Publish(context.Background(), server); err != nil {
+			b.Fatal(err)
+		}
+	}
+	mux := newListCacheTestServer(registryService, 0)
+
+	for _, fields := range []string{"full", "summary"} {
+		b.Run(fields, func(b *testing.B) {
+			var size int
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				w := listServers(b, mux, "?limit=100&fields="+fields, nil)
+				if w.Code != http.StatusOK {
+					b.Fatalf("unexpected status %d", w.Code)
+				}
+				size = w.Body.Len()
+			}
+			b.ReportMetric(float64(size), "payload-bytes")
+		})
+	}
+}
@@ -22,13 +22,13 @@ import (
 
 func TestPrometheusHandler(t *testing.T) {
 	registryService := service.NewRegistryService(database.NewMemoryDB(), config.NewConfig())
-	server, err := registryService.Publish(apiv0.ServerJSON{
+	server, err := registryService.Publish(context.Background(), apiv0.ServerJSON{
 		Name:        "io.github.example/test-server",
 		Description: "Test server detail",
 		Repository: model.Repository{
 			URL:    "https://github.com/example/test-server",
 			Source: "github",
-			ID:     "example/test-server",
+			ID:     "100000001",
 		},
 		Version: "2.0.0",
 	})
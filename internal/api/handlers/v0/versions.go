@@ -0,0 +1,63 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListVersionsInput represents the input for listing the versions of a server
+type ListVersionsInput struct {
+	Name    string `query:"name" doc:"Server name" required:"true" minLength:"1" example:"io.github.acme/weather"`
+	Cursor  string `query:"cursor" doc:"Pagination cursor: next_cursor of the previous page" required:"false"`
+	Limit   int    `query:"limit" doc:"Number of versions, or of releases with summary=true, per page, at most 100; larger values are clamped" default:"30" minimum:"1" example:"50"`
+	Summary bool   `query:"summary" doc:"Group the versions by minor release, returning each release's version count and newest version instead of every version" required:"false"`
+	Channel string `query:"channel" doc:"Only versions in this channel: 'stable' for semantic versions without a prerelease part, 'prerelease' for those with one. Versions that are not semantic versions are in neither." enum:"stable,prerelease" required:"false" example:"stable"`
+}
+
+// Resolve clamps the page size to MaxPageSize
+func (i *ListVersionsInput) Resolve(ctx huma.Context) []error {
+	clampPageSize(ctx, &i.Limit)
+	return nil
+}
+
+// ListVersionsBody lists the versions of a server, or their releases with summary=true
+type ListVersionsBody struct {
+	Name     string                 `json:"name"`
+	Versions []apiv0.ServerSummary  `json:"versions,omitempty" doc:"Versions, newest first, including deleted ones"`
+	Releases []service.VersionGroup `json:"releases,omitempty" doc:"Minor releases, newest first, with versions that are not semantic versions grouped last as 'other'"`
+	Metadata apiv0.Metadata         `json:"metadata"`
+}
+
+// RegisterVersionsEndpoint registers the server versions listing
+func RegisterVersionsEndpoint(api huma.API, registry service.RegistryService) {
+	huma.Register(api, Public(huma.Operation{
+		OperationID: "list-server-versions",
+		Method:      http.MethodGet,
+		Path:        "/v0/servers/versions",
+		Summary:     "List server versions",
+		Description: "List the versions of a server, newest first, or summarize them by minor release so servers with many nightly versions stay cheap to browse",
+		Tags:        []string{"servers"},
+	}), func(ctx context.Context, input *ListVersionsInput) (*Response[ListVersionsBody], error) {
+		list, err := registry.ListVersions(ctx, input.Name, service.VersionQuery{
+			Channel: input.Channel,
+			Summary: input.Summary,
+			Cursor:  input.Cursor,
+			Limit:   input.Limit,
+		})
+		if err != nil {
+			return nil, serviceError(err, "Server", http.StatusInternalServerError, "Failed to list server versions")
+		}
+
+		body := ListVersionsBody{
+			Name:     input.Name,
+			Versions: list.Versions,
+			Releases: list.Groups,
+			Metadata: apiv0.Metadata{NextCursor: list.NextCursor, Count: len(list.Versions) + len(list.Groups), Total: list.Total},
+		}
+		return &Response[ListVersionsBody]{Body: body}, nil
+	})
+}
@@ -0,0 +1,80 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestListVersionsEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+	for _, version := range []string{"1.0.0", "1.1.0-nightly.1", "1.1.0-nightly.2", "1.1.0"} {
+		_, err := registryService.Publish(ctx, apiv0.ServerJSON{Name: "io.github.acme/foo", Description: "Foo tools", Version: version})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, registryService)
+	v0.RegisterVersionsEndpoint(api, registryService)
+
+	get := func(target string) (int, v0.ListVersionsBody) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		var body v0.ListVersionsBody
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		}
+		return w.Code, body
+	}
+
+	t.Run("versions", func(t *testing.T) {
+		code, body := get("/v0/servers/versions?name=io.github.acme/foo&limit=3")
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, body.Versions, 3)
+		assert.Equal(t, "1.1.0", body.Versions[0].Version)
+		assert.True(t, body.Versions[0].Meta.Official.IsLatest)
+		assert.Equal(t, 3, body.Metadata.Count)
+		assert.Equal(t, 4, body.Metadata.Total)
+		assert.Empty(t, body.Releases)
+
+		code, body = get("/v0/servers/versions?name=io.github.acme/foo&limit=3&cursor=" + body.Metadata.NextCursor)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, body.Versions, 1)
+		assert.Equal(t, "1.0.0", body.Versions[0].Version)
+	})
+
+	t.Run("summary of a channel", func(t *testing.T) {
+		code, body := get("/v0/servers/versions?name=io.github.acme/foo&summary=true&channel=prerelease")
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, body.Releases, 1)
+		assert.Equal(t, "1.1", body.Releases[0].Release)
+		assert.Equal(t, 2, body.Releases[0].Count)
+		assert.Equal(t, "1.1.0-nightly.2", body.Releases[0].Newest.Version)
+		assert.Empty(t, body.Versions)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		code, _ := get("/v0/servers/versions?name=io.github.acme/missing")
+		assert.Equal(t, http.StatusNotFound, code)
+		code, _ = get("/v0/servers/versions?name=io.github.acme/foo&cursor=unknown")
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = get("/v0/servers/versions?name=io.github.acme/foo&channel=beta")
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+}
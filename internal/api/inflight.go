@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// healthPath is the health check, which fails once the server starts draining so that load
+// balancers stop routing requests to it
+const healthPath = "/v0/health"
+
+// inFlightRequests tracks the requests a server is serving, so that shutdown can wait for them
+// and report the ones still running at its deadline
+type inFlightRequests struct {
+	draining atomic.Bool
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	requests map[*http.Request]time.Time
+}
+
+func newInFlightRequests() *inFlightRequests {
+	return &inFlightRequests{requests: map[*http.Request]time.Time{}}
+}
+
+// middleware tracks each request while next serves it, and fails the health check while draining
+func (f *inFlightRequests) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.draining.Load() && r.URL.Path == healthPath {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"draining"}`))
+			return
+		}
+
+		f.wg.Add(1)
+		f.mu.Lock()
+		f.requests[r] = time.Now()
+		f.mu.Unlock()
+		defer func() {
+			f.mu.Lock()
+			delete(f.requests, r)
+			f.mu.Unlock()
+			f.wg.Done()
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// count returns the number of requests being served
+func (f *inFlightRequests) count() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return int64(len(f.requests))
+}
+
+// wait waits until every request has been served or ctx is done
+func (f *inFlightRequests) wait(ctx context.Context) error {
+	served := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(served)
+	}()
+	select {
+	case <-served:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// running describes the requests being served, longest running first, by route and elapsed time
+func (f *inFlightRequests) running() []string {
+	f.mu.Lock()
+	type request struct {
+		route   string
+		started time.Time
+	}
+	requests := make([]request, 0, len(f.requests))
+	for r, started := range f.requests {
+		requests = append(requests, request{route: r.Method + " " + r.URL.Path, started: started})
+	}
+	f.mu.Unlock()
+
+	sort.Slice(requests, func(i, j int) bool { return requests[i].started.Before(requests[j].started) })
+	described := make([]string, 0, len(requests))
+	for _, request := range requests {
+		described = append(described, fmt.Sprintf("%s (running for %s)", request.route, time.Since(request.started).Round(time.Millisecond)))
+	}
+	return described
+}
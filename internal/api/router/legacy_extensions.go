@@ -0,0 +1,68 @@
+package router
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// legacyExtensionOperations are the operations whose request body is a server JSON document
+var legacyExtensionOperations = map[string]bool{
+	"publish-server": true,
+	"edit-server":    true,
+}
+
+// maxLegacyBodyBytes matches huma's default request body limit, so oversized bodies are still rejected by huma
+const maxLegacyBodyBytes = 1024 * 1024
+
+// normalizedBodyContext replaces the request body seen by the handler
+type normalizedBodyContext struct {
+	humaContext
+	body []byte
+}
+
+func (c normalizedBodyContext) BodyReader() io.Reader {
+	return bytes.NewReader(c.body)
+}
+
+// LegacyExtensionsMiddleware rewrites server JSON bodies sent in the deprecated x-publisher
+// extension format into the canonical _meta layout before they are validated, so every
+// handler sees one representation. Requests that used the legacy format are counted and
+// marked so the handler can warn the client.
+func LegacyExtensionsMiddleware(api huma.API, metrics *telemetry.Metrics) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if ctx.Operation() == nil || !legacyExtensionOperations[ctx.Operation().OperationID] {
+			next(ctx)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(ctx.BodyReader(), maxLegacyBodyBytes+1))
+		if err != nil {
+			_ = huma.WriteErr(api, ctx, http.StatusBadRequest, "Failed to read request body", err)
+			return
+		}
+		normalized, legacy, err := apiv0.NormalizeExtensions(body)
+		if err != nil {
+			_ = huma.WriteErr(api, ctx, http.StatusBadRequest, "Invalid extensions", err)
+			return
+		}
+
+		if !legacy {
+			next(normalizedBodyContext{humaContext: ctx, body: body})
+			return
+		}
+		if metrics != nil {
+			metrics.LegacyExtensionRequests.Add(ctx.Context(), 1,
+				metric.WithAttributes(attribute.String("operation", ctx.Operation().OperationID)))
+		}
+		next(huma.WithContext(normalizedBodyContext{humaContext: ctx, body: normalized}, v0.WithLegacyExtensions(ctx.Context())))
+	}
+}
@@ -0,0 +1,101 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// publishOperationID is the operation whose response has a legacy shape
+const publishOperationID = "publish-server"
+
+// publisherAgent is the User-Agent product of the publisher CLI. Its releases that understand
+// the full publish response ask for the server profile, so one that sets no profile is older.
+const publisherAgent = "mcp-publisher"
+
+// bufferedContext holds back the status and body written by the handler so they can be rewritten
+type bufferedContext struct {
+	humaContext
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedContext) SetStatus(code int) {
+	b.status = code
+}
+
+func (b *bufferedContext) Status() int {
+	return b.status
+}
+
+func (b *bufferedContext) BodyWriter() io.Writer {
+	return &b.body
+}
+
+// legacyPublishClient reports whether a publish request should get the legacy response shape,
+// and why: an explicit profile in its Accept header wins, and otherwise publisher CLI releases
+// that predate the profile are recognized by their User-Agent
+func legacyPublishClient(accept, userAgent string) (bool, string) {
+	for _, value := range strings.Split(accept, ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		switch params["profile"] {
+		case apiv0.PublishProfileLegacy:
+			return true, "accept"
+		case apiv0.PublishProfileServer:
+			return false, ""
+		}
+	}
+	product, _, _ := strings.Cut(userAgent, "/")
+	if product == publisherAgent {
+		return true, "user_agent"
+	}
+	return false, ""
+}
+
+// LegacyPublishResponseMiddleware serves successful publishes in the {"message", "id"} shape of
+// the original handlers to clients that expect it, counting each one so the shim can be removed
+// once nobody needs it. Other clients, and every error response, are left as they are.
+func LegacyPublishResponseMiddleware(metrics *telemetry.Metrics) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if ctx.Operation() == nil || ctx.Operation().OperationID != publishOperationID {
+			next(ctx)
+			return
+		}
+		legacy, reason := legacyPublishClient(ctx.Header("Accept"), ctx.Header("User-Agent"))
+		if !legacy {
+			next(ctx)
+			return
+		}
+
+		buffered := &bufferedContext{humaContext: ctx, status: http.StatusOK}
+		next(buffered)
+
+		body := buffered.body.Bytes()
+		if buffered.status >= 200 && buffered.status < 300 {
+			var published apiv0.ServerJSON
+			if err := json.Unmarshal(body, &published); err == nil && published.Meta != nil && published.Meta.Official != nil {
+				if legacyBody, err := json.Marshal(apiv0.LegacyPublishResponse{Message: apiv0.LegacyPublishMessage, ID: published.Meta.Official.ID}); err == nil {
+					body = legacyBody
+					if metrics != nil {
+						metrics.LegacyPublishResponses.Add(ctx.Context(), 1, metric.WithAttributes(attribute.String("reason", reason)))
+					}
+				}
+			}
+		}
+		ctx.SetStatus(buffered.status)
+		_, _ = ctx.BodyWriter().Write(body)
+	}
+}
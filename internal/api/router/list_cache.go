@@ -0,0 +1,204 @@
+package router
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// listServersOperationID is the operation whose rendered responses are cached
+const listServersOperationID = "list-servers"
+
+// cachedHeader is a single response header, kept in the order it was written
+type cachedHeader struct {
+	name   string
+	value  string
+	append bool
+}
+
+// cachedPage is a fully rendered list response
+type cachedPage struct {
+	key     string
+	status  int
+	headers []cachedHeader
+	body    []byte
+}
+
+func (p *cachedPage) size() int {
+	size := len(p.key) + len(p.body)
+	for _, h := range p.headers {
+		size += len(h.name) + len(h.value)
+	}
+	return size
+}
+
+// ListCache is an in-process LRU cache of rendered server list pages.
+// All entries are dropped whenever the registry generation changes.
+type ListCache struct {
+	maxBytes   int
+	mu         sync.Mutex
+	generation uint64
+	usedBytes  int
+	order      *list.List // front is most recently used
+	entries    map[string]*list.Element
+}
+
+// NewListCache creates a list page cache bounded to maxBytes of rendered output
+func NewListCache(maxBytes int) *ListCache {
+	return &ListCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached page for key, if it was rendered at the given generation
+func (c *ListCache) get(key string, generation uint64) (*cachedPage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.syncGeneration(generation)
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	page, _ := elem.Value.(*cachedPage)
+	return page, true
+}
+
+// put stores a page rendered at the given generation, evicting least recently used pages to stay in budget
+func (c *ListCache) put(page *cachedPage, generation uint64) {
+	size := page.size()
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.syncGeneration(generation)
+	if c.generation != generation {
+		// Rendered from data that has since changed
+		return
+	}
+
+	if elem, ok := c.entries[page.key]; ok {
+		c.remove(elem)
+	}
+
+	for c.usedBytes+size > c.maxBytes && c.order.Len() > 0 {
+		c.remove(c.order.Back())
+	}
+
+	c.entries[page.key] = c.order.PushFront(page)
+	c.usedBytes += size
+}
+
+// syncGeneration drops all entries if the registry has moved on to a newer generation
+func (c *ListCache) syncGeneration(generation uint64) {
+	if generation <= c.generation {
+		return
+	}
+	c.generation = generation
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.usedBytes = 0
+}
+
+func (c *ListCache) remove(elem *list.Element) {
+	page, _ := c.order.Remove(elem).(*cachedPage)
+	delete(c.entries, page.key)
+	c.usedBytes -= page.size()
+}
+
+// humaContext lets recordingContext embed huma.Context without the field shadowing its Context() method
+type humaContext = huma.Context
+
+// recordingContext passes writes through to the wrapped context while keeping a copy of the response
+type recordingContext struct {
+	humaContext
+	page *cachedPage
+	body bytes.Buffer
+}
+
+func (r *recordingContext) SetStatus(code int) {
+	r.page.status = code
+	r.humaContext.SetStatus(code)
+}
+
+func (r *recordingContext) SetHeader(name, value string) {
+	r.page.headers = append(r.page.headers, cachedHeader{name: name, value: value})
+	r.humaContext.SetHeader(name, value)
+}
+
+func (r *recordingContext) AppendHeader(name, value string) {
+	r.page.headers = append(r.page.headers, cachedHeader{name: name, value: value, append: true})
+	r.humaContext.AppendHeader(name, value)
+}
+
+func (r *recordingContext) BodyWriter() io.Writer {
+	return io.MultiWriter(r.humaContext.BodyWriter(), &r.body)
+}
+
+// ListCacheMiddleware serves anonymous GET /v0/servers requests from cache when the registry is unchanged.
+// Requests carrying credentials always bypass the cache.
+func ListCacheMiddleware(cache *ListCache, registry service.RegistryService, metrics *telemetry.Metrics) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if ctx.Operation() == nil || ctx.Operation().OperationID != listServersOperationID || ctx.Method() != http.MethodGet {
+			next(ctx)
+			return
+		}
+
+		if ctx.Header("Authorization") != "" {
+			recordListCacheResult(ctx, metrics, "bypass")
+			next(ctx)
+			return
+		}
+
+		// Encode sorts by key, so equivalent queries share an entry
+		requestURL := ctx.URL()
+		key := requestURL.Query().Encode()
+		generation := registry.Generation()
+
+		if page, ok := cache.get(key, generation); ok {
+			recordListCacheResult(ctx, metrics, "hit")
+			for _, h := range page.headers {
+				if h.append {
+					ctx.AppendHeader(h.name, h.value)
+				} else {
+					ctx.SetHeader(h.name, h.value)
+				}
+			}
+			ctx.SetStatus(page.status)
+			_, _ = ctx.BodyWriter().Write(page.body)
+			return
+		}
+
+		recordListCacheResult(ctx, metrics, "miss")
+		recorder := &recordingContext{humaContext: ctx, page: &cachedPage{key: key}}
+		next(recorder)
+
+		if recorder.page.status == http.StatusOK {
+			recorder.page.body = recorder.body.Bytes()
+			cache.put(recorder.page, generation)
+		}
+	}
+}
+
+func recordListCacheResult(ctx huma.Context, metrics *telemetry.Metrics, result string) {
+	if metrics == nil {
+		return
+	}
+	metrics.ListCacheRequests.Add(ctx.Context(), 1, metric.WithAttributes(attribute.String("result", result)))
+}
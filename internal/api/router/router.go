@@ -2,16 +2,25 @@
 package router
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/modelcontextprotocol/registry/internal/api/handlers/admin"
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	v0auth "github.com/modelcontextprotocol/registry/internal/api/handlers/v0/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
 )
@@ -19,6 +28,16 @@ import (
 // Middleware configuration options
 type middlewareConfig struct {
 	skipPaths map[string]bool
+	fetches   service.RegistryService
+}
+
+// fetchOperations are the operations whose successful GET requests count as fetches of a
+// server's details in the per-namespace metrics
+var fetchOperations = map[string]bool{
+	"get-server":               true,
+	"get-server-readme":        true,
+	"get-server-document":      true,
+	"get-server-config-schema": true,
 }
 
 type MiddlewareOption func(*middlewareConfig)
@@ -79,9 +98,56 @@ func MetricTelemetryMiddleware(metrics *telemetry.Metrics, options ...Middleware
 		}
 
 		metrics.RequestDuration.Record(ctx.Context(), duration, metric.WithAttributes(attrs...))
+
+		// Only the version ID is passed on: the server it belongs to is looked up in the background
+		if config.fetches != nil && method == http.MethodGet && fetchOperations[ctx.Operation().OperationID] &&
+			(statusCode == http.StatusOK || statusCode == http.StatusNotModified) {
+			config.fetches.RecordFetch(ctx.Context(), ctx.Param("id"))
+		}
 	}
 }
 
+// RequestTimeoutMiddleware bounds each request with a deadline that is
+// propagated through the service layer into database and registry calls.
+// Event streams stay open by design and end when the client disconnects.
+func RequestTimeoutMiddleware(timeout time.Duration) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if getRoutePath(ctx) == v0.EventsPath {
+			next(ctx)
+			return
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx.Context(), timeout)
+		defer cancel()
+
+		next(huma.WithContext(ctx, timeoutCtx))
+	}
+}
+
+// TracePropagationMiddleware continues the W3C trace context sent in a request's traceparent
+// and tracestate headers, so server-side spans join the caller's trace
+func TracePropagationMiddleware() func(huma.Context, func(huma.Context)) {
+	tracer := otel.Tracer("github.com/modelcontextprotocol/registry/internal/api/router")
+	propagator := propagation.TraceContext{}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		traceCtx := propagator.Extract(ctx.Context(), humaHeaderCarrier{ctx})
+		traceCtx, span := tracer.Start(traceCtx, ctx.Method()+" "+getRoutePath(ctx), trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		next(huma.WithContext(ctx, traceCtx))
+	}
+}
+
+// humaHeaderCarrier reads trace headers from a huma request
+type humaHeaderCarrier struct {
+	ctx huma.Context
+}
+
+func (c humaHeaderCarrier) Get(key string) string { return c.ctx.Header(key) }
+func (c humaHeaderCarrier) Set(string, string)    {}
+func (c humaHeaderCarrier) Keys() []string        { return nil }
+
 // WithSkipPaths allows skipping instrumentation for specific paths
 func WithSkipPaths(paths ...string) MiddlewareOption {
 	return func(c *middlewareConfig) {
@@ -91,24 +157,90 @@ func WithSkipPaths(paths ...string) MiddlewareOption {
 	}
 }
 
-// NewHumaAPI creates a new Huma API with all routes registered
-func NewHumaAPI(cfg *config.Config, registry service.RegistryService, mux *http.ServeMux, metrics *telemetry.Metrics) huma.API {
+// WithFetchCounting counts successful fetches of server details on registry, for the
+// per-namespace metrics shown to publishers
+func WithFetchCounting(registry service.RegistryService) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.fetches = registry
+	}
+}
+
+// NewHumaAPI creates a new Huma API with all routes registered, including endpoints for any extra auth providers
+func NewHumaAPI(
+	cfg *config.Config, registry service.RegistryService, db database.Database, mux *http.ServeMux, metrics *telemetry.Metrics,
+	authProviders ...v0auth.Provider,
+) (huma.API, error) {
 	// Create Huma API configuration
 	humaConfig := huma.DefaultConfig("Official MCP Registry", "1.0.0")
 	humaConfig.Info.Description = "A community driven registry service for Model Context Protocol (MCP) servers.\n\n[GitHub repository](https://github.com/modelcontextprotocol/registry) | [Documentation](https://github.com/modelcontextprotocol/registry/tree/main/docs)"
 	// Disable $schema property in responses: https://github.com/danielgtaylor/huma/issues/230
 	humaConfig.CreateHooks = []func(huma.Config) huma.Config{}
+	// Operations list the Registry JWT permissions they need as scopes of this scheme
+	humaConfig.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		v0.SecurityScheme: {
+			Type:         "http",
+			Scheme:       "bearer",
+			BearerFormat: "JWT",
+			Description:  "Registry JWT from one of the /v0/auth endpoints. Scopes name the permission an operation needs as action:resource, where the resource may refer to path parameters; a bare action is checked against the server the operation acts on.",
+		},
+	}
+
+	// Report schema validation failures like the registry's own checks of a server.json
+	humaConfig.Transformers = append(humaConfig.Transformers, v0.TransformValidationErrors)
 
 	// Create a new API using humago adapter for standard library
 	api := humago.New(mux, humaConfig)
 
+	// Join the caller's trace before anything else uses the request context
+	if cfg.TracePropagation {
+		api.UseMiddleware(TracePropagationMiddleware())
+	}
+
 	// Add metrics middleware with options
 	api.UseMiddleware(MetricTelemetryMiddleware(metrics,
 		WithSkipPaths("/health", "/metrics", "/ping", "/docs"),
+		WithFetchCounting(registry),
 	))
 
+	// Bound request handling time; the request context is also cancelled when the client disconnects
+	if cfg.RequestTimeout > 0 {
+		api.UseMiddleware(RequestTimeoutMiddleware(cfg.RequestTimeout))
+	}
+
+	// Accept the deprecated x-publisher extension format, rewriting it to the _meta layout
+	api.UseMiddleware(LegacyExtensionsMiddleware(api, metrics))
+
+	// Reject server JSON fields the format does not define, unless the request opts out
+	api.UseMiddleware(UnknownFieldsMiddleware(api))
+
+	// Serve the original publish response shape to publishers that predate the full document
+	api.UseMiddleware(LegacyPublishResponseMiddleware(metrics))
+
+	// Scope each request to the tenant its token was issued for; list requests then carry a
+	// token, so the list cache below only ever serves a single-tenant registry
+	if cfg.TenancyEnabled {
+		api.UseMiddleware(TenancyMiddleware(api, cfg))
+	}
+
+	// Serve repeated anonymous list requests from pre-rendered pages. Replicas only learn of
+	// each other's writes through the invalidation channel, so without one the pages could go stale.
+	switch {
+	case cfg.ListCacheMaxBytes <= 0:
+	case cfg.Replicas > 1 && cfg.CacheInvalidationChannel == "":
+		log.Printf("List cache disabled: %d replicas configured without a cache invalidation channel", cfg.Replicas)
+	default:
+		api.UseMiddleware(ListCacheMiddleware(NewListCache(cfg.ListCacheMaxBytes), registry, metrics))
+	}
+
 	// Register routes for all API versions
-	RegisterV0Routes(api, cfg, registry, metrics)
+	if err := RegisterV0Routes(api, cfg, registry, db, metrics, authProviders...); err != nil {
+		return nil, err
+	}
+
+	// The admin UI is only routed when enabled
+	if cfg.EnableAdminUI {
+		admin.RegisterRoutes(mux, registry, cfg)
+	}
 
 	// Add /metrics for Prometheus metrics using promhttp
 	mux.Handle("/metrics", metrics.PrometheusHandler())
@@ -120,5 +252,5 @@ func NewHumaAPI(cfg *config.Config, registry service.RegistryService, mux *http.
 		}
 	})
 
-	return api
+	return api, nil
 }
@@ -0,0 +1,68 @@
+package router
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// SecurityHeaders hardens the responses of next: every response gets X-Content-Type-Options and
+// the configured Referrer-Policy, and Strict-Transport-Security when it is enabled. HTML
+// responses, the API docs and admin UI, also get the configured Content-Security-Policy unless
+// their handler set a stricter one of its own; JSON responses are left as they are. Any Server
+// header a handler sets is removed, so responses do not advertise what serves them.
+func SecurityHeaders(cfg *config.Config, next http.Handler) http.Handler {
+	var hsts string
+	if cfg.HSTSEnabled {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", int64(cfg.HSTSMaxAge.Seconds()))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		if cfg.ReferrerPolicy != "" {
+			header.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if hsts != "" {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		next.ServeHTTP(&securityHeadersWriter{ResponseWriter: w, contentSecurityPolicy: cfg.ContentSecurityPolicy}, r)
+	})
+}
+
+// securityHeadersWriter finishes a response's headers once its handler has chosen them
+type securityHeadersWriter struct {
+	http.ResponseWriter
+	contentSecurityPolicy string
+	wroteHeader           bool
+}
+
+func (w *securityHeadersWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		header := w.Header()
+		header.Del("Server")
+		mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+		if mediaType == "text/html" && w.contentSecurityPolicy != "" && header.Get("Content-Security-Policy") == "" {
+			header.Set("Content-Security-Policy", w.contentSecurityPolicy)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *securityHeadersWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			// Sniff the type as net/http would, so pages written without one still get the policy
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, to flush streamed responses
+func (w *securityHeadersWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,102 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// newSecurityHeadersHandler serves the registry's routes behind SecurityHeaders
+func newSecurityHeadersHandler(t *testing.T, cfg *config.Config) http.Handler {
+	t.Helper()
+	cfg.JWTPrivateKey = "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"
+	db := database.NewMemoryDB()
+	metrics, err := telemetry.NewMetrics(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	_, err = router.NewHumaAPI(cfg, service.NewRegistryService(db, cfg), db, mux, metrics)
+	require.NoError(t, err)
+	return router.SecurityHeaders(cfg, mux)
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	cfg := config.NewConfig()
+	handler := newSecurityHeadersHandler(t, cfg)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	t.Run("HTML routes get the content security policy", func(t *testing.T) {
+		rr := get("/docs")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Header().Get("Content-Type"), "text/html")
+		assert.Equal(t, cfg.ContentSecurityPolicy, rr.Header().Get("Content-Security-Policy"))
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "no-referrer", rr.Header().Get("Referrer-Policy"))
+	})
+
+	t.Run("JSON routes are left without one", func(t *testing.T) {
+		rr := get("/v0/servers")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Header().Get("Content-Type"), "application/json")
+		assert.Empty(t, rr.Header().Get("Content-Security-Policy"))
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "no-referrer", rr.Header().Get("Referrer-Policy"))
+	})
+
+	t.Run("HSTS is off by default", func(t *testing.T) {
+		assert.Empty(t, get("/v0/health").Header().Get("Strict-Transport-Security"))
+	})
+}
+
+func TestSecurityHeaders_Config(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.HSTSEnabled = true
+	cfg.HSTSMaxAge = 24 * time.Hour
+	cfg.ReferrerPolicy = "strict-origin-when-cross-origin"
+	cfg.ContentSecurityPolicy = ""
+	handler := newSecurityHeadersHandler(t, cfg)
+
+	for _, path := range []string{"/docs", "/v0/health"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, "max-age=86400; includeSubDomains", rr.Header().Get("Strict-Transport-Security"), path)
+		assert.Equal(t, "strict-origin-when-cross-origin", rr.Header().Get("Referrer-Policy"), path)
+		assert.Empty(t, rr.Header().Get("Content-Security-Policy"), "an empty policy is not sent")
+	}
+}
+
+func TestSecurityHeaders_HandlerHeaders(t *testing.T) {
+	handler := router.SecurityHeaders(config.NewConfig(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "example/1.0")
+		if r.URL.Path == "/own-policy" {
+			w.Header().Set("Content-Security-Policy", "default-src 'none'")
+		}
+		_, _ = w.Write([]byte("<!doctype html><title>page</title>"))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/page", nil))
+	assert.Empty(t, rr.Header().Get("Server"))
+	assert.Contains(t, rr.Header().Get("Content-Type"), "text/html", "pages without a content type are sniffed")
+	assert.Equal(t, config.NewConfig().ContentSecurityPolicy, rr.Header().Get("Content-Security-Policy"))
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/own-policy", nil))
+	assert.Equal(t, "default-src 'none'", rr.Header().Get("Content-Security-Policy"), "a handler's own policy is kept")
+}
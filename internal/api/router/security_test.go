@@ -0,0 +1,71 @@
+package router_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// stubProvider is an embedder-supplied auth method, so its endpoint is registered too
+type stubProvider struct{}
+
+func (stubProvider) Name() string { return "stub" }
+
+func (stubProvider) Authenticate(context.Context, string) (string, []auth.Permission, error) {
+	return "", nil, errors.New("not implemented")
+}
+
+func TestOperationsDeclareSecurity(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.JWTPrivateKey = "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"
+	db := database.NewMemoryDB()
+
+	shutdownTelemetry, metrics, err := telemetry.InitMetrics("test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = shutdownTelemetry(context.Background()) })
+
+	api, err := router.NewHumaAPI(cfg, service.NewRegistryService(db, cfg), db, http.NewServeMux(), metrics, stubProvider{})
+	require.NoError(t, err)
+
+	openAPI := api.OpenAPI()
+	require.Contains(t, openAPI.Components.SecuritySchemes, v0.SecurityScheme)
+
+	operations := 0
+	for path, item := range openAPI.Paths {
+		for method, op := range map[string]*huma.Operation{
+			http.MethodGet:    item.Get,
+			http.MethodHead:   item.Head,
+			http.MethodPost:   item.Post,
+			http.MethodPut:    item.Put,
+			http.MethodPatch:  item.Patch,
+			http.MethodDelete: item.Delete,
+		} {
+			if op == nil {
+				continue
+			}
+			operations++
+			// Every operation either lists the scopes it needs, or is public with an empty requirement
+			require.Len(t, op.Security, 1, "%s %s must use v0.RequireAuth or v0.Public", method, path)
+			requirement := op.Security[0]
+			if len(requirement) == 0 {
+				continue
+			}
+			assert.Len(t, requirement, 1, "%s %s", method, path)
+			assert.NotEmpty(t, requirement[v0.SecurityScheme], "%s %s must name the scopes it needs", method, path)
+		}
+	}
+	assert.Greater(t, operations, 20)
+}
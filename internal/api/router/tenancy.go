@@ -0,0 +1,74 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+)
+
+// TenantHeader selects the tenant an admin token without one of its own acts for
+const TenantHeader = tenancy.Header
+
+// tenantExemptPaths are the operations answered the same for every tenant: logging in, probes,
+// the registry's public keys, and unsubscribe links, which carry their own proof
+var tenantExemptPaths = map[string]bool{
+	"/v0/health":                         true,
+	"/v0/ping":                           true,
+	"/v0/meta":                           true,
+	"/v0/admin/jwks":                     true,
+	"/v0/notifications/{id}/unsubscribe": true,
+}
+
+// TenancyMiddleware scopes every request of a multi-tenant registry to one tenant, so the
+// database only shows it that tenant's data. A Registry JWT is required even for reads: the
+// tenant is the one the token was issued for. Admin tokens, holding edit permission for every
+// server, may carry no tenant and then act for the tenant named in the MCP-Registry-Tenant
+// header, or across all tenants without one.
+func TenancyMiddleware(api huma.API, cfg *config.Config) func(huma.Context, func(huma.Context)) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		path := getRoutePath(ctx)
+		if tenantExemptPaths[path] || strings.HasPrefix(path, "/v0/auth/") {
+			next(ctx)
+			return
+		}
+
+		const bearerPrefix = "Bearer "
+		authHeader := ctx.Header("Authorization")
+		if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "This registry serves several tenants: a Registry JWT is required. Expected 'Bearer <token>'")
+			return
+		}
+		claims, err := jwtManager.ValidateToken(ctx.Context(), authHeader[len(bearerPrefix):])
+		if err != nil {
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "Invalid or expired Registry JWT token", err)
+			return
+		}
+
+		requested := ctx.Header(TenantHeader)
+		switch {
+		case claims.Tenant != "":
+			if requested != "" && requested != claims.Tenant {
+				_ = huma.WriteErr(api, ctx, http.StatusForbidden, "This token is not valid for tenant "+requested)
+				return
+			}
+			next(huma.WithContext(ctx, tenancy.WithTenant(ctx.Context(), claims.Tenant)))
+		case !jwtManager.HasPermission("*", auth.PermissionActionEdit, claims.Permissions):
+			_ = huma.WriteErr(api, ctx, http.StatusForbidden, "This token was not issued for any tenant of this registry")
+		case requested != "":
+			if !config.TenantRegex.MatchString(requested) {
+				_ = huma.WriteErr(api, ctx, http.StatusBadRequest, "Invalid "+TenantHeader+" header")
+				return
+			}
+			next(huma.WithContext(ctx, tenancy.WithTenant(ctx.Context(), requested)))
+		default:
+			next(ctx)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// tenancyTestRegistry serves two tenants, acme and globex, from one memory database. Their
+// publishers get their tenant from TENANT_SUBJECTS.
+type tenancyTestRegistry struct {
+	t          *testing.T
+	mux        *http.ServeMux
+	jwtManager *auth.JWTManager
+}
+
+func newTenancyTestRegistry(t *testing.T) *tenancyTestRegistry {
+	t.Helper()
+	cfg := config.NewConfig()
+	cfg.JWTPrivateKey = "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"
+	cfg.EnableRegistryValidation = false
+	cfg.TenancyEnabled = true
+	cfg.EnableAdminUI = true
+	cfg.TenantSubjects = []string{"github-at:acme-org=acme", "github-at:globex-*=globex"}
+	db := database.NewMemoryDB()
+
+	shutdownTelemetry, metrics, err := telemetry.InitMetrics("test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = shutdownTelemetry(context.Background()) })
+
+	mux := http.NewServeMux()
+	_, err = router.NewHumaAPI(cfg, service.NewRegistryService(db, cfg), db, mux, metrics)
+	require.NoError(t, err)
+	return &tenancyTestRegistry{t: t, mux: mux, jwtManager: auth.NewJWTManager(cfg)}
+}
+
+// token issues a Registry JWT for a GitHub identity allowed to publish and edit under com.example
+func (r *tenancyTestRegistry) token(subject string) string {
+	r.t.Helper()
+	response, err := r.jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: subject,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "com.example/*"},
+			{Action: auth.PermissionActionEdit, ResourcePattern: "com.example/*"},
+		},
+	})
+	require.NoError(r.t, err)
+	return response.RegistryToken
+}
+
+// adminToken issues a Registry JWT with edit permission for every server, and no tenant
+func (r *tenancyTestRegistry) adminToken() string {
+	r.t.Helper()
+	response, err := r.jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+		AuthMethod: auth.MethodNone,
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionEdit, ResourcePattern: "*"},
+			{Action: auth.PermissionActionPublish, ResourcePattern: "*"},
+		},
+	})
+	require.NoError(r.t, err)
+	return response.RegistryToken
+}
+
+func (r *tenancyTestRegistry) do(method, path, token string, body any, header http.Header) *httptest.ResponseRecorder {
+	r.t.Helper()
+	var reader bytes.Buffer
+	if body != nil {
+		require.NoError(r.t, json.NewEncoder(&reader).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	w := httptest.NewRecorder()
+	r.mux.ServeHTTP(w, req)
+	return w
+}
+
+// adminPage requests an admin UI page signed in with token
+func (r *tenancyTestRegistry) adminPage(path, token string, header http.Header) *httptest.ResponseRecorder {
+	r.t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.AddCookie(&http.Cookie{Name: "mcp_registry_admin", Value: token})
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	w := httptest.NewRecorder()
+	r.mux.ServeHTTP(w, req)
+	return w
+}
+
+func (r *tenancyTestRegistry) publish(token, version string) *apiv0.ServerJSON {
+	r.t.Helper()
+	w := r.do(http.MethodPost, "/v0/publish", token, apiv0.ServerJSON{
+		Name:        "com.example/weather",
+		Description: "Weather for " + version,
+		Version:     version,
+		Readme:      "# Weather",
+	}, nil)
+	require.Equal(r.t, http.StatusOK, w.Code, w.Body.String())
+	var server apiv0.ServerJSON
+	require.NoError(r.t, json.Unmarshal(w.Body.Bytes(), &server))
+	return &server
+}
+
+func (r *tenancyTestRegistry) list(token string, header http.Header) []apiv0.ServerJSON {
+	r.t.Helper()
+	w := r.do(http.MethodGet, "/v0/servers", token, nil, header)
+	require.Equal(r.t, http.StatusOK, w.Code, w.Body.String())
+	var list apiv0.ServerListResponse
+	require.NoError(r.t, json.Unmarshal(w.Body.Bytes(), &list))
+	return list.Servers
+}
+
+func TestTenancy_Isolation(t *testing.T) {
+	registry := newTenancyTestRegistry(t)
+	acme, globex := registry.token("acme-org"), registry.token("globex-team")
+
+	// Both tenants publish a server of the same name, each becoming the latest version of its own
+	acmeServer := registry.publish(acme, "1.0.0")
+	globexServer := registry.publish(globex, "2.0.0")
+	assert.Equal(t, "acme", acmeServer.Meta.Official.Tenant)
+	assert.Equal(t, "globex", globexServer.Meta.Official.Tenant)
+	assert.True(t, acmeServer.Meta.Official.IsLatest)
+	assert.True(t, globexServer.Meta.Official.IsLatest)
+	acmeID, globexID := acmeServer.Meta.Official.ID, globexServer.Meta.Official.ID
+
+	t.Run("list", func(t *testing.T) {
+		servers := registry.list(acme, nil)
+		require.Len(t, servers, 1)
+		assert.Equal(t, acmeID, servers[0].Meta.Official.ID)
+
+		servers = registry.list(globex, nil)
+		require.Len(t, servers, 1)
+		assert.Equal(t, globexID, servers[0].Meta.Official.ID)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		for _, path := range []string{"/v0/servers/%s", "/v0/servers/%s/readme", "/v0/servers/%s/server.json", "/v0/servers/%s/config-schema"} {
+			w := registry.do(http.MethodGet, fmt.Sprintf(path, acmeID), acme, nil, nil)
+			assert.NotEqual(t, http.StatusNotFound, w.Code, path)
+			w = registry.do(http.MethodGet, fmt.Sprintf(path, acmeID), globex, nil, nil)
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		w := registry.do(http.MethodGet, "/v0/servers/exists?name=com.example/weather&version=2.0.0", acme, nil, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"exists": false, "is_latest": false}`, w.Body.String())
+	})
+
+	t.Run("edit", func(t *testing.T) {
+		edited := *globexServer
+		edited.Description = "Taken over"
+		w := registry.do(http.MethodPut, "/v0/servers/"+globexID, acme, edited, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+
+	t.Run("notifications and activity", func(t *testing.T) {
+		w := registry.do(http.MethodPost, "/v0/namespaces/com.example/notifications", acme,
+			map[string]string{"webhook_url": "https://hooks.acme.example/publish"}, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var activity v0.NamespaceActivityBody
+		w = registry.do(http.MethodGet, "/v0/namespaces/com.example/activity", acme, nil, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &activity))
+		assert.Equal(t, 1, activity.Notifications)
+		require.Len(t, activity.Recent, 1)
+		assert.Equal(t, "1.0.0", activity.Recent[0].Version)
+
+		w = registry.do(http.MethodGet, "/v0/namespaces/com.example/activity", globex, nil, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &activity))
+		assert.Equal(t, 0, activity.Notifications)
+		require.Len(t, activity.Recent, 1)
+		assert.Equal(t, "2.0.0", activity.Recent[0].Version)
+	})
+
+	t.Run("ownership", func(t *testing.T) {
+		for token, subject := range map[string]string{acme: "acme-org", globex: "globex-team"} {
+			var ownership v0.NamespaceOwnershipBody
+			w := registry.do(http.MethodGet, "/v0/namespaces/com.example/ownership", token, nil, nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ownership))
+			assert.Equal(t, subject, ownership.Subject)
+		}
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		admin := registry.adminToken()
+		assert.Len(t, registry.list(admin, nil), 2, "an admin without a tenant sees every tenant")
+
+		servers := registry.list(admin, http.Header{router.TenantHeader: {"globex"}})
+		require.Len(t, servers, 1)
+		assert.Equal(t, globexID, servers[0].Meta.Official.ID)
+
+		w := registry.do(http.MethodPost, "/v0/publish", admin, apiv0.ServerJSON{
+			Name: "com.example/unscoped", Description: "No tenant", Version: "1.0.0",
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "publishing needs a tenant")
+	})
+
+	t.Run("admin UI", func(t *testing.T) {
+		w := registry.adminPage("/admin/", acme, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), acmeID)
+		assert.NotContains(t, w.Body.String(), globexID)
+
+		w = registry.adminPage("/admin/servers/"+acmeID, acme, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = registry.adminPage("/admin/servers/"+globexID, acme, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		admin := registry.adminToken()
+		w = registry.adminPage("/admin/", admin, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), acmeID, "an admin without a tenant sees every tenant")
+		assert.Contains(t, w.Body.String(), globexID)
+
+		w = registry.adminPage("/admin/", admin, http.Header{router.TenantHeader: {"globex"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotContains(t, w.Body.String(), acmeID)
+		assert.Contains(t, w.Body.String(), globexID)
+	})
+}
+
+func TestTenancy_Rejections(t *testing.T) {
+	registry := newTenancyTestRegistry(t)
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		header   http.Header
+		expected int
+	}{
+		{"reads need a token", "/v0/servers", "", nil, http.StatusUnauthorized},
+		{"invalid token", "/v0/servers", "not-a-token", nil, http.StatusUnauthorized},
+		{"identity without a tenant", "/v0/servers", registry.token("someone-else"), nil, http.StatusForbidden},
+		{"another tenant's header", "/v0/servers", registry.token("acme-org"), http.Header{router.TenantHeader: {"globex"}}, http.StatusForbidden},
+		{"own tenant's header", "/v0/servers", registry.token("acme-org"), http.Header{router.TenantHeader: {"acme"}}, http.StatusOK},
+		{"invalid tenant header", "/v0/servers", registry.adminToken(), http.Header{router.TenantHeader: {"Not A Tenant"}}, http.StatusBadRequest},
+		{"health is exempt", "/v0/health", "", nil, http.StatusOK},
+		{"keys are exempt", "/v0/admin/jwks", "", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := registry.do(http.MethodGet, tt.path, tt.token, nil, tt.header)
+			assert.Equal(t, tt.expected, w.Code, w.Body.String())
+		})
+	}
+
+	// The admin UI signs in with the same tokens, and turns them away the same way
+	adminUITests := []struct {
+		name     string
+		token    string
+		header   http.Header
+		expected int
+	}{
+		{"admin UI: identity without a tenant", registry.token("someone-else"), nil, http.StatusForbidden},
+		{"admin UI: another tenant's header", registry.token("acme-org"), http.Header{router.TenantHeader: {"globex"}}, http.StatusForbidden},
+		{"admin UI: invalid tenant header", registry.adminToken(), http.Header{router.TenantHeader: {"Not A Tenant"}}, http.StatusBadRequest},
+	}
+	for _, tt := range adminUITests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := registry.adminPage("/admin/", tt.token, tt.header)
+			assert.Equal(t, tt.expected, w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+)
+
+func TestTracePropagationMiddleware(t *testing.T) {
+	var seen trace.SpanContext
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(router.TracePropagationMiddleware())
+	huma.Get(api, "/traced", func(ctx context.Context, _ *struct{}) (*struct{}, error) {
+		seen = trace.SpanContextFromContext(ctx)
+		return nil, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/traced", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", seen.TraceID().String())
+	assert.True(t, seen.IsSampled())
+
+	// Requests without a trace context are handled as before
+	seen = trace.SpanContext{}
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/traced", nil))
+	assert.False(t, seen.IsValid())
+}
@@ -0,0 +1,68 @@
+package router
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// UnknownFieldsMiddleware rejects publish and edit requests whose server JSON has fields the
+// format does not define, naming each one and the known field it is most likely a typo of.
+// Decoding would otherwise drop them silently. Requests that set apiv0.AllowUnknownFieldsHeader
+// have the fields dropped instead and are warned about each one, except for variables nested in
+// variables, which are rejected either way.
+func UnknownFieldsMiddleware(api huma.API) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if ctx.Operation() == nil || !legacyExtensionOperations[ctx.Operation().OperationID] {
+			next(ctx)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(ctx.BodyReader(), maxLegacyBodyBytes+1))
+		if err != nil {
+			_ = huma.WriteErr(api, ctx, http.StatusBadRequest, "Failed to read request body", err)
+			return
+		}
+
+		if !strings.EqualFold(ctx.Header(apiv0.AllowUnknownFieldsHeader), "true") {
+			if err := validators.CheckUnknownFields(body); err != nil {
+				writeError(api, ctx, v0.NewFieldValidationError("Server JSON has fields the format does not define", validators.FieldErrors(err)...))
+				return
+			}
+			next(normalizedBodyContext{humaContext: ctx, body: body})
+			return
+		}
+
+		stripped, warnings, err := validators.StripUnknownFields(body)
+		if fieldErrs := validators.FieldErrors(err); len(fieldErrs) > 0 {
+			writeError(api, ctx, v0.NewFieldValidationError("Server JSON has fields that cannot be dropped", fieldErrs...))
+			return
+		}
+		if err != nil {
+			// Bodies that are not valid JSON are left for huma to reject
+			next(normalizedBodyContext{humaContext: ctx, body: body})
+			return
+		}
+		next(huma.WithContext(normalizedBodyContext{humaContext: ctx, body: stripped}, v0.WithIgnoredUnknownFields(ctx.Context(), warnings)))
+	}
+}
+
+// writeError writes a status error the way huma writes the errors handlers return
+func writeError(api huma.API, ctx huma.Context, err huma.StatusError) {
+	ct, negotiateErr := api.Negotiate(ctx.Header("Accept"))
+	if negotiateErr != nil {
+		ct = "application/json"
+	}
+	if filter, ok := err.(huma.ContentTypeFilter); ok {
+		ct = filter.ContentType(ct)
+	}
+	ctx.SetHeader("Content-Type", ct)
+	ctx.SetStatus(err.GetStatus())
+	_ = api.Marshal(ctx.BodyWriter(), ct, err)
+}
@@ -7,17 +7,41 @@ import (
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	v0auth "github.com/modelcontextprotocol/registry/internal/api/handlers/v0/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
 )
 
 func RegisterV0Routes(
-	api huma.API, cfg *config.Config, registry service.RegistryService, metrics *telemetry.Metrics,
-) {
+	api huma.API, cfg *config.Config, registry service.RegistryService, db database.Database, metrics *telemetry.Metrics,
+	authProviders ...v0auth.Provider,
+) error {
 	v0.RegisterHealthEndpoint(api, cfg, metrics)
 	v0.RegisterPingEndpoint(api)
+	v0.RegisterMetaEndpoint(api, cfg)
+	v0.RegisterValidationRulesEndpoint(api, registry, cfg)
 	v0.RegisterServersEndpoints(api, registry)
+	v0.RegisterVersionsEndpoint(api, registry)
+	v0.RegisterForksEndpoint(api, registry)
+	v0.RegisterResolveEndpoint(api, registry)
 	v0.RegisterEditEndpoints(api, registry, cfg)
-	v0auth.RegisterAuthEndpoints(api, cfg)
+	v0.RegisterRetentionEndpoints(api, registry, cfg)
+	v0.RegisterPendingEndpoints(api, registry, cfg)
+	v0.RegisterRepairEndpoints(api, registry, cfg)
+	v0.RegisterFieldUsageEndpoints(api, registry, cfg)
+	v0.RegisterExportEndpoint(api, registry, cfg)
+	v0.RegisterNotificationEndpoints(api, registry, cfg)
+	v0.RegisterReservationEndpoints(api, registry, cfg)
+	v0.RegisterActivityEndpoints(api, registry, cfg)
+	v0.RegisterNamespaceMetricsEndpoint(api, registry, cfg)
+	v0.RegisterOwnershipEndpoint(api, registry, cfg)
+	v0.RegisterNamespaceSettingsEndpoints(api, registry, cfg)
+	v0.RegisterReportEndpoints(api, registry, cfg)
+	v0.RegisterEventsEndpoint(api, registry, cfg)
+	v0.RegisterJWKSEndpoint(api, cfg)
+	if err := v0auth.RegisterAuthEndpoints(api, cfg, db, authProviders...); err != nil {
+		return err
+	}
 	v0.RegisterPublishEndpoint(api, registry, cfg)
+	return nil
 }
@@ -3,53 +3,78 @@ package api
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"time"
 
-	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel"
 
-	"github.com/modelcontextprotocol/registry/internal/api/router"
 	"github.com/modelcontextprotocol/registry/internal/config"
-	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
 )
 
 // Server represents the HTTP server
 type Server struct {
 	config   *config.Config
-	registry service.RegistryService
-	humaAPI  huma.API
 	server   *http.Server
+	inFlight *inFlightRequests
 }
 
-// NewServer creates a new HTTP server
-func NewServer(cfg *config.Config, registryService service.RegistryService, metrics *telemetry.Metrics) *Server {
-	// Create HTTP mux and Huma API
-	mux := http.NewServeMux()
-
-	api := router.NewHumaAPI(cfg, registryService, mux, metrics)
+// streamCloser is implemented by handlers serving long-lived streams, such as registry.Registry
+// for the change feed, which must end them for shutdown to finish before its deadline
+type streamCloser interface {
+	CloseStreams()
+}
 
-	server := &Server{
+// NewServer creates a new HTTP server for handler, which is usually an assembled registry.Registry
+func NewServer(cfg *config.Config, handler http.Handler) *Server {
+	inFlight := newInFlightRequests()
+	if err := telemetry.ObserveInFlightRequests(otel.Meter(telemetry.Namespace), inFlight.count); err != nil {
+		log.Printf("Failed to export in-flight requests: %v", err)
+	}
+	server := &http.Server{
+		Addr:              cfg.ServerAddress,
+		Handler:           inFlight.middleware(handler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if closer, ok := handler.(streamCloser); ok {
+		server.RegisterOnShutdown(closer.CloseStreams)
+	}
+	return &Server{
 		config:   cfg,
-		registry: registryService,
-		humaAPI:  api,
-		server: &http.Server{
-			Addr:              cfg.ServerAddress,
-			Handler:           mux,
-			ReadHeaderTimeout: 10 * time.Second,
-		},
+		server:   server,
+		inFlight: inFlight,
 	}
-
-	return server
 }
 
 // Start begins listening for incoming HTTP requests
 func (s *Server) Start() error {
-	log.Printf("HTTP server starting on %s", s.config.ServerAddress)
-	return s.server.ListenAndServe()
+	listener, err := net.Listen("tcp", s.config.ServerAddress)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve serves incoming HTTP requests on listener
+func (s *Server) Serve(listener net.Listener) error {
+	log.Printf("HTTP server starting on %s", listener.Addr())
+	return s.server.Serve(listener)
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown drains the server. The health check fails and no new connections are accepted right
+// away, while requests already being served have until ctx is done to complete. Requests still
+// running then are abandoned and logged with their route and how long they ran.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.server.Shutdown(ctx)
+	s.inFlight.draining.Store(true)
+	err := s.server.Shutdown(ctx)
+	if err == nil {
+		err = s.inFlight.wait(ctx)
+	}
+	if err != nil {
+		for _, request := range s.inFlight.running() {
+			log.Printf("Abandoned in-flight request at shutdown: %s", request)
+		}
+	}
+	return err
 }
@@ -0,0 +1,152 @@
+//nolint:testpackage
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// startServer serves handler on a free local port, returning the server and its base URL
+func startServer(t *testing.T, handler http.Handler) (*Server, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := NewServer(&config.Config{ServerAddress: listener.Addr().String()}, handler)
+	go func() { _ = server.Serve(listener) }()
+	return server, "http://" + listener.Addr().String()
+}
+
+func TestServerShutdown(t *testing.T) {
+	t.Run("in-flight requests complete", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		mux := http.NewServeMux()
+		mux.HandleFunc("/slow", func(w http.ResponseWriter, _ *http.Request) {
+			close(started)
+			<-release
+			_, _ = w.Write([]byte("done"))
+		})
+		server, baseURL := startServer(t, mux)
+
+		type result struct {
+			status int
+			body   string
+			err    error
+		}
+		responses := make(chan result, 1)
+		go func() {
+			resp, err := http.Get(baseURL + "/slow") //nolint:noctx
+			if err != nil {
+				responses <- result{err: err}
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			responses <- result{status: resp.StatusCode, body: string(body), err: err}
+		}()
+		<-started
+		assert.Equal(t, int64(1), server.inFlight.count())
+
+		shutdown := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			shutdown <- server.Shutdown(ctx)
+		}()
+
+		// Draining: the health check fails and new connections are refused
+		require.Eventually(t, server.inFlight.draining.Load, time.Second, 10*time.Millisecond)
+		rec := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/health", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"status":"draining"}`, rec.Body.String())
+		require.Eventually(t, func() bool {
+			conn, err := net.Dial("tcp", baseURL[len("http://"):])
+			if err == nil {
+				_ = conn.Close()
+			}
+			return err != nil
+		}, time.Second, 10*time.Millisecond)
+
+		close(release)
+		response := <-responses
+		require.NoError(t, response.err)
+		assert.Equal(t, http.StatusOK, response.status)
+		assert.Equal(t, "done", response.body)
+		require.NoError(t, <-shutdown)
+		assert.Zero(t, server.inFlight.count())
+	})
+
+	t.Run("requests still running at the deadline are abandoned", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+		mux := http.NewServeMux()
+		mux.HandleFunc("/stuck", func(_ http.ResponseWriter, _ *http.Request) {
+			close(started)
+			<-release
+		})
+		server, baseURL := startServer(t, mux)
+
+		go func() {
+			resp, err := http.Get(baseURL + "/stuck") //nolint:noctx
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := server.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		running := server.inFlight.running()
+		require.Len(t, running, 1)
+		assert.Contains(t, running[0], "GET /stuck (running for ")
+	})
+
+	t.Run("streams are closed when shutdown starts", func(t *testing.T) {
+		started := make(chan struct{})
+		handler := &streamingHandler{closed: make(chan struct{})}
+		handler.mux = http.NewServeMux()
+		handler.mux.HandleFunc("/stream", func(_ http.ResponseWriter, r *http.Request) {
+			close(started)
+			select {
+			case <-handler.closed:
+			case <-r.Context().Done():
+			}
+		})
+		server, baseURL := startServer(t, handler)
+
+		go func() {
+			resp, err := http.Get(baseURL + "/stream") //nolint:noctx
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, server.Shutdown(ctx))
+		assert.Zero(t, server.inFlight.count())
+	})
+}
+
+// streamingHandler holds requests open until CloseStreams is called, as the change feed does
+type streamingHandler struct {
+	mux    *http.ServeMux
+	closed chan struct{}
+}
+
+func (h *streamingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) { h.mux.ServeHTTP(w, r) }
+func (h *streamingHandler) CloseStreams()                                    { close(h.closed) }
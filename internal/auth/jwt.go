@@ -3,8 +3,12 @@ package auth
 import (
 	"context"
 	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,13 +20,42 @@ import (
 type PermissionAction string
 
 const (
+	// Publish new server names, and new versions of existing ones
 	PermissionActionPublish PermissionAction = "publish"
+	// Publish new versions of existing servers only
+	PermissionActionPublishVersion PermissionAction = "publish_version"
 	// Intended for admins taking moderation actions only, at least for now
 	PermissionActionEdit PermissionAction = "edit"
 )
 
+// Grants reports whether a permission for this action allows the given action.
+// Publish predates publish_version, so it keeps granting both.
+func (a PermissionAction) Grants(action PermissionAction) bool {
+	return a == action || (a == PermissionActionPublish && action == PermissionActionPublishVersion)
+}
+
+// RestrictPublishScope narrows the publish permissions in a token being issued to the requested scope.
+// An empty scope or PermissionActionPublish leaves them unchanged.
+func RestrictPublishScope(permissions []Permission, scope PermissionAction) ([]Permission, error) {
+	switch scope {
+	case "", PermissionActionPublish:
+		return permissions, nil
+	case PermissionActionPublishVersion:
+		restricted := make([]Permission, len(permissions))
+		for i, perm := range permissions {
+			if perm.Action == PermissionActionPublish {
+				perm.Action = PermissionActionPublishVersion
+			}
+			restricted[i] = perm
+		}
+		return restricted, nil
+	default:
+		return nil, fmt.Errorf("unsupported publish scope: %s", scope)
+	}
+}
+
 type Permission struct {
-	Action          PermissionAction `json:"action"`   // The action type (publish or edit)
+	Action          PermissionAction `json:"action"`   // The action type (publish, publish_version or edit)
 	ResourcePattern string           `json:"resource"` // e.g., "io.github.username/*"
 }
 
@@ -33,40 +66,180 @@ type JWTClaims struct {
 	AuthMethod        Method       `json:"auth_method"`
 	AuthMethodSubject string       `json:"auth_method_sub"`
 	Permissions       []Permission `json:"permissions"`
+	// Tenant scopes every request made with the token, on registries serving several organizations
+	Tenant string `json:"tenant,omitempty"`
 }
 
+// Validation errors for tokens that are well signed but used outside their validity period
+var (
+	ErrTokenExpired     = errors.New("token is expired")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+)
+
 type TokenResponse struct {
 	RegistryToken string `json:"registry_token"`
 	ExpiresAt     int    `json:"expires_at"`
 }
 
+// signingKey is an Ed25519 key pair identified by its JWK thumbprint
+type signingKey struct {
+	kid        string
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
 // JWTManager handles JWT token operations
 type JWTManager struct {
-	privateKey    ed25519.PrivateKey
-	publicKey     ed25519.PublicKey
+	// signingKey signs new tokens
+	signingKey signingKey
+	// acceptedKeys validate tokens: the signing key first, then keys being rotated out
+	acceptedKeys []signingKey
+	// seedKey signs seed exports; it is listed in the JWKS but never accepted for tokens
+	seedKey signingKey
+	// statementKey signs statements the registry makes about itself. It is derived from the
+	// signing key and, like seedKey, listed in the JWKS but never accepted for tokens.
+	statementKey  signingKey
 	tokenDuration time.Duration
+	// leeway allows for clock skew when checking iat, nbf and exp
+	leeway time.Duration
+	// tenancy assigns tokens without a tenant one from tenantSubjects
+	tenancy        bool
+	tenantSubjects []config.TenantSubject
 }
 
+// NewJWTManager creates a JWT manager, panicking if the configured keys are invalid.
+// Config.Validate rejects such keys at startup.
 func NewJWTManager(cfg *config.Config) *JWTManager {
-	seed, err := hex.DecodeString(cfg.JWTPrivateKey)
+	manager, err := LoadJWTManager(cfg)
 	if err != nil {
-		panic(fmt.Sprintf("JWTPrivateKey must be a valid hex-encoded string: %v", err))
+		panic(err.Error())
+	}
+	return manager
+}
+
+// LoadJWTManager creates a JWT manager, returning an error if the configured keys are invalid
+func LoadJWTManager(cfg *config.Config) (*JWTManager, error) {
+	primary, err := parseSigningKey(cfg.JWTPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("JWTPrivateKey %w", err)
+	}
+
+	acceptedKeys := []signingKey{primary}
+	for i, accepted := range cfg.JWTAcceptedKeys {
+		key, err := parseSigningKey(accepted)
+		if err != nil {
+			return nil, fmt.Errorf("JWTAcceptedKeys[%d] %w", i, err)
+		}
+		if key.kid != primary.kid {
+			acceptedKeys = append(acceptedKeys, key)
+		}
+	}
+
+	seedKey := primary
+	if cfg.SeedSigningKey != "" {
+		seedKey, err = parseSigningKey(cfg.SeedSigningKey)
+		if err != nil {
+			return nil, fmt.Errorf("SeedSigningKey %w", err)
+		}
+	}
+
+	tenantSubjects, err := config.ParseTenantSubjects(cfg.TenantSubjects)
+	if err != nil {
+		return nil, fmt.Errorf("TenantSubjects %w", err)
+	}
+
+	return &JWTManager{
+		signingKey:     primary,
+		acceptedKeys:   acceptedKeys,
+		seedKey:        seedKey,
+		statementKey:   deriveStatementKey(primary),
+		tokenDuration:  5 * time.Minute, // 5-minute tokens as per requirements
+		leeway:         cfg.JWTLeeway,
+		tenancy:        cfg.TenancyEnabled,
+		tenantSubjects: tenantSubjects,
+	}, nil
+}
+
+// parseSigningKey derives an Ed25519 key pair from a hex-encoded seed
+func parseSigningKey(hexSeed string) (signingKey, error) {
+	seed, err := hex.DecodeString(hexSeed)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("must be a valid hex-encoded string: %w", err)
 	}
 
 	// Require a valid Ed25519 seed (32 bytes)
 	if len(seed) != ed25519.SeedSize {
-		panic(fmt.Sprintf("JWTPrivateKey seed must be exactly %d bytes for Ed25519, got %d bytes", ed25519.SeedSize, len(seed)))
+		return signingKey{}, fmt.Errorf("seed must be exactly %d bytes for Ed25519, got %d bytes", ed25519.SeedSize, len(seed))
 	}
 
 	// Generate the full Ed25519 key pair from the seed
 	privateKey := ed25519.NewKeyFromSeed(seed)
 	publicKey := privateKey.Public().(ed25519.PublicKey)
 
-	return &JWTManager{
-		privateKey:    privateKey,
-		publicKey:     publicKey,
-		tokenDuration: 5 * time.Minute, // 5-minute tokens as per requirements
+	return signingKey{
+		kid:        JWKThumbprint(publicKey),
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}, nil
+}
+
+// deriveStatementKey derives the statement signing key from the token signing key, so that
+// statements verify against the JWKS without another key to configure and rotate
+func deriveStatementKey(primary signingKey) signingKey {
+	seed := sha256.Sum256(append([]byte("mcp-registry-statement-key:"), primary.privateKey.Seed()...))
+	privateKey := ed25519.NewKeyFromSeed(seed[:])
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return signingKey{kid: JWKThumbprint(publicKey), privateKey: privateKey, publicKey: publicKey}
+}
+
+// JWKThumbprint computes the RFC 7638 thumbprint of an Ed25519 public key, used as its kid
+func JWKThumbprint(publicKey ed25519.PublicKey) string {
+	// Members in lexicographic order with no whitespace, as required by RFC 7638
+	canonical := fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, base64.RawURLEncoding.EncodeToString(publicKey))
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JSONWebKey is an Ed25519 public key in JWK format (RFC 8037)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+// JSONWebKeySet is a JWKS document
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS returns the public keys accepted for token validation, signing key first, followed by
+// the seed signing key when it is a separate key, and the statement signing key last
+func (j *JWTManager) JWKS() JSONWebKeySet {
+	published := j.acceptedKeys
+	if !slices.ContainsFunc(published, func(key signingKey) bool { return key.kid == j.seedKey.kid }) {
+		published = append(slices.Clip(published), j.seedKey)
+	}
+	published = append(slices.Clip(published), j.statementKey)
+	keys := make([]JSONWebKey, 0, len(published))
+	for _, key := range published {
+		keys = append(keys, JSONWebKey{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			X:         base64.RawURLEncoding.EncodeToString(key.publicKey),
+			KeyID:     key.kid,
+			Algorithm: "EdDSA",
+			Use:       "sig",
+		})
 	}
+	return JSONWebKeySet{Keys: keys}
+}
+
+// SeedSigningKey returns the key seed exports are signed with
+func (j *JWTManager) SeedSigningKey() ed25519.PrivateKey {
+	return j.seedKey.privateKey
 }
 
 // GenerateToken generates a new Registry JWT token
@@ -80,33 +253,45 @@ func (j *JWTManager) GenerateTokenResponse(_ context.Context, claims JWTClaims)
 		}
 	}
 
-	// Check permissions against denylist, provided they are not an admin
+	// Check permissions against denylist, provided they are not an admin.
+	// Both publish scopes grant publish_version, so checking it covers either
 	if !hasGlobalPermissions {
 		for _, blockedNamespace := range BlockedNamespaces {
-			if j.HasPermission(blockedNamespace+"/test", PermissionActionPublish, claims.Permissions) {
+			if j.HasPermission(blockedNamespace+"/test", PermissionActionPublishVersion, claims.Permissions) {
 				return nil, fmt.Errorf("your namespace is blocked. raise an issue at https://github.com/modelcontextprotocol/registry/ if you think this is a mistake")
 			}
 		}
 	}
 
+	// Tenants only exist on registries serving several organizations
+	if !j.tenancy {
+		claims.Tenant = ""
+	} else if claims.Tenant == "" {
+		claims.Tenant = j.tenantFor(claims.AuthMethod, claims.AuthMethodSubject)
+	}
+
+	now := time.Now()
 	if claims.IssuedAt == nil {
-		claims.IssuedAt = jwt.NewNumericDate(time.Now())
+		claims.IssuedAt = jwt.NewNumericDate(now)
 	}
 	if claims.ExpiresAt == nil {
-		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(j.tokenDuration))
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(j.tokenDuration))
 	}
 	if claims.NotBefore == nil {
-		claims.NotBefore = jwt.NewNumericDate(time.Now())
+		claims.NotBefore = jwt.NewNumericDate(now)
 	}
+	// Truncate to whole seconds as the claim is encoded, so expires_at matches it exactly
+	claims.ExpiresAt = jwt.NewNumericDate(claims.ExpiresAt.Time)
 	if claims.Issuer == "" {
 		claims.Issuer = "mcp-registry"
 	}
 
-	// Create token with claims
+	// Create token with claims, naming the signing key so validators can select it
 	token := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claims)
+	token.Header["kid"] = j.signingKey.kid
 
 	// Sign token with Ed25519 private key
-	tokenString, err := token.SignedString(j.privateKey)
+	tokenString, err := token.SignedString(j.signingKey.privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -117,20 +302,79 @@ func (j *JWTManager) GenerateTokenResponse(_ context.Context, claims JWTClaims)
 	}, nil
 }
 
-// ValidateToken validates a Registry JWT token and returns the claims
+// tenantFor returns the tenant configured for an identity, or "" if none is
+func (j *JWTManager) tenantFor(method Method, subject string) string {
+	for _, entry := range j.tenantSubjects {
+		if entry.Matches(string(method), subject) {
+			return entry.Tenant
+		}
+	}
+	return ""
+}
+
+// SignStatement signs claims the registry makes about itself, such as namespace ownership,
+// with the statement signing key and typ in the typ header. The result is a compact JWS that
+// anyone can verify against the JWKS, and that ValidateToken never accepts as a token.
+func (j *JWTManager) SignStatement(typ string, claims jwt.Claims) (string, error) {
+	statement := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claims)
+	statement.Header["kid"] = j.statementKey.kid
+	statement.Header["typ"] = typ
+
+	signed, err := statement.SignedString(j.statementKey.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign statement: %w", err)
+	}
+	return signed, nil
+}
+
+// verificationKey selects the accepted key named by the token's kid. Tokens issued
+// before kids were added are checked against every accepted key. Anything typed as other
+// than a JWT, such as a signed statement, is not a token.
+func (j *JWTManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	if typ, hasTyp := token.Header["typ"]; hasTyp && typ != "JWT" {
+		return nil, fmt.Errorf("not a registry token: typ %v", typ)
+	}
+
+	kid, hasKid := token.Header["kid"]
+	if !hasKid {
+		keySet := jwt.VerificationKeySet{Keys: make([]jwt.VerificationKey, 0, len(j.acceptedKeys))}
+		for _, key := range j.acceptedKeys {
+			keySet.Keys = append(keySet.Keys, key.publicKey)
+		}
+		return keySet, nil
+	}
+
+	for _, key := range j.acceptedKeys {
+		if key.kid == kid {
+			return key.publicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %v", kid)
+}
+
+// ValidateToken validates a Registry JWT token and returns the claims. Tokens outside their
+// validity period, allowing for the configured leeway, fail with ErrTokenExpired or
+// ErrTokenNotYetValid.
 func (j *JWTManager) ValidateToken(_ context.Context, tokenString string) (*JWTClaims, error) {
 	// Parse token
-	// This also validates expiry
+	// This also validates iat, nbf and expiry
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&JWTClaims{},
-		func(_ *jwt.Token) (interface{}, error) { return j.publicKey, nil },
+		j.verificationKey,
 		jwt.WithValidMethods([]string{"EdDSA"}),
 		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(j.leeway),
 	)
 
 	// Validate token
-	if err != nil {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, fmt.Errorf("failed to parse token: %w", ErrTokenExpired)
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return nil, fmt.Errorf("failed to parse token: %w", ErrTokenNotYetValid)
+	case err != nil:
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
 	if !token.Valid {
@@ -148,7 +392,7 @@ func (j *JWTManager) ValidateToken(_ context.Context, tokenString string) (*JWTC
 
 func (j *JWTManager) HasPermission(resource string, action PermissionAction, permissions []Permission) bool {
 	for _, perm := range permissions {
-		if perm.Action == action && isResourceMatch(resource, perm.ResourcePattern) {
+		if perm.Action.Grants(action) && isResourceMatch(resource, perm.ResourcePattern) {
 			return true
 		}
 	}
@@ -5,6 +5,8 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -180,6 +182,76 @@ func TestJWTManager_GenerateAndVerifyToken(t *testing.T) {
 	})
 }
 
+func TestJWTManager_ClockSkew(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+
+	jwtManager := auth.NewJWTManager(&config.Config{
+		JWTPrivateKey: hex.EncodeToString(testSeed),
+		JWTLeeway:     30 * time.Second,
+	})
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		issuedAt      time.Time
+		notBefore     time.Time
+		expiresAt     time.Time
+		expectedError error
+	}{
+		{name: "issued by a clock ahead within leeway", issuedAt: now.Add(10 * time.Second), notBefore: now.Add(10 * time.Second), expiresAt: now.Add(5 * time.Minute)},
+		{name: "issued by a clock ahead beyond leeway", issuedAt: now.Add(2 * time.Minute), notBefore: now, expiresAt: now.Add(5 * time.Minute), expectedError: auth.ErrTokenNotYetValid},
+		{name: "not before beyond leeway", issuedAt: now, notBefore: now.Add(2 * time.Minute), expiresAt: now.Add(5 * time.Minute), expectedError: auth.ErrTokenNotYetValid},
+		{name: "expired within leeway", issuedAt: now.Add(-5 * time.Minute), notBefore: now.Add(-5 * time.Minute), expiresAt: now.Add(-10 * time.Second)},
+		{name: "expired beyond leeway", issuedAt: now.Add(-5 * time.Minute), notBefore: now.Add(-5 * time.Minute), expiresAt: now.Add(-2 * time.Minute), expectedError: auth.ErrTokenExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenResponse, err := jwtManager.GenerateTokenResponse(ctx, auth.JWTClaims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					IssuedAt:  jwt.NewNumericDate(tt.issuedAt),
+					NotBefore: jwt.NewNumericDate(tt.notBefore),
+					ExpiresAt: jwt.NewNumericDate(tt.expiresAt),
+				},
+				AuthMethod:        auth.MethodGitHubAT,
+				AuthMethodSubject: "testuser",
+			})
+			require.NoError(t, err)
+
+			_, err = jwtManager.ValidateToken(ctx, tokenResponse.RegistryToken)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+
+	t.Run("expires_at matches the claim", func(t *testing.T) {
+		// A sub-second expiry is encoded in whole seconds
+		expiresAt := time.Unix(now.Add(time.Minute).Unix(), 999_000_000)
+		tokenResponse, err := jwtManager.GenerateTokenResponse(ctx, auth.JWTClaims{
+			RegisteredClaims:  jwt.RegisteredClaims{ExpiresAt: &jwt.NumericDate{Time: expiresAt}},
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "testuser",
+		})
+		require.NoError(t, err)
+
+		claims, err := jwtManager.ValidateToken(ctx, tokenResponse.RegistryToken)
+		require.NoError(t, err)
+		assert.Equal(t, claims.ExpiresAt.Unix(), int64(tokenResponse.ExpiresAt))
+		assert.Equal(t, expiresAt.Unix(), int64(tokenResponse.ExpiresAt))
+
+		// The claim is encoded as an integer, without a fractional part
+		payload, err := jwt.NewParser().DecodeSegment(strings.Split(tokenResponse.RegistryToken, ".")[1])
+		require.NoError(t, err)
+		assert.Contains(t, string(payload), fmt.Sprintf(`"exp":%d`, tokenResponse.ExpiresAt))
+	})
+}
+
 func TestJWTManager_HasPermission(t *testing.T) {
 	// Generate a proper Ed25519 seed for testing
 	testSeed := make([]byte, ed25519.SeedSize)
@@ -255,6 +327,24 @@ func TestJWTManager_HasPermission(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:     "legacy publish grants publish_version",
+			resource: "io.github.testuser/server1",
+			action:   auth.PermissionActionPublishVersion,
+			permissions: []auth.Permission{
+				{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.testuser/*"},
+			},
+			expected: true,
+		},
+		{
+			name:     "publish_version does not grant publish",
+			resource: "io.github.testuser/server1",
+			action:   auth.PermissionActionPublish,
+			permissions: []auth.Permission{
+				{Action: auth.PermissionActionPublishVersion, ResourcePattern: "io.github.testuser/*"},
+			},
+			expected: false,
+		},
 		{
 			name:        "empty permissions",
 			resource:    "io.github.testuser/server1",
@@ -272,6 +362,28 @@ func TestJWTManager_HasPermission(t *testing.T) {
 	}
 }
 
+func TestRestrictPublishScope(t *testing.T) {
+	permissions := []auth.Permission{
+		{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.testuser/*"},
+		{Action: auth.PermissionActionEdit, ResourcePattern: "io.github.testuser/*"},
+	}
+
+	unchanged, err := auth.RestrictPublishScope(permissions, "")
+	require.NoError(t, err)
+	assert.Equal(t, permissions, unchanged)
+
+	restricted, err := auth.RestrictPublishScope(permissions, auth.PermissionActionPublishVersion)
+	require.NoError(t, err)
+	assert.Equal(t, []auth.Permission{
+		{Action: auth.PermissionActionPublishVersion, ResourcePattern: "io.github.testuser/*"},
+		{Action: auth.PermissionActionEdit, ResourcePattern: "io.github.testuser/*"},
+	}, restricted)
+	assert.Equal(t, auth.PermissionActionPublish, permissions[0].Action, "input must not be modified")
+
+	_, err = auth.RestrictPublishScope(permissions, auth.PermissionActionEdit)
+	assert.Error(t, err)
+}
+
 func TestNewJWTManager_InvalidKeySize(t *testing.T) {
 	// Test with invalid key size (should panic)
 	cfg := &config.Config{
@@ -397,3 +509,177 @@ func TestJWTManager_BlockedNamespaces(t *testing.T) {
 		assert.NotEmpty(t, tokenResponse.RegistryToken)
 	})
 }
+
+func TestJWTManager_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+
+	newSeed := func() string {
+		seed := make([]byte, ed25519.SeedSize)
+		_, err := rand.Read(seed)
+		require.NoError(t, err)
+		return hex.EncodeToString(seed)
+	}
+	oldKey, newKey := newSeed(), newSeed()
+
+	claims := auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: "testuser",
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.testuser/*"},
+		},
+	}
+
+	before := auth.NewJWTManager(&config.Config{JWTPrivateKey: oldKey})
+	during := auth.NewJWTManager(&config.Config{JWTPrivateKey: newKey, JWTAcceptedKeys: []string{oldKey}})
+	after := auth.NewJWTManager(&config.Config{JWTPrivateKey: newKey})
+
+	oldToken, err := before.GenerateTokenResponse(ctx, claims)
+	require.NoError(t, err)
+	newToken, err := during.GenerateTokenResponse(ctx, claims)
+	require.NoError(t, err)
+
+	t.Run("tokens carry the kid of the signing key", func(t *testing.T) {
+		token, _, err := jwt.NewParser().ParseUnverified(newToken.RegistryToken, &auth.JWTClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, during.JWKS().Keys[0].KeyID, token.Header["kid"])
+		assert.Equal(t, after.JWKS().Keys[0].KeyID, token.Header["kid"])
+	})
+
+	t.Run("old tokens validate while the old key is accepted", func(t *testing.T) {
+		_, err := during.ValidateToken(ctx, oldToken.RegistryToken)
+		assert.NoError(t, err)
+		_, err = during.ValidateToken(ctx, newToken.RegistryToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("old tokens are rejected once the old key is removed", func(t *testing.T) {
+		_, err := after.ValidateToken(ctx, oldToken.RegistryToken)
+		assert.ErrorContains(t, err, "unknown signing key")
+		_, err = after.ValidateToken(ctx, newToken.RegistryToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("new tokens are rejected by managers that do not know the new key", func(t *testing.T) {
+		_, err := before.ValidateToken(ctx, newToken.RegistryToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("legacy tokens without kid are checked against every accepted key", func(t *testing.T) {
+		seed, err := hex.DecodeString(oldKey)
+		require.NoError(t, err)
+
+		legacyClaims := claims
+		legacyClaims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+		legacy, err := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, legacyClaims).SignedString(ed25519.NewKeyFromSeed(seed))
+		require.NoError(t, err)
+
+		_, err = during.ValidateToken(ctx, legacy)
+		assert.NoError(t, err)
+		_, err = after.ValidateToken(ctx, legacy)
+		assert.Error(t, err)
+	})
+
+	t.Run("JWKS lists the signing key first, then accepted keys, then the statement key", func(t *testing.T) {
+		jwks := during.JWKS()
+		require.Len(t, jwks.Keys, 3)
+		assert.Equal(t, after.JWKS().Keys[0], jwks.Keys[0])
+		assert.Equal(t, before.JWKS().Keys[0], jwks.Keys[1])
+		assert.Equal(t, after.JWKS().Keys[1], jwks.Keys[2], "the statement key follows the signing key")
+		for _, key := range jwks.Keys {
+			assert.Equal(t, "OKP", key.KeyType)
+			assert.Equal(t, "Ed25519", key.Curve)
+			assert.Equal(t, "EdDSA", key.Algorithm)
+			assert.NotEmpty(t, key.X)
+		}
+	})
+}
+
+func TestJWTManager_Tenant(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	issue := func(t *testing.T, jwtManager *auth.JWTManager, claims auth.JWTClaims) string {
+		t.Helper()
+		tokenResponse, err := jwtManager.GenerateTokenResponse(ctx, claims)
+		require.NoError(t, err)
+		verifiedClaims, err := jwtManager.ValidateToken(ctx, tokenResponse.RegistryToken)
+		require.NoError(t, err)
+		return verifiedClaims.Tenant
+	}
+
+	t.Run("single-tenant registries issue no tenant", func(t *testing.T) {
+		jwtManager := auth.NewJWTManager(&config.Config{JWTPrivateKey: hex.EncodeToString(testSeed)})
+		assert.Empty(t, issue(t, jwtManager, auth.JWTClaims{AuthMethod: auth.MethodOIDC, Tenant: "acme"}))
+	})
+
+	t.Run("tenant from claims or configured subjects", func(t *testing.T) {
+		jwtManager := auth.NewJWTManager(&config.Config{
+			JWTPrivateKey:  hex.EncodeToString(testSeed),
+			TenancyEnabled: true,
+			TenantSubjects: []string{"github-at:acme-org=acme", "github-at:globex-*=globex"},
+		})
+		assert.Equal(t, "initech", issue(t, jwtManager, auth.JWTClaims{AuthMethod: auth.MethodOIDC, Tenant: "initech"}))
+		assert.Equal(t, "acme", issue(t, jwtManager, auth.JWTClaims{AuthMethod: auth.MethodGitHubAT, AuthMethodSubject: "acme-org"}))
+		assert.Equal(t, "globex", issue(t, jwtManager, auth.JWTClaims{AuthMethod: auth.MethodGitHubAT, AuthMethodSubject: "globex-team"}))
+		assert.Empty(t, issue(t, jwtManager, auth.JWTClaims{AuthMethod: auth.MethodGitHubAT, AuthMethodSubject: "acme-org-fork"}))
+	})
+
+	t.Run("invalid tenant subjects", func(t *testing.T) {
+		_, err := auth.LoadJWTManager(&config.Config{
+			JWTPrivateKey:  hex.EncodeToString(testSeed),
+			TenancyEnabled: true,
+			TenantSubjects: []string{"acme"},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTManager_SignStatement(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	ctx := context.Background()
+	jwtManager := auth.NewJWTManager(&config.Config{JWTPrivateKey: hex.EncodeToString(testSeed)})
+
+	// Claims shaped like a token's, which must still not pass as one
+	claims := auth.JWTClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "mcp-registry",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Permissions: []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "*"}},
+	}
+	statement, err := jwtManager.SignStatement("example-statement+jwt", claims)
+	require.NoError(t, err)
+
+	t.Run("signed with a separate key listed in the JWKS", func(t *testing.T) {
+		token, _, err := jwt.NewParser().ParseUnverified(statement, &auth.JWTClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "example-statement+jwt", token.Header["typ"])
+
+		jwks := jwtManager.JWKS()
+		require.Len(t, jwks.Keys, 2)
+		assert.NotEqual(t, jwks.Keys[0].KeyID, token.Header["kid"])
+		assert.Equal(t, jwks.Keys[1].KeyID, token.Header["kid"])
+	})
+
+	t.Run("not accepted as a token", func(t *testing.T) {
+		_, err := jwtManager.ValidateToken(ctx, statement)
+		assert.ErrorContains(t, err, "not a registry token")
+	})
+
+	t.Run("not accepted as a token with its type removed", func(t *testing.T) {
+		// Dropping the typ header breaks the signature; the key would not be accepted anyway
+		token, _, err := jwt.NewParser().ParseUnverified(statement, &auth.JWTClaims{})
+		require.NoError(t, err)
+		delete(token.Header, "typ")
+		header, err := token.SigningString()
+		require.NoError(t, err)
+		forged := header + "." + strings.Split(statement, ".")[2]
+		_, err = jwtManager.ValidateToken(ctx, forged)
+		assert.ErrorContains(t, err, "unknown signing key")
+	})
+}
@@ -8,6 +8,8 @@ const (
 	MethodGitHubAT Method = "github-at"
 	// GitHub Actions OIDC authentication
 	MethodGitHubOIDC Method = "github-oidc"
+	// GitLab personal access token or CI job token authentication
+	MethodGitLabAT Method = "gitlab-at"
 	// Generic OIDC authentication
 	MethodOIDC Method = "oidc"
 	// DNS-based public/private key authentication
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	env "github.com/caarlos0/env/v11"
 )
 
@@ -9,21 +11,185 @@ type DatabaseType string
 const (
 	DatabaseTypePostgreSQL DatabaseType = "postgresql"
 	DatabaseTypeMemory     DatabaseType = "memory"
+	DatabaseTypeSQLite     DatabaseType = "sqlite"
 )
 
+// BuildInfo identifies the registry binary. cmd/registry fills it in from the values injected
+// with ldflags; it is not read from the environment.
+type BuildInfo struct {
+	Version   string
+	GitCommit string
+	BuildTime string
+}
+
 // Config holds the application configuration
 // See .env.example for more documentation
 type Config struct {
-	ServerAddress            string       `env:"SERVER_ADDRESS" envDefault:":8080"`
-	DatabaseType             DatabaseType `env:"DATABASE_TYPE" envDefault:"postgresql"`
-	DatabaseURL              string       `env:"DATABASE_URL" envDefault:"postgres://localhost:5432/mcp-registry?sslmode=disable"`
-	SeedFrom                 string       `env:"SEED_FROM" envDefault:""`
-	Version                  string       `env:"VERSION" envDefault:"dev"`
-	GithubClientID           string       `env:"GITHUB_CLIENT_ID" envDefault:""`
-	GithubClientSecret       string       `env:"GITHUB_CLIENT_SECRET" envDefault:""`
-	JWTPrivateKey            string       `env:"JWT_PRIVATE_KEY" envDefault:""`
-	EnableAnonymousAuth      bool         `env:"ENABLE_ANONYMOUS_AUTH" envDefault:"false"`
-	EnableRegistryValidation bool         `env:"ENABLE_REGISTRY_VALIDATION" envDefault:"true"`
+	ServerAddress            string        `env:"SERVER_ADDRESS" envDefault:":8080"`
+	DatabaseType             DatabaseType  `env:"DATABASE_TYPE" envDefault:"postgresql"`
+	DatabaseURL              string        `env:"DATABASE_URL" envDefault:"postgres://localhost:5432/mcp-registry?sslmode=disable"`
+	SQLitePath               string        `env:"SQLITE_PATH" envDefault:"mcp-registry.db"`
+	SeedFrom                 string        `env:"SEED_FROM" envDefault:""`
+	SeedConflictPolicy       string        `env:"SEED_CONFLICT_POLICY" envDefault:"keep-last"` // abort, keep-first or keep-last
+	SeedOnly                 []string      `env:"SEED_ONLY" envSeparator:","`                  // name globs, such as io.github.acme/*
+	SeedExclude              []string      `env:"SEED_EXCLUDE" envSeparator:","`               // name globs
+	SeedLimit                int           `env:"SEED_LIMIT" envDefault:"0"`                   // 0 imports every server
+	Version                  string        `env:"VERSION" envDefault:"dev"`
+	Environment              string        `env:"ENVIRONMENT" envDefault:"dev"`
+	GithubClientID           string        `env:"GITHUB_CLIENT_ID" envDefault:""`
+	GithubClientSecret       string        `env:"GITHUB_CLIENT_SECRET" envDefault:""`
+	JWTPrivateKey            string        `env:"JWT_PRIVATE_KEY" envDefault:""`
+	EnableAnonymousAuth      bool          `env:"ENABLE_ANONYMOUS_AUTH" envDefault:"false"`
+	EnableRegistryValidation bool          `env:"ENABLE_REGISTRY_VALIDATION" envDefault:"true"`
+	StrictPackageVersions    bool          `env:"STRICT_PACKAGE_VERSIONS" envDefault:"false"`
+	StrictVersionPrefix      bool          `env:"STRICT_VERSION_PREFIX" envDefault:"false"`
+	ListCacheMaxBytes        int           `env:"LIST_CACHE_MAX_BYTES" envDefault:"67108864"`
+	LatestCacheSize          int           `env:"LATEST_CACHE_SIZE" envDefault:"4096"`
+	RequestTimeout           time.Duration `env:"REQUEST_TIMEOUT" envDefault:"30s"`
+	ShutdownTimeout          time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"10s"`
+	TracePropagation         bool          `env:"TRACE_PROPAGATION" envDefault:"true"`
+	ServerCategories         []string      `env:"SERVER_CATEGORIES" envSeparator:"," envDefault:"ai,cloud,communication,data,databases,developer-tools,finance,knowledge,media,monitoring,productivity,search,security,other"`
+
+	// PostgreSQL connection pool: requests wait at most DatabaseAcquireTimeout for a free connection
+	// before failing as a transient storage error, statements slower than DatabaseSlowQueryThreshold
+	// (0 disables) are logged, and server list and count queries are cancelled after
+	// DatabaseListQueryTimeout (0 disables)
+	DatabaseMaxConns           int32         `env:"DATABASE_MAX_CONNS" envDefault:"30"`
+	DatabaseMinConns           int32         `env:"DATABASE_MIN_CONNS" envDefault:"5"`
+	DatabaseMaxConnLifetime    time.Duration `env:"DATABASE_MAX_CONN_LIFETIME" envDefault:"2h"`
+	DatabaseAcquireTimeout     time.Duration `env:"DATABASE_ACQUIRE_TIMEOUT" envDefault:"5s"`
+	DatabaseSlowQueryThreshold time.Duration `env:"DATABASE_SLOW_QUERY_THRESHOLD" envDefault:"500ms"`
+	DatabaseListQueryTimeout   time.Duration `env:"DATABASE_LIST_QUERY_TIMEOUT" envDefault:"5s"`
+
+	// Response hardening headers: every response gets ReferrerPolicy, pages served as HTML (the
+	// API docs and admin UI) get ContentSecurityPolicy, and Strict-Transport-Security is sent with
+	// HSTSMaxAge when HSTSEnabled, for registries only reachable over TLS. An empty policy is not sent.
+	ReferrerPolicy        string        `env:"REFERRER_POLICY" envDefault:"no-referrer"`
+	ContentSecurityPolicy string        `env:"CONTENT_SECURITY_POLICY" envDefault:"default-src 'none'; script-src https://unpkg.com; style-src 'unsafe-inline' https://unpkg.com; img-src 'self' data: https:; font-src data: https://unpkg.com; connect-src 'self'; base-uri 'none'; form-action 'self'; frame-ancestors 'none'"`
+	HSTSEnabled           bool          `env:"HSTS_ENABLED" envDefault:"false"`
+	HSTSMaxAge            time.Duration `env:"HSTS_MAX_AGE" envDefault:"8760h"`
+
+	// DatabaseReadURL is a read-only PostgreSQL replica that server lists, searches and lookups
+	// are sent to, using the pool settings above; empty sends every statement to DatabaseURL
+	DatabaseReadURL string `env:"DATABASE_READ_URL"`
+
+	// Repository hosts accepted in repository.url besides github and gitlab, as name=host[/depth]
+	// entries parsed by ParseRepositorySources
+	RepositorySources []string `env:"REPOSITORY_SOURCES" envSeparator:","`
+
+	// Retention: soft-delete old non-latest versions beyond the newest RetentionKeepVersions
+	// (0 disables the job) that are older than RetentionKeepDays
+	RetentionKeepVersions int           `env:"RETENTION_KEEP_VERSIONS" envDefault:"0"`
+	RetentionKeepDays     int           `env:"RETENTION_KEEP_DAYS" envDefault:"30"`
+	RetentionInterval     time.Duration `env:"RETENTION_INTERVAL" envDefault:"24h"`
+
+	// Remote health: probe each latest server's remote URLs every RemoteHealthInterval (0 disables
+	// the job), marking it degraded or unreachable after RemoteHealthFailureThreshold failed checks in a row
+	RemoteHealthInterval         time.Duration `env:"REMOTE_HEALTH_INTERVAL" envDefault:"0"`
+	RemoteHealthFailureThreshold int           `env:"REMOTE_HEALTH_FAILURE_THRESHOLD" envDefault:"3"`
+	RemoteHealthTimeout          time.Duration `env:"REMOTE_HEALTH_TIMEOUT" envDefault:"5s"`
+	RemoteHealthHostInterval     time.Duration `env:"REMOTE_HEALTH_HOST_INTERVAL" envDefault:"1s"`
+
+	// Link checks: HEAD each latest server's MCPB download URLs every LinkCheckInterval (0 disables
+	// the job), annotating a link as broken and notifying the namespace once it has failed for LinkCheckBrokenAfter
+	LinkCheckInterval     time.Duration `env:"LINK_CHECK_INTERVAL" envDefault:"0"`
+	LinkCheckBrokenAfter  time.Duration `env:"LINK_CHECK_BROKEN_AFTER" envDefault:"72h"`
+	LinkCheckTimeout      time.Duration `env:"LINK_CHECK_TIMEOUT" envDefault:"10s"`
+	LinkCheckHostInterval time.Duration `env:"LINK_CHECK_HOST_INTERVAL" envDefault:"1s"`
+
+	// Field usage: count the server.json fields set by the latest server versions every
+	// FieldUsageInterval (0 disables the job), for GET /v0/admin/field-usage
+	FieldUsageInterval time.Duration `env:"FIELD_USAGE_INTERVAL" envDefault:"0"`
+
+	// Maintenance: purge expired DNS authentication challenges and delivered outbox events every
+	// MaintenanceInterval (0 disables the cleanups), giving each run up to MaintenanceTaskTimeout
+	MaintenanceInterval    time.Duration `env:"MAINTENANCE_INTERVAL" envDefault:"1h"`
+	MaintenanceTaskTimeout time.Duration `env:"MAINTENANCE_TASK_TIMEOUT" envDefault:"1m"`
+
+	// Fetch metrics: count fetches of server details per namespace, server and day, adding the
+	// counts to the database every FetchMetricsFlushInterval (0 disables counting)
+	FetchMetricsFlushInterval time.Duration `env:"FETCH_METRICS_FLUSH_INTERVAL" envDefault:"1m"`
+
+	// Typosquat protection: a version published to a brand-new namespace within TyposquatMaxDistance
+	// edits of a namespace with more than TyposquatMinServers servers is held as pending until an
+	// admin approves it (0 disables the check)
+	TyposquatMaxDistance int `env:"TYPOSQUAT_MAX_DISTANCE" envDefault:"1"`
+	TyposquatMinServers  int `env:"TYPOSQUAT_MIN_SERVERS" envDefault:"10"`
+
+	// Soft launch moderation: the first versions published to a namespace with no approved servers
+	// are held as pending until an admin approves one, after which the namespace publishes instantly
+	ReviewNewNamespaces bool `env:"REVIEW_NEW_NAMESPACES" envDefault:"false"`
+
+	// Duplicate remote URLs: a remote URL another server already declares, compared ignoring trailing
+	// slashes and default ports, is reported as a warning on publish, or rejected when
+	// RejectDuplicateRemoteURLs is set. SharedRemoteURLs lists gateways many servers legitimately
+	// share; each entry matches itself and any URL below it.
+	RejectDuplicateRemoteURLs bool     `env:"REJECT_DUPLICATE_REMOTE_URLS" envDefault:"false"`
+	SharedRemoteURLs          []string `env:"SHARED_REMOTE_URLS" envSeparator:","`
+
+	// Server reports: anyone may report a server version as broken or abusive, at most
+	// ReportRateLimit times per ReportRateWindow from one client IP (0 disables the limit). A version
+	// reported ReportThreshold times in one category needs attention, and its namespace is notified
+	// (0 disables flagging).
+	// Clients are told apart by the connection's address, or with TrustForwardedFor by the last
+	// X-Forwarded-For entry, which the proxy in front of the registry must set.
+	ReportRateLimit   int           `env:"REPORT_RATE_LIMIT" envDefault:"10"`
+	ReportRateWindow  time.Duration `env:"REPORT_RATE_WINDOW" envDefault:"1h"`
+	ReportThreshold   int           `env:"REPORT_THRESHOLD" envDefault:"5"`
+	TrustForwardedFor bool          `env:"TRUST_FORWARDED_FOR" envDefault:"false"`
+
+	// Change feed: GET /v0/events streams changes to public server versions as server-sent events
+	// to at most EventsMaxConnections clients at once (0 disables the feed), and
+	// EventsMaxConnectionsPerIP from one client IP (0 for no per-client limit), told apart as for
+	// reports. The last EventsBufferSize events are kept for clients resuming with Last-Event-ID,
+	// and streams are pinged every EventsHeartbeatInterval so proxies keep them open.
+	EventsMaxConnections      int           `env:"EVENTS_MAX_CONNECTIONS" envDefault:"1000"`
+	EventsMaxConnectionsPerIP int           `env:"EVENTS_MAX_CONNECTIONS_PER_IP" envDefault:"5"`
+	EventsBufferSize          int           `env:"EVENTS_BUFFER_SIZE" envDefault:"1000"`
+	EventsHeartbeatInterval   time.Duration `env:"EVENTS_HEARTBEAT_INTERVAL" envDefault:"30s"`
+
+	// Admin UI: server-rendered pages at /admin for browsing and moderating servers; not routed when disabled
+	EnableAdminUI bool `env:"ENABLE_ADMIN_UI" envDefault:"false"`
+
+	// Latest-version and list caches: with more than one replica they are only enabled when
+	// invalidations are shared over CacheInvalidationChannel (a PostgreSQL LISTEN/NOTIFY channel)
+	Replicas                 int    `env:"REPLICAS" envDefault:"1"`
+	CacheInvalidationChannel string `env:"CACHE_INVALIDATION_CHANNEL" envDefault:""`
+
+	// Hex-encoded Ed25519 seeds of previous JWT signing keys, still accepted for validation during rotation
+	JWTAcceptedKeys []string `env:"JWT_ACCEPTED_KEYS" envSeparator:","`
+	// How far a Registry JWT's iat, nbf and exp may be off when validating it, for clock skew between replicas
+	JWTLeeway time.Duration `env:"JWT_LEEWAY" envDefault:"30s"`
+
+	// Signed seeds: exports are signed with SeedSigningKey (a hex-encoded Ed25519 seed), or the JWT
+	// signing key when it is unset. With SeedVerificationKeys (base64url public keys, the x of their
+	// JWKS entries) set, SeedFrom is only imported with a valid signature read from SeedSignatureFrom.
+	SeedSigningKey       string   `env:"SEED_SIGNING_KEY" envDefault:""`
+	SeedSignatureFrom    string   `env:"SEED_SIGNATURE_FROM" envDefault:""`
+	SeedVerificationKeys []string `env:"SEED_VERIFICATION_KEYS" envSeparator:","`
+
+	// Publish notifications: namespace owners can register webhooks or email addresses to hear about
+	// every publish under their namespace. PublicURL is used to build unsubscribe links; email
+	// registrations are only accepted when SMTPAddress is set
+	PublicURL    string `env:"PUBLIC_URL" envDefault:"http://localhost:8080"`
+	SMTPAddress  string `env:"SMTP_ADDRESS" envDefault:""`
+	SMTPFrom     string `env:"SMTP_FROM" envDefault:""`
+	SMTPUsername string `env:"SMTP_USERNAME" envDefault:""`
+	SMTPPassword string `env:"SMTP_PASSWORD" envDefault:""`
+
+	// DNS auth: legacy signed-timestamp challenges are rejected after this time (zero means still accepted)
+	DNSAuthLegacyDeadline time.Time `env:"DNS_AUTH_LEGACY_DEADLINE"`
+
+	// Tenancy: one registry serving several organizations, each seeing and changing only its own
+	// servers, notifications and namespace verifications. Every request but authentication needs a
+	// Registry JWT naming a tenant, which tokens get from the OIDC ID token claim TenantOIDCClaim
+	// (the first value starting with TenantGroupPrefix, without it, if the claim is a list such as
+	// groups) or from TenantSubjects, <auth method>:<subject>=<tenant> entries parsed by
+	// ParseTenantSubjects. Admin tokens without a tenant act across tenants.
+	TenancyEnabled    bool     `env:"TENANCY_ENABLED" envDefault:"false"`
+	TenantOIDCClaim   string   `env:"TENANT_OIDC_CLAIM" envDefault:"groups"`
+	TenantGroupPrefix string   `env:"TENANT_GROUP_PREFIX" envDefault:""`
+	TenantSubjects    []string `env:"TENANT_SUBJECTS" envSeparator:","`
 
 	// OIDC Configuration
 	OIDCEnabled      bool   `env:"OIDC_ENABLED" envDefault:"false"`
@@ -33,6 +199,14 @@ type Config struct {
 	OIDCExtraClaims  string `env:"OIDC_EXTRA_CLAIMS" envDefault:""`
 	OIDCEditPerms    string `env:"OIDC_EDIT_PERMISSIONS" envDefault:""`
 	OIDCPublishPerms string `env:"OIDC_PUBLISH_PERMISSIONS" envDefault:""`
+
+	// Build is served by GET /v0/meta
+	Build BuildInfo
+}
+
+// IsProduction reports whether the registry is running in the production environment
+func (c *Config) IsProduction() bool {
+	return c.Environment == "prod" || c.Environment == "production"
 }
 
 // NewConfig creates a new configuration with default values
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// repositorySourceNameRegex matches the names of configured repository sources
+var repositorySourceNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// builtinRepositorySources are always accepted and cannot be redefined
+var builtinRepositorySources = []string{"github", "gitlab"}
+
+// RepositorySource describes a repository host, such as a self-hosted GitLab or Gitea,
+// whose URLs servers may list in repository.url
+type RepositorySource struct {
+	// Name is the value of repository.source
+	Name string
+	// Host is the URL host, with a port if it has one. A leading *. matches any subdomain.
+	Host string
+	// MinDepth and MaxDepth bound the number of path segments, 2 for owner/repo
+	MinDepth, MaxDepth int
+}
+
+// ParseRepositorySources parses REPOSITORY_SOURCES entries of the form name=host, or
+// name=host/N or name=host/MIN-MAX to accept repository paths of other depths than
+// owner/repo, such as GitLab subgroups
+func ParseRepositorySources(entries []string) ([]RepositorySource, error) {
+	sources := make([]RepositorySource, 0, len(entries))
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		name, rest, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || rest == "" {
+			return nil, fmt.Errorf("entry %q must be name=host", entry)
+		}
+		if !repositorySourceNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("source name %q must be lowercase letters, digits and hyphens", name)
+		}
+		for _, builtin := range builtinRepositorySources {
+			if name == builtin {
+				return nil, fmt.Errorf("source %s is built in and cannot be redefined", name)
+			}
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("source %s is defined more than once", name)
+		}
+		seen[name] = true
+
+		source := RepositorySource{Name: name, MinDepth: 2, MaxDepth: 2}
+		host, depth, hasDepth := strings.Cut(rest, "/")
+		source.Host = strings.ToLower(host)
+		if hostname := strings.TrimPrefix(source.Host, "*."); hostname == "" || strings.ContainsAny(hostname, " *?#@") {
+			return nil, fmt.Errorf("source %s has an invalid host %q", name, host)
+		}
+		if hasDepth {
+			minDepth, maxDepth, isRange := strings.Cut(depth, "-")
+			if !isRange {
+				maxDepth = minDepth
+			}
+			var err error
+			if source.MinDepth, err = strconv.Atoi(minDepth); err != nil || source.MinDepth < 1 {
+				return nil, fmt.Errorf("source %s has an invalid path depth %q", name, depth)
+			}
+			if source.MaxDepth, err = strconv.Atoi(maxDepth); err != nil || source.MaxDepth < source.MinDepth {
+				return nil, fmt.Errorf("source %s has an invalid path depth %q", name, depth)
+			}
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
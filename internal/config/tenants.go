@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TenantRegex matches tenant identifiers
+var TenantRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// TenantSubject assigns the tokens issued to matching identities to a tenant
+type TenantSubject struct {
+	// Pattern is <auth method>:<subject>, such as github-at:octocat; a trailing * matches any
+	// subject starting with the part before it
+	Pattern string
+	Tenant  string
+}
+
+// Matches reports whether the identity method:subject is assigned to the entry's tenant
+func (t TenantSubject) Matches(method, subject string) bool {
+	identity := method + ":" + subject
+	if prefix, ok := strings.CutSuffix(t.Pattern, "*"); ok {
+		return strings.HasPrefix(identity, prefix)
+	}
+	return identity == t.Pattern
+}
+
+// ParseTenantSubjects parses TENANT_SUBJECTS entries of the form <auth method>:<subject>=<tenant>.
+// The first entry matching an identity decides its tenant.
+func ParseTenantSubjects(entries []string) ([]TenantSubject, error) {
+	subjects := make([]TenantSubject, 0, len(entries))
+	for _, entry := range entries {
+		pattern, tenant, found := strings.Cut(strings.TrimSpace(entry), "=")
+		method, subject, hasMethod := strings.Cut(pattern, ":")
+		if !found || !hasMethod || method == "" || subject == "" {
+			return nil, fmt.Errorf("entry %q must be <auth method>:<subject>=<tenant>", entry)
+		}
+		if !TenantRegex.MatchString(tenant) {
+			return nil, fmt.Errorf("tenant %q must be lowercase letters, digits and hyphens", tenant)
+		}
+		subjects = append(subjects, TenantSubject{Pattern: pattern, Tenant: tenant})
+	}
+	return subjects, nil
+}
@@ -0,0 +1,336 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// envPrefix is prepended to every environment variable name
+const envPrefix = "MCP_REGISTRY_"
+
+// FieldError is a problem with the value of a single configuration environment variable
+type FieldError struct {
+	Env     string // Full environment variable name, e.g. MCP_REGISTRY_OIDC_ISSUER
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Env + ": " + e.Message
+}
+
+// ValidationError lists every problem found in a configuration
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, fieldErr := range e {
+		b.WriteString("\n  - ")
+		b.WriteString(fieldErr.Error())
+	}
+	return b.String()
+}
+
+// Validate checks each setting and the requirements between settings, so that
+// misconfiguration is reported at startup rather than deep inside the service.
+// It returns nil or a ValidationError listing every problem found.
+func (c *Config) Validate() error {
+	var errs ValidationError
+	add := func(env, format string, args ...any) {
+		errs = append(errs, FieldError{Env: envPrefix + env, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.ServerAddress == "" {
+		add("SERVER_ADDRESS", "is required")
+	}
+
+	switch c.DatabaseType {
+	case DatabaseTypePostgreSQL:
+		if u, err := url.Parse(c.DatabaseURL); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			add("DATABASE_URL", "must be a postgres:// or postgresql:// connection URL when DATABASE_TYPE is %s", DatabaseTypePostgreSQL)
+		}
+		if c.DatabaseReadURL != "" {
+			if u, err := url.Parse(c.DatabaseReadURL); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+				add("DATABASE_READ_URL", "must be a postgres:// or postgresql:// connection URL")
+			}
+		}
+		if c.DatabaseMaxConns < 1 {
+			add("DATABASE_MAX_CONNS", "must be at least 1")
+		} else if c.DatabaseMinConns < 0 || c.DatabaseMinConns > c.DatabaseMaxConns {
+			add("DATABASE_MIN_CONNS", "must be between 0 and DATABASE_MAX_CONNS (%d)", c.DatabaseMaxConns)
+		}
+		if c.DatabaseAcquireTimeout <= 0 {
+			add("DATABASE_ACQUIRE_TIMEOUT", "must be positive")
+		}
+		if c.DatabaseListQueryTimeout < 0 {
+			add("DATABASE_LIST_QUERY_TIMEOUT", "must not be negative")
+		}
+	case DatabaseTypeMemory, DatabaseTypeSQLite:
+		if c.DatabaseType == DatabaseTypeSQLite && c.SQLitePath == "" {
+			add("SQLITE_PATH", "is required when DATABASE_TYPE is %s", DatabaseTypeSQLite)
+		}
+		if c.CacheInvalidationChannel != "" {
+			add("CACHE_INVALIDATION_CHANNEL", "requires DATABASE_TYPE %s", DatabaseTypePostgreSQL)
+		}
+		if c.DatabaseReadURL != "" {
+			add("DATABASE_READ_URL", "requires DATABASE_TYPE %s", DatabaseTypePostgreSQL)
+		}
+	default:
+		add("DATABASE_TYPE", "must be %s, %s or %s, got %q", DatabaseTypePostgreSQL, DatabaseTypeSQLite, DatabaseTypeMemory, c.DatabaseType)
+	}
+
+	switch c.SeedConflictPolicy {
+	case "", "abort", "keep-first", "keep-last":
+	default:
+		add("SEED_CONFLICT_POLICY", "must be abort, keep-first or keep-last, got %q", c.SeedConflictPolicy)
+	}
+	for _, pattern := range c.SeedOnly {
+		if _, err := path.Match(pattern, ""); err != nil {
+			add("SEED_ONLY", "invalid pattern %q: %v", pattern, err)
+		}
+	}
+	for _, pattern := range c.SeedExclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			add("SEED_EXCLUDE", "invalid pattern %q: %v", pattern, err)
+		}
+	}
+	if c.SeedLimit < 0 {
+		add("SEED_LIMIT", "must not be negative")
+	}
+	if c.SeedFrom != "" && !strings.HasPrefix(c.SeedFrom, "http://") && !strings.HasPrefix(c.SeedFrom, "https://") {
+		if _, err := os.Stat(c.SeedFrom); err != nil {
+			add("SEED_FROM", "must be an http(s) URL or an existing file: %v", err)
+		}
+	}
+
+	if c.SeedSigningKey != "" {
+		if err := validateSigningKey(c.SeedSigningKey); err != nil {
+			add("SEED_SIGNING_KEY", "%v", err)
+		}
+	}
+	for i, key := range c.SeedVerificationKeys {
+		if _, err := ParseVerificationKey(key); err != nil {
+			add("SEED_VERIFICATION_KEYS", "entry %d %v", i+1, err)
+		}
+	}
+	if c.SeedSignatureFrom != "" && len(c.SeedVerificationKeys) == 0 {
+		add("SEED_SIGNATURE_FROM", "requires SEED_VERIFICATION_KEYS to verify it against")
+	}
+
+	if c.EnableAnonymousAuth && c.IsProduction() {
+		add("ENABLE_ANONYMOUS_AUTH", "must not be enabled when ENVIRONMENT is %s", c.Environment)
+	}
+
+	if (c.GithubClientID == "") != (c.GithubClientSecret == "") {
+		add("GITHUB_CLIENT_SECRET", "must be set together with GITHUB_CLIENT_ID")
+	}
+
+	if err := validateSigningKey(c.JWTPrivateKey); err != nil {
+		add("JWT_PRIVATE_KEY", "%v", err)
+	}
+	for i, key := range c.JWTAcceptedKeys {
+		if err := validateSigningKey(key); err != nil {
+			add("JWT_ACCEPTED_KEYS", "entry %d %v", i+1, err)
+		}
+	}
+	if c.JWTLeeway < 0 {
+		add("JWT_LEEWAY", "must not be negative")
+	}
+
+	if c.ListCacheMaxBytes < 0 {
+		add("LIST_CACHE_MAX_BYTES", "must not be negative")
+	}
+	if c.LatestCacheSize < 0 {
+		add("LATEST_CACHE_SIZE", "must not be negative")
+	}
+	if c.HSTSEnabled && c.HSTSMaxAge < time.Second {
+		add("HSTS_MAX_AGE", "must be at least 1s when HSTS_ENABLED is set")
+	}
+	if c.RequestTimeout < 0 {
+		add("REQUEST_TIMEOUT", "must not be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		add("SHUTDOWN_TIMEOUT", "must be positive")
+	}
+	if c.Replicas < 1 {
+		add("REPLICAS", "must be at least 1")
+	}
+
+	if len(c.ServerCategories) == 0 {
+		add("SERVER_CATEGORIES", "must list at least one category")
+	}
+	for _, category := range c.ServerCategories {
+		if strings.TrimSpace(category) == "" {
+			add("SERVER_CATEGORIES", "must not contain empty categories")
+			break
+		}
+	}
+
+	if _, err := ParseRepositorySources(c.RepositorySources); err != nil {
+		add("REPOSITORY_SOURCES", "%v", err)
+	}
+
+	if c.RetentionKeepVersions < 0 {
+		add("RETENTION_KEEP_VERSIONS", "must not be negative")
+	}
+	if c.RetentionKeepDays < 0 {
+		add("RETENTION_KEEP_DAYS", "must not be negative")
+	}
+	if c.RetentionKeepVersions > 0 && c.RetentionInterval <= 0 {
+		add("RETENTION_INTERVAL", "must be positive when RETENTION_KEEP_VERSIONS is set")
+	}
+
+	if c.RemoteHealthInterval < 0 {
+		add("REMOTE_HEALTH_INTERVAL", "must not be negative")
+	}
+	if c.RemoteHealthInterval > 0 {
+		if c.RemoteHealthFailureThreshold < 1 {
+			add("REMOTE_HEALTH_FAILURE_THRESHOLD", "must be at least 1 when REMOTE_HEALTH_INTERVAL is set")
+		}
+		if c.RemoteHealthTimeout <= 0 {
+			add("REMOTE_HEALTH_TIMEOUT", "must be positive when REMOTE_HEALTH_INTERVAL is set")
+		}
+		if c.RemoteHealthHostInterval < 0 {
+			add("REMOTE_HEALTH_HOST_INTERVAL", "must not be negative")
+		}
+	}
+
+	if c.LinkCheckInterval < 0 {
+		add("LINK_CHECK_INTERVAL", "must not be negative")
+	}
+	if c.LinkCheckInterval > 0 {
+		if c.LinkCheckBrokenAfter < 0 {
+			add("LINK_CHECK_BROKEN_AFTER", "must not be negative")
+		}
+		if c.LinkCheckTimeout <= 0 {
+			add("LINK_CHECK_TIMEOUT", "must be positive when LINK_CHECK_INTERVAL is set")
+		}
+		if c.LinkCheckHostInterval < 0 {
+			add("LINK_CHECK_HOST_INTERVAL", "must not be negative")
+		}
+	}
+
+	if c.FieldUsageInterval < 0 {
+		add("FIELD_USAGE_INTERVAL", "must not be negative")
+	}
+
+	if c.MaintenanceInterval < 0 {
+		add("MAINTENANCE_INTERVAL", "must not be negative")
+	}
+	if c.MaintenanceInterval > 0 && c.MaintenanceTaskTimeout <= 0 {
+		add("MAINTENANCE_TASK_TIMEOUT", "must be positive when MAINTENANCE_INTERVAL is set")
+	}
+
+	if c.FetchMetricsFlushInterval < 0 {
+		add("FETCH_METRICS_FLUSH_INTERVAL", "must not be negative")
+	}
+
+	if c.PublicURL != "" {
+		if u, err := url.Parse(c.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("PUBLIC_URL", "must be an absolute http(s) URL")
+		}
+	}
+	if c.SMTPAddress != "" {
+		if _, _, err := net.SplitHostPort(c.SMTPAddress); err != nil {
+			add("SMTP_ADDRESS", "must be host:port: %v", err)
+		}
+		if c.SMTPFrom == "" {
+			add("SMTP_FROM", "is required when SMTP_ADDRESS is set")
+		}
+	}
+
+	if c.ReportRateLimit < 0 {
+		add("REPORT_RATE_LIMIT", "must not be negative")
+	} else if c.ReportRateLimit > 0 && c.ReportRateWindow <= 0 {
+		add("REPORT_RATE_WINDOW", "must be positive when REPORT_RATE_LIMIT is set")
+	}
+	if c.ReportThreshold < 0 {
+		add("REPORT_THRESHOLD", "must not be negative")
+	}
+	if c.EventsMaxConnections < 0 {
+		add("EVENTS_MAX_CONNECTIONS", "must not be negative")
+	} else if c.EventsMaxConnections > 0 && c.EventsHeartbeatInterval <= 0 {
+		add("EVENTS_HEARTBEAT_INTERVAL", "must be positive when EVENTS_MAX_CONNECTIONS is set")
+	}
+	if c.EventsMaxConnectionsPerIP < 0 {
+		add("EVENTS_MAX_CONNECTIONS_PER_IP", "must not be negative")
+	}
+	if c.EventsBufferSize < 0 {
+		add("EVENTS_BUFFER_SIZE", "must not be negative")
+	}
+	if c.TyposquatMaxDistance < 0 {
+		add("TYPOSQUAT_MAX_DISTANCE", "must not be negative")
+	}
+	if c.TyposquatMinServers < 0 {
+		add("TYPOSQUAT_MIN_SERVERS", "must not be negative")
+	}
+
+	for _, shared := range c.SharedRemoteURLs {
+		if u, err := url.Parse(shared); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("SHARED_REMOTE_URLS", "entries must be absolute http(s) URLs, got %q", shared)
+		}
+	}
+
+	if _, err := ParseTenantSubjects(c.TenantSubjects); err != nil {
+		add("TENANT_SUBJECTS", "%v", err)
+	}
+	if len(c.TenantSubjects) > 0 && !c.TenancyEnabled {
+		add("TENANT_SUBJECTS", "requires TENANCY_ENABLED")
+	}
+
+	if c.OIDCEnabled {
+		if u, err := url.Parse(c.OIDCIssuer); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			add("OIDC_ISSUER", "must be an http(s) URL when OIDC_ENABLED is true")
+		}
+		if c.OIDCClientID == "" {
+			add("OIDC_CLIENT_ID", "is required when OIDC_ENABLED is true")
+		}
+		if c.OIDCExtraClaims != "" {
+			var rules []map[string]any
+			if err := json.Unmarshal([]byte(c.OIDCExtraClaims), &rules); err != nil {
+				add("OIDC_EXTRA_CLAIMS", "must be a JSON array of claim objects: %v", err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ParseVerificationKey decodes an Ed25519 public key from the base64url x member of its JWK
+func ParseVerificationKey(x string) (ed25519.PublicKey, error) {
+	key, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(x, "="))
+	if err != nil {
+		return nil, fmt.Errorf("must be base64url-encoded: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("must be a %d-byte Ed25519 public key, got %d bytes", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// validateSigningKey checks that a JWT signing key is a hex-encoded Ed25519 seed
+func validateSigningKey(hexSeed string) error {
+	if hexSeed == "" {
+		return fmt.Errorf("is required")
+	}
+	seed, err := hex.DecodeString(hexSeed)
+	if err != nil {
+		return fmt.Errorf("must be hex-encoded: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("must be a %d-byte Ed25519 seed (%d hex characters), got %d bytes", ed25519.SeedSize, 2*ed25519.SeedSize, len(seed))
+	}
+	return nil
+}
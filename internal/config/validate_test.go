@@ -0,0 +1,556 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSeed = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+
+// validConfig mirrors the defaults from .env.example with a usable signing key
+func validConfig() *config.Config {
+	return &config.Config{
+		ServerAddress:          ":8080",
+		DatabaseType:           config.DatabaseTypePostgreSQL,
+		DatabaseURL:            "postgres://localhost:5432/mcp-registry?sslmode=disable",
+		DatabaseMaxConns:       30,
+		DatabaseMinConns:       5,
+		DatabaseAcquireTimeout: 5 * time.Second,
+		JWTPrivateKey:          testSeed,
+		ListCacheMaxBytes:      67108864,
+		LatestCacheSize:        4096,
+		RequestTimeout:         30 * time.Second,
+		ShutdownTimeout:        10 * time.Second,
+		Replicas:               1,
+		ServerCategories:       []string{"ai", "other"},
+		RetentionKeepDays:      30,
+		RetentionInterval:      24 * time.Hour,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	seedFile := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(seedFile, []byte("[]"), 0o600))
+
+	tests := []struct {
+		name    string
+		modify  func(*config.Config)
+		wantEnv string // empty when the config should be valid
+		wantMsg string
+	}{
+		{
+			name:   "defaults are valid",
+			modify: func(_ *config.Config) {},
+		},
+		{
+			name:    "empty server address",
+			modify:  func(c *config.Config) { c.ServerAddress = "" },
+			wantEnv: "MCP_REGISTRY_SERVER_ADDRESS",
+			wantMsg: "is required",
+		},
+		{
+			name:    "unknown database type",
+			modify:  func(c *config.Config) { c.DatabaseType = "mysql" },
+			wantEnv: "MCP_REGISTRY_DATABASE_TYPE",
+			wantMsg: `got "mysql"`,
+		},
+		{
+			name:    "non-postgres database URL",
+			modify:  func(c *config.Config) { c.DatabaseURL = "localhost:5432" },
+			wantEnv: "MCP_REGISTRY_DATABASE_URL",
+			wantMsg: "postgres://",
+		},
+		{
+			name:    "empty connection pool",
+			modify:  func(c *config.Config) { c.DatabaseMaxConns = 0 },
+			wantEnv: "MCP_REGISTRY_DATABASE_MAX_CONNS",
+			wantMsg: "at least 1",
+		},
+		{
+			name:    "more idle connections than the pool holds",
+			modify:  func(c *config.Config) { c.DatabaseMinConns = 31 },
+			wantEnv: "MCP_REGISTRY_DATABASE_MIN_CONNS",
+			wantMsg: "between 0 and DATABASE_MAX_CONNS (30)",
+		},
+		{
+			name:    "no acquire timeout",
+			modify:  func(c *config.Config) { c.DatabaseAcquireTimeout = 0 },
+			wantEnv: "MCP_REGISTRY_DATABASE_ACQUIRE_TIMEOUT",
+			wantMsg: "must be positive",
+		},
+		{
+			name:    "negative list query timeout",
+			modify:  func(c *config.Config) { c.DatabaseListQueryTimeout = -time.Second },
+			wantEnv: "MCP_REGISTRY_DATABASE_LIST_QUERY_TIMEOUT",
+			wantMsg: "must not be negative",
+		},
+		{
+			name: "memory database ignores database URL",
+			modify: func(c *config.Config) {
+				c.DatabaseType = config.DatabaseTypeMemory
+				c.DatabaseURL = ""
+			},
+		},
+		{
+			name: "sqlite database needs a path",
+			modify: func(c *config.Config) {
+				c.DatabaseType = config.DatabaseTypeSQLite
+				c.SQLitePath = ""
+			},
+			wantEnv: "MCP_REGISTRY_SQLITE_PATH",
+			wantMsg: "is required when DATABASE_TYPE is sqlite",
+		},
+		{
+			name: "invalidation channel needs postgres",
+			modify: func(c *config.Config) {
+				c.DatabaseType = config.DatabaseTypeMemory
+				c.CacheInvalidationChannel = "registry_latest_cache"
+			},
+			wantEnv: "MCP_REGISTRY_CACHE_INVALIDATION_CHANNEL",
+			wantMsg: "requires DATABASE_TYPE postgresql",
+		},
+		{
+			name:    "read replica needs a postgres URL",
+			modify:  func(c *config.Config) { c.DatabaseReadURL = "mysql://replica:3306/registry" },
+			wantEnv: "MCP_REGISTRY_DATABASE_READ_URL",
+			wantMsg: "must be a postgres:// or postgresql:// connection URL",
+		},
+		{
+			name: "read replica needs postgres",
+			modify: func(c *config.Config) {
+				c.DatabaseType = config.DatabaseTypeMemory
+				c.DatabaseReadURL = "postgres://replica:5432/registry"
+			},
+			wantEnv: "MCP_REGISTRY_DATABASE_READ_URL",
+			wantMsg: "requires DATABASE_TYPE postgresql",
+		},
+		{
+			name: "HSTS needs a max age",
+			modify: func(c *config.Config) {
+				c.HSTSEnabled = true
+				c.HSTSMaxAge = 0
+			},
+			wantEnv: "MCP_REGISTRY_HSTS_MAX_AGE",
+			wantMsg: "must be at least 1s when HSTS_ENABLED is set",
+		},
+		{
+			name: "report rate limit needs a window",
+			modify: func(c *config.Config) {
+				c.ReportRateLimit = 10
+				c.ReportRateWindow = 0
+			},
+			wantEnv: "MCP_REGISTRY_REPORT_RATE_WINDOW",
+			wantMsg: "must be positive when REPORT_RATE_LIMIT is set",
+		},
+		{
+			name:    "report threshold",
+			modify:  func(c *config.Config) { c.ReportThreshold = -1 },
+			wantEnv: "MCP_REGISTRY_REPORT_THRESHOLD",
+			wantMsg: "must not be negative",
+		},
+		{
+			name: "event streams need a heartbeat",
+			modify: func(c *config.Config) {
+				c.EventsMaxConnections = 100
+				c.EventsHeartbeatInterval = 0
+			},
+			wantEnv: "MCP_REGISTRY_EVENTS_HEARTBEAT_INTERVAL",
+			wantMsg: "must be positive when EVENTS_MAX_CONNECTIONS is set",
+		},
+		{
+			name:   "existing seed file",
+			modify: func(c *config.Config) { c.SeedFrom = seedFile },
+		},
+		{
+			name:   "seed URL is not checked",
+			modify: func(c *config.Config) { c.SeedFrom = "https://registry.example.com/v0/servers" },
+		},
+		{
+			name:    "missing seed file",
+			modify:  func(c *config.Config) { c.SeedFrom = filepath.Join(t.TempDir(), "missing.json") },
+			wantEnv: "MCP_REGISTRY_SEED_FROM",
+			wantMsg: "existing file",
+		},
+		{
+			name: "anonymous auth outside production",
+			modify: func(c *config.Config) {
+				c.Environment = "staging"
+				c.EnableAnonymousAuth = true
+			},
+		},
+		{
+			name: "anonymous auth in production",
+			modify: func(c *config.Config) {
+				c.Environment = "prod"
+				c.EnableAnonymousAuth = true
+			},
+			wantEnv: "MCP_REGISTRY_ENABLE_ANONYMOUS_AUTH",
+			wantMsg: "ENVIRONMENT is prod",
+		},
+		{
+			name:    "GitHub client ID without secret",
+			modify:  func(c *config.Config) { c.GithubClientID = "client-id" },
+			wantEnv: "MCP_REGISTRY_GITHUB_CLIENT_SECRET",
+			wantMsg: "together with GITHUB_CLIENT_ID",
+		},
+		{
+			name:    "missing JWT key",
+			modify:  func(c *config.Config) { c.JWTPrivateKey = "" },
+			wantEnv: "MCP_REGISTRY_JWT_PRIVATE_KEY",
+			wantMsg: "is required",
+		},
+		{
+			name:    "JWT key not hex",
+			modify:  func(c *config.Config) { c.JWTPrivateKey = "not-hex" },
+			wantEnv: "MCP_REGISTRY_JWT_PRIVATE_KEY",
+			wantMsg: "hex-encoded",
+		},
+		{
+			name:    "JWT key wrong length",
+			modify:  func(c *config.Config) { c.JWTPrivateKey = "deadbeef" },
+			wantEnv: "MCP_REGISTRY_JWT_PRIVATE_KEY",
+			wantMsg: "got 4 bytes",
+		},
+		{
+			name:    "accepted JWT key wrong length",
+			modify:  func(c *config.Config) { c.JWTAcceptedKeys = []string{testSeed, "deadbeef"} },
+			wantEnv: "MCP_REGISTRY_JWT_ACCEPTED_KEYS",
+			wantMsg: "entry 2",
+		},
+		{
+			name:    "negative list cache size",
+			modify:  func(c *config.Config) { c.ListCacheMaxBytes = -1 },
+			wantEnv: "MCP_REGISTRY_LIST_CACHE_MAX_BYTES",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "negative latest cache size",
+			modify:  func(c *config.Config) { c.LatestCacheSize = -1 },
+			wantEnv: "MCP_REGISTRY_LATEST_CACHE_SIZE",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "negative JWT leeway",
+			modify:  func(c *config.Config) { c.JWTLeeway = -time.Second },
+			wantEnv: "MCP_REGISTRY_JWT_LEEWAY",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "negative request timeout",
+			modify:  func(c *config.Config) { c.RequestTimeout = -time.Second },
+			wantEnv: "MCP_REGISTRY_REQUEST_TIMEOUT",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "zero shutdown timeout",
+			modify:  func(c *config.Config) { c.ShutdownTimeout = 0 },
+			wantEnv: "MCP_REGISTRY_SHUTDOWN_TIMEOUT",
+			wantMsg: "must be positive",
+		},
+		{
+			name:    "zero replicas",
+			modify:  func(c *config.Config) { c.Replicas = 0 },
+			wantEnv: "MCP_REGISTRY_REPLICAS",
+			wantMsg: "at least 1",
+		},
+		{
+			name:    "no categories",
+			modify:  func(c *config.Config) { c.ServerCategories = nil },
+			wantEnv: "MCP_REGISTRY_SERVER_CATEGORIES",
+			wantMsg: "at least one",
+		},
+		{
+			name:    "empty category",
+			modify:  func(c *config.Config) { c.ServerCategories = []string{"ai", " "} },
+			wantEnv: "MCP_REGISTRY_SERVER_CATEGORIES",
+			wantMsg: "empty categories",
+		},
+		{
+			name:    "malformed repository source",
+			modify:  func(c *config.Config) { c.RepositorySources = []string{"gitea"} },
+			wantEnv: "MCP_REGISTRY_REPOSITORY_SOURCES",
+			wantMsg: "name=host",
+		},
+		{
+			name:    "redefined built-in repository source",
+			modify:  func(c *config.Config) { c.RepositorySources = []string{"gitlab=gitlab.corp.example.com"} },
+			wantEnv: "MCP_REGISTRY_REPOSITORY_SOURCES",
+			wantMsg: "built in",
+		},
+		{
+			name:    "invalid repository path depth",
+			modify:  func(c *config.Config) { c.RepositorySources = []string{"corp=gitlab.corp.example.com/4-2"} },
+			wantEnv: "MCP_REGISTRY_REPOSITORY_SOURCES",
+			wantMsg: "path depth",
+		},
+		{
+			name:    "negative retention versions",
+			modify:  func(c *config.Config) { c.RetentionKeepVersions = -1 },
+			wantEnv: "MCP_REGISTRY_RETENTION_KEEP_VERSIONS",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "negative retention days",
+			modify:  func(c *config.Config) { c.RetentionKeepDays = -1 },
+			wantEnv: "MCP_REGISTRY_RETENTION_KEEP_DAYS",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "unknown seed conflict policy",
+			modify:  func(c *config.Config) { c.SeedConflictPolicy = "keep-both" },
+			wantEnv: "MCP_REGISTRY_SEED_CONFLICT_POLICY",
+			wantMsg: `got "keep-both"`,
+		},
+		{
+			name:    "malformed seed filter pattern",
+			modify:  func(c *config.Config) { c.SeedExclude = []string{"io.github.acme/["} },
+			wantEnv: "MCP_REGISTRY_SEED_EXCLUDE",
+			wantMsg: `invalid pattern "io.github.acme/["`,
+		},
+		{
+			name:    "negative seed limit",
+			modify:  func(c *config.Config) { c.SeedLimit = -1 },
+			wantEnv: "MCP_REGISTRY_SEED_LIMIT",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "seed signing key not hex",
+			modify:  func(c *config.Config) { c.SeedSigningKey = "not-hex" },
+			wantEnv: "MCP_REGISTRY_SEED_SIGNING_KEY",
+			wantMsg: "must be hex-encoded",
+		},
+		{
+			name:    "seed verification key wrong length",
+			modify:  func(c *config.Config) { c.SeedVerificationKeys = []string{"AAAA"} },
+			wantEnv: "MCP_REGISTRY_SEED_VERIFICATION_KEYS",
+			wantMsg: "got 3 bytes",
+		},
+		{
+			name:    "seed signature without verification keys",
+			modify:  func(c *config.Config) { c.SeedSignatureFrom = "seed.json.sig" },
+			wantEnv: "MCP_REGISTRY_SEED_SIGNATURE_FROM",
+			wantMsg: "requires SEED_VERIFICATION_KEYS",
+		},
+		{
+			name:    "relative public URL",
+			modify:  func(c *config.Config) { c.PublicURL = "registry.example.com" },
+			wantEnv: "MCP_REGISTRY_PUBLIC_URL",
+			wantMsg: "absolute http(s) URL",
+		},
+		{
+			name:    "shared remote URL without scheme",
+			modify:  func(c *config.Config) { c.SharedRemoteURLs = []string{"gateway.example.com/mcp"} },
+			wantEnv: "MCP_REGISTRY_SHARED_REMOTE_URLS",
+			wantMsg: `got "gateway.example.com/mcp"`,
+		},
+		{
+			name:    "SMTP without sender",
+			modify:  func(c *config.Config) { c.SMTPAddress = "smtp.example.com:587" },
+			wantEnv: "MCP_REGISTRY_SMTP_FROM",
+			wantMsg: "is required",
+		},
+		{
+			name:    "negative typosquat distance",
+			modify:  func(c *config.Config) { c.TyposquatMaxDistance = -1 },
+			wantEnv: "MCP_REGISTRY_TYPOSQUAT_MAX_DISTANCE",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "negative typosquat server threshold",
+			modify:  func(c *config.Config) { c.TyposquatMinServers = -1 },
+			wantEnv: "MCP_REGISTRY_TYPOSQUAT_MIN_SERVERS",
+			wantMsg: "must not be negative",
+		},
+		{
+			name: "retention without interval",
+			modify: func(c *config.Config) {
+				c.RetentionKeepVersions = 5
+				c.RetentionInterval = 0
+			},
+			wantEnv: "MCP_REGISTRY_RETENTION_INTERVAL",
+			wantMsg: "must be positive",
+		},
+		{
+			name:    "negative remote health interval",
+			modify:  func(c *config.Config) { c.RemoteHealthInterval = -time.Minute },
+			wantEnv: "MCP_REGISTRY_REMOTE_HEALTH_INTERVAL",
+			wantMsg: "must not be negative",
+		},
+		{
+			name: "remote health without threshold",
+			modify: func(c *config.Config) {
+				c.RemoteHealthInterval = time.Hour
+				c.RemoteHealthFailureThreshold = 0
+				c.RemoteHealthTimeout = 5 * time.Second
+			},
+			wantEnv: "MCP_REGISTRY_REMOTE_HEALTH_FAILURE_THRESHOLD",
+			wantMsg: "at least 1",
+		},
+		{
+			name: "remote health without timeout",
+			modify: func(c *config.Config) {
+				c.RemoteHealthInterval = time.Hour
+				c.RemoteHealthFailureThreshold = 3
+			},
+			wantEnv: "MCP_REGISTRY_REMOTE_HEALTH_TIMEOUT",
+			wantMsg: "must be positive",
+		},
+		{
+			name: "link checks without timeout",
+			modify: func(c *config.Config) {
+				c.LinkCheckInterval = time.Hour
+			},
+			wantEnv: "MCP_REGISTRY_LINK_CHECK_TIMEOUT",
+			wantMsg: "must be positive",
+		},
+		{
+			name:    "negative maintenance interval",
+			modify:  func(c *config.Config) { c.MaintenanceInterval = -time.Hour },
+			wantEnv: "MCP_REGISTRY_MAINTENANCE_INTERVAL",
+			wantMsg: "must not be negative",
+		},
+		{
+			name:    "maintenance without task timeout",
+			modify:  func(c *config.Config) { c.MaintenanceInterval = time.Hour },
+			wantEnv: "MCP_REGISTRY_MAINTENANCE_TASK_TIMEOUT",
+			wantMsg: "must be positive",
+		},
+		{
+			name:    "negative fetch metrics flush interval",
+			modify:  func(c *config.Config) { c.FetchMetricsFlushInterval = -time.Minute },
+			wantEnv: "MCP_REGISTRY_FETCH_METRICS_FLUSH_INTERVAL",
+			wantMsg: "must not be negative",
+		},
+		{
+			name: "tenancy with tenant subjects",
+			modify: func(c *config.Config) {
+				c.TenancyEnabled = true
+				c.TenantSubjects = []string{"github-at:acme-org=acme", "oidc:*@globex.example=globex"}
+			},
+		},
+		{
+			name: "tenant subject without tenant",
+			modify: func(c *config.Config) {
+				c.TenancyEnabled = true
+				c.TenantSubjects = []string{"github-at:acme-org"}
+			},
+			wantEnv: "MCP_REGISTRY_TENANT_SUBJECTS",
+			wantMsg: "<auth method>:<subject>=<tenant>",
+		},
+		{
+			name: "tenant subject with invalid tenant",
+			modify: func(c *config.Config) {
+				c.TenancyEnabled = true
+				c.TenantSubjects = []string{"github-at:acme-org=Acme Corp"}
+			},
+			wantEnv: "MCP_REGISTRY_TENANT_SUBJECTS",
+			wantMsg: "lowercase letters",
+		},
+		{
+			name: "tenant subjects without tenancy",
+			modify: func(c *config.Config) {
+				c.TenantSubjects = []string{"github-at:acme-org=acme"}
+			},
+			wantEnv: "MCP_REGISTRY_TENANT_SUBJECTS",
+			wantMsg: "requires TENANCY_ENABLED",
+		},
+		{
+			name: "complete OIDC configuration",
+			modify: func(c *config.Config) {
+				c.OIDCEnabled = true
+				c.OIDCIssuer = "https://accounts.google.com"
+				c.OIDCClientID = "client-id"
+				c.OIDCExtraClaims = `[{"hd":"modelcontextprotocol.io"}]`
+			},
+		},
+		{
+			name: "OIDC issuer without client ID",
+			modify: func(c *config.Config) {
+				c.OIDCEnabled = true
+				c.OIDCIssuer = "https://accounts.google.com"
+			},
+			wantEnv: "MCP_REGISTRY_OIDC_CLIENT_ID",
+			wantMsg: "required when OIDC_ENABLED",
+		},
+		{
+			name: "OIDC without issuer",
+			modify: func(c *config.Config) {
+				c.OIDCEnabled = true
+				c.OIDCClientID = "client-id"
+			},
+			wantEnv: "MCP_REGISTRY_OIDC_ISSUER",
+			wantMsg: "http(s) URL",
+		},
+		{
+			name: "OIDC extra claims not JSON",
+			modify: func(c *config.Config) {
+				c.OIDCEnabled = true
+				c.OIDCIssuer = "https://accounts.google.com"
+				c.OIDCClientID = "client-id"
+				c.OIDCExtraClaims = `{"hd":`
+			},
+			wantEnv: "MCP_REGISTRY_OIDC_EXTRA_CLAIMS",
+			wantMsg: "JSON array",
+		},
+		{
+			name:   "OIDC settings ignored when disabled",
+			modify: func(c *config.Config) { c.OIDCIssuer = "not a url" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.wantEnv == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr config.ValidationError
+			require.True(t, errors.As(err, &validationErr), "expected a ValidationError, got %v", err)
+			require.Len(t, validationErr, 1)
+			assert.Equal(t, tt.wantEnv, validationErr[0].Env)
+			assert.Contains(t, validationErr[0].Message, tt.wantMsg)
+		})
+	}
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTPrivateKey = "deadbeef"
+	cfg.OIDCEnabled = true
+	cfg.Replicas = 0
+
+	err := cfg.Validate()
+	require.Error(t, err)
+
+	var validationErr config.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	envs := make([]string, len(validationErr))
+	for i, fieldErr := range validationErr {
+		envs[i] = fieldErr.Env
+	}
+	assert.ElementsMatch(t, []string{
+		"MCP_REGISTRY_JWT_PRIVATE_KEY",
+		"MCP_REGISTRY_REPLICAS",
+		"MCP_REGISTRY_OIDC_ISSUER",
+		"MCP_REGISTRY_OIDC_CLIENT_ID",
+	}, envs)
+
+	// Each problem is on its own line, prefixed with its environment variable
+	lines := strings.Split(err.Error(), "\n")
+	assert.Equal(t, "invalid configuration:", lines[0])
+	assert.Len(t, lines, 5)
+	assert.Contains(t, err.Error(), "  - MCP_REGISTRY_JWT_PRIVATE_KEY: must be a 32-byte Ed25519 seed")
+}
@@ -0,0 +1,495 @@
+//nolint:testpackage
+package database
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// backends opens an empty database of each implementation, for tests every implementation must
+// pass. PostgreSQL is skipped unless MCP_REGISTRY_TEST_DATABASE_URL is set.
+var backends = map[string]func(t *testing.T) Database{
+	"memory": func(*testing.T) Database { return NewMemoryDB() },
+	"postgresql": func(t *testing.T) Database {
+		connConfig := freshDatabase(t)
+		databaseURL, err := url.Parse(os.Getenv("MCP_REGISTRY_TEST_DATABASE_URL"))
+		require.NoError(t, err)
+		databaseURL.Path = "/" + connConfig.Database
+		db, err := NewPostgreSQL(context.Background(), databaseURL.String(), PoolOptions{})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		return db
+	},
+	"sqlite": func(t *testing.T) Database {
+		db, err := NewSQLite(context.Background(), filepath.Join(t.TempDir(), "registry.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		return db
+	},
+}
+
+// clearSearchKeywords makes every stored version look like it predates search keywords, for
+// the backends that store them
+func clearSearchKeywords(t *testing.T, db Database) bool {
+	t.Helper()
+	var err error
+	switch db := db.(type) {
+	case *PostgreSQL:
+		_, err = db.pool.Exec(context.Background(), `UPDATE servers SET search_keywords = NULL`)
+	case *SQLite:
+		_, err = db.db.ExecContext(context.Background(), `UPDATE servers SET search_keywords = NULL`)
+	default:
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestConformance_Servers(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+
+			server := func(id, name, version string, age time.Duration, isLatest bool) *apiv0.ServerJSON {
+				at := now.Add(-age)
+				return &apiv0.ServerJSON{
+					Name: name, Description: "A server", Version: version,
+					Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{ID: id, PublishedAt: at, UpdatedAt: at, IsLatest: isLatest}},
+				}
+			}
+			weatherV1 := server("0b6c2f9e-1a3d-4e5f-8a7b-9c0d1e2f3a4b", "com.example/weather", "1.0.0", 3*time.Hour, false)
+			weatherV2 := server("1c7d3a0f-2b4e-4f6a-9b8c-0d1e2f3a4b5c", "com.example/weather", "2.0.0", 2*time.Hour, true)
+			weatherV2.License = "MIT OR Apache-2.0"
+			weatherV2.MCPVersion = "2025-06-18"
+			weatherV2.Packages = []model.Package{
+				{RegistryType: "npm", Identifier: "@example/weather", Version: "2.0.0", RunTimeHint: "npx"},
+				{RegistryType: "pypi", Identifier: "example-weather", Version: "2.0.0"},
+			}
+			maps := server("2d8e4b1a-3c5f-4a7b-8c9d-1e2f3a4b5c6d", "io.github.acme/maps", "0.1.0", time.Hour, true)
+			maps.Remotes = []model.Transport{{Type: "streamable-http", URL: "https://maps.acme.dev/mcp"}}
+			maps.ForkOf = "com.example/weather"
+			maps.MCPVersion = "2025-03-26"
+			pending := server("3e9f5c2b-4d6a-4b8c-9d0e-2f3a4b5c6d7e", "com.example/weather", "3.0.0", 0, false)
+			pending.Status = model.StatusPending
+			for _, s := range []*apiv0.ServerJSON{weatherV1, weatherV2, maps, pending} {
+				_, err := db.CreateServer(ctx, s)
+				require.NoError(t, err)
+			}
+			_, err := db.CreateServer(ctx, maps)
+			assert.ErrorIs(t, err, ErrAlreadyExists)
+
+			list := func(filter *ServerFilter) []string {
+				t.Helper()
+				servers, _, err := db.List(ctx, filter, "", 10)
+				require.NoError(t, err)
+				ids := []string{}
+				for _, s := range servers {
+					ids = append(ids, s.Meta.Official.ID)
+				}
+				return ids
+			}
+			ptr := func(s string) *string { return &s }
+			isLatest := true
+			status := model.StatusPending
+			since := now.Add(-150 * time.Minute)
+
+			assert.Equal(t, []string{weatherV1.Meta.Official.ID, weatherV2.Meta.Official.ID, maps.Meta.Official.ID, pending.Meta.Official.ID}, list(nil))
+			assert.Equal(t, []string{weatherV1.Meta.Official.ID, weatherV2.Meta.Official.ID, maps.Meta.Official.ID}, list(&ServerFilter{ExcludeHidden: true}))
+			assert.Equal(t, []string{maps.Meta.Official.ID}, list(&ServerFilter{Name: ptr("io.github.acme/maps")}))
+			assert.Equal(t, []string{maps.Meta.Official.ID}, list(&ServerFilter{RemoteURL: ptr("https://maps.acme.dev/mcp")}))
+			assert.Equal(t, []string{maps.Meta.Official.ID}, list(&ServerFilter{SubstringName: ptr("acme")}))
+			assert.Equal(t, []string{weatherV1.Meta.Official.ID, weatherV2.Meta.Official.ID, pending.Meta.Official.ID}, list(&ServerFilter{Namespace: ptr("com.example")}))
+			assert.Equal(t, []string{weatherV1.Meta.Official.ID}, list(&ServerFilter{Version: ptr("1.0.0")}))
+			assert.Equal(t, []string{weatherV2.Meta.Official.ID, maps.Meta.Official.ID}, list(&ServerFilter{IsLatest: &isLatest}))
+			assert.Equal(t, []string{weatherV2.Meta.Official.ID}, list(&ServerFilter{RegistryType: ptr("npm"), RuntimeHint: ptr("npx")}))
+			assert.Empty(t, list(&ServerFilter{RegistryType: ptr("pypi"), RuntimeHint: ptr("npx")}), "both fields must match one package")
+			assert.Equal(t, []string{weatherV2.Meta.Official.ID}, list(&ServerFilter{License: ptr("apache-2.0")}))
+			assert.Empty(t, list(&ServerFilter{License: ptr("Apache")}))
+			assert.Equal(t, []string{maps.Meta.Official.ID}, list(&ServerFilter{ForkOf: ptr("com.example/weather")}))
+			assert.Empty(t, list(&ServerFilter{ForkOf: ptr("io.github.acme/maps")}))
+			assert.Equal(t, []string{weatherV1.Meta.Official.ID, maps.Meta.Official.ID}, list(&ServerFilter{CompatibleWith: ptr("2025-03-26"), ExcludeHidden: true}), "unknown versions included")
+			assert.Equal(t, []string{maps.Meta.Official.ID}, list(&ServerFilter{CompatibleWith: ptr("2025-03-26"), ExcludeUnknownMCPVersion: true}))
+			assert.Equal(t, []string{weatherV2.Meta.Official.ID, maps.Meta.Official.ID}, list(&ServerFilter{CompatibleWith: ptr("2099-01-01"), ExcludeUnknownMCPVersion: true}))
+			assert.Equal(t, []string{weatherV2.Meta.Official.ID, maps.Meta.Official.ID}, list(&ServerFilter{ExcludeUnknownMCPVersion: true}))
+			assert.Equal(t, []string{pending.Meta.Official.ID}, list(&ServerFilter{Status: &status}))
+			assert.Equal(t, []string{weatherV2.Meta.Official.ID, maps.Meta.Official.ID, pending.Meta.Official.ID}, list(&ServerFilter{UpdatedSince: &since}), "oldest change first")
+
+			total, err := db.Count(ctx, &ServerFilter{Namespace: ptr("com.example"), ExcludeHidden: true})
+			require.NoError(t, err)
+			assert.Equal(t, 2, total)
+			namespaces, err := db.CountNamespaces(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{"com.example": 1, "io.github.acme": 1}, namespaces)
+
+			// Pages continue after the cursor
+			page, cursor, err := db.List(ctx, nil, "", 3)
+			require.NoError(t, err)
+			require.Len(t, page, 3)
+			assert.Equal(t, maps.Meta.Official.ID, cursor)
+			page, cursor, err = db.List(ctx, nil, cursor, 3)
+			require.NoError(t, err)
+			require.Len(t, page, 1)
+			assert.Equal(t, pending.Meta.Official.ID, page[0].Meta.Official.ID)
+			assert.Empty(t, cursor)
+			_, _, err = db.List(ctx, nil, "not-a-cursor", 3)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+			_, _, err = db.List(ctx, nil, "4f0a6d3c-5e7b-4c9d-8e1f-3a4b5c6d7e8f", 3)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+
+			found, missing, err := db.GetByIDs(ctx, []string{maps.Meta.Official.ID, "4f0a6d3c-5e7b-4c9d-8e1f-3a4b5c6d7e8f", "not-an-id"})
+			require.NoError(t, err)
+			assert.Len(t, found, 1)
+			assert.Equal(t, "io.github.acme/maps", found[maps.Meta.Official.ID].Name)
+			assert.ElementsMatch(t, []string{"4f0a6d3c-5e7b-4c9d-8e1f-3a4b5c6d7e8f", "not-an-id"}, missing)
+
+			// Heads skip held versions
+			head, err := db.FindHead(ctx, "com.example/weather", "")
+			require.NoError(t, err)
+			assert.Equal(t, weatherV2.Meta.Official.ID, head.ID)
+			assert.Equal(t, "2.0.0", head.Version)
+			assert.True(t, head.IsLatest)
+			assert.True(t, head.LastModified.Equal(weatherV2.LastModified()))
+			head, err = db.FindHead(ctx, "com.example/weather", "1.0.0")
+			require.NoError(t, err)
+			assert.False(t, head.IsLatest)
+			_, err = db.FindHead(ctx, "com.example/weather", "3.0.0")
+			assert.ErrorIs(t, err, ErrNotFound)
+			head, err = db.GetHeadByID(ctx, pending.Meta.Official.ID)
+			require.NoError(t, err)
+			assert.Equal(t, model.StatusPending, head.Status)
+			_, err = db.GetHeadByID(ctx, "not-an-id")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			// Updates replace the document
+			weatherV1.Status = model.StatusDeprecated
+			weatherV1.Meta.Official.UpdatedAt = now
+			_, err = db.UpdateServer(ctx, weatherV1.Meta.Official.ID, weatherV1)
+			require.NoError(t, err)
+			stored, err := db.GetByID(ctx, weatherV1.Meta.Official.ID)
+			require.NoError(t, err)
+			assert.Equal(t, model.StatusDeprecated, stored.Status)
+			unknown := server("4f0a6d3c-5e7b-4c9d-8e1f-3a4b5c6d7e8f", "com.example/weather", "4.0.0", 0, false)
+			_, err = db.UpdateServer(ctx, unknown.Meta.Official.ID, unknown)
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestConformance_Tenancy(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			acme, globex := tenancy.WithTenant(context.Background(), "acme"), tenancy.WithTenant(context.Background(), "globex")
+			now := time.Now().UTC().Truncate(time.Millisecond)
+
+			server := &apiv0.ServerJSON{
+				Name: "com.acme/tools", Description: "Tools", Version: "1.0.0",
+				Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{
+					ID: "5a1b7e4d-6f8c-4d0e-9f2a-4b5c6d7e8f90", PublishedAt: now, UpdatedAt: now, IsLatest: true, Tenant: "acme",
+				}},
+			}
+			_, err := db.CreateServer(globex, server)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+			_, err = db.CreateServer(acme, server)
+			require.NoError(t, err)
+
+			servers, _, err := db.List(acme, nil, "", 10)
+			require.NoError(t, err)
+			assert.Len(t, servers, 1)
+			servers, _, err = db.List(globex, nil, "", 10)
+			require.NoError(t, err)
+			assert.Empty(t, servers)
+			_, _, err = db.List(globex, nil, server.Meta.Official.ID, 10)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+			_, err = db.GetByID(globex, server.Meta.Official.ID)
+			assert.ErrorIs(t, err, ErrNotFound)
+			_, err = db.FindHead(globex, server.Name, "")
+			assert.ErrorIs(t, err, ErrNotFound)
+			_, err = db.UpdateServer(globex, server.Meta.Official.ID, server)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			// Unscoped contexts see every tenant
+			_, err = db.GetByID(context.Background(), server.Meta.Official.ID)
+			require.NoError(t, err)
+
+			require.NoError(t, db.RecordNamespaceVerification(acme, &NamespaceVerification{Namespace: "com.acme", Method: "dns", Subject: "acme.com", VerifiedAt: now}))
+			verification, err := db.GetNamespaceVerification(acme, "com.acme")
+			require.NoError(t, err)
+			assert.Equal(t, "acme", verification.Tenant)
+			_, err = db.GetNamespaceVerification(globex, "com.acme")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, db.PutNamespaceSettings(acme, &NamespaceSettings{Namespace: "com.acme", License: "MIT", UpdatedBy: "acme", UpdatedAt: now}))
+			settings, err := db.GetNamespaceSettings(acme, "com.acme")
+			require.NoError(t, err)
+			assert.Equal(t, "acme", settings.Tenant)
+			_, err = db.GetNamespaceSettings(globex, "com.acme")
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestConformance_Records(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+
+			// Challenges can be consumed once
+			challenge := &AuthChallenge{Nonce: "nonce-1", Domain: "example.com", IssuedAt: now, ExpiresAt: now.Add(time.Minute)}
+			require.NoError(t, db.CreateAuthChallenge(ctx, challenge))
+			assert.ErrorIs(t, db.CreateAuthChallenge(ctx, challenge), ErrAlreadyExists)
+			consumed, err := db.ConsumeAuthChallenge(ctx, "nonce-1")
+			require.NoError(t, err)
+			assert.Equal(t, "example.com", consumed.Domain)
+			assert.True(t, consumed.ExpiresAt.Equal(challenge.ExpiresAt))
+			_, err = db.ConsumeAuthChallenge(ctx, "nonce-1")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			// Expired challenges are purged
+			require.NoError(t, db.CreateAuthChallenge(ctx, &AuthChallenge{Nonce: "nonce-2", Domain: "example.com", IssuedAt: now, ExpiresAt: now.Add(time.Minute)}))
+			require.NoError(t, db.CreateAuthChallenge(ctx, &AuthChallenge{Nonce: "nonce-3", Domain: "example.com", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+			purged, err := db.PurgeExpiredAuthChallenges(ctx, now.Add(2*time.Minute))
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), purged)
+			_, err = db.ConsumeAuthChallenge(ctx, "nonce-2")
+			assert.ErrorIs(t, err, ErrNotFound)
+			_, err = db.ConsumeAuthChallenge(ctx, "nonce-3")
+			require.NoError(t, err)
+
+			// Reservations are replaced by namespace
+			require.NoError(t, db.PutNamespaceReservation(ctx, &NamespaceReservation{Namespace: "io.modelcontextprotocol.*", Reason: "official", CreatedBy: "admin", CreatedAt: now}))
+			require.NoError(t, db.PutNamespaceReservation(ctx, &NamespaceReservation{Namespace: "com.acme", AllowedSubjects: []string{"github-at:acme"}, CreatedBy: "admin", CreatedAt: now}))
+			require.NoError(t, db.PutNamespaceReservation(ctx, &NamespaceReservation{Namespace: "com.acme", AllowedSubjects: []string{"github-at:acme", "dns:acme.com"}, CreatedBy: "root", CreatedAt: now}))
+			reservations, err := db.ListNamespaceReservations(ctx)
+			require.NoError(t, err)
+			require.Len(t, reservations, 2)
+			assert.Equal(t, "com.acme", reservations[0].Namespace)
+			assert.Equal(t, []string{"github-at:acme", "dns:acme.com"}, reservations[0].AllowedSubjects)
+			assert.Equal(t, "root", reservations[0].CreatedBy)
+			require.NoError(t, db.DeleteNamespaceReservation(ctx, "com.acme"))
+			assert.ErrorIs(t, db.DeleteNamespaceReservation(ctx, "com.acme"), ErrNotFound)
+
+			// Verifications are replaced by namespace
+			require.NoError(t, db.RecordNamespaceVerification(ctx, &NamespaceVerification{Namespace: "com.acme", Method: "dns", Subject: "acme.com", VerifiedAt: now.Add(-time.Hour)}))
+			require.NoError(t, db.RecordNamespaceVerification(ctx, &NamespaceVerification{Namespace: "com.acme", Method: "http", Subject: "acme.com", VerifiedAt: now}))
+			verification, err := db.GetNamespaceVerification(ctx, "com.acme")
+			require.NoError(t, err)
+			assert.Equal(t, "http", verification.Method)
+			assert.True(t, verification.VerifiedAt.Equal(now))
+			_, err = db.GetNamespaceVerification(ctx, "com.globex")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			// Settings are replaced by namespace
+			require.NoError(t, db.PutNamespaceSettings(ctx, &NamespaceSettings{Namespace: "com.acme", License: "MIT", Categories: []string{"weather"}, UpdatedBy: "acme", UpdatedAt: now.Add(-time.Hour)}))
+			require.NoError(t, db.PutNamespaceSettings(ctx, &NamespaceSettings{
+				Namespace: "com.acme", License: "Apache-2.0", Categories: []string{"weather", "maps"},
+				WebsiteURL: "https://acme.com", Contact: "mcp@acme.com", UpdatedBy: "root", UpdatedAt: now,
+			}))
+			settings, err := db.GetNamespaceSettings(ctx, "com.acme")
+			require.NoError(t, err)
+			assert.Equal(t, "Apache-2.0", settings.License)
+			assert.Equal(t, []string{"weather", "maps"}, settings.Categories)
+			assert.Equal(t, "https://acme.com", settings.WebsiteURL)
+			assert.Equal(t, "mcp@acme.com", settings.Contact)
+			assert.Equal(t, "root", settings.UpdatedBy)
+			assert.True(t, settings.UpdatedAt.Equal(now))
+			require.NoError(t, db.PutNamespaceSettings(ctx, &NamespaceSettings{Namespace: "com.acme", UpdatedBy: "root", UpdatedAt: now}))
+			settings, err = db.GetNamespaceSettings(ctx, "com.acme")
+			require.NoError(t, err)
+			assert.Empty(t, settings.License)
+			assert.Empty(t, settings.Categories)
+			_, err = db.GetNamespaceSettings(ctx, "com.globex")
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestConformance_ServerFetchCounts(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			ctx := context.Background()
+			day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+			require.NoError(t, db.AddServerFetchCounts(ctx, nil))
+			require.NoError(t, db.AddServerFetchCounts(ctx, []ServerFetchCount{
+				{Namespace: "com.acme", ServerName: "com.acme/tools", Day: day.Add(15 * time.Hour), Count: 2},
+				{Namespace: "com.acme", ServerName: "com.acme/tools", Day: day.Add(16 * time.Hour), Count: 1},
+				{Namespace: "com.acme", ServerName: "com.acme/docs", Day: day, Count: 4},
+				{Namespace: "com.acme", ServerName: "com.acme/docs", Day: day.AddDate(0, 0, 1), Count: 1},
+				{Namespace: "com.acme", ServerName: "com.acme/old", Day: day.AddDate(0, 0, -1), Count: 9},
+				{Namespace: "com.globex", ServerName: "com.globex/tools", Day: day, Count: 5},
+				{Tenant: "acme", Namespace: "com.acme", ServerName: "com.acme/tools", Day: day, Count: 7},
+			}))
+			// Later batches add to the counts
+			require.NoError(t, db.AddServerFetchCounts(ctx, []ServerFetchCount{
+				{Namespace: "com.acme", ServerName: "com.acme/tools", Day: day, Count: 3},
+			}))
+
+			counts, err := db.ListServerFetchCounts(ctx, "com.acme", day.Add(12*time.Hour), day.AddDate(0, 0, 1))
+			require.NoError(t, err)
+			assert.Equal(t, []ServerFetchCount{
+				{Namespace: "com.acme", ServerName: "com.acme/docs", Day: day, Count: 4},
+				{Namespace: "com.acme", ServerName: "com.acme/tools", Day: day, Count: 6},
+				{Namespace: "com.acme", ServerName: "com.acme/docs", Day: day.AddDate(0, 0, 1), Count: 1},
+			}, counts)
+
+			// Each tenant sees only its own counts
+			counts, err = db.ListServerFetchCounts(tenancy.WithTenant(ctx, "acme"), "com.acme", day, day)
+			require.NoError(t, err)
+			require.Len(t, counts, 1)
+			assert.Equal(t, int64(7), counts[0].Count)
+			counts, err = db.ListServerFetchCounts(tenancy.WithTenant(ctx, "globex"), "com.acme", day, day)
+			require.NoError(t, err)
+			assert.Empty(t, counts)
+		})
+	}
+}
+
+func TestConformance_ServerReports(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			const toolsID, docsID = "5a1b7e4d-6f8c-4d0e-9f2a-4b5c6d7e8f90", "6b2c8f5e-7a9d-4e1f-8a3b-5c6d7e8f9a01"
+
+			report := func(id, serverID, name, category string, age time.Duration, tenant string) *ServerReport {
+				namespace, _, _ := strings.Cut(name, "/")
+				return &ServerReport{
+					ID: id, ServerID: serverID, Namespace: namespace, ServerName: name, Version: "1.0.0",
+					Category: category, Text: "It is broken", Tenant: tenant, CreatedAt: now.Add(-age),
+				}
+			}
+			for _, r := range []*ServerReport{
+				report("0e1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a01", toolsID, "com.acme/tools", "dead-remote", 2*time.Hour, ""),
+				report("0e1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a02", toolsID, "com.acme/tools", "dead-remote", time.Hour, ""),
+				report("0e1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a03", toolsID, "com.acme/tools", "spam", 3*time.Hour, ""),
+				report("0e1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a04", docsID, "com.acme/docs", "malware", time.Hour, ""),
+				report("0e1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a05", "7c3d9a6f-8b0e-4f2a-9b4c-6d7e8f9a0b12", "com.globex/tools", "spam", time.Hour, "globex"),
+			} {
+				require.NoError(t, db.CreateServerReport(ctx, r))
+			}
+			err := db.CreateServerReport(ctx, report("0e1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a01", toolsID, "com.acme/tools", "spam", 0, ""))
+			assert.ErrorIs(t, err, ErrAlreadyExists)
+
+			counts, err := db.ListServerReportCounts(ctx, ServerReportFilter{Namespace: "com.acme"})
+			require.NoError(t, err)
+			assert.Equal(t, []ServerReportCount{
+				{ServerID: docsID, ServerName: "com.acme/docs", Version: "1.0.0", Category: "malware", Count: 1, LastReportedAt: now.Add(-time.Hour)},
+				{ServerID: toolsID, ServerName: "com.acme/tools", Version: "1.0.0", Category: "dead-remote", Count: 2, LastReportedAt: now.Add(-time.Hour)},
+				{ServerID: toolsID, ServerName: "com.acme/tools", Version: "1.0.0", Category: "spam", Count: 1, LastReportedAt: now.Add(-3 * time.Hour)},
+			}, normalizeReportCounts(counts))
+
+			counts, err = db.ListServerReportCounts(ctx, ServerReportFilter{ServerID: docsID})
+			require.NoError(t, err)
+			assert.Len(t, counts, 1)
+			counts, err = db.ListServerReportCounts(ctx, ServerReportFilter{})
+			require.NoError(t, err)
+			assert.Len(t, counts, 4, "unscoped contexts count every tenant's reports")
+
+			reports, err := db.ListServerReports(ctx, toolsID)
+			require.NoError(t, err)
+			require.Len(t, reports, 3)
+			assert.Equal(t, "0e1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a02", reports[0].ID, "newest first")
+			assert.Equal(t, "It is broken", reports[0].Text)
+
+			// Each tenant sees only reports about its own servers
+			globex := tenancy.WithTenant(ctx, "globex")
+			counts, err = db.ListServerReportCounts(globex, ServerReportFilter{})
+			require.NoError(t, err)
+			require.Len(t, counts, 1)
+			assert.Equal(t, "com.globex/tools", counts[0].ServerName)
+			reports, err = db.ListServerReports(globex, toolsID)
+			require.NoError(t, err)
+			assert.Empty(t, reports)
+		})
+	}
+}
+
+// normalizeReportCounts puts report times in UTC, as backends return them in other zones
+func normalizeReportCounts(counts []ServerReportCount) []ServerReportCount {
+	for i := range counts {
+		counts[i].LastReportedAt = counts[i].LastReportedAt.UTC()
+	}
+	return counts
+}
+
+func TestConformance_TransactionRollback(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			server := &apiv0.ServerJSON{
+				Name: "com.example/rollback", Description: "Never stored", Version: "1.0.0",
+				Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{ID: "6b2c8f5e-7a9d-4e1f-8a3b-5c6d7e8f9a01", PublishedAt: now, UpdatedAt: now, IsLatest: true}},
+			}
+
+			failed := errors.New("publish failed")
+			err := db.InTransaction(ctx, func(ctx context.Context, tx Database) error {
+				if _, err := tx.CreateServer(ctx, server); err != nil {
+					return err
+				}
+				// Nested transactions join the outer one
+				return tx.InTransaction(ctx, func(ctx context.Context, tx Database) error {
+					if _, err := tx.GetByID(ctx, server.Meta.Official.ID); err != nil {
+						return err
+					}
+					return failed
+				})
+			})
+			require.ErrorIs(t, err, failed)
+			_, err = db.GetByID(ctx, server.Meta.Official.ID)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, db.InTransaction(ctx, func(ctx context.Context, tx Database) error {
+				_, err := tx.CreateServer(ctx, server)
+				return err
+			}))
+			_, err = db.GetByID(ctx, server.Meta.Official.ID)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSQLite_PersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "registry.db")
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	db, err := NewSQLite(ctx, path)
+	require.NoError(t, err)
+	_, err = db.CreateServer(ctx, &apiv0.ServerJSON{
+		Name: "com.example/durable", Description: "Survives restarts", Version: "1.0.0",
+		Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{ID: "7c3d9a6f-8b0e-4f2a-9b4c-6d7e8f9a0b12", PublishedAt: now, UpdatedAt: now, IsLatest: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// Reopening applies no migrations twice and finds the stored server
+	db, err = NewSQLite(ctx, path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	head, err := db.FindHead(ctx, "com.example/durable", "")
+	require.NoError(t, err)
+	assert.Equal(t, "7c3d9a6f-8b0e-4f2a-9b4c-6d7e8f9a0b12", head.ID)
+	assert.True(t, head.LastModified.Equal(now))
+}
@@ -6,42 +6,277 @@ import (
 	"time"
 
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
-// Common database errors
+// Common database errors. Both backends report these conditions with these errors, wrapped at
+// most, so callers classify them with errors.Is.
 var (
 	ErrNotFound          = errors.New("record not found")
 	ErrAlreadyExists     = errors.New("record already exists")
 	ErrInvalidInput      = errors.New("invalid input")
+	ErrInvalidCursor     = errors.New("invalid cursor: not a position returned by a previous page")
 	ErrDatabase          = errors.New("database error")
+	ErrTransient         = errors.New("transient database error: the request can be retried")
+	ErrQueryTimeout      = errors.New("query timed out: narrow the search or filters")
 	ErrInvalidVersion    = errors.New("invalid version: cannot publish duplicate version")
 	ErrMaxServersReached = errors.New("maximum number of versions for this server reached (10000): please reach out at https://github.com/modelcontextprotocol/registry to explain your use case")
 )
 
 // ServerFilter defines filtering options for server queries
 type ServerFilter struct {
-	Name          *string    // for finding versions of same server
-	RemoteURL     *string    // for duplicate URL detection
-	UpdatedSince  *time.Time // for incremental sync filtering
-	SubstringName *string    // for substring search on name
-	Version       *string    // for exact version matching
-	IsLatest      *bool      // for filtering latest versions only
+	Name                     *string       // for finding versions of same server
+	RemoteURL                *string       // for duplicate URL detection: has a remote with this URL, compared by CanonicalRemoteURL
+	UpdatedSince             *time.Time    // for incremental sync: changed at or after this time, oldest change first
+	SubstringName            *string       // for substring search on name
+	Search                   *string       // for search: the name contains it, or the server has all its SearchTokens as keywords; name matches list first
+	Namespace                *string       // for namespace listings: names under this namespace (the part before the slash)
+	Version                  *string       // for exact version matching
+	IsLatest                 *bool         // for filtering latest versions only
+	RegistryType             *string       // for package filtering: has a package from this registry (e.g. npm)
+	RuntimeHint              *string       // for package filtering: has a package with this runtime hint; with RegistryType, the same package
+	License                  *string       // for license filtering: the license expression mentions this SPDX identifier (case-insensitive)
+	ForkOf                   *string       // for fork listings: declares itself a fork of the server with this name
+	CompatibleWith           *string       // for client compatibility: declares no MCP protocol revision, or one no newer than this (revisions are dates, compared as strings)
+	ExcludeUnknownMCPVersion bool          // for client compatibility: declares an MCP protocol revision
+	Status                   *model.Status // for admin review: only versions with this status
+	ExcludeHidden            bool          // for public listings: hide versions held for or rejected by admin review
+	Projection               Projection    // for list summaries: which parts of each server to load
+}
+
+// Projection selects which parts of each server document List loads
+type Projection string
+
+const (
+	// ProjectionFull loads complete server documents
+	ProjectionFull Projection = ""
+	// ProjectionSummary loads only the fields of apiv0.ServerSummary; implementations
+	// may still return more, callers must project the results themselves
+	ProjectionSummary Projection = "summary"
+)
+
+// ServerHead is the registry metadata of a server version, for existence checks that do not need its document
+type ServerHead struct {
+	ID           string
+	Name         string
+	Version      string
+	Status       model.Status
+	IsLatest     bool
+	LastModified time.Time // when the version was published or its registry metadata last changed
+}
+
+// AuthChallenge is a single-use, server-issued nonce for challenge-response authentication
+type AuthChallenge struct {
+	Nonce     string
+	Domain    string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// NamespaceNotification registers a webhook or email address to be told about every publish under a namespace
+type NamespaceNotification struct {
+	ID         string
+	Namespace  string
+	WebhookURL string // set for webhook registrations
+	Email      string // set for email registrations
+	CreatedBy  string // subject of the token that registered it
+	Tenant     string // set from the context it was created in; empty in single-tenant mode
+	CreatedAt  time.Time
+}
+
+// OutboxEvent is a notification waiting to be delivered to one registration. Events are written in
+// the same transaction as the change they describe, so a restart never loses one.
+type OutboxEvent struct {
+	ID             string // also the idempotency key sent with every delivery attempt
+	RegistrationID string // the namespace notification it is delivered to
+	WebhookURL     string // set for webhook registrations
+	Email          string // set for email registrations
+	Event          string // the notification's event name, e.g. server.published
+	Payload        []byte // the JSON notification
+	CreatedAt      time.Time
+	Attempts       int       // delivery attempts started, including one in progress
+	NextAttemptAt  time.Time // when the event is next due, or its claim expires
+	LastError      string    // why the last attempt failed
+	CompletedAt    *time.Time
+}
+
+// NamespaceReservation keeps a namespace for the identities an admin allows to publish under it
+type NamespaceReservation struct {
+	Namespace       string   // a namespace, or a prefix ending in * such as io.modelcontextprotocol.*
+	AllowedSubjects []string // <auth method>:<subject> patterns that may still publish, e.g. github-at:octocat
+	Reason          string
+	CreatedBy       string // subject of the token that made the reservation
+	CreatedAt       time.Time
+}
+
+// NamespaceVerification records the latest proof that a publisher controls a namespace: the
+// authentication method and subject of the most recent publish under it
+type NamespaceVerification struct {
+	Namespace  string
+	Method     string // auth method, e.g. dns, http or github-oidc
+	Subject    string // what the method verified, e.g. the domain or the GitHub repository owner
+	Tenant     string // set from the context it was recorded in; each tenant verifies its own namespaces
+	VerifiedAt time.Time
+}
+
+// NamespaceSettings are the defaults a namespace's owners set for the optional server.json
+// fields of versions published under it. Empty fields have no default.
+type NamespaceSettings struct {
+	Namespace  string
+	License    string
+	Categories []string
+	WebsiteURL string
+	Contact    string
+	Tenant     string // set from the context it was stored in; each tenant has its own settings
+	UpdatedBy  string // subject of the token that last changed them
+	UpdatedAt  time.Time
+}
+
+// ServerFetchCount is how many times the details of a server were fetched on one day
+type ServerFetchCount struct {
+	Tenant     string // tenant the server belongs to
+	Namespace  string
+	ServerName string
+	Day        time.Time // midnight UTC of the day
+	Count      int64
+}
+
+// ServerReport is a user's report that a server version is broken or abusive
+type ServerReport struct {
+	ID         string
+	ServerID   string // registry metadata ID of the reported version
+	Namespace  string
+	ServerName string
+	Version    string
+	Category   string
+	Text       string // only shown to admins
+	Tenant     string // tenant the reported server belongs to
+	CreatedAt  time.Time
+}
+
+// ServerReportFilter selects the reports counted by ListServerReportCounts; empty fields match every report
+type ServerReportFilter struct {
+	Namespace string
+	ServerID  string
+}
+
+// ServerReportCount is how many reports of one category a server version has
+type ServerReportCount struct {
+	ServerID       string
+	ServerName     string
+	Version        string
+	Category       string
+	Count          int
+	LastReportedAt time.Time
 }
 
 // Database defines the interface for database operations
 type Database interface {
 	// Retrieve server entries with optional filtering
 	List(ctx context.Context, filter *ServerFilter, cursor string, limit int) ([]*apiv0.ServerJSON, string, error)
+	// Count the server entries matching the filter, across all pages
+	Count(ctx context.Context, filter *ServerFilter) (int, error)
 	// Retrieve a single server by its ID
 	GetByID(ctx context.Context, id string) (*apiv0.ServerJSON, error)
+	// GetByIDs retrieves several servers by ID in one lookup, keyed by ID. IDs that match no
+	// server are listed in missing, in the order given, rather than failing with ErrNotFound.
+	GetByIDs(ctx context.Context, ids []string) (found map[string]*apiv0.ServerJSON, missing []string, err error)
+	// GetHeadByID retrieves the registry metadata of a server version by ID, without loading its document
+	GetHeadByID(ctx context.Context, id string) (*ServerHead, error)
+	// FindHead retrieves the registry metadata of a server version by name and version, or of the
+	// server's latest version if version is empty. Versions held for admin approval are not found.
+	FindHead(ctx context.Context, name, version string) (*ServerHead, error)
+	// CountNamespaces returns how many distinct servers each namespace (the part of the name
+	// before the slash) has, not counting versions held for admin approval
+	CountNamespaces(ctx context.Context) (map[string]int, error)
 	// CreateServer adds a new server to the database
 	CreateServer(ctx context.Context, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error)
 	// UpdateServer updates an existing server record
 	UpdateServer(ctx context.Context, id string, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error)
+	// CreateAuthChallenge stores a new authentication challenge
+	CreateAuthChallenge(ctx context.Context, challenge *AuthChallenge) error
+	// ConsumeAuthChallenge retrieves and deletes a challenge by nonce, so it can only be used once
+	ConsumeAuthChallenge(ctx context.Context, nonce string) (*AuthChallenge, error)
+	// PurgeExpiredAuthChallenges deletes challenges that expired before now, returning how many it deleted
+	PurgeExpiredAuthChallenges(ctx context.Context, now time.Time) (int64, error)
+	// CreateNamespaceNotification stores a publish notification registration
+	CreateNamespaceNotification(ctx context.Context, notification *NamespaceNotification) error
+	// ListNamespaceNotifications returns the publish notification registrations for a namespace
+	ListNamespaceNotifications(ctx context.Context, namespace string) ([]*NamespaceNotification, error)
+	// DeleteNamespaceNotification removes a publish notification registration by ID
+	DeleteNamespaceNotification(ctx context.Context, id string) error
+	// CreateOutboxEvent stores a notification to deliver; write it in the transaction of the change it describes
+	CreateOutboxEvent(ctx context.Context, event *OutboxEvent) error
+	// ClaimOutboxEvents returns up to limit uncompleted events due at now, oldest first, counting an
+	// attempt and pushing NextAttemptAt to now+lease so no other dispatcher claims them meanwhile.
+	// An event whose dispatcher stops before completing it is claimed again once the lease passes.
+	ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxEvent, error)
+	// RetryOutboxEvent records a failed delivery attempt, making the event due again at nextAttemptAt
+	RetryOutboxEvent(ctx context.Context, id string, nextAttemptAt time.Time, lastError string) error
+	// CompleteOutboxEvent marks an event delivered, or with lastError, abandoned after its last attempt
+	CompleteOutboxEvent(ctx context.Context, id string, completedAt time.Time, lastError string) error
+	// PurgeCompletedOutboxEvents deletes events completed more than a week before now, which are only
+	// kept for investigating deliveries, returning how many it deleted
+	PurgeCompletedOutboxEvents(ctx context.Context, now time.Time) (int64, error)
+	// ListNamespaceReservations returns every namespace reservation
+	ListNamespaceReservations(ctx context.Context) ([]*NamespaceReservation, error)
+	// PutNamespaceReservation stores a namespace reservation, replacing any for the same namespace
+	PutNamespaceReservation(ctx context.Context, reservation *NamespaceReservation) error
+	// DeleteNamespaceReservation removes the reservation of a namespace
+	DeleteNamespaceReservation(ctx context.Context, namespace string) error
+	// RecordNamespaceVerification stores a namespace verification, replacing any earlier one for the namespace
+	RecordNamespaceVerification(ctx context.Context, verification *NamespaceVerification) error
+	// GetNamespaceVerification returns the latest verification of a namespace, or ErrNotFound
+	GetNamespaceVerification(ctx context.Context, namespace string) (*NamespaceVerification, error)
+	// PutNamespaceSettings stores the settings of a namespace, replacing any earlier ones
+	PutNamespaceSettings(ctx context.Context, settings *NamespaceSettings) error
+	// GetNamespaceSettings returns the settings of a namespace, or ErrNotFound if it has none
+	GetNamespaceSettings(ctx context.Context, namespace string) (*NamespaceSettings, error)
+	// AddServerFetchCounts adds each count to the fetch count of its server on its day, which is
+	// truncated to the day in UTC. Unlike most writes the tenant is taken from each count, since
+	// counts are written in batches outside the requests they attribute.
+	AddServerFetchCounts(ctx context.Context, counts []ServerFetchCount) error
+	// ListServerFetchCounts returns the fetch counts of the servers in a namespace on the days from
+	// from to to inclusive, ordered by day and then server name. Days without fetches are omitted.
+	ListServerFetchCounts(ctx context.Context, namespace string, from, to time.Time) ([]ServerFetchCount, error)
+	// CreateServerReport stores a report about a server version. Unlike most writes the tenant is
+	// taken from the report, since anyone may report a server of any tenant they can see.
+	CreateServerReport(ctx context.Context, report *ServerReport) error
+	// ListServerReportCounts counts the reports matching filter by server version and category,
+	// ordered by server name, version ID and category
+	ListServerReportCounts(ctx context.Context, filter ServerReportFilter) ([]ServerReportCount, error)
+	// ListServerReports returns the reports about a server version, newest first
+	ListServerReports(ctx context.Context, serverID string) ([]*ServerReport, error)
+	// BackfillSearchKeywords stores the search keywords of up to limit server versions written
+	// before keywords were, returning how many it updated
+	BackfillSearchKeywords(ctx context.Context, limit int) (int, error)
+	// InTransaction runs fn against a transactional view of the database, committing only if fn returns nil
+	InTransaction(ctx context.Context, fn func(ctx context.Context, tx Database) error) error
 	// Close closes the database connection
 	Close() error
 }
 
+// outboxRetention is how long completed outbox events are kept, for investigating deliveries
+const outboxRetention = 7 * 24 * time.Hour
+
+// fetchDay truncates t to the day in UTC that fetch counts are bucketed by
+func fetchDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// missingIDs lists the IDs without an entry in found, once each and in the order given
+func missingIDs(ids []string, found map[string]*apiv0.ServerJSON) []string {
+	var missing []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := found[id]; !ok && !seen[id] {
+			missing = append(missing, id)
+		}
+		seen[id] = true
+	}
+	return missing
+}
+
 // ConnectionType represents the type of database connection
 type ConnectionType string
 
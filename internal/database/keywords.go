@@ -0,0 +1,136 @@
+package database
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// minKeywordLength is the length below which tokens are too common to search by
+const minKeywordLength = 2
+
+// stopwords are description words that would match most servers
+var stopwords = map[string]bool{
+	"an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true, "for": true,
+	"from": true, "in": true, "into": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "with": true, "your": true,
+}
+
+// SearchKeywords derives the normalized keywords a server version is found by: the segments of
+// its name, its package identifiers, its repository path and the words of its description. They
+// are stored with each version when it is written, so search also matches, say, the npm package
+// a description never names.
+func SearchKeywords(server *apiv0.ServerJSON) []string {
+	set := map[string]bool{}
+	add := func(text string) {
+		for _, token := range searchTokens(text) {
+			set[token] = true
+		}
+	}
+
+	add(server.Name)
+	add(server.Title)
+	for _, pkg := range server.Packages {
+		add(urlPath(pkg.Identifier))
+	}
+	add(urlPath(server.Repository.URL))
+	add(server.Repository.Subfolder)
+	add(server.Description)
+
+	keywords := make([]string, 0, len(set))
+	for keyword := range set {
+		keywords = append(keywords, keyword)
+	}
+	slices.Sort(keywords)
+	return keywords
+}
+
+// SearchTokens splits a search query into the keywords a matching server must all have. Single
+// characters are kept, though never keywords, so that "server-3" only finds names containing it.
+func SearchTokens(query string) []string {
+	var tokens []string
+	for _, token := range words(query) {
+		if !stopwords[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	slices.Sort(tokens)
+	return slices.Compact(tokens)
+}
+
+// searchTokens splits text into keywords, without stopwords and single characters
+func searchTokens(text string) []string {
+	var tokens []string
+	for _, token := range words(text) {
+		if len(token) >= minKeywordLength && !stopwords[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// words lowercases text and splits it into words of letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// urlPath returns the path of a URL, whose scheme and host say nothing about the server, or
+// text unchanged if it is not a URL
+func urlPath(text string) string {
+	if !strings.Contains(text, "://") {
+		return text
+	}
+	parsed, err := url.Parse(text)
+	if err != nil {
+		return text
+	}
+	return parsed.Path
+}
+
+// nameMatchesAll reports whether there are tokens and every one appears in name, ranking the
+// server above those only found by their other keywords
+func nameMatchesAll(name string, tokens []string) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, token := range tokens {
+		if !strings.Contains(name, token) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSearch reports whether server is found by the search query
+func matchesSearch(server *apiv0.ServerJSON, query string) bool {
+	if strings.Contains(strings.ToLower(server.Name), strings.ToLower(query)) {
+		return true
+	}
+	tokens := SearchTokens(query)
+	if len(tokens) == 0 {
+		return false
+	}
+	keywords := SearchKeywords(server)
+	for _, token := range tokens {
+		if _, found := slices.BinarySearch(keywords, token); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// searchRank is 1 for servers whose name matches the search query, listed first, and 0 for
+// those only found by their other keywords
+func searchRank(server *apiv0.ServerJSON, query string) int {
+	name := strings.ToLower(server.Name)
+	if strings.Contains(name, strings.ToLower(query)) || nameMatchesAll(name, SearchTokens(query)) {
+		return 1
+	}
+	return 0
+}
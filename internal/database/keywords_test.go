@@ -0,0 +1,94 @@
+//nolint:testpackage
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestSearchKeywords(t *testing.T) {
+	keywords := SearchKeywords(&apiv0.ServerJSON{
+		Name:        "io.github.octocat/weather",
+		Description: "Forecasts for the city",
+		Repository:  model.Repository{URL: "https://github.com/octocat/weather-mcp", Source: "github"},
+		Packages: []model.Package{
+			{RegistryType: "npm", Identifier: "@octocat/weather-server"},
+			{RegistryType: "mcpb", Identifier: "https://example.com/releases/forecaster.mcpb"},
+		},
+	})
+	assert.Equal(t, []string{
+		"city", "forecaster", "forecasts", "github", "io", "mcp", "mcpb", "octocat", "releases", "server", "weather",
+	}, keywords)
+
+	assert.Equal(t, []string{"3", "server"}, SearchTokens("Server-3"))
+	assert.Equal(t, []string{"weather"}, SearchTokens("the weather"))
+}
+
+func TestSearch(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			meta := func(id string) *apiv0.ServerMeta {
+				return &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{ID: id, PublishedAt: now, UpdatedAt: now, IsLatest: true}}
+			}
+			search := func(query string) []string {
+				t.Helper()
+				servers, _, err := db.List(ctx, &ServerFilter{Search: &query}, "", 10)
+				require.NoError(t, err)
+				names := []string{}
+				for _, server := range servers {
+					names = append(names, server.Name)
+				}
+				return names
+			}
+
+			spreadsheets := &apiv0.ServerJSON{
+				Name: "com.example/spreadsheets", Description: "Read and write bases", Version: "1.0.0",
+				Packages: []model.Package{{RegistryType: "npm", Identifier: "@airtable/mcp-server", Version: "1.0.0"}},
+				Meta:     meta("5d1e0c8a-6b2f-4e3a-9c7d-1f0e2d3c4b5a"),
+			}
+			_, err := db.CreateServer(ctx, spreadsheets)
+			require.NoError(t, err)
+			_, err = db.CreateServer(ctx, &apiv0.ServerJSON{
+				Name: "com.airtable/sync", Description: "Keeps bases in sync", Version: "1.0.0",
+				Meta: meta("6e2f1d9b-7c3a-4f4b-8d8e-2a1f3e4d5c6b"),
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"com.airtable/sync", "com.example/spreadsheets"}, search("airtable"), "name matches first")
+			assert.Equal(t, []string{"com.example/spreadsheets"}, search("example"), "namespace segment")
+			assert.Empty(t, search("tables"))
+
+			// Edits recompute the keywords
+			spreadsheets.Description = "Read and write tables"
+			_, err = db.UpdateServer(ctx, spreadsheets.Meta.Official.ID, spreadsheets)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"com.example/spreadsheets"}, search("tables"))
+
+			// Versions stored before keywords are only found by name until backfilled
+			if clearSearchKeywords(t, db) {
+				assert.Equal(t, []string{"com.airtable/sync"}, search("airtable"))
+
+				updated, err := db.BackfillSearchKeywords(ctx, 1)
+				require.NoError(t, err)
+				assert.Equal(t, 1, updated)
+				updated, err = db.BackfillSearchKeywords(ctx, 10)
+				require.NoError(t, err)
+				assert.Equal(t, 1, updated)
+				assert.Equal(t, []string{"com.airtable/sync", "com.example/spreadsheets"}, search("airtable"))
+			}
+			updated, err := db.BackfillSearchKeywords(ctx, 10)
+			require.NoError(t, err)
+			assert.Zero(t, updated)
+		})
+	}
+}
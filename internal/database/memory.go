@@ -3,27 +3,96 @@ package database
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 )
 
 // MemoryDB is an in-memory implementation of the Database interface
 type MemoryDB struct {
-	entries map[string]*apiv0.ServerJSON // maps registry metadata ID to ServerJSON
-	mu      sync.RWMutex
+	entries       map[string]*apiv0.ServerJSON      // maps registry metadata ID to ServerJSON
+	challenges    map[string]*AuthChallenge         // maps nonce to auth challenge
+	notifications map[string]*NamespaceNotification // maps registration ID to namespace notification
+	outbox        map[string]*OutboxEvent           // maps event ID to outbox event
+	reservations  map[string]*NamespaceReservation  // maps namespace to its reservation
+	verifications map[string]*NamespaceVerification // maps tenant and namespace to its latest verification
+	settings      map[string]*NamespaceSettings     // maps tenant and namespace to its settings
+	fetchCounts   map[fetchCountKey]int64           // maps a server and day to its fetch count
+	reports       map[string]*ServerReport          // maps report ID to server report
+	mu            sync.RWMutex
 }
 
 func NewMemoryDB() *MemoryDB {
 	// Convert input ServerJSON entries to have proper metadata
 	serverRecords := make(map[string]*apiv0.ServerJSON)
 	return &MemoryDB{
-		entries: serverRecords,
+		entries:       serverRecords,
+		challenges:    make(map[string]*AuthChallenge),
+		notifications: make(map[string]*NamespaceNotification),
+		outbox:        make(map[string]*OutboxEvent),
+		reservations:  make(map[string]*NamespaceReservation),
+		verifications: make(map[string]*NamespaceVerification),
+		settings:      make(map[string]*NamespaceSettings),
+		fetchCounts:   make(map[fetchCountKey]int64),
+		reports:       make(map[string]*ServerReport),
 	}
 }
 
+// fetchCountKey identifies the fetch count of a server on a day
+type fetchCountKey struct {
+	tenant, namespace, serverName string
+	day                           time.Time
+}
+
+// Count returns how many entries match the filter
+func (db *MemoryDB) Count(ctx context.Context, filter *ServerFilter) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	count := 0
+	for _, entry := range db.entries {
+		if tenancy.Allows(ctx, tenancy.Of(entry)) && db.matchesFilter(entry, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountNamespaces returns how many distinct servers each namespace has, ignoring pending and rejected versions
+func (db *MemoryDB) CountNamespaces(ctx context.Context) (map[string]int, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	names := make(map[string]bool)
+	for _, entry := range db.entries {
+		if !entry.Status.Hidden() && tenancy.Allows(ctx, tenancy.Of(entry)) {
+			names[entry.Name] = true
+		}
+	}
+
+	counts := make(map[string]int)
+	for name := range names {
+		namespace, _, _ := strings.Cut(name, "/")
+		counts[namespace]++
+	}
+	return counts, nil
+}
+
 func (db *MemoryDB) List(
 	ctx context.Context,
 	filter *ServerFilter,
@@ -44,7 +113,9 @@ func (db *MemoryDB) List(
 	// Convert all entries to a slice for pagination
 	var allEntries []*apiv0.ServerJSON
 	for _, entry := range db.entries {
-		allEntries = append(allEntries, entry)
+		if tenancy.Allows(ctx, tenancy.Of(entry)) {
+			allEntries = append(allEntries, entry)
+		}
 	}
 
 	// Apply filtering and sorting
@@ -53,6 +124,9 @@ func (db *MemoryDB) List(
 	// Find starting point for cursor-based pagination
 	startIdx := 0
 	if cursor != "" {
+		if entry, exists := db.entries[cursor]; !exists || !tenancy.Allows(ctx, tenancy.Of(entry)) {
+			return nil, "", ErrInvalidCursor
+		}
 		for i, entry := range filteredEntries {
 			if db.getRegistryID(entry) == cursor {
 				startIdx = i + 1 // Start after the cursor
@@ -89,7 +163,7 @@ func (db *MemoryDB) GetByID(ctx context.Context, id string) (*apiv0.ServerJSON,
 	defer db.mu.RUnlock()
 
 	// Find entry by registry metadata ID
-	if entry, exists := db.entries[id]; exists {
+	if entry, exists := db.entries[id]; exists && tenancy.Allows(ctx, tenancy.Of(entry)) {
 		// Return a copy of the ServerRecord
 		entryCopy := *entry
 		return &entryCopy, nil
@@ -98,6 +172,76 @@ func (db *MemoryDB) GetByID(ctx context.Context, id string) (*apiv0.ServerJSON,
 	return nil, ErrNotFound
 }
 
+// GetByIDs retrieves several servers by ID, keyed by ID, listing the IDs that match none
+func (db *MemoryDB) GetByIDs(ctx context.Context, ids []string) (map[string]*apiv0.ServerJSON, []string, error) {
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	found := make(map[string]*apiv0.ServerJSON, len(ids))
+	for _, id := range ids {
+		if entry, exists := db.entries[id]; exists && tenancy.Allows(ctx, tenancy.Of(entry)) {
+			entryCopy := *entry
+			found[id] = &entryCopy
+		}
+	}
+	return found, missingIDs(ids, found), nil
+}
+
+// GetHeadByID retrieves the registry metadata of a server version by ID
+func (db *MemoryDB) GetHeadByID(ctx context.Context, id string) (*ServerHead, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if entry, exists := db.entries[id]; exists && tenancy.Allows(ctx, tenancy.Of(entry)) {
+		return serverHead(entry), nil
+	}
+	return nil, ErrNotFound
+}
+
+// FindHead retrieves the registry metadata of a server version by name and version, or of its latest version
+func (db *MemoryDB) FindHead(ctx context.Context, name, version string) (*ServerHead, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	for _, entry := range db.entries {
+		if entry.Name != name || entry.Status.Hidden() || !tenancy.Allows(ctx, tenancy.Of(entry)) {
+			continue
+		}
+		head := serverHead(entry)
+		if (version != "" && entry.Version == version) || (version == "" && head.IsLatest) {
+			return head, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// serverHead extracts the registry metadata of a server version
+func serverHead(server *apiv0.ServerJSON) *ServerHead {
+	head := &ServerHead{
+		Name:         server.Name,
+		Version:      server.Version,
+		Status:       server.Status,
+		LastModified: server.LastModified(),
+	}
+	if server.Meta != nil && server.Meta.Official != nil {
+		head.ID = server.Meta.Official.ID
+		head.IsLatest = server.Meta.Official.IsLatest
+	}
+	return head
+}
+
 func (db *MemoryDB) CreateServer(ctx context.Context, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -109,10 +253,17 @@ func (db *MemoryDB) CreateServer(ctx context.Context, server *apiv0.ServerJSON)
 	}
 
 	id := server.Meta.Official.ID
+	if !tenancy.Allows(ctx, tenancy.Of(server)) {
+		return nil, fmt.Errorf("%w: server belongs to another tenant", ErrInvalidInput)
+	}
 
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
+	if _, exists := db.entries[id]; exists {
+		return nil, ErrAlreadyExists
+	}
+
 	// Store the record using registry metadata ID
 	db.entries[id] = server
 
@@ -127,8 +278,9 @@ func (db *MemoryDB) UpdateServer(ctx context.Context, id string, server *apiv0.S
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
-	_, exists := db.entries[id]
-	if !exists {
+	// A server's tenant never changes, and is out of reach of other tenants
+	existing, exists := db.entries[id]
+	if !exists || !tenancy.Allows(ctx, tenancy.Of(existing)) || tenancy.Of(server) != tenancy.Of(existing) {
 		return nil, ErrNotFound
 	}
 
@@ -139,6 +291,619 @@ func (db *MemoryDB) UpdateServer(ctx context.Context, id string, server *apiv0.S
 	return server, nil
 }
 
+// CreateAuthChallenge stores a new authentication challenge
+func (db *MemoryDB) CreateAuthChallenge(ctx context.Context, challenge *AuthChallenge) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, exists := db.challenges[challenge.Nonce]; exists {
+		return ErrAlreadyExists
+	}
+
+	challengeCopy := *challenge
+	db.challenges[challenge.Nonce] = &challengeCopy
+
+	return nil
+}
+
+// ConsumeAuthChallenge retrieves and deletes a challenge by nonce, so it can only be used once
+func (db *MemoryDB) ConsumeAuthChallenge(ctx context.Context, nonce string) (*AuthChallenge, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	challenge, exists := db.challenges[nonce]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	delete(db.challenges, nonce)
+
+	return challenge, nil
+}
+
+// PurgeExpiredAuthChallenges deletes challenges that expired before now
+func (db *MemoryDB) PurgeExpiredAuthChallenges(ctx context.Context, now time.Time) (int64, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var purged int64
+	for nonce, challenge := range db.challenges {
+		if challenge.ExpiresAt.Before(now) {
+			delete(db.challenges, nonce)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// CreateNamespaceNotification stores a publish notification registration
+func (db *MemoryDB) CreateNamespaceNotification(ctx context.Context, notification *NamespaceNotification) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, exists := db.notifications[notification.ID]; exists {
+		return ErrAlreadyExists
+	}
+
+	notificationCopy := *notification
+	notificationCopy.Tenant, _ = tenancy.FromContext(ctx)
+	db.notifications[notification.ID] = &notificationCopy
+
+	return nil
+}
+
+// ListNamespaceNotifications returns the publish notification registrations for a namespace, oldest first
+func (db *MemoryDB) ListNamespaceNotifications(ctx context.Context, namespace string) ([]*NamespaceNotification, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var notifications []*NamespaceNotification
+	for _, notification := range db.notifications {
+		if notification.Namespace == namespace && tenancy.Allows(ctx, notification.Tenant) {
+			notificationCopy := *notification
+			notifications = append(notifications, &notificationCopy)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.Before(notifications[j].CreatedAt)
+	})
+
+	return notifications, nil
+}
+
+// DeleteNamespaceNotification removes a publish notification registration by ID
+func (db *MemoryDB) DeleteNamespaceNotification(ctx context.Context, id string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if notification, exists := db.notifications[id]; !exists || !tenancy.Allows(ctx, notification.Tenant) {
+		return ErrNotFound
+	}
+	delete(db.notifications, id)
+	// Undelivered events go with their registration
+	for eventID, event := range db.outbox {
+		if event.RegistrationID == id {
+			delete(db.outbox, eventID)
+		}
+	}
+
+	return nil
+}
+
+// CreateOutboxEvent stores a notification to deliver
+func (db *MemoryDB) CreateOutboxEvent(ctx context.Context, event *OutboxEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, exists := db.outbox[event.ID]; exists {
+		return ErrAlreadyExists
+	}
+	if _, exists := db.notifications[event.RegistrationID]; !exists {
+		return fmt.Errorf("%w: unknown notification registration %s", ErrInvalidInput, event.RegistrationID)
+	}
+	eventCopy := *event
+	eventCopy.Attempts = 0
+	eventCopy.LastError = ""
+	eventCopy.CompletedAt = nil
+	db.outbox[event.ID] = &eventCopy
+
+	return nil
+}
+
+// ClaimOutboxEvents returns up to limit uncompleted events due at now, leasing them until now+lease
+func (db *MemoryDB) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxEvent, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var due []*OutboxEvent
+	for _, event := range db.outbox {
+		if event.CompletedAt == nil && !event.NextAttemptAt.After(now) {
+			due = append(due, event)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].CreatedAt.Equal(due[j].CreatedAt) {
+			return due[i].CreatedAt.Before(due[j].CreatedAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*OutboxEvent, 0, len(due))
+	for _, event := range due {
+		// Replace rather than modify the stored event, so transactions see the change
+		updated := *event
+		updated.Attempts++
+		updated.NextAttemptAt = now.Add(lease)
+		db.outbox[event.ID] = &updated
+		eventCopy := updated
+		claimed = append(claimed, &eventCopy)
+	}
+	return claimed, nil
+}
+
+// RetryOutboxEvent records a failed delivery attempt, making the event due again at nextAttemptAt
+func (db *MemoryDB) RetryOutboxEvent(ctx context.Context, id string, nextAttemptAt time.Time, lastError string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	event, exists := db.outbox[id]
+	if !exists || event.CompletedAt != nil {
+		return ErrNotFound
+	}
+	updated := *event
+	updated.NextAttemptAt = nextAttemptAt
+	updated.LastError = lastError
+	db.outbox[id] = &updated
+
+	return nil
+}
+
+// CompleteOutboxEvent marks an event delivered or abandoned, purging events completed long ago
+func (db *MemoryDB) CompleteOutboxEvent(ctx context.Context, id string, completedAt time.Time, lastError string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	event, exists := db.outbox[id]
+	if !exists || event.CompletedAt != nil {
+		return ErrNotFound
+	}
+	updated := *event
+	updated.CompletedAt = &completedAt
+	updated.LastError = lastError
+	db.outbox[id] = &updated
+
+	return nil
+}
+
+// PurgeCompletedOutboxEvents deletes events completed more than outboxRetention before now
+func (db *MemoryDB) PurgeCompletedOutboxEvents(ctx context.Context, now time.Time) (int64, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var purged int64
+	purgeBefore := now.Add(-outboxRetention)
+	for id, event := range db.outbox {
+		if event.CompletedAt != nil && event.CompletedAt.Before(purgeBefore) {
+			delete(db.outbox, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// ListNamespaceReservations returns every namespace reservation, ordered by namespace
+func (db *MemoryDB) ListNamespaceReservations(ctx context.Context) ([]*NamespaceReservation, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	reservations := make([]*NamespaceReservation, 0, len(db.reservations))
+	for _, reservation := range db.reservations {
+		reservationCopy := *reservation
+		reservationCopy.AllowedSubjects = append([]string(nil), reservation.AllowedSubjects...)
+		reservations = append(reservations, &reservationCopy)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].Namespace < reservations[j].Namespace
+	})
+
+	return reservations, nil
+}
+
+// PutNamespaceReservation stores a namespace reservation, replacing any for the same namespace
+func (db *MemoryDB) PutNamespaceReservation(ctx context.Context, reservation *NamespaceReservation) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	reservationCopy := *reservation
+	reservationCopy.AllowedSubjects = append([]string(nil), reservation.AllowedSubjects...)
+	db.reservations[reservation.Namespace] = &reservationCopy
+
+	return nil
+}
+
+// DeleteNamespaceReservation removes the reservation of a namespace
+func (db *MemoryDB) DeleteNamespaceReservation(ctx context.Context, namespace string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, exists := db.reservations[namespace]; !exists {
+		return ErrNotFound
+	}
+	delete(db.reservations, namespace)
+
+	return nil
+}
+
+// RecordNamespaceVerification stores a namespace verification, replacing any earlier one for the namespace
+func (db *MemoryDB) RecordNamespaceVerification(ctx context.Context, verification *NamespaceVerification) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	verificationCopy := *verification
+	verificationCopy.Tenant, _ = tenancy.FromContext(ctx)
+	db.verifications[tenantNamespaceKey(verificationCopy.Tenant, verification.Namespace)] = &verificationCopy
+
+	return nil
+}
+
+// GetNamespaceVerification returns the latest verification of a namespace, or ErrNotFound
+func (db *MemoryDB) GetNamespaceVerification(ctx context.Context, namespace string) (*NamespaceVerification, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	tenant, _ := tenancy.FromContext(ctx)
+	verification, exists := db.verifications[tenantNamespaceKey(tenant, namespace)]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	verificationCopy := *verification
+
+	return &verificationCopy, nil
+}
+
+// PutNamespaceSettings stores the settings of a namespace, replacing any earlier ones
+func (db *MemoryDB) PutNamespaceSettings(ctx context.Context, settings *NamespaceSettings) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	settingsCopy := *settings
+	settingsCopy.Categories = slices.Clone(settings.Categories)
+	settingsCopy.Tenant, _ = tenancy.FromContext(ctx)
+	db.settings[tenantNamespaceKey(settingsCopy.Tenant, settings.Namespace)] = &settingsCopy
+
+	return nil
+}
+
+// GetNamespaceSettings returns the settings of a namespace, or ErrNotFound
+func (db *MemoryDB) GetNamespaceSettings(ctx context.Context, namespace string) (*NamespaceSettings, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	tenant, _ := tenancy.FromContext(ctx)
+	settings, exists := db.settings[tenantNamespaceKey(tenant, namespace)]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	settingsCopy := *settings
+	settingsCopy.Categories = slices.Clone(settings.Categories)
+
+	return &settingsCopy, nil
+}
+
+// AddServerFetchCounts adds each count to the fetch count of its server on its day
+func (db *MemoryDB) AddServerFetchCounts(ctx context.Context, counts []ServerFetchCount) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, count := range counts {
+		db.fetchCounts[fetchCountKey{count.Tenant, count.Namespace, count.ServerName, fetchDay(count.Day)}] += count.Count
+	}
+
+	return nil
+}
+
+// ListServerFetchCounts returns the fetch counts of the servers in a namespace on the days from from to to
+func (db *MemoryDB) ListServerFetchCounts(ctx context.Context, namespace string, from, to time.Time) ([]ServerFetchCount, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	tenant, _ := tenancy.FromContext(ctx)
+	from, to = fetchDay(from), fetchDay(to)
+	var counts []ServerFetchCount
+	for key, count := range db.fetchCounts {
+		if key.tenant != tenant || key.namespace != namespace || key.day.Before(from) || key.day.After(to) {
+			continue
+		}
+		counts = append(counts, ServerFetchCount{Tenant: key.tenant, Namespace: key.namespace, ServerName: key.serverName, Day: key.day, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if !counts[i].Day.Equal(counts[j].Day) {
+			return counts[i].Day.Before(counts[j].Day)
+		}
+		return counts[i].ServerName < counts[j].ServerName
+	})
+
+	return counts, nil
+}
+
+// CreateServerReport stores a report about a server version
+func (db *MemoryDB) CreateServerReport(ctx context.Context, report *ServerReport) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, exists := db.reports[report.ID]; exists {
+		return ErrAlreadyExists
+	}
+	reportCopy := *report
+	db.reports[report.ID] = &reportCopy
+
+	return nil
+}
+
+// ListServerReportCounts counts the reports matching filter by server version and category
+func (db *MemoryDB) ListServerReportCounts(ctx context.Context, filter ServerReportFilter) ([]ServerReportCount, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	type countKey struct{ serverID, category string }
+	byKey := make(map[countKey]*ServerReportCount)
+	for _, report := range db.reports {
+		if !tenancy.Allows(ctx, report.Tenant) ||
+			(filter.Namespace != "" && report.Namespace != filter.Namespace) ||
+			(filter.ServerID != "" && report.ServerID != filter.ServerID) {
+			continue
+		}
+		key := countKey{report.ServerID, report.Category}
+		count, ok := byKey[key]
+		if !ok {
+			count = &ServerReportCount{ServerID: report.ServerID, ServerName: report.ServerName, Version: report.Version, Category: report.Category}
+			byKey[key] = count
+		}
+		count.Count++
+		if report.CreatedAt.After(count.LastReportedAt) {
+			count.LastReportedAt = report.CreatedAt
+		}
+	}
+
+	counts := make([]ServerReportCount, 0, len(byKey))
+	for _, count := range byKey {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].ServerName != counts[j].ServerName {
+			return counts[i].ServerName < counts[j].ServerName
+		}
+		if counts[i].ServerID != counts[j].ServerID {
+			return counts[i].ServerID < counts[j].ServerID
+		}
+		return counts[i].Category < counts[j].Category
+	})
+
+	return counts, nil
+}
+
+// ListServerReports returns the reports about a server version, newest first
+func (db *MemoryDB) ListServerReports(ctx context.Context, serverID string) ([]*ServerReport, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var reports []*ServerReport
+	for _, report := range db.reports {
+		if report.ServerID == serverID && tenancy.Allows(ctx, report.Tenant) {
+			reportCopy := *report
+			reports = append(reports, &reportCopy)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if !reports[i].CreatedAt.Equal(reports[j].CreatedAt) {
+			return reports[i].CreatedAt.After(reports[j].CreatedAt)
+		}
+		return reports[i].ID > reports[j].ID
+	})
+
+	return reports, nil
+}
+
+// BackfillSearchKeywords has nothing to do: the memory database derives keywords when searching
+func (db *MemoryDB) BackfillSearchKeywords(ctx context.Context, _ int) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	return 0, nil
+}
+
+// tenantNamespaceKey keys records of a namespace, which each tenant has its own of
+func tenantNamespaceKey(tenant, namespace string) string {
+	return tenant + "\x00" + namespace
+}
+
+// InTransaction runs fn against a private copy of the data and applies the
+// changes it made only if fn succeeds and ctx is still live
+func (db *MemoryDB) InTransaction(ctx context.Context, fn func(ctx context.Context, tx Database) error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.RLock()
+	tx := &MemoryDB{
+		entries:       maps.Clone(db.entries),
+		challenges:    maps.Clone(db.challenges),
+		notifications: maps.Clone(db.notifications),
+		outbox:        maps.Clone(db.outbox),
+		verifications: maps.Clone(db.verifications),
+		settings:      maps.Clone(db.settings),
+		reports:       maps.Clone(db.reports),
+	}
+	db.mu.RUnlock()
+	snapshot := &MemoryDB{
+		entries:       maps.Clone(tx.entries),
+		challenges:    maps.Clone(tx.challenges),
+		notifications: maps.Clone(tx.notifications),
+		outbox:        maps.Clone(tx.outbox),
+		verifications: maps.Clone(tx.verifications),
+		settings:      maps.Clone(tx.settings),
+		reports:       maps.Clone(tx.reports),
+	}
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	// Only apply what the transaction changed, so concurrent writes to other records survive
+	for id, entry := range tx.entries {
+		if snapshot.entries[id] != entry {
+			db.entries[id] = entry
+		}
+	}
+	for nonce, challenge := range tx.challenges {
+		if snapshot.challenges[nonce] != challenge {
+			db.challenges[nonce] = challenge
+		}
+	}
+	for nonce := range snapshot.challenges {
+		if _, exists := tx.challenges[nonce]; !exists {
+			delete(db.challenges, nonce)
+		}
+	}
+	for id, notification := range tx.notifications {
+		if snapshot.notifications[id] != notification {
+			db.notifications[id] = notification
+		}
+	}
+	for id := range snapshot.notifications {
+		if _, exists := tx.notifications[id]; !exists {
+			delete(db.notifications, id)
+		}
+	}
+	for id, event := range tx.outbox {
+		if snapshot.outbox[id] != event {
+			db.outbox[id] = event
+		}
+	}
+	for id := range snapshot.outbox {
+		if _, exists := tx.outbox[id]; !exists {
+			delete(db.outbox, id)
+		}
+	}
+	for namespace, verification := range tx.verifications {
+		if snapshot.verifications[namespace] != verification {
+			db.verifications[namespace] = verification
+		}
+	}
+	for namespace, settings := range tx.settings {
+		if snapshot.settings[namespace] != settings {
+			db.settings[namespace] = settings
+		}
+	}
+	for id, report := range tx.reports {
+		if snapshot.reports[id] != report {
+			db.reports[id] = report
+		}
+	}
+
+	return nil
+}
+
 // For an in-memory database, this is a no-op
 func (db *MemoryDB) Close() error {
 	return nil
@@ -154,6 +919,30 @@ func (db *MemoryDB) filterAndSort(allEntries []*apiv0.ServerJSON, filter *Server
 		}
 	}
 
+	// Incremental sync lists oldest changes first so clients can checkpoint
+	if filter != nil && filter.UpdatedSince != nil {
+		sort.Slice(filteredEntries, func(i, j int) bool {
+			iModified, jModified := filteredEntries[i].LastModified(), filteredEntries[j].LastModified()
+			if !iModified.Equal(jModified) {
+				return iModified.Before(jModified)
+			}
+			return db.getRegistryID(filteredEntries[i]) < db.getRegistryID(filteredEntries[j])
+		})
+		return filteredEntries
+	}
+
+	// Searches list servers whose name matches first
+	if filter != nil && filter.Search != nil {
+		sort.Slice(filteredEntries, func(i, j int) bool {
+			iRank, jRank := searchRank(filteredEntries[i], *filter.Search), searchRank(filteredEntries[j], *filter.Search)
+			if iRank != jRank {
+				return iRank > jRank
+			}
+			return db.getRegistryID(filteredEntries[i]) < db.getRegistryID(filteredEntries[j])
+		})
+		return filteredEntries
+	}
+
 	// Sort by registry metadata ID for consistent pagination
 	sort.Slice(filteredEntries, func(i, j int) bool {
 		iID := db.getRegistryID(filteredEntries[i])
@@ -176,11 +965,17 @@ func (db *MemoryDB) matchesFilter(entry *apiv0.ServerJSON, filter *ServerFilter)
 		return false
 	}
 
+	// Check namespace filter
+	if filter.Namespace != nil && !strings.HasPrefix(entry.Name, *filter.Namespace+"/") {
+		return false
+	}
+
 	// Check remote URL filter
 	if filter.RemoteURL != nil {
+		canonical := CanonicalRemoteURL(*filter.RemoteURL)
 		found := false
 		for _, remote := range entry.Remotes {
-			if remote.URL == *filter.RemoteURL {
+			if CanonicalRemoteURL(remote.URL) == canonical {
 				found = true
 				break
 			}
@@ -195,8 +990,7 @@ func (db *MemoryDB) matchesFilter(entry *apiv0.ServerJSON, filter *ServerFilter)
 		if entry.Meta == nil || entry.Meta.Official == nil {
 			return false
 		}
-		if entry.Meta.Official.UpdatedAt.Before(*filter.UpdatedSince) ||
-			entry.Meta.Official.UpdatedAt.Equal(*filter.UpdatedSince) {
+		if entry.LastModified().Before(*filter.UpdatedSince) {
 			return false
 		}
 	}
@@ -211,6 +1005,11 @@ func (db *MemoryDB) matchesFilter(entry *apiv0.ServerJSON, filter *ServerFilter)
 		}
 	}
 
+	// Check search filter, computing keywords as they would be stored
+	if filter.Search != nil && !matchesSearch(entry, *filter.Search) {
+		return false
+	}
+
 	// Check exact version filter
 	if filter.Version != nil {
 		if entry.Version != *filter.Version {
@@ -228,9 +1027,65 @@ func (db *MemoryDB) matchesFilter(entry *apiv0.ServerJSON, filter *ServerFilter)
 		}
 	}
 
+	// Check package filters, which must all match the same package
+	if filter.RegistryType != nil || filter.RuntimeHint != nil {
+		found := false
+		for _, pkg := range entry.Packages {
+			if (filter.RegistryType == nil || pkg.RegistryType == *filter.RegistryType) &&
+				(filter.RuntimeHint == nil || pkg.RunTimeHint == *filter.RuntimeHint) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// Check license filter
+	if filter.License != nil && !licenseMentions(entry.License, *filter.License) {
+		return false
+	}
+
+	// Check fork filter
+	if filter.ForkOf != nil && entry.ForkOf != *filter.ForkOf {
+		return false
+	}
+
+	// Check protocol compatibility filters
+	if entry.MCPVersion == "" {
+		if filter.ExcludeUnknownMCPVersion {
+			return false
+		}
+	} else if filter.CompatibleWith != nil && entry.MCPVersion > *filter.CompatibleWith {
+		return false
+	}
+
+	// Check status filters
+	if filter.Status != nil && entry.Status != *filter.Status {
+		return false
+	}
+	if filter.ExcludeHidden && entry.Status.Hidden() {
+		return false
+	}
+
 	return true
 }
 
+// licenseMentions reports whether an SPDX license expression references the identifier,
+// ignoring case and "+" suffixes. It matches the same terms as the PostgreSQL license filter.
+func licenseMentions(expression, id string) bool {
+	terms := strings.FieldsFunc(expression, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '(' || r == ')'
+	})
+	for _, term := range terms {
+		if strings.EqualFold(strings.TrimSuffix(term, "+"), id) {
+			return true
+		}
+	}
+	return false
+}
+
 // getRegistryID safely extracts the registry ID from an entry
 func (db *MemoryDB) getRegistryID(entry *apiv0.ServerJSON) string {
 	if entry.Meta != nil && entry.Meta.Official != nil {
@@ -5,6 +5,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"path"
 	"sort"
@@ -17,6 +18,10 @@ import (
 //go:embed migrations/*.sql
 var migrationFiles embed.FS
 
+// migrationLockKey is the advisory lock held while migrating, so that registry instances
+// starting at the same time apply each migration exactly once
+const migrationLockKey int64 = 0x6d63707265670001
+
 // Migration represents a database migration
 type Migration struct {
 	Version int
@@ -26,12 +31,13 @@ type Migration struct {
 
 // Migrator handles database migrations
 type Migrator struct {
-	conn *pgx.Conn
+	conn  *pgx.Conn
+	files fs.FS // holds a migrations directory of NNN_name.sql files
 }
 
 // NewMigrator creates a new migrator instance
 func NewMigrator(conn *pgx.Conn) *Migrator {
-	return &Migrator{conn: conn}
+	return &Migrator{conn: conn, files: migrationFiles}
 }
 
 // ensureMigrationsTable creates the migrations tracking table if it doesn't exist
@@ -70,7 +76,7 @@ func (m *Migrator) getAppliedMigrations(ctx context.Context) (map[int]bool, erro
 
 // loadMigrations loads all migration files from the embedded filesystem
 func (m *Migrator) loadMigrations() ([]Migration, error) {
-	entries, err := migrationFiles.ReadDir("migrations")
+	entries, err := fs.ReadDir(m.files, "migrations")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
 	}
@@ -96,7 +102,7 @@ func (m *Migrator) loadMigrations() ([]Migration, error) {
 		}
 
 		// Read the migration SQL
-		content, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		content, err := fs.ReadFile(m.files, path.Join("migrations", name))
 		if err != nil {
 			return nil, fmt.Errorf("failed to read migration file %s: %w", name, err)
 		}
@@ -113,11 +119,29 @@ func (m *Migrator) loadMigrations() ([]Migration, error) {
 		return migrations[i].Version < migrations[j].Version
 	})
 
+	// Two files with the same version would leave the applied schema depending on file order
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migrations[i-1].Name, migrations[i].Name, migrations[i].Version)
+		}
+	}
+
 	return migrations, nil
 }
 
 // Migrate runs all pending migrations
 func (m *Migrator) Migrate(ctx context.Context) error {
+	// Serialize with other instances; applied migrations are read only once the lock is held
+	if _, err := m.conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx was cancelled mid-migration
+		if _, err := m.conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			log.Printf("Failed to release migration lock: %v", err)
+		}
+	}()
+
 	// Ensure the migrations table exists
 	if err := m.ensureMigrationsTable(ctx); err != nil {
 		return fmt.Errorf("failed to create migrations table: %w", err)
@@ -153,7 +177,9 @@ func (m *Migrator) Migrate(ctx context.Context) error {
 	// Apply each pending migration in a transaction
 	for _, migration := range pending {
 		if err := m.applyMigration(ctx, migration); err != nil {
-			return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+			// Each migration runs in its own transaction, so a failed one leaves no partial changes behind
+			return fmt.Errorf("failed to apply migration %d (%s), it was rolled back and later migrations were not attempted: %w",
+				migration.Version, migration.Name, err)
 		}
 		log.Printf("Applied migration %d: %s", migration.Version, migration.Name)
 	}
@@ -0,0 +1,11 @@
+-- Store server-issued authentication challenges (nonces)
+-- Each challenge is single-use: it is deleted when consumed, and expired challenges are purged
+
+CREATE TABLE auth_challenges (
+    nonce VARCHAR(64) PRIMARY KEY,
+    domain VARCHAR(255) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_auth_challenges_expires_at ON auth_challenges (expires_at);
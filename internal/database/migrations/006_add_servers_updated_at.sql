@@ -0,0 +1,16 @@
+-- Add a real timestamp column for incremental sync (updated_since)
+-- The JSONB updated_at text cannot be indexed as a timestamp, and does not sort lexically
+
+ALTER TABLE servers ADD COLUMN updated_at TIMESTAMPTZ;
+
+-- Backfill with the later of the registry metadata updated_at and published_at
+UPDATE servers SET updated_at = COALESCE(GREATEST(
+    (value->'_meta'->'io.modelcontextprotocol.registry/official'->>'updated_at')::timestamptz,
+    (value->'_meta'->'io.modelcontextprotocol.registry/official'->>'published_at')::timestamptz
+), 'epoch'::timestamptz);
+
+ALTER TABLE servers ALTER COLUMN updated_at SET NOT NULL;
+
+-- Replace the unused text index with one that serves updated_since ordering and keyset pagination
+DROP INDEX IF EXISTS idx_servers_updated_at;
+CREATE INDEX idx_servers_updated_at ON servers (updated_at, id);
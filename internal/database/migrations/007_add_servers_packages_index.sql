@@ -0,0 +1,4 @@
+-- Index packages for the registry_type and runtime_hint list filters
+-- jsonb_path_ops supports the @> containment queries used to match a single package
+
+CREATE INDEX IF NOT EXISTS idx_servers_packages ON servers USING GIN ((value->'packages') jsonb_path_ops);
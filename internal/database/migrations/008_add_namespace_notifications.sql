@@ -0,0 +1,14 @@
+-- Let namespace owners register to hear about every publish under their namespace
+-- Each registration delivers to exactly one webhook URL or email address
+
+CREATE TABLE namespace_notifications (
+    id UUID PRIMARY KEY,
+    namespace VARCHAR(255) NOT NULL,
+    webhook_url TEXT,
+    email VARCHAR(320),
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK ((webhook_url IS NULL) <> (email IS NULL))
+);
+
+CREATE INDEX idx_namespace_notifications_namespace ON namespace_notifications (namespace);
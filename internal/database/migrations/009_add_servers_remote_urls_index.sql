@@ -0,0 +1,25 @@
+-- Index the canonical remote URLs of each server for the publish-time duplicate remote URL check
+-- canonical_remote_url must match database.CanonicalRemoteURL: the scheme and host lowercased,
+-- without the default port for http or https, trailing slashes or a fragment
+
+CREATE OR REPLACE FUNCTION canonical_remote_url(url text) RETURNS text
+LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+    SELECT CASE WHEN parts IS NULL THEN url ELSE
+        lower(parts[1]) || '://' ||
+        CASE
+            WHEN lower(parts[1]) = 'https' AND lower(parts[2]) LIKE '%:443' THEN left(lower(parts[2]), -4)
+            WHEN lower(parts[1]) = 'http' AND lower(parts[2]) LIKE '%:80' THEN left(lower(parts[2]), -3)
+            ELSE lower(parts[2])
+        END ||
+        rtrim(parts[3], '/') || coalesce(parts[4], '')
+    END
+    FROM (SELECT regexp_match(url, '^([A-Za-z][A-Za-z0-9+.-]*)://([^/?#]*)([^?#]*)(\?[^#]*)?') AS parts) AS matched
+$$;
+
+CREATE OR REPLACE FUNCTION server_remote_urls(server jsonb) RETURNS text[]
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT coalesce(array_agg(canonical_remote_url(remote->>'url')), '{}')
+    FROM jsonb_array_elements(CASE jsonb_typeof(server->'remotes') WHEN 'array' THEN server->'remotes' ELSE '[]' END) AS remote
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_servers_remote_urls ON servers USING GIN (server_remote_urls(value));
@@ -0,0 +1,11 @@
+-- Let admins reserve namespaces, such as a company's own, for the identities they allow
+-- A namespace ending in * reserves every namespace starting with the part before it
+-- allowed_subjects holds <auth method>:<subject> patterns, where a trailing * matches any suffix
+
+CREATE TABLE namespace_reservations (
+    namespace VARCHAR(255) PRIMARY KEY,
+    allowed_subjects TEXT[] NOT NULL DEFAULT '{}',
+    reason TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,9 @@
+-- Record the latest proof that a publisher controls each namespace, for signed ownership statements
+-- method is the auth method of the most recent publish under the namespace, subject what it verified
+
+CREATE TABLE namespace_verifications (
+    namespace VARCHAR(255) PRIMARY KEY,
+    method VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL DEFAULT '',
+    verified_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
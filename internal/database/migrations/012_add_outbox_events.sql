@@ -0,0 +1,20 @@
+-- Deliver publish notifications through a transactional outbox: each event is written in the
+-- transaction of the change it describes, one row per registration, and a dispatcher delivers it
+-- at least once. Rows are removed with their registration.
+
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY,
+    registration_id UUID NOT NULL REFERENCES namespace_notifications (id) ON DELETE CASCADE,
+    webhook_url TEXT,
+    email VARCHAR(320),
+    event VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_outbox_events_due ON outbox_events (next_attempt_at) WHERE completed_at IS NULL;
+CREATE INDEX idx_outbox_events_registration_id ON outbox_events (registration_id);
@@ -0,0 +1,15 @@
+-- Let one registry serve several tenants, each seeing only its own servers, notification
+-- registrations and namespace verifications. Single-tenant registries leave tenant empty.
+
+ALTER TABLE servers ADD COLUMN tenant VARCHAR(255) NOT NULL DEFAULT '';
+CREATE INDEX idx_servers_tenant_id ON servers (tenant, id);
+CREATE INDEX idx_servers_tenant_updated_at ON servers (tenant, updated_at, id);
+CREATE INDEX idx_servers_tenant_name ON servers (tenant, (value->>'name'));
+
+ALTER TABLE namespace_notifications ADD COLUMN tenant VARCHAR(255) NOT NULL DEFAULT '';
+CREATE INDEX idx_namespace_notifications_tenant_namespace ON namespace_notifications (tenant, namespace);
+
+-- Each tenant verifies its own namespaces
+ALTER TABLE namespace_verifications ADD COLUMN tenant VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE namespace_verifications DROP CONSTRAINT namespace_verifications_pkey;
+ALTER TABLE namespace_verifications ADD PRIMARY KEY (tenant, namespace);
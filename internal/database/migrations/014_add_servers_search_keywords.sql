@@ -0,0 +1,6 @@
+-- Store the keywords each server version is found by in search: its name segments, package
+-- identifiers, repository path and description words, as derived by database.SearchKeywords.
+-- Rows written before this migration have none until POST /v0/admin/search-keywords backfills them.
+
+ALTER TABLE servers ADD COLUMN search_keywords TEXT[];
+CREATE INDEX idx_servers_search_keywords ON servers USING GIN (search_keywords);
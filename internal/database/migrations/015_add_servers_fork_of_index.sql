@@ -0,0 +1,4 @@
+-- Index the server each version declares itself a fork of, for listing the forks of a server
+-- and counting them on its detail response.
+
+CREATE INDEX idx_servers_fork_of ON servers ((value->>'forkOf'));
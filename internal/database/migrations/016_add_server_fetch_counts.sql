@@ -0,0 +1,13 @@
+-- Count fetches of each server's details per day, for the per-namespace metrics publishers see.
+-- The API server adds to the counts in batches, so a row is written at most once per flush.
+
+CREATE TABLE server_fetch_counts (
+    tenant VARCHAR(255) NOT NULL DEFAULT '',
+    namespace VARCHAR(255) NOT NULL,
+    server_name VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    count BIGINT NOT NULL,
+    PRIMARY KEY (tenant, namespace, server_name, day)
+);
+
+CREATE INDEX idx_server_fetch_counts_namespace_day ON server_fetch_counts (tenant, namespace, day);
@@ -0,0 +1,14 @@
+-- Let namespace owners set defaults for the optional server.json fields of versions published
+-- under their namespace. Each tenant has its own settings; empty values have no default.
+
+CREATE TABLE namespace_settings (
+    tenant VARCHAR(255) NOT NULL DEFAULT '',
+    namespace VARCHAR(255) NOT NULL,
+    license VARCHAR(200) NOT NULL DEFAULT '',
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    website_url TEXT NOT NULL DEFAULT '',
+    contact VARCHAR(255) NOT NULL DEFAULT '',
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (tenant, namespace)
+);
@@ -0,0 +1,18 @@
+-- Let anyone report a server version as broken or abusive. Admins see the reports; namespace
+-- owners only see how many each version has per category. Rows belong to the tenant of the
+-- reported server.
+
+CREATE TABLE server_reports (
+    id UUID PRIMARY KEY,
+    server_id UUID NOT NULL,
+    tenant VARCHAR(255) NOT NULL DEFAULT '',
+    namespace VARCHAR(255) NOT NULL,
+    server_name VARCHAR(255) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    text TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_server_reports_server_id ON server_reports (server_id, category);
+CREATE INDEX idx_server_reports_namespace ON server_reports (tenant, namespace);
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgresNotifier broadcasts server names over a PostgreSQL LISTEN/NOTIFY channel,
+// so that every registry replica sharing the database sees them
+type PostgresNotifier struct {
+	db      *PostgreSQL
+	channel string
+}
+
+// Notifier returns a notifier for the given channel
+func (db *PostgreSQL) Notifier(channel string) *PostgresNotifier {
+	return &PostgresNotifier{db: db, channel: channel}
+}
+
+// Invalidate notifies every listener, including this process, that name changed
+func (n *PostgresNotifier) Invalidate(ctx context.Context, name string) error {
+	if _, err := n.db.pool.Exec(ctx, "SELECT pg_notify($1, $2)", n.channel, name); err != nil {
+		return transient(fmt.Errorf("failed to notify %s: %w", n.channel, err))
+	}
+	return nil
+}
+
+// Listen holds a dedicated connection listening on the channel, calling ready once
+// it is subscribed and fn for each notification, until ctx is cancelled or the
+// connection fails
+func (n *PostgresNotifier) Listen(ctx context.Context, ready func(), fn func(name string)) error {
+	conn, err := n.db.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire listen connection: %w", err)
+	}
+	// The connection is left in LISTEN state, so close it rather than returning it to the pool
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.WithoutCancel(ctx))
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+pgx.Identifier{n.channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", n.channel, err)
+	}
+	ready()
+
+	for {
+		notification, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+		fn(notification.Payload)
+	}
+}
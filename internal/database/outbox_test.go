@@ -0,0 +1,95 @@
+//nolint:testpackage
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+
+			registration := &NamespaceNotification{ID: "4e0f8a3c-8f0d-4c1b-9d55-0d4e6f6d8a11", Namespace: "com.example", WebhookURL: "https://hooks.example.com/mcp", CreatedAt: now}
+			require.NoError(t, db.CreateNamespaceNotification(ctx, registration))
+
+			event := func(id string, createdAt time.Time) *OutboxEvent {
+				return &OutboxEvent{
+					ID: id, RegistrationID: registration.ID, WebhookURL: registration.WebhookURL, Event: "server.published",
+					Payload: []byte(`{"event":"server.published"}`), CreatedAt: createdAt, NextAttemptAt: createdAt,
+				}
+			}
+			first, second := "1b7e4c2a-0d3f-4a5b-8c6d-7e8f9a0b1c2d", "2c8f5d3b-1e4a-4b6c-9d7e-8f9a0b1c2d3e"
+
+			// Events that are not committed are never claimed
+			err := db.InTransaction(ctx, func(ctx context.Context, tx Database) error {
+				require.NoError(t, tx.CreateOutboxEvent(ctx, event("3d9a6e4c-2f5b-4c7d-8e9f-9a0b1c2d3e4f", now)))
+				return context.Canceled
+			})
+			require.ErrorIs(t, err, context.Canceled)
+
+			require.NoError(t, db.InTransaction(ctx, func(ctx context.Context, tx Database) error {
+				if err := tx.CreateOutboxEvent(ctx, event(second, now.Add(time.Second))); err != nil {
+					return err
+				}
+				return tx.CreateOutboxEvent(ctx, event(first, now))
+			}))
+			assert.ErrorIs(t, db.CreateOutboxEvent(ctx, event(first, now)), ErrAlreadyExists)
+			unknown := event("4e0b7f5d-3a6c-4d8e-9f0a-0b1c2d3e4f5a", now)
+			unknown.RegistrationID = "00000000-0000-0000-0000-000000000000"
+			assert.ErrorIs(t, db.CreateOutboxEvent(ctx, unknown), ErrInvalidInput)
+
+			// Claims take due events oldest first, one at a time
+			claimed, err := db.ClaimOutboxEvents(ctx, now.Add(time.Second), time.Minute, 1)
+			require.NoError(t, err)
+			require.Len(t, claimed, 1)
+			assert.Equal(t, first, claimed[0].ID)
+			assert.Equal(t, 1, claimed[0].Attempts)
+			assert.Equal(t, registration.WebhookURL, claimed[0].WebhookURL)
+			assert.JSONEq(t, `{"event":"server.published"}`, string(claimed[0].Payload))
+
+			claimed, err = db.ClaimOutboxEvents(ctx, now.Add(time.Second), time.Minute, 10)
+			require.NoError(t, err)
+			require.Len(t, claimed, 1, "the claimed event is leased")
+			assert.Equal(t, second, claimed[0].ID)
+
+			// A retried event is due again at the given time; an unfinished one when its lease passes
+			require.NoError(t, db.RetryOutboxEvent(ctx, first, now.Add(10*time.Second), "webhook responded with status 503"))
+			claimed, err = db.ClaimOutboxEvents(ctx, now.Add(10*time.Second), time.Minute, 10)
+			require.NoError(t, err)
+			require.Len(t, claimed, 1)
+			assert.Equal(t, first, claimed[0].ID)
+			assert.Equal(t, 2, claimed[0].Attempts)
+			assert.Equal(t, "webhook responded with status 503", claimed[0].LastError)
+
+			claimed, err = db.ClaimOutboxEvents(ctx, now.Add(2*time.Minute), time.Minute, 10)
+			require.NoError(t, err)
+			assert.Len(t, claimed, 2, "both leases expired")
+
+			// Completed events are no longer claimed
+			require.NoError(t, db.CompleteOutboxEvent(ctx, first, now.Add(2*time.Minute), ""))
+			require.NoError(t, db.CompleteOutboxEvent(ctx, second, now.Add(2*time.Minute), "gave up"))
+			assert.ErrorIs(t, db.CompleteOutboxEvent(ctx, first, now, ""), ErrNotFound)
+			assert.ErrorIs(t, db.RetryOutboxEvent(ctx, first, now, ""), ErrNotFound)
+			claimed, err = db.ClaimOutboxEvents(ctx, now.Add(time.Hour), time.Minute, 10)
+			require.NoError(t, err)
+			assert.Empty(t, claimed)
+
+			// Completed events are kept for a week
+			purged, err := db.PurgeCompletedOutboxEvents(ctx, now.Add(outboxRetention))
+			require.NoError(t, err)
+			assert.Zero(t, purged)
+			purged, err = db.PurgeCompletedOutboxEvents(ctx, now.Add(outboxRetention+3*time.Minute))
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), purged)
+			assert.ErrorIs(t, db.CompleteOutboxEvent(ctx, first, now, ""), ErrNotFound)
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// maxLoggedSQLLength bounds the SQL included in a slow query log line
+const maxLoggedSQLLength = 500
+
+var (
+	// sqlLiteralRegex matches single-quoted string literals, which are logged as '?'
+	sqlLiteralRegex = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// whitespaceRegex matches the runs of whitespace collapsed in logged SQL
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// PoolOptions configures the PostgreSQL connection pool. Zero values keep the defaults.
+type PoolOptions struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	// AcquireTimeout bounds how long a statement waits for a free connection before failing
+	// with ErrTransient. Zero waits as long as the statement's context allows.
+	AcquireTimeout time.Duration
+	// SlowQueryThreshold is the duration above which statements are logged. Zero disables it.
+	SlowQueryThreshold time.Duration
+	// ListQueryTimeout is the statement timeout of server list and count queries, which filters
+	// and searches can make expensive. Zero leaves them unbounded.
+	ListQueryTimeout time.Duration
+}
+
+// PoolOptionsFromConfig reads the pool settings from the registry configuration
+func PoolOptionsFromConfig(cfg *config.Config) PoolOptions {
+	return PoolOptions{
+		MaxConns:           cfg.DatabaseMaxConns,
+		MinConns:           cfg.DatabaseMinConns,
+		MaxConnLifetime:    cfg.DatabaseMaxConnLifetime,
+		AcquireTimeout:     cfg.DatabaseAcquireTimeout,
+		SlowQueryThreshold: cfg.DatabaseSlowQueryThreshold,
+		ListQueryTimeout:   cfg.DatabaseListQueryTimeout,
+	}
+}
+
+// apply sets the configured pool settings on config
+func (o PoolOptions) apply(config *pgxpool.Config) {
+	if o.MaxConns > 0 {
+		config.MaxConns = o.MaxConns
+	}
+	if o.MinConns > 0 {
+		config.MinConns = min(o.MinConns, config.MaxConns)
+	}
+	if o.MaxConnLifetime > 0 {
+		config.MaxConnLifetime = o.MaxConnLifetime
+	}
+	if o.SlowQueryThreshold > 0 {
+		config.ConnConfig.Tracer = &slowQueryLogger{threshold: o.SlowQueryThreshold}
+	}
+}
+
+// PoolStats reports the state of the connection pool, for telemetry
+func (db *PostgreSQL) PoolStats() *pgxpool.Stat {
+	return db.pool.Stat()
+}
+
+// timedPool runs statements on a connection from the pool, waiting at most acquireTimeout for
+// one to be free. Without it, requests queue for a connection for as long as they are allowed
+// to run, so a burst that exhausts the pool leaves clients hanging rather than told to retry.
+type timedPool struct {
+	pool           *pgxpool.Pool
+	acquireTimeout time.Duration
+}
+
+// acquire takes a connection from the pool, failing with ErrTransient if none is free in time
+func (p *timedPool) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	if p.acquireTimeout <= 0 {
+		conn, err := p.pool.Acquire(ctx)
+		if err != nil {
+			return nil, transient(fmt.Errorf("failed to acquire a database connection: %w", err))
+		}
+		return conn, nil
+	}
+
+	acquireCtx, cancel := context.WithTimeout(ctx, p.acquireTimeout)
+	defer cancel()
+	conn, err := p.pool.Acquire(acquireCtx)
+	if err != nil {
+		// Only the acquire timeout is transient; the caller's own deadline or cancellation is not
+		if ctx.Err() == nil && errors.Is(acquireCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: no database connection free within %s", ErrTransient, p.acquireTimeout)
+		}
+		return nil, transient(fmt.Errorf("failed to acquire a database connection: %w", err))
+	}
+	return conn, nil
+}
+
+func (p *timedPool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer conn.Release()
+	return conn.Exec(ctx, sql, arguments...)
+}
+
+func (p *timedPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return &releasingRows{Rows: rows, conn: conn}, nil
+}
+
+func (p *timedPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+	return &releasingRow{row: conn.QueryRow(ctx, sql, args...), conn: conn}
+}
+
+// begin starts a transaction on a connection from the pool, which is released when it ends
+func (p *timedPool) begin(ctx context.Context) (pgx.Tx, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return &releasingTx{Tx: tx, conn: conn}, nil
+}
+
+// releasingRows returns its connection to the pool once the rows are read or closed
+type releasingRows struct {
+	pgx.Rows
+	conn *pgxpool.Conn
+}
+
+func (r *releasingRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.release()
+	return false
+}
+
+func (r *releasingRows) Close() {
+	r.Rows.Close()
+	r.release()
+}
+
+func (r *releasingRows) release() {
+	if r.conn != nil {
+		r.Rows.Close()
+		r.conn.Release()
+		r.conn = nil
+	}
+}
+
+// releasingRow returns its connection to the pool once the row is scanned
+type releasingRow struct {
+	row  pgx.Row
+	conn *pgxpool.Conn
+}
+
+func (r *releasingRow) Scan(dest ...any) error {
+	defer r.conn.Release()
+	return r.row.Scan(dest...)
+}
+
+// errRow is a row that could not be queried
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
+
+// releasingTx returns its connection to the pool when the transaction commits or rolls back
+type releasingTx struct {
+	pgx.Tx
+	conn *pgxpool.Conn
+}
+
+func (t *releasingTx) Commit(ctx context.Context) error {
+	err := t.Tx.Commit(ctx)
+	t.release()
+	return err
+}
+
+func (t *releasingTx) Rollback(ctx context.Context) error {
+	err := t.Tx.Rollback(ctx)
+	t.release()
+	return err
+}
+
+func (t *releasingTx) release() {
+	if t.conn != nil {
+		t.conn.Release()
+		t.conn = nil
+	}
+}
+
+type slowQueryKey struct{}
+
+// slowQueryStart is what slowQueryLogger records when a statement starts
+type slowQueryStart struct {
+	start time.Time
+	sql   string
+	args  int
+}
+
+// slowQueryLogger logs statements that take longer than threshold, with their SQL and the number
+// of arguments. Arguments are not logged, since they hold user data and tokens.
+type slowQueryLogger struct {
+	threshold time.Duration
+}
+
+func (l *slowQueryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, slowQueryKey{}, slowQueryStart{start: time.Now(), sql: data.SQL, args: len(data.Args)})
+}
+
+func (l *slowQueryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	started, ok := ctx.Value(slowQueryKey{}).(slowQueryStart)
+	if !ok {
+		return
+	}
+	if elapsed := time.Since(started.start); elapsed >= l.threshold {
+		status := "ok"
+		if data.Err != nil {
+			status = "failed"
+		}
+		log.Printf("Slow query (%s, %d args, %s): %s", elapsed.Round(time.Millisecond), started.args, status, sanitizeSQL(started.sql))
+	}
+}
+
+// sanitizeSQL prepares a statement for logging: string literals are replaced, whitespace is
+// collapsed and long statements are truncated
+func sanitizeSQL(sql string) string {
+	sql = sqlLiteralRegex.ReplaceAllString(sql, "'?'")
+	sql = strings.TrimSpace(whitespaceRegex.ReplaceAllString(sql, " "))
+	if len(sql) > maxLoggedSQLLength {
+		sql = sql[:maxLoggedSQLLength] + "..."
+	}
+	return sql
+}
@@ -0,0 +1,213 @@
+//nolint:testpackage
+package database
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unresponsiveServer accepts connections and never answers them, so connecting to it takes
+// up a pool slot until the connect timeout
+func unresponsiveServer(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var conns []net.Conn
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	})
+	return "postgres://registry@" + listener.Addr().String() + "/registry?sslmode=disable&connect_timeout=5"
+}
+
+func TestAcquireTimeout(t *testing.T) {
+	config, err := pgxpool.ParseConfig(unresponsiveServer(t))
+	require.NoError(t, err)
+	config.MaxConns = 1
+	config.MinConns = 0
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	timed := &timedPool{pool: pool, acquireTimeout: 50 * time.Millisecond}
+	db := &PostgreSQL{pool: pool, timed: timed, conn: timed, counts: newCountCache()}
+
+	t.Run("concurrent requests fail as transient instead of queueing", func(t *testing.T) {
+		const requests = 5
+		errs := make(chan error, requests)
+		started := time.Now()
+		for range requests {
+			go func() {
+				_, err := db.GetByID(context.Background(), "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90")
+				errs <- err
+			}()
+		}
+		for range requests {
+			err := <-errs
+			assert.ErrorIs(t, err, ErrTransient)
+			assert.Contains(t, err.Error(), "no database connection free within 50ms")
+		}
+		assert.Less(t, time.Since(started), 2*time.Second)
+	})
+
+	t.Run("transactions fail as transient", func(t *testing.T) {
+		err := db.InTransaction(context.Background(), func(context.Context, Database) error {
+			t.Fatal("transaction should not start")
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrTransient)
+	})
+
+	t.Run("the caller's own deadline is not transient", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := db.GetByID(ctx, "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrTransient)
+	})
+}
+
+func TestAcquireTimeout_ExhaustedPool(t *testing.T) {
+	connConfig := freshDatabase(t)
+	databaseURL, err := url.Parse(os.Getenv("MCP_REGISTRY_TEST_DATABASE_URL"))
+	require.NoError(t, err)
+	databaseURL.Path = "/" + connConfig.Database
+
+	db, err := NewPostgreSQL(context.Background(), databaseURL.String(), PoolOptions{
+		MaxConns:       1,
+		AcquireTimeout: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	// Hold the only connection in a transaction while other requests arrive
+	holding, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- db.InTransaction(context.Background(), func(context.Context, Database) error {
+			close(holding)
+			<-release
+			return nil
+		})
+	}()
+	<-holding
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := db.List(context.Background(), nil, "", 10)
+			assert.ErrorIs(t, err, ErrTransient)
+		}()
+	}
+	wg.Wait()
+
+	close(release)
+	require.NoError(t, <-done)
+	_, _, err = db.List(context.Background(), nil, "", 10)
+	assert.NoError(t, err, "the connection is released when the transaction ends")
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{
+			name:     "whitespace is collapsed",
+			sql:      "\n\t\tSELECT value\n\t\tFROM servers\n\t\tWHERE id = $1\n\t",
+			expected: "SELECT value FROM servers WHERE id = $1",
+		},
+		{
+			name:     "string literals are replaced",
+			sql:      "SELECT 1 FROM servers WHERE value->>'name' = 'it''s secret'",
+			expected: "SELECT 1 FROM servers WHERE value->>'?' = '?'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeSQL(tt.sql))
+		})
+	}
+
+	t.Run("long statements are truncated", func(t *testing.T) {
+		sanitized := sanitizeSQL("SELECT " + strings.Repeat("column, ", 100) + "1")
+		assert.Len(t, sanitized, maxLoggedSQLLength+len("..."))
+		assert.True(t, strings.HasSuffix(sanitized, "..."))
+	})
+}
+
+func TestListQueryTimeout(t *testing.T) {
+	t.Run("cancelled statements fail without retrying", func(t *testing.T) {
+		faulty := &faultyQuerier{querier: &stubQuerier{rows: serverRows(t, "1.0.0")}, failures: 3,
+			err: &pgconn.PgError{Code: queryCanceledCode, Message: "canceling statement due to statement timeout"}}
+		db := &PostgreSQL{conn: faulty, counts: newCountCache()}
+
+		_, _, err := db.List(context.Background(), nil, "", 10)
+		assert.ErrorIs(t, err, ErrQueryTimeout)
+		assert.NotErrorIs(t, err, ErrTransient)
+		assert.Equal(t, 1, faulty.calls)
+
+		// The caller's own cancellation is reported as it is
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.NotErrorIs(t, queryTimeout(ctx, faulty.err), ErrQueryTimeout)
+	})
+
+	t.Run("slow queries are cancelled", func(t *testing.T) {
+		connConfig := freshDatabase(t)
+		databaseURL, err := url.Parse(os.Getenv("MCP_REGISTRY_TEST_DATABASE_URL"))
+		require.NoError(t, err)
+		databaseURL.Path = "/" + connConfig.Database
+
+		db, err := NewPostgreSQL(context.Background(), databaseURL.String(), PoolOptions{ListQueryTimeout: 50 * time.Millisecond})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		ctx := context.Background()
+		started := time.Now()
+		err = db.boundedRead(ctx, func(conn querier) error {
+			_, err := conn.Exec(ctx, "SELECT pg_sleep(5)")
+			return err
+		})
+		assert.ErrorIs(t, err, ErrQueryTimeout)
+		assert.Less(t, time.Since(started), 2*time.Second)
+
+		// The timeout is local to the read, so other statements on the connection are unbounded
+		_, err = db.pool.Exec(ctx, "SELECT pg_sleep(0.1)")
+		require.NoError(t, err)
+		search := "weather"
+		_, _, err = db.List(ctx, &ServerFilter{Search: &search}, "", 10)
+		assert.NoError(t, err)
+	})
+}
@@ -5,38 +5,69 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
+// uniqueViolationCode is the PostgreSQL error code for unique constraint violations
+const uniqueViolationCode = "23505"
+
+// foreignKeyViolationCode is the PostgreSQL error code for foreign key violations
+const foreignKeyViolationCode = "23503"
+
+// queryCanceledCode is the PostgreSQL error code for statements cancelled by a statement timeout
+// or a cancel request
+const queryCanceledCode = "57014"
+
+// querier is the subset of pgx shared by the pool and transactions
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// summaryProjection builds the subset of a server document needed for ProjectionSummary
+const summaryProjection = `jsonb_strip_nulls(jsonb_build_object(
+            'name', value->'name',
+            'description', value->'description',
+            'version', value->'version',
+            'status', value->'status',
+            'title', value->'title',
+            'repository', jsonb_build_object('url', value->'repository'->'url'),
+            'icons', jsonb_path_query_array(value, '$.icons[0]'),
+            '_meta', jsonb_build_object(
+                'io.modelcontextprotocol.registry/official', value->'_meta'->'io.modelcontextprotocol.registry/official'
+            )
+        ))`
+
 // PostgreSQL is an implementation of the Database interface using PostgreSQL
 type PostgreSQL struct {
 	pool *pgxpool.Pool
-}
-
-// NewPostgreSQL creates a new instance of the PostgreSQL database
-func NewPostgreSQL(ctx context.Context, connectionURI string) (*PostgreSQL, error) {
-	// Parse connection config for pool settings
-	config, err := pgxpool.ParseConfig(connectionURI)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse PostgreSQL config: %w", err)
-	}
+	timed  *timedPool // the pool, waiting a bounded time for connections
+	conn   querier // timed, or the transaction when running inside InTransaction
+	inTx   bool
+	counts *countCache // shared with transactions, which clear it when they write
 
-	// Configure pool for stability-focused defaults
-	config.MaxConns = 30                      // Handle good concurrent load
-	config.MinConns = 5                       // Keep connections warm for fast response
-	config.MaxConnIdleTime = 30 * time.Minute // Keep connections available for bursts
-	config.MaxConnLifetime = 2 * time.Hour    // Refresh connections regularly for stability
+	listTimeout time.Duration // statement timeout of list and count queries; zero leaves them unbounded
+}
 
-	// Create connection pool with configured settings
-	pool, err := pgxpool.NewWithConfig(ctx, config)
+// NewPostgreSQL creates a new instance of the PostgreSQL database, with the pool settings of opts
+func NewPostgreSQL(ctx context.Context, connectionURI string, opts PoolOptions) (*PostgreSQL, error) {
+	pool, err := newPool(ctx, connectionURI, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create PostgreSQL pool: %w", err)
+		return nil, err
 	}
 
 	// Test the connection
@@ -56,27 +87,62 @@ func NewPostgreSQL(ctx context.Context, connectionURI string) (*PostgreSQL, erro
 		return nil, fmt.Errorf("failed to run database migrations: %w", err)
 	}
 
-	return &PostgreSQL{
-		pool: pool,
-	}, nil
+	return newPostgreSQL(pool, opts), nil
 }
 
-//nolint:cyclop // Database filtering logic is inherently complex but clear
-func (db *PostgreSQL) List(
-	ctx context.Context,
-	filter *ServerFilter,
-	cursor string,
-	limit int,
-) ([]*apiv0.ServerJSON, string, error) {
-	if limit <= 0 {
-		limit = 10
+// NewPostgreSQLReplica connects to a read-only replica of the database, for
+// NewReadReplicaDatabase to send reads to. Migrations are left to the primary. A replica that
+// cannot be reached yet is only logged, since its reads fall back to the primary.
+func NewPostgreSQLReplica(ctx context.Context, connectionURI string, opts PoolOptions) (*PostgreSQL, error) {
+	pool, err := newPool(ctx, connectionURI, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		log.Printf("Warning: failed to ping PostgreSQL read replica, reading from the primary until it is reachable: %v", err)
 	}
+	return newPostgreSQL(pool, opts), nil
+}
 
-	if ctx.Err() != nil {
-		return nil, "", ctx.Err()
+// newPool creates a connection pool with the registry's defaults and the settings of opts
+func newPool(ctx context.Context, connectionURI string, opts PoolOptions) (*pgxpool.Pool, error) {
+	// Parse connection config for pool settings
+	config, err := pgxpool.ParseConfig(connectionURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PostgreSQL config: %w", err)
 	}
 
-	// Build WHERE clause for filtering
+	// Configure pool for stability-focused defaults
+	config.MaxConns = 30                      // Handle good concurrent load
+	config.MinConns = 5                       // Keep connections warm for fast response
+	config.MaxConnIdleTime = 30 * time.Minute // Keep connections available for bursts
+	config.MaxConnLifetime = 2 * time.Hour    // Refresh connections regularly for stability
+	opts.apply(config)
+
+	// Create connection pool with configured settings
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PostgreSQL pool: %w", err)
+	}
+	return pool, nil
+}
+
+func newPostgreSQL(pool *pgxpool.Pool, opts PoolOptions) *PostgreSQL {
+	timed := &timedPool{pool: pool, acquireTimeout: opts.AcquireTimeout}
+	return &PostgreSQL{
+		pool:        pool,
+		timed:       timed,
+		conn:        timed,
+		counts:      newCountCache(),
+		listTimeout: opts.ListQueryTimeout,
+	}
+}
+
+// filterConditions builds the WHERE conditions and their arguments for a filter.
+// Arguments are numbered from $1, so callers add their own from len(args)+1.
+//
+//nolint:cyclop // Database filtering logic is inherently complex but clear
+func filterConditions(filter *ServerFilter) ([]string, []any, error) {
 	var whereConditions []string
 	args := []any{}
 	argIndex := 1
@@ -89,12 +155,13 @@ func (db *PostgreSQL) List(
 			argIndex++
 		}
 		if filter.RemoteURL != nil {
-			whereConditions = append(whereConditions, fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(value->'remotes') AS remote WHERE remote->>'url' = $%d)", argIndex))
-			args = append(args, *filter.RemoteURL)
+			// Matches the expression indexed by idx_servers_remote_urls
+			whereConditions = append(whereConditions, fmt.Sprintf("server_remote_urls(value) @> ARRAY[$%d::text]", argIndex))
+			args = append(args, CanonicalRemoteURL(*filter.RemoteURL))
 			argIndex++
 		}
 		if filter.UpdatedSince != nil {
-			whereConditions = append(whereConditions, fmt.Sprintf("(value->'_meta'->'io.modelcontextprotocol.registry/official'->>'updated_at')::timestamp > $%d", argIndex))
+			whereConditions = append(whereConditions, fmt.Sprintf("updated_at >= $%d", argIndex))
 			args = append(args, *filter.UpdatedSince)
 			argIndex++
 		}
@@ -103,8 +170,25 @@ func (db *PostgreSQL) List(
 			args = append(args, "%"+*filter.SubstringName+"%")
 			argIndex++
 		}
+		if filter.Search != nil {
+			// The keyword half matches the expression indexed by idx_servers_search_keywords
+			condition := fmt.Sprintf("value->>'name' ILIKE $%d", argIndex)
+			args = append(args, "%"+*filter.Search+"%")
+			argIndex++
+			if tokens := SearchTokens(*filter.Search); len(tokens) > 0 {
+				condition = fmt.Sprintf("(%s OR search_keywords @> $%d::text[])", condition, argIndex)
+				args = append(args, tokens)
+				argIndex++
+			}
+			whereConditions = append(whereConditions, condition)
+		}
+		if filter.Namespace != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("starts_with(value->>'name', $%d)", argIndex))
+			args = append(args, *filter.Namespace+"/")
+			argIndex++
+		}
 		if filter.Version != nil {
-			whereConditions = append(whereConditions, fmt.Sprintf("(value->'version_detail'->>'version') = $%d", argIndex))
+			whereConditions = append(whereConditions, fmt.Sprintf("value->>'version' = $%d", argIndex))
 			args = append(args, *filter.Version)
 			argIndex++
 		}
@@ -113,14 +197,251 @@ func (db *PostgreSQL) List(
 			args = append(args, *filter.IsLatest)
 			argIndex++
 		}
+		if filter.RegistryType != nil || filter.RuntimeHint != nil {
+			// Containment on a single element requires one package to match every given field
+			pkg := map[string]string{}
+			if filter.RegistryType != nil {
+				pkg["registry_type"] = *filter.RegistryType
+			}
+			if filter.RuntimeHint != nil {
+				pkg["runtime_hint"] = *filter.RuntimeHint
+			}
+			packages, err := json.Marshal([]map[string]string{pkg})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to encode package filter: %w", err)
+			}
+			whereConditions = append(whereConditions, fmt.Sprintf("value->'packages' @> $%d::jsonb", argIndex))
+			args = append(args, string(packages))
+			argIndex++
+		}
+		if filter.License != nil {
+			// Match the identifier as a whole term of the expression, optionally with a "+" suffix
+			whereConditions = append(whereConditions, fmt.Sprintf("value->>'license' ~* $%d", argIndex))
+			args = append(args, `(^|[\s(])`+regexp.QuoteMeta(*filter.License)+`($|[\s)+])`)
+			argIndex++
+		}
+		if filter.ForkOf != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("value->>'forkOf' = $%d", argIndex))
+			args = append(args, *filter.ForkOf)
+			argIndex++
+		}
+		if filter.CompatibleWith != nil {
+			condition := fmt.Sprintf(`value->>'mcpVersion' COLLATE "C" <= $%d`, argIndex)
+			if !filter.ExcludeUnknownMCPVersion {
+				condition = fmt.Sprintf("(value->>'mcpVersion' IS NULL OR %s)", condition)
+			}
+			whereConditions = append(whereConditions, condition)
+			args = append(args, *filter.CompatibleWith)
+			argIndex++
+		} else if filter.ExcludeUnknownMCPVersion {
+			whereConditions = append(whereConditions, "value ? 'mcpVersion'")
+		}
+		if filter.Status != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("value->>'status' = $%d", argIndex))
+			args = append(args, string(*filter.Status))
+			argIndex++
+		}
+		if filter.ExcludeHidden {
+			whereConditions = append(whereConditions, fmt.Sprintf("COALESCE(value->>'status', '') NOT IN ('%s', '%s')", model.StatusPending, model.StatusRejected))
+		}
+	}
+
+	return whereConditions, args, nil
+}
+
+// CountNamespaces returns how many distinct servers each namespace has, ignoring pending and rejected versions
+func (db *PostgreSQL) CountNamespaces(ctx context.Context) (map[string]int, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	tenantCondition, args := tenantScope(ctx, 1)
+	if tenantCondition != "" {
+		tenantCondition = "AND " + tenantCondition
+	}
+	query := fmt.Sprintf(`
+		SELECT split_part(value->>'name', '/', 1) AS namespace, COUNT(DISTINCT value->>'name')
+		FROM servers
+		WHERE COALESCE(value->>'status', '') NOT IN ('%s', '%s') %s
+		GROUP BY namespace`, model.StatusPending, model.StatusRejected, tenantCondition)
+
+	var counts map[string]int
+	err := db.retryRead(ctx, func() error {
+		rows, err := db.conn.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to count namespaces: %w", err)
+		}
+		defer rows.Close()
+
+		counts = make(map[string]int)
+		for rows.Next() {
+			var namespace string
+			var count int
+			if err := rows.Scan(&namespace, &count); err != nil {
+				return fmt.Errorf("failed to scan namespace count: %w", err)
+			}
+			counts[namespace] = count
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating namespace counts: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// Count returns how many servers match the filter. Totals are cached briefly outside
+// transactions, since clients paging through a listing ask for the same count repeatedly.
+func (db *PostgreSQL) Count(ctx context.Context, filter *ServerFilter) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	whereConditions, args, err := filterConditions(filter)
+	if err != nil {
+		return 0, err
+	}
+	if condition, tenantArgs := tenantScope(ctx, len(args)+1); condition != "" {
+		whereConditions = append(whereConditions, condition)
+		args = append(args, tenantArgs...)
+	}
+	query := "SELECT COUNT(*) FROM servers"
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	key := fmt.Sprint(query, args)
+	if !db.inTx {
+		if total, ok := db.counts.get(key); ok {
+			return total, nil
+		}
+	}
+
+	var total int
+	err = db.retryRead(ctx, func() error {
+		return db.boundedRead(ctx, func(conn querier) error {
+			return conn.QueryRow(ctx, query, args...).Scan(&total)
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count servers: %w", err)
+	}
+
+	if !db.inTx {
+		db.counts.put(key, total)
+	}
+	return total, nil
+}
+
+// boundedRead runs a list or count read with the configured statement timeout. The timeout is
+// set for a transaction of its own, so it applies to these statements alone; reads inside a
+// caller's transaction, or without a timeout configured, run as they are. Statements cancelled
+// by the timeout fail with ErrQueryTimeout.
+func (db *PostgreSQL) boundedRead(ctx context.Context, read func(conn querier) error) error {
+	if db.listTimeout <= 0 || db.inTx {
+		return queryTimeout(ctx, read(db.conn))
+	}
+
+	tx, err := db.timed.begin(ctx)
+	if err != nil {
+		return transient(fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", max(db.listTimeout.Milliseconds(), 1))); err != nil {
+		return fmt.Errorf("failed to set statement timeout: %w", err)
+	}
+	if err := read(tx); err != nil {
+		return queryTimeout(ctx, err)
+	}
+	return tx.Commit(ctx)
+}
+
+// queryTimeout marks err with ErrQueryTimeout if it is a statement cancelled by PostgreSQL
+// rather than by the caller, whose own cancellation is returned as it is
+func queryTimeout(ctx context.Context, err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != queryCanceledCode || ctx.Err() != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+}
+
+func (db *PostgreSQL) List(
+	ctx context.Context,
+	filter *ServerFilter,
+	cursor string,
+	limit int,
+) ([]*apiv0.ServerJSON, string, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	// Build WHERE clause for filtering
+	whereConditions, args, err := filterConditions(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if condition, tenantArgs := tenantScope(ctx, len(args)+1); condition != "" {
+		whereConditions = append(whereConditions, condition)
+		args = append(args, tenantArgs...)
+	}
+	argIndex := len(args) + 1
+
+	// Incremental sync lists oldest changes first so clients can checkpoint, and searches list
+	// servers whose name matches first
+	incremental := filter != nil && filter.UpdatedSince != nil
+	orderBy := "id"
+	rank := ""
+	switch {
+	case incremental:
+		orderBy = "updated_at, id"
+	case filter != nil && filter.Search != nil:
+		var rankArgs []any
+		rank, rankArgs = searchRankExpression(*filter.Search, argIndex)
+		args = append(args, rankArgs...)
+		argIndex += len(rankArgs)
+		orderBy = rank + " DESC, id"
 	}
 
-	// Add cursor pagination using primary key ID
+	// Add cursor pagination using primary key ID (keyset on updated_at, id for incremental sync)
 	if cursor != "" {
-		if _, err := uuid.Parse(cursor); err != nil {
-			return nil, "", fmt.Errorf("invalid cursor format: %w", err)
+		// A cursor is the ID of the last server on the previous page; an unknown one would
+		// silently end incremental sync, whose keyset is looked up from it
+		if uuid.Validate(cursor) != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		cursorQuery := `SELECT EXISTS (SELECT 1 FROM servers WHERE id = $1`
+		cursorArgs := []any{cursor}
+		if condition, tenantArgs := tenantScope(ctx, 2); condition != "" {
+			cursorQuery += " AND " + condition
+			cursorArgs = append(cursorArgs, tenantArgs...)
+		}
+		var exists bool
+		err := db.retryRead(ctx, func() error {
+			return db.conn.QueryRow(ctx, cursorQuery+")", cursorArgs...).Scan(&exists)
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to look up cursor: %w", err)
+		}
+		if !exists {
+			return nil, "", ErrInvalidCursor
+		}
+		switch {
+		case incremental:
+			whereConditions = append(whereConditions, fmt.Sprintf("(updated_at, id) > (SELECT updated_at, id FROM servers WHERE id = $%d)", argIndex))
+		case rank != "":
+			whereConditions = append(whereConditions, fmt.Sprintf("(-(%s), id) > (SELECT -(%s), id FROM servers WHERE id = $%d)", rank, rank, argIndex))
+		default:
+			whereConditions = append(whereConditions, fmt.Sprintf("id > $%d", argIndex))
 		}
-		whereConditions = append(whereConditions, fmt.Sprintf("id > $%d", argIndex))
 		args = append(args, cursor)
 		argIndex++
 	}
@@ -131,42 +452,57 @@ func (db *PostgreSQL) List(
 		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
 	}
 
+	// Summaries only need a handful of fields, so avoid shipping whole documents
+	selectValue := "value"
+	if filter != nil && filter.Projection == ProjectionSummary {
+		selectValue = summaryProjection
+	}
+
 	// Simple query on servers table
 	query := fmt.Sprintf(`
-        SELECT value
+        SELECT %s
         FROM servers
         %s
-        ORDER BY id
+        ORDER BY %s
         LIMIT $%d
-    `, whereClause, argIndex)
+    `, selectValue, whereClause, orderBy, argIndex)
 	args = append(args, limit)
 
-	rows, err := db.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to query servers: %w", err)
-	}
-	defer rows.Close()
-
 	var results []*apiv0.ServerJSON
-	for rows.Next() {
-		var valueJSON []byte
+	err = db.retryRead(ctx, func() error {
+		return db.boundedRead(ctx, func(conn querier) error {
+			rows, err := conn.Query(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to query servers: %w", err)
+			}
+			defer rows.Close()
 
-		err := rows.Scan(&valueJSON)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to scan server row: %w", err)
-		}
+			results = nil
+			for rows.Next() {
+				var valueJSON []byte
 
-		// Parse the complete ServerJSON from JSONB
-		var serverJSON apiv0.ServerJSON
-		if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-			return nil, "", fmt.Errorf("failed to unmarshal server JSON: %w", err)
-		}
+				err := rows.Scan(&valueJSON)
+				if err != nil {
+					return fmt.Errorf("failed to scan server row: %w", err)
+				}
 
-		results = append(results, &serverJSON)
-	}
+				// Parse the complete ServerJSON from JSONB
+				var serverJSON apiv0.ServerJSON
+				if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
+					return fmt.Errorf("failed to unmarshal server JSON: %w", err)
+				}
 
-	if err := rows.Err(); err != nil {
-		return nil, "", fmt.Errorf("error iterating rows: %w", err)
+				results = append(results, &serverJSON)
+			}
+
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("error iterating rows: %w", err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, "", err
 	}
 
 	// Determine next cursor using registry metadata ID
@@ -186,16 +522,26 @@ func (db *PostgreSQL) GetByID(ctx context.Context, id string) (*apiv0.ServerJSON
 		return nil, ctx.Err()
 	}
 
+	// IDs are UUIDs, so anything else cannot match; PostgreSQL would reject it as a query error
+	if uuid.Validate(id) != nil {
+		return nil, ErrNotFound
+	}
+
 	query := `
 		SELECT value
 		FROM servers
 		WHERE id = $1
 	`
+	args := []any{id}
+	if condition, tenantArgs := tenantScope(ctx, 2); condition != "" {
+		query += " AND " + condition
+		args = append(args, tenantArgs...)
+	}
 
 	var valueJSON []byte
-
-	err := db.pool.QueryRow(ctx, query, id).Scan(&valueJSON)
-
+	err := db.retryRead(ctx, func() error {
+		return db.conn.QueryRow(ctx, query, args...).Scan(&valueJSON)
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -212,6 +558,157 @@ func (db *PostgreSQL) GetByID(ctx context.Context, id string) (*apiv0.ServerJSON
 	return &serverJSON, nil
 }
 
+// GetByIDs retrieves several servers by ID with a single query, keyed by ID, listing the IDs that match none
+func (db *PostgreSQL) GetByIDs(ctx context.Context, ids []string) (map[string]*apiv0.ServerJSON, []string, error) {
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+
+	// IDs are UUIDs, so anything else cannot match
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if uuid.Validate(id) == nil {
+			valid = append(valid, id)
+		}
+	}
+
+	found := make(map[string]*apiv0.ServerJSON, len(valid))
+	if len(valid) > 0 {
+		query := `
+			SELECT id, value
+			FROM servers
+			WHERE id = ANY($1)
+		`
+		args := []any{valid}
+		if condition, tenantArgs := tenantScope(ctx, 2); condition != "" {
+			query += " AND " + condition
+			args = append(args, tenantArgs...)
+		}
+
+		err := db.retryRead(ctx, func() error {
+			clear(found)
+			rows, err := db.conn.Query(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				var id string
+				var valueJSON []byte
+				if err := rows.Scan(&id, &valueJSON); err != nil {
+					return fmt.Errorf("failed to scan server row: %w", err)
+				}
+
+				var serverJSON apiv0.ServerJSON
+				if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
+					return fmt.Errorf("failed to unmarshal server JSON: %w", err)
+				}
+				found[id] = &serverJSON
+			}
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get servers by ID: %w", err)
+		}
+	}
+
+	return found, missingIDs(ids, found), nil
+}
+
+// searchRankExpression ranks a server 1 if its name matches the search query, as searchRank
+// does, and 0 otherwise, taking the query and its tokens as arguments from argIndex on
+func searchRankExpression(search string, argIndex int) (string, []any) {
+	conditions := []string{fmt.Sprintf("value->>'name' ILIKE $%d", argIndex)}
+	args := []any{"%" + search + "%"}
+	if tokens := SearchTokens(search); len(tokens) > 0 {
+		all := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			argIndex++
+			all = append(all, fmt.Sprintf("value->>'name' ILIKE $%d", argIndex))
+			args = append(args, "%"+token+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(all, " AND ")+")")
+	}
+	return "CASE WHEN " + strings.Join(conditions, " OR ") + " THEN 1 ELSE 0 END", args
+}
+
+// tenantScope returns the condition limiting a query to the tenant ctx is scoped to, taking the
+// tenant as argument number argIndex, or no condition for an unscoped context
+func tenantScope(ctx context.Context, argIndex int) (string, []any) {
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	return fmt.Sprintf("tenant = $%d", argIndex), []any{tenant}
+}
+
+// headColumns selects a ServerHead from a servers row without reading the rest of the document
+const headColumns = `id, value->>'name', value->>'version', COALESCE(value->>'status', ''),
+		COALESCE((value->'_meta'->'io.modelcontextprotocol.registry/official'->>'is_latest')::boolean, false),
+		updated_at`
+
+// GetHeadByID retrieves the registry metadata of a server version by ID, without loading its document
+func (db *PostgreSQL) GetHeadByID(ctx context.Context, id string) (*ServerHead, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if uuid.Validate(id) != nil {
+		return nil, ErrNotFound
+	}
+
+	query := `SELECT ` + headColumns + ` FROM servers WHERE id = $1`
+	args := []any{id}
+	if condition, tenantArgs := tenantScope(ctx, 2); condition != "" {
+		query += " AND " + condition
+		args = append(args, tenantArgs...)
+	}
+	return db.queryHead(ctx, query, args...)
+}
+
+// FindHead retrieves the registry metadata of a server version by name and version, or of its
+// latest version if version is empty, without loading its document
+func (db *PostgreSQL) FindHead(ctx context.Context, name, version string) (*ServerHead, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `SELECT ` + headColumns + ` FROM servers
+		WHERE value->>'name' = $1
+		  AND COALESCE(value->>'status', '') NOT IN ('pending', 'rejected')`
+	args := []any{name}
+	if version != "" {
+		query += ` AND value->>'version' = $2`
+		args = append(args, version)
+	} else {
+		query += ` AND value->'_meta'->'io.modelcontextprotocol.registry/official'->>'is_latest' = 'true'`
+	}
+	if condition, tenantArgs := tenantScope(ctx, len(args)+1); condition != "" {
+		query += " AND " + condition
+		args = append(args, tenantArgs...)
+	}
+	query += ` LIMIT 1`
+	return db.queryHead(ctx, query, args...)
+}
+
+// queryHead runs a query selecting headColumns and scans the single ServerHead it returns
+func (db *PostgreSQL) queryHead(ctx context.Context, query string, args ...any) (*ServerHead, error) {
+	var head ServerHead
+	var status string
+	err := db.retryRead(ctx, func() error {
+		return db.conn.QueryRow(ctx, query, args...).Scan(&head.ID, &head.Name, &head.Version, &status, &head.IsLatest, &head.LastModified)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get server metadata: %w", err)
+	}
+	head.Status = model.Status(status)
+	return &head, nil
+}
+
 // CreateServer adds a new server to the database
 func (db *PostgreSQL) CreateServer(ctx context.Context, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
 	if ctx.Err() != nil {
@@ -224,6 +721,9 @@ func (db *PostgreSQL) CreateServer(ctx context.Context, server *apiv0.ServerJSON
 	}
 
 	id := server.Meta.Official.ID
+	if !tenancy.Allows(ctx, tenancy.Of(server)) {
+		return nil, fmt.Errorf("%w: server belongs to another tenant", ErrInvalidInput)
+	}
 
 	// Marshal the complete server to JSONB
 	valueJSON, err := json.Marshal(server)
@@ -233,14 +733,19 @@ func (db *PostgreSQL) CreateServer(ctx context.Context, server *apiv0.ServerJSON
 
 	// Insert into simple servers table
 	query := `
-		INSERT INTO servers (id, value)
-		VALUES ($1, $2)
+		INSERT INTO servers (id, value, updated_at, tenant, search_keywords)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	_, err = db.pool.Exec(ctx, query, id, valueJSON)
+	_, err = db.conn.Exec(ctx, query, id, valueJSON, server.LastModified(), tenancy.Of(server), SearchKeywords(server))
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert server: %w", err)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, ErrAlreadyExists
+		}
+		return nil, transient(fmt.Errorf("failed to insert server: %w", err))
 	}
+	db.counts.clear()
 
 	return server, nil
 }
@@ -255,6 +760,10 @@ func (db *PostgreSQL) UpdateServer(ctx context.Context, id string, server *apiv0
 	if server.Meta == nil || server.Meta.Official == nil || server.Meta.Official.ID != id {
 		return nil, fmt.Errorf("%w: io.modelcontextprotocol.registry/official.id must match path id (%s)", ErrInvalidInput, id)
 	}
+	// A server's tenant never changes, and is out of reach of other tenants
+	if uuid.Validate(id) != nil || !tenancy.Allows(ctx, tenancy.Of(server)) {
+		return nil, ErrNotFound
+	}
 
 	// Marshal updated server
 	valueJSON, err := json.Marshal(server)
@@ -265,24 +774,856 @@ func (db *PostgreSQL) UpdateServer(ctx context.Context, id string, server *apiv0
 	// Update the complete server record in simple table
 	query := `
 		UPDATE servers 
-		SET value = $1
-		WHERE id = $2
+		SET value = $1, updated_at = $3, search_keywords = $5
+		WHERE id = $2 AND tenant = $4
 	`
 
-	result, err := db.pool.Exec(ctx, query, valueJSON, id)
+	result, err := db.conn.Exec(ctx, query, valueJSON, id, server.LastModified(), tenancy.Of(server), SearchKeywords(server))
 	if err != nil {
-		return nil, fmt.Errorf("failed to update server: %w", err)
+		return nil, transient(fmt.Errorf("failed to update server: %w", err))
 	}
 
 	if result.RowsAffected() == 0 {
 		return nil, ErrNotFound
 	}
+	db.counts.clear()
 
 	return server, nil
 }
 
-// Close closes the database connection
-func (db *PostgreSQL) Close() error {
-	db.pool.Close()
+// CreateAuthChallenge stores a new authentication challenge
+func (db *PostgreSQL) CreateAuthChallenge(ctx context.Context, challenge *AuthChallenge) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `
+		INSERT INTO auth_challenges (nonce, domain, issued_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := db.conn.Exec(ctx, query, challenge.Nonce, challenge.Domain, challenge.IssuedAt, challenge.ExpiresAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return ErrAlreadyExists
+		}
+		return transient(fmt.Errorf("failed to insert auth challenge: %w", err))
+	}
+
 	return nil
 }
+
+// ConsumeAuthChallenge retrieves and deletes a challenge by nonce, so it can only be used once
+func (db *PostgreSQL) ConsumeAuthChallenge(ctx context.Context, nonce string) (*AuthChallenge, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// DELETE ... RETURNING makes retrieval and invalidation a single atomic step,
+	// so concurrent attempts to use the same nonce cannot both succeed
+	query := `
+		DELETE FROM auth_challenges
+		WHERE nonce = $1
+		RETURNING nonce, domain, issued_at, expires_at
+	`
+
+	var challenge AuthChallenge
+	err := db.conn.QueryRow(ctx, query, nonce).Scan(
+		&challenge.Nonce, &challenge.Domain, &challenge.IssuedAt, &challenge.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, transient(fmt.Errorf("failed to consume auth challenge: %w", err))
+	}
+
+	return &challenge, nil
+}
+
+// PurgeExpiredAuthChallenges deletes challenges that expired before now
+func (db *PostgreSQL) PurgeExpiredAuthChallenges(ctx context.Context, now time.Time) (int64, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	result, err := db.conn.Exec(ctx, `DELETE FROM auth_challenges WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, transient(fmt.Errorf("failed to purge expired auth challenges: %w", err))
+	}
+	return result.RowsAffected(), nil
+}
+
+// CreateNamespaceNotification stores a publish notification registration
+func (db *PostgreSQL) CreateNamespaceNotification(ctx context.Context, notification *NamespaceNotification) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `
+		INSERT INTO namespace_notifications (id, namespace, webhook_url, email, created_by, created_at, tenant)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7)
+	`
+
+	tenant, _ := tenancy.FromContext(ctx)
+	_, err := db.conn.Exec(ctx, query, notification.ID, notification.Namespace, notification.WebhookURL,
+		notification.Email, notification.CreatedBy, notification.CreatedAt, tenant)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return ErrAlreadyExists
+		}
+		return transient(fmt.Errorf("failed to insert namespace notification: %w", err))
+	}
+
+	return nil
+}
+
+// ListNamespaceNotifications returns the publish notification registrations for a namespace, oldest first
+func (db *PostgreSQL) ListNamespaceNotifications(ctx context.Context, namespace string) ([]*NamespaceNotification, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT id, namespace, COALESCE(webhook_url, ''), COALESCE(email, ''), created_by, tenant, created_at
+		FROM namespace_notifications
+		WHERE namespace = $1
+	`
+	args := []any{namespace}
+	if condition, tenantArgs := tenantScope(ctx, 2); condition != "" {
+		query += " AND " + condition
+		args = append(args, tenantArgs...)
+	}
+	query += " ORDER BY created_at, id"
+
+	var notifications []*NamespaceNotification
+	err := db.retryRead(ctx, func() error {
+		rows, err := db.conn.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query namespace notifications: %w", err)
+		}
+		defer rows.Close()
+
+		notifications = nil
+		for rows.Next() {
+			var notification NamespaceNotification
+			if err := rows.Scan(&notification.ID, &notification.Namespace, &notification.WebhookURL,
+				&notification.Email, &notification.CreatedBy, &notification.Tenant, &notification.CreatedAt); err != nil {
+				return fmt.Errorf("failed to scan namespace notification: %w", err)
+			}
+			notifications = append(notifications, &notification)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating namespace notifications: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// DeleteNamespaceNotification removes a publish notification registration by ID
+func (db *PostgreSQL) DeleteNamespaceNotification(ctx context.Context, id string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if uuid.Validate(id) != nil {
+		return ErrNotFound
+	}
+
+	query := `DELETE FROM namespace_notifications WHERE id = $1`
+	args := []any{id}
+	if condition, tenantArgs := tenantScope(ctx, 2); condition != "" {
+		query += " AND " + condition
+		args = append(args, tenantArgs...)
+	}
+	result, err := db.conn.Exec(ctx, query, args...)
+	if err != nil {
+		return transient(fmt.Errorf("failed to delete namespace notification: %w", err))
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateOutboxEvent stores a notification to deliver
+func (db *PostgreSQL) CreateOutboxEvent(ctx context.Context, event *OutboxEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `
+		INSERT INTO outbox_events (id, registration_id, webhook_url, email, event, payload, created_at, next_attempt_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7, $8)
+	`
+
+	_, err := db.conn.Exec(ctx, query, event.ID, event.RegistrationID, event.WebhookURL, event.Email,
+		event.Event, event.Payload, event.CreatedAt, event.NextAttemptAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return ErrAlreadyExists
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return fmt.Errorf("%w: unknown notification registration %s", ErrInvalidInput, event.RegistrationID)
+		}
+		return transient(fmt.Errorf("failed to insert outbox event: %w", err))
+	}
+
+	return nil
+}
+
+// ClaimOutboxEvents returns up to limit uncompleted events due at now, leasing them until now+lease
+func (db *PostgreSQL) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxEvent, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// SKIP LOCKED lets dispatchers on several replicas claim different events at the same time
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE completed_at IS NULL AND next_attempt_at <= $1
+			ORDER BY created_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, registration_id, COALESCE(webhook_url, ''), COALESCE(email, ''), event, payload,
+			created_at, attempts, next_attempt_at, last_error
+	`
+
+	rows, err := db.conn.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, transient(fmt.Errorf("failed to claim outbox events: %w", err))
+	}
+	defer rows.Close()
+
+	var events []*OutboxEvent
+	for rows.Next() {
+		var event OutboxEvent
+		if err := rows.Scan(&event.ID, &event.RegistrationID, &event.WebhookURL, &event.Email, &event.Event,
+			&event.Payload, &event.CreatedAt, &event.Attempts, &event.NextAttemptAt, &event.LastError); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, transient(fmt.Errorf("error iterating outbox events: %w", err))
+	}
+
+	// RETURNING does not follow the subquery's order
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.Before(events[j].CreatedAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// RetryOutboxEvent records a failed delivery attempt, making the event due again at nextAttemptAt
+func (db *PostgreSQL) RetryOutboxEvent(ctx context.Context, id string, nextAttemptAt time.Time, lastError string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if uuid.Validate(id) != nil {
+		return ErrNotFound
+	}
+
+	result, err := db.conn.Exec(ctx, `
+		UPDATE outbox_events SET next_attempt_at = $2, last_error = $3
+		WHERE id = $1 AND completed_at IS NULL
+	`, id, nextAttemptAt, lastError)
+	if err != nil {
+		return transient(fmt.Errorf("failed to reschedule outbox event: %w", err))
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CompleteOutboxEvent marks an event delivered or abandoned, purging events completed long ago
+func (db *PostgreSQL) CompleteOutboxEvent(ctx context.Context, id string, completedAt time.Time, lastError string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if uuid.Validate(id) != nil {
+		return ErrNotFound
+	}
+
+	result, err := db.conn.Exec(ctx, `
+		UPDATE outbox_events SET completed_at = $2, last_error = $3
+		WHERE id = $1 AND completed_at IS NULL
+	`, id, completedAt, lastError)
+	if err != nil {
+		return transient(fmt.Errorf("failed to complete outbox event: %w", err))
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// PurgeCompletedOutboxEvents deletes events completed more than outboxRetention before now
+func (db *PostgreSQL) PurgeCompletedOutboxEvents(ctx context.Context, now time.Time) (int64, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	result, err := db.conn.Exec(ctx, `DELETE FROM outbox_events WHERE completed_at < $1`, now.Add(-outboxRetention))
+	if err != nil {
+		return 0, transient(fmt.Errorf("failed to purge completed outbox events: %w", err))
+	}
+	return result.RowsAffected(), nil
+}
+
+// ListNamespaceReservations returns every namespace reservation, ordered by namespace
+func (db *PostgreSQL) ListNamespaceReservations(ctx context.Context) ([]*NamespaceReservation, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT namespace, allowed_subjects, reason, created_by, created_at
+		FROM namespace_reservations
+		ORDER BY namespace
+	`
+
+	var reservations []*NamespaceReservation
+	err := db.retryRead(ctx, func() error {
+		rows, err := db.conn.Query(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to query namespace reservations: %w", err)
+		}
+		defer rows.Close()
+
+		reservations = nil
+		for rows.Next() {
+			var reservation NamespaceReservation
+			if err := rows.Scan(&reservation.Namespace, &reservation.AllowedSubjects, &reservation.Reason,
+				&reservation.CreatedBy, &reservation.CreatedAt); err != nil {
+				return fmt.Errorf("failed to scan namespace reservation: %w", err)
+			}
+			reservations = append(reservations, &reservation)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating namespace reservations: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// PutNamespaceReservation stores a namespace reservation, replacing any for the same namespace
+func (db *PostgreSQL) PutNamespaceReservation(ctx context.Context, reservation *NamespaceReservation) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `
+		INSERT INTO namespace_reservations (namespace, allowed_subjects, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (namespace) DO UPDATE SET
+			allowed_subjects = EXCLUDED.allowed_subjects,
+			reason = EXCLUDED.reason,
+			created_by = EXCLUDED.created_by,
+			created_at = EXCLUDED.created_at
+	`
+
+	allowedSubjects := reservation.AllowedSubjects
+	if allowedSubjects == nil {
+		allowedSubjects = []string{}
+	}
+	_, err := db.conn.Exec(ctx, query, reservation.Namespace, allowedSubjects, reservation.Reason,
+		reservation.CreatedBy, reservation.CreatedAt)
+	if err != nil {
+		return transient(fmt.Errorf("failed to store namespace reservation: %w", err))
+	}
+
+	return nil
+}
+
+// DeleteNamespaceReservation removes the reservation of a namespace
+func (db *PostgreSQL) DeleteNamespaceReservation(ctx context.Context, namespace string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	result, err := db.conn.Exec(ctx, `DELETE FROM namespace_reservations WHERE namespace = $1`, namespace)
+	if err != nil {
+		return transient(fmt.Errorf("failed to delete namespace reservation: %w", err))
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RecordNamespaceVerification stores a namespace verification, replacing any earlier one for the namespace
+func (db *PostgreSQL) RecordNamespaceVerification(ctx context.Context, verification *NamespaceVerification) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `
+		INSERT INTO namespace_verifications (tenant, namespace, method, subject, verified_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant, namespace) DO UPDATE SET
+			method = EXCLUDED.method,
+			subject = EXCLUDED.subject,
+			verified_at = EXCLUDED.verified_at
+	`
+
+	tenant, _ := tenancy.FromContext(ctx)
+	_, err := db.conn.Exec(ctx, query, tenant, verification.Namespace, verification.Method, verification.Subject, verification.VerifiedAt)
+	if err != nil {
+		return transient(fmt.Errorf("failed to store namespace verification: %w", err))
+	}
+
+	return nil
+}
+
+// GetNamespaceVerification returns the latest verification of a namespace, or ErrNotFound
+func (db *PostgreSQL) GetNamespaceVerification(ctx context.Context, namespace string) (*NamespaceVerification, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT tenant, namespace, method, subject, verified_at
+		FROM namespace_verifications
+		WHERE tenant = $1 AND namespace = $2
+	`
+
+	tenant, _ := tenancy.FromContext(ctx)
+	var verification NamespaceVerification
+	err := db.retryRead(ctx, func() error {
+		return db.conn.QueryRow(ctx, query, tenant, namespace).Scan(
+			&verification.Tenant, &verification.Namespace, &verification.Method, &verification.Subject, &verification.VerifiedAt,
+		)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get namespace verification: %w", err)
+	}
+
+	return &verification, nil
+}
+
+// PutNamespaceSettings stores the settings of a namespace, replacing any earlier ones
+func (db *PostgreSQL) PutNamespaceSettings(ctx context.Context, settings *NamespaceSettings) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `
+		INSERT INTO namespace_settings (tenant, namespace, license, categories, website_url, contact, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tenant, namespace) DO UPDATE SET
+			license = EXCLUDED.license,
+			categories = EXCLUDED.categories,
+			website_url = EXCLUDED.website_url,
+			contact = EXCLUDED.contact,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	categories := settings.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	tenant, _ := tenancy.FromContext(ctx)
+	_, err := db.conn.Exec(ctx, query, tenant, settings.Namespace, settings.License, categories, settings.WebsiteURL,
+		settings.Contact, settings.UpdatedBy, settings.UpdatedAt)
+	if err != nil {
+		return transient(fmt.Errorf("failed to store namespace settings: %w", err))
+	}
+
+	return nil
+}
+
+// GetNamespaceSettings returns the settings of a namespace, or ErrNotFound
+func (db *PostgreSQL) GetNamespaceSettings(ctx context.Context, namespace string) (*NamespaceSettings, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT tenant, namespace, license, categories, website_url, contact, updated_by, updated_at
+		FROM namespace_settings
+		WHERE tenant = $1 AND namespace = $2
+	`
+
+	tenant, _ := tenancy.FromContext(ctx)
+	var settings NamespaceSettings
+	err := db.retryRead(ctx, func() error {
+		return db.conn.QueryRow(ctx, query, tenant, namespace).Scan(
+			&settings.Tenant, &settings.Namespace, &settings.License, &settings.Categories, &settings.WebsiteURL,
+			&settings.Contact, &settings.UpdatedBy, &settings.UpdatedAt,
+		)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get namespace settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// AddServerFetchCounts adds each count to the fetch count of its server on its day, in one statement
+func (db *PostgreSQL) AddServerFetchCounts(ctx context.Context, counts []ServerFetchCount) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	// Counts for the same bucket are summed first, as one statement cannot update a row twice
+	query := `
+		INSERT INTO server_fetch_counts (tenant, namespace, server_name, day, count)
+		SELECT tenant, namespace, server_name, day, SUM(count)
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::date[], $5::bigint[]) AS c(tenant, namespace, server_name, day, count)
+		GROUP BY tenant, namespace, server_name, day
+		ON CONFLICT (tenant, namespace, server_name, day) DO UPDATE SET
+			count = server_fetch_counts.count + EXCLUDED.count
+	`
+
+	tenants := make([]string, len(counts))
+	namespaces := make([]string, len(counts))
+	serverNames := make([]string, len(counts))
+	days := make([]time.Time, len(counts))
+	values := make([]int64, len(counts))
+	for i, count := range counts {
+		tenants[i], namespaces[i], serverNames[i] = count.Tenant, count.Namespace, count.ServerName
+		days[i], values[i] = fetchDay(count.Day), count.Count
+	}
+	if _, err := db.conn.Exec(ctx, query, tenants, namespaces, serverNames, days, values); err != nil {
+		return transient(fmt.Errorf("failed to add server fetch counts: %w", err))
+	}
+
+	return nil
+}
+
+// ListServerFetchCounts returns the fetch counts of the servers in a namespace on the days from from to to
+func (db *PostgreSQL) ListServerFetchCounts(ctx context.Context, namespace string, from, to time.Time) ([]ServerFetchCount, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT tenant, namespace, server_name, day, count
+		FROM server_fetch_counts
+		WHERE tenant = $1 AND namespace = $2 AND day BETWEEN $3 AND $4
+		ORDER BY day, server_name
+	`
+
+	tenant, _ := tenancy.FromContext(ctx)
+	var counts []ServerFetchCount
+	err := db.retryRead(ctx, func() error {
+		rows, err := db.conn.Query(ctx, query, tenant, namespace, fetchDay(from), fetchDay(to))
+		if err != nil {
+			return fmt.Errorf("failed to query server fetch counts: %w", err)
+		}
+		defer rows.Close()
+
+		counts = nil
+		for rows.Next() {
+			var count ServerFetchCount
+			if err := rows.Scan(&count.Tenant, &count.Namespace, &count.ServerName, &count.Day, &count.Count); err != nil {
+				return fmt.Errorf("failed to scan server fetch count: %w", err)
+			}
+			count.Day = count.Day.UTC()
+			counts = append(counts, count)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// CreateServerReport stores a report about a server version
+func (db *PostgreSQL) CreateServerReport(ctx context.Context, report *ServerReport) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	query := `
+		INSERT INTO server_reports (id, server_id, tenant, namespace, server_name, version, category, text, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := db.conn.Exec(ctx, query, report.ID, report.ServerID, report.Tenant, report.Namespace, report.ServerName,
+		report.Version, report.Category, report.Text, report.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return ErrAlreadyExists
+		}
+		return transient(fmt.Errorf("failed to insert server report: %w", err))
+	}
+
+	return nil
+}
+
+// ListServerReportCounts counts the reports matching filter by server version and category
+func (db *PostgreSQL) ListServerReportCounts(ctx context.Context, filter ServerReportFilter) ([]ServerReportCount, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var conditions []string
+	tenantCondition, args := tenantScope(ctx, 1)
+	if tenantCondition != "" {
+		conditions = append(conditions, tenantCondition)
+	}
+	if filter.Namespace != "" {
+		args = append(args, filter.Namespace)
+		conditions = append(conditions, fmt.Sprintf("namespace = $%d", len(args)))
+	}
+	if filter.ServerID != "" {
+		if uuid.Validate(filter.ServerID) != nil {
+			return []ServerReportCount{}, nil
+		}
+		args = append(args, filter.ServerID)
+		conditions = append(conditions, fmt.Sprintf("server_id = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	query := fmt.Sprintf(`
+		SELECT server_id, server_name, version, category, COUNT(*), MAX(created_at)
+		FROM server_reports
+		%s
+		GROUP BY server_id, server_name, version, category
+		ORDER BY server_name, server_id, category`, whereClause)
+
+	var counts []ServerReportCount
+	err := db.retryRead(ctx, func() error {
+		rows, err := db.conn.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query server report counts: %w", err)
+		}
+		defer rows.Close()
+
+		counts = []ServerReportCount{}
+		for rows.Next() {
+			var count ServerReportCount
+			if err := rows.Scan(&count.ServerID, &count.ServerName, &count.Version, &count.Category, &count.Count, &count.LastReportedAt); err != nil {
+				return fmt.Errorf("failed to scan server report count: %w", err)
+			}
+			counts = append(counts, count)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// ListServerReports returns the reports about a server version, newest first
+func (db *PostgreSQL) ListServerReports(ctx context.Context, serverID string) ([]*ServerReport, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if uuid.Validate(serverID) != nil {
+		return nil, nil
+	}
+
+	conditions := []string{"server_id = $1"}
+	args := []any{serverID}
+	if tenantCondition, tenantArgs := tenantScope(ctx, 2); tenantCondition != "" {
+		conditions = append(conditions, tenantCondition)
+		args = append(args, tenantArgs...)
+	}
+	query := fmt.Sprintf(`
+		SELECT id, server_id, tenant, namespace, server_name, version, category, text, created_at
+		FROM server_reports
+		WHERE %s
+		ORDER BY created_at DESC, id DESC`, strings.Join(conditions, " AND "))
+
+	var reports []*ServerReport
+	err := db.retryRead(ctx, func() error {
+		rows, err := db.conn.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query server reports: %w", err)
+		}
+		defer rows.Close()
+
+		reports = nil
+		for rows.Next() {
+			var report ServerReport
+			if err := rows.Scan(&report.ID, &report.ServerID, &report.Tenant, &report.Namespace, &report.ServerName,
+				&report.Version, &report.Category, &report.Text, &report.CreatedAt); err != nil {
+				return fmt.Errorf("failed to scan server report: %w", err)
+			}
+			reports = append(reports, &report)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// BackfillSearchKeywords stores the search keywords of up to limit server versions stored
+// without them, leaving updated_at alone since the documents do not change
+func (db *PostgreSQL) BackfillSearchKeywords(ctx context.Context, limit int) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	type pending struct {
+		id       string
+		keywords []string
+	}
+	var batch []pending
+	err := db.retryRead(ctx, func() error {
+		rows, err := db.conn.Query(ctx, `SELECT id, value FROM servers WHERE search_keywords IS NULL ORDER BY id LIMIT $1`, limit)
+		if err != nil {
+			return fmt.Errorf("failed to query servers without search keywords: %w", err)
+		}
+		defer rows.Close()
+
+		batch = nil
+		for rows.Next() {
+			var id string
+			var valueJSON []byte
+			if err := rows.Scan(&id, &valueJSON); err != nil {
+				return fmt.Errorf("failed to scan server row: %w", err)
+			}
+			var serverJSON apiv0.ServerJSON
+			if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
+				return fmt.Errorf("failed to unmarshal server JSON: %w", err)
+			}
+			batch = append(batch, pending{id: id, keywords: SearchKeywords(&serverJSON)})
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, server := range batch {
+		if _, err := db.conn.Exec(ctx, `UPDATE servers SET search_keywords = $2 WHERE id = $1`, server.id, server.keywords); err != nil {
+			return 0, transient(fmt.Errorf("failed to store search keywords: %w", err))
+		}
+	}
+	return len(batch), nil
+}
+
+// InTransaction runs fn inside a database transaction, rolling back if fn
+// fails or ctx is cancelled before the commit
+func (db *PostgreSQL) InTransaction(ctx context.Context, fn func(ctx context.Context, tx Database) error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// Nested calls join the enclosing transaction
+	if db.inTx {
+		return fn(ctx, db)
+	}
+
+	tx, err := db.timed.begin(ctx)
+	if err != nil {
+		return transient(fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	// Roll back even if ctx was cancelled; this is a no-op after a successful commit
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	if err := fn(ctx, &PostgreSQL{pool: db.pool, timed: db.timed, conn: tx, inTx: true, counts: db.counts}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return transient(fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	// Counts read while the transaction was open may predate its writes
+	db.counts.clear()
+	return nil
+}
+
+// Close closes the database connection
+func (db *PostgreSQL) Close() error {
+	db.pool.Close()
+	return nil
+}
+
+const (
+	// countCacheTTL bounds how stale a cached total can be after writes by other replicas
+	countCacheTTL = 10 * time.Second
+	// countCacheMaxEntries bounds the number of distinct filters whose totals are kept
+	countCacheMaxEntries = 1024
+)
+
+// countCache keeps list totals for a short time, keyed by count query and arguments
+type countCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedCount
+}
+
+type cachedCount struct {
+	total   int
+	expires time.Time
+}
+
+func newCountCache() *countCache {
+	return &countCache{entries: make(map[string]cachedCount)}
+}
+
+func (c *countCache) get(key string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return 0, false
+	}
+	return entry.total, true
+}
+
+func (c *countCache) put(key string, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= countCacheMaxEntries {
+		clear(c.entries)
+	}
+	c.entries[key] = cachedCount{total: total, expires: time.Now().Add(countCacheTTL)}
+}
+
+func (c *countCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+type primaryReadsKey struct{}
+
+// WithPrimaryReads marks ctx as serving a write, whose reads must see the primary's current
+// state rather than a read replica's, which may lag behind it: a publish checking the versions
+// already published, say, or looking up the latest version it replaces
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// primaryReads reports whether ctx was marked with WithPrimaryReads
+func primaryReads(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return primary
+}
+
+// ReadReplicaDatabase sends the server reads of requests, lists, searches, counts and lookups by
+// ID, to a read replica, and everything else to the primary: writes, transactions, the head
+// lookups that conditional writes rely on, and reads on a context marked with WithPrimaryReads.
+// A replica read that fails transiently, as when the replica is unreachable, is retried on the
+// primary and counted in Fallbacks.
+type ReadReplicaDatabase struct {
+	Database // the primary
+	replica  Database
+
+	fallbacks atomic.Int64
+}
+
+// NewReadReplicaDatabase routes reads that tolerate replication lag to replica and everything else to primary
+func NewReadReplicaDatabase(primary, replica Database) *ReadReplicaDatabase {
+	return &ReadReplicaDatabase{Database: primary, replica: replica}
+}
+
+// Fallbacks returns how many replica reads have been retried on the primary since startup
+func (db *ReadReplicaDatabase) Fallbacks() int64 {
+	return db.fallbacks.Load()
+}
+
+// reader returns the database the reads of ctx go to
+func (db *ReadReplicaDatabase) reader(ctx context.Context) Database {
+	if primaryReads(ctx) {
+		return db.Database
+	}
+	return db.replica
+}
+
+// fallBack reports whether a replica read that failed with err should be retried on the primary
+func (db *ReadReplicaDatabase) fallBack(ctx context.Context, err error) bool {
+	if !errors.Is(err, ErrTransient) || ctx.Err() != nil {
+		return false
+	}
+	db.fallbacks.Add(1)
+	log.Printf("Read replica failed, reading from the primary instead: %v", err)
+	return true
+}
+
+func (db *ReadReplicaDatabase) List(ctx context.Context, filter *ServerFilter, cursor string, limit int) ([]*apiv0.ServerJSON, string, error) {
+	reader := db.reader(ctx)
+	servers, next, err := reader.List(ctx, filter, cursor, limit)
+	if reader == db.replica && db.fallBack(ctx, err) {
+		return db.Database.List(ctx, filter, cursor, limit)
+	}
+	return servers, next, err
+}
+
+func (db *ReadReplicaDatabase) Count(ctx context.Context, filter *ServerFilter) (int, error) {
+	reader := db.reader(ctx)
+	total, err := reader.Count(ctx, filter)
+	if reader == db.replica && db.fallBack(ctx, err) {
+		return db.Database.Count(ctx, filter)
+	}
+	return total, err
+}
+
+func (db *ReadReplicaDatabase) GetByID(ctx context.Context, id string) (*apiv0.ServerJSON, error) {
+	reader := db.reader(ctx)
+	server, err := reader.GetByID(ctx, id)
+	if reader == db.replica && db.fallBack(ctx, err) {
+		return db.Database.GetByID(ctx, id)
+	}
+	return server, err
+}
+
+func (db *ReadReplicaDatabase) GetByIDs(ctx context.Context, ids []string) (map[string]*apiv0.ServerJSON, []string, error) {
+	reader := db.reader(ctx)
+	found, missing, err := reader.GetByIDs(ctx, ids)
+	if reader == db.replica && db.fallBack(ctx, err) {
+		return db.Database.GetByIDs(ctx, ids)
+	}
+	return found, missing, err
+}
+
+func (db *ReadReplicaDatabase) CountNamespaces(ctx context.Context) (map[string]int, error) {
+	reader := db.reader(ctx)
+	counts, err := reader.CountNamespaces(ctx)
+	if reader == db.replica && db.fallBack(ctx, err) {
+		return db.Database.CountNamespaces(ctx)
+	}
+	return counts, err
+}
+
+// Close closes the primary and the replica
+func (db *ReadReplicaDatabase) Close() error {
+	return errors.Join(db.Database.Close(), db.replica.Close())
+}
@@ -0,0 +1,133 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// unreachableDB fails every statement as a replica that cannot be reached does
+type unreachableDB struct {
+	database.Database
+}
+
+func (unreachableDB) List(context.Context, *database.ServerFilter, string, int) ([]*apiv0.ServerJSON, string, error) {
+	return nil, "", fmt.Errorf("%w: connection refused", database.ErrTransient)
+}
+
+func (unreachableDB) GetByID(context.Context, string) (*apiv0.ServerJSON, error) {
+	return nil, fmt.Errorf("%w: connection refused", database.ErrTransient)
+}
+
+// storeServer writes a version of name to db and returns its ID
+func storeServer(t *testing.T, db database.Database, seq int, name string) string {
+	t.Helper()
+	id := fmt.Sprintf("00000000-0000-0000-0000-%012d", seq)
+	now := time.Now()
+	_, err := db.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Name: name, Description: "A test server", Version: "1.0.0",
+		Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{ID: id, PublishedAt: now, UpdatedAt: now, IsLatest: true}},
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestReadReplicaDatabase_Routing(t *testing.T) {
+	ctx := context.Background()
+	primary := database.NewMemoryDB()
+	replica := database.NewMemoryDB()
+	db := database.NewReadReplicaDatabase(primary, replica)
+
+	// A version the replica has not caught up with yet, and one only the replica has
+	lagging := storeServer(t, primary, 1, "io.github.example/lagging")
+	replicated := storeServer(t, replica, 2, "io.github.example/replicated")
+
+	t.Run("reads go to the replica", func(t *testing.T) {
+		servers, _, err := db.List(ctx, nil, "", 10)
+		require.NoError(t, err)
+		require.Len(t, servers, 1)
+		assert.Equal(t, "io.github.example/replicated", servers[0].Name)
+
+		total, err := db.Count(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+
+		_, err = db.GetByID(ctx, lagging)
+		assert.ErrorIs(t, err, database.ErrNotFound)
+		found, missing, err := db.GetByIDs(ctx, []string{lagging, replicated})
+		require.NoError(t, err)
+		assert.Contains(t, found, replicated)
+		assert.Equal(t, []string{lagging}, missing)
+
+		namespaces, err := db.CountNamespaces(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"io.github.example": 1}, namespaces)
+	})
+
+	t.Run("reads for writes go to the primary", func(t *testing.T) {
+		primaryCtx := database.WithPrimaryReads(ctx)
+		server, err := db.GetByID(primaryCtx, lagging)
+		require.NoError(t, err)
+		assert.Equal(t, "io.github.example/lagging", server.Name)
+
+		servers, _, err := db.List(primaryCtx, nil, "", 10)
+		require.NoError(t, err)
+		require.Len(t, servers, 1)
+		assert.Equal(t, "io.github.example/lagging", servers[0].Name)
+	})
+
+	t.Run("head lookups go to the primary", func(t *testing.T) {
+		head, err := db.FindHead(ctx, "io.github.example/lagging", "")
+		require.NoError(t, err)
+		assert.Equal(t, lagging, head.ID)
+	})
+
+	t.Run("writes and transactions go to the primary", func(t *testing.T) {
+		written := storeServer(t, db, 3, "io.github.example/written")
+		_, err := primary.GetByID(ctx, written)
+		require.NoError(t, err)
+		_, err = replica.GetByID(ctx, written)
+		assert.ErrorIs(t, err, database.ErrNotFound)
+
+		err = db.InTransaction(ctx, func(ctx context.Context, tx database.Database) error {
+			_, err := tx.GetByID(ctx, lagging)
+			return err
+		})
+		assert.NoError(t, err, "reads inside a transaction see the primary")
+	})
+
+	assert.Zero(t, db.Fallbacks())
+}
+
+func TestReadReplicaDatabase_Fallback(t *testing.T) {
+	ctx := context.Background()
+	primary := database.NewMemoryDB()
+	id := storeServer(t, primary, 1, "io.github.example/weather")
+	db := database.NewReadReplicaDatabase(primary, unreachableDB{Database: database.NewMemoryDB()})
+
+	servers, _, err := db.List(ctx, nil, "", 10)
+	require.NoError(t, err)
+	assert.Len(t, servers, 1)
+	server, err := db.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "io.github.example/weather", server.Name)
+	assert.Equal(t, int64(2), db.Fallbacks(), "each read the replica failed is counted")
+
+	// Answers from the replica, even failures, are not retried on the primary
+	_, err = database.NewReadReplicaDatabase(primary, database.NewMemoryDB()).GetByID(ctx, id)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	// Nor are reads of requests that have gone away
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = db.GetByID(cancelled, id)
+	assert.Error(t, err)
+	assert.Equal(t, int64(2), db.Fallbacks())
+}
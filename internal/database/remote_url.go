@@ -0,0 +1,28 @@
+package database
+
+import (
+	"regexp"
+	"strings"
+)
+
+// remoteURLRegex splits a URL into its scheme, authority, path and query, dropping any fragment.
+// Migration 009 uses the same expression in canonical_remote_url.
+var remoteURLRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*)://([^/?#]*)([^?#]*)(\?[^#]*)?`)
+
+// CanonicalRemoteURL returns the form in which remote URLs are compared when looking for
+// servers that share one: the scheme and host lowercased, without the default port for http or
+// https, trailing slashes or a fragment. Strings that are not URLs are returned unchanged.
+func CanonicalRemoteURL(rawURL string) string {
+	parts := remoteURLRegex.FindStringSubmatch(rawURL)
+	if parts == nil {
+		return rawURL
+	}
+	scheme, host := strings.ToLower(parts[1]), strings.ToLower(parts[2])
+	switch scheme {
+	case "https":
+		host = strings.TrimSuffix(host, ":443")
+	case "http":
+		host = strings.TrimSuffix(host, ":80")
+	}
+	return scheme + "://" + host + strings.TrimRight(parts[3], "/") + parts[4]
+}
@@ -0,0 +1,35 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+func TestCanonicalRemoteURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://api.example.com/mcp", "https://api.example.com/mcp"},
+		{"https://api.example.com/mcp/", "https://api.example.com/mcp"},
+		{"https://api.example.com:443/mcp", "https://api.example.com/mcp"},
+		{"http://api.example.com:80/mcp", "http://api.example.com/mcp"},
+		{"HTTPS://API.Example.com/MCP", "https://api.example.com/MCP"},
+		{"https://api.example.com", "https://api.example.com"},
+		{"https://api.example.com/", "https://api.example.com"},
+		{"https://api.example.com:8443/mcp", "https://api.example.com:8443/mcp"},
+		{"http://api.example.com:443/mcp", "http://api.example.com:443/mcp"},
+		{"https://api.example.com/mcp/?tenant=a#docs", "https://api.example.com/mcp?tenant=a"},
+		{"https://{region}.example.com/{tenant}/", "https://{region}.example.com/{tenant}"},
+		{"not a url", "not a url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.expected, database.CanonicalRemoteURL(tt.url))
+		})
+	}
+}
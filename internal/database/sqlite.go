@@ -0,0 +1,1400 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+//go:embed sqlite/migrations/*.sql
+var sqliteMigrationFiles embed.FS
+
+// sqliteBusyTimeout is how long a statement waits for another connection's write to finish
+const sqliteBusyTimeout = 5 * time.Second
+
+// JSON paths into server documents
+const (
+	sqliteName     = `value ->> '$.name'`
+	sqliteStatus   = `value ->> '$.status'`
+	sqliteIsLatest = `value ->> '$._meta."io.modelcontextprotocol.registry/official".is_latest'`
+)
+
+// sqliteHeadColumns selects a ServerHead from a servers row
+const sqliteHeadColumns = `id, ` + sqliteName + `, value ->> '$.version', COALESCE(` + sqliteStatus + `, ''),
+		COALESCE(` + sqliteIsLatest + `, 0), updated_at`
+
+// sqliteQuerier is the subset of database/sql shared by the database and transactions
+type sqliteQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SQLite is an implementation of the Database interface on an embedded SQLite file, for running
+// the registry without a PostgreSQL server. It stores the same tables as PostgreSQL and answers
+// queries the same way, including cursors, search ranking and transactions.
+type SQLite struct {
+	db   *sql.DB
+	conn sqliteQuerier // db, or the transaction when running inside InTransaction
+	inTx bool
+}
+
+var registerSQLiteFunctions = sync.OnceValue(func() error {
+	// The filters match remote URLs and licenses with the same code as the memory database
+	return errors.Join(
+		sqlite.RegisterDeterministicScalarFunction("canonical_remote_url", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			remoteURL, ok := args[0].(string)
+			if !ok {
+				return nil, nil
+			}
+			return CanonicalRemoteURL(remoteURL), nil
+		}),
+		sqlite.RegisterDeterministicScalarFunction("license_mentions", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			expression, _ := args[0].(string)
+			id, _ := args[1].(string)
+			return licenseMentions(expression, id), nil
+		}),
+	)
+})
+
+// NewSQLite opens the SQLite database at path, creating it if it does not exist, and applies
+// any pending migrations
+func NewSQLite(ctx context.Context, path string) (*SQLite, error) {
+	if err := registerSQLiteFunctions(); err != nil {
+		return nil, fmt.Errorf("failed to register SQLite functions: %w", err)
+	}
+
+	// WAL lets reads proceed while a write is in progress, and immediate transactions take the
+	// write lock up front, so two transactions never deadlock upgrading their locks
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate",
+		path, sqliteBusyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", path, err)
+	}
+	if err := migrateSQLite(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to run database migrations: %w", err)
+	}
+
+	return &SQLite{db: db, conn: db}, nil
+}
+
+// migrateSQLite applies the SQLite migrations newer than the schema version recorded in the
+// database file, each in its own transaction
+func migrateSQLite(ctx context.Context, db *sql.DB) error {
+	files, err := fs.Sub(sqliteMigrationFiles, "sqlite")
+	if err != nil {
+		return err
+	}
+	migrations, err := (&Migrator{files: files}).loadMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	for _, migration := range migrations {
+		applied, err := applySQLiteMigration(ctx, db, migration)
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s), it was rolled back and later migrations were not attempted: %w",
+				migration.Version, migration.Name, err)
+		}
+		if applied {
+			log.Printf("Applied migration %d: %s", migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// applySQLiteMigration applies a migration unless the database already has it. The version is
+// read inside the transaction, whose write lock keeps other processes from migrating meanwhile.
+func applySQLiteMigration(ctx context.Context, db *sql.DB, migration Migration) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var version int
+	if err := tx.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version >= migration.Version {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return false, fmt.Errorf("failed to execute migration SQL: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, migration.Version)); err != nil {
+		return false, fmt.Errorf("failed to record migration: %w", err)
+	}
+	return true, tx.Commit()
+}
+
+// sqliteCode returns the extended result code of a SQLite error, or 0 for other errors
+func sqliteCode(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()
+	}
+	return 0
+}
+
+// sqliteTransient marks err with ErrTransient if SQLite failed it because the database was
+// locked by another writer for longer than the busy timeout
+func sqliteTransient(err error) error {
+	switch sqliteCode(err) & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return fmt.Errorf("%w: %w", ErrTransient, err)
+	}
+	return err
+}
+
+// isSQLiteUniqueViolation reports whether err is a primary key or unique constraint violation
+func isSQLiteUniqueViolation(err error) bool {
+	code := sqliteCode(err)
+	return code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || code == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// sqliteTime converts a time to the Unix microseconds times are stored as
+func sqliteTime(t time.Time) int64 {
+	return t.UnixMicro()
+}
+
+// fromSQLiteTime converts stored Unix microseconds back to a time
+func fromSQLiteTime(micros int64) time.Time {
+	return time.UnixMicro(micros).UTC()
+}
+
+// placeholders returns n comma-separated statement placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// sqliteFilterConditions builds the WHERE conditions and their arguments for a filter, matching
+// the same servers as filterConditions does in PostgreSQL
+//
+//nolint:cyclop // Database filtering logic is inherently complex but clear
+func sqliteFilterConditions(filter *ServerFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	if filter == nil {
+		return conditions, args
+	}
+	add := func(condition string, conditionArgs ...any) {
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+
+	if filter.Name != nil {
+		add(sqliteName+` = ?`, *filter.Name)
+	}
+	if filter.RemoteURL != nil {
+		add(`EXISTS (SELECT 1 FROM json_each(servers.value, '$.remotes') AS remote
+			WHERE canonical_remote_url(remote.value ->> '$.url') = ?)`, CanonicalRemoteURL(*filter.RemoteURL))
+	}
+	if filter.UpdatedSince != nil {
+		add(`updated_at >= ?`, sqliteTime(*filter.UpdatedSince))
+	}
+	if filter.SubstringName != nil {
+		add(sqliteName+` LIKE ?`, "%"+*filter.SubstringName+"%")
+	}
+	if filter.Search != nil {
+		condition := sqliteName + ` LIKE ?`
+		searchArgs := []any{"%" + *filter.Search + "%"}
+		if tokens := SearchTokens(*filter.Search); len(tokens) > 0 {
+			// The server must have every token among its keywords
+			condition = fmt.Sprintf(`(%s OR (SELECT COUNT(DISTINCT keyword.value) FROM json_each(servers.search_keywords) AS keyword
+				WHERE keyword.value IN (%s)) = %d)`, condition, placeholders(len(tokens)), len(tokens))
+			for _, token := range tokens {
+				searchArgs = append(searchArgs, token)
+			}
+		}
+		add(condition, searchArgs...)
+	}
+	if filter.Namespace != nil {
+		prefix := *filter.Namespace + "/"
+		add(`substr(`+sqliteName+`, 1, ?) = ?`, utf8.RuneCountInString(prefix), prefix)
+	}
+	if filter.Version != nil {
+		add(`value ->> '$.version' = ?`, *filter.Version)
+	}
+	if filter.IsLatest != nil {
+		add(sqliteIsLatest+` = ?`, *filter.IsLatest)
+	}
+	if filter.RegistryType != nil || filter.RuntimeHint != nil {
+		// Both fields must match the same package
+		condition := `EXISTS (SELECT 1 FROM json_each(servers.value, '$.packages') AS pkg WHERE 1 = 1`
+		var packageArgs []any
+		if filter.RegistryType != nil {
+			condition += ` AND pkg.value ->> '$.registry_type' = ?`
+			packageArgs = append(packageArgs, *filter.RegistryType)
+		}
+		if filter.RuntimeHint != nil {
+			condition += ` AND pkg.value ->> '$.runtime_hint' = ?`
+			packageArgs = append(packageArgs, *filter.RuntimeHint)
+		}
+		add(condition+`)`, packageArgs...)
+	}
+	if filter.License != nil {
+		add(`license_mentions(value ->> '$.license', ?)`, *filter.License)
+	}
+	if filter.ForkOf != nil {
+		add(`value ->> '$.forkOf' = ?`, *filter.ForkOf)
+	}
+	if filter.CompatibleWith != nil {
+		if filter.ExcludeUnknownMCPVersion {
+			add(`value ->> '$.mcpVersion' <= ?`, *filter.CompatibleWith)
+		} else {
+			add(`(value ->> '$.mcpVersion' IS NULL OR value ->> '$.mcpVersion' <= ?)`, *filter.CompatibleWith)
+		}
+	} else if filter.ExcludeUnknownMCPVersion {
+		add(`value ->> '$.mcpVersion' IS NOT NULL`)
+	}
+	if filter.Status != nil {
+		add(sqliteStatus+` = ?`, string(*filter.Status))
+	}
+	if filter.ExcludeHidden {
+		add(fmt.Sprintf(`COALESCE(%s, '') NOT IN ('%s', '%s')`, sqliteStatus, model.StatusPending, model.StatusRejected))
+	}
+
+	return conditions, args
+}
+
+// sqliteTenantScope returns the condition limiting a query to the tenant ctx is scoped to, or no
+// condition for an unscoped context
+func sqliteTenantScope(ctx context.Context) (string, []any) {
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	return `tenant = ?`, []any{tenant}
+}
+
+// sqliteSearchRank ranks a server 1 if its name matches the search query, as searchRank does,
+// and 0 otherwise
+func sqliteSearchRank(search string) (string, []any) {
+	conditions := []string{sqliteName + ` LIKE ?`}
+	args := []any{"%" + search + "%"}
+	if tokens := SearchTokens(search); len(tokens) > 0 {
+		all := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			all = append(all, sqliteName+` LIKE ?`)
+			args = append(args, "%"+token+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(all, " AND ")+")")
+	}
+	return "CASE WHEN " + strings.Join(conditions, " OR ") + " THEN 1 ELSE 0 END", args
+}
+
+// where joins conditions into a WHERE clause, or returns "" for none
+func where(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// scopedConditions returns the conditions of filter and the tenant scope of ctx
+func scopedConditions(ctx context.Context, filter *ServerFilter) ([]string, []any) {
+	conditions, args := sqliteFilterConditions(filter)
+	if condition, tenantArgs := sqliteTenantScope(ctx); condition != "" {
+		conditions = append(conditions, condition)
+		args = append(args, tenantArgs...)
+	}
+	return conditions, args
+}
+
+// CountNamespaces returns how many distinct servers each namespace has, ignoring pending and rejected versions
+func (db *SQLite) CountNamespaces(ctx context.Context) (map[string]int, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	conditions, args := scopedConditions(ctx, &ServerFilter{ExcludeHidden: true})
+	query := fmt.Sprintf(`
+		SELECT substr(%[1]s, 1, instr(%[1]s || '/', '/') - 1) AS namespace, COUNT(DISTINCT %[1]s)
+		FROM servers%[2]s
+		GROUP BY namespace`, sqliteName, where(conditions))
+
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, sqliteTransient(fmt.Errorf("failed to count namespaces: %w", err))
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var namespace string
+		var count int
+		if err := rows.Scan(&namespace, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan namespace count: %w", err)
+		}
+		counts[namespace] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating namespace counts: %w", err)
+	}
+	return counts, nil
+}
+
+// Count returns how many servers match the filter
+func (db *SQLite) Count(ctx context.Context, filter *ServerFilter) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	conditions, args := scopedConditions(ctx, filter)
+	var total int
+	if err := db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM servers`+where(conditions), args...).Scan(&total); err != nil {
+		return 0, sqliteTransient(fmt.Errorf("failed to count servers: %w", err))
+	}
+	return total, nil
+}
+
+func (db *SQLite) List(
+	ctx context.Context,
+	filter *ServerFilter,
+	cursor string,
+	limit int,
+) ([]*apiv0.ServerJSON, string, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	conditions, args := scopedConditions(ctx, filter)
+
+	// Incremental sync lists oldest changes first so clients can checkpoint, and searches list
+	// servers whose name matches first
+	incremental := filter != nil && filter.UpdatedSince != nil
+	orderBy := "id"
+	var rank string
+	var rankArgs []any
+	switch {
+	case incremental:
+		orderBy = "updated_at, id"
+	case filter != nil && filter.Search != nil:
+		rank, rankArgs = sqliteSearchRank(*filter.Search)
+		orderBy = rank + " DESC, id"
+	}
+
+	// Continue after the cursor on the same keyset the results are ordered by
+	if cursor != "" {
+		if uuid.Validate(cursor) != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		cursorQuery := `SELECT EXISTS (SELECT 1 FROM servers WHERE id = ?`
+		cursorArgs := []any{cursor}
+		if condition, tenantArgs := sqliteTenantScope(ctx); condition != "" {
+			cursorQuery += " AND " + condition
+			cursorArgs = append(cursorArgs, tenantArgs...)
+		}
+		var exists bool
+		if err := db.conn.QueryRowContext(ctx, cursorQuery+")", cursorArgs...).Scan(&exists); err != nil {
+			return nil, "", sqliteTransient(fmt.Errorf("failed to look up cursor: %w", err))
+		}
+		if !exists {
+			return nil, "", ErrInvalidCursor
+		}
+		switch {
+		case incremental:
+			conditions = append(conditions, `(updated_at, id) > (SELECT updated_at, id FROM servers WHERE id = ?)`)
+		case rank != "":
+			conditions = append(conditions, fmt.Sprintf(`(-(%s), id) > (SELECT -(%s), id FROM servers WHERE id = ?)`, rank, rank))
+			args = append(args, rankArgs...)
+			args = append(args, rankArgs...)
+		default:
+			conditions = append(conditions, `id > ?`)
+		}
+		args = append(args, cursor)
+	}
+
+	args = append(args, rankArgs...)
+	args = append(args, limit)
+	query := `SELECT value FROM servers` + where(conditions) + ` ORDER BY ` + orderBy + ` LIMIT ?`
+
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", sqliteTransient(fmt.Errorf("failed to query servers: %w", err))
+	}
+	defer rows.Close()
+
+	var results []*apiv0.ServerJSON
+	for rows.Next() {
+		var valueJSON []byte
+		if err := rows.Scan(&valueJSON); err != nil {
+			return nil, "", fmt.Errorf("failed to scan server row: %w", err)
+		}
+		var serverJSON apiv0.ServerJSON
+		if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
+			return nil, "", fmt.Errorf("failed to unmarshal server JSON: %w", err)
+		}
+		results = append(results, &serverJSON)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	nextCursor := ""
+	if len(results) > 0 && len(results) >= limit {
+		lastResult := results[len(results)-1]
+		if lastResult.Meta != nil && lastResult.Meta.Official != nil {
+			nextCursor = lastResult.Meta.Official.ID
+		}
+	}
+
+	return results, nextCursor, nil
+}
+
+func (db *SQLite) GetByID(ctx context.Context, id string) (*apiv0.ServerJSON, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	found, _, err := db.GetByIDs(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	server, ok := found[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return server, nil
+}
+
+// GetByIDs retrieves several servers by ID with a single query, keyed by ID, listing the IDs that match none
+func (db *SQLite) GetByIDs(ctx context.Context, ids []string) (map[string]*apiv0.ServerJSON, []string, error) {
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+
+	// IDs are UUIDs, so anything else cannot match
+	var args []any
+	for _, id := range ids {
+		if uuid.Validate(id) == nil {
+			args = append(args, id)
+		}
+	}
+
+	found := make(map[string]*apiv0.ServerJSON, len(args))
+	if len(args) > 0 {
+		query := fmt.Sprintf(`SELECT id, value FROM servers WHERE id IN (%s)`, placeholders(len(args)))
+		if condition, tenantArgs := sqliteTenantScope(ctx); condition != "" {
+			query += " AND " + condition
+			args = append(args, tenantArgs...)
+		}
+
+		rows, err := db.conn.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, nil, sqliteTransient(fmt.Errorf("failed to get servers by ID: %w", err))
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id string
+			var valueJSON []byte
+			if err := rows.Scan(&id, &valueJSON); err != nil {
+				return nil, nil, fmt.Errorf("failed to scan server row: %w", err)
+			}
+			var serverJSON apiv0.ServerJSON
+			if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
+				return nil, nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
+			}
+			found[id] = &serverJSON
+		}
+		if err := rows.Err(); err != nil {
+			return nil, nil, fmt.Errorf("failed to get servers by ID: %w", err)
+		}
+	}
+
+	return found, missingIDs(ids, found), nil
+}
+
+// GetHeadByID retrieves the registry metadata of a server version by ID, without loading its document
+func (db *SQLite) GetHeadByID(ctx context.Context, id string) (*ServerHead, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if uuid.Validate(id) != nil {
+		return nil, ErrNotFound
+	}
+
+	query := `SELECT ` + sqliteHeadColumns + ` FROM servers WHERE id = ?`
+	args := []any{id}
+	if condition, tenantArgs := sqliteTenantScope(ctx); condition != "" {
+		query += " AND " + condition
+		args = append(args, tenantArgs...)
+	}
+	return db.queryHead(ctx, query, args...)
+}
+
+// FindHead retrieves the registry metadata of a server version by name and version, or of its
+// latest version if version is empty, without loading its document
+func (db *SQLite) FindHead(ctx context.Context, name, version string) (*ServerHead, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	filter := &ServerFilter{Name: &name, ExcludeHidden: true}
+	if version != "" {
+		filter.Version = &version
+	} else {
+		isLatest := true
+		filter.IsLatest = &isLatest
+	}
+	conditions, args := scopedConditions(ctx, filter)
+	return db.queryHead(ctx, `SELECT `+sqliteHeadColumns+` FROM servers`+where(conditions)+` LIMIT 1`, args...)
+}
+
+// queryHead runs a query selecting sqliteHeadColumns and scans the single ServerHead it returns
+func (db *SQLite) queryHead(ctx context.Context, query string, args ...any) (*ServerHead, error) {
+	var head ServerHead
+	var status string
+	var lastModified int64
+	err := db.conn.QueryRowContext(ctx, query, args...).Scan(&head.ID, &head.Name, &head.Version, &status, &head.IsLatest, &lastModified)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, sqliteTransient(fmt.Errorf("failed to get server metadata: %w", err))
+	}
+	head.Status = model.Status(status)
+	head.LastModified = fromSQLiteTime(lastModified)
+	return &head, nil
+}
+
+// searchKeywordsJSON encodes the search keywords of a server as they are stored
+func searchKeywordsJSON(server *apiv0.ServerJSON) (string, error) {
+	keywords, err := json.Marshal(SearchKeywords(server))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal search keywords: %w", err)
+	}
+	return string(keywords), nil
+}
+
+// CreateServer adds a new server to the database
+func (db *SQLite) CreateServer(ctx context.Context, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if server.Meta == nil || server.Meta.Official == nil {
+		return nil, fmt.Errorf("server must have registry metadata with ID")
+	}
+	if !tenancy.Allows(ctx, tenancy.Of(server)) {
+		return nil, fmt.Errorf("%w: server belongs to another tenant", ErrInvalidInput)
+	}
+
+	valueJSON, err := json.Marshal(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server JSON: %w", err)
+	}
+	keywords, err := searchKeywordsJSON(server)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.conn.ExecContext(ctx, `
+		INSERT INTO servers (id, value, updated_at, tenant, search_keywords)
+		VALUES (?, ?, ?, ?, ?)
+	`, server.Meta.Official.ID, string(valueJSON), sqliteTime(server.LastModified()), tenancy.Of(server), keywords)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, sqliteTransient(fmt.Errorf("failed to insert server: %w", err))
+	}
+
+	return server, nil
+}
+
+// UpdateServer updates an existing server record with new server details
+func (db *SQLite) UpdateServer(ctx context.Context, id string, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if server.Meta == nil || server.Meta.Official == nil || server.Meta.Official.ID != id {
+		return nil, fmt.Errorf("%w: io.modelcontextprotocol.registry/official.id must match path id (%s)", ErrInvalidInput, id)
+	}
+	// A server's tenant never changes, and is out of reach of other tenants
+	if uuid.Validate(id) != nil || !tenancy.Allows(ctx, tenancy.Of(server)) {
+		return nil, ErrNotFound
+	}
+
+	valueJSON, err := json.Marshal(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal updated server: %w", err)
+	}
+	keywords, err := searchKeywordsJSON(server)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := db.conn.ExecContext(ctx, `
+		UPDATE servers
+		SET value = ?, updated_at = ?, search_keywords = ?
+		WHERE id = ? AND tenant = ?
+	`, string(valueJSON), sqliteTime(server.LastModified()), keywords, id, tenancy.Of(server))
+	if err != nil {
+		return nil, sqliteTransient(fmt.Errorf("failed to update server: %w", err))
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return server, nil
+}
+
+// CreateAuthChallenge stores a new authentication challenge
+func (db *SQLite) CreateAuthChallenge(ctx context.Context, challenge *AuthChallenge) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO auth_challenges (nonce, domain, issued_at, expires_at)
+		VALUES (?, ?, ?, ?)
+	`, challenge.Nonce, challenge.Domain, sqliteTime(challenge.IssuedAt), sqliteTime(challenge.ExpiresAt))
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return sqliteTransient(fmt.Errorf("failed to insert auth challenge: %w", err))
+	}
+
+	return nil
+}
+
+// ConsumeAuthChallenge retrieves and deletes a challenge by nonce, so it can only be used once
+func (db *SQLite) ConsumeAuthChallenge(ctx context.Context, nonce string) (*AuthChallenge, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// DELETE ... RETURNING makes retrieval and invalidation a single atomic step
+	var challenge AuthChallenge
+	var issuedAt, expiresAt int64
+	err := db.conn.QueryRowContext(ctx, `
+		DELETE FROM auth_challenges
+		WHERE nonce = ?
+		RETURNING nonce, domain, issued_at, expires_at
+	`, nonce).Scan(&challenge.Nonce, &challenge.Domain, &issuedAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, sqliteTransient(fmt.Errorf("failed to consume auth challenge: %w", err))
+	}
+	challenge.IssuedAt, challenge.ExpiresAt = fromSQLiteTime(issuedAt), fromSQLiteTime(expiresAt)
+
+	return &challenge, nil
+}
+
+// PurgeExpiredAuthChallenges deletes challenges that expired before now
+func (db *SQLite) PurgeExpiredAuthChallenges(ctx context.Context, now time.Time) (int64, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	result, err := db.conn.ExecContext(ctx, `DELETE FROM auth_challenges WHERE expires_at < ?`, sqliteTime(now))
+	if err != nil {
+		return 0, sqliteTransient(fmt.Errorf("failed to purge expired auth challenges: %w", err))
+	}
+	return result.RowsAffected()
+}
+
+// CreateNamespaceNotification stores a publish notification registration
+func (db *SQLite) CreateNamespaceNotification(ctx context.Context, notification *NamespaceNotification) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	tenant, _ := tenancy.FromContext(ctx)
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO namespace_notifications (id, namespace, webhook_url, email, created_by, created_at, tenant)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)
+	`, notification.ID, notification.Namespace, notification.WebhookURL, notification.Email,
+		notification.CreatedBy, sqliteTime(notification.CreatedAt), tenant)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return sqliteTransient(fmt.Errorf("failed to insert namespace notification: %w", err))
+	}
+
+	return nil
+}
+
+// ListNamespaceNotifications returns the publish notification registrations for a namespace, oldest first
+func (db *SQLite) ListNamespaceNotifications(ctx context.Context, namespace string) ([]*NamespaceNotification, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT id, namespace, COALESCE(webhook_url, ''), COALESCE(email, ''), created_by, tenant, created_at
+		FROM namespace_notifications
+		WHERE namespace = ?
+	`
+	args := []any{namespace}
+	if condition, tenantArgs := sqliteTenantScope(ctx); condition != "" {
+		query += " AND " + condition
+		args = append(args, tenantArgs...)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, sqliteTransient(fmt.Errorf("failed to query namespace notifications: %w", err))
+	}
+	defer rows.Close()
+
+	var notifications []*NamespaceNotification
+	for rows.Next() {
+		var notification NamespaceNotification
+		var createdAt int64
+		if err := rows.Scan(&notification.ID, &notification.Namespace, &notification.WebhookURL,
+			&notification.Email, &notification.CreatedBy, &notification.Tenant, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan namespace notification: %w", err)
+		}
+		notification.CreatedAt = fromSQLiteTime(createdAt)
+		notifications = append(notifications, &notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating namespace notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// DeleteNamespaceNotification removes a publish notification registration by ID, with its undelivered events
+func (db *SQLite) DeleteNamespaceNotification(ctx context.Context, id string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if uuid.Validate(id) != nil {
+		return ErrNotFound
+	}
+
+	query := `DELETE FROM namespace_notifications WHERE id = ?`
+	args := []any{id}
+	if condition, tenantArgs := sqliteTenantScope(ctx); condition != "" {
+		query += " AND " + condition
+		args = append(args, tenantArgs...)
+	}
+	result, err := db.conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return sqliteTransient(fmt.Errorf("failed to delete namespace notification: %w", err))
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateOutboxEvent stores a notification to deliver
+func (db *SQLite) CreateOutboxEvent(ctx context.Context, event *OutboxEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO outbox_events (id, registration_id, webhook_url, email, event, payload, created_at, next_attempt_at)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?, ?)
+	`, event.ID, event.RegistrationID, event.WebhookURL, event.Email, event.Event, event.Payload,
+		sqliteTime(event.CreatedAt), sqliteTime(event.NextAttemptAt))
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		if sqliteCode(err) == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY {
+			return fmt.Errorf("%w: unknown notification registration %s", ErrInvalidInput, event.RegistrationID)
+		}
+		return sqliteTransient(fmt.Errorf("failed to insert outbox event: %w", err))
+	}
+
+	return nil
+}
+
+// ClaimOutboxEvents returns up to limit uncompleted events due at now, leasing them until now+lease
+func (db *SQLite) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxEvent, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// A single statement holds the write lock throughout, so no other dispatcher claims the same events
+	rows, err := db.conn.QueryContext(ctx, `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE completed_at IS NULL AND next_attempt_at <= ?
+			ORDER BY created_at, id
+			LIMIT ?
+		)
+		RETURNING id, registration_id, COALESCE(webhook_url, ''), COALESCE(email, ''), event, payload,
+			created_at, attempts, next_attempt_at, last_error
+	`, sqliteTime(now.Add(lease)), sqliteTime(now), limit)
+	if err != nil {
+		return nil, sqliteTransient(fmt.Errorf("failed to claim outbox events: %w", err))
+	}
+	defer rows.Close()
+
+	var events []*OutboxEvent
+	for rows.Next() {
+		var event OutboxEvent
+		var createdAt, nextAttemptAt int64
+		if err := rows.Scan(&event.ID, &event.RegistrationID, &event.WebhookURL, &event.Email, &event.Event,
+			&event.Payload, &createdAt, &event.Attempts, &nextAttemptAt, &event.LastError); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		event.CreatedAt, event.NextAttemptAt = fromSQLiteTime(createdAt), fromSQLiteTime(nextAttemptAt)
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, sqliteTransient(fmt.Errorf("error iterating outbox events: %w", err))
+	}
+
+	// RETURNING does not follow the subquery's order
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.Before(events[j].CreatedAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// RetryOutboxEvent records a failed delivery attempt, making the event due again at nextAttemptAt
+func (db *SQLite) RetryOutboxEvent(ctx context.Context, id string, nextAttemptAt time.Time, lastError string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	result, err := db.conn.ExecContext(ctx, `
+		UPDATE outbox_events SET next_attempt_at = ?, last_error = ?
+		WHERE id = ? AND completed_at IS NULL
+	`, sqliteTime(nextAttemptAt), lastError, id)
+	if err != nil {
+		return sqliteTransient(fmt.Errorf("failed to reschedule outbox event: %w", err))
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CompleteOutboxEvent marks an event delivered or abandoned
+func (db *SQLite) CompleteOutboxEvent(ctx context.Context, id string, completedAt time.Time, lastError string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	result, err := db.conn.ExecContext(ctx, `
+		UPDATE outbox_events SET completed_at = ?, last_error = ?
+		WHERE id = ? AND completed_at IS NULL
+	`, sqliteTime(completedAt), lastError, id)
+	if err != nil {
+		return sqliteTransient(fmt.Errorf("failed to complete outbox event: %w", err))
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// PurgeCompletedOutboxEvents deletes events completed more than outboxRetention before now
+func (db *SQLite) PurgeCompletedOutboxEvents(ctx context.Context, now time.Time) (int64, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	result, err := db.conn.ExecContext(ctx, `DELETE FROM outbox_events WHERE completed_at < ?`, sqliteTime(now.Add(-outboxRetention)))
+	if err != nil {
+		return 0, sqliteTransient(fmt.Errorf("failed to purge completed outbox events: %w", err))
+	}
+	return result.RowsAffected()
+}
+
+// ListNamespaceReservations returns every namespace reservation, ordered by namespace
+func (db *SQLite) ListNamespaceReservations(ctx context.Context) ([]*NamespaceReservation, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT namespace, allowed_subjects, reason, created_by, created_at
+		FROM namespace_reservations
+		ORDER BY namespace
+	`)
+	if err != nil {
+		return nil, sqliteTransient(fmt.Errorf("failed to query namespace reservations: %w", err))
+	}
+	defer rows.Close()
+
+	var reservations []*NamespaceReservation
+	for rows.Next() {
+		var reservation NamespaceReservation
+		var allowedSubjects string
+		var createdAt int64
+		if err := rows.Scan(&reservation.Namespace, &allowedSubjects, &reservation.Reason,
+			&reservation.CreatedBy, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan namespace reservation: %w", err)
+		}
+		if err := json.Unmarshal([]byte(allowedSubjects), &reservation.AllowedSubjects); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal allowed subjects: %w", err)
+		}
+		reservation.CreatedAt = fromSQLiteTime(createdAt)
+		reservations = append(reservations, &reservation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating namespace reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// PutNamespaceReservation stores a namespace reservation, replacing any for the same namespace
+func (db *SQLite) PutNamespaceReservation(ctx context.Context, reservation *NamespaceReservation) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	allowedSubjects := reservation.AllowedSubjects
+	if allowedSubjects == nil {
+		allowedSubjects = []string{}
+	}
+	allowedSubjectsJSON, err := json.Marshal(allowedSubjects)
+	if err != nil {
+		return fmt.Errorf("failed to marshal allowed subjects: %w", err)
+	}
+
+	_, err = db.conn.ExecContext(ctx, `
+		INSERT INTO namespace_reservations (namespace, allowed_subjects, reason, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (namespace) DO UPDATE SET
+			allowed_subjects = excluded.allowed_subjects,
+			reason = excluded.reason,
+			created_by = excluded.created_by,
+			created_at = excluded.created_at
+	`, reservation.Namespace, string(allowedSubjectsJSON), reservation.Reason, reservation.CreatedBy, sqliteTime(reservation.CreatedAt))
+	if err != nil {
+		return sqliteTransient(fmt.Errorf("failed to store namespace reservation: %w", err))
+	}
+
+	return nil
+}
+
+// DeleteNamespaceReservation removes the reservation of a namespace
+func (db *SQLite) DeleteNamespaceReservation(ctx context.Context, namespace string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	result, err := db.conn.ExecContext(ctx, `DELETE FROM namespace_reservations WHERE namespace = ?`, namespace)
+	if err != nil {
+		return sqliteTransient(fmt.Errorf("failed to delete namespace reservation: %w", err))
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RecordNamespaceVerification stores a namespace verification, replacing any earlier one for the namespace
+func (db *SQLite) RecordNamespaceVerification(ctx context.Context, verification *NamespaceVerification) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	tenant, _ := tenancy.FromContext(ctx)
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO namespace_verifications (tenant, namespace, method, subject, verified_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (tenant, namespace) DO UPDATE SET
+			method = excluded.method,
+			subject = excluded.subject,
+			verified_at = excluded.verified_at
+	`, tenant, verification.Namespace, verification.Method, verification.Subject, sqliteTime(verification.VerifiedAt))
+	if err != nil {
+		return sqliteTransient(fmt.Errorf("failed to store namespace verification: %w", err))
+	}
+
+	return nil
+}
+
+// GetNamespaceVerification returns the latest verification of a namespace, or ErrNotFound
+func (db *SQLite) GetNamespaceVerification(ctx context.Context, namespace string) (*NamespaceVerification, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	tenant, _ := tenancy.FromContext(ctx)
+	var verification NamespaceVerification
+	var verifiedAt int64
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT tenant, namespace, method, subject, verified_at
+		FROM namespace_verifications
+		WHERE tenant = ? AND namespace = ?
+	`, tenant, namespace).Scan(&verification.Tenant, &verification.Namespace, &verification.Method, &verification.Subject, &verifiedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, sqliteTransient(fmt.Errorf("failed to get namespace verification: %w", err))
+	}
+	verification.VerifiedAt = fromSQLiteTime(verifiedAt)
+
+	return &verification, nil
+}
+
+// PutNamespaceSettings stores the settings of a namespace, replacing any earlier ones
+func (db *SQLite) PutNamespaceSettings(ctx context.Context, settings *NamespaceSettings) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	categories := settings.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	categoriesJSON, err := json.Marshal(categories)
+	if err != nil {
+		return fmt.Errorf("failed to marshal categories: %w", err)
+	}
+
+	tenant, _ := tenancy.FromContext(ctx)
+	_, err = db.conn.ExecContext(ctx, `
+		INSERT INTO namespace_settings (tenant, namespace, license, categories, website_url, contact, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (tenant, namespace) DO UPDATE SET
+			license = excluded.license,
+			categories = excluded.categories,
+			website_url = excluded.website_url,
+			contact = excluded.contact,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at
+	`, tenant, settings.Namespace, settings.License, string(categoriesJSON), settings.WebsiteURL, settings.Contact,
+		settings.UpdatedBy, sqliteTime(settings.UpdatedAt))
+	if err != nil {
+		return sqliteTransient(fmt.Errorf("failed to store namespace settings: %w", err))
+	}
+
+	return nil
+}
+
+// GetNamespaceSettings returns the settings of a namespace, or ErrNotFound
+func (db *SQLite) GetNamespaceSettings(ctx context.Context, namespace string) (*NamespaceSettings, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	tenant, _ := tenancy.FromContext(ctx)
+	var settings NamespaceSettings
+	var categories string
+	var updatedAt int64
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT tenant, namespace, license, categories, website_url, contact, updated_by, updated_at
+		FROM namespace_settings
+		WHERE tenant = ? AND namespace = ?
+	`, tenant, namespace).Scan(&settings.Tenant, &settings.Namespace, &settings.License, &categories, &settings.WebsiteURL,
+		&settings.Contact, &settings.UpdatedBy, &updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, sqliteTransient(fmt.Errorf("failed to get namespace settings: %w", err))
+	}
+	if err := json.Unmarshal([]byte(categories), &settings.Categories); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal categories: %w", err)
+	}
+	settings.UpdatedAt = fromSQLiteTime(updatedAt)
+
+	return &settings, nil
+}
+
+// AddServerFetchCounts adds each count to the fetch count of its server on its day, in one transaction
+func (db *SQLite) AddServerFetchCounts(ctx context.Context, counts []ServerFetchCount) error {
+	if len(counts) == 0 {
+		return ctx.Err()
+	}
+
+	return db.InTransaction(ctx, func(ctx context.Context, tx Database) error {
+		conn := tx.(*SQLite).conn
+		for _, count := range counts {
+			_, err := conn.ExecContext(ctx, `
+				INSERT INTO server_fetch_counts (tenant, namespace, server_name, day, count)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (tenant, namespace, server_name, day) DO UPDATE SET
+					count = count + excluded.count
+			`, count.Tenant, count.Namespace, count.ServerName, sqliteTime(fetchDay(count.Day)), count.Count)
+			if err != nil {
+				return sqliteTransient(fmt.Errorf("failed to add server fetch count: %w", err))
+			}
+		}
+		return nil
+	})
+}
+
+// ListServerFetchCounts returns the fetch counts of the servers in a namespace on the days from from to to
+func (db *SQLite) ListServerFetchCounts(ctx context.Context, namespace string, from, to time.Time) ([]ServerFetchCount, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	tenant, _ := tenancy.FromContext(ctx)
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT tenant, namespace, server_name, day, count
+		FROM server_fetch_counts
+		WHERE tenant = ? AND namespace = ? AND day BETWEEN ? AND ?
+		ORDER BY day, server_name
+	`, tenant, namespace, sqliteTime(fetchDay(from)), sqliteTime(fetchDay(to)))
+	if err != nil {
+		return nil, sqliteTransient(fmt.Errorf("failed to query server fetch counts: %w", err))
+	}
+	defer rows.Close()
+
+	var counts []ServerFetchCount
+	for rows.Next() {
+		var count ServerFetchCount
+		var day int64
+		if err := rows.Scan(&count.Tenant, &count.Namespace, &count.ServerName, &day, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan server fetch count: %w", err)
+		}
+		count.Day = fromSQLiteTime(day)
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating server fetch counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CreateServerReport stores a report about a server version
+func (db *SQLite) CreateServerReport(ctx context.Context, report *ServerReport) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO server_reports (id, server_id, tenant, namespace, server_name, version, category, text, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, report.ID, report.ServerID, report.Tenant, report.Namespace, report.ServerName, report.Version,
+		report.Category, report.Text, sqliteTime(report.CreatedAt))
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return sqliteTransient(fmt.Errorf("failed to insert server report: %w", err))
+	}
+
+	return nil
+}
+
+// ListServerReportCounts counts the reports matching filter by server version and category
+func (db *SQLite) ListServerReportCounts(ctx context.Context, filter ServerReportFilter) ([]ServerReportCount, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var conditions []string
+	var args []any
+	if tenant, ok := tenancy.FromContext(ctx); ok {
+		conditions = append(conditions, "tenant = ?")
+		args = append(args, tenant)
+	}
+	if filter.Namespace != "" {
+		conditions = append(conditions, "namespace = ?")
+		args = append(args, filter.Namespace)
+	}
+	if filter.ServerID != "" {
+		conditions = append(conditions, "server_id = ?")
+		args = append(args, filter.ServerID)
+	}
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT server_id, server_name, version, category, COUNT(*), MAX(created_at)
+		FROM server_reports`+where(conditions)+`
+		GROUP BY server_id, server_name, version, category
+		ORDER BY server_name, server_id, category
+	`, args...)
+	if err != nil {
+		return nil, sqliteTransient(fmt.Errorf("failed to query server report counts: %w", err))
+	}
+	defer rows.Close()
+
+	counts := []ServerReportCount{}
+	for rows.Next() {
+		var count ServerReportCount
+		var lastReportedAt int64
+		if err := rows.Scan(&count.ServerID, &count.ServerName, &count.Version, &count.Category, &count.Count, &lastReportedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan server report count: %w", err)
+		}
+		count.LastReportedAt = fromSQLiteTime(lastReportedAt)
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating server report counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ListServerReports returns the reports about a server version, newest first
+func (db *SQLite) ListServerReports(ctx context.Context, serverID string) ([]*ServerReport, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	conditions := []string{"server_id = ?"}
+	args := []any{serverID}
+	if tenant, ok := tenancy.FromContext(ctx); ok {
+		conditions = append(conditions, "tenant = ?")
+		args = append(args, tenant)
+	}
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT id, server_id, tenant, namespace, server_name, version, category, text, created_at
+		FROM server_reports`+where(conditions)+`
+		ORDER BY created_at DESC, id DESC
+	`, args...)
+	if err != nil {
+		return nil, sqliteTransient(fmt.Errorf("failed to query server reports: %w", err))
+	}
+	defer rows.Close()
+
+	var reports []*ServerReport
+	for rows.Next() {
+		var report ServerReport
+		var createdAt int64
+		if err := rows.Scan(&report.ID, &report.ServerID, &report.Tenant, &report.Namespace, &report.ServerName,
+			&report.Version, &report.Category, &report.Text, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan server report: %w", err)
+		}
+		report.CreatedAt = fromSQLiteTime(createdAt)
+		reports = append(reports, &report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating server reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// BackfillSearchKeywords stores the search keywords of up to limit server versions stored
+// without them, leaving updated_at alone since the documents do not change
+func (db *SQLite) BackfillSearchKeywords(ctx context.Context, limit int) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	rows, err := db.conn.QueryContext(ctx, `SELECT id, value FROM servers WHERE search_keywords IS NULL ORDER BY id LIMIT ?`, limit)
+	if err != nil {
+		return 0, sqliteTransient(fmt.Errorf("failed to query servers without search keywords: %w", err))
+	}
+	type pending struct {
+		id       string
+		keywords string
+	}
+	var batch []pending
+	for rows.Next() {
+		var id string
+		var valueJSON []byte
+		if err := rows.Scan(&id, &valueJSON); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to scan server row: %w", err)
+		}
+		var serverJSON apiv0.ServerJSON
+		if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to unmarshal server JSON: %w", err)
+		}
+		keywords, err := searchKeywordsJSON(&serverJSON)
+		if err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		batch = append(batch, pending{id: id, keywords: keywords})
+	}
+	// Close the rows before writing: inside a transaction they share its connection
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return 0, fmt.Errorf("error iterating servers without search keywords: %w", err)
+	}
+
+	for _, server := range batch {
+		if _, err := db.conn.ExecContext(ctx, `UPDATE servers SET search_keywords = ? WHERE id = ?`, server.keywords, server.id); err != nil {
+			return 0, sqliteTransient(fmt.Errorf("failed to store search keywords: %w", err))
+		}
+	}
+	return len(batch), nil
+}
+
+// InTransaction runs fn inside a database transaction, rolling back if fn fails or ctx is
+// cancelled before the commit. Transactions take the write lock when they begin, so they run
+// one at a time, while reads outside them go on.
+func (db *SQLite) InTransaction(ctx context.Context, fn func(ctx context.Context, tx Database) error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// Nested calls join the enclosing transaction
+	if db.inTx {
+		return fn(ctx, db)
+	}
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqliteTransient(fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	// This is a no-op after a successful commit
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(ctx, &SQLite{db: db.db, conn: tx, inTx: true}); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sqliteTransient(fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	return nil
+}
+
+// Close closes the database file
+func (db *SQLite) Close() error {
+	return db.db.Close()
+}
@@ -0,0 +1,73 @@
+-- SQLite schema for MCP Registry, the embedded counterpart of the PostgreSQL schema as of its
+-- migration 014. Server documents and string arrays are JSON text, and times are Unix
+-- microseconds, the precision PostgreSQL keeps. Later changes to the PostgreSQL schema need a
+-- migration here too.
+
+CREATE TABLE servers (
+    id TEXT PRIMARY KEY, -- registry metadata ID
+    value TEXT NOT NULL, -- complete ServerJSON
+    updated_at INTEGER NOT NULL,
+    tenant TEXT NOT NULL DEFAULT '',
+    search_keywords TEXT -- JSON array, NULL for versions stored before keywords were
+);
+
+CREATE INDEX idx_servers_updated_at ON servers (updated_at, id);
+CREATE INDEX idx_servers_tenant_id ON servers (tenant, id);
+CREATE INDEX idx_servers_tenant_updated_at ON servers (tenant, updated_at, id);
+CREATE INDEX idx_servers_name ON servers (tenant, value ->> '$.name');
+
+CREATE TABLE auth_challenges (
+    nonce TEXT PRIMARY KEY,
+    domain TEXT NOT NULL,
+    issued_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_auth_challenges_expires_at ON auth_challenges (expires_at);
+
+CREATE TABLE namespace_notifications (
+    id TEXT PRIMARY KEY,
+    namespace TEXT NOT NULL,
+    webhook_url TEXT,
+    email TEXT,
+    created_by TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    tenant TEXT NOT NULL DEFAULT '',
+    CHECK ((webhook_url IS NULL) <> (email IS NULL))
+);
+
+CREATE INDEX idx_namespace_notifications_tenant_namespace ON namespace_notifications (tenant, namespace);
+
+CREATE TABLE outbox_events (
+    id TEXT PRIMARY KEY,
+    registration_id TEXT NOT NULL REFERENCES namespace_notifications (id) ON DELETE CASCADE,
+    webhook_url TEXT,
+    email TEXT,
+    event TEXT NOT NULL,
+    payload BLOB NOT NULL,
+    created_at INTEGER NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at INTEGER NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    completed_at INTEGER
+);
+
+CREATE INDEX idx_outbox_events_due ON outbox_events (next_attempt_at) WHERE completed_at IS NULL;
+CREATE INDEX idx_outbox_events_registration_id ON outbox_events (registration_id);
+
+CREATE TABLE namespace_reservations (
+    namespace TEXT PRIMARY KEY,
+    allowed_subjects TEXT NOT NULL DEFAULT '[]', -- JSON array
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+CREATE TABLE namespace_verifications (
+    tenant TEXT NOT NULL DEFAULT '',
+    namespace TEXT NOT NULL,
+    method TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    verified_at INTEGER NOT NULL,
+    PRIMARY KEY (tenant, namespace)
+);
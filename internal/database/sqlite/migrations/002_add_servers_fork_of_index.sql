@@ -0,0 +1,3 @@
+-- Index the server each version declares itself a fork of, as PostgreSQL migration 015 does.
+
+CREATE INDEX idx_servers_fork_of ON servers (value ->> '$.forkOf');
@@ -0,0 +1,13 @@
+-- Count fetches of each server's details per day, as PostgreSQL migration 016 does. day is
+-- midnight UTC in Unix microseconds, like the other timestamps.
+
+CREATE TABLE server_fetch_counts (
+    tenant TEXT NOT NULL DEFAULT '',
+    namespace TEXT NOT NULL,
+    server_name TEXT NOT NULL,
+    day INTEGER NOT NULL,
+    count INTEGER NOT NULL,
+    PRIMARY KEY (tenant, namespace, server_name, day)
+);
+
+CREATE INDEX idx_server_fetch_counts_namespace_day ON server_fetch_counts (tenant, namespace, day);
@@ -0,0 +1,14 @@
+-- Let namespace owners set defaults for the optional server.json fields of versions published
+-- under their namespace, as PostgreSQL migration 017 does
+
+CREATE TABLE namespace_settings (
+    tenant TEXT NOT NULL DEFAULT '',
+    namespace TEXT NOT NULL,
+    license TEXT NOT NULL DEFAULT '',
+    categories TEXT NOT NULL DEFAULT '[]', -- JSON array
+    website_url TEXT NOT NULL DEFAULT '',
+    contact TEXT NOT NULL DEFAULT '',
+    updated_by TEXT NOT NULL,
+    updated_at INTEGER NOT NULL,
+    PRIMARY KEY (tenant, namespace)
+);
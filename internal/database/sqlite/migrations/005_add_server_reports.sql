@@ -0,0 +1,16 @@
+-- Let anyone report a server version as broken or abusive, as PostgreSQL migration 018 does
+
+CREATE TABLE server_reports (
+    id TEXT PRIMARY KEY,
+    server_id TEXT NOT NULL,
+    tenant TEXT NOT NULL DEFAULT '',
+    namespace TEXT NOT NULL,
+    server_name TEXT NOT NULL,
+    version TEXT NOT NULL,
+    category TEXT NOT NULL,
+    text TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_server_reports_server_id ON server_reports (server_id, category);
+CREATE INDEX idx_server_reports_namespace ON server_reports (tenant, namespace);
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// transientCodes are the PostgreSQL error codes, outside the connection exception class 08,
+// for failures that a retry of the same statement can get past
+var transientCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown, as during a failover
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now, while the server is starting up
+}
+
+var (
+	// readAttempts bounds how many times an idempotent read is tried when it fails transiently
+	readAttempts = 3
+	// readRetryDelay is the base of the jittered exponential backoff between read attempts
+	readRetryDelay = 50 * time.Millisecond
+)
+
+// isTransient reports whether err is a database failure that may succeed if retried: a lost
+// or refused connection, a failover, a serialization failure or an exhausted connection limit.
+// Cancelled requests and errors in the statement itself are permanent.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || transientCodes[pgErr.Code]
+	}
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// transient marks err with ErrTransient if it is a transient failure, and returns it unchanged otherwise
+func transient(err error) error {
+	if !isTransient(err) || errors.Is(err, ErrTransient) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrTransient, err)
+}
+
+// retryRead runs an idempotent read, retrying it with jittered exponential backoff while it
+// fails transiently. read must start over on each call, since a connection can reset partway
+// through a result set. Inside a transaction a failed statement aborts the whole transaction,
+// so the read is not retried and only the caller can start again.
+func (db *PostgreSQL) retryRead(ctx context.Context, read func() error) error {
+	attempts := readAttempts
+	if db.inTx {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := range attempts {
+		if attempt > 0 {
+			// Full jitter keeps replicas that failed together from retrying in lockstep
+			delay := rand.N(readRetryDelay << attempt)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		err = read()
+		if !isTransient(err) {
+			return err
+		}
+	}
+	return transient(err)
+}
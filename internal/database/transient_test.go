@@ -0,0 +1,260 @@
+//nolint:testpackage
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// stubQuerier answers every query with the same rows, standing in for a PostgreSQL connection
+type stubQuerier struct {
+	rows [][]any
+}
+
+func (q *stubQuerier) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (q *stubQuerier) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return &stubRows{rows: q.rows, index: -1}, nil
+}
+
+func (q *stubQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, _ := q.Query(ctx, sql, args...)
+	return stubRow{rows: rows}
+}
+
+// stubRows iterates over fixed rows. When resetAfter is set, the connection "resets" after
+// that many rows: iteration stops and Err reports err, as pgx does for a connection lost mid-query.
+type stubRows struct {
+	pgx.Rows
+	rows       [][]any
+	index      int
+	resetAfter int
+	err        error
+}
+
+func (r *stubRows) Next() bool {
+	if r.err != nil && r.index+1 >= r.resetAfter {
+		return false
+	}
+	r.index++
+	return r.index < len(r.rows)
+}
+
+func (r *stubRows) Scan(dest ...any) error {
+	for i, value := range r.rows[r.index] {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+func (r *stubRows) Err() error {
+	return r.err
+}
+
+func (r *stubRows) Close() {}
+
+type stubRow struct {
+	rows pgx.Rows
+}
+
+func (r stubRow) Scan(dest ...any) error {
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+// faultyQuerier wraps a connection and fails its first failures calls with err. Queries fail
+// partway through their results, after the first row, like a connection reset mid-query.
+type faultyQuerier struct {
+	querier
+	failures int
+	err      error
+	calls    int
+}
+
+func (q *faultyQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	q.calls++
+	if q.calls <= q.failures {
+		return pgconn.CommandTag{}, q.err
+	}
+	return q.querier.Exec(ctx, sql, args...)
+}
+
+func (q *faultyQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	q.calls++
+	rows, err := q.querier.Query(ctx, sql, args...)
+	if err != nil || q.calls > q.failures {
+		return rows, err
+	}
+	stub, ok := rows.(*stubRows)
+	if !ok {
+		return nil, q.err
+	}
+	stub.resetAfter, stub.err = 1, q.err
+	return stub, nil
+}
+
+func (q *faultyQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	q.calls++
+	if q.calls <= q.failures {
+		return stubRow{rows: &stubRows{err: q.err, index: -1}}
+	}
+	return q.querier.QueryRow(ctx, sql, args...)
+}
+
+func serverRows(t *testing.T, versions ...string) [][]any {
+	t.Helper()
+	rows := [][]any{}
+	for i, version := range versions {
+		value, err := json.Marshal(apiv0.ServerJSON{
+			Name:    "io.github.example/server",
+			Version: version,
+			Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{
+				ID: fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1),
+			}},
+		})
+		require.NoError(t, err)
+		rows = append(rows, []any{value})
+	}
+	return rows
+}
+
+func TestPostgreSQL_RetriesTransientReads(t *testing.T) {
+	defer func(delay time.Duration) { readRetryDelay = delay }(readRetryDelay)
+	readRetryDelay = time.Millisecond
+
+	ctx := context.Background()
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	const id = "00000000-0000-0000-0000-000000000001"
+
+	t.Run("a reset mid-query is retried from the start", func(t *testing.T) {
+		conn := &faultyQuerier{querier: &stubQuerier{rows: serverRows(t, "1.0.0", "1.1.0")}, failures: 2, err: reset}
+		db := &PostgreSQL{conn: conn, counts: newCountCache()}
+
+		servers, _, err := db.List(ctx, nil, "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, 3, conn.calls)
+		require.Len(t, servers, 2, "rows read before the reset are not duplicated")
+		assert.Equal(t, "1.0.0", servers[0].Version)
+		assert.Equal(t, "1.1.0", servers[1].Version)
+	})
+
+	t.Run("single-row reads are retried", func(t *testing.T) {
+		conn := &faultyQuerier{querier: &stubQuerier{rows: serverRows(t, "1.0.0")}, failures: 1, err: &pgconn.PgError{Code: "57P01"}}
+		db := &PostgreSQL{conn: conn, counts: newCountCache()}
+
+		server, err := db.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", server.Version)
+		assert.Equal(t, 2, conn.calls)
+	})
+
+	t.Run("retries are bounded", func(t *testing.T) {
+		conn := &faultyQuerier{querier: &stubQuerier{rows: serverRows(t, "1.0.0")}, failures: 100, err: reset}
+		db := &PostgreSQL{conn: conn, counts: newCountCache()}
+
+		_, err := db.GetByID(ctx, id)
+		require.ErrorIs(t, err, ErrTransient)
+		assert.ErrorIs(t, err, syscall.ECONNRESET)
+		assert.Equal(t, readAttempts, conn.calls)
+	})
+
+	t.Run("permanent errors are not retried", func(t *testing.T) {
+		conn := &faultyQuerier{querier: &stubQuerier{}, failures: 100, err: &pgconn.PgError{Code: "42601"}}
+		db := &PostgreSQL{conn: conn, counts: newCountCache()}
+
+		_, _, err := db.List(ctx, nil, "", 10)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrTransient)
+		assert.Equal(t, 1, conn.calls)
+	})
+
+	t.Run("reads in a transaction are left to the caller", func(t *testing.T) {
+		conn := &faultyQuerier{querier: &stubQuerier{rows: serverRows(t, "1.0.0")}, failures: 1, err: reset}
+		db := &PostgreSQL{conn: conn, inTx: true, counts: newCountCache()}
+
+		_, err := db.GetByID(ctx, id)
+		require.ErrorIs(t, err, ErrTransient)
+		assert.Equal(t, 1, conn.calls)
+	})
+
+	t.Run("writes are reported as transient without retrying", func(t *testing.T) {
+		conn := &faultyQuerier{querier: &stubQuerier{}, failures: 1, err: reset}
+		db := &PostgreSQL{conn: conn, counts: newCountCache()}
+
+		server := &apiv0.ServerJSON{Name: "io.github.example/server", Version: "1.0.0", Meta: &apiv0.ServerMeta{
+			Official: &apiv0.RegistryExtensions{ID: id},
+		}}
+		_, err := db.UpdateServer(ctx, id, server)
+		require.ErrorIs(t, err, ErrTransient)
+		assert.Equal(t, 1, conn.calls)
+
+		_, err = db.UpdateServer(ctx, id, server)
+		require.NoError(t, err)
+	})
+
+	t.Run("cancellation stops retrying", func(t *testing.T) {
+		readRetryDelay = time.Hour
+		defer func() { readRetryDelay = time.Millisecond }()
+
+		conn := &faultyQuerier{querier: &stubQuerier{rows: serverRows(t, "1.0.0")}, failures: 100, err: reset}
+		db := &PostgreSQL{conn: conn, counts: newCountCache()}
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		_, err := db.GetByID(ctx, id)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, conn.calls)
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"unexpected EOF", fmt.Errorf("failed to query servers: %w", io.ErrUnexpectedEOF), true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"too many connections", &pgconn.PgError{Code: "53300"}, true},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, true},
+		{"unique violation", &pgconn.PgError{Code: uniqueViolationCode}, false},
+		{"syntax error", &pgconn.PgError{Code: "42601"}, false},
+		{"no rows", pgx.ErrNoRows, false},
+		{"cancelled", context.Canceled, false},
+		{"deadline", fmt.Errorf("failed to query servers: %w", context.DeadlineExceeded), false},
+		{"not found", ErrNotFound, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isTransient(tt.err))
+		})
+	}
+}
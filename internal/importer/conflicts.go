@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ConflictPolicy decides which record is imported when a seed lists the same server
+// name and version more than once with different contents
+type ConflictPolicy string
+
+const (
+	// ConflictAbort fails the import, before anything is written, if the seed has any conflicts
+	ConflictAbort ConflictPolicy = "abort"
+	// ConflictKeepFirst imports the first record for each name and version
+	ConflictKeepFirst ConflictPolicy = "keep-first"
+	// ConflictKeepLast imports the last record for each name and version
+	ConflictKeepLast ConflictPolicy = "keep-last"
+)
+
+// DuplicateConflict is a server name and version listed more than once with different contents
+type DuplicateConflict struct {
+	Name      string
+	Version   string
+	First     int      // record number of the first occurrence, counting from 1
+	Duplicate int      // record number of the later occurrence
+	Fields    []string // top-level fields whose values differ
+}
+
+// LatestConflict is a server with more than one version marked latest among the records to import
+type LatestConflict struct {
+	Name     string
+	Versions []string
+}
+
+// ConflictError is returned when ConflictAbort stops an import. It lists every conflict in the seed.
+type ConflictError struct {
+	Duplicates []DuplicateConflict
+	Latest     []LatestConflict
+}
+
+func (e *ConflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "seed data has %d conflicting duplicate(s) and %d server(s) with more than one latest version",
+		len(e.Duplicates), len(e.Latest))
+	for _, d := range e.Duplicates {
+		fmt.Fprintf(&b, "\n  %s", d)
+	}
+	for _, l := range e.Latest {
+		fmt.Fprintf(&b, "\n  %s", l)
+	}
+	return b.String()
+}
+
+func (d DuplicateConflict) String() string {
+	return fmt.Sprintf("%s %s: records %d and %d differ in %s", d.Name, d.Version, d.First, d.Duplicate, strings.Join(d.Fields, ", "))
+}
+
+func (l LatestConflict) String() string {
+	return fmt.Sprintf("%s: versions %s are all marked latest", l.Name, strings.Join(l.Versions, ", "))
+}
+
+// seedEntry is what the conflict scan remembers about one name and version
+type seedEntry struct {
+	first, last             int
+	firstLatest, lastLatest bool
+	version                 string
+	fields                  map[string]uint64 // hash of each top-level field of the first occurrence
+}
+
+// conflictScan finds duplicate names and versions in a seed without keeping the records themselves
+type conflictScan struct {
+	entries    map[string]*seedEntry // by name and version
+	names      map[string][]string   // keys of each server name, in seed order
+	duplicates []DuplicateConflict
+	identical  int
+}
+
+func newConflictScan() *conflictScan {
+	return &conflictScan{entries: make(map[string]*seedEntry), names: make(map[string][]string)}
+}
+
+func (c *conflictScan) add(record int, server *apiv0.ServerJSON) error {
+	fields, err := fieldHashes(server)
+	if err != nil {
+		return fmt.Errorf("failed to compare seed record %d: %w", record, err)
+	}
+	latest := isLatest(server)
+
+	key := server.Name + "\x00" + server.Version
+	entry, seen := c.entries[key]
+	if !seen {
+		c.entries[key] = &seedEntry{first: record, last: record, firstLatest: latest, lastLatest: latest, version: server.Version, fields: fields}
+		c.names[server.Name] = append(c.names[server.Name], key)
+		return nil
+	}
+
+	if differing := differingFields(entry.fields, fields); len(differing) > 0 {
+		c.duplicates = append(c.duplicates, DuplicateConflict{
+			Name:      server.Name,
+			Version:   server.Version,
+			First:     entry.first,
+			Duplicate: record,
+			Fields:    differing,
+		})
+	} else {
+		c.identical++
+	}
+	entry.last = record
+	entry.lastLatest = latest
+	return nil
+}
+
+// resolve checks the scanned seed under policy, returning a *ConflictError for ConflictAbort
+// if there are any conflicts and logging them otherwise. Duplicates with identical contents
+// are not conflicts.
+func (c *conflictScan) resolve(policy ConflictPolicy) error {
+	var latest []LatestConflict
+	for name, keys := range c.names {
+		var latestVersions []string
+		for _, key := range keys {
+			entry := c.entries[key]
+			if (policy == ConflictKeepLast && entry.lastLatest) || (policy != ConflictKeepLast && entry.firstLatest) {
+				latestVersions = append(latestVersions, entry.version)
+			}
+		}
+		if len(latestVersions) > 1 {
+			latest = append(latest, LatestConflict{Name: name, Versions: latestVersions})
+		}
+	}
+	sort.Slice(latest, func(i, j int) bool { return latest[i].Name < latest[j].Name })
+
+	if policy == ConflictAbort && (len(c.duplicates) > 0 || len(latest) > 0) {
+		return &ConflictError{Duplicates: c.duplicates, Latest: latest}
+	}
+	for _, d := range c.duplicates {
+		log.Printf("Warning: seed conflict, importing the %s record: %s", strings.TrimPrefix(string(policy), "keep-"), d)
+	}
+	for _, l := range latest {
+		log.Printf("Warning: seed conflict: %s", l)
+	}
+	if c.identical > 0 {
+		log.Printf("Import: skipping %d identical duplicate records", c.identical)
+	}
+	return nil
+}
+
+// keep reports whether a record is the one to import for its name and version
+func (c *conflictScan) keep(policy ConflictPolicy, record int, server *apiv0.ServerJSON) bool {
+	entry, ok := c.entries[server.Name+"\x00"+server.Version]
+	if !ok {
+		return true // the source changed between passes; let the database decide
+	}
+	if policy == ConflictKeepLast {
+		return record == entry.last
+	}
+	return record == entry.first
+}
+
+// isLatest reports whether a seed record is marked as its server's latest version
+func isLatest(server *apiv0.ServerJSON) bool {
+	return server.Meta != nil && server.Meta.Official != nil && server.Meta.Official.IsLatest
+}
+
+// fieldHashes hashes each top-level field of a server's JSON form, so records can be
+// compared field by field without being kept in memory
+func fieldHashes(server *apiv0.ServerJSON) (map[string]uint64, error) {
+	data, err := json.Marshal(server)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]uint64, len(fields))
+	for name, value := range fields {
+		h := fnv.New64a()
+		_, _ = h.Write(value)
+		hashes[name] = h.Sum64()
+	}
+	return hashes, nil
+}
+
+// differingFields lists the fields present in either record whose values differ, sorted
+func differingFields(a, b map[string]uint64) []string {
+	var differing []string
+	for name, hash := range a {
+		if other, ok := b[name]; !ok || other != hash {
+			differing = append(differing, name)
+		}
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			differing = append(differing, name)
+		}
+	}
+	sort.Strings(differing)
+	return differing
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// errLimitReached stops reading the seed once the filter's limit of servers has been imported
+var errLimitReached = errors.New("seed filter limit reached")
+
+// Filter selects the seed records to import, for loading a handful of known servers instead
+// of the whole seed. Patterns are globs matched against server names as by path.Match, so
+// io.github.acme/* matches every server of the io.github.acme namespace.
+type Filter struct {
+	// Only imports servers whose names match one of these patterns; empty imports every server
+	Only []string
+	// Exclude skips servers whose names match one of these patterns, even if they match Only
+	Exclude []string
+	// Limit stops the import after this many servers, without reading the rest of the seed; 0 imports them all
+	Limit int
+}
+
+// WithFilter imports only the seed records selected by filter
+func WithFilter(filter Filter) Option {
+	return func(s *Service) {
+		s.filter = filter
+	}
+}
+
+// Validate checks every pattern is a well-formed glob and the limit is not negative
+func (f Filter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Only...), f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid seed filter pattern %q: %w", pattern, err)
+		}
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("seed filter limit must not be negative, got %d", f.Limit)
+	}
+	return nil
+}
+
+func (f Filter) empty() bool {
+	return len(f.Only) == 0 && len(f.Exclude) == 0 && f.Limit == 0
+}
+
+// filterRun applies a filter to one read of the seed, counting the records each pattern matched
+type filterRun struct {
+	filter   Filter
+	only     []int // records matching each Only pattern
+	excluded []int // records skipped by each Exclude pattern
+	imported int
+}
+
+func newFilterRun(filter Filter) *filterRun {
+	return &filterRun{filter: filter, only: make([]int, len(filter.Only)), excluded: make([]int, len(filter.Exclude))}
+}
+
+// match reports whether the server named name is selected. It is checked before a record is
+// validated, so skipped records cost no more than decoding.
+func (r *filterRun) match(name string) bool {
+	matched := len(r.filter.Only) == 0
+	for i, pattern := range r.filter.Only {
+		if ok, _ := path.Match(pattern, name); ok {
+			r.only[i]++
+			matched = true
+		}
+	}
+	if !matched {
+		return false
+	}
+	for i, pattern := range r.filter.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			r.excluded[i]++
+			return false
+		}
+	}
+	return true
+}
+
+// limit wraps fn to return errLimitReached once it has been passed Limit records, so the seed
+// stops being read
+func (r *filterRun) limit(fn recordFunc) recordFunc {
+	if r.filter.Limit == 0 {
+		return fn
+	}
+	return func(record int, server *apiv0.ServerJSON) error {
+		if err := fn(record, server); err != nil {
+			return err
+		}
+		r.imported++
+		if r.imported >= r.filter.Limit {
+			return errLimitReached
+		}
+		return nil
+	}
+}
+
+// log reports how many records each part of the filter matched
+func (r *filterRun) log() {
+	for i, pattern := range r.filter.Only {
+		log.Printf("Seed filter: %d records match only pattern %q", r.only[i], pattern)
+	}
+	for i, pattern := range r.filter.Exclude {
+		log.Printf("Seed filter: %d records excluded by pattern %q", r.excluded[i], pattern)
+	}
+	if r.filter.Limit > 0 {
+		if r.imported >= r.filter.Limit {
+			log.Printf("Seed filter: stopped at the limit of %d servers without reading the rest of the seed", r.filter.Limit)
+		} else {
+			log.Printf("Seed filter: %d servers matched, under the limit of %d", r.imported, r.filter.Limit)
+		}
+	}
+}
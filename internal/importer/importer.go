@@ -1,8 +1,11 @@
 package importer
 
 import (
+	"bufio"
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -15,104 +18,324 @@ import (
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 )
 
+const (
+	// defaultBatchSize is how many servers are written per transaction
+	defaultBatchSize = 500
+	// progressInterval is how many records are processed between progress log lines
+	progressInterval = 10000
+)
+
 // Service handles importing seed data into the registry
 type Service struct {
-	db database.Database
+	db        database.Database
+	batchSize int
+	policy    ConflictPolicy
+	filter    Filter
+
+	// signaturePath and verificationKeys are set by WithSignatureVerification
+	signaturePath    string
+	verificationKeys []ed25519.PublicKey
 }
 
+// Option configures optional importer settings
+type Option func(*Service)
+
+// WithBatchSize sets how many servers are written per transaction
+func WithBatchSize(size int) Option {
+	return func(s *Service) {
+		if size > 0 {
+			s.batchSize = size
+		}
+	}
+}
+
+// WithConflictPolicy sets which record is imported when the seed lists a server name and version more than once
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(s *Service) {
+		if policy != "" {
+			s.policy = policy
+		}
+	}
+}
+
+// recordFunc receives each valid seed record with its position in the seed, counting from 1
+type recordFunc func(record int, server *apiv0.ServerJSON) error
+
+// matchFunc reports whether the seed record for the server named name should be read
+type matchFunc func(name string) bool
+
 // NewService creates a new importer service
-func NewService(db database.Database) *Service {
-	return &Service{db: db}
+func NewService(db database.Database, opts ...Option) *Service {
+	s := &Service{db: db, batchSize: defaultBatchSize, policy: ConflictKeepLast}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ImportFromPath imports seed data from various sources:
 // 1. Local file paths (*.json files) - expects extension wrapper format
 // 2. Direct HTTP URLs to seed.json files - expects extension wrapper format
 // 3. Registry root URLs (automatically appends /v0/servers and paginates)
+//
+// Seed files are either a JSON array of servers or newline-delimited JSON with one
+// server per line. They are decoded one record at a time and written in batches, so
+// memory use does not grow with the size of the file.
+//
+// The source is read twice: first to find server names and versions listed more than
+// once, which are resolved by the conflict policy before anything is written, then to
+// import the chosen records.
+//
+// With verification keys configured, the seed's signature is checked before either pass, and
+// unsigned or tampered seeds are refused without importing anything.
+//
+// With a filter configured, both passes skip the records it does not select, and stop reading
+// once its limit is reached.
 func (s *Service) ImportFromPath(ctx context.Context, path string) error {
-	servers, err := readSeedFile(ctx, path)
+	if err := s.filter.Validate(); err != nil {
+		return err
+	}
+
+	path, cleanup, err := s.verify(ctx, path)
 	if err != nil {
-		return fmt.Errorf("failed to read seed data: %w", err)
+		return err
 	}
+	defer cleanup()
 
-	// Import each server using CreateServer
-	for _, server := range servers {
-		_, err := s.db.CreateServer(ctx, server)
-		if err != nil {
-			return fmt.Errorf("failed to import server %s: %w", server.Name, err)
+	read := func(run *filterRun, fn recordFunc, quiet bool) error {
+		fn = run.limit(fn)
+		var err error
+		if isHTTP(path) {
+			// Handle HTTP URLs
+			if isRegistryAPI(path) {
+				// This is a registry API endpoint - fetch paginated data
+				err = fetchFromRegistryAPI(ctx, path, run.match, fn)
+			} else {
+				// This is a direct file URL
+				err = importFromHTTP(ctx, path, run.match, fn, quiet)
+			}
+		} else {
+			// Handle local file paths
+			err = importFromFile(path, run.match, fn, quiet)
+		}
+		if errors.Is(err, errLimitReached) {
+			return nil
 		}
+		return err
 	}
 
-	return nil
+	scan := newConflictScan()
+	if err := read(newFilterRun(s.filter), scan.add, true); err != nil {
+		return err
+	}
+	if err := scan.resolve(s.policy); err != nil {
+		return err
+	}
+
+	batch := &importBatch{ctx: ctx, db: s.db, size: s.batchSize}
+	run := newFilterRun(s.filter)
+	err = read(run, func(record int, server *apiv0.ServerJSON) error {
+		if !scan.keep(s.policy, record, server) {
+			return nil
+		}
+		return batch.add(server)
+	}, false)
+	if err != nil {
+		return err
+	}
+	if !s.filter.empty() {
+		run.log()
+	}
+
+	return batch.flush()
+}
+
+// importBatch collects servers and writes them in a single transaction once full
+type importBatch struct {
+	ctx     context.Context
+	db      database.Database
+	size    int
+	pending []*apiv0.ServerJSON
 }
 
+func (b *importBatch) add(server *apiv0.ServerJSON) error {
+	b.pending = append(b.pending, server)
+	if len(b.pending) >= b.size {
+		return b.flush()
+	}
+	return nil
+}
 
-// readSeedFile reads seed data from various sources
-func readSeedFile(ctx context.Context, path string) ([]*apiv0.ServerJSON, error) {
-	var data []byte
-	var err error
+func (b *importBatch) flush() error {
+	if len(b.pending) == 0 {
+		return nil
+	}
 
-	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
-		// Handle HTTP URLs
-		if strings.HasSuffix(path, "/v0/servers") || strings.Contains(path, "/v0/servers") {
-			// This is a registry API endpoint - fetch paginated data
-			return fetchFromRegistryAPI(ctx, path)
+	err := b.db.InTransaction(b.ctx, func(ctx context.Context, tx database.Database) error {
+		for _, server := range b.pending {
+			if _, err := tx.CreateServer(ctx, server); err != nil {
+				return fmt.Errorf("failed to import server %s: %w", server.Name, err)
+			}
 		}
-		// This is a direct file URL
-		data, err = fetchFromHTTP(ctx, path)
-	} else {
-		// Handle local file paths
-		data, err = os.ReadFile(path)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	clear(b.pending)
+	b.pending = b.pending[:0]
+	return nil
+}
+
+func importFromFile(path string, match matchFunc, fn recordFunc, quiet bool) error {
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read seed data from %s: %w", path, err)
+		return fmt.Errorf("failed to read seed data from %s: %w", path, err)
 	}
+	defer file.Close()
+
+	return readSeed(file, match, fn, quiet)
+}
 
-	// Parse extension wrapper format (only supported format)
-	var serverResponses []apiv0.ServerJSON
-	if err := json.Unmarshal(data, &serverResponses); err != nil {
-		return nil, fmt.Errorf("failed to parse seed data as extension wrapper format: %w", err)
+func importFromHTTP(ctx context.Context, url string, match matchFunc, fn recordFunc, quiet bool) error {
+	body, err := openHTTP(ctx, url)
+	if err != nil {
+		return fmt.Errorf("failed to read seed data from %s: %w", url, err)
 	}
+	defer body.Close()
+
+	return readSeed(body, match, fn, quiet)
+}
 
-	if len(serverResponses) == 0 {
-		return []*apiv0.ServerJSON{}, nil
+// readSeed decodes servers from r one at a time, validating each that match selects and
+// passing the valid ones to fn. Invalid servers are logged and skipped instead of failing the
+// whole import. quiet suppresses logging, for passes that only inspect the seed. Reading
+// stops early when fn returns errLimitReached, which is returned once the summary is logged.
+func readSeed(r io.Reader, match matchFunc, fn recordFunc, quiet bool) error {
+	counter := &countingReader{r: r}
+	buffered := bufio.NewReader(counter)
+
+	first, err := peekNonSpace(buffered)
+	if err != nil {
+		return fmt.Errorf("failed to parse seed data: %w", err)
 	}
 
-	// Validate servers and collect warnings instead of failing the whole batch
-	var validRecords []*apiv0.ServerJSON
-	var invalidServers []string
-	var validationFailures []string
+	dec := json.NewDecoder(buffered)
+	isArray := first == '['
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("failed to parse seed data: %w", err)
+		}
+	}
+
+	var processed, imported, skipped, invalid int
+	var stopped error
+	for {
+		if isArray && !dec.More() {
+			break
+		}
+
+		var server apiv0.ServerJSON
+		if err := dec.Decode(&server); err != nil {
+			if !isArray && errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("failed to parse seed record %d: %w", processed+1, err)
+		}
+		processed++
 
-	for _, response := range serverResponses {
-		if err := validators.ValidateServerJSON(&response); err != nil {
-			// Log warning and track invalid server instead of failing
-			invalidServers = append(invalidServers, response.Name)
-			validationFailures = append(validationFailures, fmt.Sprintf("Server '%s': %v", response.Name, err))
-			log.Printf("Warning: Skipping invalid server '%s': %v", response.Name, err)
+		if !match(server.Name) {
+			skipped++
 			continue
 		}
 
-		// Convert valid ServerJSON to ServerRecord
-		record := convertServerResponseToRecord(response)
-		validRecords = append(validRecords, record)
+		// Versions are normalized as publishing does, without the warning
+		server.Version, _ = validators.NormalizeVersion(server.Version)
+		err := validators.NormalizeServerJSON(&server)
+		if err == nil {
+			err = validators.ValidateVersion(server.Version)
+		}
+		if err == nil {
+			err = validators.ValidateServerJSON(&server)
+		}
+		if err != nil {
+			// Log warning and count invalid server instead of failing
+			invalid++
+			if !quiet {
+				log.Printf("Warning: Skipping invalid server '%s': %v", server.Name, err)
+			}
+		} else {
+			err := fn(processed, &server)
+			if err != nil && !errors.Is(err, errLimitReached) {
+				return err
+			}
+			imported++
+			if err != nil {
+				stopped = err
+				break
+			}
+		}
+
+		if !quiet && processed%progressInterval == 0 {
+			log.Printf("Import progress: %d records processed, %d bytes read", processed, counter.n)
+		}
+	}
+
+	if isArray && stopped == nil {
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("failed to parse seed data: %w", err)
+		}
 	}
 
 	// Print summary of validation results
-	if len(invalidServers) > 0 {
-		log.Printf("Import summary: %d valid servers imported, %d invalid servers skipped", len(validRecords), len(invalidServers))
-		log.Printf("Invalid servers: %v", invalidServers)
-		for _, failure := range validationFailures {
-			log.Printf("  - %s", failure)
+	if quiet {
+		return stopped
+	}
+	switch {
+	case invalid > 0:
+		log.Printf("Import summary: %d valid servers imported, %d invalid servers skipped (%d bytes read)", imported, invalid, counter.n)
+	case skipped > 0 || stopped != nil:
+		log.Printf("Import summary: %d servers imported of %d records read, %d not selected by the seed filter (%d bytes read)", imported, processed, skipped, counter.n)
+	default:
+		log.Printf("Import summary: All %d servers imported successfully (%d bytes read)", imported, counter.n)
+	}
+
+	return stopped
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without consuming it
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, errors.New("seed data is empty")
+			}
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = r.Discard(1)
+		default:
+			return b[0], nil
 		}
-	} else {
-		log.Printf("Import summary: All %d servers imported successfully", len(validRecords))
 	}
+}
 
-	return validRecords, nil
+// countingReader counts the bytes read through it, for progress reporting
+type countingReader struct {
+	r io.Reader
+	n int64
 }
 
-func fetchFromHTTP(ctx context.Context, url string) ([]byte, error) {
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func openHTTP(ctx context.Context, url string) (io.ReadCloser, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
@@ -122,18 +345,25 @@ func fetchFromHTTP(ctx context.Context, url string) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from HTTP: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
 		return nil, fmt.Errorf("HTTP request failed with status: %d", resp.StatusCode)
 	}
 
-	return io.ReadAll(resp.Body)
+	return resp.Body, nil
 }
 
-func fetchFromRegistryAPI(ctx context.Context, baseURL string) ([]*apiv0.ServerJSON, error) {
-	var allRecords []*apiv0.ServerJSON
+func fetchFromRegistryAPI(ctx context.Context, baseURL string, match matchFunc, fn recordFunc) error {
 	cursor := ""
+	record := 0
+	numbered := func(server *apiv0.ServerJSON) error {
+		record++
+		if !match(server.Name) {
+			return nil
+		}
+		return fn(record, server)
+	}
 
 	for {
 		url := baseURL
@@ -145,40 +375,47 @@ func fetchFromRegistryAPI(ctx context.Context, baseURL string) ([]*apiv0.ServerJ
 			}
 		}
 
-		data, err := fetchFromHTTP(ctx, url)
+		nextCursor, err := fetchRegistryPage(ctx, url, numbered)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch page from registry API: %w", err)
+			return err
 		}
 
-		var response struct {
-			Servers  []apiv0.ServerJSON `json:"servers"`
-			Metadata *struct {
-				NextCursor string `json:"next_cursor,omitempty"`
-			} `json:"metadata,omitempty"`
+		// Check if there's a next page
+		if nextCursor == "" {
+			return nil
 		}
+		cursor = nextCursor
+	}
+}
 
-		if err := json.Unmarshal(data, &response); err != nil {
-			return nil, fmt.Errorf("failed to parse registry API response: %w", err)
-		}
+// fetchRegistryPage passes each server on one page of the registry API to fn and
+// returns the cursor of the next page
+func fetchRegistryPage(ctx context.Context, url string, fn func(*apiv0.ServerJSON) error) (string, error) {
+	body, err := openHTTP(ctx, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch page from registry API: %w", err)
+	}
+	defer body.Close()
 
-		// Convert and add servers
-		for _, serverResponse := range response.Servers {
-			record := convertServerResponseToRecord(serverResponse)
-			allRecords = append(allRecords, record)
-		}
+	var response struct {
+		Servers  []apiv0.ServerJSON `json:"servers"`
+		Metadata *struct {
+			NextCursor string `json:"next_cursor,omitempty"`
+		} `json:"metadata,omitempty"`
+	}
 
-		// Check if there's a next page
-		if response.Metadata == nil || response.Metadata.NextCursor == "" {
-			break
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to parse registry API response: %w", err)
+	}
+
+	for i := range response.Servers {
+		if err := fn(&response.Servers[i]); err != nil {
+			return "", err
 		}
-		cursor = response.Metadata.NextCursor
 	}
 
-	return allRecords, nil
+	if response.Metadata == nil {
+		return "", nil
+	}
+	return response.Metadata.NextCursor, nil
 }
-
-func convertServerResponseToRecord(response apiv0.ServerJSON) *apiv0.ServerJSON {
-	// The response is already in the correct flattened format
-	// Just return a pointer to it
-	return &response
-}
\ No newline at end of file
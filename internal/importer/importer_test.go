@@ -1,11 +1,15 @@
 package importer_test
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -191,3 +195,350 @@ func TestImportService_RegistryAPI(t *testing.T) {
 	assert.Contains(t, names, "io.github.test/api-server-1")
 	assert.Contains(t, names, "io.github.test/api-server-2")
 }
+
+// batchCountingDB records the size of each transaction the importer writes
+type batchCountingDB struct {
+	database.Database
+	batches []int
+}
+
+func (db *batchCountingDB) InTransaction(ctx context.Context, fn func(ctx context.Context, tx database.Database) error) error {
+	return db.Database.InTransaction(ctx, func(ctx context.Context, tx database.Database) error {
+		counting := &createCountingDB{Database: tx}
+		if err := fn(ctx, counting); err != nil {
+			return err
+		}
+		db.batches = append(db.batches, counting.created)
+		return nil
+	})
+}
+
+type createCountingDB struct {
+	database.Database
+	created int
+}
+
+func (db *createCountingDB) CreateServer(ctx context.Context, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	db.created++
+	return db.Database.CreateServer(ctx, server)
+}
+
+func syntheticServer(i int) apiv0.ServerJSON {
+	server := apiv0.ServerJSON{
+		Name:        fmt.Sprintf("io.github.test/server-%d", i),
+		Description: "Synthetic server",
+		Version:     "1.0.0",
+		Meta: &apiv0.ServerMeta{
+			Official: &apiv0.RegistryExtensions{
+				ID:          fmt.Sprintf("synthetic-id-%d", i),
+				PublishedAt: time.Now(),
+				UpdatedAt:   time.Now(),
+				IsLatest:    true,
+			},
+		},
+	}
+	if i%1000 == 999 {
+		server.Name = "not a valid name"
+	}
+	return server
+}
+
+func TestImportService_LargeFileIsStreamedInBatches(t *testing.T) {
+	const records = 20000
+	const invalid = records / 1000
+	const batchSize = 1000
+
+	writeArray := func(w *bufio.Writer) {
+		_, _ = w.WriteString("[\n")
+		for i := range records {
+			data, err := json.Marshal(syntheticServer(i))
+			require.NoError(t, err)
+			if i > 0 {
+				_, _ = w.WriteString(",\n")
+			}
+			_, _ = w.Write(data)
+		}
+		_, _ = w.WriteString("\n]\n")
+	}
+	writeNDJSON := func(w *bufio.Writer) {
+		enc := json.NewEncoder(w)
+		for i := range records {
+			require.NoError(t, enc.Encode(syntheticServer(i)))
+		}
+	}
+
+	for name, write := range map[string]func(*bufio.Writer){"array": writeArray, "ndjson": writeNDJSON} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "seed.json")
+			file, err := os.Create(path)
+			require.NoError(t, err)
+			w := bufio.NewWriter(file)
+			write(w)
+			require.NoError(t, w.Flush())
+			require.NoError(t, file.Close())
+
+			db := &batchCountingDB{Database: database.NewMemoryDB()}
+			service := importer.NewService(db, importer.WithBatchSize(batchSize))
+			require.NoError(t, service.ImportFromPath(context.Background(), path))
+
+			total := 0
+			for _, size := range db.batches {
+				assert.LessOrEqual(t, size, batchSize)
+				total += size
+			}
+			assert.Equal(t, records-invalid, total, "every valid record is imported, invalid ones are skipped")
+			assert.Len(t, db.batches, (records-invalid+batchSize-1)/batchSize)
+
+			imported, err := db.GetByID(context.Background(), "synthetic-id-0")
+			require.NoError(t, err)
+			assert.Equal(t, "io.github.test/server-0", imported.Name)
+		})
+	}
+}
+
+func TestImportService_MalformedFile(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":           "  \n",
+		"truncated array": `[{"name": "io.github.test/a", "description": "A", "version": "1.0.0"},`,
+		"bad NDJSON line": "{\"name\": \"io.github.test/a\"}\nnot json\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "seed.json")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+			service := importer.NewService(database.NewMemoryDB())
+			assert.Error(t, service.ImportFromPath(context.Background(), path))
+		})
+	}
+}
+
+func TestImportService_NormalizesVersions(t *testing.T) {
+	record := func(name, version string) string {
+		return fmt.Sprintf(`{"name": %q, "description": "A server", "version": %q, "_meta": {"io.modelcontextprotocol.registry/official": {"id": %q, "published_at": "2025-01-01T00:00:00Z", "updated_at": "2025-01-01T00:00:00Z", "is_latest": true}}}`,
+			name, version, name)
+	}
+	seed := strings.Join([]string{
+		record("io.github.test/a", "v1.0.0"),
+		record("io.github.test/b", " 2.0.0\n"),
+		record("io.github.test/c", "1.0 beta"),
+	}, "\n")
+	path := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(path, []byte(seed), 0600))
+
+	memDB := database.NewMemoryDB()
+	require.NoError(t, importer.NewService(memDB).ImportFromPath(context.Background(), path))
+
+	servers, _, err := memDB.List(context.Background(), nil, "", 10)
+	require.NoError(t, err)
+	versions := map[string]string{}
+	for _, server := range servers {
+		versions[server.Name] = server.Version
+	}
+	assert.Equal(t, map[string]string{"io.github.test/a": "1.0.0", "io.github.test/b": "2.0.0"}, versions, "invalid versions are skipped")
+}
+
+func TestImportService_DuplicateConflicts(t *testing.T) {
+	record := func(version, description, id string, latest bool) string {
+		return fmt.Sprintf(`{"name": "io.github.test/dup", "description": %q, "version": %q, "_meta": {"io.modelcontextprotocol.registry/official": {"id": %q, "published_at": "2025-01-01T00:00:00Z", "updated_at": "2025-01-01T00:00:00Z", "is_latest": %t}}}`,
+			description, version, id, latest)
+	}
+	// Two dumps concatenated: 1.0.0 appears twice with different descriptions, and the second
+	// dump also marks 1.0.0 as latest alongside 2.0.0
+	seed := strings.Join([]string{
+		record("1.0.0", "First dump", "first-1.0.0", false),
+		record("2.0.0", "Second version", "only-2.0.0", true),
+		record("1.0.0", "Second dump", "second-1.0.0", true),
+		strings.Replace(record("1.0.0", "Unrelated", "other-1.0.0", true), "test/dup", "test/other", 1),
+		strings.Replace(record("1.0.0", "Unrelated", "other-1.0.0", true), "test/dup", "test/other", 1),
+	}, "\n")
+	path := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(path, []byte(seed), 0600))
+
+	importWith := func(t *testing.T, policy importer.ConflictPolicy) (database.Database, error) {
+		t.Helper()
+		db := database.NewMemoryDB()
+		err := importer.NewService(db, importer.WithConflictPolicy(policy)).ImportFromPath(context.Background(), path)
+		return db, err
+	}
+	descriptions := func(t *testing.T, db database.Database) map[string]string {
+		t.Helper()
+		servers, _, err := db.List(context.Background(), nil, "", 10)
+		require.NoError(t, err)
+		byVersion := map[string]string{}
+		for _, server := range servers {
+			assert.NotContains(t, byVersion, server.Name+"@"+server.Version, "duplicate imported")
+			byVersion[server.Name+"@"+server.Version] = server.Description
+		}
+		return byVersion
+	}
+
+	t.Run("keep-first", func(t *testing.T) {
+		db, err := importWith(t, importer.ConflictKeepFirst)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"io.github.test/dup@1.0.0":   "First dump",
+			"io.github.test/dup@2.0.0":   "Second version",
+			"io.github.test/other@1.0.0": "Unrelated",
+		}, descriptions(t, db))
+	})
+
+	t.Run("keep-last", func(t *testing.T) {
+		db, err := importWith(t, importer.ConflictKeepLast)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"io.github.test/dup@1.0.0":   "Second dump",
+			"io.github.test/dup@2.0.0":   "Second version",
+			"io.github.test/other@1.0.0": "Unrelated",
+		}, descriptions(t, db))
+	})
+
+	t.Run("abort reports every conflict and imports nothing", func(t *testing.T) {
+		db, err := importWith(t, importer.ConflictAbort)
+		var conflictErr *importer.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+
+		// The identical copies of io.github.test/other are not a conflict
+		assert.Equal(t, []importer.DuplicateConflict{{
+			Name:      "io.github.test/dup",
+			Version:   "1.0.0",
+			First:     1,
+			Duplicate: 3,
+			Fields:    []string{"_meta", "description"},
+		}}, conflictErr.Duplicates)
+		// Under abort the first record counts, so only 2.0.0 is latest; the report is about the seed as it would be imported
+		assert.Empty(t, conflictErr.Latest)
+		assert.Contains(t, err.Error(), "io.github.test/dup 1.0.0: records 1 and 3 differ in _meta, description")
+
+		assert.Empty(t, descriptions(t, db))
+	})
+}
+
+func TestImportService_LatestConflicts(t *testing.T) {
+	seed := `[
+		{"name": "io.github.test/twice-latest", "description": "One", "version": "1.0.0", "_meta": {"io.modelcontextprotocol.registry/official": {"id": "a", "published_at": "2025-01-01T00:00:00Z", "updated_at": "2025-01-01T00:00:00Z", "is_latest": true}}},
+		{"name": "io.github.test/twice-latest", "description": "Two", "version": "2.0.0", "_meta": {"io.modelcontextprotocol.registry/official": {"id": "b", "published_at": "2025-01-02T00:00:00Z", "updated_at": "2025-01-02T00:00:00Z", "is_latest": true}}}
+	]`
+	path := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(path, []byte(seed), 0600))
+
+	db := database.NewMemoryDB()
+	err := importer.NewService(db, importer.WithConflictPolicy(importer.ConflictAbort)).ImportFromPath(context.Background(), path)
+	var conflictErr *importer.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Empty(t, conflictErr.Duplicates)
+	assert.Equal(t, []importer.LatestConflict{{Name: "io.github.test/twice-latest", Versions: []string{"1.0.0", "2.0.0"}}}, conflictErr.Latest)
+
+	// Other policies import the records and only warn
+	db = database.NewMemoryDB()
+	require.NoError(t, importer.NewService(db).ImportFromPath(context.Background(), path))
+	servers, _, err := db.List(context.Background(), nil, "", 10)
+	require.NoError(t, err)
+	assert.Len(t, servers, 2)
+}
+
+func TestImportService_Filter(t *testing.T) {
+	imported := func(t *testing.T, filter importer.Filter) []string {
+		t.Helper()
+		db := database.NewMemoryDB()
+		err := importer.NewService(db, importer.WithFilter(filter)).ImportFromPath(context.Background(), "testdata/filter_seed.json")
+		require.NoError(t, err)
+
+		servers, _, err := db.List(context.Background(), nil, "", 20)
+		require.NoError(t, err)
+		var versions []string
+		for _, server := range servers {
+			versions = append(versions, server.Name+"@"+server.Version)
+		}
+		return versions
+	}
+
+	tests := []struct {
+		name     string
+		filter   importer.Filter
+		expected []string
+	}{
+		{
+			name:   "no filter imports everything",
+			filter: importer.Filter{},
+			expected: []string{
+				"io.github.acme/weather@1.0.0", "io.github.acme/weather@1.1.0", "io.github.acme/internal-tools@0.1.0",
+				"io.github.acme/calendar@2.0.0", "io.github.other/weather@1.0.0", "com.example/notes@3.0.0",
+			},
+		},
+		{
+			name:   "only a namespace",
+			filter: importer.Filter{Only: []string{"io.github.acme/*"}},
+			expected: []string{
+				"io.github.acme/weather@1.0.0", "io.github.acme/weather@1.1.0", "io.github.acme/internal-tools@0.1.0", "io.github.acme/calendar@2.0.0",
+			},
+		},
+		{
+			name:     "only several patterns",
+			filter:   importer.Filter{Only: []string{"*/weather", "com.example/notes"}},
+			expected: []string{"io.github.acme/weather@1.0.0", "io.github.acme/weather@1.1.0", "io.github.other/weather@1.0.0", "com.example/notes@3.0.0"},
+		},
+		{
+			name:   "exclude alone",
+			filter: importer.Filter{Exclude: []string{"io.github.acme/*"}},
+			expected: []string{
+				"io.github.other/weather@1.0.0", "com.example/notes@3.0.0",
+			},
+		},
+		{
+			name:     "exclude wins over only",
+			filter:   importer.Filter{Only: []string{"io.github.acme/*"}, Exclude: []string{"*/internal-*", "io.github.acme/calendar"}},
+			expected: []string{"io.github.acme/weather@1.0.0", "io.github.acme/weather@1.1.0"},
+		},
+		{
+			name:     "limit takes the first entries",
+			filter:   importer.Filter{Limit: 3},
+			expected: []string{"io.github.acme/weather@1.0.0", "io.github.acme/weather@1.1.0", "io.github.acme/internal-tools@0.1.0"},
+		},
+		{
+			name:     "limit counts only selected entries",
+			filter:   importer.Filter{Exclude: []string{"io.github.acme/*"}, Limit: 1},
+			expected: []string{"io.github.other/weather@1.0.0"},
+		},
+		{
+			name:     "nothing matches",
+			filter:   importer.Filter{Only: []string{"io.github.nobody/*"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.expected, imported(t, tt.filter))
+		})
+	}
+}
+
+func TestImportService_FilterLimitStopsReading(t *testing.T) {
+	// Only the first record is valid JSON, so the import succeeds only if the rest is never read
+	seed := `{"name": "io.github.test/a", "description": "A server", "version": "1.0.0", "_meta": {"io.modelcontextprotocol.registry/official": {"id": "a", "published_at": "2025-01-01T00:00:00Z", "updated_at": "2025-01-01T00:00:00Z", "is_latest": true}}}` +
+		"\nnot json\n"
+	path := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(path, []byte(seed), 0600))
+
+	memDB := database.NewMemoryDB()
+	require.Error(t, importer.NewService(memDB).ImportFromPath(context.Background(), path))
+	require.NoError(t, importer.NewService(memDB, importer.WithFilter(importer.Filter{Limit: 1})).ImportFromPath(context.Background(), path))
+
+	servers, _, err := memDB.List(context.Background(), nil, "", 10)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "io.github.test/a", servers[0].Name)
+}
+
+func TestImportService_InvalidFilter(t *testing.T) {
+	for name, filter := range map[string]importer.Filter{
+		"malformed only pattern":    {Only: []string{"io.github.acme/["}},
+		"malformed exclude pattern": {Exclude: []string{"[a-"}},
+		"negative limit":            {Limit: -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := importer.NewService(database.NewMemoryDB(), importer.WithFilter(filter)).ImportFromPath(context.Background(), "testdata/filter_seed.json")
+			assert.Error(t, err)
+		})
+	}
+}
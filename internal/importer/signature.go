@@ -0,0 +1,240 @@
+package importer
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+)
+
+// signatureAlgorithm is the only algorithm seed signatures use
+const signatureAlgorithm = "EdDSA"
+
+var (
+	// ErrUnsignedSeed is returned when verification keys are configured but the seed has no signature
+	ErrUnsignedSeed = errors.New("seed data is unsigned")
+	// ErrInvalidSeedSignature is returned when a seed's signature does not verify
+	ErrInvalidSeedSignature = errors.New("invalid seed signature")
+)
+
+// SeedSignature is a detached signature of a seed file, conventionally stored next to it as
+// <seed>.sig.
+//
+// It signs the SHA-256 digest of the seed's canonical form: every record re-encoded as compact
+// JSON with sorted keys, one per line. A JSON array and NDJSON listing the same records share a
+// signature, while any change to a record's content invalidates it.
+type SeedSignature struct {
+	// Algorithm is always EdDSA
+	Algorithm string `json:"alg"`
+	// KeyID is the JWK thumbprint of the signing key, its kid in GET /v0/admin/jwks
+	KeyID string `json:"kid"`
+	// Digest is the signed digest of the canonical seed, as sha256:<hex>
+	Digest string `json:"digest"`
+	// Signature is the base64url Ed25519 signature of the digest
+	Signature string `json:"signature"`
+}
+
+// WithSignatureVerification makes the importer refuse seeds that are not signed by one of keys.
+// signaturePath is the path or URL of the seed's detached signature; without it, seeds are
+// refused as unsigned. Without keys, seeds are imported unverified.
+func WithSignatureVerification(signaturePath string, keys []ed25519.PublicKey) Option {
+	return func(s *Service) {
+		s.signaturePath = signaturePath
+		s.verificationKeys = keys
+	}
+}
+
+// SignSeed signs the seed read from r with key
+func SignSeed(r io.Reader, key ed25519.PrivateKey) (*SeedSignature, error) {
+	digest, err := seedDigest(r)
+	if err != nil {
+		return nil, err
+	}
+	return &SeedSignature{
+		Algorithm: signatureAlgorithm,
+		KeyID:     auth.JWKThumbprint(key.Public().(ed25519.PublicKey)),
+		Digest:    formatDigest(digest),
+		Signature: base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, digest)),
+	}, nil
+}
+
+// VerifySeed checks that signature is a signature of the seed read from r by one of keys. It
+// returns an error wrapping ErrInvalidSeedSignature if it is not.
+func VerifySeed(r io.Reader, signature SeedSignature, keys []ed25519.PublicKey) error {
+	if signature.Algorithm != signatureAlgorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSeedSignature, signature.Algorithm)
+	}
+	var key ed25519.PublicKey
+	for _, candidate := range keys {
+		if auth.JWKThumbprint(candidate) == signature.KeyID {
+			key = candidate
+			break
+		}
+	}
+	if key == nil {
+		return fmt.Errorf("%w: signed by key %q, which is not a verification key", ErrInvalidSeedSignature, signature.KeyID)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not base64url-encoded", ErrInvalidSeedSignature)
+	}
+
+	digest, err := seedDigest(r)
+	if err != nil {
+		return err
+	}
+	if formatDigest(digest) != signature.Digest {
+		return fmt.Errorf("%w: seed content does not match the signed digest", ErrInvalidSeedSignature)
+	}
+	if !ed25519.Verify(key, digest, sig) {
+		return fmt.Errorf("%w: signature does not match the digest", ErrInvalidSeedSignature)
+	}
+	return nil
+}
+
+// verify checks the seed at path against its signature before anything is imported, and returns
+// the path to import from. Seeds fetched over HTTP are downloaded first, so the records imported
+// are the ones that were verified; cleanup removes the download.
+func (s *Service) verify(ctx context.Context, path string) (verified string, cleanup func(), err error) {
+	cleanup = func() {}
+	if len(s.verificationKeys) == 0 {
+		if s.signaturePath != "" {
+			return "", cleanup, errors.New("a seed signature was given but no verification keys are configured")
+		}
+		return path, cleanup, nil
+	}
+	if s.signaturePath == "" {
+		return "", cleanup, fmt.Errorf("%w: verification keys are configured, so %s needs a signature", ErrUnsignedSeed, path)
+	}
+	if isRegistryAPI(path) {
+		return "", cleanup, fmt.Errorf("%w: registry API sources cannot be signed, import a signed export instead", ErrUnsignedSeed)
+	}
+
+	var signature SeedSignature
+	if err := readSource(ctx, s.signaturePath, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&signature)
+	}); err != nil {
+		return "", cleanup, fmt.Errorf("failed to read seed signature from %s: %w", s.signaturePath, err)
+	}
+
+	if isHTTP(path) {
+		downloaded, err := downloadSeed(ctx, path)
+		if err != nil {
+			return "", cleanup, fmt.Errorf("failed to download seed data from %s: %w", path, err)
+		}
+		path = downloaded
+		cleanup = func() { _ = os.Remove(downloaded) }
+	}
+
+	err = readSource(ctx, path, func(r io.Reader) error {
+		return VerifySeed(r, signature, s.verificationKeys)
+	})
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+	return path, cleanup, nil
+}
+
+// downloadSeed copies the seed at url to a temporary file and returns its path
+func downloadSeed(ctx context.Context, url string) (string, error) {
+	file, err := os.CreateTemp("", "mcp-registry-seed-*")
+	if err != nil {
+		return "", err
+	}
+	err = readSource(ctx, url, func(r io.Reader) error {
+		_, err := io.Copy(file, r)
+		return err
+	})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// readSource passes the contents of a local file or HTTP URL to fn
+func readSource(ctx context.Context, path string, fn func(io.Reader) error) error {
+	var body io.ReadCloser
+	var err error
+	if isHTTP(path) {
+		body, err = openHTTP(ctx, path)
+	} else {
+		body, err = os.Open(path)
+	}
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return fn(body)
+}
+
+// seedDigest hashes the canonical form of the seed read from r
+func seedDigest(r io.Reader) ([]byte, error) {
+	buffered := bufio.NewReader(r)
+	first, err := peekNonSpace(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse seed data: %w", err)
+	}
+
+	dec := json.NewDecoder(buffered)
+	// Numbers keep their original text rather than being rounded through float64
+	dec.UseNumber()
+	isArray := first == '['
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("failed to parse seed data: %w", err)
+		}
+	}
+
+	hash := sha256.New()
+	for record := 1; !isArray || dec.More(); record++ {
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			if !isArray && errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse seed record %d: %w", record, err)
+		}
+		// Maps are encoded with sorted keys, so formatting and key order do not matter
+		canonical, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode seed record %d: %w", record, err)
+		}
+		hash.Write(canonical)
+		hash.Write([]byte{'\n'})
+	}
+
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("failed to parse seed data: %w", err)
+		}
+	}
+	return hash.Sum(nil), nil
+}
+
+func formatDigest(digest []byte) string {
+	return "sha256:" + hex.EncodeToString(digest)
+}
+
+func isHTTP(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// isRegistryAPI reports whether path is a registry's server list, which is fetched page by page
+func isRegistryAPI(path string) bool {
+	return isHTTP(path) && strings.Contains(path, "/v0/servers")
+}
@@ -0,0 +1,152 @@
+package importer_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/importer"
+)
+
+const signedSeed = `[
+  {"name": "io.github.test/signed", "description": "A signed server", "version": "1.0.0",
+   "_meta": {"io.modelcontextprotocol.registry/official": {"id": "signed-1", "isLatest": false}}},
+  {"name": "io.github.test/signed", "description": "A signed server", "version": "1.1.0",
+   "_meta": {"io.modelcontextprotocol.registry/official": {"id": "signed-2", "isLatest": true}}}
+]`
+
+// writeSignedSeed writes seed and its signature by key to a temporary directory, returning their paths
+func writeSignedSeed(t *testing.T, seed string, key ed25519.PrivateKey) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	seedPath := filepath.Join(dir, "seed.json")
+	require.NoError(t, os.WriteFile(seedPath, []byte(seed), 0o600))
+
+	signature, err := importer.SignSeed(strings.NewReader(seed), key)
+	require.NoError(t, err)
+	data, err := json.Marshal(signature)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(seedPath+".sig", data, 0o600))
+	return seedPath, seedPath + ".sig"
+}
+
+func testSigningKey(b byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, ed25519.SeedSize))
+}
+
+func TestImportService_SignedSeed(t *testing.T) {
+	ctx := context.Background()
+	key := testSigningKey(1)
+	keys := []ed25519.PublicKey{testSigningKey(2).Public().(ed25519.PublicKey), key.Public().(ed25519.PublicKey)}
+
+	t.Run("valid signature", func(t *testing.T) {
+		seedPath, sigPath := writeSignedSeed(t, signedSeed, key)
+		db := database.NewMemoryDB()
+		require.NoError(t, importer.NewService(db, importer.WithSignatureVerification(sigPath, keys)).ImportFromPath(ctx, seedPath))
+
+		servers, _, err := db.List(ctx, nil, "", 10)
+		require.NoError(t, err)
+		assert.Len(t, servers, 2)
+	})
+
+	t.Run("valid signature over HTTP", func(t *testing.T) {
+		seedPath, sigPath := writeSignedSeed(t, signedSeed, key)
+		server := httptest.NewServer(http.FileServer(http.Dir(filepath.Dir(seedPath))))
+		defer server.Close()
+
+		db := database.NewMemoryDB()
+		service := importer.NewService(db, importer.WithSignatureVerification(server.URL+"/"+filepath.Base(sigPath), keys))
+		require.NoError(t, service.ImportFromPath(ctx, server.URL+"/"+filepath.Base(seedPath)))
+
+		servers, _, err := db.List(ctx, nil, "", 10)
+		require.NoError(t, err)
+		assert.Len(t, servers, 2)
+	})
+
+	t.Run("signature survives reformatting", func(t *testing.T) {
+		// The same records as NDJSON with keys reordered share the array's signature
+		_, sigPath := writeSignedSeed(t, signedSeed, key)
+		ndjson := `{"version":"1.0.0","name":"io.github.test/signed","description":"A signed server",` +
+			`"_meta":{"io.modelcontextprotocol.registry/official":{"isLatest":false,"id":"signed-1"}}}` + "\n" +
+			`{"_meta":{"io.modelcontextprotocol.registry/official":{"isLatest":true,"id":"signed-2"}},` +
+			`"description":"A signed server","version":"1.1.0","name":"io.github.test/signed"}` + "\n"
+		seedPath := filepath.Join(t.TempDir(), "seed.ndjson")
+		require.NoError(t, os.WriteFile(seedPath, []byte(ndjson), 0o600))
+
+		db := database.NewMemoryDB()
+		require.NoError(t, importer.NewService(db, importer.WithSignatureVerification(sigPath, keys)).ImportFromPath(ctx, seedPath))
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		seedPath, sigPath := writeSignedSeed(t, signedSeed, key)
+		tampered := strings.Replace(signedSeed, "1.1.0", "1.1.1", 1)
+		require.NoError(t, os.WriteFile(seedPath, []byte(tampered), 0o600))
+
+		db := database.NewMemoryDB()
+		err := importer.NewService(db, importer.WithSignatureVerification(sigPath, keys)).ImportFromPath(ctx, seedPath)
+		require.ErrorIs(t, err, importer.ErrInvalidSeedSignature)
+		assert.ErrorContains(t, err, "does not match the signed digest")
+
+		servers, _, err := db.List(ctx, nil, "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, servers, "nothing is imported from a tampered seed")
+	})
+
+	t.Run("forged digest", func(t *testing.T) {
+		// Recomputing the digest of a tampered seed does not help without the key
+		seedPath, sigPath := writeSignedSeed(t, signedSeed, key)
+		tampered := strings.Replace(signedSeed, "1.1.0", "1.1.1", 1)
+		require.NoError(t, os.WriteFile(seedPath, []byte(tampered), 0o600))
+		forged, err := importer.SignSeed(strings.NewReader(tampered), testSigningKey(3))
+		require.NoError(t, err)
+		var signature importer.SeedSignature
+		data, err := os.ReadFile(sigPath)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &signature))
+		signature.Digest = forged.Digest
+
+		err = importer.VerifySeed(strings.NewReader(tampered), signature, keys)
+		require.ErrorIs(t, err, importer.ErrInvalidSeedSignature)
+		assert.ErrorContains(t, err, "signature does not match")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		seedPath, sigPath := writeSignedSeed(t, signedSeed, testSigningKey(3))
+		err := importer.NewService(database.NewMemoryDB(), importer.WithSignatureVerification(sigPath, keys)).ImportFromPath(ctx, seedPath)
+		require.ErrorIs(t, err, importer.ErrInvalidSeedSignature)
+		assert.ErrorContains(t, err, "not a verification key")
+	})
+
+	t.Run("unsigned with keys configured", func(t *testing.T) {
+		seedPath, _ := writeSignedSeed(t, signedSeed, key)
+		db := database.NewMemoryDB()
+		err := importer.NewService(db, importer.WithSignatureVerification("", keys)).ImportFromPath(ctx, seedPath)
+		require.ErrorIs(t, err, importer.ErrUnsignedSeed)
+
+		err = importer.NewService(db, importer.WithSignatureVerification(seedPath+".sig", keys)).ImportFromPath(ctx, "https://registry.example.com/v0/servers")
+		require.ErrorIs(t, err, importer.ErrUnsignedSeed, "registry API sources cannot be signed")
+
+		servers, _, err := db.List(ctx, nil, "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, servers)
+	})
+
+	t.Run("no keys configured", func(t *testing.T) {
+		seedPath, sigPath := writeSignedSeed(t, signedSeed, key)
+		require.NoError(t, importer.NewService(database.NewMemoryDB()).ImportFromPath(ctx, seedPath), "seeds are imported unverified")
+
+		err := importer.NewService(database.NewMemoryDB(), importer.WithSignatureVerification(sigPath, nil)).ImportFromPath(ctx, seedPath)
+		require.ErrorContains(t, err, "no verification keys are configured")
+	})
+}
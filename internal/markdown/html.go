@@ -0,0 +1,283 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	atxHeading   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	blockquote   = regexp.MustCompile(`^ {0,3}> ?`)
+	listItem     = regexp.MustCompile(`^ {0,3}([-*+]|[0-9]{1,9}[.)])(?:[ \t]+|$)`)
+	inlineImage  = regexp.MustCompile(`!\[([^\]]*)\]\(([^\s()]*(?:\([^\s()]*\)[^\s()]*)*)(?:\s+"[^"]*")?\)`)
+	inlineAnchor = regexp.MustCompile(`\[([^\]]+)\]\(([^\s()]*(?:\([^\s()]*\)[^\s()]*)*)(?:\s+"[^"]*")?\)`)
+
+	strong             = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	emphasis           = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*`)
+	underscoreEmphasis = regexp.MustCompile(`(^|\W)_(\S(?:.*?\S)?)_(\W|$)`) // not inside words like snake_case
+	strikethrough      = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+
+	// renderedTag matches a tag produced while rendering, stripped from protected text that ends up in alt text
+	renderedTag = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ToHTML renders markdown as HTML. It covers the common subset of CommonMark used in
+// READMEs: headings, paragraphs, block quotes, flat lists, fenced code, thematic breaks,
+// code spans, emphasis, links and images. All text is escaped and only safe URLs become
+// links, so the result is safe to embed whether or not src was sanitized.
+func ToHTML(src string) string {
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(normalize(src), "\n"))
+	return b.String()
+}
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case isFence(line):
+			i = renderFence(b, lines, i)
+
+		case atxHeading.MatchString(line):
+			parts := atxHeading.FindStringSubmatch(line)
+			level := strconv.Itoa(len(parts[1]))
+			b.WriteString("<h" + level + ">" + renderInline(parts[2]) + "</h" + level + ">\n")
+			i++
+
+		case isThematicBreak(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case blockquote.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && blockquote.MatchString(lines[i]); i++ {
+				quoted = append(quoted, blockquote.ReplaceAllString(lines[i], ""))
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+
+		case listItem.MatchString(line):
+			i = renderList(b, lines, i)
+
+		default:
+			start := i
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsBlock(lines[i]); i++ {
+			}
+			b.WriteString("<p>" + renderInline(strings.TrimSpace(strings.Join(lines[start:i], "\n"))) + "</p>\n")
+		}
+	}
+}
+
+// startsBlock reports whether line interrupts a paragraph
+func startsBlock(line string) bool {
+	return isFence(line) || atxHeading.MatchString(line) || isThematicBreak(line) ||
+		blockquote.MatchString(line) || listItem.MatchString(line)
+}
+
+func isFence(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	return len(line)-len(trimmed) <= 3 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"))
+}
+
+// renderFence renders the fenced code block starting at lines[i], returning the index after it
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	opening := strings.TrimLeft(lines[i], " ")
+	marker := opening[:len(opening)-len(strings.TrimLeft(opening, opening[:1]))]
+	language := strings.Fields(opening[len(marker):])
+
+	var code []string
+	for i++; i < len(lines) && !closesFence(lines[i], marker); i++ {
+		code = append(code, lines[i])
+	}
+
+	b.WriteString("<pre><code")
+	if len(language) > 0 {
+		b.WriteString(` class="language-` + html.EscapeString(language[0]) + `"`)
+	}
+	b.WriteString(">")
+	for _, line := range code {
+		b.WriteString(html.EscapeString(line) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i + 1
+}
+
+func isThematicBreak(line string) bool {
+	compact := strings.NewReplacer(" ", "", "\t", "").Replace(line)
+	if len(line)-len(strings.TrimLeft(line, " ")) > 3 || len(compact) < 3 {
+		return false
+	}
+	return strings.Trim(compact, compact[:1]) == "" && strings.ContainsAny(compact[:1], "-*_")
+}
+
+// renderList renders the list starting at lines[i], returning the index after it.
+// Nested content is rendered as blocks inside its item; lists themselves are not nested.
+func renderList(b *strings.Builder, lines []string, i int) int {
+	marker := listItem.FindStringSubmatch(lines[i])[1]
+	ordered := isOrdered(marker)
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+		if start, _ := strconv.Atoi(marker[:len(marker)-1]); start != 1 {
+			tag = `ol start="` + strconv.Itoa(start) + `"`
+		}
+	}
+	b.WriteString("<" + tag + ">\n")
+
+	for i < len(lines) {
+		parts := listItem.FindStringSubmatch(lines[i])
+		if parts == nil || isOrdered(parts[1]) != ordered {
+			break
+		}
+
+		// An item holds its first line and the lines after it up to the next item or
+		// an unindented block; indented lines after a blank line continue it
+		item := []string{strings.TrimPrefix(lines[i], parts[0])}
+		blank := false
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			switch {
+			case strings.TrimSpace(line) == "":
+				blank = true
+				item = append(item, "")
+				continue
+			case strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t"):
+				item = append(item, strings.TrimLeft(line, " \t"))
+				blank = false
+				continue
+			case !blank && !startsBlock(line):
+				item = append(item, line)
+				continue
+			}
+			break
+		}
+		for len(item) > 0 && item[len(item)-1] == "" {
+			item = item[:len(item)-1]
+		}
+
+		b.WriteString("<li>")
+		if len(item) <= 1 || !containsBlock(item) {
+			b.WriteString(renderInline(strings.TrimSpace(strings.Join(item, "\n"))))
+		} else {
+			b.WriteString("\n")
+			renderBlocks(b, item)
+		}
+		b.WriteString("</li>\n")
+
+		if blank && (i >= len(lines) || !listItem.MatchString(lines[i])) {
+			break
+		}
+	}
+
+	b.WriteString("</" + tag[:2] + ">\n")
+	return i
+}
+
+func isOrdered(marker string) bool {
+	last := marker[len(marker)-1]
+	return last == '.' || last == ')'
+}
+
+// containsBlock reports whether list item content needs block rendering
+func containsBlock(lines []string) bool {
+	for _, line := range lines[1:] {
+		if line == "" || startsBlock(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderInline renders code spans, links, images and emphasis, escaping everything else
+func renderInline(s string) string {
+	var protected []string
+	protect := func(text string) string {
+		protected = append(protected, text)
+		return "\x00" + strconv.Itoa(len(protected)-1) + "\x00"
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, span := range codeSpans(s) {
+		b.WriteString(s[last:span[0]])
+		n := runLength(s, span[0])
+		code := s[span[0]+n : span[1]-n]
+		if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' {
+			code = code[1 : len(code)-1]
+		}
+		b.WriteString(protect("<code>" + html.EscapeString(code) + "</code>"))
+		last = span[1]
+	}
+	b.WriteString(s[last:])
+	s = b.String()
+
+	s = backslashEscape.ReplaceAllStringFunc(s, func(match string) string {
+		return protect(html.EscapeString(match[1:]))
+	})
+
+	// Destinations and alt text go into attributes, so any text protected so far is put back
+	// before they are checked: a code span or escape must not hide a URL's scheme
+	destination := func(raw string) (string, bool) {
+		url := trimAngles(restore(raw, protected))
+		return url, !strings.ContainsAny(url, "<>") && SafeURL(url)
+	}
+
+	s = inlineImage.ReplaceAllStringFunc(s, func(match string) string {
+		parts := inlineImage.FindStringSubmatch(match)
+		alt := placeholder.ReplaceAllStringFunc(parts[1], func(match string) string {
+			return renderedTag.ReplaceAllString(restore(match, protected), "")
+		})
+		alt = html.EscapeString(html.UnescapeString(alt))
+		url, ok := destination(parts[2])
+		if !ok {
+			return protect(alt)
+		}
+		return protect(`<img src="` + escapeURL(url) + `" alt="` + alt + `">`)
+	})
+
+	s = inlineAnchor.ReplaceAllStringFunc(s, func(match string) string {
+		parts := inlineAnchor.FindStringSubmatch(match)
+		text := renderText(parts[1])
+		url, ok := destination(parts[2])
+		if !ok {
+			return protect(text)
+		}
+		return protect(anchor(url, text))
+	})
+
+	// Autolinks come last, so that one in angle brackets is a link's destination rather than a link
+	s = autolink.ReplaceAllStringFunc(s, func(match string) string {
+		url := match[1 : len(match)-1]
+		if !SafeURL(url) {
+			return protect(html.EscapeString(url))
+		}
+		return protect(anchor(url, html.EscapeString(url)))
+	})
+
+	return restore(renderText(s), protected)
+}
+
+// renderText escapes plain text and applies emphasis
+func renderText(s string) string {
+	s = html.EscapeString(html.UnescapeString(s))
+	s = strong.ReplaceAllString(s, "<strong>$1$2</strong>")
+	s = emphasis.ReplaceAllString(s, "<em>$1</em>")
+	s = underscoreEmphasis.ReplaceAllString(s, "$1<em>$2</em>$3")
+	return strikethrough.ReplaceAllString(s, "<del>$1</del>")
+}
+
+func anchor(url, text string) string {
+	return `<a href="` + escapeURL(url) + `" rel="nofollow">` + text + `</a>`
+}
+
+// escapeURL escapes a link destination for use in an attribute
+func escapeURL(url string) string {
+	return html.EscapeString(html.UnescapeString(url))
+}
@@ -0,0 +1,216 @@
+package markdown_test
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/modelcontextprotocol/registry/internal/markdown"
+)
+
+func TestSanitize_HostileInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "script element", input: "Hello <script>alert(1)</script>world", want: "Hello world"},
+		{name: "unterminated script", input: "Hello <script>alert(1)", want: "Hello "},
+		{name: "style element", input: "<style>body{display:none}</style>Text", want: "Text"},
+		{name: "event handler attribute", input: "<img src=x onerror=alert(1)>", want: ""},
+		{name: "unknown element", input: "<foo onmouseover=alert(1)>hover</foo>", want: "hover"},
+		{name: "self-closing tag without space", input: "<svg/onload=alert(1)>", want: ""},
+		{name: "iframe", input: `<iframe src="https://evil.example"></iframe>`, want: ""},
+		{name: "comment", input: "a<!-- <script>alert(1)</script> -->b", want: "ab"},
+		{name: "stray angle bracket", input: "<img src=x onerror=alert(1)//", want: "&lt;img src=x onerror=alert(1)//"},
+		{name: "javascript link", input: "[click](javascript:alert(1))", want: "click"},
+		{name: "mixed case scheme", input: "[click](JaVaScRiPt:alert(1))", want: "click"},
+		{name: "entity-encoded scheme", input: "[click](java&#115;cript:alert(1))", want: "click"},
+		{name: "entity-encoded colon", input: "[click](javascript&colon;alert(1))", want: "click"},
+		{name: "backslash-escaped colon", input: `[click](javascript\:alert(1))`, want: "click"},
+		{name: "tab inside scheme", input: "[click](java&#9;script:alert(1))", want: "click"},
+		{name: "angle-bracket destination", input: "[click](<javascript:alert(1)>)", want: "click"},
+		{name: "nested link text", input: "[[click]](javascript:alert(1))", want: "[click]"},
+		{name: "deeply nested link text", input: "[[[click]]](javascript:alert(1))", want: "[[[click]]]())"},
+		{name: "vbscript link", input: "[click](vbscript:msgbox(1))", want: "click"},
+		{name: "data image", input: "![img](data:text/html;base64,PHNjcmlwdD4=)", want: "img"},
+		{name: "javascript autolink", input: "<javascript:alert(1)>", want: ""},
+		{name: "javascript reference definition", input: "[x][ref]\n\n[ref]: javascript:alert(1)", want: "[x][ref]\n\n"},
+		{name: "tag hiding backticks", input: "<img src=\"`\" onerror=\"alert(1)\" title=\"`\">", want: ""},
+		{name: "code span split by table cells", input: "| `a | <img src=x onerror=alert(1)> | b` |", want: "| `a |  | b` |"},
+		{name: "indented fence is not trusted", input: "  ```\n<img src=x onerror=alert(1)>\n  ```", want: "  ```\n\n  ```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.Sanitize(tt.input))
+		})
+	}
+}
+
+func TestSanitize_KeepsSafeMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "headings and emphasis", input: "# Weather\n\nGet **current** conditions and _forecasts_."},
+		{name: "lists", input: "- one\n- two\n\n1. first\n2. second"},
+		{name: "links", input: `[docs](https://example.com/docs "Docs"), [guide](docs/guide.md), [top](#usage) and [mail](mailto:me@example.com)`},
+		{name: "image", input: "![logo](https://example.com/logo.png)"},
+		{name: "autolink", input: "See <https://example.com>."},
+		{name: "code span with angle brackets", input: "Set `API_KEY=<your key>` first."},
+		{name: "fenced code with HTML", input: "```html\n<script src=\"app.js\"></script>\n```"},
+		{name: "unclosed fence", input: "```\n<b>code</b>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.input, markdown.Sanitize(tt.input))
+		})
+	}
+
+	assert.Equal(t, "a &lt; b", markdown.Sanitize("a < b"))
+	assert.Equal(t, "line one\nline two", markdown.Sanitize("line one\r\nline two"))
+}
+
+func TestToHTML(t *testing.T) {
+	input := strings.Join([]string{
+		"# Weather <b>server</b>",
+		"",
+		"Get **current** conditions, _forecasts_ and `alerts<T>`.",
+		"See [the docs](https://example.com/docs) or <https://example.com>.",
+		"",
+		"- one",
+		"- two",
+		"",
+		"> quoted",
+		"",
+		"```sh",
+		"echo '<hi>'",
+		"```",
+		"",
+		"---",
+		"",
+		"[bad](javascript:alert(1)) ![logo](https://example.com/logo.png)",
+	}, "\n")
+
+	want := strings.Join([]string{
+		"<h1>Weather &lt;b&gt;server&lt;/b&gt;</h1>",
+		"<p>Get <strong>current</strong> conditions, <em>forecasts</em> and <code>alerts&lt;T&gt;</code>.",
+		`See <a href="https://example.com/docs" rel="nofollow">the docs</a> or <a href="https://example.com" rel="nofollow">https://example.com</a>.</p>`,
+		"<ul>",
+		"<li>one</li>",
+		"<li>two</li>",
+		"</ul>",
+		"<blockquote>",
+		"<p>quoted</p>",
+		"</blockquote>",
+		`<pre><code class="language-sh">echo &#39;&lt;hi&gt;&#39;`,
+		"</code></pre>",
+		"<hr>",
+		`<p>bad <img src="https://example.com/logo.png" alt="logo"></p>`,
+		"",
+	}, "\n")
+
+	assert.Equal(t, want, markdown.ToHTML(input))
+}
+
+func TestToHTML_EscapesUnsanitizedInput(t *testing.T) {
+	html := markdown.ToHTML("<script>alert(1)</script>\n\n[x](\"onmouseover=\"alert(1))")
+	assert.NotContains(t, html, "<script")
+	assert.NotContains(t, html, `"onmouseover`)
+}
+
+func TestToHTML_EdgeCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "backslash-escaped colon", input: `[click](javascript\:alert(1))`, want: "<p>click</p>\n"},
+		{name: "code span destination", input: "[x](`javascript:alert(1)`)", want: "<p>x</p>\n"},
+		{name: "autolink destination", input: "[x](<javascript:alert(1)>)", want: "<p>x</p>\n"},
+		{name: "safe angle-bracket destination", input: "[x](<https://example.com>)", want: `<p><a href="https://example.com" rel="nofollow">x</a></p>` + "\n"},
+		{name: "autolink in alt text", input: "![<https://x/onerror=alert(1)//>](https://example.com/logo.png)", want: `<p><img src="https://example.com/logo.png" alt="&lt;https://x/onerror=alert(1)//&gt;"></p>` + "\n"},
+		{name: "code span in alt text", input: "![a `b` c](https://example.com/logo.png)", want: `<p><img src="https://example.com/logo.png" alt="a b c"></p>` + "\n"},
+		{name: "empty list item", input: "-", want: "<ul>\n<li></li>\n</ul>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.ToHTML(tt.input))
+		})
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	for _, url := range []string{"https://example.com", "http://example.com", "mailto:me@example.com", "docs/guide.md", "/docs", "#usage", "?q=1", "./a:b"} {
+		assert.True(t, markdown.SafeURL(url), url)
+	}
+	for _, url := range []string{"javascript:alert(1)", " javascript:alert(1)", "data:text/html,x", "file:///etc/passwd", "JAVASCRIPT:alert(1)"} {
+		assert.False(t, markdown.SafeURL(url), url)
+	}
+}
+
+var (
+	// htmlTag matches a tag in rendered HTML, where every other '<' is escaped
+	htmlTag = regexp.MustCompile(`<[^>]*>?`)
+	// attributeValue matches a quoted attribute value; rendering escapes quotes inside values
+	attributeValue = regexp.MustCompile(`"[^"]*"`)
+	// eventHandler matches an event handler attribute in a tag whose attribute values are removed
+	eventHandler = regexp.MustCompile(`(?i)[\s/]on[a-z]+\s*=`)
+	// urlAttribute matches the value of an attribute that holds a URL
+	urlAttribute = regexp.MustCompile(`(?i)\s(?:href|src)="([^"]*)"`)
+)
+
+// FuzzToHTML checks that no markdown renders to a script element, an event handler, or a
+// link or image with a scriptable URL, whether or not it was sanitized first
+func FuzzToHTML(f *testing.F) {
+	for _, seed := range []string{
+		"# Weather\n\nGet **current** conditions, [docs](https://example.com) and `code`.",
+		"<script>alert(1)</script>",
+		"<img src=x onerror=alert(1)>",
+		"<svg/onload=alert(1)>",
+		"[click](javascript:alert(1))",
+		"[click](java&#115;cript:alert(1))",
+		"[click](javascript&colon;alert(1))",
+		"[click](<javascript:alert(1)>)",
+		"[[[click]]](javascript:alert(1))",
+		"![img](data:text/html;base64,PHNjcmlwdD4=)",
+		"<javascript:alert(1)>",
+		"[x](\"onmouseover=\"alert(1))",
+		"[x](https://example.com\" onmouseover=\"alert(1))",
+		"![x](\"onerror=alert(1)//)",
+		"<img src=\"`\" onerror=\"alert(1)\" title=\"`\">",
+		"| `a | <img src=x onerror=alert(1)> | b` |",
+		"- item\n\n  ```\n  <script>alert(1)</script>\n  ```",
+		"> ```\n> <script>alert(1)</script>",
+		"[click](javascript\\:alert(1))",
+		"[x](`javascript:alert(1)`)",
+		"[x](<https://example.com/\" onmouseover=\"alert(1)>)",
+		"![<https://x/onerror=alert(1)//>](https://example.com/logo.png)",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, src string) {
+		for _, rendered := range []string{markdown.ToHTML(src), markdown.ToHTML(markdown.Sanitize(src))} {
+			for _, tag := range htmlTag.FindAllString(rendered, -1) {
+				if strings.HasPrefix(strings.ToLower(tag), "<script") {
+					t.Fatalf("script element in %q from %q", rendered, src)
+				}
+				if eventHandler.MatchString(attributeValue.ReplaceAllString(tag, `""`)) {
+					t.Fatalf("event handler in %q from %q", tag, src)
+				}
+				for _, value := range urlAttribute.FindAllStringSubmatch(tag, -1) {
+					if !markdown.SafeURL(html.UnescapeString(value[1])) {
+						t.Fatalf("unsafe URL in %q from %q", tag, src)
+					}
+				}
+			}
+		}
+	})
+}
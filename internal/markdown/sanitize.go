@@ -0,0 +1,288 @@
+// Package markdown sanitizes publisher-supplied markdown and renders it to HTML.
+//
+// Sanitized markdown is safe to hand to any CommonMark renderer: raw HTML is removed
+// everywhere except code, and links may only point at http, https and mailto URLs
+// or relative paths.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// dangerousElements are removed along with their content, which is never meant to be read as text
+	dangerousElements = func() []*regexp.Regexp {
+		var elements []*regexp.Regexp
+		for _, name := range []string{"script", "style", "iframe", "object", "embed", "noscript", "template", "textarea", "svg", "math"} {
+			elements = append(elements, regexp.MustCompile(fmt.Sprintf(`(?is)<%[1]s\b.*?(?:</%[1]s\s*>|\z)`, name)))
+		}
+		return elements
+	}()
+
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?(?:-->|\z)`)
+
+	// htmlTag matches open and close tags, declarations and processing instructions. A tag name
+	// is followed by whitespace, '/' or '>', which tells it apart from an autolink like <https://...>
+	htmlTag = regexp.MustCompile(`(?s)</?[A-Za-z][A-Za-z0-9-]*(?:[\s/][^>]*)?>|<![^>]*>|<\?.*?\?>`)
+
+	autolink = regexp.MustCompile(`<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^<>\s]*)>`)
+
+	// inlineLink matches [text](destination "title") and images, allowing one level of nested brackets in the text
+	inlineLink = regexp.MustCompile(`(!?)\[((?:[^\[\]\\]|\\.|\[(?:[^\[\]\\]|\\.)*\])*)\]\(\s*(<[^<>\n]*>|[^\s()<]*(?:\([^\s()]*\)[^\s()<]*)*)((?:\s+(?:"[^"\n]*"|'[^'\n]*'|\([^()\n]*\)))?\s*)\)`)
+
+	// linkDestination catches any remaining destination after "](", whatever the link text looked like
+	linkDestination = regexp.MustCompile(`\]\(\s*(<[^<>\n]*>|[^\s)]*)`)
+
+	referenceDefinition = regexp.MustCompile(`(?m)^ {0,3}\[(?:[^\[\]\\]|\\.)+\]:[ \t]*\n?[ \t]*(<[^<>\n]*>|\S+).*$`)
+
+	backslashEscape = regexp.MustCompile(`\\([!-/:-@\[-` + "`" + `{-~])`)
+
+	// placeholder stands in for protected text while the rest is rewritten; NUL never survives normalize
+	placeholder = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+// Sanitize returns src with raw HTML removed and links to unsafe URLs dropped.
+// Fenced code blocks that start in the first column and inline code spans are kept
+// verbatim; everywhere else a '<' that does not start a safe autolink is escaped.
+func Sanitize(src string) string {
+	var out, prose []string
+	flush := func() {
+		if len(prose) > 0 {
+			out = append(out, sanitizeProse(strings.Join(prose, "\n")))
+			prose = nil
+		}
+	}
+
+	lines := strings.Split(normalize(src), "\n")
+	for i := 0; i < len(lines); i++ {
+		marker, ok := openingFence(lines[i])
+		if !ok {
+			prose = append(prose, lines[i])
+			continue
+		}
+
+		// A fence runs to its closing line, or to the end of the document
+		flush()
+		out = append(out, lines[i])
+		for i++; i < len(lines); i++ {
+			out = append(out, lines[i])
+			if closesFence(lines[i], marker) {
+				break
+			}
+		}
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// normalize unifies line endings and drops NUL characters
+func normalize(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	return strings.ReplaceAll(src, "\x00", "")
+}
+
+// openingFence reports whether line opens a fenced code block, returning its fence marker.
+// Only unindented fences count: an indented one may belong to a list item that a
+// renderer closes early, which would expose its contents as raw HTML.
+func openingFence(line string) (string, bool) {
+	if !strings.HasPrefix(line, "```") && !strings.HasPrefix(line, "~~~") {
+		return "", false
+	}
+	marker := line[:len(line)-len(strings.TrimLeft(line, line[:1]))]
+	if marker[0] == '`' && strings.Contains(line[len(marker):], "`") {
+		return "", false
+	}
+	return marker, true
+}
+
+// closesFence reports whether line closes a fence opened with marker
+func closesFence(line, marker string) bool {
+	trimmed := strings.TrimSpace(line)
+	if len(line)-len(strings.TrimLeft(line, " ")) > 3 || len(trimmed) < len(marker) {
+		return false
+	}
+	return strings.Trim(trimmed, marker[:1]) == ""
+}
+
+// sanitizeProse sanitizes markdown outside fenced code blocks
+func sanitizeProse(s string) string {
+	var protected []string
+	protect := func(text string) string {
+		protected = append(protected, text)
+		return "\x00" + strconv.Itoa(len(protected)-1) + "\x00"
+	}
+
+	// Keep code spans whose delimiters are not themselves inside raw HTML, which binds tighter
+	tags := rawHTML(s)
+	var b strings.Builder
+	last := 0
+	for _, span := range codeSpans(s) {
+		if insideAny(span[0], tags) || insideAny(span[1]-1, tags) {
+			continue
+		}
+		b.WriteString(s[last:span[0]])
+		b.WriteString(protect(s[span[0]:span[1]]))
+		last = span[1]
+	}
+	b.WriteString(s[last:])
+	s = b.String()
+
+	for _, element := range dangerousElements {
+		s = element.ReplaceAllString(s, "")
+	}
+	s = htmlComment.ReplaceAllString(s, "")
+	s = htmlTag.ReplaceAllString(s, "")
+
+	s = referenceDefinition.ReplaceAllStringFunc(s, func(match string) string {
+		if !SafeURL(trimAngles(referenceDefinition.FindStringSubmatch(match)[1])) {
+			return ""
+		}
+		return match
+	})
+
+	// Links keep their text when their destination is unsafe; safe angle-bracket destinations
+	// are rewritten bare so that escaping '<' below leaves them intact
+	s = inlineLink.ReplaceAllStringFunc(s, func(match string) string {
+		parts := inlineLink.FindStringSubmatch(match)
+		image, text, destination, title := parts[1], parts[2], parts[3], parts[4]
+		if !SafeURL(trimAngles(destination)) {
+			return text
+		}
+		if strings.HasPrefix(destination, "<") {
+			destination = strings.ReplaceAll(trimAngles(destination), " ", "%20")
+		}
+		return image + "[" + text + "](" + destination + title + ")"
+	})
+	s = linkDestination.ReplaceAllStringFunc(s, func(match string) string {
+		if !SafeURL(trimAngles(linkDestination.FindStringSubmatch(match)[1])) {
+			return "]("
+		}
+		return match
+	})
+
+	// Escape every '<' left over, keeping safe autolinks
+	b.Reset()
+	last = 0
+	for _, loc := range autolink.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(strings.ReplaceAll(s[last:loc[0]], "<", "&lt;"))
+		if SafeURL(s[loc[2]:loc[3]]) {
+			b.WriteString(protect(s[loc[0]:loc[1]]))
+		}
+		last = loc[1]
+	}
+	b.WriteString(strings.ReplaceAll(s[last:], "<", "&lt;"))
+
+	return restore(b.String(), protected)
+}
+
+// rawHTML returns the ranges of s that a renderer would read as raw HTML
+func rawHTML(s string) [][]int {
+	var ranges [][]int
+	for _, element := range dangerousElements {
+		ranges = append(ranges, element.FindAllStringIndex(s, -1)...)
+	}
+	ranges = append(ranges, htmlComment.FindAllStringIndex(s, -1)...)
+	return append(ranges, htmlTag.FindAllStringIndex(s, -1)...)
+}
+
+func insideAny(pos int, ranges [][]int) bool {
+	for _, r := range ranges {
+		if pos >= r[0] && pos < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// codeSpans returns the [start, end) ranges of inline code spans in s. Spans are kept to a
+// single line without table cell separators, since a renderer may split blocks or cells
+// before it looks for code.
+func codeSpans(s string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '\\':
+			i += 2
+		case '`':
+			n := runLength(s, i)
+			end := -1
+			for j := i + n; j < len(s) && s[j] != '\n' && s[j] != '|'; {
+				if s[j] != '`' {
+					j++
+					continue
+				}
+				m := runLength(s, j)
+				if m == n {
+					end = j + m
+					break
+				}
+				j += m
+			}
+			if end < 0 {
+				i += n
+				continue
+			}
+			spans = append(spans, [2]int{i, end})
+			i = end
+		default:
+			i++
+		}
+	}
+	return spans
+}
+
+// runLength counts the backticks starting at s[i]
+func runLength(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+func trimAngles(destination string) string {
+	if strings.HasPrefix(destination, "<") && strings.HasSuffix(destination, ">") {
+		return destination[1 : len(destination)-1]
+	}
+	return destination
+}
+
+// restore puts protected text back in place of its placeholders
+func restore(s string, protected []string) string {
+	for placeholder.MatchString(s) {
+		s = placeholder.ReplaceAllStringFunc(s, func(match string) string {
+			i, _ := strconv.Atoi(placeholder.FindStringSubmatch(match)[1])
+			return protected[i]
+		})
+	}
+	return s
+}
+
+// SafeURL reports whether a link destination is relative or uses the http, https or
+// mailto scheme, reading it the way a renderer and browser would: with backslash
+// escapes and character references decoded and control characters and spaces ignored
+func SafeURL(destination string) bool {
+	decoded := html.UnescapeString(backslashEscape.ReplaceAllString(destination, "$1"))
+	decoded = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, decoded)
+
+	end := strings.IndexAny(decoded, ":/?#")
+	if end < 0 || decoded[end] != ':' {
+		return true
+	}
+	switch strings.ToLower(decoded[:end]) {
+	case "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
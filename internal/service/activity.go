@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// namespaceLatestPageSize is the page size used when collecting a namespace's latest versions
+const namespaceLatestPageSize = 100
+
+// NamespaceUsage counts what has been published under a namespace, against the registry's limits
+type NamespaceUsage struct {
+	Servers              int `json:"servers" doc:"Servers with at least one version under the namespace"`
+	Versions             int `json:"versions" doc:"Versions of those servers, including deleted and pending ones"`
+	PendingVersions      int `json:"pending_versions" doc:"Versions held for admin approval"`
+	MaxVersionsPerServer int `json:"max_versions_per_server" doc:"Most versions the registry accepts for a single server"`
+}
+
+// NamespaceActivity is a publisher's overview of a namespace: a page of recently changed
+// versions, the current latest version of every server, and usage counts
+type NamespaceActivity struct {
+	// Recent lists versions published or changed at or after Since, oldest change first
+	Recent []apiv0.ServerSummary
+	// NextCursor continues Recent, or is empty on the last page
+	NextCursor string
+	// Latest lists the latest version of every server under the namespace, in ID order
+	Latest []apiv0.ServerSummary
+	Usage  NamespaceUsage
+	// Notifications is the number of publish notification registrations for the namespace
+	Notifications int
+}
+
+// NamespaceActivity composes the activity overview of a namespace. Recent is paginated
+// with cursor and limit; the other parts describe the namespace as a whole on every page.
+func (s *registryServiceImpl) NamespaceActivity(ctx context.Context, namespace string, since time.Time, cursor string, limit int) (*NamespaceActivity, error) {
+	recent, nextCursor, err := s.List(ctx, &database.ServerFilter{
+		Namespace:    &namespace,
+		UpdatedSince: &since,
+		Projection:   database.ProjectionSummary,
+	}, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := &NamespaceActivity{
+		Recent:     make([]apiv0.ServerSummary, 0, len(recent)),
+		NextCursor: nextCursor,
+		Latest:     []apiv0.ServerSummary{},
+	}
+	for i := range recent {
+		activity.Recent = append(activity.Recent, recent[i].Summary())
+	}
+
+	isLatest := true
+	latestFilter := &database.ServerFilter{Namespace: &namespace, IsLatest: &isLatest, Projection: database.ProjectionSummary}
+	latestCursor := ""
+	for {
+		servers, next, err := s.List(ctx, latestFilter, latestCursor, namespaceLatestPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for i := range servers {
+			activity.Latest = append(activity.Latest, servers[i].Summary())
+		}
+		if next == "" {
+			break
+		}
+		latestCursor = next
+	}
+
+	if activity.Usage, err = s.namespaceUsage(ctx, namespace); err != nil {
+		return nil, err
+	}
+	activity.Usage.Servers = len(activity.Latest)
+
+	notifications, err := s.db.ListNamespaceNotifications(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	activity.Notifications = len(notifications)
+
+	return activity, nil
+}
+
+// namespaceUsage counts the versions under a namespace; the caller fills in the server count
+func (s *registryServiceImpl) namespaceUsage(ctx context.Context, namespace string) (NamespaceUsage, error) {
+	usage := NamespaceUsage{MaxVersionsPerServer: maxServerVersionsPerServer}
+
+	versions, err := s.db.Count(ctx, &database.ServerFilter{Namespace: &namespace})
+	if err != nil {
+		return usage, err
+	}
+	usage.Versions = versions
+
+	pending := model.StatusPending
+	pendingVersions, err := s.db.Count(ctx, &database.ServerFilter{Namespace: &namespace, Status: &pending})
+	if err != nil {
+		return usage, err
+	}
+	usage.PendingVersions = pendingVersions
+
+	return usage, nil
+}
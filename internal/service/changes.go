@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// Actions of change feed events
+const (
+	// ChangePublished is sent when a version becomes public, on publish or when an admin approves it
+	ChangePublished = "published"
+	// ChangeUpdated is sent when a public version is edited, pinned or repaired
+	ChangeUpdated = "updated"
+	// ChangeDeleted is sent when a version is deleted, by an edit or the retention policy
+	ChangeDeleted = "deleted"
+)
+
+// changeSubscriberBuffer is how many events a subscriber may fall behind by before it is dropped
+const changeSubscriberBuffer = 64
+
+var (
+	// ErrChangeFeedDisabled is returned when subscribing to changes on a registry without a change feed
+	ErrChangeFeedDisabled = errors.New("the change feed is not enabled on this registry")
+	// ErrChangeFeedClosed is returned when subscribing to a change feed that has been closed for shutdown
+	ErrChangeFeedClosed = errors.New("the change feed is closed")
+)
+
+// ChangeEvent is a change to a public server version, as sent on the change feed
+type ChangeEvent struct {
+	// ID orders the events of one replica's feed, from 1; clients resume after it with Last-Event-ID
+	ID        uint64    `json:"id" doc:"Position of the event in this replica's change feed"`
+	Name      string    `json:"name" example:"io.github.octocat/weather"`
+	Version   string    `json:"version" example:"1.0.2"`
+	Action    string    `json:"action" enum:"published,updated,deleted"`
+	Timestamp time.Time `json:"timestamp"`
+
+	tenant string
+}
+
+// ChangeFeed passes changes to public server versions to subscribers as they happen, keeping
+// the most recent ones so that subscribers can resume after a brief disconnect. It lives in
+// memory: each replica only sees the changes it made itself, and event IDs start over when it
+// restarts.
+type ChangeFeed struct {
+	mu          sync.Mutex
+	buffer      []ChangeEvent // ring of the most recent events
+	next        int           // where in buffer the next event is written
+	lastID      uint64
+	subscribers map[*ChangeSubscription]struct{}
+	closed      bool
+}
+
+// NewChangeFeed creates a change feed keeping the last bufferSize events for resuming; 0 keeps none
+func NewChangeFeed(bufferSize int) *ChangeFeed {
+	return &ChangeFeed{
+		buffer:      make([]ChangeEvent, 0, max(bufferSize, 0)),
+		subscribers: make(map[*ChangeSubscription]struct{}),
+	}
+}
+
+// Publish numbers event and passes it to every subscriber allowed to see it. A subscriber that
+// has fallen changeSubscriberBuffer events behind is dropped rather than holding up the write;
+// it can resume from the buffer after reconnecting.
+func (f *ChangeFeed) Publish(event ChangeEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return
+	}
+
+	f.lastID++
+	event.ID = f.lastID
+	if cap(f.buffer) > 0 {
+		if len(f.buffer) < cap(f.buffer) {
+			f.buffer = append(f.buffer, event)
+		} else {
+			f.buffer[f.next] = event
+		}
+		f.next = (f.next + 1) % cap(f.buffer)
+	}
+
+	for sub := range f.subscribers {
+		if !sub.allows(event.tenant) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			f.end(sub)
+		}
+	}
+}
+
+// Subscribe starts passing events visible to ctx's tenant to a new subscription. With after
+// set to the ID of the last event a subscriber saw, the buffered events since then are returned
+// for it to send first; missed reports that some of them have already left the buffer, or that
+// after is from before this feed started, so the subscriber must catch up another way.
+func (f *ChangeFeed) Subscribe(ctx context.Context, after uint64) (sub *ChangeSubscription, backlog []ChangeEvent, missed bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil, nil, false, ErrChangeFeedClosed
+	}
+
+	sub = &ChangeSubscription{
+		feed:   f,
+		allows: func(tenant string) bool { return tenancy.Allows(ctx, tenant) },
+		events: make(chan ChangeEvent, changeSubscriberBuffer),
+		done:   make(chan struct{}),
+	}
+	f.subscribers[sub] = struct{}{}
+
+	if after == 0 {
+		return sub, nil, false, nil
+	}
+	if after > f.lastID {
+		return sub, nil, true, nil
+	}
+	// Buffered events run oldest first from next once the ring has wrapped, and from 0 before
+	oldest := f.lastID - uint64(len(f.buffer)) + 1
+	missed = after+1 < oldest
+	for i := range f.buffer {
+		event := f.buffer[(f.next+i)%len(f.buffer)]
+		if event.ID > after && sub.allows(event.tenant) {
+			backlog = append(backlog, event)
+		}
+	}
+	return sub, backlog, missed, nil
+}
+
+// Close ends every subscription and refuses new ones, so that streams do not hold up shutdown
+func (f *ChangeFeed) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for sub := range f.subscribers {
+		f.end(sub)
+	}
+}
+
+// end removes sub from the feed and signals it is done; the caller holds f.mu
+func (f *ChangeFeed) end(sub *ChangeSubscription) {
+	if _, ok := f.subscribers[sub]; ok {
+		delete(f.subscribers, sub)
+		close(sub.done)
+	}
+}
+
+// ChangeSubscription receives the events of a change feed until it is closed, dropped for
+// falling behind, or the feed is closed
+type ChangeSubscription struct {
+	feed   *ChangeFeed
+	allows func(tenant string) bool
+	events chan ChangeEvent
+	done   chan struct{}
+}
+
+// Events delivers the subscription's events in order
+func (s *ChangeSubscription) Events() <-chan ChangeEvent {
+	return s.events
+}
+
+// Done is closed when the subscription stops receiving events
+func (s *ChangeSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close stops the subscription
+func (s *ChangeSubscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.feed.end(s)
+}
+
+// WithChangeFeed publishes every change to public server versions on feed
+func WithChangeFeed(feed *ChangeFeed) Option {
+	return func(s *registryServiceImpl) {
+		s.changes = feed
+	}
+}
+
+// SubscribeChanges subscribes to the changes of public server versions visible to ctx's tenant,
+// as ChangeFeed.Subscribe does
+func (s *registryServiceImpl) SubscribeChanges(ctx context.Context, after uint64) (*ChangeSubscription, []ChangeEvent, bool, error) {
+	if s.changes == nil {
+		return nil, nil, false, ErrChangeFeedDisabled
+	}
+	return s.changes.Subscribe(ctx, after)
+}
+
+// recordChange publishes a committed change to server on the change feed, if one is configured.
+// Versions held for review or rejected are not public, so their changes are never announced.
+func (s *registryServiceImpl) recordChange(action string, server *apiv0.ServerJSON) {
+	if s.changes == nil || server == nil || server.Status.Hidden() {
+		return
+	}
+	s.changes.Publish(ChangeEvent{
+		Name:      server.Name,
+		Version:   server.Version,
+		Action:    action,
+		Timestamp: time.Now(),
+		tenant:    tenancy.Of(server),
+	})
+}
+
+// editChange is the change feed action of an edit that changed a version's status from previous to current
+func editChange(previous, current model.Status) string {
+	if current == model.StatusDeleted && previous != model.StatusDeleted {
+		return ChangeDeleted
+	}
+	return ChangeUpdated
+}
@@ -0,0 +1,159 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// changeIDs returns the IDs of events
+func changeIDs(events []ChangeEvent) []uint64 {
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestChangeFeed_Resume(t *testing.T) {
+	ctx := context.Background()
+	feed := NewChangeFeed(3)
+	for range 5 {
+		feed.Publish(ChangeEvent{Name: "com.example/weather", Action: ChangeUpdated})
+	}
+
+	tests := []struct {
+		name       string
+		after      uint64
+		wantIDs    []uint64
+		wantMissed bool
+	}{
+		{name: "new subscriber", after: 0, wantIDs: []uint64{}},
+		{name: "resumes from the buffer", after: 3, wantIDs: []uint64{4, 5}},
+		{name: "oldest buffered event is next", after: 2, wantIDs: []uint64{3, 4, 5}},
+		{name: "events left the buffer", after: 1, wantIDs: []uint64{3, 4, 5}, wantMissed: true},
+		{name: "up to date", after: 5, wantIDs: []uint64{}},
+		{name: "ID from before a restart", after: 9, wantIDs: []uint64{}, wantMissed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, backlog, missed, err := feed.Subscribe(ctx, tt.after)
+			require.NoError(t, err)
+			defer sub.Close()
+			assert.Equal(t, tt.wantIDs, changeIDs(backlog))
+			assert.Equal(t, tt.wantMissed, missed)
+		})
+	}
+}
+
+func TestChangeFeed_Subscribers(t *testing.T) {
+	feed := NewChangeFeed(10)
+
+	t.Run("events are delivered to tenants that may see them", func(t *testing.T) {
+		all, _, _, err := feed.Subscribe(context.Background(), 0)
+		require.NoError(t, err)
+		defer all.Close()
+		acme, _, _, err := feed.Subscribe(tenancy.WithTenant(context.Background(), "acme"), 0)
+		require.NoError(t, err)
+		defer acme.Close()
+
+		feed.Publish(ChangeEvent{Name: "com.other/weather", tenant: "other"})
+		feed.Publish(ChangeEvent{Name: "com.acme/weather", tenant: "acme"})
+
+		assert.Equal(t, "com.other/weather", (<-all.Events()).Name)
+		assert.Equal(t, "com.acme/weather", (<-all.Events()).Name)
+		assert.Equal(t, "com.acme/weather", (<-acme.Events()).Name)
+		assert.Empty(t, acme.Events())
+	})
+
+	t.Run("slow subscribers are dropped", func(t *testing.T) {
+		slow, _, _, err := feed.Subscribe(context.Background(), 0)
+		require.NoError(t, err)
+		for range changeSubscriberBuffer + 1 {
+			feed.Publish(ChangeEvent{Name: "com.example/weather"})
+		}
+		select {
+		case <-slow.Done():
+		default:
+			t.Fatal("a subscriber that fell behind was not dropped")
+		}
+		slow.Close() // closing again is harmless
+	})
+
+	t.Run("closing the feed ends subscriptions", func(t *testing.T) {
+		sub, _, _, err := feed.Subscribe(context.Background(), 0)
+		require.NoError(t, err)
+		feed.Close()
+		<-sub.Done()
+
+		_, _, _, err = feed.Subscribe(context.Background(), 0)
+		assert.ErrorIs(t, err, ErrChangeFeedClosed)
+	})
+}
+
+func TestRegistryService_RecordsChanges(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	feed := NewChangeFeed(100)
+	s := NewRegistryService(db, &config.Config{}, WithChangeFeed(feed))
+	sub, _, _, err := s.SubscribeChanges(ctx, 0)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	next := func() ChangeEvent {
+		t.Helper()
+		select {
+		case event := <-sub.Events():
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("no change event")
+			return ChangeEvent{}
+		}
+	}
+
+	published, err := s.Publish(ctx, apiv0.ServerJSON{Name: "com.example/weather", Description: "Weather lookups", Version: "1.0.0"})
+	require.NoError(t, err)
+	event := next()
+	assert.Equal(t, uint64(1), event.ID)
+	assert.Equal(t, "com.example/weather", event.Name)
+	assert.Equal(t, "1.0.0", event.Version)
+	assert.Equal(t, ChangePublished, event.Action)
+	assert.False(t, event.Timestamp.IsZero())
+
+	edit := *published
+	edit.Meta = nil
+	edit.Description = "Forecasts"
+	_, err = s.EditServer(ctx, published.Meta.Official.ID, edit)
+	require.NoError(t, err)
+	assert.Equal(t, ChangeUpdated, next().Action)
+
+	edit.Status = model.StatusDeleted
+	_, err = s.EditServer(ctx, published.Meta.Official.ID, edit)
+	require.NoError(t, err)
+	assert.Equal(t, ChangeDeleted, next().Action)
+
+	// Held versions are announced when they are approved, not when they are published
+	held := seedVersion(t, db, "com.example/held", "1.0.0", time.Now(), true, model.StatusPending)
+	assert.Empty(t, sub.Events())
+	_, err = s.ApprovePending(ctx, held)
+	require.NoError(t, err)
+	event = next()
+	assert.Equal(t, "com.example/held", event.Name)
+	assert.Equal(t, ChangePublished, event.Action)
+}
+
+func TestRegistryService_ChangeFeedDisabled(t *testing.T) {
+	s := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+	_, _, _, err := s.SubscribeChanges(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrChangeFeedDisabled)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ErrVersionNotNewer is returned by Publish for a conditional publish whose version is not newer
+// than the server's latest
+var ErrVersionNotNewer = errors.New("version is not newer than the latest version")
+
+type ifVersionNewerKey struct{}
+
+// WithIfVersionNewer marks ctx as a conditional publish: Publish fails with ErrVersionNotNewer,
+// before validating anything else, rather than publish a version that would not become the latest
+func WithIfVersionNewer(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ifVersionNewerKey{}, true)
+}
+
+// checkVersionNewer returns ErrVersionNotNewer if ctx is a conditional publish and version is
+// equal to or older than the latest version of name
+func (s *registryServiceImpl) checkVersionNewer(ctx context.Context, name, version string, publishTime time.Time) error {
+	if conditional, _ := ctx.Value(ifVersionNewerKey{}).(bool); !conditional {
+		return nil
+	}
+
+	latest, err := s.latestByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !newerThanLatest(version, publishTime, latest) {
+		return fmt.Errorf("%w: %s is not newer than %s", ErrVersionNotNewer, version, latest.Version)
+	}
+	return nil
+}
+
+// newerThanLatest reports whether version, published at publishTime, becomes the latest version
+// instead of latest. It is true for a server's first version.
+func newerThanLatest(version string, publishTime time.Time, latest *apiv0.ServerJSON) bool {
+	if latest == nil {
+		return true
+	}
+	if version == latest.Version {
+		return false
+	}
+	var latestPublishedAt time.Time
+	if latest.Meta != nil && latest.Meta.Official != nil {
+		latestPublishedAt = latest.Meta.Official.PublishedAt
+	}
+	return CompareVersions(version, latest.Version, publishTime, latestPublishedAt) > 0
+}
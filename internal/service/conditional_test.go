@@ -0,0 +1,55 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestPublish_IfVersionNewer(t *testing.T) {
+	tests := []struct {
+		name      string
+		published []string // versions published before the conditional publish
+		version   string
+		skipped   bool
+	}{
+		{name: "first publish", version: "1.0.0"},
+		{name: "newer version", published: []string{"1.0.0"}, version: "1.0.1"},
+		{name: "equal version", published: []string{"1.0.0"}, version: "1.0.0", skipped: true},
+		{name: "older version", published: []string{"1.0.0", "2.0.0"}, version: "1.5.0", skipped: true},
+		{name: "pre-release of the latest", published: []string{"2.0.0"}, version: "2.0.0-rc.1", skipped: true},
+		{name: "release of a pre-release", published: []string{"2.0.0-rc.1"}, version: "2.0.0"},
+		{name: "semver after a non-semver version", published: []string{"nightly-2025"}, version: "0.1.0"},
+		{name: "non-semver after a semver version", published: []string{"1.0.0"}, version: "nightly-2025", skipped: true},
+		{name: "non-semver after a non-semver version", published: []string{"nightly-1"}, version: "nightly-2"},
+		{name: "equal non-semver version", published: []string{"nightly-1"}, version: "nightly-1", skipped: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+			server := apiv0.ServerJSON{Name: "com.example/nightly", Description: "Rebuilt every night"}
+			for _, version := range tt.published {
+				server.Version = version
+				_, err := svc.Publish(ctx, server)
+				require.NoError(t, err)
+			}
+
+			server.Version = tt.version
+			published, err := svc.Publish(WithIfVersionNewer(ctx), server)
+			if tt.skipped {
+				assert.ErrorIs(t, err, ErrVersionNotNewer)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, published.Meta.Official.IsLatest, "a version that is not skipped becomes the latest")
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// ServerFetches is how often the details of one server were fetched, per day
+type ServerFetches struct {
+	Name  string         `json:"name"`
+	Total int64          `json:"total" doc:"Fetches over the whole window"`
+	Days  []DailyFetches `json:"days" doc:"Fetches per day, oldest first, leaving out days without any"`
+}
+
+// DailyFetches is how often a server's details were fetched on one day
+type DailyFetches struct {
+	Date    string `json:"date" doc:"Day in UTC" format:"date" example:"2025-08-07"`
+	Fetches int64  `json:"fetches"`
+}
+
+// RecordFetch counts a fetch of the server version with the given ID, if fetches are counted
+func (s *registryServiceImpl) RecordFetch(ctx context.Context, id string) {
+	if s.fetches != nil {
+		s.fetches.Record(ctx, id, time.Now())
+	}
+}
+
+// NamespaceFetches groups the daily fetch counts of a namespace by server, in name order
+func (s *registryServiceImpl) NamespaceFetches(ctx context.Context, namespace string, from, to time.Time) ([]ServerFetches, error) {
+	counts, err := s.db.ListServerFetchCounts(ctx, namespace, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*ServerFetches)
+	servers := []ServerFetches{}
+	for _, count := range counts {
+		server, ok := byName[count.ServerName]
+		if !ok {
+			server = &ServerFetches{Name: count.ServerName}
+			byName[count.ServerName] = server
+		}
+		server.Total += count.Count
+		server.Days = append(server.Days, DailyFetches{Date: count.Day.Format(time.DateOnly), Fetches: count.Count})
+	}
+	for _, server := range byName {
+		servers = append(servers, *server)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+
+	return servers, nil
+}
+
+// Results of attributing a fetch of server details, as exported in metrics
+const (
+	FetchCounted      = "counted"
+	FetchDropped      = "dropped"
+	FetchUnattributed = "unattributed"
+)
+
+const (
+	// fetchQueueSize bounds the fetches waiting to be attributed; more are dropped
+	fetchQueueSize = 4096
+	// fetchBatchSize is how many distinct server days are collected before a flush is forced
+	fetchBatchSize = 500
+	// fetchServerMemoSize bounds the remembered version ID to server lookups
+	fetchServerMemoSize = 10000
+	// fetchLookupTimeout bounds the lookup of the server a version ID belongs to
+	fetchLookupTimeout = 5 * time.Second
+	// fetchFlushTimeout bounds writing a batch of counts
+	fetchFlushTimeout = 30 * time.Second
+)
+
+// fetch is a successful request for the details of a server version
+type fetch struct {
+	id string
+	at time.Time
+}
+
+// fetchServer is the server a version ID belongs to
+type fetchServer struct {
+	tenant, namespace, name string
+}
+
+// fetchBucket identifies the count of a server on a day
+type fetchBucket struct {
+	server fetchServer
+	day    time.Time
+}
+
+// FetchRecorder counts fetches of server details per server and day, for the metrics publishers
+// see of their namespaces. Recording a fetch only queues its version ID, so it never slows the
+// request down: the server it belongs to is looked up in the background, and the counts are
+// added to the database in batches.
+type FetchRecorder struct {
+	db            database.Database
+	flushInterval time.Duration
+	metrics       *telemetry.Metrics
+
+	fetches chan fetch
+	// servers and pending are only used by Run
+	servers map[string]fetchServer
+	pending map[fetchBucket]int64
+}
+
+// NewFetchRecorder creates a recorder writing its counts to db every flushInterval. metrics may be nil.
+func NewFetchRecorder(db database.Database, flushInterval time.Duration, metrics *telemetry.Metrics) *FetchRecorder {
+	return &FetchRecorder{
+		db:            db,
+		flushInterval: flushInterval,
+		metrics:       metrics,
+		fetches:       make(chan fetch, fetchQueueSize),
+		servers:       make(map[string]fetchServer),
+		pending:       make(map[fetchBucket]int64),
+	}
+}
+
+// Record queues a fetch of the server version with the given ID. It never blocks: when the queue
+// is full the fetch is dropped.
+func (r *FetchRecorder) Record(ctx context.Context, id string, at time.Time) {
+	select {
+	case r.fetches <- fetch{id: id, at: at}:
+	default:
+		r.count(ctx, FetchDropped, 1)
+	}
+}
+
+// Run attributes queued fetches and flushes the counts every flush interval, or sooner once a
+// batch fills up, until ctx is cancelled. What is queued then is flushed before it returns.
+func (r *FetchRecorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Shutdown gets a little longer to attribute what is queued and write it out
+			shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchFlushTimeout)
+			defer cancel()
+			r.drain(shutdownCtx)
+			r.flush(shutdownCtx)
+			return
+		case f := <-r.fetches:
+			r.attribute(ctx, f)
+			if len(r.pending) >= fetchBatchSize {
+				r.flush(ctx)
+			}
+		case <-ticker.C:
+			r.flush(ctx)
+		}
+	}
+}
+
+// drain attributes the fetches still queued, until the queue is empty or ctx is done
+func (r *FetchRecorder) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		select {
+		case f := <-r.fetches:
+			r.attribute(ctx, f)
+		default:
+			return
+		}
+	}
+}
+
+// attribute adds a fetch to the pending count of its server
+func (r *FetchRecorder) attribute(ctx context.Context, f fetch) {
+	server, err := r.lookup(ctx, f.id)
+	if err != nil {
+		if !errors.Is(err, database.ErrNotFound) && ctx.Err() == nil {
+			log.Printf("Failed to attribute fetch of server %s: %v", f.id, err)
+		}
+		r.count(ctx, FetchUnattributed, 1)
+		return
+	}
+	r.pending[fetchBucket{server: server, day: f.at.UTC().Truncate(24 * time.Hour)}]++
+}
+
+// lookup returns the server a version ID belongs to, which never changes once published
+func (r *FetchRecorder) lookup(ctx context.Context, id string) (fetchServer, error) {
+	if server, ok := r.servers[id]; ok {
+		return server, nil
+	}
+
+	// A fetch taken off the queue as Run is stopped is still attributed
+	lookupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchLookupTimeout)
+	defer cancel()
+	// The context carries no tenant, so versions of every tenant are found
+	serverJSON, err := r.db.GetByID(lookupCtx, id)
+	if err != nil {
+		return fetchServer{}, err
+	}
+
+	namespace, _, _ := strings.Cut(serverJSON.Name, "/")
+	server := fetchServer{namespace: namespace, name: serverJSON.Name}
+	if serverJSON.Meta != nil && serverJSON.Meta.Official != nil {
+		server.tenant = serverJSON.Meta.Official.Tenant
+	}
+	if len(r.servers) >= fetchServerMemoSize {
+		clear(r.servers)
+	}
+	r.servers[id] = server
+	return server, nil
+}
+
+// flush adds the pending counts to the database. A batch that fails to write is dropped rather
+// than retried, so a database outage cannot grow it without bound.
+func (r *FetchRecorder) flush(ctx context.Context) {
+	if len(r.pending) == 0 {
+		return
+	}
+
+	counts := make([]database.ServerFetchCount, 0, len(r.pending))
+	var fetches int64
+	for bucket, count := range r.pending {
+		counts = append(counts, database.ServerFetchCount{
+			Tenant:     bucket.server.tenant,
+			Namespace:  bucket.server.namespace,
+			ServerName: bucket.server.name,
+			Day:        bucket.day,
+			Count:      count,
+		})
+		fetches += count
+	}
+	clear(r.pending)
+
+	flushCtx, cancel := context.WithTimeout(ctx, fetchFlushTimeout)
+	defer cancel()
+	if err := r.db.AddServerFetchCounts(flushCtx, counts); err != nil {
+		log.Printf("Failed to write %d server fetch counts: %v", len(counts), err)
+		r.count(ctx, FetchDropped, fetches)
+		return
+	}
+	r.count(ctx, FetchCounted, fetches)
+}
+
+func (r *FetchRecorder) count(ctx context.Context, result string, n int64) {
+	if r.metrics == nil {
+		return
+	}
+	r.metrics.FetchAttributions.Add(context.WithoutCancel(ctx), n, metric.WithAttributes(attribute.String("result", result)))
+}
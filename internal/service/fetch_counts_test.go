@@ -0,0 +1,157 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// batchRecordingDB records the batches of fetch counts written to it
+type batchRecordingDB struct {
+	database.Database
+	mu      sync.Mutex
+	batches [][]database.ServerFetchCount
+}
+
+func (db *batchRecordingDB) AddServerFetchCounts(ctx context.Context, counts []database.ServerFetchCount) error {
+	db.mu.Lock()
+	db.batches = append(db.batches, counts)
+	db.mu.Unlock()
+	return db.Database.AddServerFetchCounts(ctx, counts)
+}
+
+func (db *batchRecordingDB) batchCount() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return len(db.batches)
+}
+
+// seedFetchServer stores a version of a server under tenant and returns its ID
+func seedFetchServer(t *testing.T, db database.Database, seq int, name, tenant string) string {
+	t.Helper()
+	id := fmt.Sprintf("00000000-0000-0000-0000-%012d", seq)
+	now := time.Now()
+	ctx := context.Background()
+	if tenant != "" {
+		ctx = tenancy.WithTenant(ctx, tenant)
+	}
+	_, err := db.CreateServer(ctx, &apiv0.ServerJSON{
+		Name: name, Description: "A test server", Version: "1.0.0",
+		Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{ID: id, PublishedAt: now, UpdatedAt: now, IsLatest: true, Tenant: tenant}},
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestFetchRecorder_Attribution(t *testing.T) {
+	ctx := context.Background()
+	db := &batchRecordingDB{Database: database.NewMemoryDB()}
+	weather := seedFetchServer(t, db, 1, "io.github.octocat/weather", "")
+	docs := seedFetchServer(t, db, 2, "io.github.octocat/docs", "")
+	acme := seedFetchServer(t, db, 3, "io.github.octocat/weather", "acme")
+
+	recorder := NewFetchRecorder(db, time.Hour, nil)
+	day := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	recorder.Record(ctx, weather, day)
+	recorder.Record(ctx, weather, day.Add(10*time.Hour))
+	recorder.Record(ctx, weather, day.AddDate(0, 0, 1))
+	recorder.Record(ctx, docs, day)
+	recorder.Record(ctx, acme, day)
+	recorder.Record(ctx, "00000000-0000-0000-0000-999999999999", day)
+
+	// Stopping the recorder flushes what it has attributed
+	stopped, stop := context.WithCancel(ctx)
+	stop()
+	recorder.Run(stopped)
+
+	require.Equal(t, 1, db.batchCount(), "the fetches are written in one batch")
+	counts, err := db.ListServerFetchCounts(ctx, "io.github.octocat", day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	midnight := day.Truncate(24 * time.Hour)
+	assert.Equal(t, []database.ServerFetchCount{
+		{Namespace: "io.github.octocat", ServerName: "io.github.octocat/docs", Day: midnight, Count: 1},
+		{Namespace: "io.github.octocat", ServerName: "io.github.octocat/weather", Day: midnight, Count: 2},
+		{Namespace: "io.github.octocat", ServerName: "io.github.octocat/weather", Day: midnight.AddDate(0, 0, 1), Count: 1},
+	}, counts, "fetches of unknown versions are not attributed")
+
+	counts, err = db.ListServerFetchCounts(tenancy.WithTenant(ctx, "acme"), "io.github.octocat", day, day)
+	require.NoError(t, err)
+	assert.Equal(t, []database.ServerFetchCount{
+		{Tenant: "acme", Namespace: "io.github.octocat", ServerName: "io.github.octocat/weather", Day: midnight, Count: 1},
+	}, counts, "fetches count for the tenant of the version")
+}
+
+func TestFetchRecorder_Batching(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("flushes every interval", func(t *testing.T) {
+		db := &batchRecordingDB{Database: database.NewMemoryDB()}
+		id := seedFetchServer(t, db, 1, "io.github.octocat/weather", "")
+		recorder := NewFetchRecorder(db, 10*time.Millisecond, nil)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			recorder.Run(runCtx)
+			close(done)
+		}()
+		for range 5 {
+			recorder.Record(ctx, id, time.Now())
+		}
+		require.Eventually(t, func() bool { return db.batchCount() > 0 }, 5*time.Second, 5*time.Millisecond)
+		cancel()
+		<-done
+
+		counts, err := db.ListServerFetchCounts(ctx, "io.github.octocat", time.Now(), time.Now())
+		require.NoError(t, err)
+		require.Len(t, counts, 1)
+		assert.Equal(t, int64(5), counts[0].Count)
+		assert.Less(t, db.batchCount(), 5, "fetches are written in batches, not one at a time")
+	})
+
+	t.Run("flushes a full batch early", func(t *testing.T) {
+		db := &batchRecordingDB{Database: database.NewMemoryDB()}
+		id := seedFetchServer(t, db, 1, "io.github.octocat/weather", "")
+		recorder := NewFetchRecorder(db, time.Hour, nil)
+
+		// Each day is its own bucket, so a batch fills up with fetches of one server
+		start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := range fetchBatchSize {
+			recorder.attribute(ctx, fetch{id: id, at: start.AddDate(0, 0, i)})
+		}
+		assert.Len(t, recorder.pending, fetchBatchSize)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			recorder.Run(runCtx)
+			close(done)
+		}()
+		recorder.Record(ctx, id, start)
+		require.Eventually(t, func() bool { return db.batchCount() == 1 }, 5*time.Second, 5*time.Millisecond)
+		cancel()
+		<-done
+		assert.Equal(t, 1, db.batchCount())
+	})
+
+	t.Run("drops fetches when the queue is full", func(t *testing.T) {
+		db := &batchRecordingDB{Database: database.NewMemoryDB()}
+		recorder := NewFetchRecorder(db, time.Hour, nil)
+		start := time.Now()
+		for range fetchQueueSize + 10 {
+			recorder.Record(ctx, "00000000-0000-0000-0000-000000000001", time.Now())
+		}
+		assert.Less(t, time.Since(start), time.Second, "recording never waits for the recorder")
+		assert.Len(t, recorder.fetches, fetchQueueSize)
+	})
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// fieldUsagePageSize is the page size used when walking the latest server versions for field
+// usage; only one page is held at a time
+var fieldUsagePageSize = 500
+
+// FieldUsage reports how often the latest server versions set each field of server.json, to
+// show which fields are used widely enough to keep
+type FieldUsage struct {
+	GeneratedAt time.Time `json:"generated_at" doc:"When the latest server versions were walked"`
+	Servers     int       `json:"servers" doc:"Number of latest server versions walked, not counting deleted ones"`
+	// Fields is keyed by JSON path, with [] for the elements of an array and * for the values of
+	// an object keyed by name, as in packages[].runtime_arguments[].variables.*.is_secret
+	Fields map[string]FieldCount `json:"fields" doc:"Usage of each field, keyed by JSON path; [] stands for array elements and * for the values of a map"`
+}
+
+// FieldCount is the usage of one field of server.json
+type FieldCount struct {
+	Count   int     `json:"count" doc:"How many of the objects the field belongs to set it"`
+	Total   int     `json:"total" doc:"How many objects the field belongs to were walked, such as every argument for an argument field"`
+	Percent float64 `json:"percent" doc:"Count as a percentage of total, to one decimal place"`
+}
+
+// fieldUsageCache keeps the last field usage report of the whole registry
+type fieldUsageCache struct {
+	mu     sync.Mutex
+	report *FieldUsage
+}
+
+// FieldUsage walks the latest version of every server and counts the fields they set, page by
+// page, reusing a registry-wide report younger than maxAge. Counts are limited to the tenant of
+// a scoped ctx, whose reports are never reused.
+func (s *registryServiceImpl) FieldUsage(ctx context.Context, maxAge time.Duration) (*FieldUsage, error) {
+	_, scoped := tenancy.FromContext(ctx)
+	if !scoped && maxAge > 0 {
+		s.fieldUsage.mu.Lock()
+		report := s.fieldUsage.report
+		s.fieldUsage.mu.Unlock()
+		if report != nil && time.Since(report.GeneratedAt) < maxAge {
+			return report, nil
+		}
+	}
+
+	counter := newFieldUsageCounter()
+	isLatest := true
+	filter := &database.ServerFilter{IsLatest: &isLatest, ExcludeHidden: true}
+	servers := 0
+	cursor := ""
+	for {
+		page, nextCursor, err := s.db.List(ctx, filter, cursor, fieldUsagePageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, server := range page {
+			if server.Status == model.StatusDeleted {
+				continue
+			}
+			counter.walk(reflect.ValueOf(server).Elem(), "")
+			servers++
+		}
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	report := &FieldUsage{GeneratedAt: time.Now(), Servers: servers, Fields: counter.result()}
+	if !scoped {
+		s.fieldUsage.mu.Lock()
+		s.fieldUsage.report = report
+		s.fieldUsage.mu.Unlock()
+	}
+	return report, nil
+}
+
+// fieldUsageCounter counts the fields set in server documents by reflecting on their types, so
+// fields added to server.json are counted without changes here
+type fieldUsageCounter struct {
+	counts map[string]*FieldCount
+}
+
+func newFieldUsageCounter() *fieldUsageCounter {
+	c := &fieldUsageCounter{counts: map[string]*FieldCount{}}
+	// Every field is reported, including those no server sets
+	c.register(reflect.TypeOf(apiv0.ServerJSON{}), "", map[reflect.Type]bool{})
+	return c
+}
+
+// jsonFields calls fn with each field of struct type t and its JSON name, flattening inline
+// structs into their parent the way encoding/json does
+func jsonFields(t reflect.Type, index []int, fn func(field reflect.StructField, index []int, name string)) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			jsonFields(field.Type, fieldIndex, fn)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fn(field, fieldIndex, name)
+	}
+}
+
+// nested returns the struct type that a field of type t holds, and the path segment between the
+// field and the struct's fields, or nil if the field is a plain value
+func nested(t reflect.Type) (reflect.Type, string) {
+	suffix := "."
+	for {
+		switch t.Kind() {
+		case reflect.Pointer:
+			t = t.Elem()
+			continue
+		case reflect.Slice, reflect.Array:
+			t, suffix = t.Elem(), suffix[:len(suffix)-1]+"[]."
+			continue
+		case reflect.Map:
+			t, suffix = t.Elem(), suffix+"*."
+			continue
+		case reflect.Struct:
+			if t == reflect.TypeOf(time.Time{}) {
+				return nil, ""
+			}
+			return t, suffix
+		default:
+			return nil, ""
+		}
+	}
+}
+
+// register adds a zero count for every field path of struct type t
+func (c *fieldUsageCounter) register(t reflect.Type, prefix string, seen map[reflect.Type]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	jsonFields(t, nil, func(field reflect.StructField, _ []int, name string) {
+		path := prefix + name
+		c.counts[path] = &FieldCount{}
+		if skipFieldUsage(path) {
+			return
+		}
+		if elem, suffix := nested(field.Type); elem != nil {
+			c.register(elem, path+suffix, seen)
+		}
+	})
+}
+
+// skipFieldUsage reports whether the fields under path are left uncounted: the _meta object is
+// written by the registry and by publishers' own tools, not described by server.json
+func skipFieldUsage(path string) bool {
+	return path == "_meta"
+}
+
+// walk counts the fields of struct value v, found at prefix
+func (c *fieldUsageCounter) walk(v reflect.Value, prefix string) {
+	jsonFields(v.Type(), nil, func(_ reflect.StructField, index []int, name string) {
+		path := prefix + name
+		count := c.counts[path]
+		count.Total++
+		value := v.FieldByIndex(index)
+		if value.IsZero() || ((value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.Len() == 0) {
+			return
+		}
+		count.Count++
+		if !skipFieldUsage(path) {
+			c.descend(value, path+".")
+		}
+	})
+}
+
+// descend walks the structs held by value, found at prefix
+func (c *fieldUsageCounter) descend(value reflect.Value, prefix string) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			c.descend(value.Elem(), prefix)
+		}
+	case reflect.Slice, reflect.Array:
+		prefix = strings.TrimSuffix(prefix, ".") + "[]."
+		for i := range value.Len() {
+			c.descend(value.Index(i), prefix)
+		}
+	case reflect.Map:
+		for iter := value.MapRange(); iter.Next(); {
+			c.descend(iter.Value(), prefix+"*.")
+		}
+	case reflect.Struct:
+		if value.Type() != reflect.TypeOf(time.Time{}) {
+			c.walk(value, prefix)
+		}
+	default:
+	}
+}
+
+// result returns the counts with their percentages
+func (c *fieldUsageCounter) result() map[string]FieldCount {
+	fields := make(map[string]FieldCount, len(c.counts))
+	for path, count := range c.counts {
+		field := *count
+		if field.Total > 0 {
+			field.Percent = math.Round(float64(field.Count)*1000/float64(field.Total)) / 10
+		}
+		fields[path] = field
+	}
+	return fields
+}
+
+// FieldUsageJob periodically refreshes the field usage report served to admins
+type FieldUsageJob struct {
+	registry RegistryService
+	interval time.Duration
+}
+
+// NewFieldUsageJob creates a job that walks the latest server versions every interval
+func NewFieldUsageJob(registry RegistryService, interval time.Duration) *FieldUsageJob {
+	return &FieldUsageJob{
+		registry: registry,
+		interval: interval,
+	}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *FieldUsageJob) Start(ctx context.Context) {
+	go j.Run(ctx)
+}
+
+// Run runs the job until ctx is cancelled, returning once a run in progress has stopped
+func (j *FieldUsageJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.runOnce(ctx)
+		}
+	}
+}
+
+func (j *FieldUsageJob) runOnce(ctx context.Context) {
+	report, err := j.registry.FieldUsage(ctx, 0)
+	if err != nil {
+		log.Printf("Field usage job failed: %v", err)
+		return
+	}
+	log.Printf("Field usage job counted the fields of %d servers", report.Servers)
+}
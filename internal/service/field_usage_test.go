@@ -0,0 +1,102 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pageRecordingDB records the page sizes servers are listed with
+type pageRecordingDB struct {
+	database.Database
+	limits []int
+}
+
+func (db *pageRecordingDB) List(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerJSON, string, error) {
+	db.limits = append(db.limits, limit)
+	return db.Database.List(ctx, filter, cursor, limit)
+}
+
+func TestFieldUsage(t *testing.T) {
+	ctx := context.Background()
+	memory := database.NewMemoryDB()
+	db := &pageRecordingDB{Database: memory}
+	svc := NewRegistryService(db, &config.Config{})
+
+	publish := func(name, version string, latest bool, status model.Status, edit func(*apiv0.ServerJSON)) {
+		t.Helper()
+		id := seedVersion(t, memory, name, version, time.Now(), latest, status)
+		if edit == nil {
+			return
+		}
+		server, err := memory.GetByID(ctx, id)
+		require.NoError(t, err)
+		edit(server)
+		_, err = memory.UpdateServer(ctx, id, server)
+		require.NoError(t, err)
+	}
+	publish("com.example/cli", "1.0.0", true, model.StatusActive, func(s *apiv0.ServerJSON) {
+		s.Packages = []model.Package{{
+			RegistryType: "npm", Identifier: "@example/cli", Version: "1.0.0",
+			RuntimeArguments: []model.Argument{
+				{Type: model.ArgumentTypeNamed, Name: "--port", ValueHint: "port"},
+				{Type: model.ArgumentTypePositional, IsRepeated: true, InputWithVariables: model.InputWithVariables{
+					Variables: map[string]model.Input{"dir": {IsSecret: true}, "mode": {}},
+				}},
+			},
+			EnvironmentVariables: []model.KeyValueInput{{Name: "TOKEN", InputWithVariables: model.InputWithVariables{Input: model.Input{IsSecret: true}}}},
+		}}
+	})
+	publish("com.example/remote", "1.0.0", true, model.StatusActive, func(s *apiv0.ServerJSON) {
+		s.Title = "Remote"
+		s.Remotes = []model.Transport{{Type: "sse", URL: "https://remote.example.com/sse"}}
+	})
+	publish("com.example/plain", "1.0.0", true, model.StatusActive, nil)
+	// Older and deleted versions are not counted
+	publish("com.example/plain", "0.9.0", false, model.StatusActive, func(s *apiv0.ServerJSON) { s.Title = "Old" })
+	publish("com.example/gone", "1.0.0", true, model.StatusDeleted, func(s *apiv0.ServerJSON) { s.Title = "Gone" })
+
+	// Servers are read a page at a time
+	defer func(pageSize int) { fieldUsagePageSize = pageSize }(fieldUsagePageSize)
+	fieldUsagePageSize = 2
+
+	report, err := svc.FieldUsage(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Servers)
+	require.Greater(t, len(db.limits), 1)
+	for _, limit := range db.limits {
+		assert.Equal(t, 2, limit)
+	}
+
+	assert.Equal(t, FieldCount{Count: 3, Total: 3, Percent: 100}, report.Fields["name"])
+	assert.Equal(t, FieldCount{Count: 1, Total: 3, Percent: 33.3}, report.Fields["title"])
+	assert.Equal(t, FieldCount{Count: 1, Total: 3, Percent: 33.3}, report.Fields["remotes"])
+	assert.Equal(t, FieldCount{Count: 1, Total: 1, Percent: 100}, report.Fields["remotes[].url"])
+	assert.Equal(t, FieldCount{Count: 0, Total: 1, Percent: 0}, report.Fields["remotes[].headers"])
+	assert.Equal(t, FieldCount{Count: 1, Total: 2, Percent: 50}, report.Fields["packages[].runtime_arguments[].value_hint"])
+	assert.Equal(t, FieldCount{Count: 1, Total: 2, Percent: 50}, report.Fields["packages[].runtime_arguments[].is_repeated"])
+	assert.Equal(t, FieldCount{Count: 1, Total: 2, Percent: 50}, report.Fields["packages[].runtime_arguments[].variables"])
+	assert.Equal(t, FieldCount{Count: 1, Total: 2, Percent: 50}, report.Fields["packages[].runtime_arguments[].variables.*.is_secret"])
+	assert.Equal(t, FieldCount{Count: 1, Total: 1, Percent: 100}, report.Fields["packages[].environment_variables[].is_secret"])
+	assert.Equal(t, FieldCount{Count: 3, Total: 3, Percent: 100}, report.Fields["_meta"])
+
+	// Fields no server sets are reported, while registry metadata is not broken down
+	assert.Equal(t, FieldCount{}, report.Fields["packages[].package_arguments[].value_hint"])
+	assert.Equal(t, FieldCount{}, report.Fields["packages[].transport.headers[].variables.*.choices"])
+	assert.NotContains(t, report.Fields, "_meta.io.modelcontextprotocol.registry/official")
+
+	// Reports are reused until they are older than the given age
+	db.limits = nil
+	cached, err := svc.FieldUsage(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Same(t, report, cached)
+	assert.Empty(t, db.limits)
+}
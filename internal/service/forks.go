@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// ForkList is a page of the forks of a server
+type ForkList struct {
+	// Forks lists the latest version of each fork, deleted ones included
+	Forks []apiv0.ServerSummary
+	// NextCursor continues the list, or is empty on the last page
+	NextCursor string
+	// Total counts the forks across all pages
+	Total int
+}
+
+// validateForkLineage checks that the server serverJSON declares itself a fork of can be forked
+// and that the fork would not close a cycle
+func (s *registryServiceImpl) validateForkLineage(ctx context.Context, serverJSON apiv0.ServerJSON) error {
+	if serverJSON.ForkOf == "" || serverJSON.Status == model.StatusDeleted {
+		return nil
+	}
+	return validators.ValidateForkLineage(serverJSON.Name, serverJSON.ForkOf, func(name string) (string, bool, error) {
+		latest, err := s.latestByName(ctx, name)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to look up fork origin: %w", err)
+		}
+		if latest == nil {
+			return "", false, nil
+		}
+		return latest.ForkOf, forkable(latest), nil
+	})
+}
+
+// forkable reports whether server, the latest version of a server, can be linked to as a fork's origin
+func forkable(server *apiv0.ServerJSON) bool {
+	return !server.Status.Hidden() && server.Status != model.StatusDeleted
+}
+
+// ForkLineage resolves the server a version declares itself a fork of, and counts the forks of
+// the server it belongs to, as they are when it is served. An origin that has since been deleted,
+// or is not public, is reported with the unknown status and no ID.
+func (s *registryServiceImpl) ForkLineage(ctx context.Context, server *apiv0.ServerJSON) (*apiv0.ForkOrigin, int, error) {
+	var origin *apiv0.ForkOrigin
+	if server.ForkOf != "" {
+		origin = &apiv0.ForkOrigin{Name: server.ForkOf, Status: apiv0.ForkOriginUnknown}
+		latest, err := s.latestByName(ctx, server.ForkOf)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to look up fork origin: %w", err)
+		}
+		if latest != nil && forkable(latest) && latest.Meta != nil && latest.Meta.Official != nil {
+			origin.ID, origin.Status = latest.Meta.Official.ID, apiv0.ForkOriginAvailable
+		}
+	}
+
+	filter := forksFilter(server.Name)
+	forks, err := s.db.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count forks: %w", err)
+	}
+	if forks > 0 {
+		deleted := model.StatusDeleted
+		filter.Status = &deleted
+		deletedForks, err := s.db.Count(ctx, filter)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count forks: %w", err)
+		}
+		forks -= deletedForks
+	}
+	return origin, forks, nil
+}
+
+// ListForks lists the latest version of each server declaring itself a fork of the server named
+// name, deleted forks included so clients can tell them apart by status. It returns
+// database.ErrNotFound if no public server has that name.
+func (s *registryServiceImpl) ListForks(ctx context.Context, name, cursor string, limit int) (*ForkList, error) {
+	original, err := s.latestByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil || original.Status.Hidden() {
+		return nil, database.ErrNotFound
+	}
+
+	filter := forksFilter(name)
+	filter.Projection = database.ProjectionSummary
+	forks, nextCursor, err := s.List(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.db.Count(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count forks: %w", err)
+	}
+
+	list := &ForkList{Forks: make([]apiv0.ServerSummary, 0, len(forks)), NextCursor: nextCursor, Total: total}
+	for i := range forks {
+		list.Forks = append(list.Forks, forks[i].Summary())
+	}
+	return list, nil
+}
+
+// forksFilter selects the latest public version of each fork of the server named name
+func forksFilter(name string) *database.ServerFilter {
+	isLatest := true
+	return &database.ServerFilter{ForkOf: &name, IsLatest: &isLatest, ExcludeHidden: true}
+}
@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestForkLineage(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+
+	publish := func(name, version, forkOf string) (*apiv0.ServerJSON, error) {
+		t.Helper()
+		return registryService.Publish(ctx, apiv0.ServerJSON{Name: name, Description: "A server", Version: version, ForkOf: forkOf})
+	}
+	edit := func(server *apiv0.ServerJSON, mutate func(*apiv0.ServerJSON)) error {
+		t.Helper()
+		edited := *server
+		edited.Meta = nil
+		mutate(&edited)
+		_, err := registryService.EditServer(ctx, server.Meta.Official.ID, edited)
+		return err
+	}
+	requireForkError := func(t *testing.T, err error, contains string) {
+		t.Helper()
+		var fieldErr *validators.FieldError
+		require.True(t, errors.As(err, &fieldErr), "expected a field error, got %v", err)
+		assert.Equal(t, apiv0.ErrorCodeInvalidForkOf, fieldErr.Code)
+		assert.Equal(t, "/forkOf", fieldErr.Field)
+		assert.Contains(t, err.Error(), contains)
+	}
+
+	original, err := publish("com.example/original", "1.0.0", "")
+	require.NoError(t, err)
+	fork, err := publish("com.example/fork", "1.0.0", "com.example/original")
+	require.NoError(t, err)
+	forkOfFork, err := publish("com.example/fork-of-fork", "1.0.0", "com.example/fork")
+	require.NoError(t, err)
+
+	t.Run("self reference", func(t *testing.T) {
+		_, err := publish("com.example/self", "1.0.0", "com.example/self")
+		requireForkError(t, err, "cannot be a fork of itself")
+	})
+
+	t.Run("missing origin", func(t *testing.T) {
+		_, err := publish("com.example/orphan", "1.0.0", "com.example/missing")
+		requireForkError(t, err, "does not exist")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		// The original naming its fork's fork as its origin would close a loop of three
+		err := edit(original, func(s *apiv0.ServerJSON) { s.ForkOf = "com.example/fork-of-fork" })
+		requireForkError(t, err, "com.example/original -> com.example/fork-of-fork -> com.example/fork -> com.example/original")
+
+		_, err = publish("com.example/original", "1.1.0", "com.example/fork")
+		requireForkError(t, err, "fork cycle")
+	})
+
+	t.Run("origin and forks", func(t *testing.T) {
+		origin, forks, err := registryService.ForkLineage(ctx, fork)
+		require.NoError(t, err)
+		assert.Equal(t, &apiv0.ForkOrigin{Name: "com.example/original", ID: original.Meta.Official.ID, Status: apiv0.ForkOriginAvailable}, origin)
+		assert.Equal(t, 1, forks)
+
+		origin, forks, err = registryService.ForkLineage(ctx, original)
+		require.NoError(t, err)
+		assert.Nil(t, origin)
+		assert.Equal(t, 1, forks)
+	})
+
+	t.Run("reverse listing", func(t *testing.T) {
+		_, err := publish("com.example/another-fork", "1.0.0", "com.example/original")
+		require.NoError(t, err)
+
+		list, err := registryService.ListForks(ctx, "com.example/original", "", 1)
+		require.NoError(t, err)
+		require.Len(t, list.Forks, 1)
+		assert.Equal(t, 2, list.Total)
+		require.NotEmpty(t, list.NextCursor)
+		names := []string{list.Forks[0].Name}
+
+		list, err = registryService.ListForks(ctx, "com.example/original", list.NextCursor, 1)
+		require.NoError(t, err)
+		require.Len(t, list.Forks, 1)
+		assert.Empty(t, list.NextCursor)
+		names = append(names, list.Forks[0].Name)
+		assert.ElementsMatch(t, []string{"com.example/fork", "com.example/another-fork"}, names)
+
+		list, err = registryService.ListForks(ctx, "com.example/fork-of-fork", "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, list.Forks)
+		assert.Zero(t, list.Total)
+
+		_, err = registryService.ListForks(ctx, "com.example/missing", "", 10)
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("deleted origin", func(t *testing.T) {
+		require.NoError(t, edit(fork, func(s *apiv0.ServerJSON) { s.Status = model.StatusDeleted }))
+
+		// The fork's own fork keeps its link, rendered as an unknown origin
+		origin, _, err := registryService.ForkLineage(ctx, forkOfFork)
+		require.NoError(t, err)
+		assert.Equal(t, &apiv0.ForkOrigin{Name: "com.example/fork", Status: apiv0.ForkOriginUnknown}, origin)
+
+		// and can still be edited without dropping it
+		require.NoError(t, edit(forkOfFork, func(s *apiv0.ServerJSON) { s.Description = "Still a fork" }))
+
+		// but new forks of the deleted server are refused
+		_, err = publish("com.example/late-fork", "1.0.0", "com.example/fork")
+		requireForkError(t, err, "does not exist")
+
+		// Deleted forks are listed with their status but no longer counted
+		list, err := registryService.ListForks(ctx, "com.example/original", "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, list.Total)
+		_, forks, err := registryService.ForkLineage(ctx, original)
+		require.NoError(t, err)
+		assert.Equal(t, 1, forks)
+	})
+}
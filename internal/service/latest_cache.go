@@ -0,0 +1,165 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// CacheInvalidator broadcasts latest-version cache invalidations between registry replicas
+type CacheInvalidator interface {
+	// Invalidate tells every replica, including this one, that the versions of name changed
+	Invalidate(ctx context.Context, name string) error
+	// Listen calls ready once it is receiving invalidations, then fn for each one,
+	// until ctx is cancelled or the channel fails
+	Listen(ctx context.Context, ready func(), fn func(name string)) error
+}
+
+// latestEntry is a cached latest-version record; server is nil when the name has no latest version
+type latestEntry struct {
+	name   string
+	server *apiv0.ServerJSON
+}
+
+// latestCache is a bounded LRU of latest-version records keyed by server name.
+//
+// Every invalidation bumps a counter, and a record read from the database is only
+// stored if no invalidation happened since the read started. Writers invalidate both
+// before and after writing, so a reader racing a write can never cache the old record
+// past the end of that write.
+type latestCache struct {
+	maxEntries int
+	metrics    *telemetry.Metrics
+
+	mu      sync.Mutex
+	enabled bool
+	epoch   uint64
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+func newLatestCache(maxEntries int, enabled bool, metrics *telemetry.Metrics) *latestCache {
+	return &latestCache{
+		maxEntries: maxEntries,
+		metrics:    metrics,
+		enabled:    enabled,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached latest record for name. On a miss it returns a token to pass to put
+func (c *latestCache) get(ctx context.Context, name string) (server *apiv0.ServerJSON, ok bool, token uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.enabled {
+		return nil, false, 0
+	}
+
+	if elem, found := c.entries[name]; found {
+		c.order.MoveToFront(elem)
+		c.record(ctx, "hit")
+		entry, _ := elem.Value.(*latestEntry)
+		return entry.server, true, 0
+	}
+
+	c.record(ctx, "miss")
+	return nil, false, c.epoch
+}
+
+// put stores the latest record for name, unless the cache was invalidated since token was issued
+func (c *latestCache) put(name string, server *apiv0.ServerJSON, token uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.enabled || token != c.epoch {
+		return
+	}
+
+	if elem, found := c.entries[name]; found {
+		elem.Value = &latestEntry{name: name, server: server}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[name] = c.order.PushFront(&latestEntry{name: name, server: server})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		entry, _ := c.order.Remove(oldest).(*latestEntry)
+		delete(c.entries, entry.name)
+	}
+}
+
+// invalidate drops the record for name and rejects any in-flight reads
+func (c *latestCache) invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	if elem, found := c.entries[name]; found {
+		c.order.Remove(elem)
+		delete(c.entries, name)
+	}
+}
+
+// setEnabled turns the cache on or off; either way all records are dropped
+func (c *latestCache) setEnabled(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.enabled = enabled
+	c.epoch++
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+func (c *latestCache) record(ctx context.Context, result string) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.LatestCacheRequests.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+// listenForInvalidations applies invalidations from other replicas until ctx is cancelled,
+// dropping their latest versions and moving to a new generation for each one. While the channel
+// is down the latest-version cache is disabled, since invalidations may be missed.
+func (s *registryServiceImpl) listenForInvalidations(ctx context.Context) {
+	const retryDelay = 5 * time.Second
+
+	ready := func() {
+		s.invalidationsLive.Store(true)
+		s.generation.Add(1)
+		// A cache created without room is off for good, as when tenancy is enabled
+		if s.latest.maxEntries > 0 {
+			s.latest.setEnabled(true)
+		}
+	}
+	invalidate := func(name string) {
+		s.latest.invalidate(name)
+		s.generation.Add(1)
+	}
+	for ctx.Err() == nil {
+		err := s.invalidator.Listen(ctx, ready, invalidate)
+		s.invalidationsLive.Store(false)
+		s.latest.setEnabled(false)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Cache invalidation channel failed, caches disabled until it reconnects: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
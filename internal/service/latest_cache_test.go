@@ -0,0 +1,283 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// latestReadDB counts latest-version lookups and runs afterLatestRead once a lookup
+// has read from the database but before its result is returned to the service
+type latestReadDB struct {
+	database.Database
+	latestReads     atomic.Int64
+	afterLatestRead func()
+}
+
+func (db *latestReadDB) List(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerJSON, string, error) {
+	servers, next, err := db.Database.List(ctx, filter, cursor, limit)
+	if filter != nil && filter.IsLatest != nil {
+		db.latestReads.Add(1)
+		if hook := db.afterLatestRead; hook != nil {
+			db.afterLatestRead = nil
+			hook()
+		}
+	}
+	return servers, next, err
+}
+
+// fakeInvalidationBus delivers invalidations to every service listening on it, like a shared channel
+type fakeInvalidationBus struct {
+	mu        sync.Mutex
+	listeners []func(name string)
+}
+
+func (b *fakeInvalidationBus) Invalidate(_ context.Context, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, fn := range b.listeners {
+		fn(name)
+	}
+	return nil
+}
+
+func (b *fakeInvalidationBus) Listen(ctx context.Context, ready func(), fn func(name string)) error {
+	b.mu.Lock()
+	b.listeners = append(b.listeners, fn)
+	b.mu.Unlock()
+	ready()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func latestCacheEnabled(s RegistryService) bool {
+	c := s.(*registryServiceImpl).latest
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enabled
+}
+
+func publishVersion(t *testing.T, s RegistryService, name, version string) *apiv0.ServerJSON {
+	t.Helper()
+	published, err := s.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        name,
+		Description: "A server",
+		Version:     version,
+	})
+	require.NoError(t, err)
+	return published
+}
+
+func TestGetLatestByName_CachesUntilWrite(t *testing.T) {
+	ctx := context.Background()
+	db := &latestReadDB{Database: database.NewMemoryDB()}
+	s := NewRegistryService(db, &config.Config{LatestCacheSize: 10})
+	name := "com.example/cached"
+
+	_, err := s.GetLatestByName(ctx, name)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	first := publishVersion(t, s, name, "1.0.0")
+	db.latestReads.Store(0)
+
+	for range 3 {
+		latest, err := s.GetLatestByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", latest.Version)
+	}
+	assert.Equal(t, int64(1), db.latestReads.Load(), "only the first lookup should reach the database")
+
+	// Publishing a newer version invalidates the cached record
+	publishVersion(t, s, name, "1.1.0")
+	latest, err := s.GetLatestByName(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", latest.Version)
+
+	// So does pinning it
+	_, err = s.SetPinned(ctx, latest.Meta.Official.ID, true)
+	require.NoError(t, err)
+	latest, err = s.GetLatestByName(ctx, name)
+	require.NoError(t, err)
+	assert.True(t, latest.Meta.Official.Pinned)
+
+	// Editing an older version invalidates too, without changing which version is latest
+	_, err = s.SetPinned(ctx, first.Meta.Official.ID, true)
+	require.NoError(t, err)
+	latest, err = s.GetLatestByName(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", latest.Version)
+}
+
+func TestGetLatestByName_StaleReadIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	db := &latestReadDB{Database: database.NewMemoryDB()}
+	s := NewRegistryService(db, &config.Config{LatestCacheSize: 10})
+	name := "com.example/racy"
+
+	publishVersion(t, s, name, "1.0.0")
+
+	// A publish completes after the lookup read 1.0.0 but before it could cache it
+	db.afterLatestRead = func() {
+		publishVersion(t, s, name, "2.0.0")
+	}
+	latest, err := s.GetLatestByName(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", latest.Version, "the racing lookup returns what it read")
+
+	latest, err = s.GetLatestByName(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", latest.Version, "the stale record must not have been cached")
+}
+
+func TestGetLatestByName_InterleavedReadsAndWrites(t *testing.T) {
+	ctx := context.Background()
+	memDB := database.NewMemoryDB()
+	s := NewRegistryService(memDB, &config.Config{LatestCacheSize: 2})
+	names := []string{"com.example/a", "com.example/b", "com.example/c"}
+	const versions = 20
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := 1; v <= versions; v++ {
+				publishVersion(t, s, name, fmt.Sprintf("1.0.%d", v))
+			}
+		}()
+	}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range versions * len(names) {
+				_, err := s.GetLatestByName(ctx, names[i%len(names)])
+				if err != nil {
+					assert.ErrorIs(t, err, database.ErrNotFound)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	isLatest := true
+	for _, name := range names {
+		stored, _, err := memDB.List(ctx, &database.ServerFilter{Name: &name, IsLatest: &isLatest}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, fmt.Sprintf("1.0.%d", versions), stored[0].Version)
+
+		cached, err := s.GetLatestByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, stored[0].Version, cached.Version)
+		assert.Equal(t, stored[0].Meta.Official.ID, cached.Meta.Official.ID)
+	}
+}
+
+func TestLatestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newLatestCache(2, true, nil)
+	ctx := context.Background()
+
+	for _, name := range []string{"a", "b"} {
+		_, _, token := c.get(ctx, name)
+		c.put(name, &apiv0.ServerJSON{Name: name}, token)
+	}
+	_, ok, _ := c.get(ctx, "a")
+	require.True(t, ok)
+
+	_, _, token := c.get(ctx, "c")
+	c.put("c", &apiv0.ServerJSON{Name: "c"}, token)
+
+	_, ok, _ = c.get(ctx, "b")
+	assert.False(t, ok, "b was least recently used")
+	_, ok, _ = c.get(ctx, "a")
+	assert.True(t, ok)
+	_, ok, _ = c.get(ctx, "c")
+	assert.True(t, ok)
+}
+
+func TestNewRegistryService_LatestCacheAcrossReplicas(t *testing.T) {
+	ctx := context.Background()
+	memDB := database.NewMemoryDB()
+
+	t.Run("disabled for multiple replicas without an invalidation channel", func(t *testing.T) {
+		s := NewRegistryService(memDB, &config.Config{LatestCacheSize: 10, Replicas: 2})
+		assert.False(t, latestCacheEnabled(s))
+	})
+
+	t.Run("disabled when the size is zero", func(t *testing.T) {
+		s := NewRegistryService(memDB, &config.Config{LatestCacheSize: 0})
+		assert.False(t, latestCacheEnabled(s))
+	})
+
+	t.Run("shared invalidations keep replicas consistent", func(t *testing.T) {
+		listenCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		bus := &fakeInvalidationBus{}
+		cfg := &config.Config{LatestCacheSize: 10, Replicas: 2}
+		replicaA := NewRegistryService(memDB, cfg, WithCacheInvalidator(listenCtx, bus))
+		replicaB := NewRegistryService(memDB, cfg, WithCacheInvalidator(listenCtx, bus))
+		require.Eventually(t, func() bool {
+			return latestCacheEnabled(replicaA) && latestCacheEnabled(replicaB)
+		}, time.Second, 10*time.Millisecond)
+
+		name := "com.example/replicated"
+		publishVersion(t, replicaA, name, "1.0.0")
+		latest, err := replicaA.GetLatestByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", latest.Version)
+
+		publishVersion(t, replicaB, name, "2.0.0")
+		latest, err = replicaA.GetLatestByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", latest.Version, "replica A must see replica B's publish")
+
+		// Losing the channel disables the cache
+		cancel()
+		require.Eventually(t, func() bool {
+			return !latestCacheEnabled(replicaA)
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("stays disabled in multi-tenant mode once the channel is listening", func(t *testing.T) {
+		listenCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		s := NewRegistryService(database.NewMemoryDB(), &config.Config{LatestCacheSize: 10, Replicas: 2, TenancyEnabled: true},
+			WithCacheInvalidator(listenCtx, &fakeInvalidationBus{}))
+		require.Eventually(t, func() bool {
+			return s.(*registryServiceImpl).invalidationsLive.Load()
+		}, time.Second, 10*time.Millisecond)
+		assert.False(t, latestCacheEnabled(s))
+
+		// Each tenant sees its own latest version of a name both publish
+		name := "com.example/shared"
+		acme := tenancy.WithTenant(ctx, "acme")
+		other := tenancy.WithTenant(ctx, "other")
+		_, err := s.Publish(acme, apiv0.ServerJSON{Name: name, Description: "A server", Version: "1.0.0"})
+		require.NoError(t, err)
+		_, err = s.Publish(other, apiv0.ServerJSON{Name: name, Description: "A server", Version: "2.0.0"})
+		require.NoError(t, err)
+
+		for _, read := range []struct {
+			ctx     context.Context
+			version string
+		}{{acme, "1.0.0"}, {other, "2.0.0"}, {acme, "1.0.0"}} {
+			latest, err := s.GetLatestByName(read.ctx, name)
+			require.NoError(t, err)
+			assert.Equal(t, read.version, latest.Version)
+		}
+	})
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// linkCheckPageSize is the page size used when scanning latest versions for package link checks
+const linkCheckPageSize = 100
+
+// SetPackageLinks records the result of checking a server version's MCPB download URLs, and
+// notifies the namespace about links that have just been marked broken
+func (s *registryServiceImpl) SetPackageLinks(ctx context.Context, id string, links []apiv0.PackageLink) (*apiv0.ServerJSON, error) {
+	ctx = database.WithPrimaryReads(ctx)
+	server, err := s.db.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if server.Meta == nil || server.Meta.Official == nil {
+		return nil, fmt.Errorf("%w: server %s has no registry metadata", database.ErrInvalidInput, id)
+	}
+
+	wasBroken := make(map[string]bool)
+	for _, link := range server.Meta.Official.PackageLinks {
+		wasBroken[link.Identifier] = link.LinkStatus == apiv0.LinkStatusBroken
+	}
+
+	official := *server.Meta.Official
+	official.PackageLinks = links
+	meta := *server.Meta
+	meta.Official = &official
+	updated := *server
+	updated.Meta = &meta
+
+	if err := s.invalidateLatest(ctx, server.Name); err != nil {
+		return nil, err
+	}
+	defer s.invalidateLatestAfterWrite(ctx, server.Name)
+
+	// Store the links and queue notifications for newly broken ones together
+	var serverRecord *apiv0.ServerJSON
+	err = s.db.InTransaction(ctx, func(ctx context.Context, tx database.Database) error {
+		record, err := tx.UpdateServer(ctx, id, &updated)
+		if err != nil {
+			return err
+		}
+		serverRecord = record
+
+		for _, link := range links {
+			if link.LinkStatus == apiv0.LinkStatusBroken && !wasBroken[link.Identifier] {
+				if err := s.queueLinkBrokenNotification(ctx, tx, record, link); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.generation.Add(1)
+	s.wakeNotifications()
+	return serverRecord, nil
+}
+
+// queueLinkBrokenNotification writes notifications for a package link that has just been marked
+// broken to the outbox in tx, if a dispatcher is configured
+func (s *registryServiceImpl) queueLinkBrokenNotification(ctx context.Context, tx database.Database, server *apiv0.ServerJSON, link apiv0.PackageLink) error {
+	if s.notifications == nil {
+		return nil
+	}
+	namespace, _, _ := strings.Cut(server.Name, "/")
+	status := server.Status
+	if status == "" {
+		status = model.StatusActive // the schema default
+	}
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(server))
+	return s.notifications.enqueue(ctx, tx, PublishNotification{
+		Event:     PackageLinkBrokenEvent,
+		Namespace: namespace,
+		Server: NotificationServer{
+			ID:      server.Meta.Official.ID,
+			Name:    server.Name,
+			Version: server.Version,
+			Status:  string(status),
+		},
+		PublishedAt: server.Meta.Official.PublishedAt,
+		PackageLink: &link,
+	})
+}
+
+// LinkCheckResult is the outcome of one request for a package download URL
+type LinkCheckResult struct {
+	OK         bool
+	StatusCode int    // 0 when no response was received
+	ETag       string // the response's ETag, or the one sent if the response was 304 Not Modified
+}
+
+// LinkChecker checks that package download URLs still resolve, spacing requests to the same host
+type LinkChecker struct {
+	client  *http.Client
+	timeout time.Duration
+	hosts   *hostLimiter
+}
+
+// NewLinkChecker creates a checker that gives each request timeout to respond and
+// starts requests to the same host at least hostInterval apart
+func NewLinkChecker(timeout, hostInterval time.Duration) *LinkChecker {
+	return &LinkChecker{
+		client:  &http.Client{},
+		timeout: timeout,
+		hosts:   newHostLimiter(hostInterval),
+	}
+}
+
+// Check sends a HEAD request to downloadURL, following redirects to where release assets are
+// stored. If etag is set it is sent as If-None-Match, and 304 Not Modified counts as resolving.
+// Otherwise only 2xx responses do. Request failures are reported as a failed result; an
+// error is only returned if ctx is cancelled.
+func (c *LinkChecker) Check(ctx context.Context, downloadURL, etag string) (LinkCheckResult, error) {
+	parsed, err := url.Parse(downloadURL)
+	if err != nil || parsed.Host == "" {
+		return LinkCheckResult{}, nil
+	}
+
+	if err := c.hosts.wait(ctx, parsed.Host); err != nil {
+		return LinkCheckResult{}, err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodHead, downloadURL, nil)
+	if err != nil {
+		return LinkCheckResult{}, nil
+	}
+	req.Header.Set("User-Agent", "MCP-Registry-Link-Check/1.0")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return LinkCheckResult{}, ctx.Err()
+	}
+	resp.Body.Close()
+
+	result := LinkCheckResult{StatusCode: resp.StatusCode, ETag: resp.Header.Get("ETag")}
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		result.OK = true
+		if result.ETag == "" {
+			result.ETag = etag
+		}
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		result.OK = true
+	}
+	return result, nil
+}
+
+// PackageLinkJob periodically checks the MCPB download URLs of each server's latest version
+type PackageLinkJob struct {
+	registry    RegistryService
+	checker     *LinkChecker
+	brokenAfter time.Duration
+	interval    time.Duration
+}
+
+// NewPackageLinkJob creates a job that checks package links every interval, marking a link
+// broken once it has been failing for brokenAfter
+func NewPackageLinkJob(registry RegistryService, checker *LinkChecker, brokenAfter, interval time.Duration) *PackageLinkJob {
+	return &PackageLinkJob{
+		registry:    registry,
+		checker:     checker,
+		brokenAfter: brokenAfter,
+		interval:    interval,
+	}
+}
+
+// NewPackageLinkJobFromConfig creates the package link job configured for this registry
+func NewPackageLinkJobFromConfig(registry RegistryService, cfg *config.Config) *PackageLinkJob {
+	checker := NewLinkChecker(cfg.LinkCheckTimeout, cfg.LinkCheckHostInterval)
+	return NewPackageLinkJob(registry, checker, cfg.LinkCheckBrokenAfter, cfg.LinkCheckInterval)
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *PackageLinkJob) Start(ctx context.Context) {
+	go j.Run(ctx)
+}
+
+// Run runs the job until ctx is cancelled, returning once a run in progress has stopped
+func (j *PackageLinkJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.runOnce(ctx)
+		}
+	}
+}
+
+func (j *PackageLinkJob) runOnce(ctx context.Context) {
+	checked, err := j.CheckAll(ctx)
+	if err != nil {
+		log.Printf("Package link job failed after checking %d servers: %v", checked, err)
+		return
+	}
+	log.Printf("Package link job checked %d servers", checked)
+}
+
+// CheckAll checks the MCPB package links of every latest, non-deleted server version and
+// records the results, returning how many servers were checked
+func (j *PackageLinkJob) CheckAll(ctx context.Context) (int, error) {
+	isLatest := true
+	filter := &database.ServerFilter{IsLatest: &isLatest}
+
+	checked := 0
+	cursor := ""
+	for {
+		servers, nextCursor, err := j.registry.List(ctx, filter, cursor, linkCheckPageSize)
+		if err != nil {
+			return checked, err
+		}
+
+		for i := range servers {
+			server := &servers[i]
+			if server.Status == model.StatusDeleted || server.Meta == nil || server.Meta.Official == nil {
+				continue
+			}
+			urls := mcpbPackageURLs(server.Packages)
+			if len(urls) == 0 {
+				continue
+			}
+
+			if err := j.check(ctx, server, urls); err != nil {
+				return checked, err
+			}
+			checked++
+		}
+
+		if nextCursor == "" {
+			return checked, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// check requests one server's package links and records the results
+func (j *PackageLinkJob) check(ctx context.Context, server *apiv0.ServerJSON, urls []string) error {
+	previous := make(map[string]*apiv0.PackageLink)
+	for i, link := range server.Meta.Official.PackageLinks {
+		previous[link.Identifier] = &server.Meta.Official.PackageLinks[i]
+	}
+
+	links := make([]apiv0.PackageLink, 0, len(urls))
+	for _, downloadURL := range urls {
+		etag := ""
+		if link := previous[downloadURL]; link != nil {
+			etag = link.ETag
+		}
+		result, err := j.checker.Check(ctx, downloadURL, etag)
+		if err != nil {
+			return err
+		}
+		links = append(links, nextPackageLink(previous[downloadURL], downloadURL, result, j.brokenAfter, time.Now()))
+	}
+
+	if _, err := j.registry.SetPackageLinks(ctx, server.Meta.Official.ID, links); err != nil {
+		return fmt.Errorf("failed to record package links of %s: %w", server.Name, err)
+	}
+	return nil
+}
+
+// nextPackageLink works out a link's state from its previous state and the latest check. A failing
+// link is only marked broken once it has failed for brokenAfter, so a brief outage doesn't flag it.
+func nextPackageLink(previous *apiv0.PackageLink, identifier string, result LinkCheckResult, brokenAfter time.Duration, now time.Time) apiv0.PackageLink {
+	link := apiv0.PackageLink{
+		Identifier:     identifier,
+		LastCheckedAt:  now,
+		LastCheckOK:    result.OK,
+		LastHTTPStatus: result.StatusCode,
+		ETag:           result.ETag,
+	}
+	if result.OK {
+		return link
+	}
+
+	link.FailingSince = &now
+	if previous != nil {
+		if previous.FailingSince != nil {
+			link.FailingSince = previous.FailingSince
+		}
+		link.ETag = previous.ETag // keep validating against the last copy that resolved
+	}
+	if now.Sub(*link.FailingSince) >= brokenAfter {
+		link.LinkStatus = apiv0.LinkStatusBroken
+	}
+	return link
+}
+
+// mcpbPackageURLs returns the distinct download URLs of a server's MCPB packages
+func mcpbPackageURLs(packages []model.Package) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, pkg := range packages {
+		if pkg.RegistryType != model.RegistryTypeMCPB || seen[pkg.Identifier] {
+			continue
+		}
+		if !strings.HasPrefix(pkg.Identifier, "https://") && !strings.HasPrefix(pkg.Identifier, "http://") {
+			continue
+		}
+		seen[pkg.Identifier] = true
+		urls = append(urls, pkg.Identifier)
+	}
+	return urls
+}
@@ -0,0 +1,182 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedPackageServer(t *testing.T, db database.Database, name string, status model.Status, packages ...model.Package) string {
+	t.Helper()
+
+	id := seedVersion(t, db, name, "1.0.0", time.Now(), true, status)
+	server, err := db.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	server.Packages = packages
+	_, err = db.UpdateServer(context.Background(), id, server)
+	require.NoError(t, err)
+	return id
+}
+
+func TestPackageLinkJob(t *testing.T) {
+	ctx := context.Background()
+
+	// The release asset is deleted and restored between runs
+	var mu sync.Mutex
+	deleted := false
+	var ifNoneMatch []string
+	assets := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		mu.Lock()
+		defer mu.Unlock()
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		switch {
+		case deleted:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer assets.Close()
+	setDeleted := func(value bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		deleted = value
+		ifNoneMatch = nil
+	}
+
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{}, WithNotifications(NewNotificationDispatcher(db, &config.Config{})))
+
+	assetURL := assets.URL + "/releases/download/v1.0.0/foo.mcpb"
+	mcpb := model.Package{RegistryType: model.RegistryTypeMCPB, Identifier: assetURL, Version: "1.0.0"}
+	npm := model.Package{RegistryType: model.RegistryTypeNPM, Identifier: "@example/foo", Version: "1.0.0"}
+	fooID := seedPackageServer(t, db, "com.example/foo", model.StatusActive, mcpb, npm)
+	deletedID := seedPackageServer(t, db, "com.example/deleted", model.StatusDeleted, mcpb)
+	seedPackageServer(t, db, "com.example/npm-only", model.StatusActive, npm)
+
+	links := func(id string) []apiv0.PackageLink {
+		t.Helper()
+		server, err := db.GetByID(ctx, id)
+		require.NoError(t, err)
+		return server.Meta.Official.PackageLinks
+	}
+	require.NoError(t, db.CreateNamespaceNotification(ctx, &database.NamespaceNotification{
+		ID: "5d2b6c1e-7a4f-4e8b-9c3d-1f0e2a4b6c8d", Namespace: "com.example", WebhookURL: "https://hooks.example.com/mcp", CreatedAt: time.Now(),
+	}))
+	notified := func() []PublishNotification {
+		t.Helper()
+		events, err := db.ClaimOutboxEvents(ctx, time.Now(), time.Hour, 100)
+		require.NoError(t, err)
+		var notifications []PublishNotification
+		for _, event := range events {
+			var notification PublishNotification
+			require.NoError(t, json.Unmarshal(event.Payload, &notification))
+			notifications = append(notifications, notification)
+			require.NoError(t, db.CompleteOutboxEvent(ctx, event.ID, time.Now(), ""))
+		}
+		return notifications
+	}
+
+	checker := NewLinkChecker(time.Second, 0)
+	patient := NewPackageLinkJob(svc, checker, time.Hour, time.Hour)
+	immediate := NewPackageLinkJob(svc, checker, 0, time.Hour)
+
+	// The asset resolves, and its ETag is stored for the next check
+	checked, err := patient.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, checked, "only servers with MCPB packages are checked")
+	require.Len(t, links(fooID), 1)
+	link := links(fooID)[0]
+	assert.Equal(t, assetURL, link.Identifier)
+	assert.True(t, link.LastCheckOK)
+	assert.Equal(t, http.StatusOK, link.LastHTTPStatus)
+	assert.Equal(t, `"v1"`, link.ETag)
+	assert.Empty(t, link.LinkStatus)
+	assert.Nil(t, links(deletedID), "deleted servers are not checked")
+
+	// An unchanged asset is revalidated with a conditional request
+	setDeleted(false)
+	_, err = patient.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`"v1"`}, ifNoneMatch)
+	link = links(fooID)[0]
+	assert.True(t, link.LastCheckOK)
+	assert.Equal(t, http.StatusNotModified, link.LastHTTPStatus)
+	assert.Equal(t, `"v1"`, link.ETag)
+
+	// A failing link is recorded, but not flagged until it has failed for the grace period
+	setDeleted(true)
+	_, err = patient.CheckAll(ctx)
+	require.NoError(t, err)
+	link = links(fooID)[0]
+	assert.False(t, link.LastCheckOK)
+	assert.Equal(t, http.StatusNotFound, link.LastHTTPStatus)
+	require.NotNil(t, link.FailingSince)
+	failingSince := *link.FailingSince
+	assert.Empty(t, link.LinkStatus)
+	assert.Empty(t, notified())
+
+	// Past the grace period the link is annotated broken and the namespace is notified once
+	_, err = immediate.CheckAll(ctx)
+	require.NoError(t, err)
+	link = links(fooID)[0]
+	assert.Equal(t, apiv0.LinkStatusBroken, link.LinkStatus)
+	assert.Equal(t, failingSince, *link.FailingSince, "failing since the first failed check")
+	notifications := notified()
+	require.Len(t, notifications, 1)
+	assert.Equal(t, PackageLinkBrokenEvent, notifications[0].Event)
+	assert.Equal(t, "com.example", notifications[0].Namespace)
+	assert.Equal(t, fooID, notifications[0].Server.ID)
+	require.NotNil(t, notifications[0].PackageLink)
+	assert.Equal(t, assetURL, notifications[0].PackageLink.Identifier)
+
+	_, err = immediate.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, notified(), "a link that stays broken is only notified once")
+
+	// Restoring the asset clears the annotation
+	setDeleted(false)
+	_, err = immediate.CheckAll(ctx)
+	require.NoError(t, err)
+	link = links(fooID)[0]
+	assert.True(t, link.LastCheckOK)
+	assert.Nil(t, link.FailingSince)
+	assert.Empty(t, link.LinkStatus)
+	assert.Empty(t, notified())
+}
+
+func TestNextPackageLink(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-2 * time.Hour)
+	failing := &apiv0.PackageLink{Identifier: "https://example.com/foo.mcpb", FailingSince: &since, ETag: `"v1"`}
+
+	stillFailing := nextPackageLink(failing, failing.Identifier, LinkCheckResult{StatusCode: http.StatusNotFound}, 3*time.Hour, now)
+	assert.Equal(t, since, *stillFailing.FailingSince)
+	assert.Empty(t, stillFailing.LinkStatus)
+	assert.Equal(t, `"v1"`, stillFailing.ETag)
+
+	broken := nextPackageLink(failing, failing.Identifier, LinkCheckResult{}, time.Hour, now)
+	assert.Equal(t, apiv0.LinkStatusBroken, broken.LinkStatus)
+	assert.Equal(t, 0, broken.LastHTTPStatus, "no response")
+
+	recovered := nextPackageLink(failing, failing.Identifier, LinkCheckResult{OK: true, StatusCode: http.StatusOK, ETag: `"v2"`}, time.Hour, now)
+	assert.Nil(t, recovered.FailingSince)
+	assert.Empty(t, recovered.LinkStatus)
+	assert.Equal(t, `"v2"`, recovered.ETag)
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+)
+
+// Results of a maintenance task run, as logged and exported in metrics
+const (
+	MaintenanceResultOK      = "ok"
+	MaintenanceResultError   = "error"
+	MaintenanceResultTimeout = "timeout"
+	MaintenanceResultPanic   = "panic"
+	MaintenanceResultSkipped = "skipped"
+)
+
+// MaintenanceTask is a cleanup run periodically by a MaintenanceScheduler
+type MaintenanceTask struct {
+	// Name identifies the task in logs and metrics
+	Name string
+	// Interval is how often the task runs
+	Interval time.Duration
+	// Timeout bounds a single run; zero uses the scheduler's default
+	Timeout time.Duration
+	// Run removes whatever has expired as of now and returns how many rows it removed. It should
+	// return once ctx is cancelled.
+	Run func(ctx context.Context, now time.Time) (int64, error)
+}
+
+// MaintenanceResult is the outcome of one run of a maintenance task
+type MaintenanceResult struct {
+	Task     string
+	Result   string
+	Removed  int64
+	Duration time.Duration
+	Err      error
+}
+
+// scheduledTask is a registered task and its scheduling state
+type scheduledTask struct {
+	MaintenanceTask
+	next time.Time
+	// running is set while a run is in progress, including one abandoned after its timeout
+	running atomic.Bool
+}
+
+// MaintenanceScheduler runs the registry's periodic cleanups from a single loop. Runs are
+// serialized so cleanups never compete with each other for the database; each is bounded by
+// a timeout, and a panicking task is recovered without affecting the others.
+type MaintenanceScheduler struct {
+	tasks          []*scheduledTask
+	defaultTimeout time.Duration
+	metrics        *telemetry.Metrics
+	// onResult, if set, is called with the result of every run, for tests
+	onResult func(MaintenanceResult)
+}
+
+// NewMaintenanceScheduler creates a scheduler whose tasks time out after defaultTimeout unless
+// they set their own. metrics may be nil.
+func NewMaintenanceScheduler(defaultTimeout time.Duration, metrics *telemetry.Metrics) *MaintenanceScheduler {
+	return &MaintenanceScheduler{
+		defaultTimeout: defaultTimeout,
+		metrics:        metrics,
+	}
+}
+
+// Register adds a task, first run one interval after the scheduler starts. It must be called
+// before Run.
+func (s *MaintenanceScheduler) Register(task MaintenanceTask) {
+	s.tasks = append(s.tasks, &scheduledTask{MaintenanceTask: task})
+}
+
+// Run runs the registered tasks when they are due until ctx is cancelled, returning once a run
+// in progress has stopped or timed out
+func (s *MaintenanceScheduler) Run(ctx context.Context) {
+	if len(s.tasks) == 0 {
+		return
+	}
+	start := time.Now()
+	for _, task := range s.tasks {
+		task.next = start.Add(task.Interval)
+	}
+
+	timer := time.NewTimer(time.Until(s.nextDue()))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			s.runDue(ctx, time.Now())
+			timer.Reset(time.Until(s.nextDue()))
+		}
+	}
+}
+
+// nextDue returns when the next task is due
+func (s *MaintenanceScheduler) nextDue() time.Time {
+	next := s.tasks[0].next
+	for _, task := range s.tasks[1:] {
+		if task.next.Before(next) {
+			next = task.next
+		}
+	}
+	return next
+}
+
+// runDue runs, one after another, every task due at now and schedules its next run
+func (s *MaintenanceScheduler) runDue(ctx context.Context, now time.Time) {
+	for _, task := range s.tasks {
+		if ctx.Err() != nil {
+			return
+		}
+		if task.next.After(now) {
+			continue
+		}
+		// Runs that fell behind are not caught up on, the next one cleans up everything
+		task.next = now.Add(task.Interval)
+		s.record(ctx, s.runTask(ctx, task))
+	}
+}
+
+// runTask runs task once, abandoning it once its timeout passes. An abandoned run keeps the
+// task from starting again until it returns.
+func (s *MaintenanceScheduler) runTask(ctx context.Context, task *scheduledTask) MaintenanceResult {
+	result := MaintenanceResult{Task: task.Name}
+	if !task.running.CompareAndSwap(false, true) {
+		result.Result = MaintenanceResultSkipped
+		result.Err = errors.New("the previous run has not returned")
+		return result
+	}
+
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = s.defaultTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		removed int64
+		err     error
+		panic   any
+	}
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		defer task.running.Store(false)
+		defer func() {
+			if p := recover(); p != nil {
+				done <- outcome{panic: p}
+			}
+		}()
+		removed, err := task.Run(runCtx, start)
+		done <- outcome{removed: removed, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		result.Duration = time.Since(start)
+		result.Removed = out.removed
+		switch {
+		case out.panic != nil:
+			result.Result = MaintenanceResultPanic
+			result.Err = fmt.Errorf("panic: %v", out.panic)
+		case out.err != nil && errors.Is(runCtx.Err(), context.DeadlineExceeded):
+			result.Result = MaintenanceResultTimeout
+			result.Err = out.err
+		case out.err != nil:
+			result.Result = MaintenanceResultError
+			result.Err = out.err
+		default:
+			result.Result = MaintenanceResultOK
+		}
+	case <-runCtx.Done():
+		result.Duration = time.Since(start)
+		result.Result = MaintenanceResultTimeout
+		result.Err = runCtx.Err()
+	}
+	return result
+}
+
+// record logs the result of a run and exports it in metrics
+func (s *MaintenanceScheduler) record(ctx context.Context, result MaintenanceResult) {
+	if result.Err != nil {
+		log.Printf("Maintenance task %s failed (%s) after %s: %v", result.Task, result.Result, result.Duration.Round(time.Millisecond), result.Err)
+	} else {
+		log.Printf("Maintenance task %s removed %d rows in %s", result.Task, result.Removed, result.Duration.Round(time.Millisecond))
+	}
+
+	if s.metrics != nil {
+		// Use a context that outlives shutdown so the last run is still counted
+		ctx = context.WithoutCancel(ctx)
+		task := attribute.String("task", result.Task)
+		s.metrics.MaintenanceRuns.Add(ctx, 1, metric.WithAttributes(task, attribute.String("result", result.Result)))
+		s.metrics.MaintenanceRowsRemoved.Add(ctx, result.Removed, metric.WithAttributes(task))
+		if result.Result != MaintenanceResultSkipped {
+			s.metrics.MaintenanceDuration.Record(ctx, result.Duration.Seconds(), metric.WithAttributes(task))
+		}
+	}
+
+	if s.onResult != nil {
+		s.onResult(result)
+	}
+}
+
+// DatabaseMaintenanceTasks returns the cleanups of expired database state, each run every interval:
+// DNS authentication challenges past their expiry and delivered outbox events past their retention
+func DatabaseMaintenanceTasks(db database.Database, interval time.Duration) []MaintenanceTask {
+	return []MaintenanceTask{
+		{Name: "auth_challenges", Interval: interval, Run: db.PurgeExpiredAuthChallenges},
+		{Name: "outbox_events", Interval: interval, Run: db.PurgeCompletedOutboxEvents},
+	}
+}
@@ -0,0 +1,189 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordResults makes the scheduler collect the results of its runs
+func recordResults(s *MaintenanceScheduler) func() []MaintenanceResult {
+	var mu sync.Mutex
+	var results []MaintenanceResult
+	s.onResult = func(result MaintenanceResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+	}
+	return func() []MaintenanceResult {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]MaintenanceResult(nil), results...)
+	}
+}
+
+func TestMaintenanceScheduler_Scheduling(t *testing.T) {
+	scheduler := NewMaintenanceScheduler(time.Second, nil)
+	results := recordResults(scheduler)
+
+	var fast, slow atomic.Int32
+	scheduler.Register(MaintenanceTask{Name: "fast", Interval: 10 * time.Millisecond, Run: func(context.Context, time.Time) (int64, error) {
+		fast.Add(1)
+		return 2, nil
+	}})
+	scheduler.Register(MaintenanceTask{Name: "slow", Interval: time.Hour, Run: func(context.Context, time.Time) (int64, error) {
+		slow.Add(1)
+		return 0, nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return fast.Load() >= 3 }, 5*time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Zero(t, slow.Load(), "tasks first run one interval after the scheduler starts")
+	for _, result := range results() {
+		assert.Equal(t, "fast", result.Task)
+		assert.Equal(t, MaintenanceResultOK, result.Result)
+		assert.Equal(t, int64(2), result.Removed)
+	}
+
+	t.Run("due tasks run one after another", func(t *testing.T) {
+		scheduler := NewMaintenanceScheduler(time.Second, nil)
+		var running, overlapped atomic.Bool
+		var order []string
+		for _, name := range []string{"first", "second", "third"} {
+			scheduler.Register(MaintenanceTask{Name: name, Interval: time.Hour, Run: func(context.Context, time.Time) (int64, error) {
+				if !running.CompareAndSwap(false, true) {
+					overlapped.Store(true)
+				}
+				defer running.Store(false)
+				time.Sleep(5 * time.Millisecond)
+				order = append(order, name)
+				return 0, nil
+			}})
+		}
+		now := time.Now()
+		scheduler.tasks[2].next = now.Add(time.Minute)
+
+		scheduler.runDue(context.Background(), now)
+		assert.False(t, overlapped.Load())
+		assert.Equal(t, []string{"first", "second"}, order, "tasks that are not due are left for later")
+		assert.Equal(t, now.Add(time.Minute), scheduler.tasks[2].next)
+		assert.Equal(t, now.Add(time.Hour), scheduler.tasks[0].next)
+	})
+}
+
+func TestMaintenanceScheduler_Timeout(t *testing.T) {
+	scheduler := NewMaintenanceScheduler(20*time.Millisecond, nil)
+	results := recordResults(scheduler)
+
+	// A task that ignores its context is abandoned, and skipped until it returns
+	release := make(chan struct{})
+	scheduler.Register(MaintenanceTask{Name: "stuck", Interval: time.Hour, Run: func(context.Context, time.Time) (int64, error) {
+		<-release
+		return 0, nil
+	}})
+	// A task that honours its context stops at its own timeout
+	scheduler.Register(MaintenanceTask{Name: "cancellable", Interval: time.Hour, Timeout: 10 * time.Millisecond, Run: func(ctx context.Context, _ time.Time) (int64, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}})
+	var ran atomic.Bool
+	scheduler.Register(MaintenanceTask{Name: "after", Interval: time.Hour, Run: func(context.Context, time.Time) (int64, error) {
+		ran.Store(true)
+		return 0, nil
+	}})
+
+	start := time.Now()
+	scheduler.runDue(context.Background(), start)
+	assert.Less(t, time.Since(start), time.Second, "the scheduler does not wait for a stuck task")
+	assert.True(t, ran.Load(), "tasks after one that timed out still run")
+
+	got := results()
+	require.Len(t, got, 3)
+	assert.Equal(t, MaintenanceResultTimeout, got[0].Result)
+	assert.ErrorIs(t, got[0].Err, context.DeadlineExceeded)
+	assert.Equal(t, MaintenanceResultTimeout, got[1].Result)
+	assert.ErrorIs(t, got[1].Err, context.DeadlineExceeded)
+	assert.Equal(t, MaintenanceResultOK, got[2].Result)
+
+	for _, task := range scheduler.tasks {
+		task.next = time.Time{}
+	}
+	scheduler.runDue(context.Background(), time.Now())
+	assert.Equal(t, MaintenanceResultSkipped, results()[3].Result)
+
+	close(release)
+	require.Eventually(t, func() bool { return !scheduler.tasks[0].running.Load() }, 5*time.Second, 5*time.Millisecond)
+	scheduler.tasks[0].next = time.Time{}
+	scheduler.runDue(context.Background(), time.Now())
+	assert.Equal(t, MaintenanceResultOK, results()[6].Result, "the task runs again once the abandoned run returns")
+}
+
+func TestMaintenanceScheduler_PanicIsolation(t *testing.T) {
+	scheduler := NewMaintenanceScheduler(time.Second, nil)
+	results := recordResults(scheduler)
+
+	var panics, runs atomic.Int32
+	scheduler.Register(MaintenanceTask{Name: "panics", Interval: time.Hour, Run: func(context.Context, time.Time) (int64, error) {
+		panics.Add(1)
+		panic("boom")
+	}})
+	scheduler.Register(MaintenanceTask{Name: "healthy", Interval: time.Hour, Run: func(context.Context, time.Time) (int64, error) {
+		runs.Add(1)
+		return 3, nil
+	}})
+
+	for range 2 {
+		for _, task := range scheduler.tasks {
+			task.next = time.Time{}
+		}
+		scheduler.runDue(context.Background(), time.Now())
+	}
+
+	assert.Equal(t, int32(2), panics.Load(), "a task that panicked runs again on schedule")
+	assert.Equal(t, int32(2), runs.Load())
+	got := results()
+	require.Len(t, got, 4)
+	assert.Equal(t, MaintenanceResultPanic, got[0].Result)
+	assert.ErrorContains(t, got[0].Err, "boom")
+	assert.Equal(t, MaintenanceResultOK, got[1].Result)
+	assert.Equal(t, int64(3), got[1].Removed)
+}
+
+func TestDatabaseMaintenanceTasks(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	now := time.Now()
+	require.NoError(t, db.CreateAuthChallenge(ctx, &database.AuthChallenge{Nonce: "expired", Domain: "example.com", IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)}))
+	require.NoError(t, db.CreateAuthChallenge(ctx, &database.AuthChallenge{Nonce: "live", Domain: "example.com", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	scheduler := NewMaintenanceScheduler(time.Second, nil)
+	results := recordResults(scheduler)
+	for _, task := range DatabaseMaintenanceTasks(db, time.Hour) {
+		scheduler.Register(task)
+	}
+	scheduler.runDue(ctx, now)
+
+	got := results()
+	require.Len(t, got, 2)
+	assert.Equal(t, MaintenanceResult{Task: "auth_challenges", Result: MaintenanceResultOK, Removed: 1, Duration: got[0].Duration}, got[0])
+	assert.Equal(t, "outbox_events", got[1].Task)
+	assert.Equal(t, MaintenanceResultOK, got[1].Result)
+
+	_, err := db.ConsumeAuthChallenge(ctx, "live")
+	require.NoError(t, err)
+}
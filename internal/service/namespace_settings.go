@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// NamespaceSettings returns the settings of namespace, or database.ErrNotFound if its owners have set none
+func (s *registryServiceImpl) NamespaceSettings(ctx context.Context, namespace string) (*database.NamespaceSettings, error) {
+	return s.db.GetNamespaceSettings(ctx, namespace)
+}
+
+// PutNamespaceSettings checks the defaults of settings as publishing checks the fields they fill
+// in, then stores them, replacing the namespace's earlier settings
+func (s *registryServiceImpl) PutNamespaceSettings(ctx context.Context, settings *database.NamespaceSettings) (*database.NamespaceSettings, error) {
+	defaults := apiv0.ServerJSON{
+		License:    settings.License,
+		Categories: settings.Categories,
+		WebsiteURL: settings.WebsiteURL,
+		Contact:    settings.Contact,
+	}
+	if err := validators.NormalizeServerJSON(&defaults); err != nil {
+		return nil, fmt.Errorf("%w: %w", database.ErrInvalidInput, err)
+	}
+	if err := validators.ValidateNamespaceDefaults(&defaults, s.cfg.ServerCategories); err != nil {
+		return nil, err
+	}
+
+	stored := &database.NamespaceSettings{
+		Namespace:  settings.Namespace,
+		License:    defaults.License,
+		Categories: defaults.Categories,
+		WebsiteURL: defaults.WebsiteURL,
+		Contact:    defaults.Contact,
+		UpdatedBy:  settings.UpdatedBy,
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.db.PutNamespaceSettings(ctx, stored); err != nil {
+		return nil, err
+	}
+	return s.db.GetNamespaceSettings(ctx, settings.Namespace)
+}
+
+// applyNamespaceDefaults fills the optional fields server leaves unset with the defaults of its
+// namespace's settings, returning the JSON names of the fields it filled. Fields the server sets
+// keep their values.
+func (s *registryServiceImpl) applyNamespaceDefaults(ctx context.Context, server *apiv0.ServerJSON) ([]string, error) {
+	namespace, _, _ := strings.Cut(server.Name, "/")
+	settings, err := s.db.GetNamespaceSettings(ctx, namespace)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var inherited []string
+	if server.License == "" && settings.License != "" {
+		server.License = settings.License
+		inherited = append(inherited, "license")
+	}
+	if len(server.Categories) == 0 && len(settings.Categories) > 0 {
+		server.Categories = slices.Clone(settings.Categories)
+		inherited = append(inherited, "categories")
+	}
+	if server.WebsiteURL == "" && settings.WebsiteURL != "" {
+		server.WebsiteURL = settings.WebsiteURL
+		inherited = append(inherited, "websiteUrl")
+	}
+	if server.Contact == "" && settings.Contact != "" {
+		server.Contact = settings.Contact
+		inherited = append(inherited, "contact")
+	}
+	return inherited, nil
+}
@@ -0,0 +1,111 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestNamespaceDefaults(t *testing.T) {
+	ctx := context.Background()
+	svc := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+
+	_, err := svc.PutNamespaceSettings(ctx, &database.NamespaceSettings{
+		Namespace:  "com.acme",
+		License:    "MIT",
+		Categories: []string{"weather", "maps"},
+		WebsiteURL: "https://acme.com",
+		Contact:    "mcp@acme.com",
+		UpdatedBy:  "octocat",
+	})
+	require.NoError(t, err)
+
+	publish := func(server apiv0.ServerJSON) *apiv0.ServerJSON {
+		t.Helper()
+		server.Description = "A test server"
+		published, err := svc.Publish(ctx, server)
+		require.NoError(t, err)
+		return published
+	}
+
+	var weather string
+	t.Run("unset fields are inherited", func(t *testing.T) {
+		server := publish(apiv0.ServerJSON{Name: "com.acme/weather", Version: "1.0.0"})
+		weather = server.Meta.Official.ID
+		assert.Equal(t, "MIT", server.License)
+		assert.Equal(t, []string{"weather", "maps"}, server.Categories)
+		assert.Equal(t, "https://acme.com", server.WebsiteURL)
+		assert.Equal(t, "mcp@acme.com", server.Contact)
+		assert.Equal(t, []string{"license", "categories", "websiteUrl", "contact"}, server.Meta.Official.InheritedFields)
+	})
+
+	t.Run("explicit values win", func(t *testing.T) {
+		server := publish(apiv0.ServerJSON{
+			Name:       "com.acme/maps",
+			Version:    "1.0.0",
+			License:    "Apache-2.0",
+			Categories: []string{"geo"},
+			Contact:    "maps@acme.com",
+		})
+		assert.Equal(t, "Apache-2.0", server.License)
+		assert.Equal(t, []string{"geo"}, server.Categories)
+		assert.Equal(t, "https://acme.com", server.WebsiteURL)
+		assert.Equal(t, "maps@acme.com", server.Contact)
+		assert.Equal(t, []string{"websiteUrl"}, server.Meta.Official.InheritedFields)
+	})
+
+	t.Run("other namespaces inherit nothing", func(t *testing.T) {
+		server := publish(apiv0.ServerJSON{Name: "com.acme.labs/weather", Version: "1.0.0"})
+		assert.Empty(t, server.License)
+		assert.Empty(t, server.Meta.Official.InheritedFields)
+	})
+
+	t.Run("changed settings apply to later publishes only", func(t *testing.T) {
+		_, err := svc.PutNamespaceSettings(ctx, &database.NamespaceSettings{Namespace: "com.acme", Contact: "support@acme.com"})
+		require.NoError(t, err)
+
+		server := publish(apiv0.ServerJSON{Name: "com.acme/weather", Version: "1.1.0"})
+		assert.Empty(t, server.License)
+		assert.Equal(t, "support@acme.com", server.Contact)
+		assert.Equal(t, []string{"contact"}, server.Meta.Official.InheritedFields)
+
+		first, err := svc.GetByID(ctx, weather)
+		require.NoError(t, err)
+		assert.Equal(t, "MIT", first.License)
+		assert.Equal(t, "mcp@acme.com", first.Contact)
+	})
+}
+
+func TestPutNamespaceSettings_Validation(t *testing.T) {
+	ctx := context.Background()
+	svc := NewRegistryService(database.NewMemoryDB(), &config.Config{ServerCategories: []string{"weather", "maps"}})
+
+	tests := []struct {
+		name     string
+		settings database.NamespaceSettings
+		want     error
+	}{
+		{name: "invalid license", settings: database.NamespaceSettings{License: "MIT OR"}, want: validators.ErrInvalidLicense},
+		{name: "category outside the taxonomy", settings: database.NamespaceSettings{Categories: []string{"games"}}, want: validators.ErrUnknownCategory},
+		{name: "website that is not a URL", settings: database.NamespaceSettings{WebsiteURL: "acme.com"}, want: validators.ErrInvalidWebsiteURL},
+		{name: "blank contact", settings: database.NamespaceSettings{Contact: "  "}, want: validators.ErrInvalidContact},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.settings.Namespace = "com.acme"
+			_, err := svc.PutNamespaceSettings(ctx, &tt.settings)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	_, err := svc.NamespaceSettings(ctx, "com.acme")
+	assert.ErrorIs(t, err, database.ErrNotFound, "rejected settings are not stored")
+}
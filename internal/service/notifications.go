@@ -0,0 +1,473 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/netip"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const (
+	// PublishNotificationEvent is the event name sent with every publish notification
+	PublishNotificationEvent = "server.published"
+	// PackageLinkBrokenEvent is the event name sent when an MCPB package's download URL is found broken
+	PackageLinkBrokenEvent = "package.link_broken"
+	// ServerReportedEvent is the event name sent when the reports of a server version in a category reach the report threshold
+	ServerReportedEvent = "server.reported"
+
+	notificationWorkers   = 4
+	notificationBatchSize = 32
+	notificationTimeout   = 10 * time.Second
+	// notificationAttempts bounds how often a delivery is tried, backing off exponentially from
+	// the dispatcher's backoff up to maxNotificationBackoff; about two hours in all by default
+	notificationAttempts   = 10
+	maxNotificationBackoff = time.Hour
+	// notificationPollInterval is how often the outbox is checked for events written by other
+	// replicas or due for a retry; this replica's publishes wake the dispatcher at once
+	notificationPollInterval = 5 * time.Second
+	// notificationLease is how long a claimed event is hidden from other dispatchers. It must
+	// outlast a delivery attempt; an event whose dispatcher stopped mid-delivery is retried after it.
+	notificationLease = 5 * time.Minute
+)
+
+var (
+	// ErrEmailNotificationsDisabled is returned when registering an email address on a registry without SMTP configured
+	ErrEmailNotificationsDisabled = errors.New("email notifications are not configured on this registry")
+	// ErrInvalidUnsubscribeToken is returned when an unsubscribe token was not issued for the registration
+	ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+)
+
+// Publisher identifies who published a server version, for notifications
+type Publisher struct {
+	AuthMethod string `json:"auth_method"`
+	Subject    string `json:"subject"`
+}
+
+type publisherKey struct{}
+
+// WithPublisher records who is publishing in ctx, so publish notifications can name them
+func WithPublisher(ctx context.Context, publisher Publisher) context.Context {
+	return context.WithValue(ctx, publisherKey{}, publisher)
+}
+
+// publisherFrom returns the publisher recorded by WithPublisher, if any
+func publisherFrom(ctx context.Context) Publisher {
+	publisher, _ := ctx.Value(publisherKey{}).(Publisher)
+	return publisher
+}
+
+// PublishNotification is the JSON body POSTed to webhooks when a server version is published,
+// when one of its package links breaks, or when users report it
+type PublishNotification struct {
+	Event          string             `json:"event"`
+	Namespace      string             `json:"namespace"`
+	Server         NotificationServer `json:"server"`
+	PublishedBy    Publisher          `json:"published_by"`
+	PublishedAt    time.Time          `json:"published_at"`
+	UnsubscribeURL string             `json:"unsubscribe_url"`
+	// IdempotencyKey is the same for every delivery attempt of a notification to a registration,
+	// so receivers can discard duplicates
+	IdempotencyKey string `json:"idempotency_key"`
+
+	// PackageLink is the broken link, for PackageLinkBrokenEvent
+	PackageLink *apiv0.PackageLink `json:"package_link,omitempty"`
+	// Reports is the category that reached the report threshold, for ServerReportedEvent
+	Reports *ReportNotification `json:"reports,omitempty"`
+}
+
+// ReportNotification is how often users reported a server version in one category. The text of
+// the reports is only shown to admins.
+type ReportNotification struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// NotificationServer is the published server version a notification is about
+type NotificationServer struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// RegisterNotification stores a webhook or email registration for publishes under namespace.
+// Exactly one of webhookURL and email must be set.
+func (s *registryServiceImpl) RegisterNotification(ctx context.Context, namespace, webhookURL, email, createdBy string) (*database.NamespaceNotification, error) {
+	if (webhookURL == "") == (email == "") {
+		return nil, fmt.Errorf("%w: exactly one of webhook_url and email is required", database.ErrInvalidInput)
+	}
+	if webhookURL != "" {
+		if err := validateWebhookURL(webhookURL); err != nil {
+			return nil, fmt.Errorf("%w: %w", database.ErrInvalidInput, err)
+		}
+	}
+	if email != "" {
+		if s.cfg.SMTPAddress == "" {
+			return nil, ErrEmailNotificationsDisabled
+		}
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Name != "" || address.Address != email {
+			return nil, fmt.Errorf("%w: invalid email address", database.ErrInvalidInput)
+		}
+	}
+
+	notification := &database.NamespaceNotification{
+		ID:         uuid.New().String(),
+		Namespace:  namespace,
+		WebhookURL: webhookURL,
+		Email:      email,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.db.CreateNamespaceNotification(ctx, notification); err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// Unsubscribe removes a notification registration if token was issued for it
+func (s *registryServiceImpl) Unsubscribe(ctx context.Context, id, token string) error {
+	if !hmac.Equal([]byte(token), []byte(unsubscribeToken(s.cfg, id))) {
+		return ErrInvalidUnsubscribeToken
+	}
+	return s.db.DeleteNamespaceNotification(ctx, id)
+}
+
+// UnsubscribeURL returns the link that removes a notification registration without signing in
+func (s *registryServiceImpl) UnsubscribeURL(id string) string {
+	return unsubscribeURL(s.cfg, id)
+}
+
+// unsubscribeToken signs a registration ID with a key derived from the JWT signing key,
+// so that whoever receives notifications can stop them without a registry token
+func unsubscribeToken(cfg *config.Config, id string) string {
+	key := sha256.Sum256([]byte("namespace-notification-unsubscribe:" + cfg.JWTPrivateKey))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func unsubscribeURL(cfg *config.Config, id string) string {
+	query := url.Values{"token": {unsubscribeToken(cfg, id)}}
+	return strings.TrimSuffix(cfg.PublicURL, "/") + "/v0/notifications/" + url.PathEscape(id) + "/unsubscribe?" + query.Encode()
+}
+
+// validateWebhookURL requires an https URL that does not name a non-public address. It only turns
+// away obviously bad registrations early: a hostname can resolve anywhere, so the dispatcher's
+// client enforces the same address policy again each time it connects.
+func validateWebhookURL(webhookURL string) error {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Host == "" {
+		return errors.New("invalid webhook URL")
+	}
+	if parsed.Scheme != "https" {
+		return errors.New("webhook URL must use https")
+	}
+	host := parsed.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return errors.New("webhook URL must not point at localhost")
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !isPublicAddress(ip) {
+		return errors.New("webhook URL must not point at a private address")
+	}
+	return nil
+}
+
+// NotificationDispatcher delivers publish notifications in the background through a transactional
+// outbox: notifications are written to the database along with the change they describe, one event
+// per registration, and delivered from there with retries. Delivery is at least once; a receiver
+// may see an event more than once, with the same idempotency key.
+type NotificationDispatcher struct {
+	db   database.Database
+	cfg  *config.Config
+	wake chan struct{}
+
+	client       *http.Client
+	sendMail     func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	backoff      time.Duration
+	pollInterval time.Duration
+	lease        time.Duration
+}
+
+// NewNotificationDispatcher creates a dispatcher that delivers the events in db's outbox
+func NewNotificationDispatcher(db database.Database, cfg *config.Config) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		db:   db,
+		cfg:  cfg,
+		wake: make(chan struct{}, 1),
+		// Webhook URLs come from namespace owners: only connect to public addresses, and do
+		// not let a redirect lead somewhere else
+		client:       newPublicClient(notificationTimeout),
+		sendMail:     smtp.SendMail,
+		backoff:      10 * time.Second,
+		pollInterval: notificationPollInterval,
+		lease:        notificationLease,
+	}
+}
+
+// enqueue writes notification to the outbox, one event for each registration for its namespace.
+// Call it with the transaction that makes the change, and Wake once that commits.
+func (d *NotificationDispatcher) enqueue(ctx context.Context, tx database.Database, notification PublishNotification) error {
+	registrations, err := tx.ListNamespaceNotifications(ctx, notification.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to load notification registrations for %s: %w", notification.Namespace, err)
+	}
+
+	now := time.Now()
+	for _, registration := range registrations {
+		id := uuid.New().String()
+		personal := notification
+		personal.UnsubscribeURL = unsubscribeURL(d.cfg, registration.ID)
+		personal.IdempotencyKey = id
+		payload, err := json.Marshal(personal)
+		if err != nil {
+			return err
+		}
+		if err := tx.CreateOutboxEvent(ctx, &database.OutboxEvent{
+			ID:             id,
+			RegistrationID: registration.ID,
+			WebhookURL:     registration.WebhookURL,
+			Email:          registration.Email,
+			Event:          notification.Event,
+			Payload:        payload,
+			CreatedAt:      now,
+			NextAttemptAt:  now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Wake makes the dispatcher check the outbox now rather than at its next poll
+func (d *NotificationDispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start delivers outbox events in the background until ctx is cancelled. Events left over from
+// before a restart, including ones a stopped dispatcher was delivering, are delivered once their
+// lease passes.
+func (d *NotificationDispatcher) Start(ctx context.Context) {
+	go d.Run(ctx)
+}
+
+// Run delivers outbox events until ctx is cancelled, returning once the deliveries in progress
+// have stopped
+func (d *NotificationDispatcher) Run(ctx context.Context) {
+	for {
+		d.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+// deliverDue delivers the events that are due, a batch at a time
+func (d *NotificationDispatcher) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := d.db.ClaimOutboxEvents(ctx, time.Now(), d.lease, notificationBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to claim notification outbox events: %v", err)
+			}
+			return
+		}
+
+		var wg sync.WaitGroup
+		workers := make(chan struct{}, notificationWorkers)
+		for _, event := range events {
+			wg.Add(1)
+			workers <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-workers }()
+				d.deliver(ctx, event)
+			}()
+		}
+		wg.Wait()
+
+		if len(events) < notificationBatchSize {
+			return
+		}
+	}
+}
+
+// deliver makes one delivery attempt for a claimed event, then completes it or schedules a retry
+func (d *NotificationDispatcher) deliver(ctx context.Context, event *database.OutboxEvent) {
+	var notification PublishNotification
+	err := json.Unmarshal(event.Payload, &notification)
+	if err == nil {
+		if event.WebhookURL != "" {
+			err = d.postWebhook(ctx, event.WebhookURL, notification)
+		} else {
+			err = d.sendEmail(event.Email, notification)
+		}
+	}
+	if ctx.Err() != nil {
+		// Stopping: the event is claimed again once its lease passes
+		return
+	}
+
+	switch {
+	case err == nil:
+		err = d.db.CompleteOutboxEvent(ctx, event.ID, time.Now(), "")
+	case event.Attempts >= notificationAttempts:
+		log.Printf("Giving up on %s notification %s to registration %s after %d attempts: %v",
+			event.Event, event.ID, event.RegistrationID, event.Attempts, err)
+		err = d.db.CompleteOutboxEvent(ctx, event.ID, time.Now(), err.Error())
+	default:
+		err = d.db.RetryOutboxEvent(ctx, event.ID, time.Now().Add(d.retryDelay(event.Attempts)), err.Error())
+	}
+	// The registration may have been removed meanwhile, taking the event with it
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		log.Printf("Failed to record delivery of notification %s: %v", event.ID, err)
+	}
+}
+
+// retryDelay is how long to wait after a delivery's attempt-th failure
+func (d *NotificationDispatcher) retryDelay(attempt int) time.Duration {
+	delay := d.backoff
+	for range attempt - 1 {
+		delay *= 2
+		if delay >= maxNotificationBackoff {
+			return maxNotificationBackoff
+		}
+	}
+	return delay
+}
+
+func (d *NotificationDispatcher) postWebhook(ctx context.Context, webhookURL string, notification PublishNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MCP-Registry-Notifications")
+	req.Header.Set("Idempotency-Key", notification.IdempotencyKey)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *NotificationDispatcher) sendEmail(to string, notification PublishNotification) error {
+	if d.cfg.SMTPAddress == "" {
+		return ErrEmailNotificationsDisabled
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", d.cfg.SMTPFrom)
+	fmt.Fprintf(&body, "To: %s\r\n", to)
+	// Every attempt sends the same Message-ID, so mail systems can discard duplicates
+	fmt.Fprintf(&body, "Message-ID: <%s@mcp-registry>\r\n", notification.IdempotencyKey)
+	if link := notification.PackageLink; link != nil {
+		fmt.Fprintf(&body, "Subject: A package link of %s %s is broken\r\n", notification.Server.Name, notification.Server.Version)
+		body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		fmt.Fprintf(&body, "The MCP registry can no longer download a package of %s version %s:\r\n\r\n%s\r\n\r\n",
+			notification.Server.Name, notification.Server.Version, link.Identifier)
+		if link.LastHTTPStatus != 0 {
+			fmt.Fprintf(&body, "The last check returned HTTP %d. ", link.LastHTTPStatus)
+		}
+		if link.FailingSince != nil {
+			fmt.Fprintf(&body, "It has been failing since %s. ", link.FailingSince.Format(time.RFC3339))
+		}
+		body.WriteString("Publish a new version with a working URL to fix it.\r\n\r\n")
+	} else if reports := notification.Reports; reports != nil {
+		fmt.Fprintf(&body, "Subject: %s %s was reported as %s\r\n", notification.Server.Name, notification.Server.Version, reports.Category)
+		body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		fmt.Fprintf(&body, "Users of the MCP registry have reported %s version %s as %s %d times. Registry admins have been asked to look into it.\r\n\r\n",
+			notification.Server.Name, notification.Server.Version, reports.Category, reports.Count)
+	} else {
+		fmt.Fprintf(&body, "Subject: %s %s was published to the MCP registry\r\n", notification.Server.Name, notification.Server.Version)
+		body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		fmt.Fprintf(&body, "%s version %s was published under the %s namespace.\r\n\r\n",
+			notification.Server.Name, notification.Server.Version, notification.Namespace)
+	}
+	fmt.Fprintf(&body, "Server ID: %s\r\nStatus: %s\r\n", notification.Server.ID, notification.Server.Status)
+	if notification.PublishedBy.Subject != "" {
+		fmt.Fprintf(&body, "Published by: %s (%s)\r\n", notification.PublishedBy.Subject, notification.PublishedBy.AuthMethod)
+	}
+	fmt.Fprintf(&body, "Published at: %s\r\n\r\n", notification.PublishedAt.Format(time.RFC3339))
+	fmt.Fprintf(&body, "To stop these emails, visit %s\r\n", notification.UnsubscribeURL)
+
+	var smtpAuth smtp.Auth
+	if d.cfg.SMTPUsername != "" {
+		host, _, _ := net.SplitHostPort(d.cfg.SMTPAddress)
+		smtpAuth = smtp.PlainAuth("", d.cfg.SMTPUsername, d.cfg.SMTPPassword, host)
+	}
+	return d.sendMail(d.cfg.SMTPAddress, smtpAuth, d.cfg.SMTPFrom, []string{to}, []byte(body.String()))
+}
+
+// queuePublishNotification writes notifications for a newly published server version to the
+// outbox in tx, if a dispatcher is configured
+func (s *registryServiceImpl) queuePublishNotification(ctx context.Context, tx database.Database, server *apiv0.ServerJSON) error {
+	if s.notifications == nil || server.Meta == nil || server.Meta.Official == nil {
+		return nil
+	}
+	namespace, _, _ := strings.Cut(server.Name, "/")
+	status := server.Status
+	if status == "" {
+		status = model.StatusActive // the schema default
+	}
+	// Registrations belong to the tenant of the server, whoever is publishing
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(server))
+	return s.notifications.enqueue(ctx, tx, PublishNotification{
+		Event:     PublishNotificationEvent,
+		Namespace: namespace,
+		Server: NotificationServer{
+			ID:      server.Meta.Official.ID,
+			Name:    server.Name,
+			Version: server.Version,
+			Status:  string(status),
+		},
+		PublishedBy: publisherFrom(ctx),
+		PublishedAt: server.Meta.Official.PublishedAt,
+	})
+}
+
+// wakeNotifications starts delivering notifications written by a committed transaction, if a dispatcher is configured
+func (s *registryServiceImpl) wakeNotifications() {
+	if s.notifications != nil {
+		s.notifications.Wake()
+	}
+}
@@ -0,0 +1,287 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishNotifications(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan PublishNotification, 1)
+	attempts := 0
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable) // retried
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var notification PublishNotification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		received <- notification
+	}))
+	defer webhook.Close()
+
+	emails := make(chan string, 1)
+	cfg := &config.Config{
+		JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c",
+		PublicURL:     "https://registry.example.com",
+		SMTPAddress:   "smtp.example.com:587",
+		SMTPFrom:      "registry@example.com",
+	}
+	db := database.NewMemoryDB()
+	dispatcher := NewNotificationDispatcher(db, cfg)
+	allowLoopback(dispatcher.client)
+	dispatcher.backoff = time.Millisecond
+	dispatcher.pollInterval = 10 * time.Millisecond
+	dispatcher.sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, cfg.SMTPAddress, addr)
+		assert.Equal(t, cfg.SMTPFrom, from)
+		assert.Equal(t, []string{"owner@example.com"}, to)
+		emails <- string(msg)
+		return nil
+	}
+	dispatcher.Start(ctx)
+	s := NewRegistryService(db, cfg, WithNotifications(dispatcher)).(*registryServiceImpl)
+
+	// Registrations are validated by the API; store the test server's http URL directly
+	hook := &database.NamespaceNotification{ID: "4e0f8a3c-8f0d-4c1b-9d55-0d4e6f6d8a11", Namespace: "io.github.octocat", WebhookURL: webhook.URL, CreatedAt: time.Now()}
+	require.NoError(t, db.CreateNamespaceNotification(ctx, hook))
+	mail, err := s.RegisterNotification(ctx, "io.github.octocat", "", "owner@example.com", "octocat")
+	require.NoError(t, err)
+	other := &database.NamespaceNotification{ID: "9b7c3a5e-2f1d-4e6a-8c0b-7d9e1f2a3b4c", Namespace: "io.github.someone-else", WebhookURL: webhook.URL, CreatedAt: time.Now()}
+	require.NoError(t, db.CreateNamespaceNotification(ctx, other))
+
+	publishCtx := WithPublisher(ctx, Publisher{AuthMethod: "github-at", Subject: "octocat"})
+	published, err := s.Publish(publishCtx, apiv0.ServerJSON{
+		Name:        "io.github.octocat/weather",
+		Description: "Weather lookups",
+		Version:     "1.2.0",
+	})
+	require.NoError(t, err)
+
+	select {
+	case notification := <-received:
+		assert.Equal(t, PublishNotificationEvent, notification.Event)
+		assert.Equal(t, "io.github.octocat", notification.Namespace)
+		assert.Equal(t, NotificationServer{
+			ID:      published.Meta.Official.ID,
+			Name:    "io.github.octocat/weather",
+			Version: "1.2.0",
+			Status:  "active",
+		}, notification.Server)
+		assert.Equal(t, Publisher{AuthMethod: "github-at", Subject: "octocat"}, notification.PublishedBy)
+		assert.True(t, published.Meta.Official.PublishedAt.Equal(notification.PublishedAt))
+		assert.Equal(t, unsubscribeURL(cfg, hook.ID), notification.UnsubscribeURL)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+
+	select {
+	case msg := <-emails:
+		assert.Contains(t, msg, "To: owner@example.com\r\n")
+		assert.Contains(t, msg, "io.github.octocat/weather version 1.2.0")
+		assert.Contains(t, msg, "Published by: octocat (github-at)")
+		assert.Contains(t, msg, unsubscribeURL(cfg, mail.ID))
+	case <-time.After(5 * time.Second):
+		t.Fatal("email was not sent")
+	}
+
+	// Only the publishing namespace's registrations are notified
+	select {
+	case notification := <-received:
+		t.Fatalf("unexpected notification for %s", notification.Namespace)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	s := NewRegistryService(database.NewMemoryDB(), cfg).(*registryServiceImpl)
+	ctx := context.Background()
+
+	registration, err := s.RegisterNotification(ctx, "io.github.octocat", "https://hooks.example.com/mcp", "", "octocat")
+	require.NoError(t, err)
+
+	token := unsubscribeToken(cfg, registration.ID)
+	assert.NotEqual(t, token, unsubscribeToken(cfg, "00000000-0000-0000-0000-000000000000"))
+	rotated := &config.Config{JWTPrivateKey: strings.Repeat("ab", 32)}
+	assert.NotEqual(t, token, unsubscribeToken(rotated, registration.ID))
+
+	assert.ErrorIs(t, s.Unsubscribe(ctx, registration.ID, token[:len(token)-1]), ErrInvalidUnsubscribeToken)
+	assert.ErrorIs(t, s.Unsubscribe(ctx, "00000000-0000-0000-0000-000000000000", token), ErrInvalidUnsubscribeToken)
+	require.NoError(t, s.Unsubscribe(ctx, registration.ID, token))
+	assert.ErrorIs(t, s.Unsubscribe(ctx, registration.ID, token), database.ErrNotFound)
+}
+
+func TestNotificationOutbox_RedeliveredAfterCrash(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var keys []string
+	started := make(chan struct{})
+	received := make(chan PublishNotification, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification PublishNotification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		first := len(keys) == 1
+		mu.Unlock()
+		if first {
+			// Hang until the dispatcher is killed mid-delivery
+			close(started)
+			<-r.Context().Done()
+			return
+		}
+		received <- notification
+	}))
+	defer webhook.Close()
+
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	db := database.NewMemoryDB()
+	hook := &database.NamespaceNotification{ID: "4e0f8a3c-8f0d-4c1b-9d55-0d4e6f6d8a11", Namespace: "io.github.octocat", WebhookURL: webhook.URL, CreatedAt: time.Now()}
+	require.NoError(t, db.CreateNamespaceNotification(ctx, hook))
+
+	crashed := NewNotificationDispatcher(db, cfg)
+	allowLoopback(crashed.client)
+	crashed.lease = 100 * time.Millisecond
+	crashedCtx, crash := context.WithCancel(ctx)
+	crashed.Start(crashedCtx)
+
+	s := NewRegistryService(db, cfg, WithNotifications(crashed))
+	_, err := s.Publish(ctx, apiv0.ServerJSON{Name: "io.github.octocat/weather", Description: "Weather lookups", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+	crash()
+
+	// The event stays claimed by the stopped dispatcher until its lease passes
+	events, err := db.ClaimOutboxEvents(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	restarted := NewNotificationDispatcher(db, cfg)
+	allowLoopback(restarted.client)
+	restarted.pollInterval = 10 * time.Millisecond
+	restarted.Start(ctx)
+
+	select {
+	case notification := <-received:
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, keys, 2)
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1], "a redelivery has the same idempotency key")
+		assert.Equal(t, keys[0], notification.IdempotencyKey)
+		assert.Equal(t, "io.github.octocat/weather", notification.Server.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not redelivered after the restart")
+	}
+
+	require.Eventually(t, func() bool {
+		events, err := db.ClaimOutboxEvents(ctx, time.Now().Add(time.Hour), time.Minute, 10)
+		return err == nil && len(events) == 0
+	}, 5*time.Second, 10*time.Millisecond, "the delivered event is completed")
+}
+
+// failingOutboxDB fails to write outbox events
+type failingOutboxDB struct {
+	database.Database
+}
+
+func (db *failingOutboxDB) InTransaction(ctx context.Context, fn func(ctx context.Context, tx database.Database) error) error {
+	return db.Database.InTransaction(ctx, func(ctx context.Context, tx database.Database) error {
+		return fn(ctx, &failingOutboxDB{Database: tx})
+	})
+}
+
+func (db *failingOutboxDB) CreateOutboxEvent(context.Context, *database.OutboxEvent) error {
+	return errors.New("outbox unavailable")
+}
+
+func TestNotificationOutbox_WrittenWithThePublish(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{JWTPrivateKey: "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"}
+	memDB := database.NewMemoryDB()
+	require.NoError(t, memDB.CreateNamespaceNotification(ctx, &database.NamespaceNotification{
+		ID: "4e0f8a3c-8f0d-4c1b-9d55-0d4e6f6d8a11", Namespace: "com.example", Email: "owner@example.com", CreatedAt: time.Now(),
+	}))
+	server := apiv0.ServerJSON{Name: "com.example/weather", Description: "Weather lookups", Version: "1.0.0"}
+
+	// A publish whose notifications cannot be written is not stored either
+	db := &failingOutboxDB{Database: memDB}
+	_, err := NewRegistryService(db, cfg, WithNotifications(NewNotificationDispatcher(db, cfg))).Publish(ctx, server)
+	require.ErrorContains(t, err, "outbox unavailable")
+	_, err = memDB.FindHead(ctx, server.Name, "")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	published, err := NewRegistryService(memDB, cfg, WithNotifications(NewNotificationDispatcher(memDB, cfg))).Publish(ctx, server)
+	require.NoError(t, err)
+	events, err := memDB.ClaimOutboxEvents(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "owner@example.com", events[0].Email)
+	assert.Equal(t, PublishNotificationEvent, events[0].Event)
+	assert.Equal(t, 1, events[0].Attempts)
+	var notification PublishNotification
+	require.NoError(t, json.Unmarshal(events[0].Payload, &notification))
+	assert.Equal(t, published.Meta.Official.ID, notification.Server.ID)
+	assert.Equal(t, events[0].ID, notification.IdempotencyKey)
+
+	// Removing the registration discards its undelivered events
+	require.NoError(t, memDB.DeleteNamespaceNotification(ctx, events[0].RegistrationID))
+	assert.ErrorIs(t, memDB.CompleteOutboxEvent(ctx, events[0].ID, time.Now(), ""), database.ErrNotFound)
+}
+
+func TestNotificationRetryDelay(t *testing.T) {
+	dispatcher := NewNotificationDispatcher(database.NewMemoryDB(), &config.Config{})
+	assert.Equal(t, 10*time.Second, dispatcher.retryDelay(1))
+	assert.Equal(t, 20*time.Second, dispatcher.retryDelay(2))
+	assert.Equal(t, 80*time.Second, dispatcher.retryDelay(4))
+	assert.Equal(t, maxNotificationBackoff, dispatcher.retryDelay(notificationAttempts))
+}
+
+func TestNotificationDispatcher_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	_, port, err := net.SplitHostPort(webhook.Listener.Addr().String())
+	require.NoError(t, err)
+
+	// Registration only rejects URLs that name a private address; the dispatcher refuses the
+	// connection whatever the hostname resolves to
+	dispatcher := NewNotificationDispatcher(database.NewMemoryDB(), &config.Config{})
+	for _, webhookURL := range []string{webhook.URL, "http://localhost:" + port} {
+		err := dispatcher.postWebhook(context.Background(), webhookURL+"/hook", PublishNotification{IdempotencyKey: "key"})
+		require.ErrorIs(t, err, errNonPublicAddress, webhookURL)
+	}
+	assert.False(t, called, "webhooks at private addresses are never called")
+}
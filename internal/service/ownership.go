@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// NamespaceOwnership returns the latest verification of a namespace, or database.ErrNotFound
+// if nobody has published under it with a verified identity
+func (s *registryServiceImpl) NamespaceOwnership(ctx context.Context, namespace string) (*database.NamespaceVerification, error) {
+	return s.db.GetNamespaceVerification(ctx, namespace)
+}
+
+// recordVerification notes that the publisher in ctx proved control of the namespace of
+// server name at publishedAt. Publishes without a verified identity, such as anonymous ones
+// or imports, leave the record unchanged.
+func recordVerification(ctx context.Context, tx database.Database, name string, publishedAt time.Time) error {
+	publisher := publisherFrom(ctx)
+	if publisher.AuthMethod == "" || publisher.AuthMethod == string(auth.MethodNone) {
+		return nil
+	}
+	namespace, _, _ := strings.Cut(name, "/")
+	return tx.RecordNamespaceVerification(ctx, &database.NamespaceVerification{
+		Namespace:  namespace,
+		Method:     publisher.AuthMethod,
+		Subject:    publisher.Subject,
+		VerifiedAt: publishedAt,
+	})
+}
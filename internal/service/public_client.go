@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errNonPublicAddress is returned when a request to a publisher-supplied URL would connect
+// to an address inside the registry's network
+var errNonPublicAddress = errors.New("refusing to connect to a non-public address")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which net.IP does not count as private
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// isPublicAddress reports whether ip may be reached by requests to publisher-supplied URLs
+func isPublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// publicDialer returns a dialer that refuses connections to non-public addresses. The check runs
+// on the address being connected to, after DNS resolution, so a hostname that resolves to a
+// private address is refused just like the address itself.
+func publicDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errNonPublicAddress, address)
+			}
+			if !isPublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", errNonPublicAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+}
+
+// newPublicClient returns a client for requests to publisher-supplied URLs. It only connects to
+// public addresses, never through a proxy, which would make the dial check meaningless, and does
+// not follow redirects.
+func newPublicClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = publicDialer().DialContext
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
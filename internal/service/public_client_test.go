@@ -0,0 +1,47 @@
+//nolint:testpackage
+package service
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// allowLoopback lets client reach httptest servers, which listen on loopback addresses,
+// while keeping its redirect policy
+func allowLoopback(client *http.Client) {
+	client.Transport = http.DefaultTransport
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.215.14", true},
+		{"2606:2800:21f:cb07:6820:80da:af6b:8b2c", true},
+		{"100.63.255.255", true},
+		{"100.128.0.0", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, isPublicAddress(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
@@ -4,37 +4,121 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/markdown"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
 	"github.com/modelcontextprotocol/registry/internal/validators"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 const maxServerVersionsPerServer = 10000
 
 // registryServiceImpl implements the RegistryService interface using our Database
 type registryServiceImpl struct {
-	db  database.Database
-	cfg *config.Config
+	db         database.Database
+	cfg        *config.Config
+	generation atomic.Uint64
+
+	metrics     *telemetry.Metrics
+	latest      *latestCache
+	invalidator CacheInvalidator
+	listenCtx   context.Context
+	// invalidationsLive is set while the invalidation channel is listening; while it is not,
+	// writes on other replicas go unnoticed
+	invalidationsLive atomic.Bool
+
+	notifications *NotificationDispatcher
+	changes       *ChangeFeed
+	fetches       *FetchRecorder
+	reservations  reservationCache
+	fieldUsage    fieldUsageCache
+}
+
+// Option configures optional registry service dependencies
+type Option func(*registryServiceImpl)
+
+// WithMetrics records cache metrics
+func WithMetrics(metrics *telemetry.Metrics) Option {
+	return func(s *registryServiceImpl) {
+		s.metrics = metrics
+	}
+}
+
+// WithCacheInvalidator shares latest-version cache invalidations with other replicas,
+// listening for theirs until ctx is cancelled
+func WithCacheInvalidator(ctx context.Context, invalidator CacheInvalidator) Option {
+	return func(s *registryServiceImpl) {
+		s.invalidator = invalidator
+		s.listenCtx = ctx
+	}
+}
+
+// WithNotifications queues publish notifications for namespace owners on dispatcher
+func WithNotifications(dispatcher *NotificationDispatcher) Option {
+	return func(s *registryServiceImpl) {
+		s.notifications = dispatcher
+	}
+}
+
+// WithFetchRecorder counts fetches of server details on recorder
+func WithFetchRecorder(recorder *FetchRecorder) Option {
+	return func(s *registryServiceImpl) {
+		s.fetches = recorder
+	}
 }
 
 // NewRegistryService creates a new registry service with the provided database
-func NewRegistryService(db database.Database, cfg *config.Config) RegistryService {
-	return &registryServiceImpl{
+func NewRegistryService(db database.Database, cfg *config.Config, opts ...Option) RegistryService {
+	s := &registryServiceImpl{
 		db:  db,
 		cfg: cfg,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	switch {
+	case cfg.LatestCacheSize <= 0:
+		s.latest = newLatestCache(0, false, s.metrics)
+	case cfg.TenancyEnabled:
+		// Tenants publish servers of the same name, which the cache keys entries by
+		log.Printf("Latest-version cache disabled: multi-tenant mode is enabled")
+		s.latest = newLatestCache(0, false, s.metrics)
+	case s.invalidator != nil:
+		// Enabled once the invalidation channel is listening
+		s.latest = newLatestCache(cfg.LatestCacheSize, false, s.metrics)
+	case cfg.Replicas > 1:
+		log.Printf("Latest-version cache disabled: %d replicas configured without a cache invalidation channel", cfg.Replicas)
+		s.latest = newLatestCache(0, false, s.metrics)
+	default:
+		s.latest = newLatestCache(cfg.LatestCacheSize, true, s.metrics)
+	}
+	if s.invalidator != nil {
+		// Other replicas' writes also change what Generation reports, so the list cache sees them
+		go s.listenForInvalidations(s.listenCtx)
+	}
+
+	return s
 }
 
-// List returns registry entries with cursor-based pagination and optional filtering
-func (s *registryServiceImpl) List(filter *database.ServerFilter, cursor string, limit int) ([]apiv0.ServerJSON, string, error) {
-	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// Count returns how many registry entries match the filter
+func (s *registryServiceImpl) Count(ctx context.Context, filter *database.ServerFilter) (int, error) {
+	return s.db.Count(ctx, filter)
+}
 
+// List returns registry entries with cursor-based pagination and optional filtering
+func (s *registryServiceImpl) List(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]apiv0.ServerJSON, string, error) {
 	// If limit is not set or negative, use a default limit
 	if limit <= 0 {
 		limit = 30
@@ -56,11 +140,7 @@ func (s *registryServiceImpl) List(filter *database.ServerFilter, cursor string,
 }
 
 // GetByID retrieves a specific server by its registry metadata ID in flattened format
-func (s *registryServiceImpl) GetByID(id string) (*apiv0.ServerJSON, error) {
-	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
+func (s *registryServiceImpl) GetByID(ctx context.Context, id string) (*apiv0.ServerJSON, error) {
 	serverRecord, err := s.db.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -70,25 +150,161 @@ func (s *registryServiceImpl) GetByID(id string) (*apiv0.ServerJSON, error) {
 	return serverRecord, nil
 }
 
+// GetByIDs retrieves several servers by registry metadata ID with a single database lookup,
+// for responses composed from many servers
+func (s *registryServiceImpl) GetByIDs(ctx context.Context, ids []string) (map[string]*apiv0.ServerJSON, []string, error) {
+	return s.db.GetByIDs(ctx, ids)
+}
+
+// GetHeadByID retrieves the registry metadata of a server version by ID
+func (s *registryServiceImpl) GetHeadByID(ctx context.Context, id string) (*database.ServerHead, error) {
+	return s.db.GetHeadByID(ctx, id)
+}
+
+// FindHead retrieves the registry metadata of a server version by name, or of its latest version if version is empty
+func (s *registryServiceImpl) FindHead(ctx context.Context, name, version string) (*database.ServerHead, error) {
+	return s.db.FindHead(ctx, name, version)
+}
+
+// GetLatestByName retrieves the latest version of a server by name
+func (s *registryServiceImpl) GetLatestByName(ctx context.Context, name string) (*apiv0.ServerJSON, error) {
+	server, err := s.latestByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if server == nil {
+		return nil, database.ErrNotFound
+	}
+	return server, nil
+}
+
+// latestByName returns the latest version of a server, or nil if it has none, from the cache when possible
+func (s *registryServiceImpl) latestByName(ctx context.Context, name string) (*apiv0.ServerJSON, error) {
+	cached, ok, token := s.latest.get(ctx, name)
+	if ok {
+		return cached, nil
+	}
+
+	isLatest := true
+	filter := &database.ServerFilter{Name: &name, IsLatest: &isLatest}
+	servers, _, err := s.db.List(ctx, filter, "", 1)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return nil, err
+	}
+
+	var server *apiv0.ServerJSON
+	if len(servers) > 0 {
+		server = servers[0]
+	}
+	s.latest.put(name, server, token)
+	return server, nil
+}
+
+// invalidateLatest drops the cached latest version of name on this and every other replica.
+// Writes call it both before and after changing a server, so that no replica can cache a
+// record read while the write was in flight.
+func (s *registryServiceImpl) invalidateLatest(ctx context.Context, name string) error {
+	s.latest.invalidate(name)
+	if s.invalidator == nil {
+		return nil
+	}
+	if err := s.invalidator.Invalidate(ctx, name); err != nil {
+		return fmt.Errorf("failed to invalidate latest-version cache: %w", err)
+	}
+	return nil
+}
+
+// invalidateLatestAfterWrite is invalidateLatest for after a write has committed, when failing is no longer an option
+func (s *registryServiceImpl) invalidateLatestAfterWrite(ctx context.Context, name string) {
+	if err := s.invalidateLatest(context.WithoutCancel(ctx), name); err != nil {
+		log.Printf("Failed to invalidate latest version of %s: %v", name, err)
+	}
+}
+
+// recordWarnings counts the warnings of a successful publish or edit by code
+func (s *registryServiceImpl) recordWarnings(ctx context.Context, operation string) {
+	if s.metrics == nil {
+		return
+	}
+	for _, warning := range validators.WarningsFrom(ctx) {
+		s.metrics.PublishWarnings.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("code", warning.Code), attribute.String("operation", operation)))
+	}
+}
+
 // Publish publishes a server with flattened _meta extensions
-func (s *registryServiceImpl) Publish(req apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
-	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+func (s *registryServiceImpl) Publish(ctx context.Context, req apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	// The versions and latest version checked against must be current, not a replica's
+	ctx = database.WithPrimaryReads(ctx)
+
+	// Every server of a multi-tenant registry belongs to the tenant that published it
+	tenant, scoped := tenancy.FromContext(ctx)
+	if s.cfg.TenancyEnabled && !scoped {
+		return nil, tenancy.ErrTenantRequired
+	}
+
+	// Reject invalid UTF-8 and store text in NFC
+	if err := validators.NormalizeServerJSON(&req); err != nil {
+		return nil, err
+	}
+
+	// Trim the version and strip a leading v, so versions order and display consistently
+	if err := validators.NormalizeServerVersion(ctx, &req, s.cfg.StrictVersionPrefix); err != nil {
+		return nil, err
+	}
+
+	// Optional fields left unset take their namespace's defaults, and are validated with the rest
+	inherited, err := s.applyNamespaceDefaults(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	// A conditional publish of a version that would not become the latest is skipped before
+	// the request is validated, so pipelines that republish unchanged servers stay cheap
+	if err := s.checkVersionNewer(ctx, req.Name, req.Version, time.Now()); err != nil {
+		return nil, err
+	}
 
 	// Validate the request
-	if err := validators.ValidatePublishRequest(req, s.cfg); err != nil {
+	if err := validators.ValidatePublishRequest(ctx, req, s.cfg); err != nil {
+		return nil, err
+	}
+
+	// Only the identities a reservation allows may publish under a reserved namespace
+	if err := s.CheckNamespaceReservation(ctx, req.Name, publisherFrom(ctx)); err != nil {
 		return nil, err
 	}
 
 	publishTime := time.Now()
 	serverJSON := req
+	sanitizeMarkdown(&serverJSON)
+
+	// Fill in or verify the repository ID against the hosting provider
+	if err := s.resolveRepositoryID(ctx, &serverJSON); err != nil {
+		return nil, err
+	}
 
 	// Check for duplicate remote URLs
 	if err := s.validateNoDuplicateRemoteURLs(ctx, serverJSON); err != nil {
 		return nil, err
 	}
 
+	// Check the server it is a fork of exists and the fork closes no cycle
+	if err := s.validateForkLineage(ctx, serverJSON); err != nil {
+		return nil, err
+	}
+
+	// Hold versions in a brand-new namespace for admin approval when it resembles an established
+	// one, or when every new namespace is reviewed
+	reason, err := s.holdReason(ctx, serverJSON.Name)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		log.Printf("Warning: holding %s %s for admin approval: %s", serverJSON.Name, serverJSON.Version, reason)
+		serverJSON.Status = model.StatusPending
+	}
+
 	filter := &database.ServerFilter{Name: &serverJSON.Name}
 	existingServerVersions, _, err := s.db.List(ctx, filter, "", maxServerVersionsPerServer)
 	if err != nil && !errors.Is(err, database.ErrNotFound) {
@@ -109,20 +325,11 @@ func (s *registryServiceImpl) Publish(req apiv0.ServerJSON) (*apiv0.ServerJSON,
 	}
 
 	// Determine if this version should be marked as latest
-	existingLatest := s.getCurrentLatestVersion(existingServerVersions)
-	isNewLatest := true
-	if existingLatest != nil {
-		var existingPublishedAt time.Time
-		if existingLatest.Meta != nil && existingLatest.Meta.Official != nil {
-			existingPublishedAt = existingLatest.Meta.Official.PublishedAt
-		}
-		isNewLatest = CompareVersions(
-			serverJSON.Version,
-			existingLatest.Version,
-			publishTime,
-			existingPublishedAt,
-		) > 0
+	existingLatest, err := s.latestByName(ctx, serverJSON.Name)
+	if err != nil {
+		return nil, err
 	}
+	isNewLatest := newerThanLatest(serverJSON.Version, publishTime, existingLatest)
 
 	// Create complete server with metadata
 	server := serverJSON // Copy the input
@@ -138,91 +345,223 @@ func (s *registryServiceImpl) Publish(req apiv0.ServerJSON) (*apiv0.ServerJSON,
 		PublishedAt: publishTime,
 		UpdatedAt:   publishTime,
 		IsLatest:    isNewLatest,
+		HasReadme:   server.Readme != "",
+		Tenant:      tenant,
+
+		InheritedFields: inherited,
 	}
 
-	// Create server in database
-	serverRecord, err := s.db.CreateServer(ctx, &server)
-	if err != nil {
+	if err := s.invalidateLatest(ctx, serverJSON.Name); err != nil {
 		return nil, err
 	}
+	defer s.invalidateLatestAfterWrite(ctx, serverJSON.Name)
+
+	// Create the new version and demote the previous latest together, so a
+	// cancelled request never leaves two latest versions or an orphaned write
+	var serverRecord *apiv0.ServerJSON
+	err = s.db.InTransaction(ctx, func(ctx context.Context, tx database.Database) error {
+		created, err := tx.CreateServer(ctx, &server)
+		if err != nil {
+			return err
+		}
+		serverRecord = created
 
-	// Mark previous latest as no longer latest
-	if isNewLatest && existingLatest != nil {
-		var existingLatestID string
-		if existingLatest.Meta != nil && existingLatest.Meta.Official != nil {
-			existingLatestID = existingLatest.Meta.Official.ID
+		if err := recordVerification(ctx, tx, server.Name, publishTime); err != nil {
+			return err
 		}
-		if existingLatestID != "" {
-			// Update the existing server to set is_latest = false
-			existingLatest.Meta.Official.IsLatest = false
-			existingLatest.Meta.Official.UpdatedAt = time.Now()
-			if _, err := s.db.UpdateServer(ctx, existingLatestID, existingLatest); err != nil {
-				return nil, err
+		if err := s.queuePublishNotification(ctx, tx, created); err != nil {
+			return err
+		}
+
+		// Mark previous latest as no longer latest
+		if isNewLatest && existingLatest != nil && existingLatest.Meta != nil && existingLatest.Meta.Official != nil {
+			// Update a copy so the stored record is untouched if the transaction rolls back
+			official := *existingLatest.Meta.Official
+			official.IsLatest = false
+			official.UpdatedAt = time.Now()
+			meta := *existingLatest.Meta
+			meta.Official = &official
+			demoted := *existingLatest
+			demoted.Meta = &meta
+
+			if _, err := tx.UpdateServer(ctx, official.ID, &demoted); err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.generation.Add(1)
+	s.wakeNotifications()
+	s.recordChange(ChangePublished, serverRecord)
+	s.recordWarnings(ctx, "publish")
 
 	// Return the server record directly
 	return serverRecord, nil
 }
 
-// validateNoDuplicateRemoteURLs checks that no other server is using the same remote URLs
+// validateNoDuplicateRemoteURLs looks for other servers already declaring the remote URLs of
+// serverDetail, comparing canonical URLs. Deleted and rejected versions don't count, and neither
+// do URLs under a configured shared gateway. A conflict is rejected when the registry is
+// configured to, and otherwise added to ctx as a warning.
 func (s *registryServiceImpl) validateNoDuplicateRemoteURLs(ctx context.Context, serverDetail apiv0.ServerJSON) error {
-	// Check each remote URL in the new server for conflicts
-	for _, remote := range serverDetail.Remotes {
-		// Use filter to find servers with this remote URL
-		filter := &database.ServerFilter{RemoteURL: &remote.URL}
+	for i, remote := range serverDetail.Remotes {
+		if s.isSharedRemoteURL(remote.URL) {
+			continue
+		}
 
+		filter := &database.ServerFilter{RemoteURL: &remote.URL}
 		conflictingServers, _, err := s.db.List(ctx, filter, "", 1000)
 		if err != nil {
 			return fmt.Errorf("failed to check remote URL conflict: %w", err)
 		}
 
-		// Check if any conflicting server has a different name
 		for _, conflictingServer := range conflictingServers {
-			if conflictingServer.Name != serverDetail.Name {
+			if conflictingServer.Name == serverDetail.Name ||
+				conflictingServer.Status == model.StatusDeleted || conflictingServer.Status == model.StatusRejected {
+				continue
+			}
+			if s.cfg.RejectDuplicateRemoteURLs {
 				return fmt.Errorf("remote URL %s is already used by server %s", remote.URL, conflictingServer.Name)
 			}
+			validators.Warn(ctx, apiv0.Warning{
+				Code:    apiv0.WarningDuplicateRemoteURL,
+				Path:    fmt.Sprintf("remotes[%d].url", i),
+				Message: fmt.Sprintf("remote URL %s is already used by server %s; check that it points at this server", remote.URL, conflictingServer.Name),
+			})
+			break
 		}
 	}
 
 	return nil
 }
 
-// getCurrentLatestVersion finds the current latest version from existing server versions
-func (s *registryServiceImpl) getCurrentLatestVersion(existingServerVersions []*apiv0.ServerJSON) *apiv0.ServerJSON {
-	for _, server := range existingServerVersions {
-		if server.Meta != nil && server.Meta.Official != nil &&
-			server.Meta.Official.IsLatest {
-			return server
+// isSharedRemoteURL reports whether remoteURL is, or is below, one of the configured shared gateways
+func (s *registryServiceImpl) isSharedRemoteURL(remoteURL string) bool {
+	canonical := database.CanonicalRemoteURL(remoteURL)
+	for _, shared := range s.cfg.SharedRemoteURLs {
+		gateway := database.CanonicalRemoteURL(shared)
+		if canonical == gateway || strings.HasPrefix(canonical, gateway+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRepositoryID looks up the repository ID when registry validation is enabled
+func (s *registryServiceImpl) resolveRepositoryID(ctx context.Context, serverJSON *apiv0.ServerJSON) error {
+	if !s.cfg.EnableRegistryValidation || serverJSON.Status == model.StatusDeleted {
+		return nil
+	}
+	return validators.ResolveRepositoryID(ctx, &serverJSON.Repository)
+}
+
+// sanitizeMarkdown strips raw HTML and unsafe links from the inline README and release notes,
+// dropping either if nothing is left
+func sanitizeMarkdown(serverJSON *apiv0.ServerJSON) {
+	for _, text := range []*string{&serverJSON.Readme, &serverJSON.ReleaseNotes} {
+		if *text == "" {
+			continue
+		}
+		*text = markdown.Sanitize(*text)
+		if strings.TrimSpace(*text) == "" {
+			*text = ""
 		}
 	}
-	return nil
 }
 
 // EditServer updates an existing server with new details (admin operation)
-func (s *registryServiceImpl) EditServer(id string, req apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+func (s *registryServiceImpl) EditServer(ctx context.Context, id string, req apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	ctx = database.WithPrimaryReads(ctx)
+
+	// Reject invalid UTF-8 and store text in NFC
+	if err := validators.NormalizeServerJSON(&req); err != nil {
+		return nil, err
+	}
+
+	current, err := s.db.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the editable fields are taken from the request; the rest must match the stored version
+	serverJSON, err := validators.MergeEdit(current, &req)
+	if err != nil {
+		return nil, err
+	}
 
 	// Validate the request
-	if err := validators.ValidatePublishRequest(req, s.cfg); err != nil {
+	if err := validators.ValidatePublishRequest(ctx, serverJSON, s.cfg); err != nil {
 		return nil, err
 	}
 
-	serverJSON := req
+	sanitizeMarkdown(&serverJSON)
+
+	// Fill in or verify the repository ID against the hosting provider
+	if err := s.resolveRepositoryID(ctx, &serverJSON); err != nil {
+		return nil, err
+	}
 
 	// Check for duplicate remote URLs
 	if err := s.validateNoDuplicateRemoteURLs(ctx, serverJSON); err != nil {
 		return nil, err
 	}
 
-	// Update server in database
-	serverRecord, err := s.db.UpdateServer(ctx, id, &serverJSON)
+	// A changed fork reference is checked as on publish; an unchanged one may have been left
+	// dangling by the deletion of its origin, which does not make the version invalid
+	if serverJSON.ForkOf != current.ForkOf {
+		if err := s.validateForkLineage(ctx, serverJSON); err != nil {
+			return nil, err
+		}
+	}
+	// Clients cannot send registry metadata, so carry it over from the stored version
+	if current.Meta != nil && current.Meta.Official != nil {
+		official := *current.Meta.Official
+		official.UpdatedAt = time.Now()
+		official.HasReadme = serverJSON.Readme != ""
+		var meta apiv0.ServerMeta
+		if serverJSON.Meta != nil {
+			meta = *serverJSON.Meta
+		}
+		meta.Official = &official
+		serverJSON.Meta = &meta
+	}
+
+	if err := s.invalidateLatest(ctx, serverJSON.Name); err != nil {
+		return nil, err
+	}
+	defer s.invalidateLatestAfterWrite(ctx, serverJSON.Name)
+
+	// Update server in database, queueing notifications with the change
+	var serverRecord *apiv0.ServerJSON
+	err = s.db.InTransaction(ctx, func(ctx context.Context, tx database.Database) error {
+		updated, err := tx.UpdateServer(ctx, id, &serverJSON)
+		if err != nil {
+			return err
+		}
+		serverRecord = updated
+		return s.queuePublishNotification(ctx, tx, updated)
+	})
 	if err != nil {
 		return nil, err
 	}
+	s.generation.Add(1)
+	s.wakeNotifications()
+	s.recordChange(editChange(current.Status, serverRecord.Status), serverRecord)
+	s.recordWarnings(ctx, "edit")
 
 	// Return the server record directly
 	return serverRecord, nil
 }
+
+// Generation returns a counter that is incremented after every successful publish or edit, on
+// this replica or, through the invalidation channel, on another. While the channel is down each
+// call returns a new generation, so nothing cached against one is ever reused.
+func (s *registryServiceImpl) Generation() uint64 {
+	if s.invalidator != nil && !s.invalidationsLive.Load() {
+		return s.generation.Add(1)
+	}
+	return s.generation.Load()
+}
@@ -7,9 +7,11 @@ import (
 
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/validators"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidateNoDuplicateRemoteURLs(t *testing.T) {
@@ -35,10 +37,10 @@ func TestValidateNoDuplicateRemoteURLs(t *testing.T) {
 	}
 
 	memDB := database.NewMemoryDB()
-	service := NewRegistryService(memDB, &config.Config{EnableRegistryValidation: false})
+	service := NewRegistryService(memDB, &config.Config{EnableRegistryValidation: false, RejectDuplicateRemoteURLs: true})
 
 	for _, server := range existingServers {
-		_, err := service.Publish(*server)
+		_, err := service.Publish(context.Background(), *server)
 		if err != nil {
 			t.Fatalf("failed to publish server: %v", err)
 		}
@@ -86,6 +88,19 @@ func TestValidateNoDuplicateRemoteURLs(t *testing.T) {
 			expectError: true,
 			errorMsg:    "remote URL https://api.example.com/mcp is already used by server com.example/existing-server",
 		},
+		{
+			name: "duplicate remote URL in another form - should fail",
+			serverDetail: apiv0.ServerJSON{
+				Name:        "com.example/new-server",
+				Description: "A new server with duplicate URL",
+				Version: "1.0.0",
+				Remotes: []model.Transport{
+					{Type: "streamable-http", URL: "https://API.example.com:443/mcp/"}, // Same URL, differently written
+				},
+			},
+			expectError: true,
+			errorMsg:    "is already used by server com.example/existing-server",
+		},
 		{
 			name: "updating same server with same URLs - should pass",
 			serverDetail: apiv0.ServerJSON{
@@ -116,3 +131,356 @@ func TestValidateNoDuplicateRemoteURLs(t *testing.T) {
 		})
 	}
 }
+
+func TestDuplicateRemoteURLWarnings(t *testing.T) {
+	existing := apiv0.ServerJSON{
+		Name:        "com.example/existing-server",
+		Description: "An existing server",
+		Version:     "1.0.0",
+		Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://gateway.example.com/tools/existing"}},
+	}
+	newServer := func(url string) apiv0.ServerJSON {
+		return apiv0.ServerJSON{
+			Name:        "com.example/new-server",
+			Description: "A new server",
+			Version:     "1.0.0",
+			Remotes:     []model.Transport{{Type: "streamable-http", URL: url}},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		cfg              config.Config
+		url              string
+		expectedWarnings []string
+	}{
+		{
+			name:             "duplicate is published with a warning",
+			url:              "https://gateway.example.com/tools/existing/",
+			expectedWarnings: []string{"remote URL https://gateway.example.com/tools/existing/ is already used by server com.example/existing-server; check that it points at this server"},
+		},
+		{
+			name: "unique URL has no warning",
+			url:  "https://gateway.example.com/tools/new",
+		},
+		{
+			name: "shared gateway is exempt",
+			cfg:  config.Config{SharedRemoteURLs: []string{"https://gateway.example.com/tools"}},
+			url:  "https://gateway.example.com/tools/existing",
+		},
+		{
+			name:             "allowlist entry covers only URLs below it",
+			cfg:              config.Config{SharedRemoteURLs: []string{"https://gateway.example.com/to"}},
+			url:              "https://gateway.example.com/tools/existing",
+			expectedWarnings: []string{"remote URL https://gateway.example.com/tools/existing is already used by server com.example/existing-server; check that it points at this server"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRegistryService(database.NewMemoryDB(), &tt.cfg)
+			_, err := service.Publish(context.Background(), existing)
+			require.NoError(t, err)
+
+			ctx := validators.WithWarnings(context.Background())
+			_, err = service.Publish(ctx, newServer(tt.url))
+			require.NoError(t, err)
+
+			var messages []string
+			for _, warning := range validators.WarningsFrom(ctx) {
+				assert.Equal(t, apiv0.WarningDuplicateRemoteURL, warning.Code)
+				assert.Equal(t, "remotes[0].url", warning.Path)
+				messages = append(messages, warning.Message)
+			}
+			assert.Equal(t, tt.expectedWarnings, messages)
+		})
+	}
+
+	t.Run("shared gateway is exempt when rejecting", func(t *testing.T) {
+		service := NewRegistryService(database.NewMemoryDB(), &config.Config{
+			RejectDuplicateRemoteURLs: true,
+			SharedRemoteURLs:          []string{"https://gateway.example.com"},
+		})
+		_, err := service.Publish(context.Background(), existing)
+		require.NoError(t, err)
+
+		_, err = service.Publish(context.Background(), newServer(existing.Remotes[0].URL))
+		assert.NoError(t, err)
+	})
+
+	t.Run("deleted servers don't conflict", func(t *testing.T) {
+		service := NewRegistryService(database.NewMemoryDB(), &config.Config{RejectDuplicateRemoteURLs: true})
+		deleted := existing
+		deleted.Status = model.StatusDeleted
+		_, err := service.Publish(context.Background(), deleted)
+		require.NoError(t, err)
+
+		_, err = service.Publish(context.Background(), newServer(existing.Remotes[0].URL))
+		assert.NoError(t, err)
+	})
+}
+
+// cancellingDB cancels the request context as soon as the new version is
+// written, simulating a client that disconnects mid-publish, and records the
+// context error seen by the following database call
+type cancellingDB struct {
+	database.Database
+	cancel   context.CancelFunc
+	observed *error
+}
+
+func (db *cancellingDB) InTransaction(ctx context.Context, fn func(ctx context.Context, tx database.Database) error) error {
+	return db.Database.InTransaction(ctx, func(ctx context.Context, tx database.Database) error {
+		return fn(ctx, &cancellingDB{Database: tx, cancel: db.cancel, observed: db.observed})
+	})
+}
+
+func (db *cancellingDB) CreateServer(ctx context.Context, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	created, err := db.Database.CreateServer(ctx, server)
+	db.cancel()
+	return created, err
+}
+
+func (db *cancellingDB) UpdateServer(ctx context.Context, id string, server *apiv0.ServerJSON) (*apiv0.ServerJSON, error) {
+	*db.observed = ctx.Err()
+	return db.Database.UpdateServer(ctx, id, server)
+}
+
+// countingDB counts lookups by ID, one per database query
+type countingDB struct {
+	database.Database
+	lookups int
+}
+
+func (db *countingDB) GetByID(ctx context.Context, id string) (*apiv0.ServerJSON, error) {
+	db.lookups++
+	return db.Database.GetByID(ctx, id)
+}
+
+func (db *countingDB) GetByIDs(ctx context.Context, ids []string) (map[string]*apiv0.ServerJSON, []string, error) {
+	db.lookups++
+	return db.Database.GetByIDs(ctx, ids)
+}
+
+func TestGetByIDs(t *testing.T) {
+	ctx := context.Background()
+	db := &countingDB{Database: database.NewMemoryDB()}
+	service := NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	var ids []string
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		published, err := service.Publish(ctx, apiv0.ServerJSON{
+			Name:        "com.example/batched",
+			Description: "A test server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+		ids = append(ids, published.Meta.Official.ID)
+	}
+	unknown := "00000000-0000-0000-0000-000000000000"
+
+	db.lookups = 0
+	found, missing, err := service.GetByIDs(ctx, []string{ids[2], unknown, ids[0], ids[1], "not-a-uuid", ids[0]})
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.lookups, "all servers are fetched with a single query")
+
+	require.Len(t, found, 3)
+	for i, id := range ids {
+		assert.Equal(t, []string{"1.0.0", "1.1.0", "1.2.0"}[i], found[id].Version)
+	}
+	assert.Equal(t, []string{unknown, "not-a-uuid"}, missing)
+
+	found, missing, err = service.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	assert.Empty(t, missing)
+}
+
+func TestPublish_CancelledMidPublishLeavesNoPartialWrite(t *testing.T) {
+	memDB := database.NewMemoryDB()
+	cfg := &config.Config{EnableRegistryValidation: false}
+	name := "com.example/cancelled"
+
+	first, err := NewRegistryService(memDB, cfg).Publish(context.Background(), apiv0.ServerJSON{
+		Name:        name,
+		Description: "A server",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var observed error
+	db := &cancellingDB{Database: memDB, cancel: cancel, observed: &observed}
+
+	_, err = NewRegistryService(db, cfg).Publish(ctx, apiv0.ServerJSON{
+		Name:        name,
+		Description: "A server",
+		Version:     "2.0.0",
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, observed, context.Canceled, "demoting the previous latest should see the cancellation")
+
+	// Neither the new version nor the demotion of the old latest was persisted
+	versions, _, err := memDB.List(context.Background(), &database.ServerFilter{Name: &name}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, first.Meta.Official.ID, versions[0].Meta.Official.ID)
+	assert.True(t, versions[0].Meta.Official.IsLatest)
+}
+
+func TestPublish_ReadsFromPrimary(t *testing.T) {
+	ctx := context.Background()
+	primary := database.NewMemoryDB()
+	// The replica never catches up, so only reads from the primary see what was published
+	db := database.NewReadReplicaDatabase(primary, database.NewMemoryDB())
+	service := NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+	server := apiv0.ServerJSON{Name: "com.example/replicated", Description: "A server", Version: "1.0.0"}
+
+	first, err := service.Publish(ctx, server)
+	require.NoError(t, err)
+	assert.True(t, first.Meta.Official.IsLatest)
+
+	// The duplicate is found on the primary
+	_, err = service.Publish(ctx, server)
+	assert.Error(t, err)
+
+	// The new version replaces the latest version on the primary
+	server.Version = "1.1.0"
+	second, err := service.Publish(ctx, server)
+	require.NoError(t, err)
+	assert.True(t, second.Meta.Official.IsLatest)
+	previous, err := primary.GetByID(ctx, first.Meta.Official.ID)
+	require.NoError(t, err)
+	assert.False(t, previous.Meta.Official.IsLatest)
+
+	// Plain reads go to the replica
+	_, err = service.GetByID(ctx, second.Meta.Official.ID)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestEditServer_KeepsRegistryMetadata(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+
+	published, err := service.Publish(ctx, apiv0.ServerJSON{
+		Name:        "com.example/server",
+		Description: "A test server",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+	id := published.Meta.Official.ID
+	_, err = service.SetPinned(ctx, id, true)
+	require.NoError(t, err)
+
+	edited, err := service.EditServer(ctx, id, apiv0.ServerJSON{
+		Name:        "com.example/server",
+		Description: "An edited server",
+		Version:     "1.0.0",
+		Status:      model.StatusDeprecated,
+		Meta: &apiv0.ServerMeta{
+			PublisherProvided: map[string]interface{}{"replaced_by": "com.example/other"},
+		},
+	})
+	require.NoError(t, err)
+
+	stored, err := service.GetByID(ctx, id)
+	require.NoError(t, err)
+	for _, server := range []*apiv0.ServerJSON{edited, stored} {
+		assert.Equal(t, "An edited server", server.Description)
+		require.NotNil(t, server.Meta.Official)
+		assert.Equal(t, id, server.Meta.Official.ID)
+		assert.True(t, server.Meta.Official.IsLatest)
+		assert.True(t, server.Meta.Official.Pinned)
+		assert.True(t, server.Meta.Official.UpdatedAt.After(published.Meta.Official.UpdatedAt))
+		assert.Equal(t, "com.example/other", server.Meta.PublisherProvided["replaced_by"])
+	}
+
+	_, err = service.EditServer(ctx, "missing", apiv0.ServerJSON{
+		Name:        "com.example/server",
+		Description: "An edited server",
+		Version:     "1.0.0",
+	})
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestPublish_SanitizesReadme(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+
+	published, err := service.Publish(ctx, apiv0.ServerJSON{
+		Name:        "com.example/readme",
+		Description: "A test server",
+		Version:     "1.0.0",
+		Readme:      "# Usage\r\n\r\n<img src=x onerror=alert(1)>See [docs](javascript:alert(1)).",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "# Usage\n\nSee docs.", published.Readme)
+	assert.True(t, published.Meta.Official.HasReadme)
+
+	// A README that is nothing but markup is dropped
+	edited, err := service.EditServer(ctx, published.Meta.Official.ID, apiv0.ServerJSON{
+		Name:        "com.example/readme",
+		Description: "A test server",
+		Version:     "1.0.0",
+		Readme:      "<script>alert(1)</script>\n",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, edited.Readme)
+	assert.False(t, edited.Meta.Official.HasReadme)
+}
+
+func TestPublish_SanitizesReleaseNotes(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemoryDB(), &config.Config{EnableRegistryValidation: false})
+
+	published, err := service.Publish(ctx, apiv0.ServerJSON{
+		Name:         "com.example/release-notes",
+		Description:  "A test server",
+		Version:      "1.1.0",
+		ReleaseNotes: "## Fixes\n\n- Retry <b>timeouts</b> ([#12](javascript:alert(1)))",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "## Fixes\n\n- Retry timeouts (#12)", published.ReleaseNotes)
+
+	// Notes that are nothing but markup are dropped
+	edited, err := service.EditServer(ctx, published.Meta.Official.ID, apiv0.ServerJSON{
+		Name:         "com.example/release-notes",
+		Description:  "A test server",
+		Version:      "1.1.0",
+		ReleaseNotes: "<style>body{}</style>",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, edited.ReleaseNotes)
+}
+
+func TestPublish_NormalizesVersion(t *testing.T) {
+	server := func(version string) apiv0.ServerJSON {
+		return apiv0.ServerJSON{Name: "com.example/versioned", Description: "A versioned server", Version: version}
+	}
+
+	t.Run("leading v is stripped with a warning", func(t *testing.T) {
+		service := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+		ctx := validators.WithWarnings(context.Background())
+		published, err := service.Publish(ctx, server(" v1.2.3"))
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3", published.Version)
+		require.Len(t, validators.WarningsFrom(ctx), 1)
+		assert.Equal(t, apiv0.WarningVersionPrefix, validators.WarningsFrom(ctx)[0].Code)
+
+		_, err = service.Publish(context.Background(), server("1.2.3"))
+		assert.Error(t, err, "the stripped version is taken")
+	})
+
+	t.Run("strict prefix rejects it", func(t *testing.T) {
+		service := NewRegistryService(database.NewMemoryDB(), &config.Config{StrictVersionPrefix: true})
+		_, err := service.Publish(context.Background(), server("v1.2.3"))
+		assert.ErrorIs(t, err, validators.ErrVersionPrefix)
+	})
+
+	t.Run("invalid versions are rejected", func(t *testing.T) {
+		service := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+		_, err := service.Publish(context.Background(), server("1.2.3 beta"))
+		assert.ErrorIs(t, err, validators.ErrInvalidServerVersion)
+	})
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// remoteHealthPageSize is the page size used when scanning latest versions for remote health checks
+const remoteHealthPageSize = 100
+
+// SetRemoteHealth records the result of probing a server version's remote endpoints.
+// Only the registry metadata changes; the publisher-declared status is left alone.
+func (s *registryServiceImpl) SetRemoteHealth(ctx context.Context, id string, health *apiv0.RemoteHealth) (*apiv0.ServerJSON, error) {
+	ctx = database.WithPrimaryReads(ctx)
+	server, err := s.db.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if server.Meta == nil || server.Meta.Official == nil {
+		return nil, fmt.Errorf("%w: server %s has no registry metadata", database.ErrInvalidInput, id)
+	}
+
+	official := *server.Meta.Official
+	official.RemoteHealth = health
+	meta := *server.Meta
+	meta.Official = &official
+	updated := *server
+	updated.Meta = &meta
+
+	if err := s.invalidateLatest(ctx, server.Name); err != nil {
+		return nil, err
+	}
+	defer s.invalidateLatestAfterWrite(ctx, server.Name)
+
+	serverRecord, err := s.db.UpdateServer(ctx, id, &updated)
+	if err != nil {
+		return nil, err
+	}
+	s.generation.Add(1)
+	return serverRecord, nil
+}
+
+// RemoteProber checks that remote endpoints respond, spacing requests to the same host
+type RemoteProber struct {
+	client  *http.Client
+	timeout time.Duration
+	hosts   *hostLimiter
+}
+
+// NewRemoteProber creates a prober that gives each request timeout to respond and
+// starts requests to the same host at least hostInterval apart. Remote URLs come from
+// publishers, so the prober only connects to public addresses and does not follow redirects.
+func NewRemoteProber(timeout, hostInterval time.Duration) *RemoteProber {
+	return &RemoteProber{
+		client:  newPublicClient(0),
+		timeout: timeout,
+		hosts:   newHostLimiter(hostInterval),
+	}
+}
+
+// Probe sends a HEAD request to remoteURL. Any response below 500 counts as alive, since
+// MCP endpoints commonly answer HEAD with 401 or 405; errors, timeouts and 5xx do not.
+func (p *RemoteProber) Probe(ctx context.Context, remoteURL string) error {
+	parsed, err := url.Parse(remoteURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid remote URL %s", remoteURL)
+	}
+
+	if err := p.hosts.wait(ctx, parsed.Host); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, remoteURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "MCP-Registry-Remote-Health/1.0")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("remote %s returned status %d", remoteURL, resp.StatusCode)
+	}
+	return nil
+}
+
+// hostLimiter spaces requests to the same host, so background jobs never hammer one origin
+type hostLimiter struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	nextSlot map[string]time.Time // earliest time the next request to each host may start
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{interval: interval, nextSlot: make(map[string]time.Time)}
+}
+
+// wait reserves the next request slot for host and sleeps until it starts
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.nextSlot[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.nextSlot[host] = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RemoteHealthJob periodically probes the remote endpoints of each server's latest version
+type RemoteHealthJob struct {
+	registry         RegistryService
+	prober           *RemoteProber
+	failureThreshold int
+	interval         time.Duration
+}
+
+// NewRemoteHealthJob creates a job that probes remotes every interval, marking a server
+// degraded or unreachable once failureThreshold checks in a row have failed
+func NewRemoteHealthJob(registry RegistryService, prober *RemoteProber, failureThreshold int, interval time.Duration) *RemoteHealthJob {
+	return &RemoteHealthJob{
+		registry:         registry,
+		prober:           prober,
+		failureThreshold: failureThreshold,
+		interval:         interval,
+	}
+}
+
+// NewRemoteHealthJobFromConfig creates the remote health job configured for this registry
+func NewRemoteHealthJobFromConfig(registry RegistryService, cfg *config.Config) *RemoteHealthJob {
+	prober := NewRemoteProber(cfg.RemoteHealthTimeout, cfg.RemoteHealthHostInterval)
+	return NewRemoteHealthJob(registry, prober, cfg.RemoteHealthFailureThreshold, cfg.RemoteHealthInterval)
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *RemoteHealthJob) Start(ctx context.Context) {
+	go j.Run(ctx)
+}
+
+// Run runs the job until ctx is cancelled, returning once a run in progress has stopped
+func (j *RemoteHealthJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.runOnce(ctx)
+		}
+	}
+}
+
+func (j *RemoteHealthJob) runOnce(ctx context.Context) {
+	checked, err := j.CheckAll(ctx)
+	if err != nil {
+		log.Printf("Remote health job failed after checking %d servers: %v", checked, err)
+		return
+	}
+	log.Printf("Remote health job checked %d servers", checked)
+}
+
+// CheckAll probes every latest, non-deleted server version that has remotes and records
+// the result, returning how many servers were checked
+func (j *RemoteHealthJob) CheckAll(ctx context.Context) (int, error) {
+	isLatest := true
+	filter := &database.ServerFilter{IsLatest: &isLatest}
+
+	checked := 0
+	cursor := ""
+	for {
+		servers, nextCursor, err := j.registry.List(ctx, filter, cursor, remoteHealthPageSize)
+		if err != nil {
+			return checked, err
+		}
+
+		for i := range servers {
+			server := &servers[i]
+			if server.Status == model.StatusDeleted || server.Meta == nil || server.Meta.Official == nil {
+				continue
+			}
+			urls := probeableRemoteURLs(server.Remotes)
+			if len(urls) == 0 {
+				continue
+			}
+
+			if err := j.check(ctx, server, urls); err != nil {
+				return checked, err
+			}
+			checked++
+		}
+
+		if nextCursor == "" {
+			return checked, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// check probes one server's remotes and records its new health
+func (j *RemoteHealthJob) check(ctx context.Context, server *apiv0.ServerJSON, urls []string) error {
+	failed := 0
+	for _, remoteURL := range urls {
+		if err := j.prober.Probe(ctx, remoteURL); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed++
+		}
+	}
+
+	official := server.Meta.Official
+	health := nextRemoteHealth(official.RemoteHealth, failed, len(urls), j.failureThreshold, time.Now())
+	if _, err := j.registry.SetRemoteHealth(ctx, official.ID, health); err != nil {
+		return fmt.Errorf("failed to record remote health of %s: %w", server.Name, err)
+	}
+	return nil
+}
+
+// nextRemoteHealth works out a server's health from its previous health and the latest check.
+// Failures below the threshold keep the previous status, so a single blip doesn't flag a server.
+func nextRemoteHealth(previous *apiv0.RemoteHealth, failed, total, threshold int, now time.Time) *apiv0.RemoteHealth {
+	health := &apiv0.RemoteHealth{
+		Status:        apiv0.RemoteHealthHealthy,
+		LastCheckedAt: now,
+	}
+	if failed == 0 {
+		return health
+	}
+
+	if previous != nil {
+		health.Status = previous.Status
+		health.ConsecutiveFailures = previous.ConsecutiveFailures
+	}
+	health.ConsecutiveFailures++
+
+	if health.ConsecutiveFailures >= threshold {
+		if failed == total {
+			health.Status = apiv0.RemoteHealthUnreachable
+		} else {
+			health.Status = apiv0.RemoteHealthDegraded
+		}
+	}
+	return health
+}
+
+// probeableRemoteURLs returns the remote URLs that can be requested as-is, skipping
+// templated URLs whose variables are filled in by the client
+func probeableRemoteURLs(remotes []model.Transport) []string {
+	var urls []string
+	for _, remote := range remotes {
+		if remote.URL == "" || strings.Contains(remote.URL, "{") {
+			continue
+		}
+		urls = append(urls, remote.URL)
+	}
+	return urls
+}
@@ -0,0 +1,201 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedRemoteServer(t *testing.T, db database.Database, name string, status model.Status, remoteURLs ...string) string {
+	t.Helper()
+
+	id := seedVersion(t, db, name, "1.0.0", time.Now(), true, status)
+	server, err := db.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	for _, remoteURL := range remoteURLs {
+		server.Remotes = append(server.Remotes, model.Transport{Type: "streamable-http", URL: remoteURL})
+	}
+	_, err = db.UpdateServer(context.Background(), id, server)
+	require.NoError(t, err)
+	return id
+}
+
+func TestRemoteHealthJob(t *testing.T) {
+	ctx := context.Background()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		w.WriteHeader(http.StatusMethodNotAllowed) // still alive
+	}))
+	defer healthy.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer hanging.Close()
+	defer close(release)
+
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{})
+
+	healthyID := seedRemoteServer(t, db, "com.example/healthy", model.StatusActive, healthy.URL+"/mcp")
+	failingID := seedRemoteServer(t, db, "com.example/failing", model.StatusActive, failing.URL+"/mcp")
+	hangingID := seedRemoteServer(t, db, "com.example/hanging", model.StatusActive, hanging.URL+"/mcp")
+	mixedID := seedRemoteServer(t, db, "com.example/mixed", model.StatusDeprecated, healthy.URL+"/sse", failing.URL+"/mcp")
+	deletedID := seedRemoteServer(t, db, "com.example/deleted", model.StatusDeleted, failing.URL+"/mcp")
+	templatedID := seedRemoteServer(t, db, "com.example/templated", model.StatusActive, "https://{tenant}.example.com/mcp")
+	seedVersion(t, db, "com.example/package-only", "1.0.0", time.Now(), true, model.StatusActive)
+
+	prober := NewRemoteProber(200*time.Millisecond, 0)
+	allowLoopback(prober.client)
+	job := NewRemoteHealthJob(svc, prober, 2, time.Hour)
+
+	health := func(id string) *apiv0.RemoteHealth {
+		t.Helper()
+		server, err := db.GetByID(ctx, id)
+		require.NoError(t, err)
+		return server.Meta.Official.RemoteHealth
+	}
+
+	// First run: failures are counted but below the threshold nothing is flagged
+	checked, err := job.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, checked)
+
+	assert.Equal(t, apiv0.RemoteHealthHealthy, health(healthyID).Status)
+	assert.Equal(t, 0, health(healthyID).ConsecutiveFailures)
+	for _, id := range []string{failingID, hangingID, mixedID} {
+		assert.Equal(t, apiv0.RemoteHealthHealthy, health(id).Status, id)
+		assert.Equal(t, 1, health(id).ConsecutiveFailures, id)
+	}
+	assert.Nil(t, health(deletedID), "deleted servers are not probed")
+	assert.Nil(t, health(templatedID), "templated remote URLs are not probed")
+
+	// Second run reaches the threshold
+	_, err = job.CheckAll(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, apiv0.RemoteHealthHealthy, health(healthyID).Status)
+	assert.Equal(t, apiv0.RemoteHealthUnreachable, health(failingID).Status)
+	assert.Equal(t, apiv0.RemoteHealthUnreachable, health(hangingID).Status, "timeouts count as failures")
+	assert.Equal(t, apiv0.RemoteHealthDegraded, health(mixedID).Status, "one of two remotes failing is degraded")
+	assert.WithinDuration(t, time.Now(), health(failingID).LastCheckedAt, time.Minute)
+
+	// Publisher-declared status is never changed
+	for id, want := range map[string]model.Status{failingID: model.StatusActive, mixedID: model.StatusDeprecated} {
+		server, err := db.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, want, server.Status)
+	}
+}
+
+func TestNextRemoteHealth(t *testing.T) {
+	now := time.Now()
+	unreachable := &apiv0.RemoteHealth{Status: apiv0.RemoteHealthUnreachable, ConsecutiveFailures: 5}
+
+	recovered := nextRemoteHealth(unreachable, 0, 1, 3, now)
+	assert.Equal(t, apiv0.RemoteHealthHealthy, recovered.Status)
+	assert.Equal(t, 0, recovered.ConsecutiveFailures)
+	assert.Equal(t, now, recovered.LastCheckedAt)
+
+	stillDown := nextRemoteHealth(unreachable, 1, 1, 3, now)
+	assert.Equal(t, apiv0.RemoteHealthUnreachable, stillDown.Status)
+	assert.Equal(t, 6, stillDown.ConsecutiveFailures)
+
+	firstFailure := nextRemoteHealth(nil, 1, 1, 3, now)
+	assert.Equal(t, apiv0.RemoteHealthHealthy, firstFailure.Status)
+	assert.Equal(t, 1, firstFailure.ConsecutiveFailures)
+}
+
+func TestRemoteProber_RateLimitsPerHost(t *testing.T) {
+	var mu sync.Mutex
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, time.Now())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	const interval = 100 * time.Millisecond
+	prober := NewRemoteProber(time.Second, interval)
+	allowLoopback(prober.client)
+	for range 3 {
+		require.NoError(t, prober.Probe(context.Background(), server.URL))
+	}
+
+	// Cancelling while waiting for a slot gives up without sending the request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, prober.Probe(ctx, server.URL), context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requests, 3)
+	for i := 1; i < len(requests); i++ {
+		assert.GreaterOrEqual(t, requests[i].Sub(requests[i-1]), interval-10*time.Millisecond)
+	}
+}
+
+func TestRemoteProber_RefusesPrivateAddresses(t *testing.T) {
+	probed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		probed = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	prober := NewRemoteProber(time.Second, 0)
+	for _, remoteURL := range []string{
+		server.URL + "/mcp",
+		"http://localhost:" + port + "/mcp", // checked after the name resolves
+	} {
+		err := prober.Probe(context.Background(), remoteURL)
+		require.ErrorIs(t, err, errNonPublicAddress, remoteURL)
+	}
+	assert.False(t, probed, "private addresses are never probed")
+}
+
+func TestRemoteProber_DoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		followed = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	redirecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/internal", http.StatusFound)
+	}))
+	defer redirecting.Close()
+
+	prober := NewRemoteProber(time.Second, 0)
+	allowLoopback(prober.client)
+	require.NoError(t, prober.Probe(context.Background(), redirecting.URL+"/mcp"), "a redirect still shows the remote is alive")
+	assert.False(t, followed)
+}
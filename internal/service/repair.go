@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// TextRepair is a stored server version whose text RepairText rewrote, or would rewrite
+type TextRepair struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Fields  []string `json:"fields" doc:"Paths of the changed fields, such as packages[0].description"`
+}
+
+// RepairText normalizes the text and version of every stored server version the way
+// publishing does, dropping invalid UTF-8 that publishing would reject. Versions stored before publishing
+// normalized text, or imported by other means, are rewritten with a new updated_at, so
+// mirrors pick them up. With dryRun set, it only reports what would change.
+func (s *registryServiceImpl) RepairText(ctx context.Context, dryRun bool) ([]TextRepair, error) {
+	ctx = database.WithPrimaryReads(ctx)
+	repairs := []TextRepair{}
+	cursor := ""
+	for {
+		page, nextCursor, err := s.db.List(ctx, nil, cursor, retentionPageSize)
+		if err != nil {
+			return repairs, fmt.Errorf("failed to list servers: %w", err)
+		}
+		for _, server := range page {
+			if server.Meta == nil || server.Meta.Official == nil {
+				continue
+			}
+			repaired, fields, err := repairedCopy(server)
+			if err != nil {
+				return repairs, err
+			}
+			repairedVersion, err := s.repairVersion(ctx, repaired)
+			if err != nil {
+				return repairs, err
+			}
+			if repairedVersion {
+				fields = append(fields, "version")
+			}
+			if len(fields) == 0 {
+				continue
+			}
+
+			repair := TextRepair{ID: server.Meta.Official.ID, Name: server.Name, Version: server.Version, Fields: fields}
+			if !dryRun {
+				if err := s.storeRepaired(ctx, server.Name, repaired); err != nil {
+					return repairs, fmt.Errorf("failed to repair %s version %s: %w", server.Name, server.Version, err)
+				}
+				log.Printf("audit: repaired text of server %s version %s (id=%s, fields=%s)",
+					repair.Name, repair.Version, repair.ID, strings.Join(fields, ","))
+			}
+			repairs = append(repairs, repair)
+		}
+		if nextCursor == "" {
+			return repairs, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// repairedCopy repairs a deep copy of server, leaving the original untouched
+func repairedCopy(server *apiv0.ServerJSON) (*apiv0.ServerJSON, []string, error) {
+	// The copy goes through JSON, which turns invalid UTF-8 into U+FFFD for the repair to drop
+	data, err := json.Marshal(server)
+	if err != nil {
+		return nil, nil, err
+	}
+	var repaired apiv0.ServerJSON
+	if err := json.Unmarshal(data, &repaired); err != nil {
+		return nil, nil, err
+	}
+
+	return &repaired, validators.RepairServerJSON(&repaired), nil
+}
+
+// repairVersion normalizes the version of server the way publishing does, reporting whether it
+// changed. Versions still invalid once normalized, or whose normalized version the server
+// already has, such as v1.0.0 next to 1.0.0, are left as they are.
+func (s *registryServiceImpl) repairVersion(ctx context.Context, server *apiv0.ServerJSON) (bool, error) {
+	version, _ := validators.NormalizeVersion(server.Version)
+	if version == server.Version || validators.ValidateVersion(version) != nil {
+		return false, nil
+	}
+	_, err := s.db.FindHead(ctx, server.Name, version)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, database.ErrNotFound) {
+		return false, fmt.Errorf("failed to check %s version %s: %w", server.Name, version, err)
+	}
+	server.Version = version
+	return true, nil
+}
+
+// storeRepaired writes a repaired server version, bumping its updated_at
+func (s *registryServiceImpl) storeRepaired(ctx context.Context, name string, repaired *apiv0.ServerJSON) error {
+	official := *repaired.Meta.Official
+	official.UpdatedAt = time.Now()
+	repaired.Meta.Official = &official
+
+	if err := s.invalidateLatest(ctx, name); err != nil {
+		return err
+	}
+	defer s.invalidateLatestAfterWrite(ctx, name)
+
+	if _, err := s.db.UpdateServer(ctx, official.ID, repaired); err != nil {
+		return err
+	}
+	s.generation.Add(1)
+	s.recordChange(ChangeUpdated, repaired)
+	return nil
+}
+
+// BackfillSearchKeywords stores the search keywords of every server version written before
+// publishing derived them, in batches. Until then those versions are only found by name.
+func (s *registryServiceImpl) BackfillSearchKeywords(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		updated, err := s.db.BackfillSearchKeywords(ctx, retentionPageSize)
+		total += updated
+		if err != nil {
+			return total, fmt.Errorf("failed to backfill search keywords: %w", err)
+		}
+		if updated == 0 {
+			if total > 0 {
+				log.Printf("audit: backfilled search keywords of %d server versions", total)
+				s.generation.Add(1)
+			}
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,93 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairText(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{})
+	publishedAt := time.Now().Add(-24 * time.Hour)
+
+	// Rows stored before publishing normalized text
+	brokenID := seedVersion(t, db, "com.example/broken", "1.0.0", publishedAt, true, model.StatusActive)
+	broken, err := db.GetByID(ctx, brokenID)
+	require.NoError(t, err)
+	broken.Description = "Weather \xed\xa0\x80 lookups\u202e"
+	broken.Title = "Cafe\u0301"
+	_, err = db.UpdateServer(ctx, brokenID, broken)
+	require.NoError(t, err)
+
+	cleanID := seedVersion(t, db, "com.example/clean", "1.0.0", publishedAt, true, model.StatusActive)
+
+	// Dry run reports without changing anything
+	generation := svc.Generation()
+	preview, err := svc.RepairText(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, preview, 1)
+	assert.Equal(t, TextRepair{ID: brokenID, Name: "com.example/broken", Version: "1.0.0", Fields: []string{"description", "title"}}, preview[0])
+	assert.Equal(t, generation, svc.Generation())
+	stored, err := db.GetByID(ctx, brokenID)
+	require.NoError(t, err)
+	assert.Equal(t, broken.Description, stored.Description)
+
+	// Applying rewrites exactly the previewed versions
+	repaired, err := svc.RepairText(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, preview, repaired)
+	assert.Greater(t, svc.Generation(), generation)
+
+	stored, err = db.GetByID(ctx, brokenID)
+	require.NoError(t, err)
+	assert.Equal(t, "Weather  lookups", stored.Description)
+	assert.Equal(t, "Caf\u00e9", stored.Title)
+	assert.True(t, stored.Meta.Official.UpdatedAt.After(publishedAt))
+	assert.Equal(t, publishedAt.Unix(), stored.Meta.Official.PublishedAt.Unix())
+
+	clean, err := db.GetByID(ctx, cleanID)
+	require.NoError(t, err)
+	assert.Equal(t, publishedAt.Unix(), clean.Meta.Official.UpdatedAt.Unix(), "versions with clean text are not rewritten")
+
+	// Nothing is left to repair
+	again, err := svc.RepairText(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+}
+
+func TestRepairText_Versions(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{})
+	publishedAt := time.Now().Add(-24 * time.Hour)
+
+	// Versions stored before publishing normalized them
+	prefixedID := seedVersion(t, db, "com.example/prefixed", "v1.0.0", publishedAt, true, model.StatusActive)
+	paddedID := seedVersion(t, db, "com.example/padded", " 2.0.0 ", publishedAt, true, model.StatusActive)
+	seedVersion(t, db, "com.example/both", "1.0.0", publishedAt, false, model.StatusActive)
+	seedVersion(t, db, "com.example/both", "v1.0.0", publishedAt, true, model.StatusActive)
+	seedVersion(t, db, "com.example/unfixable", "1.0.0 beta", publishedAt, true, model.StatusActive)
+
+	repaired, err := svc.RepairText(ctx, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []TextRepair{
+		{ID: prefixedID, Name: "com.example/prefixed", Version: "v1.0.0", Fields: []string{"version"}},
+		{ID: paddedID, Name: "com.example/padded", Version: " 2.0.0 ", Fields: []string{"version"}},
+	}, repaired, "versions that would collide or stay invalid are left alone")
+
+	stored, err := db.GetByID(ctx, prefixedID)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", stored.Version)
+	found, err := db.FindHead(ctx, "com.example/padded", "2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, paddedID, found.ID)
+}
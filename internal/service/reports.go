@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// Categories of server reports
+const (
+	ReportCategoryBrokenPackage = "broken-package"
+	ReportCategoryDeadRemote    = "dead-remote"
+	ReportCategoryMalware       = "malware"
+	ReportCategorySpam          = "spam"
+	ReportCategoryOther         = "other"
+)
+
+// ReportCategories lists every category a server version can be reported under
+var ReportCategories = []string{
+	ReportCategoryBrokenPackage,
+	ReportCategoryDeadRemote,
+	ReportCategoryMalware,
+	ReportCategorySpam,
+	ReportCategoryOther,
+}
+
+// MaxReportTextLength is the most characters the text of a server report may have
+const MaxReportTextLength = 2000
+
+// ServerReports summarizes the reports about one server version, without their text
+type ServerReports struct {
+	ID             string         `json:"id" doc:"Server version ID"`
+	Name           string         `json:"name"`
+	Version        string         `json:"version"`
+	Categories     map[string]int `json:"categories" doc:"Number of reports in each category the version was reported under"`
+	Total          int            `json:"total"`
+	LastReportedAt time.Time      `json:"last_reported_at"`
+	NeedsAttention bool           `json:"needs_attention" doc:"Whether the reports in a category reached the registry's report threshold"`
+}
+
+// ReportServer records a user's report that a server version is broken or abusive. Once the
+// reports of the version in a category reach the report threshold, its namespace is notified.
+func (s *registryServiceImpl) ReportServer(ctx context.Context, id, category, text string) (*database.ServerReport, error) {
+	if !slices.Contains(ReportCategories, category) {
+		return nil, fmt.Errorf("%w: unknown report category %q, expected one of %s", database.ErrInvalidInput, category, strings.Join(ReportCategories, ", "))
+	}
+	text = validators.SanitizeText(text)
+	if utf8.RuneCountInString(text) > MaxReportTextLength {
+		return nil, fmt.Errorf("%w: report text is longer than %d characters", database.ErrInvalidInput, MaxReportTextLength)
+	}
+
+	server, err := s.db.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Versions hidden from the public API, such as those held for review, cannot be reported
+	if server.Status.Hidden() {
+		return nil, database.ErrNotFound
+	}
+
+	namespace, _, _ := strings.Cut(server.Name, "/")
+	report := &database.ServerReport{
+		ID:         uuid.New().String(),
+		ServerID:   id,
+		Namespace:  namespace,
+		ServerName: server.Name,
+		Version:    server.Version,
+		Category:   category,
+		Text:       text,
+		Tenant:     tenancy.Of(server),
+		CreatedAt:  time.Now(),
+	}
+
+	// Store the report and queue the notification for reaching the threshold together
+	err = s.db.InTransaction(ctx, func(ctx context.Context, tx database.Database) error {
+		if err := tx.CreateServerReport(ctx, report); err != nil {
+			return err
+		}
+		if s.cfg.ReportThreshold == 0 {
+			return nil
+		}
+		counts, err := tx.ListServerReportCounts(ctx, database.ServerReportFilter{ServerID: id})
+		if err != nil {
+			return err
+		}
+		for _, count := range counts {
+			// Only the report that reaches the threshold notifies, so owners hear of it once
+			if count.Category == category && count.Count == s.cfg.ReportThreshold {
+				return s.queueReportNotification(ctx, tx, server, count)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.wakeNotifications()
+	return report, nil
+}
+
+// queueReportNotification writes notifications for a server version whose reports in a category
+// reached the report threshold to the outbox in tx, if a dispatcher is configured
+func (s *registryServiceImpl) queueReportNotification(ctx context.Context, tx database.Database, server *apiv0.ServerJSON, count database.ServerReportCount) error {
+	if s.notifications == nil || server.Meta == nil || server.Meta.Official == nil {
+		return nil
+	}
+	namespace, _, _ := strings.Cut(server.Name, "/")
+	status := server.Status
+	if status == "" {
+		status = model.StatusActive // the schema default
+	}
+	ctx = tenancy.WithTenant(ctx, tenancy.Of(server))
+	return s.notifications.enqueue(ctx, tx, PublishNotification{
+		Event:     ServerReportedEvent,
+		Namespace: namespace,
+		Server: NotificationServer{
+			ID:      server.Meta.Official.ID,
+			Name:    server.Name,
+			Version: server.Version,
+			Status:  string(status),
+		},
+		PublishedAt: server.Meta.Official.PublishedAt,
+		Reports:     &ReportNotification{Category: count.Category, Count: count.Count},
+	})
+}
+
+// ServerReportSummaries counts the reports matching filter for each server version, in name order
+func (s *registryServiceImpl) ServerReportSummaries(ctx context.Context, filter database.ServerReportFilter) ([]ServerReports, error) {
+	counts, err := s.db.ListServerReportCounts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []ServerReports{}
+	for _, count := range counts {
+		// Counts arrive ordered by version, so each version's categories are adjacent
+		if len(summaries) == 0 || summaries[len(summaries)-1].ID != count.ServerID {
+			summaries = append(summaries, ServerReports{
+				ID:         count.ServerID,
+				Name:       count.ServerName,
+				Version:    count.Version,
+				Categories: make(map[string]int),
+			})
+		}
+		summary := &summaries[len(summaries)-1]
+		summary.Categories[count.Category] = count.Count
+		summary.Total += count.Count
+		if count.LastReportedAt.After(summary.LastReportedAt) {
+			summary.LastReportedAt = count.LastReportedAt
+		}
+		if s.cfg.ReportThreshold > 0 && count.Count >= s.cfg.ReportThreshold {
+			summary.NeedsAttention = true
+		}
+	}
+
+	return summaries, nil
+}
+
+// ListServerReports returns the reports about a server version, with their text, newest first
+func (s *registryServiceImpl) ListServerReports(ctx context.Context, id string) ([]*database.ServerReport, error) {
+	return s.db.ListServerReports(ctx, id)
+}
@@ -0,0 +1,144 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestReportServer_Threshold(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{ReportThreshold: 3}
+	db := database.NewMemoryDB()
+	require.NoError(t, db.CreateNamespaceNotification(ctx, &database.NamespaceNotification{
+		ID: "4e0f8a3c-8f0d-4c1b-9d55-0d4e6f6d8a11", Namespace: "com.example", Email: "owner@example.com", CreatedAt: time.Now(),
+	}))
+	s := NewRegistryService(db, cfg, WithNotifications(NewNotificationDispatcher(db, cfg)))
+
+	published, err := s.Publish(ctx, apiv0.ServerJSON{Name: "com.example/weather", Description: "Weather lookups", Version: "1.0.0"})
+	require.NoError(t, err)
+	id := published.Meta.Official.ID
+
+	summary := func() ServerReports {
+		t.Helper()
+		summaries, err := s.ServerReportSummaries(ctx, database.ServerReportFilter{Namespace: "com.example"})
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		return summaries[0]
+	}
+	outbox := func() []*database.OutboxEvent {
+		t.Helper()
+		// Claim far in the future with no lease, so each call sees every queued event
+		events, err := db.ClaimOutboxEvents(ctx, time.Now().Add(time.Hour), 0, 10)
+		require.NoError(t, err)
+		var reported []*database.OutboxEvent
+		for _, event := range events {
+			if event.Event == ServerReportedEvent {
+				reported = append(reported, event)
+			}
+		}
+		return reported
+	}
+
+	for range 2 {
+		_, err := s.ReportServer(ctx, id, ReportCategoryDeadRemote, "502 Bad Gateway")
+		require.NoError(t, err)
+	}
+	_, err = s.ReportServer(ctx, id, ReportCategorySpam, "")
+	require.NoError(t, err)
+	assert.False(t, summary().NeedsAttention, "no category has reached the threshold")
+	assert.Empty(t, outbox())
+
+	_, err = s.ReportServer(ctx, id, ReportCategoryDeadRemote, "still down")
+	require.NoError(t, err)
+	got := summary()
+	assert.True(t, got.NeedsAttention)
+	assert.Equal(t, map[string]int{ReportCategoryDeadRemote: 3, ReportCategorySpam: 1}, got.Categories)
+	assert.Equal(t, 4, got.Total)
+
+	events := outbox()
+	require.Len(t, events, 1)
+	assert.Equal(t, ServerReportedEvent, events[0].Event)
+	assert.Equal(t, "owner@example.com", events[0].Email)
+	var notification PublishNotification
+	require.NoError(t, json.Unmarshal(events[0].Payload, &notification))
+	assert.Equal(t, id, notification.Server.ID)
+	assert.Equal(t, &ReportNotification{Category: ReportCategoryDeadRemote, Count: 3}, notification.Reports)
+	assert.NotContains(t, string(events[0].Payload), "still down", "the owner is not sent the text of reports")
+
+	// Reports past the threshold do not notify again
+	_, err = s.ReportServer(ctx, id, ReportCategoryDeadRemote, "")
+	require.NoError(t, err)
+	assert.Len(t, outbox(), 1)
+}
+
+func TestReportServer_ThresholdDisabled(t *testing.T) {
+	ctx := context.Background()
+	s := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+
+	published, err := s.Publish(ctx, apiv0.ServerJSON{Name: "com.example/weather", Description: "Weather lookups", Version: "1.0.0"})
+	require.NoError(t, err)
+	for range 10 {
+		_, err := s.ReportServer(ctx, published.Meta.Official.ID, ReportCategoryMalware, "")
+		require.NoError(t, err)
+	}
+
+	summaries, err := s.ServerReportSummaries(ctx, database.ServerReportFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 10, summaries[0].Total)
+	assert.False(t, summaries[0].NeedsAttention)
+}
+
+func TestReportServer_Validation(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	s := NewRegistryService(db, &config.Config{})
+
+	published, err := s.Publish(ctx, apiv0.ServerJSON{Name: "com.example/weather", Description: "Weather lookups", Version: "1.0.0"})
+	require.NoError(t, err)
+	id := published.Meta.Official.ID
+
+	t.Run("unknown category", func(t *testing.T) {
+		_, err := s.ReportServer(ctx, id, "boring", "")
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("text is sanitized", func(t *testing.T) {
+		report, err := s.ReportServer(ctx, id, ReportCategoryOther, "  broken\x1b[31m link\u200b\n")
+		require.NoError(t, err)
+		assert.Equal(t, "broken[31m link", report.Text)
+
+		stored, err := s.ListServerReports(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, "broken[31m link", stored[0].Text)
+	})
+
+	t.Run("text is capped", func(t *testing.T) {
+		_, err := s.ReportServer(ctx, id, ReportCategoryOther, strings.Repeat("x", MaxReportTextLength+1))
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		_, err := s.ReportServer(ctx, "00000000-0000-0000-0000-000000000000", ReportCategoryOther, "")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("hidden versions cannot be reported", func(t *testing.T) {
+		held := seedVersion(t, db, "com.example/held", "1.0.0", time.Now(), true, model.StatusPending)
+		_, err := s.ReportServer(ctx, held, ReportCategorySpam, "")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
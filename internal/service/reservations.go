@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// ErrNamespaceReserved is returned when publishing under a reserved namespace with an identity
+// its reservation doesn't allow
+var ErrNamespaceReserved = errors.New("namespace is reserved")
+
+// reservationCacheTTL bounds how long a replica keeps using reservations another replica changed.
+// Changes made through this replica apply immediately.
+const reservationCacheTTL = 30 * time.Second
+
+// namespacePatternRegex matches a namespace, or a namespace prefix followed by *
+var namespacePatternRegex = regexp.MustCompile(`^[a-zA-Z0-9.-]+\*?$`)
+
+// reservationCache holds the namespace reservations in memory, since every publish consults them
+type reservationCache struct {
+	mu           sync.Mutex
+	reservations []*database.NamespaceReservation
+	loadedAt     time.Time
+	generation   uint64 // bumped by invalidate, so a load that raced a change isn't kept
+}
+
+// get returns the reservations, loading them from db when the cached copy is missing or stale
+func (c *reservationCache) get(ctx context.Context, db database.Database) ([]*database.NamespaceReservation, error) {
+	c.mu.Lock()
+	if !c.loadedAt.IsZero() && time.Since(c.loadedAt) < reservationCacheTTL {
+		defer c.mu.Unlock()
+		return c.reservations, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	reservations, err := db.ListNamespaceReservations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.reservations, c.loadedAt = reservations, time.Now()
+	}
+	return reservations, nil
+}
+
+// invalidate drops the cached reservations after a change
+func (c *reservationCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reservations, c.loadedAt = nil, time.Time{}
+	c.generation++
+}
+
+// ListNamespaceReservations returns every namespace reservation
+func (s *registryServiceImpl) ListNamespaceReservations(ctx context.Context) ([]*database.NamespaceReservation, error) {
+	return s.db.ListNamespaceReservations(ctx)
+}
+
+// ReserveNamespace reserves namespace, or a prefix of namespaces when it ends in *, for the
+// identities matching allowedSubjects, replacing any earlier reservation of it. Each allowed
+// subject is an auth method and subject joined by a colon, where a trailing * matches any suffix.
+func (s *registryServiceImpl) ReserveNamespace(ctx context.Context, namespace string, allowedSubjects []string, reason, createdBy string) (*database.NamespaceReservation, error) {
+	if !namespacePatternRegex.MatchString(namespace) {
+		return nil, fmt.Errorf("%w: namespace must be a namespace, optionally ending in *", database.ErrInvalidInput)
+	}
+	for _, subject := range allowedSubjects {
+		method, pattern, ok := strings.Cut(subject, ":")
+		if !ok || method == "" || pattern == "" {
+			return nil, fmt.Errorf("%w: allowed subject %q must be <auth method>:<subject>", database.ErrInvalidInput, subject)
+		}
+	}
+
+	reservation := &database.NamespaceReservation{
+		Namespace:       namespace,
+		AllowedSubjects: allowedSubjects,
+		Reason:          reason,
+		CreatedBy:       createdBy,
+		CreatedAt:       time.Now(),
+	}
+	if err := s.db.PutNamespaceReservation(ctx, reservation); err != nil {
+		return nil, err
+	}
+	s.reservations.invalidate()
+	return reservation, nil
+}
+
+// UnreserveNamespace removes the reservation of namespace
+func (s *registryServiceImpl) UnreserveNamespace(ctx context.Context, namespace string) error {
+	if err := s.db.DeleteNamespaceReservation(ctx, namespace); err != nil {
+		return err
+	}
+	s.reservations.invalidate()
+	return nil
+}
+
+// CheckNamespaceReservation returns ErrNamespaceReserved if the namespace of server name is
+// reserved and publisher is not allowed by every reservation covering it
+func (s *registryServiceImpl) CheckNamespaceReservation(ctx context.Context, name string, publisher Publisher) error {
+	reservations, err := s.reservations.get(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	namespace, _, _ := strings.Cut(name, "/")
+	subject := publisher.AuthMethod + ":" + publisher.Subject
+	for _, reservation := range reservations {
+		if !matchesPattern(namespace, reservation.Namespace) {
+			continue
+		}
+		allowed := false
+		for _, pattern := range reservation.AllowedSubjects {
+			if publisher.AuthMethod != "" && matchesPattern(subject, pattern) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			if reservation.Reason != "" {
+				return fmt.Errorf("%w: %s: %s", ErrNamespaceReserved, reservation.Namespace, reservation.Reason)
+			}
+			return fmt.Errorf("%w: %s", ErrNamespaceReserved, reservation.Namespace)
+		}
+	}
+	return nil
+}
+
+// matchesPattern reports whether value equals pattern or, when pattern ends in *, starts with
+// the part before it
+func matchesPattern(value, pattern string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(value, prefix)
+	}
+	return value == pattern
+}
@@ -0,0 +1,83 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceReservations(t *testing.T) {
+	ctx := context.Background()
+	svc := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+
+	_, err := svc.ReserveNamespace(ctx, "com.acme", []string{"github-oidc:repo:acme/*", "dns:acme.com"}, "Acme's own namespace", "admin")
+	require.NoError(t, err)
+	_, err = svc.ReserveNamespace(ctx, "io.modelcontextprotocol.*", nil, "", "admin")
+	require.NoError(t, err)
+
+	publish := func(name string, publisher Publisher) error {
+		_, err := svc.Publish(WithPublisher(ctx, publisher), apiv0.ServerJSON{
+			Name:        name,
+			Description: "A test server",
+			Version:     "1.0.0",
+		})
+		return err
+	}
+
+	tests := []struct {
+		name      string
+		server    string
+		publisher Publisher
+		reserved  bool
+	}{
+		{name: "unreserved namespace", server: "com.example/server", publisher: Publisher{AuthMethod: "github-at", Subject: "octocat"}},
+		{name: "reserved namespace", server: "com.acme/server", publisher: Publisher{AuthMethod: "github-at", Subject: "octocat"}, reserved: true},
+		{name: "allowed subject pattern", server: "com.acme/ci", publisher: Publisher{AuthMethod: "github-oidc", Subject: "repo:acme/tools:ref:refs/heads/main"}},
+		{name: "allowed subject", server: "com.acme/dns", publisher: Publisher{AuthMethod: "dns", Subject: "acme.com"}},
+		{name: "subject of another auth method", server: "com.acme/http", publisher: Publisher{AuthMethod: "http", Subject: "acme.com"}, reserved: true},
+		{name: "unknown publisher", server: "com.acme/seed", reserved: true},
+		{name: "sub-namespace of an exact reservation", server: "com.acme.labs/server", publisher: Publisher{AuthMethod: "github-at", Subject: "octocat"}},
+		{name: "prefix reservation", server: "io.modelcontextprotocol.anonymous/server", publisher: Publisher{AuthMethod: "none", Subject: "anonymous"}, reserved: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := publish(tt.server, tt.publisher)
+			if tt.reserved {
+				assert.ErrorIs(t, err, ErrNamespaceReserved)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("refusal names the reason", func(t *testing.T) {
+		err := svc.CheckNamespaceReservation(ctx, "com.acme/server", Publisher{AuthMethod: "github-at", Subject: "octocat"})
+		assert.EqualError(t, err, "namespace is reserved: com.acme: Acme's own namespace")
+	})
+
+	t.Run("changes apply immediately", func(t *testing.T) {
+		octocat := Publisher{AuthMethod: "github-at", Subject: "octocat"}
+		_, err := svc.ReserveNamespace(ctx, "com.acme", []string{"github-at:octocat"}, "", "admin")
+		require.NoError(t, err)
+		assert.NoError(t, svc.CheckNamespaceReservation(ctx, "com.acme/server", octocat))
+
+		require.NoError(t, svc.UnreserveNamespace(ctx, "io.modelcontextprotocol.*"))
+		assert.NoError(t, svc.CheckNamespaceReservation(ctx, "io.modelcontextprotocol.anonymous/server", Publisher{AuthMethod: "none", Subject: "anonymous"}))
+
+		assert.ErrorIs(t, svc.UnreserveNamespace(ctx, "io.modelcontextprotocol.*"), database.ErrNotFound)
+	})
+
+	t.Run("invalid reservations", func(t *testing.T) {
+		_, err := svc.ReserveNamespace(ctx, "com.acme/server", nil, "", "admin")
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+		_, err = svc.ReserveNamespace(ctx, "com.acme", []string{"octocat"}, "", "admin")
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
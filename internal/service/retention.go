@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/tenancy"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// retentionPageSize is the page size used when scanning all servers for retention
+const retentionPageSize = 1000
+
+// RetentionPolicy decides which versions of a server are kept.
+// A version is kept if it is the latest, is pinned by an admin, is among the
+// KeepVersions newest versions, or was published within KeepWithin.
+type RetentionPolicy struct {
+	KeepVersions int
+	KeepWithin   time.Duration
+}
+
+// RetentionPolicyFromConfig builds the retention policy configured for this registry
+func RetentionPolicyFromConfig(cfg *config.Config) RetentionPolicy {
+	return RetentionPolicy{
+		KeepVersions: cfg.RetentionKeepVersions,
+		KeepWithin:   time.Duration(cfg.RetentionKeepDays) * 24 * time.Hour,
+	}
+}
+
+// RetentionCandidate is a server version that falls outside the retention policy
+type RetentionCandidate struct {
+	ID          string    `json:"id" doc:"Server ID (UUID)"`
+	Name        string    `json:"name" doc:"Server name"`
+	Version     string    `json:"version" doc:"Server version"`
+	PublishedAt time.Time `json:"published_at" doc:"When the version was published"`
+}
+
+// selectForRemoval returns the versions of a single server that the policy does not retain.
+// Only published versions, active or deprecated, count towards KeepVersions and are removed;
+// deleted versions and those held for admin review are left alone.
+func (p RetentionPolicy) selectForRemoval(versions []*apiv0.ServerJSON, now time.Time) []*apiv0.ServerJSON {
+	live := make([]*apiv0.ServerJSON, 0, len(versions))
+	for _, v := range versions {
+		// Versions published without a status are active
+		published := v.Status != model.StatusDeleted && !v.Status.Hidden()
+		if published && v.Meta != nil && v.Meta.Official != nil {
+			live = append(live, v)
+		}
+	}
+
+	// Newest first, using the same ordering that decides the latest version
+	sort.SliceStable(live, func(i, j int) bool {
+		a, b := live[i].Meta.Official, live[j].Meta.Official
+		return CompareVersions(live[i].Version, live[j].Version, a.PublishedAt, b.PublishedAt) > 0
+	})
+
+	var remove []*apiv0.ServerJSON
+	for i, v := range live {
+		official := v.Meta.Official
+		switch {
+		case official.IsLatest, official.Pinned:
+		case i < p.KeepVersions:
+		case p.KeepWithin > 0 && now.Sub(official.PublishedAt) < p.KeepWithin:
+		default:
+			remove = append(remove, v)
+		}
+	}
+	return remove
+}
+
+// ApplyRetention finds server versions outside the retention policy and soft-deletes them.
+// With dryRun set, it only reports what would be removed.
+func (s *registryServiceImpl) ApplyRetention(ctx context.Context, policy RetentionPolicy, dryRun bool) ([]RetentionCandidate, error) {
+	ctx = database.WithPrimaryReads(ctx)
+	if policy.KeepVersions < 1 {
+		return nil, fmt.Errorf("retention policy must keep at least one version")
+	}
+
+	byName, err := s.allServersByName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(byName))
+	for key := range byName {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	now := time.Now()
+	candidates := []RetentionCandidate{}
+	for _, key := range keys {
+		for _, server := range policy.selectForRemoval(byName[key], now) {
+			candidate := RetentionCandidate{
+				ID:          server.Meta.Official.ID,
+				Name:        server.Name,
+				Version:     server.Version,
+				PublishedAt: server.Meta.Official.PublishedAt,
+			}
+
+			if !dryRun {
+				if err := s.softDelete(ctx, server, now); err != nil {
+					return candidates, fmt.Errorf("failed to delete %s version %s: %w", server.Name, server.Version, err)
+				}
+				log.Printf("audit: retention soft-deleted server %s version %s (id=%s, published_at=%s, keep_versions=%d, keep_within=%s)",
+					candidate.Name, candidate.Version, candidate.ID, candidate.PublishedAt.Format(time.RFC3339), policy.KeepVersions, policy.KeepWithin)
+			}
+
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates, nil
+}
+
+// allServersByName pages through every server version and groups them by tenant and name, since
+// tenants publish servers of the same name independently
+func (s *registryServiceImpl) allServersByName(ctx context.Context) (map[string][]*apiv0.ServerJSON, error) {
+	byName := map[string][]*apiv0.ServerJSON{}
+	cursor := ""
+	for {
+		page, nextCursor, err := s.db.List(ctx, nil, cursor, retentionPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list servers: %w", err)
+		}
+		for _, server := range page {
+			key := tenancy.Of(server) + " " + server.Name
+			byName[key] = append(byName[key], server)
+		}
+		if nextCursor == "" {
+			return byName, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// softDelete marks a server version as deleted, the same way the edit endpoint does
+func (s *registryServiceImpl) softDelete(ctx context.Context, server *apiv0.ServerJSON, now time.Time) error {
+	official := *server.Meta.Official
+	official.UpdatedAt = now
+	meta := *server.Meta
+	meta.Official = &official
+
+	deleted := *server
+	deleted.Status = model.StatusDeleted
+	deleted.Meta = &meta
+
+	if err := s.invalidateLatest(ctx, server.Name); err != nil {
+		return err
+	}
+	defer s.invalidateLatestAfterWrite(ctx, server.Name)
+
+	if _, err := s.db.UpdateServer(ctx, official.ID, &deleted); err != nil {
+		return err
+	}
+	s.generation.Add(1)
+	s.recordChange(ChangeDeleted, &deleted)
+	return nil
+}
+
+// SetPinned sets the admin pin that exempts a server version from retention
+func (s *registryServiceImpl) SetPinned(ctx context.Context, id string, pinned bool) (*apiv0.ServerJSON, error) {
+	ctx = database.WithPrimaryReads(ctx)
+	server, err := s.db.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if server.Meta == nil || server.Meta.Official == nil {
+		return nil, fmt.Errorf("%w: server %s has no registry metadata", database.ErrInvalidInput, id)
+	}
+
+	official := *server.Meta.Official
+	official.Pinned = pinned
+	official.UpdatedAt = time.Now()
+	meta := *server.Meta
+	meta.Official = &official
+	updated := *server
+	updated.Meta = &meta
+
+	if err := s.invalidateLatest(ctx, server.Name); err != nil {
+		return nil, err
+	}
+	defer s.invalidateLatestAfterWrite(ctx, server.Name)
+
+	serverRecord, err := s.db.UpdateServer(ctx, id, &updated)
+	if err != nil {
+		return nil, err
+	}
+	s.generation.Add(1)
+	s.recordChange(ChangeUpdated, serverRecord)
+	return serverRecord, nil
+}
+
+// RetentionJob periodically applies the retention policy
+type RetentionJob struct {
+	registry RegistryService
+	policy   RetentionPolicy
+	interval time.Duration
+}
+
+// NewRetentionJob creates a job that applies policy every interval
+func NewRetentionJob(registry RegistryService, policy RetentionPolicy, interval time.Duration) *RetentionJob {
+	return &RetentionJob{
+		registry: registry,
+		policy:   policy,
+		interval: interval,
+	}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *RetentionJob) Start(ctx context.Context) {
+	go j.Run(ctx)
+}
+
+// Run runs the job until ctx is cancelled, returning once a run in progress has stopped
+func (j *RetentionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.runOnce(ctx)
+		}
+	}
+}
+
+func (j *RetentionJob) runOnce(ctx context.Context) {
+	removed, err := j.registry.ApplyRetention(ctx, j.policy, false)
+	if err != nil {
+		log.Printf("Retention job failed after removing %d versions: %v", len(removed), err)
+		return
+	}
+	log.Printf("Retention job removed %d versions", len(removed))
+}
@@ -0,0 +1,163 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedVersion(t *testing.T, db database.Database, name, version string, publishedAt time.Time, isLatest bool, status model.Status) string {
+	t.Helper()
+
+	id := fmt.Sprintf("%s@%s", name, version)
+	_, err := db.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Name:        name,
+		Description: "A test server",
+		Version:     version,
+		Status:      status,
+		Meta: &apiv0.ServerMeta{
+			Official: &apiv0.RegistryExtensions{
+				ID:          id,
+				PublishedAt: publishedAt,
+				UpdatedAt:   publishedAt,
+				IsLatest:    isLatest,
+			},
+		},
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestApplyRetention(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{})
+	now := time.Now()
+
+	// 25 nightly versions, one published per day, the newest being latest
+	const nightly = "com.example/nightly"
+	const versionCount = 25
+	ids := make(map[string]string, versionCount)
+	for i := 0; i < versionCount; i++ {
+		version := fmt.Sprintf("1.0.%d", i)
+		status := model.StatusActive
+		if i == 1 {
+			status = model.StatusDeleted
+		}
+		publishedAt := now.Add(-time.Duration(versionCount-i) * 24 * time.Hour)
+		ids[version] = seedVersion(t, db, nightly, version, publishedAt, i == versionCount-1, status)
+	}
+
+	// A server with few old versions is untouched
+	seedVersion(t, db, "com.example/stable", "1.0.0", now.AddDate(-1, 0, 0), false, model.StatusActive)
+	seedVersion(t, db, "com.example/stable", "2.0.0", now.AddDate(-1, 0, 0), true, model.StatusActive)
+
+	_, err := svc.SetPinned(ctx, ids["1.0.3"], true)
+	require.NoError(t, err)
+
+	policy := RetentionPolicy{KeepVersions: 5, KeepWithin: 10 * 24 * time.Hour}
+
+	// Kept: latest (1.0.24), newest five (1.0.20-1.0.24), last ten days (1.0.16-1.0.24) and pinned (1.0.3).
+	// 1.0.1 is already deleted.
+	var expected []string
+	for i := 0; i < 16; i++ {
+		if i != 1 && i != 3 {
+			expected = append(expected, fmt.Sprintf("1.0.%d", i))
+		}
+	}
+
+	versionsOf := func(candidates []RetentionCandidate) []string {
+		var versions []string
+		for _, c := range candidates {
+			assert.Equal(t, nightly, c.Name)
+			versions = append(versions, c.Version)
+		}
+		return versions
+	}
+
+	// Dry run reports without changing anything
+	generation := svc.Generation()
+	preview, err := svc.ApplyRetention(ctx, policy, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expected, versionsOf(preview))
+	assert.Equal(t, generation, svc.Generation())
+	for _, version := range expected {
+		server, err := db.GetByID(ctx, ids[version])
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusActive, server.Status)
+	}
+
+	// Applying soft-deletes exactly the previewed versions
+	removed, err := svc.ApplyRetention(ctx, policy, false)
+	require.NoError(t, err)
+	assert.Equal(t, preview, removed)
+	assert.Greater(t, svc.Generation(), generation)
+
+	retained := map[string]bool{}
+	for version, id := range ids {
+		server, err := db.GetByID(ctx, id)
+		require.NoError(t, err, "soft-deleted versions must remain in the database")
+		if server.Status != model.StatusDeleted {
+			retained[version] = true
+		}
+	}
+	expectedRetained := map[string]bool{"1.0.3": true}
+	for i := 16; i < versionCount; i++ {
+		expectedRetained[fmt.Sprintf("1.0.%d", i)] = true
+	}
+	assert.Equal(t, expectedRetained, retained)
+
+	latest, err := db.GetByID(ctx, ids["1.0.24"])
+	require.NoError(t, err)
+	assert.True(t, latest.Meta.Official.IsLatest)
+
+	// A second run has nothing left to do
+	again, err := svc.ApplyRetention(ctx, policy, false)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+}
+
+func TestApplyRetention_HeldVersionsDoNotCount(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{})
+	now := time.Now()
+
+	const name = "com.example/reviewed"
+	seedVersion(t, db, name, "1.0.0", now.AddDate(0, -3, 0), false, model.StatusActive)
+	seedVersion(t, db, name, "1.1.0", now.AddDate(0, -2, 0), true, model.StatusDeprecated)
+	pending := seedVersion(t, db, name, "2.0.0", now.AddDate(0, -1, 0), false, model.StatusPending)
+	rejected := seedVersion(t, db, name, "2.1.0", now.AddDate(0, -1, 0), false, model.StatusRejected)
+
+	// The two published versions are the two newest that count, whatever is awaiting review
+	removed, err := svc.ApplyRetention(ctx, RetentionPolicy{KeepVersions: 2}, false)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+
+	// Held versions are never removed by retention
+	removed, err = svc.ApplyRetention(ctx, RetentionPolicy{KeepVersions: 1}, false)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "1.0.0", removed[0].Version)
+	for id, status := range map[string]model.Status{pending: model.StatusPending, rejected: model.StatusRejected} {
+		server, err := db.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, status, server.Status)
+	}
+}
+
+func TestApplyRetention_RequiresKeepVersions(t *testing.T) {
+	svc := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+
+	_, err := svc.ApplyRetention(context.Background(), RetentionPolicy{KeepWithin: time.Hour}, true)
+	assert.Error(t, err)
+}
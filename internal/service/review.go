@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// holdReason returns why a new version of name must be held for admin approval, or "" if it
+// can be published directly. Only namespaces without an approved server are held, so once an
+// admin approves a namespace's first version its later publishes go through instantly.
+func (s *registryServiceImpl) holdReason(ctx context.Context, name string) (string, error) {
+	if s.cfg.TyposquatMaxDistance <= 0 && !s.cfg.ReviewNewNamespaces {
+		return "", nil
+	}
+	namespace, _, _ := strings.Cut(name, "/")
+
+	counts, err := s.db.CountNamespaces(ctx)
+	if err != nil {
+		return "", err
+	}
+	if counts[namespace] > 0 {
+		return "", nil
+	}
+
+	if lookalike := s.lookalikeNamespace(namespace, counts); lookalike != "" {
+		return fmt.Sprintf("its namespace resembles established namespace %s", lookalike), nil
+	}
+	if s.cfg.ReviewNewNamespaces {
+		return "it is the first publish to its namespace", nil
+	}
+	return "", nil
+}
+
+// ApprovePending releases a server version held for admin approval, making it active
+func (s *registryServiceImpl) ApprovePending(ctx context.Context, id string) (*apiv0.ServerJSON, error) {
+	return s.review(ctx, id, model.StatusActive, "")
+}
+
+// RejectPending declines a server version held for admin approval. It stays hidden from public
+// endpoints, and its publisher can read the reason from the server's registry metadata.
+func (s *registryServiceImpl) RejectPending(ctx context.Context, id, reason string) (*apiv0.ServerJSON, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("%w: a rejection reason is required", database.ErrInvalidInput)
+	}
+	return s.review(ctx, id, model.StatusRejected, reason)
+}
+
+// review moves a pending server version to status, recording the reason for a rejection
+func (s *registryServiceImpl) review(ctx context.Context, id string, status model.Status, reason string) (*apiv0.ServerJSON, error) {
+	ctx = database.WithPrimaryReads(ctx)
+	server, err := s.db.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if server.Status != model.StatusPending {
+		return nil, fmt.Errorf("%w: server %s is not pending approval", database.ErrInvalidInput, id)
+	}
+	if server.Meta == nil || server.Meta.Official == nil {
+		return nil, fmt.Errorf("%w: server %s has no registry metadata", database.ErrInvalidInput, id)
+	}
+
+	// Bump updated_at so sync clients pick up an approved version and polling publishers see the decision
+	official := *server.Meta.Official
+	official.UpdatedAt = time.Now()
+	official.RejectionReason = reason
+	meta := *server.Meta
+	meta.Official = &official
+	updated := *server
+	updated.Meta = &meta
+	updated.Status = status
+
+	if err := s.invalidateLatest(ctx, server.Name); err != nil {
+		return nil, err
+	}
+	defer s.invalidateLatestAfterWrite(ctx, server.Name)
+
+	serverRecord, err := s.db.UpdateServer(ctx, id, &updated)
+	if err != nil {
+		return nil, err
+	}
+	s.generation.Add(1)
+	// Rejected versions stay hidden, so only approvals are announced
+	s.recordChange(ChangePublished, serverRecord)
+	return serverRecord, nil
+}
@@ -0,0 +1,126 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish_ReviewsNewNamespaces(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{ReviewNewNamespaces: true})
+	seedVersion(t, db, "io.github.acme/server", "1.0.0", time.Now(), true, model.StatusActive)
+
+	publish := func(name, version string) *apiv0.ServerJSON {
+		published, err := svc.Publish(ctx, apiv0.ServerJSON{
+			Name:        name,
+			Description: "A test server",
+			Version:     version,
+			Status:      model.StatusActive,
+		})
+		require.NoError(t, err)
+		return published
+	}
+
+	t.Run("established namespaces publish instantly", func(t *testing.T) {
+		assert.Equal(t, model.StatusActive, publish("io.github.acme/new-tool", "1.0.0").Status)
+	})
+
+	first := publish("io.github.octocat/weather", "1.0.0")
+	second := publish("io.github.octocat/weather", "1.1.0")
+	rejected := publish("io.github.spammer/tool", "1.0.0")
+
+	t.Run("new namespaces are held until a version is approved", func(t *testing.T) {
+		assert.Equal(t, model.StatusPending, first.Status)
+		assert.Equal(t, model.StatusPending, second.Status)
+
+		_, err := svc.ApprovePending(ctx, first.Meta.Official.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusActive, publish("io.github.octocat/weather", "1.2.0").Status)
+		assert.Equal(t, model.StatusActive, publish("io.github.octocat/forecast", "1.0.0").Status)
+	})
+
+	t.Run("rejection keeps the version hidden and the namespace untrusted", func(t *testing.T) {
+		server, err := svc.RejectPending(ctx, rejected.Meta.Official.ID, "Spam")
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusRejected, server.Status)
+		assert.Equal(t, "Spam", server.Meta.Official.RejectionReason)
+
+		servers, _, err := svc.List(ctx, &database.ServerFilter{ExcludeHidden: true}, "", 100)
+		require.NoError(t, err)
+		for _, server := range servers {
+			assert.NotEqual(t, "io.github.spammer/tool", server.Name)
+		}
+
+		assert.Equal(t, model.StatusPending, publish("io.github.spammer/tool", "1.0.1").Status)
+	})
+}
+
+func TestPublish_NamespaceReviewDisabled(t *testing.T) {
+	svc := NewRegistryService(database.NewMemoryDB(), &config.Config{})
+
+	published, err := svc.Publish(context.Background(), apiv0.ServerJSON{
+		Name:        "io.github.octocat/weather",
+		Description: "A test server",
+		Version:     "1.0.0",
+		Status:      model.StatusActive,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusActive, published.Status)
+}
+
+func TestApprovePending(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{})
+	publishedAt := time.Now().Add(-time.Hour)
+	heldID := seedVersion(t, db, "io.github.acrne/tool", "1.0.0", publishedAt, true, model.StatusPending)
+	activeID := seedVersion(t, db, "io.github.acme/tool", "1.0.0", publishedAt, true, model.StatusActive)
+
+	approved, err := svc.ApprovePending(ctx, heldID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusActive, approved.Status)
+	assert.True(t, approved.Meta.Official.UpdatedAt.After(publishedAt))
+
+	_, err = svc.ApprovePending(ctx, heldID)
+	assert.ErrorIs(t, err, database.ErrInvalidInput, "already approved")
+
+	_, err = svc.ApprovePending(ctx, activeID)
+	assert.ErrorIs(t, err, database.ErrInvalidInput)
+
+	_, err = svc.ApprovePending(ctx, "missing")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestRejectPending(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{})
+	publishedAt := time.Now().Add(-time.Hour)
+	heldID := seedVersion(t, db, "io.github.acrne/tool", "1.0.0", publishedAt, true, model.StatusPending)
+	activeID := seedVersion(t, db, "io.github.acme/tool", "1.0.0", publishedAt, true, model.StatusActive)
+
+	_, err := svc.RejectPending(ctx, heldID, " ")
+	assert.ErrorIs(t, err, database.ErrInvalidInput, "a reason is required")
+
+	rejected, err := svc.RejectPending(ctx, heldID, "Impersonates io.github.acme")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusRejected, rejected.Status)
+	assert.Equal(t, "Impersonates io.github.acme", rejected.Meta.Official.RejectionReason)
+	assert.True(t, rejected.Meta.Official.UpdatedAt.After(publishedAt))
+
+	_, err = svc.ApprovePending(ctx, heldID)
+	assert.ErrorIs(t, err, database.ErrInvalidInput, "rejected versions cannot be approved")
+
+	_, err = svc.RejectPending(ctx, activeID, "Spam")
+	assert.ErrorIs(t, err, database.ErrInvalidInput)
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	"github.com/modelcontextprotocol/registry/internal/database"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 )
@@ -8,11 +11,83 @@ import (
 // RegistryService defines the interface for registry operations
 type RegistryService interface {
 	// Retrieve all servers with optional filtering
-	List(filter *database.ServerFilter, cursor string, limit int) ([]apiv0.ServerJSON, string, error)
+	List(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]apiv0.ServerJSON, string, error)
+	// Count the servers matching the filter, across all pages
+	Count(ctx context.Context, filter *database.ServerFilter) (int, error)
 	// Retrieve a single server by registry metadata ID
-	GetByID(id string) (*apiv0.ServerJSON, error)
+	GetByID(ctx context.Context, id string) (*apiv0.ServerJSON, error)
+	// Retrieve several servers by registry metadata ID in one database lookup, keyed by ID,
+	// listing the IDs that match none
+	GetByIDs(ctx context.Context, ids []string) (map[string]*apiv0.ServerJSON, []string, error)
+	// Retrieve the latest version of a server by name
+	GetLatestByName(ctx context.Context, name string) (*apiv0.ServerJSON, error)
+	// Retrieve the registry metadata of a server version by ID, without its document
+	GetHeadByID(ctx context.Context, id string) (*database.ServerHead, error)
+	// Retrieve the registry metadata of a server version by name, or of its latest version if version is empty
+	FindHead(ctx context.Context, name, version string) (*database.ServerHead, error)
 	// Publish a server
-	Publish(req apiv0.ServerJSON) (*apiv0.ServerJSON, error)
+	Publish(ctx context.Context, req apiv0.ServerJSON) (*apiv0.ServerJSON, error)
 	// Update an existing server
-	EditServer(id string, req apiv0.ServerJSON) (*apiv0.ServerJSON, error)
+	EditServer(ctx context.Context, id string, req apiv0.ServerJSON) (*apiv0.ServerJSON, error)
+	// ApplyRetention soft-deletes versions outside the retention policy, or only reports them when dryRun is set
+	ApplyRetention(ctx context.Context, policy RetentionPolicy, dryRun bool) ([]RetentionCandidate, error)
+	// SetPinned sets the admin pin that exempts a server version from retention
+	SetPinned(ctx context.Context, id string, pinned bool) (*apiv0.ServerJSON, error)
+	// ApprovePending releases a server version held for admin approval, making it active
+	ApprovePending(ctx context.Context, id string) (*apiv0.ServerJSON, error)
+	// RejectPending declines a server version held for admin approval, recording the reason for its publisher
+	RejectPending(ctx context.Context, id, reason string) (*apiv0.ServerJSON, error)
+	// RepairText normalizes the text of stored server versions, or only reports what would change when dryRun is set
+	RepairText(ctx context.Context, dryRun bool) ([]TextRepair, error)
+	// BackfillSearchKeywords stores search keywords for the server versions written before they were, returning how many
+	BackfillSearchKeywords(ctx context.Context) (int, error)
+	// SetRemoteHealth records the result of probing a server version's remote endpoints
+	SetRemoteHealth(ctx context.Context, id string, health *apiv0.RemoteHealth) (*apiv0.ServerJSON, error)
+	// SetPackageLinks records the result of checking a server version's MCPB download URLs
+	SetPackageLinks(ctx context.Context, id string, links []apiv0.PackageLink) (*apiv0.ServerJSON, error)
+	// RegisterNotification registers a webhook URL or email address to be told about publishes under namespace
+	RegisterNotification(ctx context.Context, namespace, webhookURL, email, createdBy string) (*database.NamespaceNotification, error)
+	// Unsubscribe removes a notification registration, given the token from its unsubscribe link
+	Unsubscribe(ctx context.Context, id, token string) error
+	// UnsubscribeURL returns the unsubscribe link for a notification registration
+	UnsubscribeURL(id string) string
+	// ListNamespaceReservations returns every namespace reservation
+	ListNamespaceReservations(ctx context.Context) ([]*database.NamespaceReservation, error)
+	// ReserveNamespace reserves a namespace, or a prefix ending in *, for the allowed <auth method>:<subject> patterns
+	ReserveNamespace(ctx context.Context, namespace string, allowedSubjects []string, reason, createdBy string) (*database.NamespaceReservation, error)
+	// UnreserveNamespace removes the reservation of a namespace
+	UnreserveNamespace(ctx context.Context, namespace string) error
+	// CheckNamespaceReservation returns ErrNamespaceReserved if publisher may not publish name because its namespace is reserved
+	CheckNamespaceReservation(ctx context.Context, name string, publisher Publisher) error
+	// NamespaceOwnership returns the latest verification of a namespace, or database.ErrNotFound if it has none
+	NamespaceOwnership(ctx context.Context, namespace string) (*database.NamespaceVerification, error)
+	// NamespaceSettings returns the settings of a namespace, or database.ErrNotFound if it has none
+	NamespaceSettings(ctx context.Context, namespace string) (*database.NamespaceSettings, error)
+	// PutNamespaceSettings validates and stores the defaults new publishes under a namespace inherit
+	PutNamespaceSettings(ctx context.Context, settings *database.NamespaceSettings) (*database.NamespaceSettings, error)
+	// ListVersions lists the versions of a server, newest first, or summarizes them by minor release
+	ListVersions(ctx context.Context, name string, query VersionQuery) (*VersionList, error)
+	// NamespaceActivity composes a publisher's overview of a namespace, paginating its recently changed versions
+	NamespaceActivity(ctx context.Context, namespace string, since time.Time, cursor string, limit int) (*NamespaceActivity, error)
+	// RecordFetch counts a successful fetch of a server version's details, without blocking
+	RecordFetch(ctx context.Context, id string)
+	// NamespaceFetches returns the daily fetch counts of the servers under a namespace on the days from from to to
+	NamespaceFetches(ctx context.Context, namespace string, from, to time.Time) ([]ServerFetches, error)
+	// ReportServer records a user's report that a server version is broken or abusive
+	ReportServer(ctx context.Context, id, category, text string) (*database.ServerReport, error)
+	// ServerReportSummaries counts the reports matching filter for each server version
+	ServerReportSummaries(ctx context.Context, filter database.ServerReportFilter) ([]ServerReports, error)
+	// ListServerReports returns the reports about a server version, with their text, newest first
+	ListServerReports(ctx context.Context, id string) ([]*database.ServerReport, error)
+	// ForkLineage resolves the origin a server version declares itself a fork of and counts the forks of its server
+	ForkLineage(ctx context.Context, server *apiv0.ServerJSON) (*apiv0.ForkOrigin, int, error)
+	// ListForks lists the latest version of each fork of a server
+	ListForks(ctx context.Context, name, cursor string, limit int) (*ForkList, error)
+	// FieldUsage counts the fields set by the latest server versions, reusing a report younger than maxAge
+	FieldUsage(ctx context.Context, maxAge time.Duration) (*FieldUsage, error)
+	// SubscribeChanges subscribes to changes of public server versions, returning the buffered
+	// events after the event ID after and whether some of them are no longer buffered
+	SubscribeChanges(ctx context.Context, after uint64) (*ChangeSubscription, []ChangeEvent, bool, error)
+	// Generation returns a counter that changes whenever registry data is modified
+	Generation() uint64
 }
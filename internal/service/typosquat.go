@@ -0,0 +1,75 @@
+package service
+
+import (
+	"sort"
+	"strings"
+)
+
+// confusables maps character sequences that render alike to a common form, so that
+// io.github.acrne and io.github.acme compare as equal rather than two edits apart
+var confusables = strings.NewReplacer(
+	"rn", "m",
+	"vv", "w",
+	"cl", "d",
+	"0", "o",
+	"1", "l",
+	"5", "s",
+)
+
+// lookalikeNamespace returns the established namespace that a brand-new namespace resembles,
+// or "" if there is none. counts holds the number of servers in each existing namespace. A
+// namespace is established once it has more than TyposquatMinServers servers, and resembles
+// another within TyposquatMaxDistance edits.
+func (s *registryServiceImpl) lookalikeNamespace(namespace string, counts map[string]int) string {
+	if s.cfg.TyposquatMaxDistance <= 0 {
+		return ""
+	}
+
+	// Check candidates in a fixed order so the reported namespace is deterministic
+	established := make([]string, 0, len(counts))
+	for existing, count := range counts {
+		if count > s.cfg.TyposquatMinServers {
+			established = append(established, existing)
+		}
+	}
+	sort.Strings(established)
+
+	closest, best := "", s.cfg.TyposquatMaxDistance+1
+	for _, existing := range established {
+		distance := min(editDistance(namespace, existing), editDistance(confusables.Replace(namespace), confusables.Replace(existing)))
+		if distance < best {
+			closest, best = existing, distance
+		}
+	}
+	return closest
+}
+
+// editDistance returns the number of single-character insertions, deletions, substitutions
+// and adjacent transpositions needed to turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// Three rolling rows of the optimal string alignment table
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+	return prev[len(rb)]
+}
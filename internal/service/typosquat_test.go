@@ -0,0 +1,120 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish_HoldsLookalikeNamespaces(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{TyposquatMaxDistance: 1, TyposquatMinServers: 2})
+
+	// io.github.acme is established, io.github.acme2 already coexists with it, and
+	// io.github.small is too small to be worth impersonating
+	now := time.Now()
+	for i := range 3 {
+		seedVersion(t, db, fmt.Sprintf("io.github.acme/server-%d", i), "1.0.0", now, true, model.StatusActive)
+	}
+	seedVersion(t, db, "io.github.acme2/server", "1.0.0", now, true, model.StatusActive)
+	seedVersion(t, db, "io.github.small/server", "1.0.0", now, true, model.StatusActive)
+
+	tests := []struct {
+		name       string
+		serverName string
+		wantStatus model.Status
+	}{
+		{name: "confusable characters", serverName: "io.github.acrne/tool", wantStatus: model.StatusPending},
+		{name: "single substitution", serverName: "io.github.acmf/tool", wantStatus: model.StatusPending},
+		{name: "adjacent transposition", serverName: "io.github.acem/tool", wantStatus: model.StatusPending},
+		{name: "established namespace", serverName: "io.github.acme/new-tool", wantStatus: model.StatusActive},
+		{name: "existing similar namespace", serverName: "io.github.acme2/new-tool", wantStatus: model.StatusActive},
+		{name: "resembles only a small namespace", serverName: "io.github.smal/tool", wantStatus: model.StatusActive},
+		{name: "unrelated namespace", serverName: "io.github.weather/tool", wantStatus: model.StatusActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			published, err := svc.Publish(ctx, apiv0.ServerJSON{
+				Name:        tt.serverName,
+				Description: "A test server",
+				Version:     "1.0.0",
+				Status:      model.StatusActive,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, published.Status)
+		})
+	}
+
+	t.Run("held namespaces stay held until approved", func(t *testing.T) {
+		published, err := svc.Publish(ctx, apiv0.ServerJSON{
+			Name:        "io.github.acrne/another-tool",
+			Description: "A test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusPending, published.Status)
+	})
+
+	t.Run("held versions are excluded from public listings", func(t *testing.T) {
+		servers, _, err := svc.List(ctx, &database.ServerFilter{ExcludeHidden: true}, "", 100)
+		require.NoError(t, err)
+		for _, server := range servers {
+			assert.NotEqual(t, model.StatusPending, server.Status, server.Name)
+		}
+
+		pending := model.StatusPending
+		held, _, err := svc.List(ctx, &database.ServerFilter{Status: &pending}, "", 100)
+		require.NoError(t, err)
+		assert.Len(t, held, 4)
+	})
+}
+
+func TestPublish_TyposquatCheckDisabled(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	svc := NewRegistryService(db, &config.Config{TyposquatMaxDistance: 0, TyposquatMinServers: 0})
+	seedVersion(t, db, "io.github.acme/server", "1.0.0", time.Now(), true, model.StatusActive)
+
+	published, err := svc.Publish(ctx, apiv0.ServerJSON{
+		Name:        "io.github.acrne/tool",
+		Description: "A test server",
+		Version:     "1.0.0",
+		Status:      model.StatusActive,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusActive, published.Status)
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"acme", "acme", 0},
+		{"acme", "acmf", 1},
+		{"acme", "acm", 1},
+		{"acme", "acmee", 1},
+		{"acme", "acem", 1},
+		{"acme", "acrne", 2},
+		{"", "acme", 4},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, editDistance(tt.a, tt.b))
+			assert.Equal(t, tt.want, editDistance(tt.b, tt.a))
+		})
+	}
+}
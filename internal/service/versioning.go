@@ -5,32 +5,14 @@ import (
 	"time"
 
 	"golang.org/x/mod/semver"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
 )
 
-// IsSemanticVersion checks if a version string follows semantic versioning format
-// Uses the official golang.org/x/mod/semver package for validation
-// Requires exactly three parts: major.minor.patch (optionally with prerelease/build)
+// IsSemanticVersion checks if a version string follows semantic versioning format: exactly
+// three parts, major.minor.patch, optionally with prerelease and build metadata
 func IsSemanticVersion(version string) bool {
-	// The semver package requires a "v" prefix, so add it for validation
-	versionWithV := ensureVPrefix(version)
-	if !semver.IsValid(versionWithV) {
-		return false
-	}
-
-	// Additional validation: require exactly three parts (major.minor.patch)
-	// Strip the v prefix and any prerelease/build metadata for counting parts
-	// This ensures semver compliance, because the default go module accepts invalid semvers :/
-	// (See https://pkg.go.dev/golang.org/x/mod/semver)
-	versionCore := strings.TrimPrefix(versionWithV, "v")
-	if idx := strings.Index(versionCore, "-"); idx != -1 {
-		versionCore = versionCore[:idx]
-	}
-	if idx := strings.Index(versionCore, "+"); idx != -1 {
-		versionCore = versionCore[:idx]
-	}
-
-	parts := strings.Split(versionCore, ".")
-	return len(parts) == 3
+	return validators.ClassifyVersion(version) == validators.VersionSemver
 }
 
 // ensureVPrefix adds a "v" prefix if not present
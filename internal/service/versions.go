@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// Release channels of server versions, by their semantic version
+const (
+	// ChannelStable holds semantic versions without a prerelease part, as in 1.2.0
+	ChannelStable = "stable"
+	// ChannelPrerelease holds semantic versions with one, as in 1.3.0-nightly.20250807
+	ChannelPrerelease = "prerelease"
+)
+
+// OtherRelease is the release group of versions that are not semantic versions
+const OtherRelease = "other"
+
+// versionsPageSize is the page size used when loading every version of a server
+const versionsPageSize = 100
+
+// VersionQuery selects and paginates the versions of a server
+type VersionQuery struct {
+	// Channel keeps only versions in ChannelStable or ChannelPrerelease; empty keeps every version
+	Channel string
+	// Summary groups the versions by minor release instead of listing them
+	Summary bool
+	// Cursor continues from a previous page: the ID of its last version, or the release of its last group
+	Cursor string
+	Limit  int
+}
+
+// VersionGroup summarizes the versions of a server from one minor release
+type VersionGroup struct {
+	Release string              `json:"release" doc:"The minor release, as in 1.2, or 'other' for versions that are not semantic versions" example:"1.2"`
+	Count   int                 `json:"count" doc:"Versions in the release"`
+	Newest  apiv0.ServerSummary `json:"newest" doc:"The newest version in the release"`
+}
+
+// VersionList is a page of the versions of a server, newest first, or of their groups
+type VersionList struct {
+	// Versions lists the versions, unless the query asked for a summary
+	Versions []apiv0.ServerSummary
+	// Groups lists the minor releases, for summary queries
+	Groups []VersionGroup
+	// NextCursor continues the list, or is empty on the last page
+	NextCursor string
+	// Total counts the versions or groups across all pages
+	Total int
+}
+
+// VersionChannel returns the channel of a version, or "" for versions that are not semantic
+// versions, which are in neither
+func VersionChannel(version string) string {
+	switch {
+	case !IsSemanticVersion(version):
+		return ""
+	case semver.Prerelease(ensureVPrefix(version)) != "":
+		return ChannelPrerelease
+	default:
+		return ChannelStable
+	}
+}
+
+// releaseOf returns the minor release a version belongs to, as in 1.2 for 1.2.3-rc.1
+func releaseOf(version string) string {
+	if !IsSemanticVersion(version) {
+		return OtherRelease
+	}
+	return strings.TrimPrefix(semver.MajorMinor(ensureVPrefix(version)), "v")
+}
+
+// sortVersionsNewestFirst orders versions the same way the latest version is decided
+func sortVersionsNewestFirst(versions []apiv0.ServerJSON) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		var a, b apiv0.RegistryExtensions
+		if versions[i].Meta != nil && versions[i].Meta.Official != nil {
+			a = *versions[i].Meta.Official
+		}
+		if versions[j].Meta != nil && versions[j].Meta.Official != nil {
+			b = *versions[j].Meta.Official
+		}
+		return CompareVersions(versions[i].Version, versions[j].Version, a.PublishedAt, b.PublishedAt) > 0
+	})
+}
+
+// SummarizeVersions groups versions by minor release, newest release first and versions that
+// are not semantic versions last. Each group counts its versions and carries the newest one.
+func SummarizeVersions(versions []apiv0.ServerJSON) []VersionGroup {
+	sorted := make([]apiv0.ServerJSON, len(versions))
+	copy(sorted, versions)
+	sortVersionsNewestFirst(sorted)
+
+	// With the versions newest first, each release's first version is its newest and releases
+	// come newest first, with versions that are not semantic versions, sorted below every
+	// semantic version, last
+	groups := []VersionGroup{}
+	index := map[string]int{}
+	for i := range sorted {
+		release := releaseOf(sorted[i].Version)
+		if n, ok := index[release]; ok {
+			groups[n].Count++
+			continue
+		}
+		index[release] = len(groups)
+		groups = append(groups, VersionGroup{Release: release, Count: 1, Newest: sorted[i].Summary()})
+	}
+	return groups
+}
+
+// ListVersions lists the versions of the server named name, newest first, or groups them by
+// minor release. Versions held for or rejected by admin review are left out. It returns
+// database.ErrNotFound if the server has no versions, and database.ErrInvalidCursor for a
+// cursor that is not in the list.
+func (s *registryServiceImpl) ListVersions(ctx context.Context, name string, query VersionQuery) (*VersionList, error) {
+	filter := &database.ServerFilter{Name: &name, ExcludeHidden: true, Projection: database.ProjectionSummary}
+	var versions []apiv0.ServerJSON
+	cursor := ""
+	for {
+		page, next, err := s.List(ctx, filter, cursor, versionsPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions: %w", err)
+		}
+		versions = append(versions, page...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(versions) == 0 {
+		return nil, database.ErrNotFound
+	}
+
+	if query.Channel != "" {
+		kept := versions[:0]
+		for _, version := range versions {
+			if VersionChannel(version.Version) == query.Channel {
+				kept = append(kept, version)
+			}
+		}
+		versions = kept
+	}
+
+	list := &VersionList{}
+	if query.Summary {
+		groups := SummarizeVersions(versions)
+		start, err := pageStart(len(groups), query.Cursor, func(i int) string { return groups[i].Release })
+		if err != nil {
+			return nil, err
+		}
+		end := min(start+query.Limit, len(groups))
+		list.Groups, list.Total = groups[start:end], len(groups)
+		if end < len(groups) {
+			list.NextCursor = groups[end-1].Release
+		}
+		return list, nil
+	}
+
+	sortVersionsNewestFirst(versions)
+	id := func(i int) string {
+		if versions[i].Meta == nil || versions[i].Meta.Official == nil {
+			return ""
+		}
+		return versions[i].Meta.Official.ID
+	}
+	start, err := pageStart(len(versions), query.Cursor, id)
+	if err != nil {
+		return nil, err
+	}
+	end := min(start+query.Limit, len(versions))
+	list.Versions, list.Total = make([]apiv0.ServerSummary, 0, end-start), len(versions)
+	for i := start; i < end; i++ {
+		list.Versions = append(list.Versions, versions[i].Summary())
+	}
+	if end < len(versions) {
+		list.NextCursor = id(end - 1)
+	}
+	return list, nil
+}
+
+// pageStart returns the index a page starts at: 0 without a cursor, or the one after the entry
+// whose key is the cursor
+func pageStart(n int, cursor string, key func(int) string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	for i := range n {
+		if key(i) == cursor {
+			return i + 1, nil
+		}
+	}
+	return 0, database.ErrInvalidCursor
+}
@@ -0,0 +1,186 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// syntheticVersions builds servers for versions, published a minute apart in order
+func syntheticVersions(versions ...string) []apiv0.ServerJSON {
+	start := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	servers := make([]apiv0.ServerJSON, 0, len(versions))
+	for i, version := range versions {
+		servers = append(servers, apiv0.ServerJSON{
+			Name:    "io.github.acme/nightly",
+			Version: version,
+			Meta: &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{
+				ID:          fmt.Sprintf("id-%d", i),
+				PublishedAt: start.Add(time.Duration(i) * time.Minute),
+			}},
+		})
+	}
+	return servers
+}
+
+func TestVersionChannel(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.2.0", service.ChannelStable},
+		{"0.0.1", service.ChannelStable},
+		{"1.2.0+build.5", service.ChannelStable},
+		{"1.3.0-nightly.20250807", service.ChannelPrerelease},
+		{"2.0.0-rc.1", service.ChannelPrerelease},
+		{"nightly", ""},
+		{"2025.08.07", ""},
+		{"1.2", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, service.VersionChannel(tt.version))
+		})
+	}
+}
+
+func TestSummarizeVersions(t *testing.T) {
+	type group struct {
+		release string
+		count   int
+		newest  string
+	}
+	tests := []struct {
+		name     string
+		versions []string
+		want     []group
+	}{
+		{
+			name: "no versions",
+			want: []group{},
+		},
+		{
+			name:     "one release",
+			versions: []string{"1.0.0", "1.0.1", "1.0.2"},
+			want:     []group{{"1.0", 3, "1.0.2"}},
+		},
+		{
+			name:     "newest release first, whatever the publish order",
+			versions: []string{"2.0.0", "1.1.0", "1.2.0", "1.1.1", "0.9.0"},
+			want:     []group{{"2.0", 1, "2.0.0"}, {"1.2", 1, "1.2.0"}, {"1.1", 2, "1.1.1"}, {"0.9", 1, "0.9.0"}},
+		},
+		{
+			name: "nightly prereleases count toward their release",
+			versions: []string{
+				"1.3.0-nightly.20250801", "1.3.0-nightly.20250802", "1.2.0", "1.3.0-nightly.20250803", "1.3.0",
+			},
+			want: []group{{"1.3", 4, "1.3.0"}, {"1.2", 1, "1.2.0"}},
+		},
+		{
+			name:     "a release with only prereleases",
+			versions: []string{"1.0.0", "2.0.0-rc.1", "2.0.0-rc.2"},
+			want:     []group{{"2.0", 2, "2.0.0-rc.2"}, {"1.0", 1, "1.0.0"}},
+		},
+		{
+			name:     "non-semver strays are grouped last, newest published first",
+			versions: []string{"nightly-a", "1.0.0", "2025.08.07", "0.1.0", "nightly-b"},
+			want:     []group{{"1.0", 1, "1.0.0"}, {"0.1", 1, "0.1.0"}, {service.OtherRelease, 3, "nightly-b"}},
+		},
+		{
+			name:     "only non-semver strays",
+			versions: []string{"alpha", "beta"},
+			want:     []group{{service.OtherRelease, 2, "beta"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []group{}
+			for _, g := range service.SummarizeVersions(syntheticVersions(tt.versions...)) {
+				got = append(got, group{g.Release, g.Count, g.Newest.Version})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB()
+	registry := service.NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+	for _, server := range syntheticVersions("1.0.0", "1.1.0-nightly.1", "1.1.0-nightly.2", "1.1.0", "stray", "1.2.0-rc.1") {
+		_, err := db.CreateServer(ctx, &server)
+		require.NoError(t, err)
+	}
+	// Versions held for admin review are left out
+	held := syntheticVersions("9.0.0")[0]
+	held.Status = model.StatusPending
+	held.Meta.Official.ID = "held"
+	_, err := db.CreateServer(ctx, &held)
+	require.NoError(t, err)
+
+	versionsOf := func(list *service.VersionList) []string {
+		versions := []string{}
+		for _, version := range list.Versions {
+			versions = append(versions, version.Version)
+		}
+		return versions
+	}
+
+	t.Run("paginated newest first", func(t *testing.T) {
+		first, err := registry.ListVersions(ctx, "io.github.acme/nightly", service.VersionQuery{Limit: 4})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.2.0-rc.1", "1.1.0", "1.1.0-nightly.2", "1.1.0-nightly.1"}, versionsOf(first))
+		assert.Equal(t, 6, first.Total)
+		require.NotEmpty(t, first.NextCursor)
+
+		second, err := registry.ListVersions(ctx, "io.github.acme/nightly", service.VersionQuery{Limit: 4, Cursor: first.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.0.0", "stray"}, versionsOf(second))
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("channels", func(t *testing.T) {
+		stable, err := registry.ListVersions(ctx, "io.github.acme/nightly", service.VersionQuery{Channel: service.ChannelStable, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.1.0", "1.0.0"}, versionsOf(stable))
+
+		prerelease, err := registry.ListVersions(ctx, "io.github.acme/nightly", service.VersionQuery{Channel: service.ChannelPrerelease, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.2.0-rc.1", "1.1.0-nightly.2", "1.1.0-nightly.1"}, versionsOf(prerelease))
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		first, err := registry.ListVersions(ctx, "io.github.acme/nightly", service.VersionQuery{Summary: true, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, first.Groups, 2)
+		assert.Equal(t, "1.2", first.Groups[0].Release)
+		assert.Equal(t, 3, first.Groups[1].Count)
+		assert.Equal(t, 4, first.Total)
+		assert.Equal(t, "1.1", first.NextCursor)
+
+		second, err := registry.ListVersions(ctx, "io.github.acme/nightly", service.VersionQuery{Summary: true, Limit: 2, Cursor: first.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, second.Groups, 2)
+		assert.Equal(t, "1.0", second.Groups[0].Release)
+		assert.Equal(t, service.OtherRelease, second.Groups[1].Release)
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := registry.ListVersions(ctx, "io.github.acme/missing", service.VersionQuery{Limit: 10})
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		_, err = registry.ListVersions(ctx, "io.github.acme/nightly", service.VersionQuery{Limit: 10, Cursor: "unknown"})
+		require.ErrorIs(t, err, database.ErrInvalidCursor)
+	})
+}
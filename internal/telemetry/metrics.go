@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -32,6 +34,45 @@ type Metrics struct {
 
 	// Up tracks the health of the service
 	Up metric.Int64Gauge
+
+	// ListCacheRequests tracks list page cache lookups by result (hit, miss, bypass)
+	ListCacheRequests metric.Int64Counter
+
+	// LatestCacheRequests tracks latest-version cache lookups by result (hit, miss)
+	LatestCacheRequests metric.Int64Counter
+
+	// LegacyExtensionRequests tracks requests using the deprecated x-publisher extension format, by operation
+	LegacyExtensionRequests metric.Int64Counter
+
+	// LegacyPublishResponses tracks publish responses served in the legacy {"message", "id"} shape, by reason
+	LegacyPublishResponses metric.Int64Counter
+
+	// PublishWarnings tracks the warnings returned by successful publish and edit requests, by code and operation
+	PublishWarnings metric.Int64Counter
+
+	// MaintenanceRuns tracks maintenance task runs, by task and result (ok, error, timeout, panic, skipped)
+	MaintenanceRuns metric.Int64Counter
+
+	// MaintenanceRowsRemoved tracks the rows removed by maintenance tasks, by task
+	MaintenanceRowsRemoved metric.Int64Counter
+
+	// MaintenanceDuration tracks the duration of maintenance task runs, by task
+	MaintenanceDuration metric.Float64Histogram
+
+	// FetchAttributions tracks fetches of server details by how they were attributed to a
+	// namespace (counted, dropped, unattributed)
+	FetchAttributions metric.Int64Counter
+
+	// meter creates the instruments registered after construction, such as the database pool gauges
+	meter metric.Meter
+}
+
+// PoolStats is a snapshot of a database connection pool
+type PoolStats struct {
+	InUse    int64         // connections running a statement
+	Idle     int64         // open connections free for the next statement
+	Waits    int64         // acquires that had to wait for a connection, since startup
+	WaitTime time.Duration // total time spent in those waits, since startup
 }
 
 // ShutdownFunc is a delegate that shuts down the OpenTelemetry components.
@@ -73,14 +114,179 @@ func NewMetrics(meter metric.Meter) (*Metrics, error) {
 		return nil, fmt.Errorf("failed to create service up gauge: %w", err)
 	}
 
+	listCacheRequests, err := meter.Int64Counter(
+		Namespace+".list_cache.requests",
+		metric.WithDescription("Total number of server list cache lookups by result"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create list cache counter: %w", err)
+	}
+
+	latestCacheRequests, err := meter.Int64Counter(
+		Namespace+".latest_cache.requests",
+		metric.WithDescription("Total number of latest-version cache lookups by result"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create latest cache counter: %w", err)
+	}
+
+	legacyExtensionRequests, err := meter.Int64Counter(
+		Namespace+".legacy_extension.requests",
+		metric.WithDescription("Total number of requests using the deprecated x-publisher extension format"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create legacy extension counter: %w", err)
+	}
+
+	legacyPublishResponses, err := meter.Int64Counter(
+		Namespace+".legacy_publish.responses",
+		metric.WithDescription("Total number of publish responses served in the legacy message and id shape"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create legacy publish counter: %w", err)
+	}
+
+	publishWarnings, err := meter.Int64Counter(
+		Namespace+".publish.warnings",
+		metric.WithDescription("Total number of warnings returned by successful publish and edit requests by code"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create publish warnings counter: %w", err)
+	}
+
+	maintenanceRuns, err := meter.Int64Counter(
+		Namespace+".maintenance.runs",
+		metric.WithDescription("Total number of maintenance task runs by task and result"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create maintenance runs counter: %w", err)
+	}
+
+	maintenanceRowsRemoved, err := meter.Int64Counter(
+		Namespace+".maintenance.rows_removed",
+		metric.WithDescription("Total number of expired rows removed by maintenance tasks by task"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create maintenance rows counter: %w", err)
+	}
+
+	maintenanceDuration, err := meter.Float64Histogram(
+		Namespace+".maintenance.duration",
+		metric.WithDescription("Duration of maintenance task runs in seconds"),
+		metric.WithExplicitBucketBoundaries(
+			0.01, 0.05, 0.1, 0.5, 1.0, 5.0, 10.0, 30.0, 60.0, 300.0,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create maintenance duration histogram: %w", err)
+	}
+
+	fetchAttributions, err := meter.Int64Counter(
+		Namespace+".fetch_counts.fetches",
+		metric.WithDescription("Total number of server detail fetches by attribution result"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fetch attribution counter: %w", err)
+	}
+
 	return &Metrics{
-		Requests:        req,
-		RequestDuration: reqDuration,
-		ErrorCount:      errCount,
-		Up:              up,
+		Requests:                req,
+		RequestDuration:         reqDuration,
+		ErrorCount:              errCount,
+		Up:                      up,
+		ListCacheRequests:       listCacheRequests,
+		LatestCacheRequests:     latestCacheRequests,
+		LegacyExtensionRequests: legacyExtensionRequests,
+		LegacyPublishResponses:  legacyPublishResponses,
+		PublishWarnings:         publishWarnings,
+		MaintenanceRuns:         maintenanceRuns,
+		MaintenanceRowsRemoved:  maintenanceRowsRemoved,
+		MaintenanceDuration:     maintenanceDuration,
+		FetchAttributions:       fetchAttributions,
+		meter:                   meter,
 	}, nil
 }
 
+// ObserveDatabasePool exports the state of a database connection pool as gauges, reading it
+// with stats whenever metrics are collected
+func (m *Metrics) ObserveDatabasePool(stats func() PoolStats) error {
+	connections, err := m.meter.Int64ObservableGauge(
+		Namespace+".db.pool.connections",
+		metric.WithDescription("Open database connections by state (in_use, idle)"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pool connections gauge: %w", err)
+	}
+	waits, err := m.meter.Int64ObservableCounter(
+		Namespace+".db.pool.waits",
+		metric.WithDescription("Total number of statements that waited for a free database connection"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pool waits counter: %w", err)
+	}
+	waitTime, err := m.meter.Float64ObservableCounter(
+		Namespace+".db.pool.wait_duration",
+		metric.WithDescription("Total time statements spent waiting for a free database connection in seconds"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pool wait duration counter: %w", err)
+	}
+
+	_, err = m.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		snapshot := stats()
+		o.ObserveInt64(connections, snapshot.InUse, metric.WithAttributes(attribute.String("state", "in_use")))
+		o.ObserveInt64(connections, snapshot.Idle, metric.WithAttributes(attribute.String("state", "idle")))
+		o.ObserveInt64(waits, snapshot.Waits)
+		o.ObserveFloat64(waitTime, snapshot.WaitTime.Seconds())
+		return nil
+	}, connections, waits, waitTime)
+	if err != nil {
+		return fmt.Errorf("failed to register pool metrics callback: %w", err)
+	}
+	return nil
+}
+
+// ObserveReplicaFallbacks exports how many reads the database read replica failed and the
+// primary served instead, reading the total since startup with fallbacks whenever metrics are collected
+func (m *Metrics) ObserveReplicaFallbacks(fallbacks func() int64) error {
+	counter, err := m.meter.Int64ObservableCounter(
+		Namespace+".db.replica.fallbacks",
+		metric.WithDescription("Total number of reads the database read replica failed, served by the primary instead"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create replica fallbacks counter: %w", err)
+	}
+	_, err = m.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(counter, fallbacks())
+		return nil
+	}, counter)
+	if err != nil {
+		return fmt.Errorf("failed to register replica fallbacks callback: %w", err)
+	}
+	return nil
+}
+
+// ObserveInFlightRequests exports the number of HTTP requests being served as a gauge, reading it
+// with count whenever metrics are collected
+func ObserveInFlightRequests(meter metric.Meter, count func() int64) error {
+	inFlight, err := meter.Int64ObservableGauge(
+		Namespace+".http.requests.in_flight",
+		metric.WithDescription("Number of HTTP requests being served"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create in-flight requests gauge: %w", err)
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(inFlight, count())
+		return nil
+	}, inFlight)
+	if err != nil {
+		return fmt.Errorf("failed to register in-flight requests callback: %w", err)
+	}
+	return nil
+}
+
 func NewPrometheusMeterProvider(res *resource.Resource, exp *prometheus.Exporter) (*sdkmetric.MeterProvider, error) {
 	if exp == nil {
 		return nil, errors.New("exporter cannot be nil")
@@ -0,0 +1,45 @@
+// Package tenancy carries the tenant a request acts for when one registry serves several
+// organizations. The database scopes every server, notification and namespace verification
+// read and write to the tenant in the context; a context without one, as in single-tenant mode,
+// for background jobs and for admins acting across tenants, sees everything.
+package tenancy
+
+import (
+	"context"
+	"errors"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ErrTenantRequired is returned for writes that must belong to a tenant made without one
+var ErrTenantRequired = errors.New("this registry serves several tenants: the request must act for one")
+
+// Header selects the tenant an admin token without one of its own acts for
+const Header = "MCP-Registry-Tenant"
+
+type tenantKey struct{}
+
+// WithTenant scopes ctx to tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant ctx is scoped to, and whether it is scoped at all
+func FromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok
+}
+
+// Allows reports whether ctx may see data belonging to tenant
+func Allows(ctx context.Context, tenant string) bool {
+	scoped, ok := FromContext(ctx)
+	return !ok || scoped == tenant
+}
+
+// Of returns the tenant a server version belongs to, empty in single-tenant mode
+func Of(server *apiv0.ServerJSON) string {
+	if server == nil || server.Meta == nil || server.Meta.Official == nil {
+		return ""
+	}
+	return server.Meta.Official.Tenant
+}
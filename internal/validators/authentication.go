@@ -0,0 +1,83 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// validateAuthentication checks that a remote's authentication, if any, is coherent: its scheme
+// is known, it has the fields the scheme needs and no others, and it carries no credentials.
+// headers are the remote's headers, one of which the header scheme must name.
+func validateAuthentication(auth *model.Authentication, headers []model.KeyValueInput) error {
+	if auth == nil {
+		return nil
+	}
+	if !slices.Contains(Rules.AuthenticationSchemes, auth.Scheme) {
+		return fmt.Errorf("%w: unsupported scheme %q", ErrInvalidAuthentication, auth.Scheme)
+	}
+
+	oauth2 := auth.Scheme == model.AuthenticationSchemeOAuth2
+	if !oauth2 && (auth.AuthorizationURL != "" || auth.TokenURL != "" || len(auth.Scopes) > 0) {
+		return fmt.Errorf("%w: authorization_url, token_url and scopes are only allowed for the oauth2 scheme", ErrInvalidAuthentication)
+	}
+	if auth.Scheme != model.AuthenticationSchemeHeader && auth.HeaderName != "" {
+		return fmt.Errorf("%w: header_name is only allowed for the header scheme", ErrInvalidAuthentication)
+	}
+
+	switch auth.Scheme {
+	case model.AuthenticationSchemeOAuth2:
+		if err := validateOAuth2Endpoint(auth.AuthorizationURL); err != nil {
+			return fmt.Errorf("%w: authorization_url %w", ErrInvalidAuthentication, err)
+		}
+		if err := validateOAuth2Endpoint(auth.TokenURL); err != nil {
+			return fmt.Errorf("%w: token_url %w", ErrInvalidAuthentication, err)
+		}
+		for _, scope := range auth.Scopes {
+			if scope == "" {
+				return fmt.Errorf("%w: scopes must not be empty", ErrInvalidAuthentication)
+			}
+			if _, ok := findCredential(scope); ok {
+				return fmt.Errorf("%w: scope %s looks like a credential", ErrInvalidAuthentication, redact(scope))
+			}
+		}
+	case model.AuthenticationSchemeHeader:
+		if auth.HeaderName == "" {
+			return fmt.Errorf("%w: header_name is required for the header scheme", ErrInvalidAuthentication)
+		}
+		declared := slices.ContainsFunc(headers, func(header model.KeyValueInput) bool {
+			return http.CanonicalHeaderKey(header.Name) == http.CanonicalHeaderKey(auth.HeaderName)
+		})
+		if !declared {
+			return fmt.Errorf("%w: header_name %s is not one of the remote's headers", ErrInvalidAuthentication, auth.HeaderName)
+		}
+	}
+	return nil
+}
+
+// validateOAuth2Endpoint checks an OAuth 2.0 endpoint is an https URL that carries no
+// credentials, neither as userinfo nor in its query
+func validateOAuth2Endpoint(endpoint string) error {
+	if endpoint == "" {
+		return errors.New("is required for the oauth2 scheme")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("must be an https URL: %s", endpoint)
+	}
+	if u.User != nil {
+		return errors.New("must not contain credentials")
+	}
+	for _, values := range u.Query() {
+		for _, value := range values {
+			if _, ok := findCredential(value); ok || looksRandom(value) {
+				return errors.New("must not contain credentials")
+			}
+		}
+	}
+	return nil
+}
@@ -7,17 +7,94 @@ var (
 	// Repository validation errors
 	ErrInvalidRepositoryURL = errors.New("invalid repository URL")
 	ErrInvalidSubfolderPath = errors.New("invalid subfolder path")
+	ErrInvalidRepositoryID  = errors.New("invalid repository ID")
+	ErrRepositoryIDMismatch = errors.New("repository ID does not match the repository URL")
+
+	// Document validation errors
+	ErrUnknownField = errors.New("unknown field")
+
+	// Server name validation errors
+	ErrSuspiciousUnicode = errors.New("server name contains non-ASCII or invisible characters")
+
+	// Text encoding errors
+	ErrInvalidUTF8 = errors.New("text is not valid UTF-8")
 
 	// Package validation errors
-	ErrPackageNameHasSpaces = errors.New("package name cannot contain spaces")
+	ErrPackageNameHasSpaces       = errors.New("package name cannot contain spaces")
+	ErrDuplicatePackage           = errors.New("duplicate package")
+	ErrConflictingPackageVersions = errors.New("package listed with conflicting versions")
+	ErrInvalidOCIReference        = errors.New("invalid OCI image reference")
+	ErrReleaseTagMismatch         = errors.New("release tag does not match package version")
 
 	// Remote validation errors
-	ErrInvalidRemoteURL = errors.New("invalid remote URL")
+	ErrInvalidRemoteURL      = errors.New("invalid remote URL")
+	ErrDuplicateRemoteURL    = errors.New("duplicate remote URL")
+	ErrInvalidAuthentication = errors.New("invalid authentication")
+
+	// Template validation errors
+	ErrMalformedTemplate  = errors.New("malformed template placeholder")
+	ErrUndeclaredVariable = errors.New("template placeholder references undeclared variable")
+	ErrSecretInURL        = errors.New("secret variable must not be interpolated into a URL")
+	ErrUnresolvedVariable = errors.New("URL template variable has no default and is not a described required input")
+
+	// Input variable validation errors
+	ErrInvalidVariableName = errors.New("invalid variable name")
+	ErrTooManyVariables    = errors.New("too many variables")
+	ErrNestedVariables     = errors.New("variables cannot declare variables")
+
+	// Header validation errors
+	ErrTooManyHeaders     = errors.New("too many headers")
+	ErrHeaderValueTooLong = errors.New("header value too long")
+	ErrSecretHeaderValue  = errors.New("secret header must not have a literal value")
+	ErrCredentialInHeader = errors.New("header value looks like a credential")
+	ErrForbiddenHeader    = errors.New("header not allowed")
 
 	// Registry validation errors
 	ErrUnsupportedRegistryBaseURL   = errors.New("unsupported registry base URL")
 	ErrMismatchedRegistryTypeAndURL = errors.New("registry type and base URL do not match")
 
+	// Display metadata validation errors
+	ErrInvalidTitle            = errors.New("invalid title")
+	ErrTooManyIcons            = errors.New("too many icons")
+	ErrInvalidIconURL          = errors.New("invalid icon URL")
+	ErrUnsupportedIconMimeType = errors.New("unsupported icon MIME type")
+	ErrInvalidIconSize         = errors.New("invalid icon size")
+	ErrTooManyCategories       = errors.New("too many categories")
+	ErrDuplicateCategory       = errors.New("duplicate category")
+	ErrUnknownCategory         = errors.New("unknown category")
+
+	// Documentation validation errors
+	ErrInvalidDocumentationURL = errors.New("invalid documentation URL")
+	ErrInvalidWebsiteURL       = errors.New("invalid website URL")
+	ErrReadmeTooLarge          = errors.New("readme too large")
+	ErrReleaseNotesTooLarge    = errors.New("release notes too large")
+
+	// Path validation errors. ErrInvalidFilePath wraps one of the others, which describe the problem.
+	ErrInvalidFilePath  = errors.New("invalid file path")
+	ErrPathTraversal    = errors.New(`must not contain ".." segments`)
+	ErrAbsolutePath     = errors.New("must be a relative path")
+	ErrEmptyPathSegment = errors.New("must not contain empty segments")
+
+	// License validation errors
+	ErrInvalidLicense  = errors.New("invalid license")
+	ErrLicenseMismatch = errors.New("license does not match the package registry")
+
+	// Contact validation errors
+	ErrInvalidContact = errors.New("invalid contact")
+
+	// Fork lineage validation errors
+	ErrInvalidForkOf = errors.New("invalid forkOf")
+
+	// Protocol version validation errors
+	ErrUnknownMCPVersion = errors.New("unknown MCP protocol version")
+
+	// Server version validation errors
+	ErrInvalidServerVersion = errors.New("invalid version")
+	ErrVersionPrefix        = errors.New("version has a leading v")
+
+	// Edit validation errors
+	ErrImmutableField = errors.New("field cannot be edited")
+
 	// Argument validation errors
 	ErrNamedArgumentNameRequired     = errors.New("named argument name is required")
 	ErrInvalidNamedArgumentName      = errors.New("invalid named argument name format")
@@ -32,3 +109,38 @@ const (
 	SourceGitHub RepositorySource = "github"
 	SourceGitLab RepositorySource = "gitlab"
 )
+
+// Display metadata limits
+const (
+	MaxTitleLength   = 100
+	MaxIcons         = 8
+	MaxIconDimension = 1024
+	MaxCategories    = 5
+)
+
+// Transport header limits
+const (
+	MaxTransportHeaders  = 20
+	MaxHeaderValueLength = 1024
+)
+
+// MaxInputVariables is the most variables one input may declare
+const MaxInputVariables = 16
+
+// MaxReadmeBytes caps the size of a server's inline markdown README
+const MaxReadmeBytes = 32 * 1024
+
+// MaxReleaseNotesBytes caps the size of the markdown notes on what changed in a version
+const MaxReleaseNotesBytes = 16 * 1024
+
+// MaxLicenseLength is the longest SPDX license expression accepted
+const MaxLicenseLength = 200
+
+// MaxVersionLength is the longest server version accepted
+const MaxVersionLength = 64
+
+// MaxContactLength is the longest contact accepted
+const MaxContactLength = 200
+
+// MaxForkDepth is the longest chain of forks followed when checking a new fork's lineage
+const MaxForkDepth = 32
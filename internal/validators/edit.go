@@ -0,0 +1,104 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// editableFields are the server.json fields an edit may change, by JSON name. They describe a
+// version without changing what clients install or run, so they can be corrected after
+// publishing. Every other field, including ones added to server.json later, is fixed once
+// published: changing it takes a new version. The publisher-provided extension in _meta is
+// editable too, and registry metadata is kept from the stored version.
+var editableFields = map[string]bool{
+	"status":           true,
+	"description":      true,
+	"repository":       true,
+	"title":            true,
+	"icons":            true,
+	"categories":       true,
+	"documentationUrl": true,
+	"websiteUrl":       true,
+	"readme":           true,
+	"releaseNotes":     true,
+	"forkOf":           true, // checked again by the edit if it changes
+}
+
+// MergeEdit returns the stored server version current with the editable fields of req. Fields
+// that cannot be edited must be sent as stored, or left out when they are empty; each one
+// that differs is reported as an ErrImmutableField, attributed to the field. Registry metadata
+// in req's _meta is rejected as it is on publish, so it cannot be smuggled into an edit; the
+// result's _meta holds only req's publisher-provided extension, for the caller to add the
+// stored registry metadata to.
+func MergeEdit(current, req *apiv0.ServerJSON) (apiv0.ServerJSON, error) {
+	var errs []error
+	if req.Meta != nil && req.Meta.Official != nil {
+		errs = append(errs, fieldError("/_meta", fmt.Errorf("%w: registry metadata '_meta.%s' is set by the registry and cannot be sent with an edit",
+			ErrImmutableField, apiv0.OfficialMetaKey)))
+	}
+
+	merged := *current
+	from := reflect.ValueOf(req).Elem()
+	to := reflect.ValueOf(&merged).Elem()
+	for i := range from.NumField() {
+		name := jsonFieldName(from.Type().Field(i))
+		if name == "" || name == "_meta" {
+			continue
+		}
+		if editableFields[name] {
+			to.Field(i).Set(from.Field(i))
+			continue
+		}
+		if !sameValue(from.Field(i), to.Field(i)) {
+			errs = append(errs, fieldError("/"+name, fmt.Errorf("%w: %s cannot be changed after publishing; publish a new version instead", ErrImmutableField, name)))
+		}
+	}
+	if len(errs) > 0 {
+		return apiv0.ServerJSON{}, errors.Join(errs...)
+	}
+
+	merged.Meta = nil
+	if req.Meta != nil && req.Meta.PublisherProvided != nil {
+		merged.Meta = &apiv0.ServerMeta{PublisherProvided: req.Meta.PublisherProvided}
+	}
+	return merged, nil
+}
+
+// jsonFieldName returns the name field is encoded as, or "" if it is not encoded
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" || !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// sameValue reports whether a and b encode the same JSON, treating empty values as absent, as
+// the omitempty fields of server.json do
+func sameValue(a, b reflect.Value) bool {
+	if isEmptyValue(a) || isEmptyValue(b) {
+		return isEmptyValue(a) && isEmptyValue(b)
+	}
+	aJSON, aErr := json.Marshal(a.Interface())
+	bJSON, bErr := json.Marshal(b.Interface())
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
@@ -0,0 +1,87 @@
+package validators_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeEdit(t *testing.T) {
+	current := func() *apiv0.ServerJSON {
+		server := textServer("Weather lookups", "Your API key")
+		server.License = "MIT"
+		server.Categories = []string{"weather"}
+		server.Meta = &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{
+			ID: "4e0f8a3c-8f0d-4c1b-9d55-0d4e6f6d8a11", PublishedAt: time.Now(), IsLatest: true,
+		}}
+		return &server
+	}
+
+	t.Run("editable fields are taken from the edit", func(t *testing.T) {
+		req := *current()
+		req.Meta = &apiv0.ServerMeta{PublisherProvided: map[string]any{"build": "42"}}
+		req.Description = "Forecasts and weather lookups"
+		req.Status = model.StatusDeprecated
+		req.Categories = nil
+		req.Packages = append([]model.Package{}, req.Packages...) // an equal copy is not a change
+
+		merged, err := validators.MergeEdit(current(), &req)
+		require.NoError(t, err)
+		assert.Equal(t, "Forecasts and weather lookups", merged.Description)
+		assert.Equal(t, model.StatusDeprecated, merged.Status)
+		assert.Empty(t, merged.Categories)
+		assert.Equal(t, "MIT", merged.License)
+		require.NotNil(t, merged.Meta)
+		assert.Nil(t, merged.Meta.Official, "registry metadata is left for the caller to carry over")
+		assert.Equal(t, map[string]any{"build": "42"}, merged.Meta.PublisherProvided)
+	})
+
+	t.Run("empty locked fields match absent ones", func(t *testing.T) {
+		stored := current()
+		stored.Remotes = []model.Transport{}
+		req := *current()
+		req.Meta = nil
+		req.Remotes = nil
+
+		_, err := validators.MergeEdit(stored, &req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("locked fields are rejected with their pointers", func(t *testing.T) {
+		req := *current()
+		req.Meta = nil
+		req.Version = "2.0.0"
+		req.License = ""
+		req.Packages = []model.Package{{RegistryType: model.RegistryTypeNPM, Identifier: "@attacker/weather", Version: "1.0.0"}}
+
+		_, err := validators.MergeEdit(current(), &req)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, validators.ErrImmutableField))
+
+		var fields []string
+		for _, fieldErr := range validators.FieldErrors(err) {
+			assert.Equal(t, apiv0.ErrorCodeImmutableField, fieldErr.Code)
+			fields = append(fields, fieldErr.Field)
+		}
+		assert.ElementsMatch(t, []string{"/version", "/license", "/packages"}, fields)
+	})
+
+	t.Run("registry metadata cannot be sent", func(t *testing.T) {
+		req := *current()
+		req.Meta.Official.IsLatest = false
+
+		_, err := validators.MergeEdit(current(), &req)
+		require.Error(t, err)
+		fieldErrs := validators.FieldErrors(err)
+		require.Len(t, fieldErrs, 1)
+		assert.Equal(t, "/_meta", fieldErrs[0].Field)
+		assert.Equal(t, apiv0.ErrorCodeImmutableField, fieldErrs[0].Code)
+	})
+
+}
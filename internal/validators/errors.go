@@ -0,0 +1,138 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// FieldError is a validation failure of one field of a server.json. It wraps the error the
+// check returned, so errors.Is still finds the sentinel, and adds the field's JSON pointer and
+// a machine-readable code, which the API reports the same way as schema validation failures.
+type FieldError struct {
+	// Code is the machine-readable code of the failure, e.g. "invalid_title"
+	Code string
+	// Field is the JSON pointer to the field in the server.json, e.g. "/packages/0/identifier"
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// errorCodes are the codes of the sentinel validation errors. Failures without one get
+// apiv0.ErrorCodeInvalidValue.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrUnknownField, apiv0.ErrorCodeUnexpectedProperty},
+	{ErrInvalidRepositoryURL, apiv0.ErrorCodeInvalidRepositoryURL},
+	{ErrInvalidSubfolderPath, apiv0.ErrorCodeInvalidSubfolderPath},
+	{ErrInvalidRepositoryID, apiv0.ErrorCodeInvalidRepositoryID},
+	{ErrRepositoryIDMismatch, apiv0.ErrorCodeRepositoryIDMismatch},
+	{ErrSuspiciousUnicode, apiv0.ErrorCodeSuspiciousUnicode},
+	{ErrInvalidUTF8, apiv0.ErrorCodeInvalidUTF8},
+	{ErrPackageNameHasSpaces, apiv0.ErrorCodePackageNameHasSpaces},
+	{ErrDuplicatePackage, apiv0.ErrorCodeDuplicatePackage},
+	{ErrConflictingPackageVersions, apiv0.ErrorCodeConflictingPackageVersions},
+	{ErrInvalidOCIReference, apiv0.ErrorCodeInvalidOCIReference},
+	{ErrReleaseTagMismatch, apiv0.ErrorCodeReleaseTagMismatch},
+	{ErrInvalidRemoteURL, apiv0.ErrorCodeInvalidRemoteURL},
+	{ErrDuplicateRemoteURL, apiv0.ErrorCodeDuplicateRemoteURL},
+	{ErrInvalidAuthentication, apiv0.ErrorCodeInvalidAuthentication},
+	{ErrMalformedTemplate, apiv0.ErrorCodeMalformedTemplate},
+	{ErrUndeclaredVariable, apiv0.ErrorCodeUndeclaredVariable},
+	{ErrSecretInURL, apiv0.ErrorCodeSecretInURL},
+	{ErrUnresolvedVariable, apiv0.ErrorCodeUnresolvedURLVariable},
+	{ErrInvalidVariableName, apiv0.ErrorCodeInvalidVariableName},
+	{ErrTooManyVariables, apiv0.ErrorCodeTooManyVariables},
+	{ErrNestedVariables, apiv0.ErrorCodeNestedVariables},
+	{ErrTooManyHeaders, apiv0.ErrorCodeTooManyHeaders},
+	{ErrHeaderValueTooLong, apiv0.ErrorCodeHeaderValueTooLong},
+	{ErrSecretHeaderValue, apiv0.ErrorCodeSecretHeaderValue},
+	{ErrCredentialInHeader, apiv0.ErrorCodeCredentialInHeader},
+	{ErrForbiddenHeader, apiv0.ErrorCodeForbiddenHeader},
+	{ErrUnsupportedRegistryBaseURL, apiv0.ErrorCodeUnsupportedRegistryBaseURL},
+	{ErrMismatchedRegistryTypeAndURL, apiv0.ErrorCodeMismatchedRegistryTypeAndURL},
+	{ErrInvalidTitle, apiv0.ErrorCodeInvalidTitle},
+	{ErrTooManyIcons, apiv0.ErrorCodeTooManyIcons},
+	{ErrInvalidIconURL, apiv0.ErrorCodeInvalidIconURL},
+	{ErrUnsupportedIconMimeType, apiv0.ErrorCodeUnsupportedIconMimeType},
+	{ErrInvalidIconSize, apiv0.ErrorCodeInvalidIconSize},
+	{ErrTooManyCategories, apiv0.ErrorCodeTooManyCategories},
+	{ErrDuplicateCategory, apiv0.ErrorCodeDuplicateCategory},
+	{ErrUnknownCategory, apiv0.ErrorCodeUnknownCategory},
+	{ErrInvalidDocumentationURL, apiv0.ErrorCodeInvalidDocumentationURL},
+	{ErrInvalidWebsiteURL, apiv0.ErrorCodeInvalidWebsiteURL},
+	{ErrReadmeTooLarge, apiv0.ErrorCodeReadmeTooLarge},
+	{ErrReleaseNotesTooLarge, apiv0.ErrorCodeReleaseNotesTooLarge},
+	{ErrInvalidFilePath, apiv0.ErrorCodeInvalidFilePath},
+	{ErrInvalidLicense, apiv0.ErrorCodeInvalidLicense},
+	{ErrLicenseMismatch, apiv0.ErrorCodeLicenseMismatch},
+	{ErrInvalidContact, apiv0.ErrorCodeInvalidContact},
+	{ErrInvalidServerVersion, apiv0.ErrorCodeInvalidVersion},
+	{ErrVersionPrefix, apiv0.ErrorCodeVersionPrefix},
+	{ErrImmutableField, apiv0.ErrorCodeImmutableField},
+	{ErrInvalidForkOf, apiv0.ErrorCodeInvalidForkOf},
+	{ErrUnknownMCPVersion, apiv0.ErrorCodeUnknownMCPVersion},
+	{ErrNamedArgumentNameRequired, apiv0.ErrorCodeNamedArgumentNameRequired},
+	{ErrInvalidNamedArgumentName, apiv0.ErrorCodeInvalidNamedArgumentName},
+	{ErrArgumentValueStartsWithName, apiv0.ErrorCodeArgumentValueStartsWithName},
+	{ErrArgumentDefaultStartsWithName, apiv0.ErrorCodeArgumentDefaultStartsWithName},
+}
+
+// fieldError attributes err, if any, to the field at the JSON pointer field. Errors already
+// attributed to a field, more precisely, are returned unchanged.
+func fieldError(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var attributed *FieldError
+	if errors.As(err, &attributed) {
+		return err
+	}
+	code := apiv0.ErrorCodeInvalidValue
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			code = known.code
+			break
+		}
+	}
+	return &FieldError{Code: code, Field: field, Err: err}
+}
+
+// FieldErrors returns the FieldErrors in err, which may join several, as CheckUnknownFields does
+func FieldErrors(err error) []*FieldError {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var fieldErrs []*FieldError
+	for _, err := range errs {
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			fieldErrs = append(fieldErrs, fieldErr)
+		}
+	}
+	return fieldErrs
+}
+
+// JSONPointer builds the RFC 6901 JSON pointer to a field from its path, as in
+// JSONPointer("packages", 0, "identifier") for "/packages/0/identifier"
+func JSONPointer(path ...any) string {
+	var pointer strings.Builder
+	for _, token := range path {
+		pointer.WriteByte('/')
+		escaped := strings.ReplaceAll(fmt.Sprint(token), "~", "~0")
+		pointer.WriteString(strings.ReplaceAll(escaped, "/", "~1"))
+	}
+	return pointer.String()
+}
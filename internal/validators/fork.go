@@ -0,0 +1,64 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateForkOf checks that a server's fork reference, if any, is a well-formed server name
+// other than the server's own
+func validateForkOf(name, forkOf string) error {
+	if forkOf == "" {
+		return nil
+	}
+	if !serverNameRegex.MatchString(forkOf) {
+		return fmt.Errorf("%w: %s is not a server name in the format 'dns-namespace/name'", ErrInvalidForkOf, forkOf)
+	}
+	if forkOf == name {
+		return fmt.Errorf("%w: a server cannot be a fork of itself", ErrInvalidForkOf)
+	}
+	return nil
+}
+
+// ForkLookup returns the fork reference of the latest version of the named server, empty if it
+// has none or there is no such server, and whether the server can be forked: it exists, is
+// public and has not been deleted
+type ForkLookup func(name string) (forkOf string, available bool, err error)
+
+// ValidateForkLineage checks that the server named by forkOf can be forked and that following
+// the fork references from it never leads back to name, so forks cannot form a cycle. Deleted
+// ancestors are followed too, since an admin may restore them. Chains longer than MaxForkDepth
+// are rejected rather than followed to their end.
+func ValidateForkLineage(name, forkOf string, lookup ForkLookup) error {
+	if forkOf == "" {
+		return nil
+	}
+	if err := validateForkOf(name, forkOf); err != nil {
+		return fieldError("/forkOf", err)
+	}
+
+	chain := []string{name}
+	visited := map[string]bool{name: true}
+	current := forkOf
+	for depth := 0; current != ""; depth++ {
+		if visited[current] {
+			chain = append(chain, current)
+			return fieldError("/forkOf", fmt.Errorf("%w: fork cycle %s", ErrInvalidForkOf, strings.Join(chain, " -> ")))
+		}
+		if depth == MaxForkDepth {
+			return fieldError("/forkOf", fmt.Errorf("%w: fork chain is longer than %d servers", ErrInvalidForkOf, MaxForkDepth))
+		}
+		visited[current] = true
+		chain = append(chain, current)
+
+		next, available, err := lookup(current)
+		if err != nil {
+			return err
+		}
+		if current == forkOf && !available {
+			return fieldError("/forkOf", fmt.Errorf("%w: server %s does not exist", ErrInvalidForkOf, forkOf))
+		}
+		current = next
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// hopByHop is why hop-by-hop headers (RFC 7230 §6.1) are rejected
+const hopByHop = "is a hop-by-hop header that proxies consume, so it describes a single connection rather than the server"
+
+// forbiddenHeaders are the transport headers a server may not declare, with why. Clients apply
+// declared headers to every request, and these are managed by the HTTP stack itself.
+var forbiddenHeaders = map[string]string{
+	"Connection":         hopByHop,
+	"Keep-Alive":         hopByHop,
+	"Proxy-Authenticate": hopByHop,
+	"Proxy-Connection":   hopByHop,
+	"Te":                 hopByHop,
+	"Trailer":            hopByHop,
+	"Transfer-Encoding":  hopByHop,
+	"Upgrade":            hopByHop,
+	"Host":               "is set by the client from the remote URL",
+	"Content-Length":     "is set by the client from each request body",
+}
+
+// authorizationHeaders carry credentials that clients manage themselves
+var authorizationHeaders = []string{"Authorization", "Proxy-Authorization"}
+
+// validateHeaderNames rejects transport headers that clients must not set from server.json
+func validateHeaderNames(headers []model.KeyValueInput) error {
+	for _, header := range headers {
+		if reason, ok := forbiddenHeaders[http.CanonicalHeaderKey(header.Name)]; ok {
+			return fmt.Errorf("%w: %s %s", ErrForbiddenHeader, header.Name, reason)
+		}
+	}
+	return nil
+}
+
+// warnAuthorizationHeaders adds a warning to ctx for each Authorization or Proxy-Authorization
+// header that is not a secret the user supplies. Clients applying it would override their own
+// authentication, or send every user the same credentials.
+func warnAuthorizationHeaders(ctx context.Context, server apiv0.ServerJSON) {
+	for i, pkg := range server.Packages {
+		warnAuthorizationHeaderInputs(ctx, fmt.Sprintf("packages[%d].transport.headers", i), pkg.Transport.Headers)
+	}
+	for i, remote := range server.Remotes {
+		warnAuthorizationHeaderInputs(ctx, fmt.Sprintf("remotes[%d].headers", i), remote.Headers)
+	}
+}
+
+// warnAuthorizationHeaderInputs checks the headers at path for warnAuthorizationHeaders
+func warnAuthorizationHeaderInputs(ctx context.Context, path string, headers []model.KeyValueInput) {
+	for j, header := range headers {
+		if !slices.Contains(authorizationHeaders, http.CanonicalHeaderKey(header.Name)) {
+			continue
+		}
+		// As in validateHeaderSecrets, a template such as "Bearer {token}" is not a literal value
+		placeholders, _ := parseTemplatePlaceholders(header.Value)
+		literal := header.Default != "" || (header.Value != "" && len(placeholders) == 0)
+		if header.IsSecret && !literal {
+			continue
+		}
+		Warn(ctx, apiv0.Warning{
+			Code: apiv0.WarningAuthorizationHeader,
+			Path: fmt.Sprintf("%s[%d]", path, j),
+			Message: fmt.Sprintf("header %s overrides the client's own authentication unless the user supplies it; mark it is_secret without a literal value or default",
+				header.Name),
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/validators/registries"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ParseLicenseExpression parses an SPDX license expression such as "MIT OR Apache-2.0" or
+// "(GPL-2.0-only WITH Classpath-exception-2.0) AND BSD-3-Clause", and returns the license
+// identifiers it references in order, without "+" suffixes and exceptions. Identifiers are not
+// checked against the SPDX license list, so new licenses and LicenseRef- identifiers are
+// accepted; the operators AND, OR and WITH must be upper case.
+func ParseLicenseExpression(expression string) ([]string, error) {
+	tokens, err := tokenizeLicense(expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+
+	p := &licenseParser{tokens: tokens}
+	if err := p.parseOr(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return p.ids, nil
+}
+
+// tokenizeLicense splits an expression into parentheses and words
+func tokenizeLicense(expression string) ([]string, error) {
+	var tokens []string
+	start := -1
+	for i, r := range expression {
+		isSeparator := r == ' ' || r == '\t' || r == '(' || r == ')'
+		if isSeparator && start >= 0 {
+			tokens = append(tokens, expression[start:i])
+			start = -1
+		}
+		switch {
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+		case isSeparator:
+		case isLicenseIDChar(r) || r == '+' || r == ':':
+			if start < 0 {
+				start = i
+			}
+		default:
+			return nil, fmt.Errorf("invalid character %q", r)
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, expression[start:])
+	}
+	return tokens, nil
+}
+
+// licenseParser is a recursive descent parser for the SPDX expression grammar:
+//
+//	or-expression   = and-expression *("OR" and-expression)
+//	and-expression  = with-expression *("AND" with-expression)
+//	with-expression = simple-expression ["WITH" exception-id] | "(" or-expression ")"
+//
+// AND binds tighter than OR, and WITH only applies to a single license.
+type licenseParser struct {
+	tokens []string
+	pos    int
+	ids    []string
+}
+
+func (p *licenseParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *licenseParser) parseOr() error {
+	if err := p.parseAnd(); err != nil {
+		return err
+	}
+	for p.peek() == "OR" {
+		p.pos++
+		if err := p.parseAnd(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *licenseParser) parseAnd() error {
+	if err := p.parseWith(); err != nil {
+		return err
+	}
+	for p.peek() == "AND" {
+		p.pos++
+		if err := p.parseWith(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *licenseParser) parseWith() error {
+	token := p.peek()
+	switch token {
+	case "":
+		return fmt.Errorf("expression ends where a license was expected")
+	case "(":
+		p.pos++
+		if err := p.parseOr(); err != nil {
+			return err
+		}
+		if p.peek() != ")" {
+			return fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return nil
+	case ")", "AND", "OR", "WITH":
+		return fmt.Errorf("unexpected %q where a license was expected", token)
+	}
+
+	id, err := parseSimpleLicense(token)
+	if err != nil {
+		return err
+	}
+	p.ids = append(p.ids, id)
+	p.pos++
+
+	if p.peek() == "WITH" {
+		p.pos++
+		exception := p.peek()
+		if !isLicenseIDString(exception) || isLicenseOperator(exception) {
+			return fmt.Errorf("WITH must be followed by a license exception identifier")
+		}
+		p.pos++
+	}
+	return nil
+}
+
+// parseSimpleLicense checks a license identifier, an identifier with a "+" (or later version)
+// suffix, or a LicenseRef, and returns the identifier without the suffix
+func parseSimpleLicense(token string) (string, error) {
+	if document, ref, ok := strings.Cut(token, ":"); ok {
+		if !hasIDStringAfter(document, "DocumentRef-") || !hasIDStringAfter(ref, "LicenseRef-") {
+			return "", fmt.Errorf("invalid license reference %q (expected DocumentRef-<id>:LicenseRef-<id>)", token)
+		}
+		return token, nil
+	}
+	if strings.HasPrefix(token, "LicenseRef-") {
+		if !hasIDStringAfter(token, "LicenseRef-") {
+			return "", fmt.Errorf("invalid license reference %q", token)
+		}
+		return token, nil
+	}
+
+	id := strings.TrimSuffix(token, "+")
+	if !isLicenseIDString(id) {
+		return "", fmt.Errorf("invalid license identifier %q", token)
+	}
+	return id, nil
+}
+
+func hasIDStringAfter(s, prefix string) bool {
+	return strings.HasPrefix(s, prefix) && isLicenseIDString(strings.TrimPrefix(s, prefix))
+}
+
+// isLicenseIDString reports whether s is a non-empty SPDX idstring: letters, digits, "." and "-"
+func isLicenseIDString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isLicenseIDChar(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLicenseIDChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-'
+}
+
+func isLicenseOperator(token string) bool {
+	return token == "AND" || token == "OR" || token == "WITH"
+}
+
+// validateLicense checks that an optional license is a well-formed SPDX expression
+func validateLicense(license string) error {
+	if license == "" {
+		return nil
+	}
+	if len(license) > Rules.MaxLicenseLength {
+		return fmt.Errorf("%w: %d characters, at most %d allowed", ErrInvalidLicense, len(license), Rules.MaxLicenseLength)
+	}
+	if _, err := ParseLicenseExpression(license); err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrInvalidLicense, license, err)
+	}
+	return nil
+}
+
+// validatePackageLicenses checks the server's license against the licenses its packages'
+// registries declare. A package conflicts when it declares a license the server's expression
+// doesn't mention; packages without a declared license, or with one that isn't an SPDX
+// expression, are not checked.
+func validatePackageLicenses(ctx context.Context, req apiv0.ServerJSON) error {
+	if req.License == "" {
+		return nil
+	}
+	ids, err := ParseLicenseExpression(req.License)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrInvalidLicense, req.License, err)
+	}
+	mentioned := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		mentioned[strings.ToLower(id)] = true
+	}
+
+	for _, pkg := range req.Packages {
+		declared, err := registries.DeclaredLicense(ctx, pkg)
+		if err != nil {
+			log.Printf("Warning: could not read the declared license of package %s: %v", pkg.Identifier, err)
+			continue
+		}
+		declaredIDs, err := ParseLicenseExpression(declared)
+		if err != nil {
+			continue
+		}
+		for _, id := range declaredIDs {
+			if !mentioned[strings.ToLower(id)] {
+				return fmt.Errorf("%w: package %s declares %q, but the server's license is %q", ErrLicenseMismatch, pkg.Identifier, declared, req.License)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// validateMCPVersion checks that a server's declared protocol revision, if any, is one of
+// Rules.MCPVersions. Revisions are dates, so a newer revision compares greater as a string.
+func validateMCPVersion(mcpVersion string) error {
+	if mcpVersion == "" || slices.Contains(Rules.MCPVersions, mcpVersion) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q, expected one of %s", ErrUnknownMCPVersion, mcpVersion, strings.Join(Rules.MCPVersions, ", "))
+}
@@ -0,0 +1,177 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// disguisingFields are the fields invisible characters are stripped from, since they are
+// shown to users to identify a server
+var disguisingFields = map[string]bool{"name": true, "title": true, "description": true}
+
+// isInvisibleControl reports whether r is a zero-width or bidirectional control character,
+// which render as nothing but can reorder or disguise the text around them
+func isInvisibleControl(r rune) bool {
+	switch {
+	case r >= 0x200B && r <= 0x200F: // zero-width space, joiners and directional marks
+		return true
+	case r >= 0x202A && r <= 0x202E: // bidi embeddings and overrides
+		return true
+	case r >= 0x2066 && r <= 0x2069: // bidi isolates
+		return true
+	case r == 0x2060 || r == 0xFEFF || r == 0x061C: // word joiner, zero-width no-break space, Arabic letter mark
+		return true
+	}
+	return false
+}
+
+// normalizeString rewrites s in NFC, removing invisible control characters if strip is set
+func normalizeString(s string, strip bool) string {
+	if strip {
+		s = strings.Map(func(r rune) rune {
+			if isInvisibleControl(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	return norm.NFC.String(s)
+}
+
+// SanitizeText cleans free text submitted outside server.json, such as server reports: it
+// drops invalid UTF-8, control characters other than newlines and tabs, and zero-width and
+// bidirectional controls, then trims the result and rewrites it in NFC
+func SanitizeText(s string) string {
+	s = strings.ToValidUTF8(s, "")
+	s = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || isInvisibleControl(r) || (unicode.IsControl(r) && r != '\n' && r != '\t') {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(norm.NFC.String(s))
+}
+
+// NormalizeServerJSON checks that every string in server is valid UTF-8 and rewrites it in
+// NFC. Zero-width and bidirectional control characters are removed from names, titles and
+// descriptions. Text containing U+FFFD is rejected too: it is what JSON decoding leaves of
+// invalid UTF-8 and unpaired surrogates. Errors name the offending field, such as
+// packages[0].environment_variables[1].description.
+func NormalizeServerJSON(server *apiv0.ServerJSON) error {
+	return walkStrings(reflect.ValueOf(server).Elem(), "", "", func(path, field, s string) (string, error) {
+		if !utf8.ValidString(s) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidUTF8, path)
+		}
+		if strings.ContainsRune(s, utf8.RuneError) {
+			return "", fmt.Errorf("%w: %s contains U+FFFD, left by text that was not valid UTF-8", ErrInvalidUTF8, path)
+		}
+		return normalizeString(s, disguisingFields[field]), nil
+	})
+}
+
+// RepairServerJSON normalizes server like NormalizeServerJSON, but drops invalid UTF-8 and
+// U+FFFD instead of rejecting them. It returns the paths of the fields it changed.
+func RepairServerJSON(server *apiv0.ServerJSON) []string {
+	var changed []string
+	_ = walkStrings(reflect.ValueOf(server).Elem(), "", "", func(path, field, s string) (string, error) {
+		repaired := strings.ReplaceAll(strings.ToValidUTF8(s, ""), string(utf8.RuneError), "")
+		repaired = normalizeString(repaired, disguisingFields[field])
+		if repaired != s {
+			changed = append(changed, path)
+		}
+		return repaired, nil
+	})
+	return changed
+}
+
+// walkStrings calls fn for every string reachable from v, replacing it with the result. path
+// is v's position in the JSON document and field the JSON name of the struct field holding it.
+// Map keys are checked but never rewritten, since that could merge two entries.
+func walkStrings(v reflect.Value, path, field string, fn func(path, field, s string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.String:
+		s, err := fn(path, field, v.String())
+		if err != nil {
+			return err
+		}
+		if s != v.String() {
+			v.SetString(s)
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return walkStrings(v.Elem(), path, field, fn)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		// Interface values can't be changed in place, so walk a copy and store it back
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := walkStrings(elem, path, field, fn); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := walkStrings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), field, fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for _, key := range v.MapKeys() {
+			keyPath := joinPath(path, key.String())
+			if _, err := fn(keyPath, "", key.String()); err != nil {
+				return err
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			if err := walkStrings(elem, keyPath, key.String(), fn); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			structField := v.Type().Field(i)
+			if !structField.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			fieldPath := path
+			if !structField.Anonymous || name != "" {
+				if name == "" {
+					name = structField.Name
+				}
+				fieldPath = joinPath(path, name)
+			} else {
+				// Embedded structs are inlined into their parent
+				name = field
+			}
+			if err := walkStrings(v.Field(i), fieldPath, name, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
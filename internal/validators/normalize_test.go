@@ -0,0 +1,154 @@
+package validators_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// textServer returns a server with the given package environment variable description
+func textServer(description, envDescription string) apiv0.ServerJSON {
+	return apiv0.ServerJSON{
+		Name:        "io.github.example/weather",
+		Description: description,
+		Version:     "1.0.0",
+		Packages: []model.Package{{
+			RegistryType: model.RegistryTypeNPM,
+			Identifier:   "@example/weather",
+			Version:      "1.0.0",
+			Transport:    model.Transport{Type: model.TransportTypeStdio},
+			EnvironmentVariables: []model.KeyValueInput{
+				{Name: "API_KEY", InputWithVariables: model.InputWithVariables{Input: model.Input{Description: envDescription}}},
+			},
+		}},
+	}
+}
+
+func TestNormalizeServerJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		description    string
+		envDescription string
+		expectedDesc   string
+		expectedEnv    string
+		expectedError  string
+	}{
+		{
+			name:           "plain text is unchanged",
+			description:    "Weather lookups",
+			envDescription: "Key for the weather service",
+			expectedDesc:   "Weather lookups",
+			expectedEnv:    "Key for the weather service",
+		},
+		{
+			name:           "decomposed text is composed",
+			description:    "Cafe\u0301 finder",
+			envDescription: "Cle\u0301",
+			expectedDesc:   "Caf\u00e9 finder",
+			expectedEnv:    "Cl\u00e9",
+		},
+		{
+			name:           "zero-width and bidi controls are stripped from descriptions",
+			description:    "Weather\u200b look\u202eups\u2066\u2069\ufeff",
+			envDescription: "Key\u200d",
+			expectedDesc:   "Weather lookups",
+			expectedEnv:    "Key",
+		},
+		{
+			name:           "invalid byte sequence",
+			description:    "Weather lookups",
+			envDescription: "Key \xff\xfe for the service",
+			expectedError:  "packages[0].environment_variables[0].description",
+		},
+		{
+			name:          "truncated multi-byte sequence",
+			description:   "Weather \xe2\x82",
+			expectedError: "description",
+		},
+		{
+			name:          "encoded surrogate half",
+			description:   "Weather \xed\xa0\x80",
+			expectedError: "description",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := textServer(tt.description, tt.envDescription)
+			err := validators.NormalizeServerJSON(&server)
+			if tt.expectedError != "" {
+				require.ErrorIs(t, err, validators.ErrInvalidUTF8)
+				assert.Contains(t, err.Error(), ": "+tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDesc, server.Description)
+			assert.Equal(t, tt.expectedEnv, server.Packages[0].EnvironmentVariables[0].Description)
+		})
+	}
+}
+
+func TestNormalizeServerJSON_UnpairedSurrogateFromJSON(t *testing.T) {
+	// Tools that build JSON from UTF-16 strings can emit half of a surrogate pair, which
+	// decoding replaces with U+FFFD
+	var server apiv0.ServerJSON
+	require.NoError(t, json.Unmarshal([]byte(`{"name": "io.github.example/weather", "description": "Weather \ud83c lookups", "version": "1.0.0"}`), &server))
+
+	err := validators.NormalizeServerJSON(&server)
+	require.ErrorIs(t, err, validators.ErrInvalidUTF8)
+	assert.Contains(t, err.Error(), ": description contains U+FFFD")
+}
+
+func TestNormalizeServerJSON_OnlyDisguisingFieldsAreStripped(t *testing.T) {
+	// Joiners are part of some emoji and scripts, so values users send to servers keep them
+	server := textServer("Weather", "Key")
+	server.Packages[0].EnvironmentVariables[0].Default = "a\u200db"
+	server.Packages[0].EnvironmentVariables[0].Variables = map[string]model.Input{
+		"tag": {Description: "Ta\u200bg", Default: "e\u0301"},
+	}
+
+	require.NoError(t, validators.NormalizeServerJSON(&server))
+	env := server.Packages[0].EnvironmentVariables[0]
+	assert.Equal(t, "a\u200db", env.Default)
+	assert.Equal(t, "Tag", env.Variables["tag"].Description)
+	assert.Equal(t, "\u00e9", env.Variables["tag"].Default)
+}
+
+func TestRepairServerJSON(t *testing.T) {
+	server := textServer("Weather \xff lookups\u200b", "Cle\u0301")
+	server.Title = "Weather"
+
+	changed := validators.RepairServerJSON(&server)
+	assert.ElementsMatch(t, []string{"description", "packages[0].environment_variables[0].description"}, changed)
+	assert.Equal(t, "Weather  lookups", server.Description)
+	assert.Equal(t, "Cl\u00e9", server.Packages[0].EnvironmentVariables[0].Description)
+	require.NoError(t, validators.NormalizeServerJSON(&server), "repaired text must pass normalization")
+
+	assert.Empty(t, validators.RepairServerJSON(&server), "repairing twice changes nothing")
+}
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "plain text", text: "The remote is down", expected: "The remote is down"},
+		{name: "newlines and tabs are kept", text: "line one\n\tline two", expected: "line one\n\tline two"},
+		{name: "terminal escapes lose their control character", text: "red\x1b[31m text", expected: "red[31m text"},
+		{name: "invisible controls are removed", text: "mal\u200bware \u202egnp.exe", expected: "malware gnp.exe"},
+		{name: "invalid UTF-8 is removed", text: "bad \xff bytes", expected: "bad  bytes"},
+		{name: "text is trimmed and composed", text: "  Cle\u0301 \r\n", expected: "Cl\u00e9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validators.SanitizeText(tt.text))
+		})
+	}
+}
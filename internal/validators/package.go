@@ -3,8 +3,11 @@ package validators
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/modelcontextprotocol/registry/internal/validators/registries"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
@@ -12,6 +15,9 @@ import (
 // 1. allowed on the official registry (based on registry base url); and
 // 2. owned by the publisher, by checking for a matching server name in the package metadata
 func ValidatePackage(ctx context.Context, pkg model.Package, serverName string) error {
+	if !slices.Contains(Rules.RegistryTypes, pkg.RegistryType) {
+		return fmt.Errorf("unsupported registry type: %s", pkg.RegistryType)
+	}
 	switch pkg.RegistryType {
 	case model.RegistryTypeNPM:
 		return registries.ValidateNPM(ctx, pkg, serverName)
@@ -27,3 +33,56 @@ func ValidatePackage(ctx context.Context, pkg model.Package, serverName string)
 		return fmt.Errorf("unsupported registry type: %s", pkg.RegistryType)
 	}
 }
+
+// warnNumericURLVariables adds a warning to ctx for each port-like placeholder in a package
+// transport URL whose variable is not declared with format number, so clients can check the value
+func warnNumericURLVariables(ctx context.Context, server apiv0.ServerJSON) {
+	for i, pkg := range server.Packages {
+		placeholders, err := parseTemplatePlaceholders(pkg.Transport.URL)
+		if err != nil {
+			continue
+		}
+		inputs := collectVariableInputs(&pkg)
+		for _, placeholder := range placeholders {
+			name := placeholderName(placeholder)
+			if !isPortLikeName(name) {
+				continue
+			}
+			numeric := slices.ContainsFunc(inputs[name], func(input model.Input) bool { return input.Format == model.FormatNumber })
+			if !numeric {
+				Warn(ctx, apiv0.Warning{
+					Code:    apiv0.WarningNumericVariableFormat,
+					Path:    fmt.Sprintf("packages[%d].transport.url", i),
+					Message: fmt.Sprintf("placeholder %s looks like a number; declare its variable with format number", placeholder),
+				})
+			}
+		}
+	}
+}
+
+// isPortLikeName reports whether a variable name is port or ends with a port word, as in
+// http_port, HTTP-PORT or serverPort, but not report or transport
+func isPortLikeName(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "port" || strings.HasSuffix(lower, "_port") || strings.HasSuffix(lower, "-port") ||
+		strings.HasSuffix(name, "Port")
+}
+
+// warnMutableImageTags adds a warning to ctx for each OCI package referenced only by the latest
+// tag, which can point at a different image each time a client pulls it
+func warnMutableImageTags(ctx context.Context, server apiv0.ServerJSON) {
+	for i, pkg := range server.Packages {
+		if pkg.RegistryType != model.RegistryTypeOCI {
+			continue
+		}
+		ref, err := registries.ParseOCIReference(pkg.Identifier, pkg.Version)
+		if err != nil || !ref.MutableTagOnly() {
+			continue
+		}
+		Warn(ctx, apiv0.Warning{
+			Code:    apiv0.WarningMutableImageTag,
+			Path:    fmt.Sprintf("packages[%d].version", i),
+			Message: fmt.Sprintf("image %s is referenced by the mutable latest tag; publish a versioned tag, or pin it with tag@sha256:<digest>", ref),
+		})
+	}
+}
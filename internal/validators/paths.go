@@ -0,0 +1,135 @@
+package validators
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// subfolderPathRegex lists the characters allowed in a repository subfolder
+var subfolderPathRegex = regexp.MustCompile(`^[a-zA-Z0-9\-_./]+$`)
+
+// validatePath checks a path shipped in server.json. It must not climb out of its base
+// directory with ".." or contain empty segments, and must be relative unless allowAbsolute
+// is set. Both / and \ separate segments, and paths starting with a drive letter or ~ count
+// as absolute, since the path is used on the client's machine.
+func validatePath(path string, allowAbsolute bool) error {
+	rest, absolute := trimAbsolutePrefix(path)
+	if absolute && !allowAbsolute {
+		return ErrAbsolutePath
+	}
+
+	segments := strings.FieldsFunc(rest, isPathSeparator)
+	if slices.Contains(segments, "..") {
+		return ErrPathTraversal
+	}
+	// FieldsFunc drops empty segments, so count separators to find them
+	if rest != "" && len(segments) != strings.Count(rest, "/")+strings.Count(rest, `\`)+1 {
+		return ErrEmptyPathSegment
+	}
+	return nil
+}
+
+// trimAbsolutePrefix removes the root of an absolute path (/, \, C:\ or ~/), reporting whether it had one
+func trimAbsolutePrefix(path string) (string, bool) {
+	switch {
+	case strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`):
+		return path[2:], true
+	case path == "~":
+		return "", true
+	case len(path) >= 2 && path[1] == ':' && isDriveLetter(path[0]):
+		return strings.TrimLeft(path[2:], `/\`), true
+	case strings.HasPrefix(path, "/") || strings.HasPrefix(path, `\`):
+		return strings.TrimLeft(path, `/\`), true
+	default:
+		return path, false
+	}
+}
+
+func isPathSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
+
+func isDriveLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// validateSubfolderPath checks a repository subfolder: a relative path of plain segments
+func validateSubfolderPath(path string) error {
+	if err := validatePath(path, false); err != nil {
+		return err
+	}
+	if !subfolderPathRegex.MatchString(path) {
+		return fmt.Errorf("must only contain letters, digits, '-', '_', '.' and '/'")
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." {
+			return fmt.Errorf(`must not contain "." segments`)
+		}
+	}
+	return nil
+}
+
+// validatePackagePaths checks the paths in a package's arguments and environment variables.
+// Inputs with the file_path format must hold valid paths, absolute only when the input sets
+// allow_absolute. Other values are free-form, but must not embed paths that climb out of the
+// working directory. field is the package's position in server.json, used in errors. The
+// variables of inputs are checked the same way by validateVariables.
+func validatePackagePaths(field string, pkg *model.Package) error {
+	for i, arg := range pkg.RuntimeArguments {
+		if err := validateInputPaths(fmt.Sprintf("%s.runtime_arguments[%d]", field, i), &arg.Input); err != nil {
+			return err
+		}
+	}
+	for i, arg := range pkg.PackageArguments {
+		if err := validateInputPaths(fmt.Sprintf("%s.package_arguments[%d]", field, i), &arg.Input); err != nil {
+			return err
+		}
+	}
+	for i, env := range pkg.EnvironmentVariables {
+		if err := validateInputPaths(fmt.Sprintf("%s.environment_variables[%d]", field, i), &env.Input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateInputPaths checks an input's value and default
+func validateInputPaths(field string, input *model.Input) error {
+	for _, value := range []struct{ name, path string }{{"value", input.Value}, {"default", input.Default}} {
+		if value.path == "" {
+			continue
+		}
+		var err error
+		if isFilePathFormat(input.Format) {
+			err = validatePath(value.path, input.AllowAbsolute)
+		} else if containsPathTraversal(value.path) {
+			err = ErrPathTraversal
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s.%s %w: %s", ErrInvalidFilePath, field, value.name, err, value.path)
+		}
+	}
+	return nil
+}
+
+// isFilePathFormat reports whether an input holds a file path. The schema spells the format
+// "filepath", and the model "file_path"; both are accepted.
+func isFilePathFormat(format model.Format) bool {
+	return format == model.FormatFilePath || format == "filepath"
+}
+
+// containsPathTraversal reports whether a free-form value embeds a path with a ".." segment,
+// such as "../../etc/config" or "--config=../secrets". A lone ".." is not treated as a path.
+func containsPathTraversal(value string) bool {
+	if !strings.ContainsAny(value, `/\`) {
+		return false
+	}
+	segments := strings.FieldsFunc(value, func(r rune) bool {
+		return isPathSeparator(r) || r == '=' || r == ' '
+	})
+	return slices.Contains(segments, "..")
+}
@@ -0,0 +1,103 @@
+package registries
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// DeclaredLicense returns the license a package's registry declares for the published version,
+// or "" if the registry has no license metadata for it. Only npm and PyPI declare licenses;
+// other registry types always return "". The value is returned as the registry reports it and
+// is not necessarily an SPDX expression.
+func DeclaredLicense(ctx context.Context, pkg model.Package) (string, error) {
+	switch pkg.RegistryType {
+	case model.RegistryTypeNPM:
+		return declaredNPMLicense(ctx, pkg)
+	case model.RegistryTypePyPI:
+		return declaredPyPILicense(ctx, pkg)
+	default:
+		return "", nil
+	}
+}
+
+// declaredNPMLicense reads the "license" field of an npm version document. Old packages may
+// declare it as an object with a "type" instead of a string.
+func declaredNPMLicense(ctx context.Context, pkg model.Package) (string, error) {
+	if pkg.RegistryBaseURL == "" {
+		pkg.RegistryBaseURL = model.RegistryURLNPM
+	}
+
+	var version struct {
+		License json.RawMessage `json:"license"`
+	}
+	if err := fetchLicenseJSON(ctx, pkg.RegistryBaseURL+"/"+pkg.Identifier+"/"+pkg.Version, &version); err != nil {
+		return "", err
+	}
+	if len(version.License) == 0 {
+		return "", nil
+	}
+
+	var license string
+	if err := json.Unmarshal(version.License, &license); err == nil {
+		return license, nil
+	}
+	var legacy struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(version.License, &legacy); err == nil {
+		return legacy.Type, nil
+	}
+	return "", nil
+}
+
+// declaredPyPILicense reads the core metadata License-Expression of a PyPI release, falling back
+// to the older free-form License field
+func declaredPyPILicense(ctx context.Context, pkg model.Package) (string, error) {
+	if pkg.RegistryBaseURL == "" {
+		pkg.RegistryBaseURL = model.RegistryURLPyPI
+	}
+
+	var release struct {
+		Info struct {
+			License           string `json:"license"`
+			LicenseExpression string `json:"license_expression"`
+		} `json:"info"`
+	}
+	if err := fetchLicenseJSON(ctx, pkg.RegistryBaseURL+"/pypi/"+pkg.Identifier+"/"+pkg.Version+"/json", &release); err != nil {
+		return "", err
+	}
+	if release.Info.LicenseExpression != "" {
+		return release.Info.LicenseExpression, nil
+	}
+	return release.Info.License, nil
+}
+
+func fetchLicenseJSON(ctx context.Context, url string, v any) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "MCP-Registry-Validator/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch license metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch license metadata (status: %d)", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse license metadata: %w", err)
+	}
+	return nil
+}
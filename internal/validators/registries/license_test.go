@@ -0,0 +1,68 @@
+package registries_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/validators/registries"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeclaredLicense(t *testing.T) {
+	documents := map[string]string{
+		"/string/1.0.0":               `{"license": "MIT"}`,
+		"/legacy/1.0.0":               `{"license": {"type": "ISC", "url": "https://opensource.org/licenses/ISC"}}`,
+		"/unlicensed/1.0.0":           `{"name": "unlicensed"}`,
+		"/pypi/expression/1.0.0/json": `{"info": {"license_expression": "MIT OR Apache-2.0", "license": "ignored"}}`,
+		"/pypi/classifier/1.0.0/json": `{"info": {"license": "BSD-3-Clause", "license_expression": null}}`,
+		"/pypi/unlicensed/1.0.0/json": `{"info": {}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		document, ok := documents[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(document))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		registryType string
+		identifier   string
+		want         string
+		wantErr      bool
+	}{
+		{name: "npm string", registryType: model.RegistryTypeNPM, identifier: "string", want: "MIT"},
+		{name: "npm legacy object", registryType: model.RegistryTypeNPM, identifier: "legacy", want: "ISC"},
+		{name: "npm without license", registryType: model.RegistryTypeNPM, identifier: "unlicensed", want: ""},
+		{name: "npm missing version", registryType: model.RegistryTypeNPM, identifier: "missing", wantErr: true},
+		{name: "pypi license expression", registryType: model.RegistryTypePyPI, identifier: "expression", want: "MIT OR Apache-2.0"},
+		{name: "pypi license field", registryType: model.RegistryTypePyPI, identifier: "classifier", want: "BSD-3-Clause"},
+		{name: "pypi without license", registryType: model.RegistryTypePyPI, identifier: "unlicensed", want: ""},
+		{name: "registry without license metadata", registryType: model.RegistryTypeOCI, identifier: "example/image", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := model.Package{
+				RegistryType:    tt.registryType,
+				RegistryBaseURL: server.URL,
+				Identifier:      tt.identifier,
+				Version:         "1.0.0",
+			}
+			license, err := registries.DeclaredLicense(context.Background(), pkg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, license)
+		})
+	}
+}
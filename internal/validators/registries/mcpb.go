@@ -115,17 +115,21 @@ func validateMCPBUrl(fullURL string) error {
 	return nil
 }
 
+// gitHubReleasePattern matches GitHub release asset paths: /owner/repo/releases/download/tag/filename
+//   - owner: username or organization (1-39 chars, alphanumeric + hyphens, no consecutive hyphens)
+//   - repo: repository name (similar rules to owner)
+//   - tag: release tag (can contain various characters but not empty)
+//   - filename: asset filename (not empty)
+var gitHubReleasePattern = regexp.MustCompile(`^/([a-zA-Z0-9]([a-zA-Z0-9\-]{0,37}[a-zA-Z0-9])?)/([a-zA-Z0-9._\-]+)/releases/download/([^/]+)/([^/]+)$`)
+
+// gitLabReleasePattern matches GitLab release asset paths: /owner/repo/-/releases/tag/downloads/filename,
+// where the project path may include nested groups
+var gitLabReleasePattern = regexp.MustCompile(`^/([a-zA-Z0-9._\-]+(?:/[a-zA-Z0-9._\-]+)*)/-/releases/([^/]+)/downloads/([^/]+)$`)
+
 // isValidGitHubReleaseURL validates that a path follows the GitHub release asset pattern
 // Pattern: /owner/repo/releases/download/tag/filename
 func isValidGitHubReleaseURL(path string) bool {
-	// GitHub release URL pattern: /owner/repo/releases/download/tag/filename
-	// - owner: username or organization (1-39 chars, alphanumeric + hyphens, no consecutive hyphens)
-	// - repo: repository name (similar rules to owner)
-	// - tag: release tag (can contain various characters but not empty)
-	// - filename: asset filename (not empty)
-	pattern := `^/([a-zA-Z0-9]([a-zA-Z0-9\-]{0,37}[a-zA-Z0-9])?)/([a-zA-Z0-9._\-]+)/releases/download/([^/]+)/([^/]+)$`
-	matched, _ := regexp.MatchString(pattern, path)
-	return matched
+	return gitHubReleasePattern.MatchString(path)
 }
 
 // isValidGitLabReleaseURL validates that a path follows GitLab release asset patterns
@@ -139,8 +143,7 @@ func isValidGitLabReleaseURL(path string) bool {
 	// project path from the GitLab-specific routes. Everything before "/-/" is the project path.
 
 	// Pattern 1: Release downloads with /-/releases/tag/downloads/filename
-	releasePattern := `^/([a-zA-Z0-9._\-]+(?:/[a-zA-Z0-9._\-]+)*)/-/releases/([^/]+)/downloads/([^/]+)$`
-	if matched, _ := regexp.MatchString(releasePattern, path); matched {
+	if gitLabReleasePattern.MatchString(path) {
 		return true
 	}
 
@@ -170,3 +173,51 @@ func inferMCPBRegistryBaseURL(identifier string) (string, error) {
 		return "", fmt.Errorf("invalid host for MCPB package: %s, expected github or gitlab", host)
 	}
 }
+
+// MCPBReleaseTag returns the release tag named in a GitHub or GitLab release asset URL. It
+// returns false for URLs without one, such as GitLab package files.
+func MCPBReleaseTag(identifier string) (string, bool) {
+	parsedURL, err := url.Parse(identifier)
+	if err != nil {
+		return "", false
+	}
+
+	var match []string
+	switch strings.ToLower(parsedURL.Host) {
+	case "github.com", "www.github.com":
+		if match = gitHubReleasePattern.FindStringSubmatch(parsedURL.Path); match != nil {
+			return match[4], true
+		}
+	case "gitlab.com", "www.gitlab.com":
+		if match = gitLabReleasePattern.FindStringSubmatch(parsedURL.Path); match != nil {
+			return match[2], true
+		}
+	}
+	return "", false
+}
+
+// ValidateMCPBReleaseTag checks that the release tag in an MCPB package URL names the package
+// version, so the registry does not list a version whose download is a different release. A
+// leading "v" and build metadata are ignored on both sides. Packages that set
+// unversioned_release_tag, and URLs without a tag, are not checked.
+func ValidateMCPBReleaseTag(pkg model.Package) error {
+	if pkg.RegistryType != model.RegistryTypeMCPB || pkg.UnversionedReleaseTag || pkg.Version == "" {
+		return nil
+	}
+	tag, ok := MCPBReleaseTag(pkg.Identifier)
+	if !ok {
+		return nil
+	}
+	if comparableReleaseVersion(tag) != comparableReleaseVersion(pkg.Version) {
+		return fmt.Errorf("the URL is tagged %q but the package version is %q; update one to match, "+
+			"or set unversioned_release_tag if the project's release tags are not versions", tag, pkg.Version)
+	}
+	return nil
+}
+
+// comparableReleaseVersion strips a leading "v" and any build metadata from a tag or version
+func comparableReleaseVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	version, _, _ = strings.Cut(version, "+")
+	return version
+}
@@ -95,3 +95,84 @@ func TestValidateMCPB(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMCPBReleaseTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		identifier  string
+		version     string
+		unversioned bool
+		wantErr     string
+	}{
+		{
+			name:       "GitHub tag matching the version",
+			identifier: "https://github.com/example/server/releases/download/1.8.0/server.mcpb",
+			version:    "1.8.0",
+		},
+		{
+			name:       "GitHub v-prefixed tag",
+			identifier: "https://github.com/example/server/releases/download/v1.8.0/server.mcpb",
+			version:    "1.8.0",
+		},
+		{
+			name:       "build metadata is ignored",
+			identifier: "https://github.com/example/server/releases/download/v1.8.0+build.5/server.mcpb",
+			version:    "1.8.0",
+		},
+		{
+			name:       "GitHub tag of another release",
+			identifier: "https://github.com/example/server/releases/download/v1.7.2/server.mcpb",
+			version:    "1.8.0",
+			wantErr:    `the URL is tagged "v1.7.2" but the package version is "1.8.0"`,
+		},
+		{
+			name:       "GitLab tag matching the version",
+			identifier: "https://gitlab.com/group/subgroup/server/-/releases/2.0.0/downloads/server.mcpb",
+			version:    "2.0.0",
+		},
+		{
+			name:       "GitLab v-prefixed tag",
+			identifier: "https://gitlab.com/example/server/-/releases/v2.0.0/downloads/server.mcpb",
+			version:    "2.0.0",
+		},
+		{
+			name:       "GitLab tag of another release",
+			identifier: "https://gitlab.com/example/server/-/releases/v2.0.0/downloads/server.mcpb",
+			version:    "2.1.0",
+			wantErr:    `the URL is tagged "v2.0.0" but the package version is "2.1.0"`,
+		},
+		{
+			name:       "prerelease suffixes must match",
+			identifier: "https://github.com/example/server/releases/download/v1.8.0/server.mcpb",
+			version:    "1.8.0-rc.1",
+			wantErr:    `the URL is tagged "v1.8.0" but the package version is "1.8.0-rc.1"`,
+		},
+		{
+			name:        "opted out for tags that are not versions",
+			identifier:  "https://github.com/example/server/releases/download/nightly/server.mcpb",
+			version:     "1.8.0",
+			unversioned: true,
+		},
+		{
+			name:       "GitLab package files have no tag",
+			identifier: "https://gitlab.com/example/server/-/package_files/123/download",
+			version:    "1.8.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registries.ValidateMCPBReleaseTag(model.Package{
+				RegistryType:          model.RegistryTypeMCPB,
+				Identifier:            tt.identifier,
+				Version:               tt.version,
+				UnversionedReleaseTag: tt.unversioned,
+			})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,11 +18,34 @@ import (
 
 const (
 	dockerIoAPIBaseURL = "https://registry-1.docker.io"
+	// ociMaxNameLength bounds a repository name, including its registry host
+	ociMaxNameLength = 255
+	// ociDefaultTag is the tag clients pull when a reference names neither a tag nor a digest
+	ociDefaultTag = "latest"
 )
 
-// OCIAuthResponse represents the Docker Hub authentication response
+// The distribution reference grammar, https://github.com/distribution/reference/blob/main/regexp.go
+var (
+	ociDomainRegex        = regexp.MustCompile(`^(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?$`)
+	ociPathComponentRegex = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	ociTagRegex           = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+	// Only the registered algorithms are accepted, with their exact lengths
+	ociDigestRegex = regexp.MustCompile(`^(?:sha256:[a-f0-9]{64}|sha512:[a-f0-9]{128})$`)
+	// ociChallengeParamRegex matches the key="value" parameters of a WWW-Authenticate challenge
+	ociChallengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// ociRegistries maps each supported OCI registry base URL to the hosts that name it in image references
+var ociRegistries = map[string][]string{
+	model.RegistryURLDocker: {"docker.io", "index.docker.io", "registry-1.docker.io"},
+	model.RegistryURLGHCR:   {"ghcr.io"},
+	model.RegistryURLQuay:   {"quay.io"},
+}
+
+// OCIAuthResponse represents a registry token service response
 type OCIAuthResponse struct {
-	Token string `json:"token"`
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
 }
 
 // OCIManifest represents an OCI image manifest
@@ -38,66 +65,191 @@ type OCIImageConfig struct {
 	} `json:"config"`
 }
 
-// ValidateOCI validates that an OCI image contains the correct MCP server name annotation
-func ValidateOCI(ctx context.Context, pkg model.Package, serverName string) error {
-	// Set default registry base URL if empty
-	if pkg.RegistryBaseURL == "" {
-		pkg.RegistryBaseURL = model.RegistryURLDocker
+// OCIReference is a parsed OCI image reference
+type OCIReference struct {
+	Domain     string // the registry host named in the identifier, empty for Docker Hub
+	Repository string // e.g. example/weather
+	Tag        string // empty if the reference only has a digest, or neither
+	Digest     string // e.g. sha256:..., empty if the image is not pinned
+}
+
+// ParseOCIReference parses an OCI package: its identifier follows the distribution reference
+// grammar, [registry/]repository[:tag][@digest], and its version is the tag, optionally followed
+// by @digest. A tag or digest given in both must be the same.
+func ParseOCIReference(identifier, version string) (OCIReference, error) {
+	var ref OCIReference
+	name := identifier
+	if before, digest, found := strings.Cut(name, "@"); found {
+		name, ref.Digest = before, digest
+	}
+	// A colon after the last slash starts the tag; one before it is the registry's port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if name == "" {
+		return ref, fmt.Errorf("missing repository name in %q", identifier)
+	}
+	if len(name) > ociMaxNameLength {
+		return ref, fmt.Errorf("repository name is longer than %d characters", ociMaxNameLength)
 	}
 
-	// Validate that the registry base URL matches OCI/Docker exactly
-	if pkg.RegistryBaseURL != model.RegistryURLDocker {
-		return fmt.Errorf("registry type and base URL do not match: '%s' is not valid for registry type '%s'. Expected: %s",
-			pkg.RegistryBaseURL, model.RegistryTypeOCI, model.RegistryURLDocker)
+	components := strings.Split(name, "/")
+	if len(components) > 1 && isOCIDomain(components[0]) {
+		ref.Domain = components[0]
+		components = components[1:]
+		if !ociDomainRegex.MatchString(ref.Domain) {
+			return ref, fmt.Errorf("invalid registry host %q", ref.Domain)
+		}
+	}
+	for _, component := range components {
+		if !ociPathComponentRegex.MatchString(component) {
+			return ref, fmt.Errorf("invalid repository name %q: each path component must be lowercase letters and digits, separated by '.', '_', '__' or '-'", name)
+		}
 	}
+	ref.Repository = strings.Join(components, "/")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	versionTag, versionDigest, _ := strings.Cut(version, "@")
+	if versionTag != "" {
+		if ref.Tag != "" && ref.Tag != versionTag {
+			return ref, fmt.Errorf("tag %q in the identifier does not match version %q", ref.Tag, versionTag)
+		}
+		ref.Tag = versionTag
+	}
+	if versionDigest != "" {
+		if ref.Digest != "" && ref.Digest != versionDigest {
+			return ref, fmt.Errorf("digest %q in the identifier does not match the version's digest %q", ref.Digest, versionDigest)
+		}
+		ref.Digest = versionDigest
+	}
 
-	// Parse image reference (namespace/repo or repo)
-	namespace, repo, err := parseImageReference(pkg.Identifier)
-	if err != nil {
-		return fmt.Errorf("invalid OCI image reference: %w", err)
+	if ref.Tag != "" && !ociTagRegex.MatchString(ref.Tag) {
+		return ref, fmt.Errorf("invalid tag %q: up to 128 letters, digits, '_', '.' and '-', not starting with '.' or '-'", ref.Tag)
+	}
+	if ref.Digest != "" && !ociDigestRegex.MatchString(ref.Digest) {
+		return ref, fmt.Errorf("invalid digest %q: expected sha256: followed by 64 lowercase hex digits, or sha512: followed by 128", ref.Digest)
 	}
+	return ref, nil
+}
 
-	apiBaseURL := pkg.RegistryBaseURL
-	if pkg.RegistryBaseURL == model.RegistryURLDocker {
-		// docker.io is an exceptional registry that was created before standardisation, so needs a custom API base url
-		// https://github.com/containers/image/blob/5e4845dddd57598eb7afeaa6e0f4c76531bd3c91/docker/docker_client.go#L225-L229
-		apiBaseURL = dockerIoAPIBaseURL
+// isOCIDomain reports whether the first component of a reference names a registry rather than
+// starting the repository path, following the rules docker pull uses
+func isOCIDomain(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost" || component != strings.ToLower(component)
+}
+
+// Reference is what the image's manifest is fetched by: the digest if pinned, otherwise the tag
+func (r OCIReference) Reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	if r.Tag != "" {
+		return r.Tag
+	}
+	return ociDefaultTag
+}
+
+// MutableTagOnly reports whether the reference is not pinned by digest and only names latest,
+// explicitly or by default
+func (r OCIReference) MutableTagOnly() bool {
+	return r.Digest == "" && (r.Tag == "" || r.Tag == ociDefaultTag)
+}
+
+func (r OCIReference) String() string {
+	name := r.Repository
+	if r.Domain != "" {
+		name = r.Domain + "/" + name
+	}
+	if r.Tag != "" {
+		name += ":" + r.Tag
 	}
+	if r.Digest != "" {
+		name += "@" + r.Digest
+	}
+	return name
+}
 
-	tag := pkg.Version
-	manifestURL := fmt.Sprintf("%s/v2/%s/%s/manifests/%s", apiBaseURL, namespace, repo, tag)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, manifestURL, nil)
+// ociRegistryBaseURL returns the supported registry an image is on, from the package's base URL
+// or, if that is empty, the registry host in its identifier
+func ociRegistryBaseURL(baseURL string, ref OCIReference) (string, error) {
+	domain := strings.ToLower(ref.Domain)
+	if baseURL == "" {
+		if domain == "" {
+			return model.RegistryURLDocker, nil
+		}
+		for registryBaseURL, hosts := range ociRegistries {
+			if slices.Contains(hosts, domain) {
+				return registryBaseURL, nil
+			}
+		}
+		return "", fmt.Errorf("registry %s is not supported for OCI packages. Expected one of: %s", ref.Domain, supportedOCIRegistries())
+	}
+
+	hosts, ok := ociRegistries[baseURL]
+	if !ok {
+		return "", fmt.Errorf("registry type and base URL do not match: '%s' is not valid for registry type '%s'. Expected one of: %s",
+			baseURL, model.RegistryTypeOCI, supportedOCIRegistries())
+	}
+	if domain != "" && !slices.Contains(hosts, domain) {
+		return "", fmt.Errorf("image '%s' is on %s, but registry_base_url is %s", ref, ref.Domain, baseURL)
+	}
+	return baseURL, nil
+}
+
+// supportedOCIRegistries lists the supported OCI registry base URLs, for error messages
+func supportedOCIRegistries() string {
+	return strings.Join(slices.Sorted(maps.Keys(ociRegistries)), ", ")
+}
+
+// ValidateOCI validates that an OCI image exists, at its digest if it is pinned, and contains the
+// correct MCP server name annotation
+func ValidateOCI(ctx context.Context, pkg model.Package, serverName string) error {
+	ref, err := ParseOCIReference(pkg.Identifier, pkg.Version)
 	if err != nil {
-		return fmt.Errorf("failed to create manifest request: %w", err)
+		return fmt.Errorf("invalid OCI image reference: %w", err)
+	}
+	baseURL, err := ociRegistryBaseURL(pkg.RegistryBaseURL, ref)
+	if err != nil {
+		return err
 	}
 
-	// Get auth token for docker.io
-	// We only support auth for docker.io, other registries must allow unauthed requests
-	if apiBaseURL == dockerIoAPIBaseURL {
-		token, err := getDockerIoAuthToken(ctx, client, namespace, repo)
-		if err != nil {
-			return fmt.Errorf("failed to authenticate with Docker registry: %w", err)
+	apiBaseURL := baseURL
+	repository := ref.Repository
+	if baseURL == model.RegistryURLDocker {
+		// docker.io is an exceptional registry that was created before standardisation, so needs a custom API base url
+		// https://github.com/containers/image/blob/5e4845dddd57598eb7afeaa6e0f4c76531bd3c91/docker/docker_client.go#L225-L229
+		apiBaseURL = dockerIoAPIBaseURL
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
 		}
-		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
-	req.Header.Set("Accept", "application/vnd.docker.distribution.manifest.v2+json,application/vnd.oci.image.manifest.v1+json")
-	req.Header.Set("User-Agent", "MCP-Registry-Validator/1.0")
+	registry := &ociClient{
+		client:     &http.Client{Timeout: 10 * time.Second},
+		apiBaseURL: apiBaseURL,
+		repository: repository,
+	}
+	return validateOCIImage(ctx, registry, ref, serverName)
+}
 
-	resp, err := client.Do(req)
+// validateOCIImage checks the MCP server name annotation of the image ref in registry
+func validateOCIImage(ctx context.Context, registry *ociClient, ref OCIReference, serverName string) error {
+	resp, err := registry.get(ctx, "/manifests/"+ref.Reference(), strings.Join([]string{
+		"application/vnd.docker.distribution.manifest.v2+json",
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.oci.image.index.v1+json",
+	}, ","))
 	if err != nil {
 		return fmt.Errorf("failed to fetch OCI manifest: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("OCI image '%s/%s:%s' not found (status: %d)", namespace, repo, tag, resp.StatusCode)
+		return fmt.Errorf("OCI image '%s' not found (status: %d)", ref, resp.StatusCode)
 	}
 	if resp.StatusCode == http.StatusTooManyRequests {
 		// Rate limited, skip validation for now
-		log.Printf("Warning: Rate limited when accessing OCI image '%s/%s:%s'. Skipping validation.", namespace, repo, tag)
+		log.Printf("Warning: Rate limited when accessing OCI image '%s'. Skipping validation.", ref)
 		return nil
 	}
 	if resp.StatusCode != http.StatusOK {
@@ -113,7 +265,7 @@ func ValidateOCI(ctx context.Context, pkg model.Package, serverName string) erro
 	var configDigest string
 	if len(manifest.Manifests) > 0 {
 		// This is a multi-arch image, get the specific manifest
-		specificManifest, err := getSpecificManifest(ctx, client, apiBaseURL, namespace, repo, manifest.Manifests[0].Digest)
+		specificManifest, err := getSpecificManifest(ctx, registry, manifest.Manifests[0].Digest)
 		if err != nil {
 			return fmt.Errorf("failed to get specific manifest: %w", err)
 		}
@@ -123,18 +275,18 @@ func ValidateOCI(ctx context.Context, pkg model.Package, serverName string) erro
 	}
 
 	if configDigest == "" {
-		return fmt.Errorf("unable to determine image config digest for '%s/%s:%s'", namespace, repo, tag)
+		return fmt.Errorf("unable to determine image config digest for '%s'", ref)
 	}
 
 	// Get image config (contains labels)
-	config, err := getImageConfig(ctx, client, apiBaseURL, namespace, repo, configDigest)
+	config, err := getImageConfig(ctx, registry, configDigest)
 	if err != nil {
 		return fmt.Errorf("failed to get image config: %w", err)
 	}
 
 	mcpName, exists := config.Config.Labels["io.modelcontextprotocol.server.name"]
 	if !exists {
-		return fmt.Errorf("OCI image '%s/%s:%s' is missing required annotation. Add this to your Dockerfile: LABEL io.modelcontextprotocol.server.name=\"%s\"", namespace, repo, tag, serverName)
+		return fmt.Errorf("OCI image '%s' is missing required annotation. Add this to your Dockerfile: LABEL io.modelcontextprotocol.server.name=\"%s\"", ref, serverName)
 	}
 
 	if mcpName != serverName {
@@ -144,28 +296,78 @@ func ValidateOCI(ctx context.Context, pkg model.Package, serverName string) erro
 	return nil
 }
 
-func parseImageReference(identifier string) (string, string, error) {
-	parts := strings.Split(identifier, "/")
-	switch len(parts) {
-	case 2:
-		return parts[0], parts[1], nil
-	case 1:
-		return "library", parts[0], nil
-	default:
-		return "", "", fmt.Errorf("invalid image reference: %s", identifier)
+// ociClient reads a repository through a registry's distribution API. Public images on most
+// registries, Docker Hub and GHCR included, still need an anonymous token, which is requested
+// from the token service the registry names when it challenges a request.
+type ociClient struct {
+	client     *http.Client
+	apiBaseURL string
+	repository string
+	token      string
+}
+
+// get requests path under the repository, such as /manifests/latest, authenticating if challenged
+func (c *ociClient) get(ctx context.Context, path, accept string) (*http.Response, error) {
+	resp, err := c.do(ctx, path, accept)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || c.token != "" {
+		return resp, err
+	}
+	realm, params, ok := parseBearerChallenge(resp.Header.Get("WWW-Authenticate"))
+	if !ok {
+		return resp, nil
 	}
+	resp.Body.Close()
+
+	token, err := c.anonymousToken(ctx, realm, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with OCI registry: %w", err)
+	}
+	c.token = token
+	return c.do(ctx, path, accept)
 }
 
-// getDockerIoAuthToken retrieves an authentication token from Docker Hub
-func getDockerIoAuthToken(ctx context.Context, client *http.Client, namespace, repo string) (string, error) {
-	authURL := fmt.Sprintf("https://auth.docker.io/token?service=registry.docker.io&scope=repository:%s/%s:pull", namespace, repo)
+func (c *ociClient) do(ctx context.Context, path, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiBaseURL+"/v2/"+c.repository+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "MCP-Registry-Validator/1.0")
+	return c.client.Do(req)
+}
+
+// parseBearerChallenge reads the token service realm and its parameters from a WWW-Authenticate header
+func parseBearerChallenge(header string) (string, url.Values, bool) {
+	scheme, rest, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", nil, false
+	}
+	params := url.Values{}
+	var realm string
+	for _, match := range ociChallengeParamRegex.FindAllStringSubmatch(rest, -1) {
+		if match[1] == "realm" {
+			realm = match[2]
+		} else {
+			params.Set(match[1], match[2])
+		}
+	}
+	return realm, params, realm != ""
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, authURL, nil)
+// anonymousToken requests a pull token for the repository from the token service at realm
+func (c *ociClient) anonymousToken(ctx context.Context, realm string, params url.Values) (string, error) {
+	if params.Get("scope") == "" {
+		params.Set("scope", "repository:"+c.repository+":pull")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+params.Encode(), nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create auth request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to request auth token: %w", err)
 	}
@@ -179,31 +381,15 @@ func getDockerIoAuthToken(ctx context.Context, client *http.Client, namespace, r
 	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
 		return "", fmt.Errorf("failed to parse auth response: %w", err)
 	}
-
-	return authResp.Token, nil
+	if authResp.Token != "" {
+		return authResp.Token, nil
+	}
+	return authResp.AccessToken, nil
 }
 
 // getSpecificManifest retrieves a specific manifest for multi-arch images
-func getSpecificManifest(ctx context.Context, client *http.Client, apiBaseURL, namespace, repo, digest string) (*OCIManifest, error) {
-	manifestURL := fmt.Sprintf("%s/v2/%s/%s/manifests/%s", apiBaseURL, namespace, repo, digest)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, manifestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create specific manifest request: %w", err)
-	}
-
-	// Get auth token for docker.io
-	if apiBaseURL == dockerIoAPIBaseURL {
-		token, err := getDockerIoAuthToken(ctx, client, namespace, repo)
-		if err != nil {
-			return nil, fmt.Errorf("failed to authenticate with Docker registry: %w", err)
-		}
-		req.Header.Set("Authorization", "Bearer "+token)
-	}
-
-	req.Header.Set("Accept", "application/vnd.oci.image.manifest.v1+json")
-	req.Header.Set("User-Agent", "MCP-Registry-Validator/1.0")
-
-	resp, err := client.Do(req)
+func getSpecificManifest(ctx context.Context, registry *ociClient, digest string) (*OCIManifest, error) {
+	resp, err := registry.get(ctx, "/manifests/"+digest, "application/vnd.oci.image.manifest.v1+json,application/vnd.docker.distribution.manifest.v2+json")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch specific manifest: %w", err)
 	}
@@ -222,26 +408,8 @@ func getSpecificManifest(ctx context.Context, client *http.Client, apiBaseURL, n
 }
 
 // getImageConfig retrieves the image configuration containing labels
-func getImageConfig(ctx context.Context, client *http.Client, apiBaseURL, namespace, repo, configDigest string) (*OCIImageConfig, error) {
-	configURL := fmt.Sprintf("%s/v2/%s/%s/blobs/%s", apiBaseURL, namespace, repo, configDigest)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, configURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create config request: %w", err)
-	}
-
-	// Get auth token for docker.io
-	if apiBaseURL == dockerIoAPIBaseURL {
-		token, err := getDockerIoAuthToken(ctx, client, namespace, repo)
-		if err != nil {
-			return nil, fmt.Errorf("failed to authenticate with Docker registry: %w", err)
-		}
-		req.Header.Set("Authorization", "Bearer "+token)
-	}
-
-	req.Header.Set("Accept", "application/vnd.docker.distribution.manifest.v2+json")
-	req.Header.Set("User-Agent", "MCP-Registry-Validator/1.0")
-
-	resp, err := client.Do(req)
+func getImageConfig(ctx context.Context, registry *ociClient, configDigest string) (*OCIImageConfig, error) {
+	resp, err := registry.get(ctx, "/blobs/"+configDigest, "application/vnd.docker.distribution.manifest.v2+json")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch image config: %w", err)
 	}
@@ -0,0 +1,103 @@
+//nolint:testpackage
+package registries
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testManifestDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	testConfigDigest   = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// fakeOCIRegistry serves one image of example/weather, tagged 1.0.0, behind an anonymous token
+// service like Docker Hub's and GHCR's
+func fakeOCIRegistry(t *testing.T, serverName string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var requested []string
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "registry.test", r.URL.Query().Get("service"))
+		assert.Equal(t, "repository:example/weather:pull", r.URL.Query().Get("scope"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "anonymous"})
+	})
+	mux.HandleFunc("/v2/example/weather/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry.test",scope="repository:example/weather:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		path := strings.TrimPrefix(r.URL.Path, "/v2/example/weather")
+		requested = append(requested, path)
+		switch path {
+		case "/manifests/1.0.0", "/manifests/" + testManifestDigest:
+			_ = json.NewEncoder(w).Encode(map[string]any{"config": map[string]string{"digest": testConfigDigest}})
+		case "/blobs/" + testConfigDigest:
+			_ = json.NewEncoder(w).Encode(map[string]any{"config": map[string]any{
+				"Labels": map[string]string{"io.modelcontextprotocol.server.name": serverName},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &requested
+}
+
+func TestValidateOCIImage(t *testing.T) {
+	server, requested := fakeOCIRegistry(t, "com.example/weather")
+	validate := func(identifier, version string) error {
+		ref, err := ParseOCIReference(identifier, version)
+		require.NoError(t, err)
+		registry := &ociClient{client: server.Client(), apiBaseURL: server.URL, repository: ref.Repository}
+		return validateOCIImage(context.Background(), registry, ref, "com.example/weather")
+	}
+
+	t.Run("tag", func(t *testing.T) {
+		*requested = nil
+		require.NoError(t, validate("example/weather", "1.0.0"))
+		assert.Equal(t, []string{"/manifests/1.0.0", "/blobs/" + testConfigDigest}, *requested)
+	})
+
+	t.Run("pinned digest is fetched by digest", func(t *testing.T) {
+		*requested = nil
+		require.NoError(t, validate("example/weather", "1.0.0@"+testManifestDigest))
+		assert.Equal(t, []string{"/manifests/" + testManifestDigest, "/blobs/" + testConfigDigest}, *requested)
+	})
+
+	t.Run("unknown digest", func(t *testing.T) {
+		err := validate("example/weather", "1.0.0@sha256:"+strings.Repeat("3", 64))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("wrong server name", func(t *testing.T) {
+		ref, err := ParseOCIReference("example/weather", "1.0.0")
+		require.NoError(t, err)
+		registry := &ociClient{client: server.Client(), apiBaseURL: server.URL, repository: ref.Repository}
+		err = validateOCIImage(context.Background(), registry, ref, "com.example/other")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ownership validation failed")
+	})
+}
+
+func TestParseBearerChallenge(t *testing.T) {
+	realm, params, ok := parseBearerChallenge(`Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:example/weather:pull"`)
+	require.True(t, ok)
+	assert.Equal(t, "https://ghcr.io/token", realm)
+	assert.Equal(t, "ghcr.io", params.Get("service"))
+	assert.Equal(t, "repository:example/weather:pull", params.Get("scope"))
+
+	_, _, ok = parseBearerChallenge(`Basic realm="registry"`)
+	assert.False(t, ok)
+}
@@ -2,13 +2,145 @@ package registries_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/modelcontextprotocol/registry/internal/validators/registries"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParseOCIReference(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		version    string
+		expected   registries.OCIReference
+		reference  string
+		mutable    bool
+	}{
+		{
+			name:       "official image",
+			identifier: "nginx",
+			version:    "1.27",
+			expected:   registries.OCIReference{Repository: "nginx", Tag: "1.27"},
+			reference:  "1.27",
+		},
+		{
+			name:       "no tag or digest means latest",
+			identifier: "example/weather",
+			expected:   registries.OCIReference{Repository: "example/weather"},
+			reference:  "latest",
+			mutable:    true,
+		},
+		{
+			name:       "explicit latest",
+			identifier: "example/weather",
+			version:    "latest",
+			expected:   registries.OCIReference{Repository: "example/weather", Tag: "latest"},
+			reference:  "latest",
+			mutable:    true,
+		},
+		{
+			name:       "latest pinned by digest",
+			identifier: "example/weather",
+			version:    "latest@" + testDigest,
+			expected:   registries.OCIReference{Repository: "example/weather", Tag: "latest", Digest: testDigest},
+			reference:  testDigest,
+		},
+		{
+			name:       "digest in the identifier",
+			identifier: "example/weather@" + testDigest,
+			version:    "1.0.0",
+			expected:   registries.OCIReference{Repository: "example/weather", Tag: "1.0.0", Digest: testDigest},
+			reference:  testDigest,
+		},
+		{
+			name:       "registry host with port",
+			identifier: "localhost:5000/example/weather:1.0.0",
+			version:    "1.0.0",
+			expected:   registries.OCIReference{Domain: "localhost:5000", Repository: "example/weather", Tag: "1.0.0"},
+			reference:  "1.0.0",
+		},
+		{
+			name:       "ghcr image with separators",
+			identifier: "ghcr.io/example-org/weather__mcp.server",
+			version:    "v1.0.0_rc.1",
+			expected:   registries.OCIReference{Domain: "ghcr.io", Repository: "example-org/weather__mcp.server", Tag: "v1.0.0_rc.1"},
+			reference:  "v1.0.0_rc.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := registries.ParseOCIReference(tt.identifier, tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ref)
+			assert.Equal(t, tt.reference, ref.Reference())
+			assert.Equal(t, tt.mutable, ref.MutableTagOnly())
+		})
+	}
+}
+
+func TestParseOCIReference_Invalid(t *testing.T) {
+	tests := []struct {
+		name         string
+		identifier   string
+		version      string
+		errorMessage string
+	}{
+		{"uppercase repository", "example/Weather", "1.0.0", "invalid repository name"},
+		{"npm-style scope", "@example/weather", "1.0.0", "missing repository name"},
+		{"empty path component", "example//weather", "1.0.0", "invalid repository name"},
+		{"trailing separator", "example/weather-", "1.0.0", "invalid repository name"},
+		{"triple underscore", "example/weather___mcp", "1.0.0", "invalid repository name"},
+		{"empty name", ":1.0.0", "", "missing repository name"},
+		{"name too long", "example/" + strings.Repeat("a", 250), "1.0.0", "longer than 255"},
+		{"invalid registry host", "-ghcr.io/example/weather", "1.0.0", "invalid registry host"},
+		{"tag starting with a dot", "example/weather", ".1", "invalid tag"},
+		{"tag too long", "example/weather", strings.Repeat("a", 129), "invalid tag"},
+		{"tag mismatch", "example/weather:1.0.0", "2.0.0", "does not match"},
+		{"short digest", "example/weather", "1.0.0@sha256:abc123", "invalid digest"},
+		{"uppercase digest", "example/weather", "1.0.0@" + strings.ToUpper(testDigest), "invalid digest"},
+		{"unsupported algorithm", "example/weather", "1.0.0@md5:0123456789abcdef0123456789abcdef", "invalid digest"},
+		{"digest mismatch", "example/weather@" + testDigest, "1.0.0@sha256:" + strings.Repeat("f", 64), "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registries.ParseOCIReference(tt.identifier, tt.version)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMessage)
+		})
+	}
+}
+
+func TestValidateOCI_RegistryBaseURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		identifier   string
+		baseURL      string
+		errorMessage string
+	}{
+		{"unsupported base URL", "example/weather", "https://registry.example.com", "registry type and base URL do not match"},
+		{"identifier on another registry", "ghcr.io/example/weather", model.RegistryURLDocker, "but registry_base_url is"},
+		{"unsupported registry host", "registry.example.com/example/weather", "", "is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registries.ValidateOCI(context.Background(), model.Package{
+				RegistryType:    model.RegistryTypeOCI,
+				RegistryBaseURL: tt.baseURL,
+				Identifier:      tt.identifier,
+				Version:         "1.0.0",
+			}, "com.example/weather")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMessage)
+		})
+	}
+}
+
 func TestValidateOCI_RealPackages(t *testing.T) {
 	ctx := context.Background()
 
@@ -0,0 +1,156 @@
+package validators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// Hosting provider API base URLs used to resolve repository IDs (configurable for testing)
+var (
+	GitHubAPIBaseURL = "https://api.github.com"
+	GitLabAPIBaseURL = "https://gitlab.com/api/v4"
+)
+
+// Repository hosts accepted besides github and gitlab, set from the registry's configuration
+var (
+	repositorySourcesMu sync.RWMutex
+	repositorySources   = map[RepositorySource]config.RepositorySource{}
+)
+
+// repositoryPathSegmentRegex matches one segment of a repository path, such as an owner or repository name
+var repositoryPathSegmentRegex = regexp.MustCompile(`^[\w.-]+$`)
+
+// SetRepositorySources replaces the repository sources accepted besides github and gitlab. The
+// registry sets them from REPOSITORY_SOURCES at startup. Sources only widen which repository
+// URLs are valid: they grant no namespace and are not trusted for remote URL domain matching.
+func SetRepositorySources(sources []config.RepositorySource) {
+	configured := make(map[RepositorySource]config.RepositorySource, len(sources))
+	for _, source := range sources {
+		configured[RepositorySource(source.Name)] = source
+	}
+	repositorySourcesMu.Lock()
+	defer repositorySourcesMu.Unlock()
+	repositorySources = configured
+}
+
+// configuredRepositorySource returns the configured source with the given name
+func configuredRepositorySource(name RepositorySource) (config.RepositorySource, bool) {
+	repositorySourcesMu.RLock()
+	defer repositorySourcesMu.RUnlock()
+	source, ok := repositorySources[name]
+	return source, ok
+}
+
+// matchesRepositorySource reports whether rawURL is a repository on the source's host, with a
+// path of between MinDepth and MaxDepth segments
+func matchesRepositorySource(source config.RepositorySource, rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return false
+	}
+
+	host := strings.ToLower(u.Host)
+	if suffix, wildcard := strings.CutPrefix(source.Host, "*"); wildcard {
+		if !strings.HasSuffix(host, suffix) || len(host) == len(suffix) {
+			return false
+		}
+	} else if host != source.Host {
+		return false
+	}
+
+	segments := strings.Split(strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), "/"), "/")
+	if len(segments) < source.MinDepth || len(segments) > source.MaxDepth {
+		return false
+	}
+	for _, segment := range segments {
+		if !repositoryPathSegmentRegex.MatchString(segment) {
+			return false
+		}
+	}
+	return true
+}
+
+// repositoryIDResponse is the part of the GitHub repository and GitLab project APIs we need
+type repositoryIDResponse struct {
+	ID int64 `json:"id"`
+}
+
+// ResolveRepositoryID looks up the repository's ID from its hosting provider, filling it in when
+// absent and rejecting a provided ID that does not match. If the provider cannot be reached or does
+// not know the repository (for example because it is private), the repository is left unchanged.
+func ResolveRepositoryID(ctx context.Context, repo *model.Repository) error {
+	if repo.URL == "" {
+		return nil
+	}
+
+	resolved, ok := lookupRepositoryID(ctx, RepositorySource(repo.Source), repo.URL)
+	if !ok {
+		return nil
+	}
+
+	if repo.ID == "" {
+		repo.ID = resolved
+		return nil
+	}
+	if repo.ID != resolved {
+		return fmt.Errorf("%w: %s reports ID %s for %s, but %s was provided", ErrRepositoryIDMismatch, repo.Source, resolved, repo.URL, repo.ID)
+	}
+
+	return nil
+}
+
+// lookupRepositoryID fetches the numeric ID of the repository at repoURL, reporting false if it could not be determined
+func lookupRepositoryID(ctx context.Context, source RepositorySource, repoURL string) (string, bool) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return "", false
+	}
+	path := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+
+	var apiURL string
+	switch source {
+	case SourceGitHub:
+		apiURL = GitHubAPIBaseURL + "/repos/" + path
+	case SourceGitLab:
+		// GitLab addresses projects by their URL-encoded full path
+		apiURL = GitLabAPIBaseURL + "/projects/" + strings.ReplaceAll(path, "/", "%2F")
+	default:
+		return "", false
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", false
+	}
+	req.Header.Set("User-Agent", "MCP-Registry-Validator/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+
+	var body repositoryIDResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.ID <= 0 {
+		return "", false
+	}
+
+	return strconv.FormatInt(body.ID, 10), true
+}
@@ -0,0 +1,70 @@
+package validators
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// Rules are the limits and allowed values the validators enforce. The checks read them from
+// here and GET /v0/meta/validation-rules serves them, so the two cannot drift apart. Name and
+// description lengths are enforced by the API schema; a test keeps them equal to its tags.
+var Rules = apiv0.ValidationRules{
+	NamePattern:          `^[^/]+/[\s\S]+$`,
+	MaxNameLength:        200,
+	MaxDescriptionLength: 100,
+	MaxVersionLength:     MaxVersionLength,
+	MaxTitleLength:       MaxTitleLength,
+	MaxLicenseLength:     MaxLicenseLength,
+	MaxContactLength:     MaxContactLength,
+	MaxReadmeBytes:       MaxReadmeBytes,
+	MaxReleaseNotesBytes: MaxReleaseNotesBytes,
+	MaxIcons:             MaxIcons,
+	MaxIconDimension:     MaxIconDimension,
+	IconMimeTypes:        []string{"image/jpeg", "image/png", "image/svg+xml", "image/webp"},
+	MaxCategories:        MaxCategories,
+	MCPVersions:          []string{"2024-11-05", "2025-03-26", "2025-06-18"},
+	RegistryTypes: []string{
+		model.RegistryTypeMCPB, model.RegistryTypeNPM, model.RegistryTypeNuGet, model.RegistryTypeOCI, model.RegistryTypePyPI,
+	},
+	PackageTransports: []string{model.TransportTypeSSE, model.TransportTypeStdio, model.TransportTypeStreamableHTTP},
+	RemoteTransports:  []string{model.TransportTypeSSE, model.TransportTypeStreamableHTTP},
+	AuthenticationSchemes: []string{
+		model.AuthenticationSchemeBearer, model.AuthenticationSchemeHeader, model.AuthenticationSchemeNone, model.AuthenticationSchemeOAuth2,
+	},
+	RepositorySources:    []string{string(SourceGitHub), string(SourceGitLab)},
+	MaxTransportHeaders:  MaxTransportHeaders,
+	MaxHeaderValueLength: MaxHeaderValueLength,
+	MaxInputVariables:    MaxInputVariables,
+	ForbiddenHeaders:     slices.Sorted(maps.Keys(forbiddenHeaders)),
+}
+
+// serverNameRegex matches the server names Rules.NamePattern allows
+var serverNameRegex = regexp.MustCompile(Rules.NamePattern)
+
+// CurrentRules returns Rules as the registry applies them: with the repository sources it was
+// configured to accept, the category taxonomy, empty when any category is accepted, and the
+// reserved namespaces
+func CurrentRules(categories, reservedNamespaces []string) apiv0.ValidationRules {
+	rules := Rules
+	repositorySourcesMu.RLock()
+	rules.RepositorySources = slices.Clone(Rules.RepositorySources)
+	for name := range repositorySources {
+		rules.RepositorySources = append(rules.RepositorySources, string(name))
+	}
+	repositorySourcesMu.RUnlock()
+	slices.Sort(rules.RepositorySources[len(Rules.RepositorySources):])
+
+	rules.Categories = slices.Clone(categories)
+	if rules.Categories == nil {
+		rules.Categories = []string{}
+	}
+	rules.ReservedNamespaces = slices.Clone(reservedNamespaces)
+	if rules.ReservedNamespaces == nil {
+		rules.ReservedNamespaces = []string{}
+	}
+	return rules
+}
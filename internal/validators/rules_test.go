@@ -0,0 +1,181 @@
+package validators_test
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// TestRulesMatchChecks probes the validators with every value of each enum they check, and
+// values just inside and outside each limit, asserting the rules say the same
+func TestRulesMatchChecks(t *testing.T) {
+	rules := validators.Rules
+	validate := func(mutate func(*apiv0.ServerJSON)) error {
+		server := apiv0.ServerJSON{Name: "com.example/server", Description: "A server", Version: "1.0.0"}
+		mutate(&server)
+		return validators.ValidateServerJSON(&server)
+	}
+
+	t.Run("registry types", func(t *testing.T) {
+		for _, registryType := range []string{
+			model.RegistryTypeNPM, model.RegistryTypePyPI, model.RegistryTypeOCI, model.RegistryTypeNuGet, model.RegistryTypeMCPB,
+		} {
+			assert.Contains(t, rules.RegistryTypes, registryType)
+		}
+		err := validators.ValidatePackage(context.Background(), model.Package{RegistryType: "cargo"}, "com.example/server")
+		assert.ErrorContains(t, err, "unsupported registry type")
+	})
+
+	t.Run("transports", func(t *testing.T) {
+		for _, transport := range []string{model.TransportTypeStdio, model.TransportTypeStreamableHTTP, model.TransportTypeSSE, "websocket"} {
+			err := validate(func(s *apiv0.ServerJSON) {
+				s.Packages = []model.Package{{RegistryType: model.RegistryTypeNPM, Identifier: "server", Version: "1.0.0", Transport: model.Transport{Type: transport}}}
+			})
+			unsupported := err != nil && strings.Contains(err.Error(), "unsupported transport type")
+			assert.Equal(t, !unsupported, slices.Contains(rules.PackageTransports, transport), transport)
+
+			err = validate(func(s *apiv0.ServerJSON) {
+				s.Remotes = []model.Transport{{Type: transport, URL: "https://server.example.com/mcp"}}
+			})
+			unsupported = err != nil && strings.Contains(err.Error(), "unsupported transport type")
+			assert.Equal(t, !unsupported, slices.Contains(rules.RemoteTransports, transport), transport)
+		}
+	})
+
+	t.Run("authentication schemes", func(t *testing.T) {
+		for _, scheme := range []string{
+			model.AuthenticationSchemeNone, model.AuthenticationSchemeBearer, model.AuthenticationSchemeOAuth2, model.AuthenticationSchemeHeader, "basic",
+		} {
+			err := validate(func(s *apiv0.ServerJSON) {
+				s.Remotes = []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: "https://server.example.com/mcp",
+					Authentication: &model.Authentication{Scheme: scheme}}}
+			})
+			unsupported := err != nil && strings.Contains(err.Error(), "unsupported scheme")
+			assert.Equal(t, !unsupported, slices.Contains(rules.AuthenticationSchemes, scheme), scheme)
+		}
+	})
+
+	t.Run("repository sources", func(t *testing.T) {
+		for _, source := range []string{string(validators.SourceGitHub), string(validators.SourceGitLab)} {
+			assert.Contains(t, rules.RepositorySources, source)
+		}
+		assert.Contains(t, validators.CurrentRules(nil, nil).RepositorySources, "github")
+	})
+
+	t.Run("icon MIME types", func(t *testing.T) {
+		for _, mimeType := range []string{"image/png", "image/jpeg", "image/svg+xml", "image/webp", "image/gif", "image/x-icon"} {
+			err := validate(func(s *apiv0.ServerJSON) {
+				s.Icons = []model.Icon{{Src: "https://example.com/icon", MimeType: mimeType}}
+			})
+			assert.Equal(t, err == nil, slices.Contains(rules.IconMimeTypes, mimeType), mimeType)
+		}
+	})
+
+	t.Run("MCP versions", func(t *testing.T) {
+		for _, version := range []string{"2024-11-05", "2025-03-26", "2025-06-18", "2025-01-01", "latest"} {
+			err := validate(func(s *apiv0.ServerJSON) { s.MCPVersion = version })
+			assert.Equal(t, err == nil, slices.Contains(rules.MCPVersions, version), version)
+			if err != nil {
+				assert.ErrorIs(t, err, validators.ErrUnknownMCPVersion)
+			}
+		}
+		assert.True(t, slices.IsSorted(rules.MCPVersions), "oldest first")
+	})
+
+	t.Run("forbidden headers", func(t *testing.T) {
+		for _, name := range append([]string{"X-Api-Version", "Accept"}, rules.ForbiddenHeaders...) {
+			err := validate(func(s *apiv0.ServerJSON) {
+				s.Remotes = []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: "https://server.example.com/mcp",
+					Headers: []model.KeyValueInput{{Name: strings.ToLower(name), InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "1"}}}}}}
+			})
+			assert.Equal(t, err != nil, slices.Contains(rules.ForbiddenHeaders, name), name)
+		}
+	})
+
+	t.Run("name pattern", func(t *testing.T) {
+		pattern := regexp.MustCompile(rules.NamePattern)
+		for _, name := range []string{"com.example/server", "com.example/a/b", "com.example", "/server", "com.example/", "a/b"} {
+			err := validate(func(s *apiv0.ServerJSON) { s.Name = name })
+			assert.Equal(t, err == nil, pattern.MatchString(name), name)
+		}
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		limits := map[string]struct {
+			limit  int
+			mutate func(s *apiv0.ServerJSON, n int)
+		}{
+			"title":         {rules.MaxTitleLength, func(s *apiv0.ServerJSON, n int) { s.Title = strings.Repeat("t", n) }},
+			"license":       {rules.MaxLicenseLength, func(s *apiv0.ServerJSON, n int) { s.License = "MIT" + strings.Repeat(" ", n-3) }},
+			"readme":        {rules.MaxReadmeBytes, func(s *apiv0.ServerJSON, n int) { s.Readme = strings.Repeat("r", n) }},
+			"release notes": {rules.MaxReleaseNotesBytes, func(s *apiv0.ServerJSON, n int) { s.ReleaseNotes = strings.Repeat("r", n) }},
+			"icons": {rules.MaxIcons, func(s *apiv0.ServerJSON, n int) {
+				s.Icons = make([]model.Icon, n)
+				for i := range s.Icons {
+					s.Icons[i] = model.Icon{Src: "https://example.com/icon.png", MimeType: "image/png"}
+				}
+			}},
+			"icon dimension": {rules.MaxIconDimension, func(s *apiv0.ServerJSON, n int) {
+				size := strconv.Itoa(n) + "x" + strconv.Itoa(n)
+				s.Icons = []model.Icon{{Src: "https://example.com/icon.png", MimeType: "image/png", Sizes: []string{size}}}
+			}},
+			"headers": {rules.MaxTransportHeaders, func(s *apiv0.ServerJSON, n int) {
+				headers := make([]model.KeyValueInput, n)
+				for i := range headers {
+					headers[i] = model.KeyValueInput{Name: "X-Header-" + strconv.Itoa(i)}
+				}
+				s.Remotes = []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: "https://server.example.com/mcp", Headers: headers}}
+			}},
+			"variables": {rules.MaxInputVariables, func(s *apiv0.ServerJSON, n int) {
+				variables := make(map[string]model.Input, n)
+				for i := range n {
+					variables["v"+strconv.Itoa(i)] = model.Input{}
+				}
+				s.Remotes = []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: "https://server.example.com/mcp",
+					Headers: []model.KeyValueInput{{Name: "X-Region", InputWithVariables: model.InputWithVariables{Variables: variables}}}}}
+			}},
+			"header value": {rules.MaxHeaderValueLength, func(s *apiv0.ServerJSON, n int) {
+				s.Remotes = []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: "https://server.example.com/mcp",
+					Headers: []model.KeyValueInput{{Name: "X-Region", InputWithVariables: model.InputWithVariables{Input: model.Input{Value: strings.Repeat("a", n)}}}}}}
+			}},
+		}
+		for name, tt := range limits {
+			t.Run(name, func(t *testing.T) {
+				assert.NoError(t, validate(func(s *apiv0.ServerJSON) { tt.mutate(s, tt.limit) }))
+				assert.Error(t, validate(func(s *apiv0.ServerJSON) { tt.mutate(s, tt.limit+1) }))
+			})
+		}
+
+		cfg := config.NewConfig()
+		cfg.ServerCategories = nil
+		categories := make([]string, rules.MaxCategories+1)
+		for i := range categories {
+			categories[i] = "category-" + strconv.Itoa(i)
+		}
+		server := apiv0.ServerJSON{Name: "com.example/server", Description: "A server", Version: "1.0.0", Categories: categories[:rules.MaxCategories]}
+		require.NoError(t, validators.ValidatePublishRequest(context.Background(), server, cfg))
+		server.Categories = categories
+		assert.ErrorIs(t, validators.ValidatePublishRequest(context.Background(), server, cfg), validators.ErrTooManyCategories)
+	})
+
+	t.Run("schema lengths", func(t *testing.T) {
+		serverType := reflect.TypeOf(apiv0.ServerJSON{})
+		for field, limit := range map[string]int{"Name": rules.MaxNameLength, "Description": rules.MaxDescriptionLength} {
+			structField, ok := serverType.FieldByName(field)
+			require.True(t, ok)
+			assert.Equal(t, strconv.Itoa(limit), structField.Tag.Get("maxLength"), field)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// credentialPrefixes are the prefixes of well-known API token formats
+var credentialPrefixes = []string{
+	// GitHub and GitLab
+	"ghp_", "gho_", "ghu_", "ghs_", "ghr_", "github_pat_", "glpat-",
+	// OpenAI, Anthropic and Stripe
+	"sk-", "sk_live_", "sk_test_", "rk_live_",
+	// Slack
+	"xoxb-", "xoxp-", "xoxa-", "xoxs-",
+	// AWS access keys and Google API keys
+	"AKIA", "ASIA", "AIza",
+	// Hugging Face and npm
+	"hf_", "npm_",
+}
+
+// Thresholds of the high-entropy heuristic. Random tokens of at least minCredentialLength
+// characters drawn from letters and digits score well above minCredentialEntropy bits per
+// character, while words, region names and version strings score below it.
+const (
+	minCredentialLength  = 20
+	minCredentialEntropy = 3.5
+)
+
+// validateHeaderSecrets rejects transport headers that would publish a credential in a known token
+// format to every registry consumer, and headers beyond the size limits. Errors never include the
+// suspected secret.
+func validateHeaderSecrets(headers []model.KeyValueInput) error {
+	if len(headers) > Rules.MaxTransportHeaders {
+		return fmt.Errorf("%w: %d headers, at most %d allowed", ErrTooManyHeaders, len(headers), Rules.MaxTransportHeaders)
+	}
+
+	for _, header := range headers {
+		for _, value := range []string{header.Value, header.Default} {
+			if len(value) > Rules.MaxHeaderValueLength {
+				return fmt.Errorf("%w: header %s is %d bytes, at most %d allowed", ErrHeaderValueTooLong, header.Name, len(value), Rules.MaxHeaderValueLength)
+			}
+		}
+
+		// A template such as "Bearer {token}" is filled in by the user; only literal values leak
+		placeholders, _ := parseTemplatePlaceholders(header.Value)
+		if header.IsSecret && len(placeholders) == 0 && (header.Value != "" || header.Default != "") {
+			return fmt.Errorf("%w: header %s is marked secret, so the value must be supplied by the user", ErrSecretHeaderValue, header.Name)
+		}
+
+		// Headers that are templates and not secret are trusted to hold only public literals
+		if len(placeholders) > 0 && !header.IsSecret {
+			continue
+		}
+		for _, value := range []string{header.Value, header.Default} {
+			if token, ok := findCredential(value); ok {
+				return fmt.Errorf("%w: header %s contains %s; pass credentials in a variable marked is_secret instead",
+					ErrCredentialInHeader, header.Name, redact(token))
+			}
+		}
+	}
+	return nil
+}
+
+// warnSuspiciousHeaders adds a warning to ctx for each header value with a token that is long,
+// random-looking and in no known credential format. Such tokens are often API keys, but also
+// public client IDs, so they don't stop a publish. Like errors, warnings never include the token.
+func warnSuspiciousHeaders(ctx context.Context, server apiv0.ServerJSON) {
+	for i, pkg := range server.Packages {
+		warnSuspiciousHeaderValues(ctx, fmt.Sprintf("packages[%d].transport.headers", i), pkg.Transport.Headers)
+	}
+	for i, remote := range server.Remotes {
+		warnSuspiciousHeaderValues(ctx, fmt.Sprintf("remotes[%d].headers", i), remote.Headers)
+	}
+}
+
+// warnSuspiciousHeaderValues checks the headers at path for warnSuspiciousHeaders
+func warnSuspiciousHeaderValues(ctx context.Context, path string, headers []model.KeyValueInput) {
+	for j, header := range headers {
+		// As in validateHeaderSecrets, non-secret templates are trusted to hold only public literals
+		placeholders, _ := parseTemplatePlaceholders(header.Value)
+		if len(placeholders) > 0 && !header.IsSecret {
+			continue
+		}
+		for _, field := range []struct{ name, value string }{{"value", header.Value}, {"default", header.Default}} {
+			token, ok := findToken(field.value, looksRandom)
+			if !ok {
+				continue
+			}
+			Warn(ctx, apiv0.Warning{
+				Code: apiv0.WarningSuspiciousHeaderValue,
+				Path: fmt.Sprintf("%s[%d].%s", path, j, field.name),
+				Message: fmt.Sprintf("header %s contains %s; if it is a secret, pass it in a variable marked is_secret instead",
+					header.Name, redact(token)),
+			})
+		}
+	}
+}
+
+// findCredential returns the first whitespace-separated token of value in a known credential format
+func findCredential(value string) (string, bool) {
+	return findToken(value, hasCredentialPrefix)
+}
+
+// findToken returns the first whitespace-separated token of value that match reports
+func findToken(value string, match func(token string) bool) (string, bool) {
+	for _, token := range strings.Fields(value) {
+		if match(token) {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// hasCredentialPrefix reports whether a token has a known API token prefix followed by enough
+// characters to be a real token
+func hasCredentialPrefix(token string) bool {
+	for _, prefix := range credentialPrefixes {
+		if strings.HasPrefix(token, prefix) && len(token) >= len(prefix)+16 {
+			return true
+		}
+	}
+	return false
+}
+
+// looksRandom reports whether a token without a known prefix is long, high-entropy and made
+// only of the characters tokens are encoded with
+func looksRandom(token string) bool {
+	if hasCredentialPrefix(token) || len(token) < minCredentialLength {
+		return false
+	}
+	var letters, digits bool
+	for _, r := range token {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			letters = true
+		case r >= '0' && r <= '9':
+			digits = true
+		case strings.ContainsRune("+/=_-.", r):
+		default:
+			return false
+		}
+	}
+	return letters && digits && shannonEntropy(token) >= minCredentialEntropy
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	var entropy float64
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// redact identifies a suspected secret in an error message without revealing it
+func redact(token string) string {
+	if len(token) <= 8 {
+		return fmt.Sprintf("a %d-character credential", len(token))
+	}
+	return fmt.Sprintf("a %d-character credential starting %q", len(token), token[:4])
+}
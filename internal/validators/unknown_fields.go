@@ -0,0 +1,245 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// maxFieldSuggestionDistance is the most edits between an unknown field and a known one for
+// the known one to be suggested
+const maxFieldSuggestionDistance = 2
+
+var serverJSONType = reflect.TypeOf(apiv0.ServerJSON{})
+
+// variableType is the type of the variables an input declares, which cannot declare their own
+var variableType = reflect.TypeOf(model.Input{})
+
+// CheckUnknownFields reports the fields of a server.json document that the format does not
+// define, which decoding it would silently drop: a typo such as "respository" would otherwise
+// publish a server without a repository. Each unknown field is a FieldError at its JSON pointer,
+// suggesting the known field it is closest to, and all of them are joined into one error.
+// Documents that are not valid JSON are left for decoding to reject.
+func CheckUnknownFields(data []byte) error {
+	document, err := decodeDocument(data)
+	if err != nil {
+		return nil
+	}
+	var errs []error
+	for _, field := range walkUnknownFields(document, serverJSONType, nil, false) {
+		errs = append(errs, fieldError(JSONPointer(field.path...), field.err))
+	}
+	return errors.Join(errs...)
+}
+
+// StripUnknownFields removes the fields CheckUnknownFields reports from a server.json document,
+// returning the document without them and a warning for each one removed. Variables nested in
+// variables are not removed but rejected, as FieldErrors, since the publisher meant them to be
+// filled in.
+func StripUnknownFields(data []byte) ([]byte, []apiv0.Warning, error) {
+	document, err := decodeDocument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	removed := walkUnknownFields(document, serverJSONType, nil, true)
+	var errs []error
+	for _, field := range removed {
+		if errors.Is(field.err, ErrNestedVariables) {
+			errs = append(errs, fieldError(JSONPointer(field.path...), field.err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	if len(removed) == 0 {
+		return data, nil, nil
+	}
+	stripped, err := json.Marshal(document)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings := make([]apiv0.Warning, 0, len(removed))
+	for _, field := range removed {
+		warnings = append(warnings, apiv0.Warning{
+			Code:    apiv0.WarningUnknownField,
+			Path:    warningPath(field.path),
+			Message: "ignored " + field.err.Error(),
+		})
+	}
+	return stripped, warnings, nil
+}
+
+// unknownField is a field of a document that its type does not define
+type unknownField struct {
+	path []any
+	err  error
+}
+
+// warningPath formats a path the way warnings locate fields, as in packages[0].transport.url
+func warningPath(path []any) string {
+	var formatted strings.Builder
+	for _, token := range path {
+		switch token := token.(type) {
+		case int:
+			fmt.Fprintf(&formatted, "[%d]", token)
+		default:
+			if formatted.Len() > 0 {
+				formatted.WriteByte('.')
+			}
+			fmt.Fprint(&formatted, token)
+		}
+	}
+	return formatted.String()
+}
+
+// decodeDocument decodes JSON generically, keeping numbers as written
+func decodeDocument(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// walkUnknownFields returns each key of value, at path, that t does not decode, descending into
+// the keys it does. With strip set the unknown keys are also deleted.
+func walkUnknownFields(value any, t reflect.Type, path []any, strip bool) []unknownField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var fields []unknownField
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			// Values of the wrong type are for schema validation to reject
+			return nil
+		}
+		known := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			if fieldType, ok := known[key]; ok {
+				fields = append(fields, walkUnknownFields(object[key], fieldType, append(path, key), strip)...)
+				continue
+			}
+			err := unknownFieldError(key, known)
+			if t == variableType && key == "variables" {
+				err = fmt.Errorf("%w: %s is a variable, so it cannot declare variables of its own", ErrNestedVariables, warningPath(path))
+			}
+			fields = append(fields, unknownField{path: append(append([]any(nil), path...), key), err: err})
+			if strip {
+				delete(object, key)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			fields = append(fields, walkUnknownFields(item, t.Elem(), append(path, i), strip)...)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(object) {
+			fields = append(fields, walkUnknownFields(object[key], t.Elem(), append(path, key), strip)...)
+		}
+	default:
+		// Scalars and free-form values such as interfaces have no fields to check
+	}
+	return fields
+}
+
+// sortedKeys returns the keys of object in order, so fields are reported in a stable order
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonFields maps the JSON names of t's fields to their types, including the fields of embedded
+// structs, which encoding/json decodes as if they were t's own
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for embeddedName, embeddedType := range jsonFields(embedded) {
+					if _, shadowed := fields[embeddedName]; !shadowed {
+						fields[embeddedName] = embeddedType
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// unknownFieldError describes an unknown field, suggesting the known field it is most likely a typo of
+func unknownFieldError(name string, fields map[string]reflect.Type) error {
+	suggestion, best := "", maxFieldSuggestionDistance+1
+	for known := range fields {
+		distance := fieldNameDistance(strings.ToLower(name), strings.ToLower(known))
+		if distance < best || (distance == best && known < suggestion) {
+			suggestion, best = known, distance
+		}
+	}
+	if suggestion == "" {
+		return fmt.Errorf("%w %q", ErrUnknownField, name)
+	}
+	return fmt.Errorf("%w %q; did you mean %q?", ErrUnknownField, name, suggestion)
+}
+
+// fieldNameDistance is the Levenshtein distance between two field names
+func fieldNameDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
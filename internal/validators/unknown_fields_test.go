@@ -0,0 +1,150 @@
+package validators_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestCheckUnknownFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		// want maps the JSON pointer of each unknown field to its message
+		want map[string]string
+	}{
+		{
+			name: "known fields",
+			document: `{
+				"$schema": "https://static.modelcontextprotocol.io/schemas/2025-09-29/server.schema.json",
+				"name": "io.github.example/weather",
+				"description": "Weather forecasts",
+				"version": "1.0.0",
+				"repository": {"url": "https://github.com/example/weather", "source": "github"},
+				"packages": [{
+					"registry_type": "npm",
+					"identifier": "@example/weather",
+					"version": "1.0.0",
+					"transport": {"type": "stdio"},
+					"package_arguments": [{"type": "positional", "value": "--verbose", "variables": {"level": {"default": "1"}}}],
+					"environment_variables": [{"name": "API_KEY", "is_secret": true}]
+				}],
+				"remotes": [{"type": "streamable-http", "url": "https://weather.example.com/mcp", "headers": [{"name": "X-Region", "value": "eu"}]}],
+				"_meta": {"io.modelcontextprotocol.registry/publisher-provided": {"anything": {"goes": true}}}
+			}`,
+		},
+		{
+			name:     "misspelled top-level field",
+			document: `{"name": "io.github.example/weather", "respository": {"url": "https://github.com/example/weather"}, "verison": "1.0.0"}`,
+			want: map[string]string{
+				"/respository": `unknown field "respository"; did you mean "repository"?`,
+				"/verison":     `unknown field "verison"; did you mean "version"?`,
+			},
+		},
+		{
+			name:     "camel case package field",
+			document: `{"packages": [{"identifier": "@example/weather"}, {"registryType": "npm", "runtimeHint": "npx"}]}`,
+			want: map[string]string{
+				"/packages/1/registryType": `unknown field "registryType"; did you mean "registry_type"?`,
+				"/packages/1/runtimeHint":  `unknown field "runtimeHint"; did you mean "runtime_hint"?`,
+			},
+		},
+		{
+			name:     "nested argument field",
+			document: `{"packages": [{"package_arguments": [{"type": "named", "name": "--port", "valu": "8080"}]}]}`,
+			want: map[string]string{
+				"/packages/0/package_arguments/0/valu": `unknown field "valu"; did you mean "value"?`,
+			},
+		},
+		{
+			name:     "unknown _meta key",
+			document: `{"_meta": {"io.modelcontextprotocol.registry/publisher_provided": {"tool": "ci"}}}`,
+			want: map[string]string{
+				"/_meta/io.modelcontextprotocol.registry~1publisher_provided": `unknown field "io.modelcontextprotocol.registry/publisher_provided"; did you mean "io.modelcontextprotocol.registry/publisher-provided"?`,
+			},
+		},
+		{
+			name:     "unknown field without a likely match",
+			document: `{"name": "io.github.example/weather", "homepage": "https://weather.example.com"}`,
+			want: map[string]string{
+				"/homepage": `unknown field "homepage"`,
+			},
+		},
+		{
+			name:     "invalid JSON is left for decoding",
+			document: `{"name": `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validators.CheckUnknownFields([]byte(tt.document))
+			if len(tt.want) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, validators.ErrUnknownField))
+
+			got := map[string]string{}
+			for _, fieldErr := range validators.FieldErrors(err) {
+				assert.Equal(t, apiv0.ErrorCodeUnexpectedProperty, fieldErr.Code)
+				got[fieldErr.Field] = fieldErr.Error()
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckUnknownFields_NestedVariables(t *testing.T) {
+	err := validators.CheckUnknownFields([]byte(`{"packages": [{"runtime_arguments": [{"type": "named", "name": "--listen", "value": "{addr}",
+		"variables": {"addr": {"value": "{host}", "variables": {"host": {"default": "localhost"}}}}}]}]}`))
+	require.ErrorIs(t, err, validators.ErrNestedVariables)
+	fieldErrs := validators.FieldErrors(err)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, apiv0.ErrorCodeNestedVariables, fieldErrs[0].Code)
+	assert.Equal(t, "/packages/0/runtime_arguments/0/variables/addr/variables", fieldErrs[0].Field)
+	assert.Contains(t, fieldErrs[0].Error(), "packages[0].runtime_arguments[0].variables.addr is a variable")
+}
+
+func TestStripUnknownFields(t *testing.T) {
+	stripped, warnings, err := validators.StripUnknownFields([]byte(`{
+		"name": "io.github.example/weather",
+		"verison": "1.0.0",
+		"packages": [{"identifier": "@example/weather", "package_arguments": [{"type": "positional", "valu": "x"}], "build": 42}]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []apiv0.Warning{
+		{Code: apiv0.WarningUnknownField, Path: "packages[0].build", Message: `ignored unknown field "build"`},
+		{Code: apiv0.WarningUnknownField, Path: "packages[0].package_arguments[0].valu", Message: `ignored unknown field "valu"; did you mean "value"?`},
+		{Code: apiv0.WarningUnknownField, Path: "verison", Message: `ignored unknown field "verison"; did you mean "version"?`},
+	}, warnings)
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(stripped, &document))
+	assert.Equal(t, map[string]any{
+		"name":     "io.github.example/weather",
+		"packages": []any{map[string]any{"identifier": "@example/weather", "package_arguments": []any{map[string]any{"type": "positional"}}}},
+	}, document)
+	assert.NoError(t, validators.CheckUnknownFields(stripped))
+
+	// Nested variables are rejected rather than dropped
+	_, _, err = validators.StripUnknownFields([]byte(`{"remotes": [{"headers": [{"name": "X-Region", "value": "{region}",
+		"variables": {"region": {"variables": {"zone": {}}}}}]}], "verison": "1.0.0"}`))
+	require.ErrorIs(t, err, validators.ErrNestedVariables)
+	fieldErrs := validators.FieldErrors(err)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "/remotes/0/headers/0/variables/region/variables", fieldErrs[0].Field)
+
+	unchanged := []byte(`{"name": "io.github.example/weather"}`)
+	stripped, warnings, err = validators.StripUnknownFields(unchanged)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, unchanged, stripped)
+}
@@ -1,17 +1,25 @@
 package validators
 
 import (
+	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
 var (
+	// iconSizeRegex matches icon sizes in WIDTHxHEIGHT format, e.g. 48x48
+	iconSizeRegex = regexp.MustCompile(`^([1-9][0-9]{0,3})x([1-9][0-9]{0,3})$`)
+
 	// Regular expressions for validating repository URLs
 	// These regex patterns ensure the URL is in the format of a valid GitHub or GitLab repository
 	// For example:	// - GitHub: https://github.com/user/repo
 	githubURLRegex = regexp.MustCompile(`^https?://(www\.)?github\.com/[\w.-]+/[\w.-]+/?$`)
 	gitlabURLRegex = regexp.MustCompile(`^https?://(www\.)?gitlab\.com/[\w.-]+/[\w.-]+/?$`)
+
+	// numericIDRegex matches the numeric repository and project IDs used by GitHub and GitLab
+	numericIDRegex = regexp.MustCompile(`^[1-9][0-9]*$`)
 )
 
 // IsValidRepositoryURL checks if the given URL is valid for the specified repository source
@@ -22,6 +30,23 @@ func IsValidRepositoryURL(source RepositorySource, url string) bool {
 	case SourceGitLab:
 		return gitlabURLRegex.MatchString(url)
 	}
+	if configured, ok := configuredRepositorySource(source); ok {
+		return matchesRepositorySource(configured, url)
+	}
+	return false
+}
+
+// IsValidRepositoryID checks if the given ID has the format used by the specified repository source
+// (the numeric repository database ID for GitHub, the numeric project ID for GitLab). Configured
+// sources such as self-hosted GitLab and Gitea use numeric IDs too.
+func IsValidRepositoryID(source RepositorySource, id string) bool {
+	switch source {
+	case SourceGitHub, SourceGitLab:
+		return numericIDRegex.MatchString(id)
+	}
+	if _, ok := configuredRepositorySource(source); ok {
+		return numericIDRegex.MatchString(id)
+	}
 	return false
 }
 
@@ -30,6 +55,18 @@ func HasNoSpaces(s string) bool {
 	return !strings.Contains(s, " ")
 }
 
+// containsSuspiciousUnicode reports whether s contains characters that could make it look like a
+// different name: anything outside printable ASCII, including homoglyphs from other scripts,
+// zero-width and other invisible characters, and control characters
+func containsSuspiciousUnicode(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			return true
+		}
+	}
+	return false
+}
+
 // extractTemplateVariables extracts template variables from a URL string
 // e.g., "http://{host}:{port}/mcp" returns ["host", "port"]
 func extractTemplateVariables(url string) []string {
@@ -45,6 +82,40 @@ func extractTemplateVariables(url string) []string {
 	return variables
 }
 
+// parseTemplatePlaceholders strictly parses the {name} placeholders in s, returning
+// each one's exact text (including braces) in order of appearance.
+// Unclosed, nested, empty and unmatched braces are errors quoting the offending text.
+func parseTemplatePlaceholders(s string) ([]string, error) {
+	var placeholders []string
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '}':
+			return nil, fmt.Errorf("%w: unmatched \"}\" in %q", ErrMalformedTemplate, s[:i+1])
+		case '{':
+			end := strings.IndexAny(s[i+1:], "{}")
+			if end < 0 || s[i+1+end] == '{' {
+				unclosed := s[i:]
+				if end >= 0 {
+					unclosed = s[i : i+1+end]
+				}
+				return nil, fmt.Errorf("%w: unclosed placeholder %q", ErrMalformedTemplate, unclosed)
+			}
+			placeholder := s[i : i+end+2]
+			if strings.TrimSpace(placeholder[1:len(placeholder)-1]) == "" {
+				return nil, fmt.Errorf("%w: empty placeholder %q", ErrMalformedTemplate, placeholder)
+			}
+			placeholders = append(placeholders, placeholder)
+			i += end + 1
+		}
+	}
+	return placeholders, nil
+}
+
+// placeholderName returns the variable name of a placeholder, e.g. "{host}" returns "host"
+func placeholderName(placeholder string) string {
+	return placeholder[1 : len(placeholder)-1]
+}
+
 // replaceTemplateVariables replaces template variables with placeholder values for URL validation
 func replaceTemplateVariables(rawURL string) string {
 	// Replace common template variables with valid placeholder values for parsing
@@ -61,9 +132,15 @@ func replaceTemplateVariables(rawURL string) string {
 		result = strings.ReplaceAll(result, placeholder, replacement)
 	}
 	
-	// Handle any remaining {variable} patterns with generic placeholder
+	// Handle any remaining {variable} patterns with generic placeholder, or a port number for
+	// port-like names such as {http_port}
 	re := regexp.MustCompile(`\{[^}]+\}`)
-	result = re.ReplaceAllString(result, "placeholder")
+	result = re.ReplaceAllStringFunc(result, func(placeholder string) string {
+		if isPortLikeName(placeholderName(placeholder)) {
+			return "8080"
+		}
+		return "placeholder"
+	})
 	
 	return result
 }
@@ -93,36 +170,7 @@ func IsValidURL(rawURL string) bool {
 // IsValidSubfolderPath checks if a subfolder path is valid
 func IsValidSubfolderPath(path string) bool {
 	// Empty path is valid (subfolder is optional)
-	if path == "" {
-		return true
-	}
-
-	// Must not start with / (must be relative)
-	if strings.HasPrefix(path, "/") {
-		return false
-	}
-
-	// Must not end with / (clean path format)
-	if strings.HasSuffix(path, "/") {
-		return false
-	}
-
-	// Check for valid path characters (alphanumeric, dash, underscore, dot, forward slash)
-	validPathRegex := regexp.MustCompile(`^[a-zA-Z0-9\-_./]+$`)
-	if !validPathRegex.MatchString(path) {
-		return false
-	}
-
-	// Check that path segments are valid
-	segments := strings.Split(path, "/")
-	for _, segment := range segments {
-		// Disallow empty segments ("//"), current dir ("."), and parent dir ("..")
-		if segment == "" || segment == "." || segment == ".." {
-			return false
-		}
-	}
-
-	return true
+	return path == "" || validateSubfolderPath(path) == nil
 }
 
 // IsValidRemoteURL checks if a URL is valid for remotes (stricter than packages - no localhost allowed)
@@ -183,3 +231,39 @@ func IsValidTemplatedURL(rawURL string, availableVariables []string, allowTempla
 	
 	return true
 }
+
+// IsValidIconURL checks if an icon URL is an absolute https URL with a host
+func IsValidIconURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" && u.Host != ""
+}
+
+// IsValidDocumentationURL checks if a documentation URL is an absolute http or https URL with a host
+func IsValidDocumentationURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// IsValidIconSize checks if an icon size is "any" or WIDTHxHEIGHT within MaxIconDimension
+func IsValidIconSize(size string) bool {
+	if size == "any" {
+		return true
+	}
+	matches := iconSizeRegex.FindStringSubmatch(size)
+	if matches == nil {
+		return false
+	}
+	for _, dimension := range matches[1:] {
+		n, err := strconv.Atoi(dimension)
+		if err != nil || n > Rules.MaxIconDimension {
+			return false
+		}
+	}
+	return true
+}
@@ -7,8 +7,11 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/validators/registries"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 	"github.com/modelcontextprotocol/registry/pkg/model"
 )
@@ -16,32 +19,84 @@ import (
 func ValidateServerJSON(serverJSON *apiv0.ServerJSON) error {
 	// Validate server name exists and format
 	if _, err := parseServerName(*serverJSON); err != nil {
-		return err
+		return fieldError("/name", err)
 	}
 
 	// Validate repository
 	if err := validateRepository(&serverJSON.Repository); err != nil {
+		return fieldError("/repository", err)
+	}
+
+	// Validate display metadata (title and icons)
+	if err := validateTitle(serverJSON.Title); err != nil {
+		return fieldError("/title", err)
+	}
+	if err := validateIcons(serverJSON.Icons); err != nil {
+		return fieldError("/icons", err)
+	}
+
+	// Validate documentation (documentation URL and README)
+	if err := validateDocumentation(serverJSON); err != nil {
 		return err
 	}
 
+	// Validate the license expression
+	if err := validateLicense(serverJSON.License); err != nil {
+		return fieldError("/license", err)
+	}
+
+	// Validate the contact
+	if err := validateContact(serverJSON.Contact); err != nil {
+		return fieldError("/contact", err)
+	}
+
+	// Validate the fork reference; that the server exists is checked on publish
+	if err := validateForkOf(serverJSON.Name, serverJSON.ForkOf); err != nil {
+		return fieldError("/forkOf", err)
+	}
+
+	// Validate the declared protocol revision
+	if err := validateMCPVersion(serverJSON.MCPVersion); err != nil {
+		return fieldError("/mcpVersion", err)
+	}
+
 	// Validate all packages (basic field validation)
 	// Detailed package validation (including registry checks) is done during publish
-	for _, pkg := range serverJSON.Packages {
+	for i, pkg := range serverJSON.Packages {
 		if err := validatePackageField(&pkg); err != nil {
-			return err
+			return fieldError(JSONPointer("packages", i), err)
 		}
+		if err := validatePackagePaths(fmt.Sprintf("packages[%d]", i), &pkg); err != nil {
+			return fieldError(JSONPointer("packages", i), err)
+		}
+	}
+
+	// Validate the variables declared by package and remote inputs
+	if err := validateVariables(serverJSON); err != nil {
+		return err
+	}
+
+	// Reject packages listed more than once
+	if err := validateNoDuplicatePackages(serverJSON.Packages); err != nil {
+		return err
 	}
 
 	// Validate all remotes
-	for _, remote := range serverJSON.Remotes {
+	if err := validateNoDuplicateRemotes(serverJSON.Remotes); err != nil {
+		return err
+	}
+	for i, remote := range serverJSON.Remotes {
 		if err := validateRemoteTransport(&remote); err != nil {
-			return err
+			return fieldError(JSONPointer("remotes", i), err)
+		}
+		if err := validateAuthentication(remote.Authentication, remote.Headers); err != nil {
+			return fieldError(JSONPointer("remotes", i, "authentication"), err)
 		}
 	}
 
 	// Validate reverse-DNS namespace matching for remote URLs
 	if err := validateRemoteNamespaceMatch(*serverJSON); err != nil {
-		return err
+		return fieldError("/remotes", err)
 	}
 
 	return nil
@@ -56,47 +111,234 @@ func validateRepository(obj *model.Repository) error {
 	// validate the repository source
 	repoSource := RepositorySource(obj.Source)
 	if !IsValidRepositoryURL(repoSource, obj.URL) {
-		return fmt.Errorf("%w: %s", ErrInvalidRepositoryURL, obj.URL)
+		return fieldError("/repository/url", fmt.Errorf("%w: %s", ErrInvalidRepositoryURL, obj.URL))
+	}
+
+	// validate the repository ID format if present
+	if obj.ID != "" && !IsValidRepositoryID(repoSource, obj.ID) {
+		return fieldError("/repository/id", fmt.Errorf("%w: %s (%s repository IDs are numeric)", ErrInvalidRepositoryID, obj.ID, obj.Source))
 	}
 
 	// validate subfolder if present
-	if obj.Subfolder != "" && !IsValidSubfolderPath(obj.Subfolder) {
-		return fmt.Errorf("%w: %s", ErrInvalidSubfolderPath, obj.Subfolder)
+	if obj.Subfolder != "" {
+		if err := validateSubfolderPath(obj.Subfolder); err != nil {
+			return fieldError("/repository/subfolder", fmt.Errorf("%w: repository.subfolder %w: %s", ErrInvalidSubfolderPath, err, obj.Subfolder))
+		}
 	}
 
 	return nil
 }
 
+func validateTitle(title string) error {
+	if title == "" {
+		return nil
+	}
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("%w: title cannot be blank", ErrInvalidTitle)
+	}
+	if utf8.RuneCountInString(title) > Rules.MaxTitleLength {
+		return fmt.Errorf("%w: title exceeds %d characters", ErrInvalidTitle, Rules.MaxTitleLength)
+	}
+	for _, r := range title {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: title cannot contain control characters", ErrInvalidTitle)
+		}
+	}
+	return nil
+}
+
+func validateIcons(icons []model.Icon) error {
+	if len(icons) > Rules.MaxIcons {
+		return fmt.Errorf("%w: %d icons provided, at most %d allowed", ErrTooManyIcons, len(icons), Rules.MaxIcons)
+	}
+	for i, icon := range icons {
+		if !IsValidIconURL(icon.Src) {
+			return fieldError(JSONPointer("icons", i, "src"), fmt.Errorf("%w: icon %d: %s (must be an https URL)", ErrInvalidIconURL, i, icon.Src))
+		}
+		if !slices.Contains(Rules.IconMimeTypes, icon.MimeType) {
+			return fieldError(JSONPointer("icons", i, "mimeType"), fmt.Errorf("%w: icon %d: %q", ErrUnsupportedIconMimeType, i, icon.MimeType))
+		}
+		for j, size := range icon.Sizes {
+			if !IsValidIconSize(size) {
+				return fieldError(JSONPointer("icons", i, "sizes", j), fmt.Errorf("%w: icon %d: %q (expected WIDTHxHEIGHT up to %d, or 'any')", ErrInvalidIconSize, i, size, Rules.MaxIconDimension))
+			}
+		}
+	}
+	return nil
+}
+
+func validateDocumentation(serverJSON *apiv0.ServerJSON) error {
+	if serverJSON.DocumentationURL != "" && !IsValidDocumentationURL(serverJSON.DocumentationURL) {
+		return fieldError("/documentationUrl", fmt.Errorf("%w: %s (must be an http or https URL)", ErrInvalidDocumentationURL, serverJSON.DocumentationURL))
+	}
+	if serverJSON.WebsiteURL != "" && !IsValidDocumentationURL(serverJSON.WebsiteURL) {
+		return fieldError("/websiteUrl", fmt.Errorf("%w: %s (must be an http or https URL)", ErrInvalidWebsiteURL, serverJSON.WebsiteURL))
+	}
+	if len(serverJSON.Readme) > Rules.MaxReadmeBytes {
+		return fieldError("/readme", fmt.Errorf("%w: %d bytes, at most %d allowed", ErrReadmeTooLarge, len(serverJSON.Readme), Rules.MaxReadmeBytes))
+	}
+	if len(serverJSON.ReleaseNotes) > Rules.MaxReleaseNotesBytes {
+		return fieldError("/releaseNotes", fmt.Errorf("%w: %d bytes, at most %d allowed", ErrReleaseNotesTooLarge, len(serverJSON.ReleaseNotes), Rules.MaxReleaseNotesBytes))
+	}
+	return nil
+}
+
+// validateContact checks the contact, free text such as an email address or a support URL
+func validateContact(contact string) error {
+	if contact == "" {
+		return nil
+	}
+	if strings.TrimSpace(contact) == "" {
+		return fmt.Errorf("%w: contact cannot be blank", ErrInvalidContact)
+	}
+	if utf8.RuneCountInString(contact) > Rules.MaxContactLength {
+		return fmt.Errorf("%w: contact exceeds %d characters", ErrInvalidContact, Rules.MaxContactLength)
+	}
+	for _, r := range contact {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: contact cannot contain control characters", ErrInvalidContact)
+		}
+	}
+	return nil
+}
+
+// ValidateNamespaceDefaults checks the fields a namespace's settings give defaults for, license,
+// categories, websiteUrl and contact, as publishing checks them, so versions that inherit them
+// are not rejected for them
+func ValidateNamespaceDefaults(defaults *apiv0.ServerJSON, taxonomy []string) error {
+	if err := validateLicense(defaults.License); err != nil {
+		return fieldError("/license", err)
+	}
+	if err := validateCategories(defaults.Categories, taxonomy); err != nil {
+		return fieldError("/categories", err)
+	}
+	if defaults.WebsiteURL != "" && !IsValidDocumentationURL(defaults.WebsiteURL) {
+		return fieldError("/websiteUrl", fmt.Errorf("%w: %s (must be an http or https URL)", ErrInvalidWebsiteURL, defaults.WebsiteURL))
+	}
+	if err := validateContact(defaults.Contact); err != nil {
+		return fieldError("/contact", err)
+	}
+	return nil
+}
+
+// validateCategories checks categories against the configured taxonomy.
+// An empty taxonomy accepts any category.
+func validateCategories(categories []string, taxonomy []string) error {
+	if len(categories) > Rules.MaxCategories {
+		return fmt.Errorf("%w: %d categories provided, at most %d allowed", ErrTooManyCategories, len(categories), Rules.MaxCategories)
+	}
+
+	seen := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		if seen[category] {
+			return fmt.Errorf("%w: %s", ErrDuplicateCategory, category)
+		}
+		seen[category] = true
+
+		if len(taxonomy) > 0 && !slices.Contains(taxonomy, category) {
+			return fmt.Errorf("%w: %s (allowed: %s)", ErrUnknownCategory, category, strings.Join(taxonomy, ", "))
+		}
+	}
+	return nil
+}
+
+// validateNoDuplicatePackages rejects packages with the same registry type, identifier and version
+func validateNoDuplicatePackages(packages []model.Package) error {
+	type packageKey struct{ registryType, identifier, version string }
+	seen := make(map[packageKey]int, len(packages))
+	for i, pkg := range packages {
+		key := packageKey{pkg.RegistryType, pkg.Identifier, pkg.Version}
+		if first, ok := seen[key]; ok {
+			return fieldError(JSONPointer("packages", i), fmt.Errorf("%w: packages %d and %d are both %s package %s version %s",
+				ErrDuplicatePackage, first, i, pkg.RegistryType, pkg.Identifier, pkg.Version))
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// validatePackageVersionConflicts finds packages with the same registry type and identifier but
+// different versions, which leaves install tooling to guess which one to use. Conflicts are
+// added to ctx as warnings, or returned as an error when strict is set.
+func validatePackageVersionConflicts(ctx context.Context, packages []model.Package, strict bool) error {
+	type packageKey struct{ registryType, identifier string }
+	seen := make(map[packageKey]int, len(packages))
+	for i, pkg := range packages {
+		key := packageKey{pkg.RegistryType, pkg.Identifier}
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = i
+			continue
+		}
+		if packages[first].Version == pkg.Version {
+			continue
+		}
+
+		err := fmt.Errorf("%w: packages %d and %d are both %s package %s, with versions %s and %s",
+			ErrConflictingPackageVersions, first, i, pkg.RegistryType, pkg.Identifier, packages[first].Version, pkg.Version)
+		if strict {
+			return fieldError(JSONPointer("packages", i, "version"), err)
+		}
+		Warn(ctx, apiv0.Warning{Code: apiv0.WarningConflictingPackageVersions, Path: fmt.Sprintf("packages[%d].version", i), Message: err.Error()})
+	}
+	return nil
+}
+
+// validateNoDuplicateRemotes rejects remotes that share a URL
+func validateNoDuplicateRemotes(remotes []model.Transport) error {
+	seen := make(map[string]int, len(remotes))
+	for i, remote := range remotes {
+		if first, ok := seen[remote.URL]; ok {
+			return fieldError(JSONPointer("remotes", i, "url"), fmt.Errorf("%w: remotes %d and %d both use %s", ErrDuplicateRemoteURL, first, i, remote.URL))
+		}
+		seen[remote.URL] = i
+	}
+	return nil
+}
+
 func validatePackageField(obj *model.Package) error {
 	if !HasNoSpaces(obj.Identifier) {
 		return ErrPackageNameHasSpaces
 	}
 
+	// OCI references are checked against the image reference grammar before any registry lookup
+	if obj.RegistryType == model.RegistryTypeOCI {
+		if _, err := registries.ParseOCIReference(obj.Identifier, obj.Version); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidOCIReference, err)
+		}
+	}
+
+	// MCPB release URLs must point at the release of the declared version
+	if err := registries.ValidateMCPBReleaseTag(*obj); err != nil {
+		return fmt.Errorf("%w: %w", ErrReleaseTagMismatch, err)
+	}
+
 	// Validate runtime arguments
 	for _, arg := range obj.RuntimeArguments {
-		if err := validateArgument(&arg); err != nil {
+		if err := ValidateArgument(&arg); err != nil {
 			return fmt.Errorf("invalid runtime argument: %w", err)
 		}
 	}
 
 	// Validate package arguments
 	for _, arg := range obj.PackageArguments {
-		if err := validateArgument(&arg); err != nil {
+		if err := ValidateArgument(&arg); err != nil {
 			return fmt.Errorf("invalid package argument: %w", err)
 		}
 	}
 
 	// Validate transport with template variable support
 	availableVariables := collectAvailableVariables(obj)
-	if err := validatePackageTransport(&obj.Transport, availableVariables); err != nil {
+	if err := validatePackageTransport(&obj.Transport, availableVariables, collectSecretVariables(obj), collectVariableInputs(obj)); err != nil {
 		return fmt.Errorf("invalid transport: %w", err)
 	}
 
 	return nil
 }
 
-// validateArgument validates argument details
-func validateArgument(obj *model.Argument) error {
+// ValidateArgument validates argument details. mcp-publisher init also uses it to check
+// arguments as they are entered.
+func ValidateArgument(obj *model.Argument) error {
 	if obj.Type == model.ArgumentTypeNamed {
 		// Validate named argument name format
 		if err := validateNamedArgumentName(obj.Name); err != nil {
@@ -173,9 +415,130 @@ func collectAvailableVariables(pkg *model.Package) []string {
 	return variables
 }
 
+// collectSecretVariables collects the template variables of a package that are marked as secret
+func collectSecretVariables(pkg *model.Package) map[string]bool {
+	secrets := make(map[string]bool)
+	for _, env := range pkg.EnvironmentVariables {
+		if env.IsSecret {
+			secrets[env.Name] = true
+		}
+	}
+	for _, arg := range append(slices.Clone(pkg.RuntimeArguments), pkg.PackageArguments...) {
+		if !arg.IsSecret {
+			continue
+		}
+		if arg.Name != "" {
+			secrets[arg.Name] = true
+		}
+		if arg.ValueHint != "" {
+			secrets[arg.ValueHint] = true
+		}
+	}
+	return secrets
+}
+
+// collectVariableInputs collects the declarations of each template variable of a package, by
+// environment variable name and by argument name and value hint
+func collectVariableInputs(pkg *model.Package) map[string][]model.Input {
+	inputs := make(map[string][]model.Input)
+	for _, env := range pkg.EnvironmentVariables {
+		inputs[env.Name] = append(inputs[env.Name], env.Input)
+	}
+	for _, arg := range append(slices.Clone(pkg.RuntimeArguments), pkg.PackageArguments...) {
+		if arg.Name != "" {
+			inputs[arg.Name] = append(inputs[arg.Name], arg.Input)
+		}
+		if arg.ValueHint != "" && arg.ValueHint != arg.Name {
+			inputs[arg.ValueHint] = append(inputs[arg.ValueHint], arg.Input)
+		}
+	}
+	return inputs
+}
+
+// isResolvableInput reports whether a client can fill in a variable: it has a value or default,
+// or is required and described so the client can ask the user for it
+func isResolvableInput(input model.Input) bool {
+	return input.Value != "" || input.Default != "" || (input.IsRequired && strings.TrimSpace(input.Description) != "")
+}
+
+// validateURLVariableInputs checks that a client can fill in every placeholder of a package
+// transport URL, which it needs to do before it can connect to the server
+func validateURLVariableInputs(rawURL string, variableInputs map[string][]model.Input) error {
+	placeholders, err := parseTemplatePlaceholders(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w in URL %s", err, rawURL)
+	}
+	for _, placeholder := range placeholders {
+		if !slices.ContainsFunc(variableInputs[placeholderName(placeholder)], isResolvableInput) {
+			return fmt.Errorf("%w: placeholder %s in URL %s needs a default, or is_required with a description",
+				ErrUnresolvedVariable, placeholder, rawURL)
+		}
+	}
+	return nil
+}
+
+// validateURLPlaceholders checks that every placeholder in a package transport URL is well formed,
+// declared by the package, and not a secret, which would leak into logs and history with the URL
+func validateURLPlaceholders(rawURL string, availableVariables []string, secretVariables map[string]bool) error {
+	placeholders, err := parseTemplatePlaceholders(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w in URL %s", err, rawURL)
+	}
+
+	var undefined []string
+	for _, placeholder := range placeholders {
+		if !slices.Contains(availableVariables, placeholderName(placeholder)) {
+			undefined = append(undefined, placeholder)
+		}
+	}
+	if len(undefined) > 0 {
+		return fmt.Errorf("%w: template variables in URL %s reference undefined variables %s. Available variables: %v",
+			ErrInvalidRemoteURL, rawURL, strings.Join(undefined, ", "), availableVariables)
+	}
+
+	for _, placeholder := range placeholders {
+		if secretVariables[placeholderName(placeholder)] {
+			return fmt.Errorf("%w: placeholder %s in URL %s", ErrSecretInURL, placeholder, rawURL)
+		}
+	}
+	return nil
+}
+
+// validateTransportHeaders checks the placeholders in transport header values. Each must be well formed
+// and declared in the header's variables (or, for packages, by the package itself).
+func validateTransportHeaders(headers []model.KeyValueInput, packageVariables []string) error {
+	if err := validateHeaderSecrets(headers); err != nil {
+		return err
+	}
+	if err := validateHeaderNames(headers); err != nil {
+		return err
+	}
+	for _, header := range headers {
+		placeholders, err := parseTemplatePlaceholders(header.Value)
+		if err != nil {
+			return fmt.Errorf("%w in header %s", err, header.Name)
+		}
+
+		for _, placeholder := range placeholders {
+			name := placeholderName(placeholder)
+			if _, ok := header.Variables[name]; !ok && !slices.Contains(packageVariables, name) {
+				return fmt.Errorf("%w: placeholder %s in header %s", ErrUndeclaredVariable, placeholder, header.Name)
+			}
+		}
+	}
+	return nil
+}
+
 // validatePackageTransport validates a package's transport with templating support
-func validatePackageTransport(transport *model.Transport, availableVariables []string) error {
+func validatePackageTransport(transport *model.Transport, availableVariables []string, secretVariables map[string]bool, variableInputs map[string][]model.Input) error {
 	// Validate transport type is supported
+	if !slices.Contains(Rules.PackageTransports, transport.Type) {
+		return fmt.Errorf("unsupported transport type: %s", transport.Type)
+	}
+	// Clients run packages themselves, so only remotes say how to authenticate
+	if transport.Authentication != nil {
+		return fmt.Errorf("%w: only remotes can declare authentication", ErrInvalidAuthentication)
+	}
 	switch transport.Type {
 	case model.TransportTypeStdio:
 		// Validate that URL is empty for stdio transport
@@ -188,17 +551,17 @@ func validatePackageTransport(transport *model.Transport, availableVariables []s
 		if transport.URL == "" {
 			return fmt.Errorf("url is required for %s transport type", transport.Type)
 		}
-		// Validate URL format with template variable support
+		// Validate placeholders strictly before the URL format, so errors name the exact placeholder
+		if err := validateURLPlaceholders(transport.URL, availableVariables, secretVariables); err != nil {
+			return err
+		}
 		if !IsValidTemplatedURL(transport.URL, availableVariables, true) {
-			// Check if it's a template variable issue or basic URL issue
-			templateVars := extractTemplateVariables(transport.URL)
-			if len(templateVars) > 0 {
-				return fmt.Errorf("%w: template variables in URL %s reference undefined variables. Available variables: %v", 
-					ErrInvalidRemoteURL, transport.URL, availableVariables)
-			}
 			return fmt.Errorf("%w: %s", ErrInvalidRemoteURL, transport.URL)
 		}
-		return nil
+		if err := validateURLVariableInputs(transport.URL, variableInputs); err != nil {
+			return err
+		}
+		return validateTransportHeaders(transport.Headers, availableVariables)
 	default:
 		return fmt.Errorf("unsupported transport type: %s", transport.Type)
 	}
@@ -207,6 +570,9 @@ func validatePackageTransport(transport *model.Transport, availableVariables []s
 // validateRemoteTransport validates a remote transport (no templating allowed)
 func validateRemoteTransport(obj *model.Transport) error {
 	// Validate transport type is supported - remotes only support streamable-http and sse
+	if !slices.Contains(Rules.RemoteTransports, obj.Type) {
+		return fmt.Errorf("unsupported transport type for remotes: %s (only streamable-http and sse are supported)", obj.Type)
+	}
 	switch obj.Type {
 	case model.TransportTypeStreamableHTTP, model.TransportTypeSSE:
 		// URL is required for streamable-http and sse
@@ -214,20 +580,23 @@ func validateRemoteTransport(obj *model.Transport) error {
 			return fmt.Errorf("url is required for %s transport type", obj.Type)
 		}
 		// Validate URL format (no templates allowed for remotes, no localhost)
+		if _, err := parseTemplatePlaceholders(obj.URL); err != nil {
+			return fmt.Errorf("%w in URL %s", err, obj.URL)
+		}
 		if !IsValidRemoteURL(obj.URL) {
 			return fmt.Errorf("%w: %s", ErrInvalidRemoteURL, obj.URL)
 		}
-		return nil
+		return validateTransportHeaders(obj.Headers, nil)
 	default:
 		return fmt.Errorf("unsupported transport type for remotes: %s (only streamable-http and sse are supported)", obj.Type)
 	}
 }
 
 // ValidatePublishRequest validates a complete publish request including extensions
-func ValidatePublishRequest(req apiv0.ServerJSON, cfg *config.Config) error {
+func ValidatePublishRequest(ctx context.Context, req apiv0.ServerJSON, cfg *config.Config) error {
 	// Validate publisher extensions in _meta
 	if err := validatePublisherExtensions(req); err != nil {
-		return err
+		return fieldError("/_meta", err)
 	}
 
 	// Validate the server detail (includes all nested validation)
@@ -235,19 +604,50 @@ func ValidatePublishRequest(req apiv0.ServerJSON, cfg *config.Config) error {
 		return err
 	}
 
+	// Reject lookalike names built from homoglyphs or invisible characters
+	if containsSuspiciousUnicode(req.Name) {
+		return fieldError("/name", fmt.Errorf("%w: %q", ErrSuspiciousUnicode, req.Name))
+	}
+
+	// The pending and rejected statuses are set by the registry when holding a version for admin approval
+	if req.Status.Hidden() {
+		return fieldError("/status", fmt.Errorf("status %s cannot be set by publishers", req.Status))
+	}
+
+	// Validate categories against the registry's taxonomy
+	if err := validateCategories(req.Categories, cfg.ServerCategories); err != nil {
+		return fieldError("/categories", err)
+	}
+
+	// Warn about (or, in strict mode, reject) the same package listed with different versions
+	if err := validatePackageVersionConflicts(ctx, req.Packages, cfg.StrictPackageVersions); err != nil {
+		return err
+	}
+
+	// Warn about header values that might be credentials without a known token format
+	warnSuspiciousHeaders(ctx, req)
+	warnAuthorizationHeaders(ctx, req)
+	warnMutableImageTags(ctx, req)
+	warnNumericURLVariables(ctx, req)
+	warnUnusedVariables(ctx, req)
+
 	// Validate registry ownership for all packages if validation is enabled and server is not deleted
 	if cfg.EnableRegistryValidation && req.Status != model.StatusDeleted {
-		ctx := context.Background()
 		for i, pkg := range req.Packages {
 			if err := ValidatePackage(ctx, pkg, req.Name); err != nil {
-				return fmt.Errorf("registry validation failed for package %d (%s): %w", i, pkg.Identifier, err)
+				return fieldError(JSONPointer("packages", i), fmt.Errorf("registry validation failed for package %d (%s): %w", i, pkg.Identifier, err))
 			}
 		}
+		if err := validatePackageLicenses(ctx, req); err != nil {
+			return fieldError("/license", err)
+		}
 	}
 
 	return nil
 }
 
+// validatePublisherExtensions checks the _meta extensions a publisher sent. Requests in the
+// legacy x-publisher format are normalized to _meta before they get here, so this is the only check.
 func validatePublisherExtensions(req apiv0.ServerJSON) error {
 	const maxExtensionSize = 4 * 1024 // 4KB limit
 
@@ -255,17 +655,17 @@ func validatePublisherExtensions(req apiv0.ServerJSON) error {
 	if req.Meta != nil && req.Meta.PublisherProvided != nil {
 		extensionsJSON, err := json.Marshal(req.Meta.PublisherProvided)
 		if err != nil {
-			return fmt.Errorf("failed to marshal _meta.io.modelcontextprotocol.registry/publisher-provided extension: %w", err)
+			return fmt.Errorf("failed to marshal _meta.%s extension: %w", apiv0.PublisherProvidedMetaKey, err)
 		}
 		if len(extensionsJSON) > maxExtensionSize {
-			return fmt.Errorf("_meta.io.modelcontextprotocol.registry/publisher-provided extension exceeds 4KB limit (%d bytes)", len(extensionsJSON))
+			return fmt.Errorf("_meta.%s extension exceeds 4KB limit (%d bytes)", apiv0.PublisherProvidedMetaKey, len(extensionsJSON))
 		}
 	}
 
 	if req.Meta != nil {
 		// Validate that only publisher-provided data is allowed in _meta during publish (no official registry metadata should be present)
 		if req.Meta.Official != nil {
-			return fmt.Errorf("official registry metadata '_meta.io.modelcontextprotocol.registry/official' is not allowed during publish")
+			return fmt.Errorf("official registry metadata '_meta.%s' is not allowed during publish", apiv0.OfficialMetaKey)
 		}
 	}
 
@@ -279,12 +679,10 @@ func parseServerName(serverJSON apiv0.ServerJSON) (string, error) {
 	}
 
 	// Validate format: dns-namespace/name
-	if !strings.Contains(name, "/") {
-		return "", fmt.Errorf("server name must be in format 'dns-namespace/name' (e.g., 'com.example.api/server')")
-	}
-
-	parts := strings.SplitN(name, "/", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+	if !serverNameRegex.MatchString(name) {
+		if !strings.Contains(name, "/") {
+			return "", fmt.Errorf("server name must be in format 'dns-namespace/name' (e.g., 'com.example.api/server')")
+		}
 		return "", fmt.Errorf("server name must be in format 'dns-namespace/name' with non-empty namespace and name parts")
 	}
 
@@ -0,0 +1,114 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// variableNameRegex matches the names of the variables an input declares. Values refer to them
+// as {name}, so names are plain tokens that read the same in every template.
+var variableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// inputWithVariables is an input that may declare variables, at its path in server.json
+type inputWithVariables struct {
+	path  []any
+	input *model.InputWithVariables
+}
+
+// collectInputsWithVariables returns every input of a server that may declare variables: the
+// arguments and environment variables of packages, and the headers of package and remote transports
+func collectInputsWithVariables(server *apiv0.ServerJSON) []inputWithVariables {
+	var inputs []inputWithVariables
+	add := func(input *model.InputWithVariables, path ...any) {
+		inputs = append(inputs, inputWithVariables{path: path, input: input})
+	}
+	for i := range server.Packages {
+		pkg := &server.Packages[i]
+		for j := range pkg.RuntimeArguments {
+			add(&pkg.RuntimeArguments[j].InputWithVariables, "packages", i, "runtime_arguments", j)
+		}
+		for j := range pkg.PackageArguments {
+			add(&pkg.PackageArguments[j].InputWithVariables, "packages", i, "package_arguments", j)
+		}
+		for j := range pkg.EnvironmentVariables {
+			add(&pkg.EnvironmentVariables[j].InputWithVariables, "packages", i, "environment_variables", j)
+		}
+		for j := range pkg.Transport.Headers {
+			add(&pkg.Transport.Headers[j].InputWithVariables, "packages", i, "transport", "headers", j)
+		}
+	}
+	for i := range server.Remotes {
+		for j := range server.Remotes[i].Headers {
+			add(&server.Remotes[i].Headers[j].InputWithVariables, "remotes", i, "headers", j)
+		}
+	}
+	return inputs
+}
+
+// validateVariables checks the variables every input of a server declares. Variables cannot
+// declare variables of their own; the model has no room for them, and CheckUnknownFields
+// rejects them in documents.
+func validateVariables(server *apiv0.ServerJSON) error {
+	for _, input := range collectInputsWithVariables(server) {
+		if err := validateInputVariables(input.path, input.input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateInputVariables checks the variables of the input at path: there are at most
+// Rules.MaxInputVariables, each named by a token and valid as an input in its own right
+func validateInputVariables(path []any, input *model.InputWithVariables) error {
+	variablesPath := append(slices.Clone(path), "variables")
+	if len(input.Variables) > Rules.MaxInputVariables {
+		return fieldError(JSONPointer(variablesPath...), fmt.Errorf("%w: %s declares %d variables, at most %d allowed",
+			ErrTooManyVariables, warningPath(path), len(input.Variables), Rules.MaxInputVariables))
+	}
+	for _, name := range slices.Sorted(maps.Keys(input.Variables)) {
+		variablePath := append(slices.Clone(variablesPath), name)
+		if !variableNameRegex.MatchString(name) {
+			return fieldError(JSONPointer(variablePath...), fmt.Errorf("%w: %s (names start with a letter or '_', followed by letters, digits, '_' or '-')",
+				ErrInvalidVariableName, warningPath(variablePath)))
+		}
+		variable := input.Variables[name]
+		if err := validateInputPaths(warningPath(variablePath), &variable); err != nil {
+			return fieldError(JSONPointer(variablePath...), err)
+		}
+	}
+	return nil
+}
+
+// warnUnusedVariables adds a warning to ctx for each variable that neither the value nor the
+// default of its input refers to, which clients would prompt for and then throw away
+func warnUnusedVariables(ctx context.Context, server apiv0.ServerJSON) {
+	for _, input := range collectInputsWithVariables(&server) {
+		if len(input.input.Variables) == 0 {
+			continue
+		}
+		used := make(map[string]bool)
+		for _, value := range []string{input.input.Value, input.input.Default} {
+			// A malformed template uses none of its variables
+			placeholders, _ := parseTemplatePlaceholders(value)
+			for _, placeholder := range placeholders {
+				used[placeholderName(placeholder)] = true
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(input.input.Variables)) {
+			if used[name] {
+				continue
+			}
+			Warn(ctx, apiv0.Warning{
+				Code:    apiv0.WarningUnusedVariable,
+				Path:    warningPath(append(slices.Clone(input.path), "variables", name)),
+				Message: fmt.Sprintf("variable %s is not used by the value or default of its input; refer to it as {%s}, or remove it", name, name),
+			})
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package validators_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// serverWithVariables returns a server whose package has a stdio transport and a named argument
+// with value and variables, after two other runtime arguments
+func serverWithVariables(value string, variables map[string]model.Input) apiv0.ServerJSON {
+	return apiv0.ServerJSON{
+		Name:        "com.example/test-server",
+		Description: "A test server",
+		Version:     "1.0.0",
+		Packages: []model.Package{{
+			RegistryType: model.RegistryTypeNPM,
+			Identifier:   "@example/server",
+			Version:      "1.0.0",
+			Transport:    model.Transport{Type: model.TransportTypeStdio},
+			RuntimeArguments: []model.Argument{
+				{Type: model.ArgumentTypePositional, InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "--verbose"}}},
+				{Type: model.ArgumentTypePositional, InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "--quiet"}}},
+				{
+					Type:               model.ArgumentTypeNamed,
+					Name:               "--listen",
+					InputWithVariables: model.InputWithVariables{Input: model.Input{Value: value}, Variables: variables},
+				},
+			},
+		}},
+	}
+}
+
+func TestValidate_InputVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		variables map[string]model.Input
+		wantErr   error
+		wantField string
+		wantMsg   string
+	}{
+		{
+			name:      "valid variables",
+			value:     "{host}:{http_port}",
+			variables: map[string]model.Input{"host": {Default: "localhost"}, "http_port": {Format: model.FormatNumber, Default: "8080"}},
+		},
+		{
+			name:      "names may use dashes",
+			value:     "{listen-port}",
+			variables: map[string]model.Input{"listen-port": {Default: "8080"}},
+		},
+		{
+			name:      "name with a space",
+			value:     "{my port}",
+			variables: map[string]model.Input{"my port": {Default: "8080"}},
+			wantErr:   validators.ErrInvalidVariableName,
+			wantField: "/packages/0/runtime_arguments/2/variables/my port",
+			wantMsg:   "packages[0].runtime_arguments[2].variables.my port",
+		},
+		{
+			name:      "name with braces",
+			value:     "{port}",
+			variables: map[string]model.Input{"{port}": {Default: "8080"}},
+			wantErr:   validators.ErrInvalidVariableName,
+			wantField: "/packages/0/runtime_arguments/2/variables/{port}",
+		},
+		{
+			name:      "name starting with a digit",
+			value:     "{1port}",
+			variables: map[string]model.Input{"1port": {Default: "8080"}},
+			wantErr:   validators.ErrInvalidVariableName,
+		},
+		{
+			name:      "empty name",
+			value:     "localhost",
+			variables: map[string]model.Input{"": {Default: "8080"}},
+			wantErr:   validators.ErrInvalidVariableName,
+		},
+		{
+			name:      "variable default with traversal",
+			value:     "{dir}",
+			variables: map[string]model.Input{"dir": {Default: "../../etc"}},
+			wantErr:   validators.ErrInvalidFilePath,
+			wantField: "/packages/0/runtime_arguments/2/variables/dir",
+			wantMsg:   `packages[0].runtime_arguments[2].variables.dir.default must not contain ".." segments`,
+		},
+		{
+			name:      "absolute file path variable",
+			value:     "{dir}",
+			variables: map[string]model.Input{"dir": {Format: model.FormatFilePath, Value: "/var/data"}},
+			wantErr:   validators.ErrInvalidFilePath,
+			wantField: "/packages/0/runtime_arguments/2/variables/dir",
+			wantMsg:   "packages[0].runtime_arguments[2].variables.dir.value must be a relative path",
+		},
+		{
+			name:      "absolute file path variable that allows it",
+			value:     "{dir}",
+			variables: map[string]model.Input{"dir": {Format: model.FormatFilePath, Value: "/var/data", AllowAbsolute: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serverWithVariables(tt.value, tt.variables)
+			err := validators.ValidateServerJSON(&server)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantField != "" {
+				fieldErrs := validators.FieldErrors(err)
+				require.Len(t, fieldErrs, 1)
+				assert.Equal(t, tt.wantField, fieldErrs[0].Field)
+			}
+			if tt.wantMsg != "" {
+				assert.Contains(t, err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestValidate_InputVariablesLimit(t *testing.T) {
+	variables := make(map[string]model.Input, validators.MaxInputVariables+1)
+	value := ""
+	for i := range validators.MaxInputVariables {
+		name := "v" + strconv.Itoa(i)
+		variables[name] = model.Input{Default: "x"}
+		value += "{" + name + "}"
+	}
+	server := serverWithVariables(value, variables)
+	require.NoError(t, validators.ValidateServerJSON(&server))
+
+	variables["extra"] = model.Input{Default: "x"}
+	err := validators.ValidateServerJSON(&server)
+	require.ErrorIs(t, err, validators.ErrTooManyVariables)
+	fieldErrs := validators.FieldErrors(err)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, apiv0.ErrorCodeTooManyVariables, fieldErrs[0].Code)
+	assert.Equal(t, "/packages/0/runtime_arguments/2/variables", fieldErrs[0].Field)
+	assert.Contains(t, err.Error(), "packages[0].runtime_arguments[2] declares 17 variables, at most 16 allowed")
+}
+
+func TestValidate_HeaderVariables(t *testing.T) {
+	server := apiv0.ServerJSON{
+		Name:        "com.example/test-server",
+		Description: "A test server",
+		Version:     "1.0.0",
+		Remotes: []model.Transport{{
+			Type: model.TransportTypeStreamableHTTP,
+			URL:  "https://example.com/mcp",
+			Headers: []model.KeyValueInput{{
+				Name: "X-Region",
+				InputWithVariables: model.InputWithVariables{
+					Input:     model.Input{Value: "{region.name}"},
+					Variables: map[string]model.Input{"region.name": {Default: "eu"}},
+				},
+			}},
+		}},
+	}
+	err := validators.ValidateServerJSON(&server)
+	require.ErrorIs(t, err, validators.ErrInvalidVariableName)
+	fieldErrs := validators.FieldErrors(err)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, apiv0.ErrorCodeInvalidVariableName, fieldErrs[0].Code)
+	assert.Equal(t, "/remotes/0/headers/0/variables/region.name", fieldErrs[0].Field)
+}
+
+func TestValidate_UnusedVariablesWarn(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     model.Input
+		variables map[string]model.Input
+		want      []string
+	}{
+		{
+			name:      "all used",
+			input:     model.Input{Value: "{host}:{port}"},
+			variables: map[string]model.Input{"host": {Default: "localhost"}, "port": {Format: model.FormatNumber, Default: "8080"}},
+		},
+		{
+			name:      "used by the default",
+			input:     model.Input{Default: "{host}:8080"},
+			variables: map[string]model.Input{"host": {Default: "localhost"}},
+		},
+		{
+			name:      "unused variables",
+			input:     model.Input{Value: "{host}"},
+			variables: map[string]model.Input{"host": {Default: "localhost"}, "port": {Default: "8080"}, "scheme": {Default: "http"}},
+			want:      []string{"packages[0].runtime_arguments[2].variables.port", "packages[0].runtime_arguments[2].variables.scheme"},
+		},
+		{
+			name:      "value without placeholders",
+			input:     model.Input{Value: "localhost"},
+			variables: map[string]model.Input{"host": {Default: "localhost"}},
+			want:      []string{"packages[0].runtime_arguments[2].variables.host"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serverWithVariables("", tt.variables)
+			server.Packages[0].RuntimeArguments[2].Input = tt.input
+			ctx := validators.WithWarnings(context.Background())
+			require.NoError(t, validators.ValidatePublishRequest(ctx, server, &config.Config{}))
+
+			var unused []string
+			for _, warning := range validators.WarningsFrom(ctx) {
+				if warning.Code == apiv0.WarningUnusedVariable {
+					unused = append(unused, warning.Path)
+				}
+			}
+			assert.Equal(t, tt.want, unused)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/mod/semver"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// VersionKind is how a server version is ordered against the server's other versions
+type VersionKind string
+
+const (
+	// VersionSemver is a semantic version, major.minor.patch with optional prerelease and build
+	// metadata, ordered by semantic version precedence
+	VersionSemver VersionKind = "semver"
+	// VersionOpaque is any other version, such as a date or a commit hash, ordered by when it was published
+	VersionOpaque VersionKind = "opaque"
+)
+
+// ClassifyVersion reports whether version is a semantic version or an opaque one. Versions
+// stored before publishing stripped a leading "v", such as v1.2.3, are still semantic.
+func ClassifyVersion(version string) VersionKind {
+	// The semver package requires a "v" prefix, and accepts versions without all three parts,
+	// such as v1.2, which are not semantic versions
+	withV := version
+	if !strings.HasPrefix(withV, "v") {
+		withV = "v" + withV
+	}
+	if !semver.IsValid(withV) {
+		return VersionOpaque
+	}
+	core, _, _ := strings.Cut(strings.TrimPrefix(withV, "v"), "-")
+	core, _, _ = strings.Cut(core, "+")
+	if strings.Count(core, ".") != 2 {
+		return VersionOpaque
+	}
+	return VersionSemver
+}
+
+// NormalizeVersion trims the whitespace around a server version and strips a single leading
+// "v" from versions such as v1.2.3, reporting whether it stripped one. Versions where the "v"
+// is not followed by a digit, such as vNext, are left as they are.
+func NormalizeVersion(version string) (string, bool) {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') && version[1] >= '0' && version[1] <= '9' {
+		return version[1:], true
+	}
+	return version, false
+}
+
+// NormalizeServerVersion normalizes the version of a server being published with
+// NormalizeVersion and checks the result with ValidateVersion. A stripped "v" is reported in
+// ctx as a warning, or, with rejectPrefix set, rejects the version instead.
+func NormalizeServerVersion(ctx context.Context, server *apiv0.ServerJSON, rejectPrefix bool) error {
+	normalized, prefixed := NormalizeVersion(server.Version)
+	if prefixed {
+		if rejectPrefix {
+			return fieldError("/version", fmt.Errorf("%w: %q (publish it as %q, without the leading v)", ErrVersionPrefix, server.Version, normalized))
+		}
+		Warn(ctx, apiv0.Warning{
+			Code:    apiv0.WarningVersionPrefix,
+			Path:    "version",
+			Message: fmt.Sprintf("version %q was published as %q; leave out the leading v", strings.TrimSpace(server.Version), normalized),
+		})
+	}
+	if err := ValidateVersion(normalized); err != nil {
+		return err
+	}
+	server.Version = normalized
+	return nil
+}
+
+// ValidateVersion checks a normalized server version is present, at most MaxVersionLength
+// characters, and free of whitespace and control characters, which break ordering and
+// display. Edits are not checked, so versions stored before publishing checked them can still
+// be deprecated or deleted.
+func ValidateVersion(version string) error {
+	if version == "" {
+		return fieldError("/version", fmt.Errorf("%w: version is required", ErrInvalidServerVersion))
+	}
+	if length := utf8.RuneCountInString(version); length > Rules.MaxVersionLength {
+		return fieldError("/version", fmt.Errorf("%w: %d characters, at most %d allowed", ErrInvalidServerVersion, length, Rules.MaxVersionLength))
+	}
+	for _, r := range version {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fieldError("/version", fmt.Errorf("%w: %q contains whitespace or control characters", ErrInvalidServerVersion, version))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package validators_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeServerVersion(t *testing.T) {
+	// A version as long as git describe output for a long branch name
+	describe := "1.2.3-" + strings.Repeat("feature-branch-", 30) + "g1a2b3c4"
+
+	tests := []struct {
+		name            string
+		version         string
+		expected        string
+		expectedWarning bool
+		expectedError   error
+	}{
+		{name: "semantic version", version: "1.2.3", expected: "1.2.3"},
+		{name: "leading v is stripped", version: "v1.2.3", expected: "1.2.3", expectedWarning: true},
+		{name: "leading capital V is stripped", version: "V2.0.0", expected: "2.0.0", expectedWarning: true},
+		{name: "only a single v is stripped", version: "vv1.2.3", expected: "vv1.2.3"},
+		{name: "v not followed by a digit is kept", version: "vNext", expected: "vNext"},
+		{name: "surrounding whitespace is trimmed", version: " 1.2.3\n", expected: "1.2.3"},
+		{name: "whitespace and v", version: "\tv1.2.3 ", expected: "1.2.3", expectedWarning: true},
+		{name: "opaque version", version: "2025.01.15", expected: "2025.01.15"},
+		{name: "inner whitespace", version: "1.2.3 beta", expectedError: validators.ErrInvalidServerVersion},
+		{name: "control character", version: "1.2.3\x00", expectedError: validators.ErrInvalidServerVersion},
+		{name: "empty", version: "", expectedError: validators.ErrInvalidServerVersion},
+		{name: "only whitespace", version: "   ", expectedError: validators.ErrInvalidServerVersion},
+		{name: "only v", version: "v", expected: "v"},
+		{name: "too long", version: describe, expectedError: validators.ErrInvalidServerVersion},
+		{name: "at the maximum length", version: strings.Repeat("1", validators.MaxVersionLength), expected: strings.Repeat("1", validators.MaxVersionLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := validators.WithWarnings(context.Background())
+			server := apiv0.ServerJSON{Name: "io.github.example/weather", Version: tt.version}
+			err := validators.NormalizeServerVersion(ctx, &server, false)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				assert.Equal(t, tt.version, server.Version, "rejected versions are left as they are")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, server.Version)
+			if tt.expectedWarning {
+				require.Len(t, validators.WarningsFrom(ctx), 1)
+				assert.Equal(t, apiv0.WarningVersionPrefix, validators.WarningsFrom(ctx)[0].Code)
+				assert.Equal(t, "version", validators.WarningsFrom(ctx)[0].Path)
+			} else {
+				assert.Empty(t, validators.WarningsFrom(ctx))
+			}
+		})
+	}
+}
+
+func TestNormalizeServerVersion_RejectPrefix(t *testing.T) {
+	server := apiv0.ServerJSON{Name: "io.github.example/weather", Version: "v1.2.3"}
+	err := validators.NormalizeServerVersion(context.Background(), &server, true)
+	require.ErrorIs(t, err, validators.ErrVersionPrefix)
+	assert.Equal(t, "v1.2.3", server.Version)
+
+	server.Version = "vNext"
+	require.NoError(t, validators.NormalizeServerVersion(context.Background(), &server, true))
+	assert.Equal(t, "vNext", server.Version)
+}
+
+func TestClassifyVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected validators.VersionKind
+	}{
+		{"1.2.3", validators.VersionSemver},
+		{"1.2.3-beta.1", validators.VersionSemver},
+		{"1.2.3+build.5", validators.VersionSemver},
+		{"1.2.3-rc.1+build.5", validators.VersionSemver},
+		{"v1.2.3", validators.VersionSemver},
+		{"0.0.0", validators.VersionSemver},
+		{"1.2", validators.VersionOpaque},
+		{"1", validators.VersionOpaque},
+		{"1.2.3.4", validators.VersionOpaque},
+		{"01.2.3", validators.VersionOpaque},
+		{"2025.01.15", validators.VersionOpaque},
+		{"vNext", validators.VersionOpaque},
+		{"latest", validators.VersionOpaque},
+		{"1a2b3c4", validators.VersionOpaque},
+		{"", validators.VersionOpaque},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validators.ClassifyVersion(tt.version))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+type warningsKey struct{}
+
+// warningCollector holds the warnings added to a request's context
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []apiv0.Warning
+}
+
+// WithWarnings returns a ctx that collects the non-fatal findings of validating and publishing a
+// server, for the caller to report. Read them with WarningsFrom.
+func WithWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, warningsKey{}, &warningCollector{})
+}
+
+// WarningsFrom returns the warnings added to ctx since WithWarnings
+func WarningsFrom(ctx context.Context) []apiv0.Warning {
+	collector, ok := ctx.Value(warningsKey{}).(*warningCollector)
+	if !ok {
+		return nil
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	return append([]apiv0.Warning(nil), collector.warnings...)
+}
+
+// Warn adds a non-fatal finding to ctx. When the caller isn't collecting warnings, as when
+// importing seed data, it is logged instead.
+func Warn(ctx context.Context, warning apiv0.Warning) {
+	collector, ok := ctx.Value(warningsKey{}).(*warningCollector)
+	if !ok {
+		if warning.Path != "" {
+			log.Printf("Warning: %s: %s", warning.Path, warning.Message)
+		} else {
+			log.Printf("Warning: %s", warning.Message)
+		}
+		return
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.warnings = append(collector.warnings, warning)
+}
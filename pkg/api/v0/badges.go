@@ -0,0 +1,79 @@
+package v0
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Badge is a trust signal the registry derives from a server version when serving it. Badges
+// are never stored or accepted from publishers.
+type Badge string
+
+const (
+	// BadgeDomainVerified marks a server whose namespace is a domain, such as com.example.
+	// Publishing there requires proving control of the domain over DNS or HTTP, or an
+	// OIDC grant configured by the registry's operators.
+	BadgeDomainVerified Badge = "domain_verified"
+	// BadgeAccountVerified marks a server whose namespace is a GitHub or GitLab account or
+	// group (io.github.* or io.gitlab.*), which only its members can publish to
+	BadgeAccountVerified Badge = "account_verified"
+	// BadgeRepositoryMatchesNamespace marks a server whose repository is hosted under the
+	// GitHub or GitLab account its namespace belongs to
+	BadgeRepositoryMatchesNamespace Badge = "repository_matches_namespace"
+	// BadgePackageHashes marks a server whose packages all declare the SHA-256 of their file
+	BadgePackageHashes Badge = "package_hashes"
+)
+
+// anonymousNamespace is where anonymous tokens publish; it carries no identity
+const anonymousNamespace = "io.modelcontextprotocol.anonymous"
+
+// ComputeBadges derives the server's badges from its name, repository and packages
+func (s *ServerJSON) ComputeBadges() []Badge {
+	var badges []Badge
+	namespace, _, _ := strings.Cut(s.Name, "/")
+
+	// The account path of a GitHub or GitLab namespace, such as octocat or group/subgroup
+	var host, account string
+	switch {
+	case namespace == anonymousNamespace:
+	case strings.HasPrefix(namespace, "io.github."):
+		host, account = "github.com", strings.TrimPrefix(namespace, "io.github.")
+	case strings.HasPrefix(namespace, "io.gitlab."):
+		// Nested GitLab groups map to dotted namespaces
+		host, account = "gitlab.com", strings.ReplaceAll(strings.TrimPrefix(namespace, "io.gitlab."), ".", "/")
+	case strings.Contains(namespace, "."):
+		badges = append(badges, BadgeDomainVerified)
+	}
+
+	if account != "" {
+		badges = append(badges, BadgeAccountVerified)
+		if repositoryUnder(s.Repository.URL, host, account) {
+			badges = append(badges, BadgeRepositoryMatchesNamespace)
+		}
+	}
+
+	if len(s.Packages) > 0 {
+		hashed := true
+		for _, pkg := range s.Packages {
+			if pkg.FileSHA256 == "" {
+				hashed = false
+				break
+			}
+		}
+		if hashed {
+			badges = append(badges, BadgePackageHashes)
+		}
+	}
+	return badges
+}
+
+// repositoryUnder reports whether repoURL is a repository on host inside the account path
+func repositoryUnder(repoURL, host, account string) bool {
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Scheme != "https" || !strings.EqualFold(strings.TrimPrefix(u.Host, "www."), host) {
+		return false
+	}
+	path := strings.Trim(u.Path, "/")
+	prefix := account + "/"
+	return len(path) > len(prefix) && strings.EqualFold(path[:len(prefix)], prefix)
+}
@@ -0,0 +1,213 @@
+package v0
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ValidationError is the problem details body of requests rejected as invalid, whether by the
+// API's schema or by the registry's own checks of a server.json
+type ValidationError struct {
+	Title  string        `json:"title,omitempty" doc:"A short summary of the problem type" example:"Bad Request"`
+	Status int           `json:"status,omitempty" doc:"HTTP status code" example:"400"`
+	Detail string        `json:"detail,omitempty" doc:"What the request was rejected for" example:"Failed to publish server"`
+	Errors []ErrorDetail `json:"errors" doc:"The problems found"`
+}
+
+// ErrorDetail is one problem found with a request
+type ErrorDetail struct {
+	Code     string `json:"code" doc:"Machine-readable error code" example:"invalid_title"`
+	Location string `json:"location,omitempty" doc:"JSON pointer to the request body field the problem is with, or the parameter, as in query.limit" example:"/packages/0/identifier"`
+	Message  string `json:"message" doc:"What was found"`
+	Value    any    `json:"value,omitempty" doc:"The value at the location"`
+}
+
+// Error codes of schema validation failures, returned for any API request
+const (
+	// ErrorCodeRequired is returned for a missing required field
+	ErrorCodeRequired = "required"
+	// ErrorCodeUnexpectedProperty is returned for a field the schema does not define
+	ErrorCodeUnexpectedProperty = "unexpected_property"
+	// ErrorCodeInvalidType is returned for a value of the wrong JSON type
+	ErrorCodeInvalidType = "invalid_type"
+	// ErrorCodeInvalidValue is returned for any other value the schema or checks reject
+	ErrorCodeInvalidValue = "invalid_value"
+)
+
+// ErrorCodeTransientStorage is the code of 503 responses to requests that failed on a transient
+// database problem, such as a failover. Retrying is safe: a failed read changed nothing, and a
+// publish whose commit was applied before the connection dropped is rejected as a duplicate.
+const ErrorCodeTransientStorage = "TRANSIENT_STORAGE"
+
+// ErrorCodeQueryTimeout is the code of 503 responses to list and search requests whose database
+// query ran longer than the registry allows. Narrowing the search or filters, or a smaller page,
+// makes the query cheaper.
+const ErrorCodeQueryTimeout = "QUERY_TIMEOUT"
+
+// ErrorCodeRateLimited is the code of 429 responses to requests over a per-client limit, such as
+// server reports. The Retry-After header says when the client may try again.
+const ErrorCodeRateLimited = "RATE_LIMITED"
+
+// Error codes of the registry's own checks of a server.json, returned for publish and edit requests
+const (
+	// Repository
+	ErrorCodeInvalidRepositoryURL = "invalid_repository_url"
+	ErrorCodeInvalidSubfolderPath = "invalid_subfolder_path"
+	ErrorCodeInvalidRepositoryID  = "invalid_repository_id"
+	ErrorCodeRepositoryIDMismatch = "repository_id_mismatch"
+
+	// Server name and text
+	ErrorCodeSuspiciousUnicode = "suspicious_unicode"
+	ErrorCodeInvalidUTF8       = "invalid_utf8"
+
+	// Packages
+	ErrorCodePackageNameHasSpaces         = "package_name_has_spaces"
+	ErrorCodeDuplicatePackage             = "duplicate_package"
+	ErrorCodeConflictingPackageVersions   = "conflicting_package_versions"
+	ErrorCodeInvalidOCIReference          = "invalid_oci_reference"
+	ErrorCodeReleaseTagMismatch           = "release_tag_mismatch"
+	ErrorCodeUnsupportedRegistryBaseURL   = "unsupported_registry_base_url"
+	ErrorCodeMismatchedRegistryTypeAndURL = "mismatched_registry_type_and_url"
+
+	// Remotes
+	ErrorCodeInvalidRemoteURL      = "invalid_remote_url"
+	ErrorCodeDuplicateRemoteURL    = "duplicate_remote_url"
+	ErrorCodeInvalidAuthentication = "invalid_authentication"
+
+	// Template placeholders
+	ErrorCodeMalformedTemplate     = "malformed_template"
+	ErrorCodeUndeclaredVariable    = "undeclared_variable"
+	ErrorCodeSecretInURL           = "secret_in_url"
+	ErrorCodeUnresolvedURLVariable = "unresolved_url_variable"
+
+	// Input variables
+	ErrorCodeInvalidVariableName = "invalid_variable_name"
+	ErrorCodeTooManyVariables    = "too_many_variables"
+	ErrorCodeNestedVariables     = "nested_variables"
+
+	// Headers
+	ErrorCodeTooManyHeaders     = "too_many_headers"
+	ErrorCodeHeaderValueTooLong = "header_value_too_long"
+	ErrorCodeSecretHeaderValue  = "secret_header_value"
+	ErrorCodeCredentialInHeader = "credential_in_header"
+	ErrorCodeForbiddenHeader    = "forbidden_header"
+
+	// Display metadata
+	ErrorCodeInvalidTitle            = "invalid_title"
+	ErrorCodeTooManyIcons            = "too_many_icons"
+	ErrorCodeInvalidIconURL          = "invalid_icon_url"
+	ErrorCodeUnsupportedIconMimeType = "unsupported_icon_mime_type"
+	ErrorCodeInvalidIconSize         = "invalid_icon_size"
+	ErrorCodeTooManyCategories       = "too_many_categories"
+	ErrorCodeDuplicateCategory       = "duplicate_category"
+	ErrorCodeUnknownCategory         = "unknown_category"
+
+	// Documentation and license
+	ErrorCodeInvalidDocumentationURL = "invalid_documentation_url"
+	ErrorCodeInvalidWebsiteURL       = "invalid_website_url"
+	ErrorCodeReadmeTooLarge          = "readme_too_large"
+	ErrorCodeReleaseNotesTooLarge    = "release_notes_too_large"
+	ErrorCodeInvalidFilePath         = "invalid_file_path"
+	ErrorCodeInvalidLicense          = "invalid_license"
+	ErrorCodeLicenseMismatch         = "license_mismatch"
+	ErrorCodeInvalidContact          = "invalid_contact"
+
+	// Fork lineage
+	ErrorCodeInvalidForkOf = "invalid_fork_of"
+
+	// Protocol version
+	ErrorCodeUnknownMCPVersion = "unknown_mcp_version"
+
+	// Server version
+	ErrorCodeInvalidVersion = "invalid_version"
+	ErrorCodeVersionPrefix  = "version_prefix"
+
+	// Edits
+	ErrorCodeImmutableField = "immutable_field"
+
+	// Arguments
+	ErrorCodeNamedArgumentNameRequired     = "named_argument_name_required"
+	ErrorCodeInvalidNamedArgumentName      = "invalid_named_argument_name"
+	ErrorCodeArgumentValueStartsWithName   = "argument_value_starts_with_name"
+	ErrorCodeArgumentDefaultStartsWithName = "argument_default_starts_with_name"
+)
+
+// retryableStatuses are answered by load balancers and a busy registry to requests that can
+// succeed when sent again
+var retryableStatuses = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// RegistryError is an error response of the registry API, as parsed by ParseErrorResponse
+type RegistryError struct {
+	Status int
+	// Code is the machine-readable code of the response, or of its first problem, such as
+	// TRANSIENT_STORAGE or invalid_title. It is empty for errors without one, such as a 404.
+	Code string
+	// Message is the detail of the response, or its title
+	Message string
+	// Field is the JSON pointer to the request body field, or the parameter, that the first
+	// problem is with, as in /packages/0/identifier or query.limit
+	Field string
+	// Retryable reports whether sending the same request again can succeed
+	Retryable bool
+	// Errors lists every problem found with the request
+	Errors []ErrorDetail
+	// Body is the response body as received
+	Body []byte
+}
+
+func (e *RegistryError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = strings.TrimSpace(string(e.Body))
+	}
+	var problems strings.Builder
+	for _, detail := range e.Errors {
+		if detail.Code == "" {
+			continue
+		}
+		if detail.Location != "" {
+			_, _ = fmt.Fprintf(&problems, "; [%s] %s: %s", detail.Code, detail.Location, detail.Message)
+		} else {
+			_, _ = fmt.Fprintf(&problems, "; [%s] %s", detail.Code, detail.Message)
+		}
+	}
+	return fmt.Sprintf("registry returned status %d: %s%s", e.Status, message, problems.String())
+}
+
+// ParseErrorResponse parses the body of a response with an error status. Problem details,
+// whether a ValidationError or another error with a code, fill in the code, message and field;
+// other bodies are only kept in Body.
+func ParseErrorResponse(status int, body []byte) *RegistryError {
+	parsed := &RegistryError{Status: status, Retryable: retryableStatuses[status], Body: body}
+
+	var problem struct {
+		Title  string        `json:"title"`
+		Detail string        `json:"detail"`
+		Code   string        `json:"code"`
+		Errors []ErrorDetail `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &problem); err != nil {
+		return parsed
+	}
+	parsed.Code, parsed.Message, parsed.Errors = problem.Code, problem.Detail, problem.Errors
+	if parsed.Message == "" {
+		parsed.Message = problem.Title
+	}
+	if len(problem.Errors) > 0 {
+		parsed.Field = problem.Errors[0].Location
+		if parsed.Code == "" {
+			parsed.Code = problem.Errors[0].Code
+		}
+	}
+	if parsed.Code == ErrorCodeTransientStorage {
+		parsed.Retryable = true
+	}
+	return parsed
+}
@@ -0,0 +1,128 @@
+package v0_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// roundTrip serves body with status from an httptest server and parses the response
+func roundTrip(t *testing.T, status int, body []byte) *apiv0.RegistryError {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL) //nolint:noctx
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return apiv0.ParseErrorResponse(resp.StatusCode, content)
+}
+
+func TestParseErrorResponse(t *testing.T) {
+	t.Run("validation error codes", func(t *testing.T) {
+		for _, code := range []string{
+			apiv0.ErrorCodeRequired,
+			apiv0.ErrorCodeUnexpectedProperty,
+			apiv0.ErrorCodeInvalidType,
+			apiv0.ErrorCodeInvalidValue,
+			apiv0.ErrorCodeInvalidRepositoryURL,
+			apiv0.ErrorCodeInvalidSubfolderPath,
+			apiv0.ErrorCodeInvalidRepositoryID,
+			apiv0.ErrorCodeRepositoryIDMismatch,
+			apiv0.ErrorCodeSuspiciousUnicode,
+			apiv0.ErrorCodeInvalidUTF8,
+			apiv0.ErrorCodePackageNameHasSpaces,
+			apiv0.ErrorCodeDuplicatePackage,
+			apiv0.ErrorCodeConflictingPackageVersions,
+			apiv0.ErrorCodeInvalidOCIReference,
+			apiv0.ErrorCodeReleaseTagMismatch,
+			apiv0.ErrorCodeUnsupportedRegistryBaseURL,
+			apiv0.ErrorCodeMismatchedRegistryTypeAndURL,
+			apiv0.ErrorCodeInvalidRemoteURL,
+			apiv0.ErrorCodeDuplicateRemoteURL,
+			apiv0.ErrorCodeInvalidAuthentication,
+			apiv0.ErrorCodeMalformedTemplate,
+			apiv0.ErrorCodeUndeclaredVariable,
+			apiv0.ErrorCodeSecretInURL,
+			apiv0.ErrorCodeUnresolvedURLVariable,
+			apiv0.ErrorCodeInvalidVariableName,
+			apiv0.ErrorCodeTooManyVariables,
+			apiv0.ErrorCodeNestedVariables,
+			apiv0.ErrorCodeTooManyHeaders,
+			apiv0.ErrorCodeHeaderValueTooLong,
+			apiv0.ErrorCodeSecretHeaderValue,
+			apiv0.ErrorCodeCredentialInHeader,
+			apiv0.ErrorCodeForbiddenHeader,
+			apiv0.ErrorCodeInvalidTitle,
+			apiv0.ErrorCodeTooManyIcons,
+			apiv0.ErrorCodeInvalidIconURL,
+			apiv0.ErrorCodeUnsupportedIconMimeType,
+			apiv0.ErrorCodeInvalidIconSize,
+			apiv0.ErrorCodeTooManyCategories,
+			apiv0.ErrorCodeDuplicateCategory,
+			apiv0.ErrorCodeUnknownCategory,
+			apiv0.ErrorCodeInvalidDocumentationURL,
+			apiv0.ErrorCodeReadmeTooLarge,
+			apiv0.ErrorCodeReleaseNotesTooLarge,
+			apiv0.ErrorCodeInvalidFilePath,
+			apiv0.ErrorCodeInvalidLicense,
+			apiv0.ErrorCodeLicenseMismatch,
+			apiv0.ErrorCodeInvalidForkOf,
+			apiv0.ErrorCodeUnknownMCPVersion,
+			apiv0.ErrorCodeNamedArgumentNameRequired,
+			apiv0.ErrorCodeInvalidNamedArgumentName,
+			apiv0.ErrorCodeArgumentValueStartsWithName,
+			apiv0.ErrorCodeArgumentDefaultStartsWithName,
+		} {
+			body, err := json.Marshal(apiv0.ValidationError{
+				Title:  "Bad Request",
+				Status: http.StatusBadRequest,
+				Detail: "Failed to publish server",
+				Errors: []apiv0.ErrorDetail{{Code: code, Location: "/packages/0", Message: "rejected"}},
+			})
+			require.NoError(t, err)
+
+			parsed := roundTrip(t, http.StatusBadRequest, body)
+			assert.Equal(t, http.StatusBadRequest, parsed.Status, code)
+			assert.Equal(t, code, parsed.Code)
+			assert.Equal(t, "/packages/0", parsed.Field, code)
+			assert.Equal(t, "Failed to publish server", parsed.Message, code)
+			assert.False(t, parsed.Retryable, code)
+			assert.Equal(t, "registry returned status 400: Failed to publish server; ["+code+"] /packages/0: rejected", parsed.Error())
+		}
+	})
+
+	t.Run("transient storage", func(t *testing.T) {
+		parsed := roundTrip(t, http.StatusServiceUnavailable, []byte(`{"title": "Service Unavailable", "status": 503,
+			"detail": "Failed to list servers: the registry's database is temporarily unavailable, retry the request", "code": "TRANSIENT_STORAGE"}`))
+		assert.Equal(t, apiv0.ErrorCodeTransientStorage, parsed.Code)
+		assert.True(t, parsed.Retryable)
+		assert.Empty(t, parsed.Field)
+	})
+
+	t.Run("errors without a code", func(t *testing.T) {
+		parsed := roundTrip(t, http.StatusNotFound, []byte(`{"title": "Not Found", "status": 404, "detail": "Server not found"}`))
+		assert.Empty(t, parsed.Code)
+		assert.Equal(t, "Server not found", parsed.Message)
+		assert.False(t, parsed.Retryable)
+		assert.Equal(t, "registry returned status 404: Server not found", parsed.Error())
+
+		parsed = roundTrip(t, http.StatusBadGateway, []byte("upstream connect error\n"))
+		assert.Empty(t, parsed.Code)
+		assert.True(t, parsed.Retryable)
+		assert.Equal(t, "registry returned status 502: upstream connect error", parsed.Error())
+	})
+}
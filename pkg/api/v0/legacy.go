@@ -0,0 +1,130 @@
+package v0
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Extension keys of the legacy format, from before publisher and registry metadata moved under _meta
+const (
+	// LegacyPublisherKey held publisher-provided metadata, either beside the server or in a {"server": ...} wrapper
+	LegacyPublisherKey = "x-publisher"
+	// LegacyRegistryKey held registry metadata, which publishers may not set
+	LegacyRegistryKey = "x-io.modelcontextprotocol.registry"
+)
+
+// Keys under _meta that the legacy extensions map to
+const (
+	PublisherProvidedMetaKey = "io.modelcontextprotocol.registry/publisher-provided"
+	OfficialMetaKey          = "io.modelcontextprotocol.registry/official"
+)
+
+// LegacyExtensionsWarning is returned to clients that send the legacy extension format
+const LegacyExtensionsWarning = "The x-publisher extension format is deprecated and will be removed: " +
+	"send the server JSON at the top level with publisher metadata under " +
+	"_meta[\"" + PublisherProvidedMetaKey + "\"]"
+
+// AllowUnknownFieldsHeader, set to true, has publish and edit requests drop the server JSON
+// fields the format does not define with a warning, instead of being rejected for them. It is
+// deprecated, kept for one release cycle while publishers fix their server.json files.
+const AllowUnknownFieldsHeader = "Allow-Unknown-Fields"
+
+// NormalizeExtensions rewrites a server JSON document that uses the legacy extension
+// format into the canonical layout, reporting whether it did so. Documents already in
+// the canonical layout, or that are not JSON objects, are returned unchanged.
+//
+// Both legacy shapes are accepted: the {"server": {...}, "x-publisher": {...}} publish
+// request wrapper, and x-publisher or x-io.modelcontextprotocol.registry alongside the
+// server's own fields.
+func NormalizeExtensions(data []byte) ([]byte, bool, error) {
+	// Leave malformed documents for the caller's decoder to report
+	if !json.Valid(data) {
+		return data, false, nil
+	}
+	var parsed any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keep numbers exactly as sent
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, false, err
+	}
+	doc, ok := parsed.(map[string]any)
+	if !ok {
+		return data, false, nil
+	}
+
+	legacy := false
+	server := doc
+	if wrapped, ok := doc["server"].(map[string]any); ok && doc["name"] == nil {
+		server = wrapped
+		for key, value := range doc {
+			if key == "server" {
+				continue
+			}
+			if _, exists := server[key]; exists {
+				return nil, false, fmt.Errorf("%s is set both inside and outside the server wrapper", key)
+			}
+			server[key] = value
+		}
+		legacy = true
+	}
+
+	var meta map[string]any
+	if raw, exists := server["_meta"]; exists && raw != nil {
+		var ok bool
+		if meta, ok = raw.(map[string]any); !ok {
+			return nil, false, errors.New("_meta must be an object")
+		}
+	}
+	for _, keys := range [][2]string{
+		{LegacyPublisherKey, PublisherProvidedMetaKey},
+		{LegacyRegistryKey, OfficialMetaKey},
+	} {
+		legacyKey, metaKey := keys[0], keys[1]
+		value, exists := server[legacyKey]
+		if !exists {
+			continue
+		}
+		legacy = true
+		delete(server, legacyKey)
+		if meta == nil {
+			meta = make(map[string]any)
+		}
+		if _, exists := meta[metaKey]; exists {
+			return nil, false, fmt.Errorf("%s and _meta[%q] cannot both be set", legacyKey, metaKey)
+		}
+		meta[metaKey] = value
+	}
+
+	if !legacy {
+		return data, false, nil
+	}
+	if meta != nil {
+		server["_meta"] = meta
+	}
+	normalized, err := json.Marshal(server)
+	if err != nil {
+		return nil, false, err
+	}
+	return normalized, true, nil
+}
+
+// Profiles a publish request can ask for with the profile parameter of its Accept header, as in
+// Accept: application/json; profile="server"
+const (
+	// PublishProfileServer is the published server JSON with registry metadata under _meta
+	PublishProfileServer = "server"
+	// PublishProfileLegacy is LegacyPublishResponse, the shape publishers released before the server profile expect
+	PublishProfileLegacy = "legacy"
+)
+
+// LegacyPublishResponse is the publish response of the original handlers, still served to
+// publisher versions that predate the server profile
+type LegacyPublishResponse struct {
+	Message string `json:"message"`
+	ID      string `json:"id"`
+}
+
+// LegacyPublishMessage is the message of a LegacyPublishResponse
+const LegacyPublishMessage = "Server publication successful"
@@ -0,0 +1,46 @@
+package v0
+
+// APIVersion is the major version of the registry API that this package describes. Registries
+// serve it from GET /v0/meta, so clients can tell when they are talking to an incompatible one.
+const APIVersion = "v0"
+
+// RegistryMeta identifies a registry build and the optional features it has enabled
+type RegistryMeta struct {
+	APIVersion string          `json:"api_version" example:"v0" doc:"Major version of the registry API"`
+	Version    string          `json:"version" example:"v1.2.0" doc:"Version of the registry build"`
+	GitCommit  string          `json:"git_commit" doc:"Git commit the registry was built from"`
+	BuildTime  string          `json:"build_time" doc:"When the registry was built"`
+	Database   string          `json:"database" enum:"postgresql,memory" doc:"Database the registry stores servers in"`
+	Features   map[string]bool `json:"features" doc:"Optional features and whether they are enabled. Keys may be added in future releases; clients should treat a missing key as disabled."`
+}
+
+// ValidationRules are the limits and allowed values a registry checks server.json documents
+// against, served from GET /v0/meta/validation-rules so forms and tools can validate before
+// publishing. Lengths count characters and sizes count bytes.
+type ValidationRules struct {
+	NamePattern           string   `json:"name_pattern" doc:"Regular expression server names must match: a namespace and a name separated by a slash" example:"^[^/]+/[\\s\\S]+$"`
+	MaxNameLength         int      `json:"max_name_length" example:"200"`
+	MaxDescriptionLength  int      `json:"max_description_length" example:"100"`
+	MaxVersionLength      int      `json:"max_version_length" doc:"Longest server version, in characters" example:"64"`
+	MaxTitleLength        int      `json:"max_title_length" example:"100"`
+	MaxLicenseLength      int      `json:"max_license_length" doc:"Longest SPDX license expression" example:"200"`
+	MaxContactLength      int      `json:"max_contact_length" doc:"Longest contact, in characters" example:"200"`
+	MaxReadmeBytes        int      `json:"max_readme_bytes" example:"32768"`
+	MaxReleaseNotesBytes  int      `json:"max_release_notes_bytes" example:"16384"`
+	MaxIcons              int      `json:"max_icons" example:"8"`
+	MaxIconDimension      int      `json:"max_icon_dimension" doc:"Largest width or height of an icon size, as in 1024x1024" example:"1024"`
+	IconMimeTypes         []string `json:"icon_mime_types" example:"[\"image/png\",\"image/svg+xml\"]"`
+	MaxCategories         int      `json:"max_categories" example:"5"`
+	MCPVersions           []string `json:"mcp_versions" doc:"MCP protocol revisions a server may declare as its mcpVersion, oldest first" example:"[\"2025-03-26\",\"2025-06-18\"]"`
+	Categories            []string `json:"categories" doc:"Categories servers may list; empty when any category is accepted"`
+	RegistryTypes         []string `json:"registry_types" doc:"Package registry types" example:"[\"npm\",\"pypi\"]"`
+	PackageTransports     []string `json:"package_transports" doc:"Transport types of packages" example:"[\"stdio\"]"`
+	RemoteTransports      []string `json:"remote_transports" doc:"Transport types of remotes" example:"[\"streamable-http\"]"`
+	AuthenticationSchemes []string `json:"authentication_schemes" doc:"Authentication schemes of remotes" example:"[\"oauth2\"]"`
+	RepositorySources     []string `json:"repository_sources" doc:"Repository sources, including any hosts the registry is configured to accept" example:"[\"github\",\"gitlab\"]"`
+	MaxTransportHeaders   int      `json:"max_transport_headers" doc:"Most headers a transport may declare" example:"20"`
+	MaxHeaderValueLength  int      `json:"max_header_value_length" doc:"Longest header value or default, in bytes" example:"1024"`
+	MaxInputVariables     int      `json:"max_input_variables" doc:"Most variables one input may declare" example:"16"`
+	ForbiddenHeaders      []string `json:"forbidden_headers" doc:"Headers transports may not declare, compared case-insensitively" example:"[\"Host\"]"`
+	ReservedNamespaces    []string `json:"reserved_namespaces" doc:"Namespaces, or prefixes ending in *, that only the identities their reservation allows may publish under"`
+}
@@ -12,37 +12,169 @@ type RegistryExtensions struct {
 	PublishedAt time.Time `json:"published_at"`
 	UpdatedAt   time.Time `json:"updated_at,omitempty"`
 	IsLatest    bool      `json:"is_latest"`
+	Pinned      bool      `json:"pinned,omitempty"`
+	HasReadme   bool      `json:"has_readme,omitempty"` // the README is served by GET /v0/servers/{id}/readme
+	// Tenant is the organization the version belongs to on registries serving several; never set by publishers
+	Tenant string `json:"tenant,omitempty"`
+	// InheritedFields names the server.json fields the version left unset and took from its namespace's defaults
+	InheritedFields []string `json:"inherited_fields,omitempty" doc:"server.json fields the version left unset and took from its namespace's default settings when it was published" enum:"license,categories,websiteUrl,contact"`
+
+	// Badges are derived from the server version each time it is served; see ComputeBadges
+	Badges []Badge `json:"badges,omitempty" doc:"Trust signals derived from the server version when it is served, never set by publishers. domain_verified: the namespace is a domain (such as com.example), which requires proving control of it over DNS or HTTP to publish to. account_verified: the namespace is a GitHub or GitLab account or group (io.github.* or io.gitlab.*), which only its members can publish to. repository_matches_namespace: the repository is hosted under the namespace's GitHub or GitLab account. package_hashes: every package declares the SHA-256 of its file. Servers in io.modelcontextprotocol.anonymous get neither verification badge. Omitted from fields=summary lists." enum:"domain_verified,account_verified,repository_matches_namespace,package_hashes"`
+
+	// RejectionReason is set when an admin rejects a version held for review; only its publisher and admins can see it
+	RejectionReason string `json:"rejection_reason,omitempty"`
+
+	// RemoteHealth is recorded by the optional remote liveness job; it never changes the server's status
+	RemoteHealth *RemoteHealth `json:"remote_health,omitempty"`
+
+	// PackageLinks are recorded by the optional link checker for each MCPB package's download URL
+	PackageLinks []PackageLink `json:"package_links,omitempty"`
+
+	// ForkOrigin and Forks are resolved each time a single server version is served, never stored
+	ForkOrigin *ForkOrigin `json:"fork_origin,omitempty"`
+	Forks      int         `json:"forks,omitempty"` // servers whose latest version is a fork of this one, deleted ones excepted
+}
+
+// ForkOriginStatus tells whether the server a fork was derived from can still be found
+type ForkOriginStatus string
+
+const (
+	ForkOriginAvailable ForkOriginStatus = "available"
+	ForkOriginUnknown   ForkOriginStatus = "unknown" // the original has been deleted or is not public
+)
+
+// ForkOrigin is the server a fork declares, with forkOf, that it was derived from
+type ForkOrigin struct {
+	Name   string           `json:"name"`
+	ID     string           `json:"id,omitempty"` // the original's latest version; absent when its origin is unknown
+	Status ForkOriginStatus `json:"status"`
+}
+
+// RemoteHealthStatus summarises whether a server's remote endpoints are responding
+type RemoteHealthStatus string
+
+const (
+	RemoteHealthHealthy     RemoteHealthStatus = "healthy"
+	RemoteHealthDegraded    RemoteHealthStatus = "degraded"    // some remotes have failed repeatedly
+	RemoteHealthUnreachable RemoteHealthStatus = "unreachable" // every remote has failed repeatedly
+)
+
+// RemoteHealth is the result of probing a server's remote endpoints
+type RemoteHealth struct {
+	Status              RemoteHealthStatus `json:"status"`
+	LastCheckedAt       time.Time          `json:"last_checked_at"`
+	ConsecutiveFailures int                `json:"consecutive_failures,omitempty"`
+}
+
+// LinkStatus annotates a package link that has been failing for longer than the registry's grace period
+type LinkStatus string
+
+const LinkStatusBroken LinkStatus = "broken"
+
+// PackageLink is the result of checking that an MCPB package's download URL still resolves
+type PackageLink struct {
+	Identifier     string     `json:"identifier"`
+	LastCheckedAt  time.Time  `json:"last_checked_at"`
+	LastCheckOK    bool       `json:"last_check_ok"`
+	LastHTTPStatus int        `json:"last_http_status,omitempty"` // absent when no response was received
+	FailingSince   *time.Time `json:"failing_since,omitempty"`
+	ETag           string     `json:"etag,omitempty"` // sent as If-None-Match on the next check
+	LinkStatus     LinkStatus `json:"link_status,omitempty"`
 }
 
 // ServerListResponse represents the paginated server list response
 type ServerListResponse struct {
-	Servers  []ServerJSON `json:"servers"`
-	Metadata Metadata     `json:"metadata"`
+	Servers    []ServerJSON      `json:"servers"`
+	Tombstones []ServerTombstone `json:"tombstones,omitempty"`
+	Metadata   Metadata          `json:"metadata"`
+}
+
+// ServerSummaryListResponse is the paginated server list response for fields=summary
+type ServerSummaryListResponse struct {
+	Servers    []ServerSummary   `json:"servers"`
+	Tombstones []ServerTombstone `json:"tombstones,omitempty"`
+	Metadata   Metadata          `json:"metadata"`
+}
+
+// ServerSummary is the compact list representation of a server, carrying what a
+// catalog needs to render an entry but none of its packages or remotes
+type ServerSummary struct {
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Version       string       `json:"version"`
+	Status        model.Status `json:"status,omitempty"`
+	Title         string       `json:"title,omitempty"`
+	RepositoryURL string       `json:"repository_url,omitempty"`
+	Icon          *model.Icon  `json:"icon,omitempty"`
+	Meta          *ServerMeta  `json:"_meta,omitempty"`
+}
+
+// ServerTombstone is the minimal record of a deleted server version, returned to
+// incremental sync clients so mirrors can remove it
+type ServerTombstone struct {
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	DeletedAt time.Time `json:"deleted_at"`
 }
 
 // ServerMeta represents the structured metadata with known extension fields
 type ServerMeta struct {
-	Official         *RegistryExtensions    `json:"io.modelcontextprotocol.registry/official,omitempty"`
+	Official          *RegistryExtensions    `json:"io.modelcontextprotocol.registry/official,omitempty"`
 	PublisherProvided map[string]interface{} `json:"io.modelcontextprotocol.registry/publisher-provided,omitempty"`
 }
 
 // ServerJSON represents complete server information as defined in the MCP spec, with extension support
 type ServerJSON struct {
-	Schema        string              `json:"$schema,omitempty"`
-	Name          string              `json:"name" minLength:"1" maxLength:"200"`
-	Description   string              `json:"description" minLength:"1" maxLength:"100"`
-	Status        model.Status        `json:"status,omitempty" minLength:"1"`
-	Repository    model.Repository    `json:"repository,omitempty"`
-	Version string `json:"version"`
-	Packages      []model.Package     `json:"packages,omitempty"`
-	Remotes       []model.Transport   `json:"remotes,omitempty"`
-	Meta          *ServerMeta         `json:"_meta,omitempty"`
+	Schema           string            `json:"$schema,omitempty"`
+	Name             string            `json:"name" minLength:"1" maxLength:"200"`
+	Description      string            `json:"description" minLength:"1" maxLength:"100"`
+	Status           model.Status      `json:"status,omitempty" minLength:"1"`
+	Repository       model.Repository  `json:"repository,omitempty"`
+	Version          string            `json:"version"`
+	Title            string            `json:"title,omitempty" maxLength:"100"`
+	Icons            []model.Icon      `json:"icons,omitempty" maxItems:"8"`
+	Categories       []string          `json:"categories,omitempty" maxItems:"5"`
+	DocumentationURL string            `json:"documentationUrl,omitempty" format:"uri"`
+	WebsiteURL       string            `json:"websiteUrl,omitempty" format:"uri"`
+	Readme           string            `json:"readme,omitempty"`
+	ReleaseNotes     string            `json:"releaseNotes,omitempty"`
+	License          string            `json:"license,omitempty" maxLength:"200"`
+	Contact          string            `json:"contact,omitempty" maxLength:"200"`
+	ForkOf           string            `json:"forkOf,omitempty" maxLength:"200"`
+	MCPVersion       string            `json:"mcpVersion,omitempty"`
+	Packages         []model.Package   `json:"packages,omitempty"`
+	Remotes          []model.Transport `json:"remotes,omitempty"`
+	Meta             *ServerMeta       `json:"_meta,omitempty"`
 }
 
 // Metadata represents pagination metadata
 type Metadata struct {
-	NextCursor string `json:"next_cursor,omitempty"`
-	Count      int    `json:"count"`
+	NextCursor   string     `json:"next_cursor,omitempty"`
+	Count        int        `json:"count"`
+	Total        int        `json:"total"` // entries matching the filters across all pages, tombstones included
+	MaxUpdatedAt *time.Time `json:"max_updated_at,omitempty"`
+}
+
+// Summary returns the compact list representation of the server, keeping only
+// the first icon and the official registry metadata
+func (s *ServerJSON) Summary() ServerSummary {
+	summary := ServerSummary{
+		Name:          s.Name,
+		Description:   s.Description,
+		Version:       s.Version,
+		Status:        s.Status,
+		Title:         s.Title,
+		RepositoryURL: s.Repository.URL,
+	}
+	if len(s.Icons) > 0 {
+		icon := s.Icons[0]
+		summary.Icon = &icon
+	}
+	if s.Meta != nil && s.Meta.Official != nil {
+		summary.Meta = &ServerMeta{Official: s.Meta.Official}
+	}
+	return summary
 }
 
 func (s *ServerJSON) GetID() string {
@@ -51,3 +183,15 @@ func (s *ServerJSON) GetID() string {
 	}
 	return ""
 }
+
+// LastModified returns when the server's registry metadata last changed: the later
+// of its updated_at and published_at timestamps, or the zero time if it has none
+func (s *ServerJSON) LastModified() time.Time {
+	if s.Meta == nil || s.Meta.Official == nil {
+		return time.Time{}
+	}
+	if s.Meta.Official.PublishedAt.After(s.Meta.Official.UpdatedAt) {
+		return s.Meta.Official.PublishedAt
+	}
+	return s.Meta.Official.UpdatedAt
+}
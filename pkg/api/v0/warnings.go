@@ -0,0 +1,34 @@
+package v0
+
+// Warning is a problem the registry found with a published server that did not stop it from
+// being published, returned in the warnings of publish and edit responses
+type Warning struct {
+	Code    string `json:"code" doc:"Machine-readable warning code" example:"duplicate_remote_url"`
+	Path    string `json:"path,omitempty" doc:"The server.json field the warning is about, if any" example:"remotes[0].url"`
+	Message string `json:"message" doc:"What was found and what to do about it"`
+}
+
+// Warning codes
+const (
+	// WarningLegacyExtensions is returned for requests in the deprecated x-publisher extension format
+	WarningLegacyExtensions = "legacy_extensions"
+	// WarningDuplicateRemoteURL is returned for a remote URL that another server already declares
+	WarningDuplicateRemoteURL = "duplicate_remote_url"
+	// WarningConflictingPackageVersions is returned for a package listed twice with different versions
+	WarningConflictingPackageVersions = "conflicting_package_versions"
+	// WarningSuspiciousHeaderValue is returned for a header value that might be a credential
+	WarningSuspiciousHeaderValue = "suspicious_header_value"
+	// WarningAuthorizationHeader is returned for an Authorization header that is not a secret the user supplies
+	WarningAuthorizationHeader = "authorization_header"
+	// WarningMutableImageTag is returned for an OCI image referenced only by the latest tag, without a digest
+	WarningMutableImageTag = "mutable_image_tag"
+	// WarningNumericVariableFormat is returned for a port-like URL placeholder whose variable is not declared with format number
+	WarningNumericVariableFormat = "numeric_variable_format"
+	// WarningUnusedVariable is returned for a variable that the value and default of its input never use
+	WarningUnusedVariable = "unused_variable"
+	// WarningVersionPrefix is returned for a version published without the leading v it was sent with
+	WarningVersionPrefix = "version_prefix"
+	// WarningUnknownField is returned for a field the server.json format does not define, dropped
+	// because the request set AllowUnknownFieldsHeader
+	WarningUnknownField = "unknown_field"
+)
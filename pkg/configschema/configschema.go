@@ -0,0 +1,215 @@
+// Package configschema describes what a user supplies to run an MCP server as a JSON Schema,
+// so that clients can render a settings form for it.
+//
+// Generate maps the inputs in a server.json, such as environment variables, arguments, header
+// values and templated remote URLs, to properties grouped under their server.json field
+// names. Inputs with a fixed value are left out, and a templated value is replaced by the
+// variables it is filled in from:
+//
+//	schema := configschema.Generate(server)
+//	data, err := json.MarshalIndent(schema, "", "  ")
+//
+// Each package and remote is one way to run the server. A server with several of them gets
+// one alternative per package or remote under oneOf, told apart by the constant "option"
+// property each requires.
+package configschema
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// Draft is the JSON Schema dialect of generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// OptionProperty is the property that names the package or remote an alternative configures
+const OptionProperty = "option"
+
+// placeholderRegex matches the {variable} placeholders in a remote URL
+var placeholderRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Schema is the subset of JSON Schema that Generate emits
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Const       string             `json:"const,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	Default     any                `json:"default,omitempty"`
+	WriteOnly   bool               `json:"writeOnly,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+}
+
+// Generate returns the JSON Schema for the configuration of server
+func Generate(server apiv0.ServerJSON) *Schema {
+	var options []*Schema
+	for _, pkg := range server.Packages {
+		options = append(options, packageSchema(pkg))
+	}
+	for _, remote := range server.Remotes {
+		options = append(options, remoteSchema(remote))
+	}
+
+	root := &Schema{Schema: Draft, Title: server.Name, Description: server.Description, Type: "object"}
+	if server.Title != "" {
+		root.Title = server.Title
+	}
+	switch len(options) {
+	case 0:
+	case 1:
+		root.Properties = options[0].Properties
+		root.Required = options[0].Required
+	default:
+		for _, option := range options {
+			option.property(OptionProperty, &Schema{Const: option.Title}, true)
+		}
+		root.OneOf = options
+	}
+	return root
+}
+
+// packageSchema describes the inputs of a package, titled with its registry type and identifier
+func packageSchema(pkg model.Package) *Schema {
+	option := &Schema{Title: pkg.RegistryType + " " + pkg.Identifier, Type: "object"}
+	option.group("environment_variables", keyValueInputs(pkg.EnvironmentVariables))
+	option.group("runtime_arguments", argumentInputs(pkg.RuntimeArguments))
+	option.group("package_arguments", argumentInputs(pkg.PackageArguments))
+	option.group("headers", keyValueInputs(pkg.Transport.Headers))
+	return option
+}
+
+// remoteSchema describes the inputs of a remote, titled with its transport type and URL. Each
+// placeholder in the URL is a required string under url_variables.
+func remoteSchema(remote model.Transport) *Schema {
+	option := &Schema{Title: remote.Type + " " + remote.URL, Type: "object"}
+
+	variables := &Schema{Type: "object"}
+	for _, match := range placeholderRegex.FindAllStringSubmatch(remote.URL, -1) {
+		variables.property(match[1], &Schema{Type: "string"}, true)
+	}
+	option.group("url_variables", variables)
+	option.group("headers", keyValueInputs(remote.Headers))
+	return option
+}
+
+// keyValueInputs describes environment variables or headers, keyed by name
+func keyValueInputs(inputs []model.KeyValueInput) *Schema {
+	group := &Schema{Type: "object"}
+	for _, input := range inputs {
+		if schema, required := userInput(input.InputWithVariables, false); schema != nil {
+			group.property(input.Name, schema, required)
+		}
+	}
+	return group
+}
+
+// argumentInputs describes arguments: named arguments by name, and positional ones by their
+// value hint, or by position when they have none
+func argumentInputs(arguments []model.Argument) *Schema {
+	group := &Schema{Type: "object"}
+	for i, argument := range arguments {
+		key := argument.Name
+		if argument.Type == model.ArgumentTypePositional {
+			key = argument.ValueHint
+		}
+		if key == "" {
+			key = fmt.Sprintf("argument_%d", i+1)
+		}
+		if schema, required := userInput(argument.InputWithVariables, argument.IsRepeated); schema != nil {
+			group.property(key, schema, required)
+		}
+	}
+	return group
+}
+
+// userInput returns the schema for what the user supplies for input and whether it is
+// required, or nil when the value is fixed. A templated value is an object of its variables.
+// A repeated input is an array of values.
+func userInput(input model.InputWithVariables, repeated bool) (*Schema, bool) {
+	if input.Value == "" {
+		schema := inputSchema(input.Input)
+		if repeated {
+			schema = &Schema{Description: schema.Description, Type: "array", Items: schema}
+			schema.Items.Description = ""
+		}
+		return schema, input.IsRequired
+	}
+	if len(input.Variables) == 0 {
+		return nil, false
+	}
+
+	schema := &Schema{Description: input.Description, Type: "object"}
+	for _, name := range slices.Sorted(maps.Keys(input.Variables)) {
+		variable := input.Variables[name]
+		schema.property(name, inputSchema(variable), variable.IsRequired)
+	}
+	return schema, len(schema.Required) > 0
+}
+
+// inputSchema maps a single value's format, choices, default and secrecy onto JSON Schema
+func inputSchema(input model.Input) *Schema {
+	schema := &Schema{Description: input.Description, Type: "string", WriteOnly: input.IsSecret}
+	switch input.Format {
+	case model.FormatNumber:
+		schema.Type = "number"
+	case model.FormatBoolean:
+		schema.Type = "boolean"
+	case model.FormatFilePath:
+		schema.Format = string(model.FormatFilePath)
+	case model.FormatString:
+	}
+	for _, choice := range input.Choices {
+		schema.Enum = append(schema.Enum, typedValue(input.Format, choice))
+	}
+	if input.Default != "" {
+		schema.Default = typedValue(input.Format, input.Default)
+	}
+	return schema
+}
+
+// typedValue converts a value written as a string in server.json to the input's type,
+// leaving values that don't parse as strings
+func typedValue(format model.Format, value string) any {
+	switch format {
+	case model.FormatNumber:
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case model.FormatBoolean:
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	case model.FormatString, model.FormatFilePath:
+	}
+	return value
+}
+
+// property adds a property to an object schema, listing it as required if it is
+func (s *Schema) property(name string, schema *Schema, required bool) {
+	if s.Properties == nil {
+		s.Properties = make(map[string]*Schema)
+	}
+	if _, exists := s.Properties[name]; !exists && required {
+		s.Required = append(s.Required, name)
+	}
+	s.Properties[name] = schema
+}
+
+// group adds an object of inputs to an option, unless it is empty. The group is required
+// when any of its inputs is.
+func (s *Schema) group(name string, group *Schema) {
+	if len(group.Properties) > 0 {
+		s.property(name, group, len(group.Required) > 0)
+	}
+}
@@ -0,0 +1,169 @@
+package configschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/configschema"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestGenerate_Inputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      model.Package
+		expected string // the generated properties and required list
+	}{
+		{
+			name:     "no inputs",
+			pkg:      model.Package{},
+			expected: `{}`,
+		},
+		{
+			name: "required secret environment variable",
+			pkg: model.Package{EnvironmentVariables: []model.KeyValueInput{
+				{Name: "API_KEY", InputWithVariables: model.InputWithVariables{Input: model.Input{Description: "Weather API key", IsRequired: true, IsSecret: true}}},
+			}},
+			expected: `{
+				"properties": {"environment_variables": {"type": "object", "properties": {
+					"API_KEY": {"description": "Weather API key", "type": "string", "writeOnly": true}
+				}, "required": ["API_KEY"]}},
+				"required": ["environment_variables"]
+			}`,
+		},
+		{
+			name: "choices and defaults take the input's format",
+			pkg: model.Package{EnvironmentVariables: []model.KeyValueInput{
+				{Name: "UNITS", InputWithVariables: model.InputWithVariables{Input: model.Input{Choices: []string{"metric", "imperial"}, Default: "metric"}}},
+				{Name: "RETRIES", InputWithVariables: model.InputWithVariables{Input: model.Input{Format: model.FormatNumber, Choices: []string{"1", "3"}, Default: "3"}}},
+				{Name: "VERBOSE", InputWithVariables: model.InputWithVariables{Input: model.Input{Format: model.FormatBoolean, Default: "false"}}},
+				{Name: "CACHE_DIR", InputWithVariables: model.InputWithVariables{Input: model.Input{Format: model.FormatFilePath}}},
+			}},
+			expected: `{
+				"properties": {"environment_variables": {"type": "object", "properties": {
+					"UNITS": {"type": "string", "enum": ["metric", "imperial"], "default": "metric"},
+					"RETRIES": {"type": "number", "enum": [1, 3], "default": 3},
+					"VERBOSE": {"type": "boolean", "default": false},
+					"CACHE_DIR": {"type": "string", "format": "file_path"}
+				}}}
+			}`,
+		},
+		{
+			name: "fixed values are left out",
+			pkg: model.Package{EnvironmentVariables: []model.KeyValueInput{
+				{Name: "LOG_FORMAT", InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "json"}}},
+			}},
+			expected: `{}`,
+		},
+		{
+			name: "templated value is replaced by its variables",
+			pkg: model.Package{Transport: model.Transport{Type: "streamable-http", URL: "http://localhost:8080/mcp", Headers: []model.KeyValueInput{
+				{Name: "Authorization", InputWithVariables: model.InputWithVariables{
+					Input: model.Input{Description: "Bearer token", Value: "Bearer {token}"},
+					Variables: map[string]model.Input{
+						"token":  {IsRequired: true, IsSecret: true},
+						"tenant": {Description: "Optional tenant"},
+					},
+				}},
+			}}},
+			expected: `{
+				"properties": {"headers": {"type": "object", "properties": {
+					"Authorization": {"description": "Bearer token", "type": "object", "properties": {
+						"tenant": {"description": "Optional tenant", "type": "string"},
+						"token": {"type": "string", "writeOnly": true}
+					}, "required": ["token"]}
+				}, "required": ["Authorization"]}},
+				"required": ["headers"]
+			}`,
+		},
+		{
+			name: "named, positional and repeated arguments",
+			pkg: model.Package{
+				RuntimeArguments: []model.Argument{
+					{Type: model.ArgumentTypeNamed, Name: "--memory", InputWithVariables: model.InputWithVariables{Input: model.Input{Default: "512m"}}},
+				},
+				PackageArguments: []model.Argument{
+					{Type: model.ArgumentTypePositional, ValueHint: "workspace", InputWithVariables: model.InputWithVariables{Input: model.Input{Format: model.FormatFilePath, IsRequired: true}}},
+					{Type: model.ArgumentTypePositional, InputWithVariables: model.InputWithVariables{Input: model.Input{Description: "Extra flag"}}},
+					{Type: model.ArgumentTypeNamed, Name: "--allow", IsRepeated: true, InputWithVariables: model.InputWithVariables{Input: model.Input{Description: "Allowed host", Choices: []string{"a", "b"}}}},
+					{Type: model.ArgumentTypeNamed, Name: "--stdio", InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "true"}}},
+				},
+			},
+			expected: `{
+				"properties": {
+					"runtime_arguments": {"type": "object", "properties": {
+						"--memory": {"type": "string", "default": "512m"}
+					}},
+					"package_arguments": {"type": "object", "properties": {
+						"workspace": {"type": "string", "format": "file_path"},
+						"argument_2": {"description": "Extra flag", "type": "string"},
+						"--allow": {"description": "Allowed host", "type": "array", "items": {"type": "string", "enum": ["a", "b"]}}
+					}, "required": ["workspace"]}
+				},
+				"required": ["package_arguments"]
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pkg.RegistryType = "npm"
+			tt.pkg.Identifier = "@example/weather"
+			schema := configschema.Generate(apiv0.ServerJSON{Name: "io.github.example/weather", Packages: []model.Package{tt.pkg}})
+
+			actual, err := json.Marshal(struct {
+				Properties map[string]*configschema.Schema `json:"properties,omitempty"`
+				Required   []string                        `json:"required,omitempty"`
+			}{schema.Properties, schema.Required})
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func TestGenerate_Alternatives(t *testing.T) {
+	t.Run("single remote", func(t *testing.T) {
+		schema := configschema.Generate(apiv0.ServerJSON{
+			Name:        "com.example/weather",
+			Title:       "Weather",
+			Description: "Weather lookups",
+			Remotes:     []model.Transport{{Type: "streamable-http", URL: "https://{region}.example.com/{tenant}/mcp"}},
+		})
+
+		actual, err := json.Marshal(schema)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "Weather",
+			"description": "Weather lookups",
+			"type": "object",
+			"properties": {"url_variables": {"type": "object", "properties": {
+				"region": {"type": "string"},
+				"tenant": {"type": "string"}
+			}, "required": ["region", "tenant"]}},
+			"required": ["url_variables"]
+		}`, string(actual))
+	})
+
+	t.Run("package and remote", func(t *testing.T) {
+		schema := configschema.Generate(apiv0.ServerJSON{
+			Name:     "com.example/weather",
+			Packages: []model.Package{{RegistryType: "pypi", Identifier: "weather-mcp"}},
+			Remotes:  []model.Transport{{Type: "sse", URL: "https://example.com/sse"}},
+		})
+
+		assert.Equal(t, "com.example/weather", schema.Title)
+		assert.Empty(t, schema.Properties)
+		require.Len(t, schema.OneOf, 2)
+		assert.Equal(t, "pypi weather-mcp", schema.OneOf[0].Title)
+		assert.Equal(t, "pypi weather-mcp", schema.OneOf[0].Properties[configschema.OptionProperty].Const)
+		assert.Equal(t, "sse https://example.com/sse", schema.OneOf[1].Properties[configschema.OptionProperty].Const)
+		for _, option := range schema.OneOf {
+			assert.Equal(t, []string{configschema.OptionProperty}, option.Required)
+		}
+	})
+}
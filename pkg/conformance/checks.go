@@ -0,0 +1,282 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// check is one conformance check. Checks run in order and may rely on state left by earlier ones.
+type check struct {
+	name string
+	fn   func(r *run, ctx context.Context) error
+}
+
+var checks = []check{
+	{"publish", (*run).checkPublish},
+	{"get by id", (*run).checkGet},
+	{"list", (*run).checkList},
+	{"version ordering", (*run).checkVersionOrdering},
+	{"duplicate version", (*run).checkDuplicateVersion},
+	{"error codes", (*run).checkErrorCodes},
+}
+
+// publish sends a server to the publish endpoint, returning the status code and the decoded
+// response when it succeeded
+func (r *run) publish(ctx context.Context, server apiv0.ServerJSON) (int, *apiv0.ServerJSON, error) {
+	body, err := json.Marshal(server)
+	if err != nil {
+		return 0, nil, err
+	}
+	status, content, err := r.do(ctx, http.MethodPost, "/v0/publish", r.token, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if status != http.StatusOK {
+		return status, nil, fmt.Errorf("publishing %s %s: %w", server.Name, server.Version, apiv0.ParseErrorResponse(status, content))
+	}
+	var published apiv0.ServerJSON
+	if err := json.Unmarshal(content, &published); err != nil {
+		return status, nil, fmt.Errorf("publishing %s %s: invalid response: %w", server.Name, server.Version, err)
+	}
+	return status, &published, nil
+}
+
+// get fetches a server version by its registry ID
+func (r *run) get(ctx context.Context, id string) (*apiv0.ServerJSON, error) {
+	status, content, err := r.do(ctx, http.MethodGet, "/v0/servers/"+url.PathEscape(id), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("GET /v0/servers/%s: %w", id, apiv0.ParseErrorResponse(status, content))
+	}
+	var server apiv0.ServerJSON
+	if err := json.Unmarshal(content, &server); err != nil {
+		return nil, fmt.Errorf("GET /v0/servers/%s: invalid response: %w", id, err)
+	}
+	return &server, nil
+}
+
+// list returns every version of the named server, following pagination
+func (r *run) list(ctx context.Context, name, version string) ([]apiv0.ServerJSON, error) {
+	var versions []apiv0.ServerJSON
+	cursor := ""
+	for {
+		query := url.Values{"search": {name}}
+		if version != "" {
+			query.Set("version", version)
+		}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		path := "/v0/servers?" + query.Encode()
+		status, content, err := r.do(ctx, http.MethodGet, path, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %w", path, apiv0.ParseErrorResponse(status, content))
+		}
+
+		var page apiv0.ServerListResponse
+		if err := json.Unmarshal(content, &page); err != nil {
+			return nil, fmt.Errorf("GET %s: invalid response: %w", path, err)
+		}
+		if page.Metadata.Count != len(page.Servers) {
+			return nil, fmt.Errorf("GET %s: metadata.count is %d but the page has %d servers", path, page.Metadata.Count, len(page.Servers))
+		}
+		// search may match other names, so keep only the named server
+		for _, server := range page.Servers {
+			if server.Name == name {
+				versions = append(versions, server)
+			}
+		}
+
+		if page.Metadata.NextCursor == "" {
+			return versions, nil
+		}
+		if page.Metadata.NextCursor == cursor {
+			return nil, fmt.Errorf("GET %s: next_cursor did not advance", path)
+		}
+		cursor = page.Metadata.NextCursor
+	}
+}
+
+// testServer returns a minimal server.json for a version of the run's test server
+func (r *run) testServer(version, description string) apiv0.ServerJSON {
+	return apiv0.ServerJSON{
+		Name:        r.name,
+		Description: description,
+		Version:     version,
+	}
+}
+
+func (r *run) checkPublish(ctx context.Context) error {
+	if r.token == "" {
+		return errSkipped{"no credentials"}
+	}
+	r.name = r.serverName("conformance")
+
+	_, published, err := r.publish(ctx, r.testServer("1.0.0", "Conformance test server"))
+	if err != nil {
+		return err
+	}
+	if published.Name != r.name || published.Version != "1.0.0" {
+		return fmt.Errorf("response names %s %s, want %s 1.0.0", published.Name, published.Version, r.name)
+	}
+	if published.Meta == nil || published.Meta.Official == nil || published.Meta.Official.ID == "" {
+		return errors.New("response has no registry metadata with an id")
+	}
+	if !published.Meta.Official.IsLatest {
+		return errors.New("the first version of a server is not marked is_latest")
+	}
+	r.published = published
+	return nil
+}
+
+func (r *run) checkGet(ctx context.Context) error {
+	if r.published == nil {
+		return errSkipped{"publish failed"}
+	}
+	server, err := r.get(ctx, r.published.Meta.Official.ID)
+	if err != nil {
+		return err
+	}
+	if err := compareServers(r.published, server); err != nil {
+		return fmt.Errorf("server read back differs from the publish response: %w", err)
+	}
+
+	status, content, err := r.do(ctx, http.MethodGet, "/v0/servers/00000000-0000-0000-0000-000000000000", "", nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNotFound {
+		return fmt.Errorf("GET of an unknown id: registry responded %d, want 404: %s", status, content)
+	}
+	return nil
+}
+
+func (r *run) checkList(ctx context.Context) error {
+	if r.published == nil {
+		return errSkipped{"publish failed"}
+	}
+	versions, err := r.list(ctx, r.name, "")
+	if err != nil {
+		return err
+	}
+	if len(versions) != 1 {
+		return fmt.Errorf("listing %s returned %d versions, want 1", r.name, len(versions))
+	}
+	return compareServers(r.published, &versions[0])
+}
+
+func (r *run) checkVersionOrdering(ctx context.Context) error {
+	if r.published == nil {
+		return errSkipped{"publish failed"}
+	}
+	// Publish out of order: the latest version is the highest, not the most recent
+	for _, version := range []string{"1.2.0", "1.1.0"} {
+		if _, _, err := r.publish(ctx, r.testServer(version, "Conformance test server")); err != nil {
+			return err
+		}
+	}
+
+	versions, err := r.list(ctx, r.name, "")
+	if err != nil {
+		return err
+	}
+	var latest []string
+	for _, server := range versions {
+		if server.Meta != nil && server.Meta.Official != nil && server.Meta.Official.IsLatest {
+			latest = append(latest, server.Version)
+		}
+	}
+	if len(versions) != 3 {
+		return fmt.Errorf("listing %s returned %d versions, want 3", r.name, len(versions))
+	}
+	if len(latest) != 1 || latest[0] != "1.2.0" {
+		return fmt.Errorf("versions marked is_latest are %v, want [1.2.0]", latest)
+	}
+
+	latestOnly, err := r.list(ctx, r.name, "latest")
+	if err != nil {
+		return err
+	}
+	if len(latestOnly) != 1 || latestOnly[0].Version != "1.2.0" {
+		return fmt.Errorf("version=latest returned %d versions, want only 1.2.0", len(latestOnly))
+	}
+	return nil
+}
+
+func (r *run) checkDuplicateVersion(ctx context.Context) error {
+	if r.published == nil {
+		return errSkipped{"publish failed"}
+	}
+	status, _, err := r.publish(ctx, r.testServer("1.0.0", "A different description"))
+	if err == nil {
+		return errors.New("republishing an existing version succeeded")
+	}
+	if status < 400 || status >= 500 {
+		return err
+	}
+
+	server, err := r.get(ctx, r.published.Meta.Official.ID)
+	if err != nil {
+		return err
+	}
+	if server.Description != r.published.Description {
+		return errors.New("a rejected republish changed the original version")
+	}
+	return nil
+}
+
+func (r *run) checkErrorCodes(ctx context.Context) error {
+	valid, err := json.Marshal(apiv0.ServerJSON{Name: r.serverName("conformance-errors"), Description: "Never published", Version: "1.0.0"})
+	if err != nil {
+		return err
+	}
+
+	cases := []struct {
+		name      string
+		token     string
+		body      []byte
+		want      int
+		anyErr    bool   // any 4xx status is accepted
+		wantCode  string // the error code the response must carry, if any
+		wantField string
+	}{
+		{name: "publish without a token", body: valid, anyErr: true},
+		{name: "publish with an invalid token", token: "not-a-token", body: valid, want: http.StatusUnauthorized},
+		{name: "publish malformed JSON", token: r.token, body: []byte(`{"name": `), anyErr: true},
+		{
+			name: "publish without a name", token: r.token, body: []byte(`{"description": "No name", "version": "1.0.0"}`), anyErr: true,
+			wantCode: apiv0.ErrorCodeRequired, wantField: "/name",
+		},
+	}
+	for _, tc := range cases {
+		status, content, err := r.do(ctx, http.MethodPost, "/v0/publish", tc.token, tc.body)
+		if err != nil {
+			return err
+		}
+		if (tc.anyErr && (status < 400 || status >= 500)) || (!tc.anyErr && status != tc.want) {
+			want := fmt.Sprint(tc.want)
+			if tc.anyErr {
+				want = "4xx"
+			}
+			return fmt.Errorf("%s: registry responded %d, want %s: %s", tc.name, status, want, content)
+		}
+		if tc.wantCode == "" {
+			continue
+		}
+		if problem := apiv0.ParseErrorResponse(status, content); problem.Code != tc.wantCode || problem.Field != tc.wantField {
+			return fmt.Errorf("%s: error is %q at %q, want %q at %q: %s", tc.name, problem.Code, problem.Field, tc.wantCode, tc.wantField, content)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+// Package conformance checks that a registry implements the MCP registry API.
+//
+// A Suite publishes servers to a running registry and reads them back, checking
+// publishing, listing, lookup by ID, latest-version ordering, duplicate rejection and
+// error status codes, and that each example server.json round-trips unchanged. It
+// works against any implementation reachable over HTTP:
+//
+//	suite := conformance.Suite{
+//		BaseURL:     "https://registry.example.com",
+//		Credentials: conformance.AnonymousAuth("https://registry.example.com"),
+//		Namespace:   "io.modelcontextprotocol.anonymous",
+//	}
+//	report := suite.Run(ctx)
+//	report.WriteText(os.Stdout)
+//	if !report.Passed() {
+//		os.Exit(1)
+//	}
+//
+// Servers are published under Namespace with a per-run suffix, so the checks can be
+// run repeatedly against the same registry. Examples are published concurrently, each
+// under a name of its own. Examples with remotes can only be published
+// once per registry, since a remote URL belongs to a single server. The suite never
+// edits or deletes servers.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// CredentialProvider supplies the Registry JWT the suite publishes with. The token
+// must allow publishing under the suite's Namespace.
+type CredentialProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// CredentialFunc adapts a function to a CredentialProvider
+type CredentialFunc func(ctx context.Context) (string, error)
+
+// Token calls f
+func (f CredentialFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticToken publishes with a Registry JWT obtained in advance
+func StaticToken(token string) CredentialProvider {
+	return CredentialFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// AnonymousAuth exchanges nothing for an anonymous Registry JWT at POST /v0/auth/none,
+// which allows publishing under io.modelcontextprotocol.anonymous. The registry must
+// have anonymous auth enabled.
+func AnonymousAuth(baseURL string) CredentialProvider {
+	return CredentialFunc(func(ctx context.Context) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(baseURL, "/")+"/v0/auth/none", nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("anonymous auth failed: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("anonymous auth failed: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("anonymous auth failed: registry responded %d: %s", resp.StatusCode, body)
+		}
+
+		var token struct {
+			RegistryToken string `json:"registry_token"`
+		}
+		if err := json.Unmarshal(body, &token); err != nil || token.RegistryToken == "" {
+			return "", fmt.Errorf("anonymous auth returned no registry_token: %s", body)
+		}
+		return token.RegistryToken, nil
+	})
+}
+
+// Suite is a conformance run against one registry
+type Suite struct {
+	// BaseURL is the registry's root URL, such as https://registry.example.com
+	BaseURL string
+	// Credentials supplies the token to publish with
+	Credentials CredentialProvider
+	// Namespace is the reverse-DNS namespace the credentials may publish under
+	Namespace string
+	// Examples are server.json documents to publish and read back, such as those
+	// returned by ExamplesFromMarkdown. Their names are moved into Namespace.
+	Examples []Example
+	// RunID distinguishes this run's server names from earlier runs; defaults to the current time
+	RunID string
+	// HTTPClient sends the suite's requests; defaults to a client with a 30 second timeout
+	HTTPClient *http.Client
+	// Concurrency is how many examples are published at once; defaults to 8
+	Concurrency int
+}
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	// StatusSkip means the check could not run because a check it depends on failed
+	StatusSkip Status = "skip"
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Check    string        `json:"check"`
+	Status   Status        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report lists the outcome of every check in a run, in the order they ran
+type Report struct {
+	BaseURL string   `json:"base_url"`
+	RunID   string   `json:"run_id"`
+	Results []Result `json:"results"`
+}
+
+// Passed reports whether no check failed. Skipped checks follow from a failure, so a
+// report with skips has failed too.
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if result.Status != StatusPass {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures returns the results that did not pass
+func (r *Report) Failures() []Result {
+	var failures []Result
+	for _, result := range r.Results {
+		if result.Status != StatusPass {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// WriteText writes a human-readable summary of the report
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	passed := 0
+	for _, result := range r.Results {
+		if result.Status == StatusPass {
+			passed++
+		}
+		fmt.Fprintf(&b, "%-4s  %s", strings.ToUpper(string(result.Status)), result.Check)
+		if result.Message != "" {
+			fmt.Fprintf(&b, ": %s", result.Message)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d/%d checks passed against %s\n", passed, len(r.Results), r.BaseURL)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Run runs every check against the registry and reports the outcome of each. Errors
+// talking to the registry are reported as failed checks rather than returned.
+func (s Suite) Run(ctx context.Context) *Report {
+	if s.RunID == "" {
+		s.RunID = time.Now().UTC().Format("20060102t150405")
+	}
+	if s.HTTPClient == nil {
+		s.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if s.Concurrency <= 0 {
+		s.Concurrency = 8
+	}
+	s.BaseURL = strings.TrimSuffix(s.BaseURL, "/")
+
+	r := &run{suite: s, report: &Report{BaseURL: s.BaseURL, RunID: s.RunID}}
+	if token, err := s.Credentials.Token(ctx); err != nil {
+		r.record("credentials", 0, fmt.Errorf("failed to get a token: %w", err))
+	} else {
+		r.token = token
+	}
+
+	for _, check := range checks {
+		start := time.Now()
+		err := check.fn(r, ctx)
+		r.record(check.name, time.Since(start), err)
+	}
+	r.checkExamples(ctx)
+	return r.report
+}
+
+// checkExamples checks the suite's examples with a pool of Concurrency workers. Each example
+// is published under its own name, so examples don't interfere with each other, and results
+// are recorded in the examples' order.
+func (r *run) checkExamples(ctx context.Context) {
+	type outcome struct {
+		duration time.Duration
+		err      error
+	}
+	outcomes := make([]outcome, len(r.suite.Examples))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(r.suite.Concurrency, len(r.suite.Examples)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				start := time.Now()
+				err := r.checkExample(ctx, r.suite.Examples[i])
+				outcomes[i] = outcome{time.Since(start), err}
+			}
+		}()
+	}
+	for i := range r.suite.Examples {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, example := range r.suite.Examples {
+		r.record(fmt.Sprintf("example at line %d", example.Line), outcomes[i].duration, outcomes[i].err)
+	}
+}
+
+// errSkipped marks a check that depends on one that failed
+type errSkipped struct {
+	reason string
+}
+
+func (e errSkipped) Error() string {
+	return e.reason
+}
+
+// run is the state shared by the checks of one Run
+type run struct {
+	suite  Suite
+	report *Report
+	token  string
+
+	// Set by the publish check for the checks that read the server back
+	name      string
+	published *apiv0.ServerJSON
+}
+
+func (r *run) record(check string, duration time.Duration, err error) {
+	result := Result{Check: check, Status: StatusPass, Duration: duration}
+	if err != nil {
+		result.Status = StatusFail
+		if skipped, ok := err.(errSkipped); ok {
+			result.Status = StatusSkip
+			err = skipped
+		}
+		result.Message = err.Error()
+	}
+	r.report.Results = append(r.report.Results, result)
+}
+
+// serverName returns a name under the suite's namespace that is unique to this run
+func (r *run) serverName(base string) string {
+	return r.suite.Namespace + "/" + base + "-" + r.suite.RunID
+}
+
+// do sends a request to the registry, returning the status code and body
+func (r *run) do(ctx context.Context, method, path, token string, body []byte) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.suite.BaseURL+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := r.suite.HTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: failed to read response: %w", method, path, err)
+	}
+	return resp.StatusCode, content, nil
+}
@@ -0,0 +1,152 @@
+package conformance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/conformance"
+	"github.com/modelcontextprotocol/registry/pkg/registry"
+)
+
+// newRegistry serves an in-process registry backed by the memory database
+func newRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := registry.NewConfig()
+	cfg.JWTPrivateKey = "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"
+	cfg.EnableAnonymousAuth = true
+	cfg.EnableRegistryValidation = false
+	cfg.SeedFrom = ""
+
+	reg, err := registry.New(context.Background(), registry.WithConfig(cfg), registry.WithDatabase(registry.NewMemoryDB()))
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, reg.Shutdown(context.Background())) })
+
+	server := httptest.NewServer(reg)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSuite_InProcessRegistry(t *testing.T) {
+	server := newRegistry(t)
+	examples, err := conformance.ExamplesFromMarkdown(filepath.Join("..", "..", "docs", "reference", "server-json", "generic-server-json.md"))
+	require.NoError(t, err)
+	require.NotEmpty(t, examples)
+
+	suite := conformance.Suite{
+		BaseURL:     server.URL,
+		Credentials: conformance.AnonymousAuth(server.URL),
+		Namespace:   "io.modelcontextprotocol.anonymous",
+		Examples:    examples,
+	}
+	report := suite.Run(context.Background())
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteText(&out))
+	assert.True(t, report.Passed(), out.String())
+	assert.Len(t, report.Results, 6+len(examples))
+	assert.Contains(t, out.String(), "checks passed against "+server.URL)
+
+	t.Run("runs are repeatable against the same registry", func(t *testing.T) {
+		// Examples with remotes cannot be republished, since remote URLs are unique
+		rerun := suite
+		rerun.RunID = report.RunID + "-again"
+		rerun.Examples = nil
+		again := rerun.Run(context.Background())
+		assert.True(t, again.Passed(), again.Failures())
+	})
+
+	t.Run("report is structured", func(t *testing.T) {
+		data, err := json.Marshal(report)
+		require.NoError(t, err)
+		var decoded conformance.Report
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, report.Results, decoded.Results)
+		assert.Equal(t, conformance.StatusPass, decoded.Results[0].Status)
+		assert.Equal(t, "publish", decoded.Results[0].Check)
+	})
+}
+
+func TestSuite_ExamplesSharingAName(t *testing.T) {
+	server := newRegistry(t)
+	example := []byte(`{"name": "io.github.example/weather", "description": "Weather lookups", "version": "1.0.0"}`)
+	invalid := []byte(`{"name": "io.github.example/weather", "version": "1.0.0"}`)
+
+	report := conformance.Suite{
+		BaseURL:     server.URL,
+		Credentials: conformance.AnonymousAuth(server.URL),
+		Namespace:   "io.modelcontextprotocol.anonymous",
+		Examples:    []conformance.Example{{Content: example, Line: 10}, {Content: invalid, Line: 20}, {Content: example, Line: 30}},
+		Concurrency: 3,
+	}.Run(context.Background())
+
+	// Each example is published under its own name, so a failing one doesn't affect the rest,
+	// and the same version can be published twice
+	examples := report.Results[len(report.Results)-3:]
+	assert.Equal(t, "example at line 10", examples[0].Check)
+	assert.Equal(t, conformance.StatusPass, examples[0].Status, examples[0].Message)
+	assert.Equal(t, "example at line 20", examples[1].Check)
+	assert.Equal(t, conformance.StatusFail, examples[1].Status)
+	assert.Equal(t, "example at line 30", examples[2].Check)
+	assert.Equal(t, conformance.StatusPass, examples[2].Status, examples[2].Message)
+}
+
+func TestSuite_ReportsFailures(t *testing.T) {
+	// A registry that accepts every publish, including duplicates, and never marks a latest version
+	var published []apiv0.ServerJSON
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v0/publish", func(w http.ResponseWriter, r *http.Request) {
+		var server apiv0.ServerJSON
+		if err := json.NewDecoder(r.Body).Decode(&server); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		server.Meta = &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{ID: "6f1c2e1a-3b7d-4c52-9a0e-2d8f5b4c7e90"}}
+		published = append(published, server)
+		_ = json.NewEncoder(w).Encode(server)
+	})
+	stub := httptest.NewServer(mux)
+	defer stub.Close()
+
+	report := conformance.Suite{
+		BaseURL:     stub.URL,
+		Credentials: conformance.StaticToken("token"),
+		Namespace:   "com.example",
+		RunID:       "test",
+	}.Run(context.Background())
+
+	assert.False(t, report.Passed())
+	statuses := map[string]conformance.Status{}
+	for _, result := range report.Results {
+		statuses[result.Check] = result.Status
+	}
+	assert.Equal(t, map[string]conformance.Status{
+		"publish":           conformance.StatusFail, // not marked is_latest
+		"get by id":         conformance.StatusSkip,
+		"list":              conformance.StatusSkip,
+		"version ordering":  conformance.StatusSkip,
+		"duplicate version": conformance.StatusSkip,
+		"error codes":       conformance.StatusFail, // accepts publishes without a token
+	}, statuses)
+	assert.Equal(t, "com.example/conformance-test", published[0].Name)
+	assert.Len(t, report.Failures(), 6)
+}
+
+func TestSuite_CredentialFailure(t *testing.T) {
+	stub := httptest.NewServer(http.NotFoundHandler())
+	defer stub.Close()
+
+	report := conformance.Suite{BaseURL: stub.URL, Credentials: conformance.AnonymousAuth(stub.URL), Namespace: "com.example"}.Run(context.Background())
+	require.NotEmpty(t, report.Results)
+	assert.Equal(t, "credentials", report.Results[0].Check)
+	assert.Equal(t, conformance.StatusFail, report.Results[0].Status)
+	assert.Contains(t, report.Results[0].Message, "404")
+}
@@ -0,0 +1,115 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// Example is a server.json document to publish and read back
+type Example struct {
+	Content []byte
+	// Line is where the example starts in its source, to identify it in reports
+	Line int
+}
+
+// jsonBlock matches fenced JSON code blocks in markdown, capturing their contents
+var jsonBlock = regexp.MustCompile("(?s)```json\n(.*?)\n```")
+
+// ExamplesFromMarkdown extracts every fenced JSON code block in a markdown file, such as
+// docs/reference/server-json/generic-server-json.md, as an example
+func ExamplesFromMarkdown(path string) ([]Example, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read examples: %w", err)
+	}
+
+	matches := jsonBlock.FindAllSubmatchIndex(b, -1)
+	examples := make([]Example, len(matches))
+	for i, match := range matches {
+		start, end := match[2], match[3]
+		examples[i] = Example{
+			Content: b[start:end],
+			Line:    1 + bytes.Count(b[:start], []byte{'\n'}), // line numbers start at 1
+		}
+	}
+	return examples, nil
+}
+
+// checkExample publishes an example under the suite's namespace and checks that the registry
+// serves it back unchanged. Examples run concurrently, so checkExample must not modify r.
+func (r *run) checkExample(ctx context.Context, example Example) error {
+	if r.token == "" {
+		return errSkipped{"no credentials"}
+	}
+
+	var expected apiv0.ServerJSON
+	if err := json.Unmarshal(example.Content, &expected); err != nil {
+		return fmt.Errorf("example isn't valid server.json: %w", err)
+	}
+	// Move the example into the namespace the credentials can publish to. Examples often
+	// share a name, so each gets its line as a suffix to keep one failure from cascading.
+	base := expected.Name
+	if _, name, found := strings.Cut(expected.Name, "/"); found {
+		base = name
+	}
+	expected.Name = r.serverName(fmt.Sprintf("%s-l%d", base, example.Line))
+
+	_, published, err := r.publish(ctx, expected)
+	if err != nil {
+		return err
+	}
+	if published.Meta == nil || published.Meta.Official == nil || published.Meta.Official.ID == "" {
+		return errors.New("publish response has no registry metadata with an id")
+	}
+
+	actual, err := r.get(ctx, published.Meta.Official.ID)
+	if err != nil {
+		return err
+	}
+	return compareServers(&expected, actual)
+}
+
+// compareServers reports the first field in which a served server differs from what was
+// expected. Registry metadata is ignored, and publisher metadata is only compared if expected has it.
+func compareServers(expected, actual *apiv0.ServerJSON) error {
+	if expected.Name != actual.Name {
+		return fmt.Errorf("name mismatch: expected %q, got %q", expected.Name, actual.Name)
+	}
+	if expected.Description != actual.Description {
+		return fmt.Errorf("description mismatch: expected %q, got %q", expected.Description, actual.Description)
+	}
+	if expected.Status != "" && expected.Status != actual.Status {
+		return fmt.Errorf("status mismatch: expected %q, got %q", expected.Status, actual.Status)
+	}
+	if expected.Version != actual.Version {
+		return fmt.Errorf("version mismatch: expected %q, got %q", expected.Version, actual.Version)
+	}
+	if !reflect.DeepEqual(expected.Repository, actual.Repository) {
+		return fmt.Errorf("repository mismatch: expected %+v, got %+v", expected.Repository, actual.Repository)
+	}
+	if !reflect.DeepEqual(expected.Packages, actual.Packages) {
+		return fmt.Errorf("packages mismatch: expected %+v, got %+v", expected.Packages, actual.Packages)
+	}
+	if !reflect.DeepEqual(expected.Remotes, actual.Remotes) {
+		return fmt.Errorf("remotes mismatch: expected %+v, got %+v", expected.Remotes, actual.Remotes)
+	}
+
+	if expected.Meta != nil && expected.Meta.PublisherProvided != nil {
+		if actual.Meta == nil || actual.Meta.PublisherProvided == nil {
+			return errors.New("expected publisher metadata, but got none")
+		}
+		if !reflect.DeepEqual(expected.Meta.PublisherProvided, actual.Meta.PublisherProvided) {
+			return fmt.Errorf("publisher metadata mismatch: expected %+v, got %+v", expected.Meta.PublisherProvided, actual.Meta.PublisherProvided)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+// Package launch turns the packages and remotes in a server.json into concrete instructions for
+// starting or connecting to the server, so clients need not each choose a package and assemble
+// its command line:
+//
+//	resolution := launch.Resolve(server, launch.Capabilities{Runtimes: []string{"npx", "docker"}, OS: "linux", Arch: "amd64"})
+//
+// Each plan is one way to run the server, most preferred first: packages built for the client's
+// platform, then other packages, then remotes, each in the order server.json lists them.
+// Packages whose runtime the client lacks, or built for another platform, are skipped with the
+// reason why.
+//
+// Fixed values and defaults are filled in. A value the user must still supply is left in the
+// command line, environment, URL or headers as a {name} placeholder, and listed once under the
+// plan's inputs by that name. Optional inputs without a value or default are left out.
+package launch
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// placeholderRegex matches the {name} placeholders in a value or URL
+var placeholderRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Capabilities describes what a client can run
+type Capabilities struct {
+	Runtimes       []string `json:"runtimes,omitempty" doc:"Commands the client can launch packages with, such as npx, uvx, docker or dnx, and mcpb for MCPB bundles; any runtime when empty" example:"[\"npx\",\"docker\"]"`
+	OS             string   `json:"os,omitempty" doc:"Client operating system, as Go names it (darwin, linux or windows); MCPB bundles built for another one are skipped" example:"linux"`
+	Arch           string   `json:"arch,omitempty" doc:"Client CPU architecture, as Go names it (amd64 or arm64); MCPB bundles built for another one are skipped" example:"arm64"`
+	ExcludeRemotes bool     `json:"exclude_remotes,omitempty" doc:"Leave out remotes, for clients that only run servers locally"`
+}
+
+// Kind tells whether a plan starts a package or connects to a remote
+type Kind string
+
+const (
+	KindPackage Kind = "package"
+	KindRemote  Kind = "remote"
+)
+
+// Plan is one way to run a server
+type Plan struct {
+	Kind      Kind   `json:"kind"`
+	Option    string `json:"option" doc:"The package or remote the plan runs, titled as in the configuration schema: registry type and identifier, or transport type and URL"`
+	Transport string `json:"transport" doc:"How the client talks to the server once started: stdio, streamable-http or sse"`
+	// Command and Args start a package; MCPB bundles have none, since the bundle says how it is run
+	Command    string            `json:"command,omitempty" example:"npx"`
+	Args       []string          `json:"args,omitempty" example:"[\"-y\",\"@example/weather@1.0.0\"]"`
+	Env        map[string]string `json:"env,omitempty" doc:"Environment variables to start the package with"`
+	Download   string            `json:"download,omitempty" doc:"URL of the MCPB bundle to install"`
+	FileSHA256 string            `json:"file_sha256,omitempty" doc:"SHA-256 the downloaded bundle must have"`
+	// URL and Headers are the remote to connect to, or a package's endpoint for HTTP transports
+	URL            string                `json:"url,omitempty"`
+	Headers        map[string]string     `json:"headers,omitempty"`
+	Authentication *model.Authentication `json:"authentication,omitempty"`
+	Inputs         []PlanInput           `json:"inputs,omitempty" doc:"Values the user must supply, each replacing its {name} placeholder"`
+}
+
+// PlanInput is a value the user must supply before a plan can run
+type PlanInput struct {
+	Name        string       `json:"name" doc:"Placeholder name, written {name} where the value goes"`
+	Location    string       `json:"location" doc:"The server.json field declaring it: environment_variables, runtime_arguments, package_arguments, headers or url_variables" enum:"environment_variables,runtime_arguments,package_arguments,headers,url_variables"`
+	Description string       `json:"description,omitempty"`
+	Format      model.Format `json:"format,omitempty"`
+	IsSecret    bool         `json:"is_secret,omitempty"`
+	Choices     []string     `json:"choices,omitempty"`
+}
+
+// Skipped is a package or remote no plan was made for
+type Skipped struct {
+	Option string `json:"option"`
+	Reason string `json:"reason" example:"runtime uvx is not available"`
+}
+
+// Resolution is the plans for running a server, most preferred first
+type Resolution struct {
+	Plans   []Plan    `json:"plans"`
+	Skipped []Skipped `json:"skipped,omitempty"`
+}
+
+// launcher is how packages from a registry are started when they give no runtime hint
+type launcher struct {
+	command string
+	// args come before the package's runtime arguments whenever this command is used
+	args []string
+	// reference names the package version on the command line, empty for MCPB bundles
+	reference func(pkg model.Package) string
+}
+
+var launchers = map[string]launcher{
+	model.RegistryTypeNPM: {
+		command:   "npx",
+		args:      []string{"-y"},
+		reference: func(pkg model.Package) string { return pkg.Identifier + "@" + pkg.Version },
+	},
+	model.RegistryTypePyPI: {
+		command:   "uvx",
+		reference: func(pkg model.Package) string { return pkg.Identifier + "==" + pkg.Version },
+	},
+	model.RegistryTypeOCI: {
+		command:   "docker",
+		args:      []string{"run", "-i", "--rm"},
+		reference: ociReference,
+	},
+	model.RegistryTypeNuGet: {
+		command:   "dnx",
+		reference: func(pkg model.Package) string { return pkg.Identifier + "@" + pkg.Version },
+	},
+	model.RegistryTypeMCPB: {
+		command: "mcpb",
+	},
+}
+
+// ociReference is the image to run: the identifier, tagged with the version unless it already
+// names a tag or digest
+func ociReference(pkg model.Package) string {
+	if strings.Contains(pkg.Identifier, "@") || strings.LastIndex(pkg.Identifier, ":") > strings.LastIndex(pkg.Identifier, "/") {
+		return pkg.Identifier
+	}
+	return pkg.Identifier + ":" + pkg.Version
+}
+
+// Resolve returns the plans for running server with what the client can run
+func Resolve(server apiv0.ServerJSON, capabilities Capabilities) Resolution {
+	type ranked struct {
+		plan Plan
+		rank int
+	}
+	var plans []ranked
+	resolution := Resolution{Plans: []Plan{}}
+	for _, pkg := range server.Packages {
+		plan, rank, reason := packagePlan(pkg, capabilities)
+		if reason != "" {
+			resolution.Skipped = append(resolution.Skipped, Skipped{Option: plan.Option, Reason: reason})
+			continue
+		}
+		plans = append(plans, ranked{plan, rank})
+	}
+	for _, remote := range server.Remotes {
+		plan := remotePlan(remote)
+		if capabilities.ExcludeRemotes {
+			resolution.Skipped = append(resolution.Skipped, Skipped{Option: plan.Option, Reason: "remotes are excluded"})
+			continue
+		}
+		plans = append(plans, ranked{plan, 2})
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool { return plans[i].rank < plans[j].rank })
+	for _, p := range plans {
+		resolution.Plans = append(resolution.Plans, p.plan)
+	}
+	return resolution
+}
+
+// packagePlan returns the plan for starting pkg and its rank: 0 when it is built for the
+// client's platform, 1 otherwise. It returns the reason instead when the client cannot run it.
+func packagePlan(pkg model.Package, capabilities Capabilities) (Plan, int, string) {
+	plan := Plan{Kind: KindPackage, Option: pkg.RegistryType + " " + pkg.Identifier, Transport: pkg.Transport.Type}
+	launcher, ok := launchers[pkg.RegistryType]
+	if !ok {
+		return plan, 0, fmt.Sprintf("registry type %s is not supported", pkg.RegistryType)
+	}
+	command := launcher.command
+	if pkg.RunTimeHint != "" {
+		command = pkg.RunTimeHint
+	}
+	if len(capabilities.Runtimes) > 0 && !slices.Contains(capabilities.Runtimes, command) {
+		return plan, 0, fmt.Sprintf("runtime %s is not available", command)
+	}
+
+	rank := 1
+	if pkg.RegistryType == model.RegistryTypeMCPB {
+		os, arch := bundlePlatform(pkg.Identifier)
+		if (os != "" && capabilities.OS != "" && os != capabilities.OS) || (arch != "" && capabilities.Arch != "" && arch != capabilities.Arch) {
+			return plan, 0, fmt.Sprintf("built for %s", strings.Trim(os+"/"+arch, "/"))
+		}
+		if (os != "" && os == capabilities.OS) || (arch != "" && arch == capabilities.Arch) {
+			rank = 0
+		}
+		plan.Download, plan.FileSHA256 = pkg.Identifier, pkg.FileSHA256
+	}
+
+	r := &resolver{known: map[string]string{}}
+	var env []string
+	for _, variable := range pkg.EnvironmentVariables {
+		if value, use := r.resolve("environment_variables", variable.Name, variable.InputWithVariables); use {
+			if plan.Env == nil {
+				plan.Env = map[string]string{}
+			}
+			plan.Env[variable.Name] = value
+			env = append(env, variable.Name)
+		}
+	}
+	runtimeArgs := r.arguments("runtime_arguments", pkg.RuntimeArguments)
+	packageArgs := r.arguments("package_arguments", pkg.PackageArguments)
+
+	if pkg.RegistryType != model.RegistryTypeMCPB {
+		plan.Command = command
+		if command == launcher.command {
+			plan.Args = append(plan.Args, launcher.args...)
+		}
+		// Containers only see the environment variables passed to them
+		if pkg.RegistryType == model.RegistryTypeOCI {
+			for _, name := range env {
+				plan.Args = append(plan.Args, "-e", name)
+			}
+		}
+		plan.Args = append(plan.Args, runtimeArgs...)
+		plan.Args = append(plan.Args, launcher.reference(pkg))
+	}
+	plan.Args = append(plan.Args, packageArgs...)
+
+	if pkg.Transport.URL != "" {
+		plan.URL = r.fill(pkg.Transport.URL)
+	}
+	plan.Headers = r.headers(pkg.Transport.Headers)
+	plan.Inputs = r.inputs
+	return plan, rank, ""
+}
+
+// remotePlan returns the plan for connecting to remote. Each placeholder in its URL is an input.
+func remotePlan(remote model.Transport) Plan {
+	plan := Plan{
+		Kind:           KindRemote,
+		Option:         remote.Type + " " + remote.URL,
+		Transport:      remote.Type,
+		URL:            remote.URL,
+		Authentication: remote.Authentication,
+	}
+	r := &resolver{known: map[string]string{}}
+	for _, match := range placeholderRegex.FindAllStringSubmatch(remote.URL, -1) {
+		r.need("url_variables", match[1], model.Input{})
+	}
+	plan.Headers = r.headers(remote.Headers)
+	plan.Inputs = r.inputs
+	return plan
+}
+
+// bundlePlatform returns the operating system and architecture an MCPB bundle's file name says
+// it was built for, as Go names them, or empty strings when it names none
+func bundlePlatform(identifier string) (os, arch string) {
+	name := strings.ReplaceAll(strings.ToLower(path.Base(identifier)), "x86_64", "x64")
+	for _, token := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		switch token {
+		case "darwin", "macos", "mac", "osx":
+			os = "darwin"
+		case "linux":
+			os = "linux"
+		case "windows", "win", "win32", "win64":
+			os = "windows"
+		case "amd64", "x64":
+			arch = "amd64"
+		case "arm64", "aarch64":
+			arch = "arm64"
+		}
+	}
+	return os, arch
+}
+
+// resolver fills in the values of one package or remote, collecting the inputs left to the user
+type resolver struct {
+	// known maps the template variables of a package, its environment variable names and its
+	// arguments' names and value hints, to their values, for its transport URL
+	known  map[string]string
+	inputs []PlanInput
+}
+
+// resolve returns the value of an input and whether to use it: fixed values and defaults are
+// used as they are, and a required value the user must supply is a {name} placeholder. A
+// templated value has its variables' values and defaults filled in; it is used with placeholders
+// for the others only when it, or one of the variables left, is required.
+func (r *resolver) resolve(location, name string, input model.InputWithVariables) (string, bool) {
+	value, use := r.value(location, name, input)
+	if use {
+		r.known[name] = value
+	}
+	return value, use
+}
+
+func (r *resolver) value(location, name string, input model.InputWithVariables) (string, bool) {
+	switch {
+	case input.Value == "" && input.Default != "":
+		return input.Default, true
+	case input.Value == "" && input.IsRequired:
+		r.need(location, name, input.Input)
+		return "{" + name + "}", true
+	case input.Value == "":
+		return "", false
+	}
+
+	required := input.IsRequired
+	var missing []string
+	value := placeholderRegex.ReplaceAllStringFunc(input.Value, func(placeholder string) string {
+		variable, ok := input.Variables[placeholder[1:len(placeholder)-1]]
+		switch {
+		case !ok:
+			return placeholder
+		case variable.Value != "":
+			return variable.Value
+		case variable.Default != "":
+			return variable.Default
+		}
+		missing = append(missing, placeholder[1:len(placeholder)-1])
+		required = required || variable.IsRequired
+		return placeholder
+	})
+	if len(missing) > 0 && !required {
+		return "", false
+	}
+	for _, variable := range missing {
+		r.need(location, variable, input.Variables[variable])
+	}
+	return value, true
+}
+
+// need lists an input the user must supply, once per name
+func (r *resolver) need(location, name string, input model.Input) {
+	if slices.ContainsFunc(r.inputs, func(i PlanInput) bool { return i.Name == name }) {
+		return
+	}
+	r.inputs = append(r.inputs, PlanInput{
+		Name:        name,
+		Location:    location,
+		Description: input.Description,
+		Format:      input.Format,
+		IsSecret:    input.IsSecret,
+		Choices:     input.Choices,
+	})
+}
+
+// arguments returns the command line arguments for args. A named argument is its name followed
+// by its value. Positional arguments are named by their value hint, or by position when they
+// have none. Repeated arguments are given once.
+func (r *resolver) arguments(location string, args []model.Argument) []string {
+	var line []string
+	for i, arg := range args {
+		name := arg.ValueHint
+		if name == "" && arg.Type == model.ArgumentTypeNamed {
+			name = strings.TrimLeft(arg.Name, "-")
+		}
+		if name == "" {
+			name = fmt.Sprintf("%s_%d", strings.TrimSuffix(location, "s"), i+1)
+		}
+		value, use := r.resolve(location, name, arg.InputWithVariables)
+		if !use {
+			continue
+		}
+		if arg.Name != "" {
+			r.known[arg.Name] = value
+		}
+		if arg.Type == model.ArgumentTypeNamed {
+			line = append(line, arg.Name)
+		}
+		line = append(line, value)
+	}
+	return line
+}
+
+// headers returns the values of headers, keyed by name
+func (r *resolver) headers(headers []model.KeyValueInput) map[string]string {
+	var values map[string]string
+	for _, header := range headers {
+		if value, use := r.resolve("headers", header.Name, header.InputWithVariables); use {
+			if values == nil {
+				values = map[string]string{}
+			}
+			values[header.Name] = value
+		}
+	}
+	return values
+}
+
+// fill substitutes the package values known for the placeholders in a transport URL, leaving
+// the others for the client
+func (r *resolver) fill(template string) string {
+	return placeholderRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if value, ok := r.known[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
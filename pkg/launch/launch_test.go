@@ -0,0 +1,324 @@
+package launch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/launch"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func input(i model.Input) model.InputWithVariables {
+	return model.InputWithVariables{Input: i}
+}
+
+func TestResolve_CommandLines(t *testing.T) {
+	stdio := model.Transport{Type: model.TransportTypeStdio}
+	tests := []struct {
+		name     string
+		pkg      model.Package
+		expected launch.Plan
+	}{
+		{
+			name: "npm",
+			pkg:  model.Package{RegistryType: model.RegistryTypeNPM, Identifier: "@example/weather", Version: "1.2.0", Transport: stdio},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "npm @example/weather", Transport: "stdio",
+				Command: "npx", Args: []string{"-y", "@example/weather@1.2.0"},
+			},
+		},
+		{
+			name: "pypi",
+			pkg:  model.Package{RegistryType: model.RegistryTypePyPI, Identifier: "weather-mcp", Version: "0.3.1", Transport: stdio},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "pypi weather-mcp", Transport: "stdio",
+				Command: "uvx", Args: []string{"weather-mcp==0.3.1"},
+			},
+		},
+		{
+			name: "nuget",
+			pkg:  model.Package{RegistryType: model.RegistryTypeNuGet, Identifier: "Example.Weather", Version: "2.0.0", Transport: stdio},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "nuget Example.Weather", Transport: "stdio",
+				Command: "dnx", Args: []string{"Example.Weather@2.0.0"},
+			},
+		},
+		{
+			name: "oci tags the image with the version",
+			pkg:  model.Package{RegistryType: model.RegistryTypeOCI, Identifier: "docker.io/example/weather", Version: "1.0.0", Transport: stdio},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "oci docker.io/example/weather", Transport: "stdio",
+				Command: "docker", Args: []string{"run", "-i", "--rm", "docker.io/example/weather:1.0.0"},
+			},
+		},
+		{
+			name: "oci keeps a tag in the identifier",
+			pkg:  model.Package{RegistryType: model.RegistryTypeOCI, Identifier: "localhost:5000/weather:1.0.0", Version: "1.0.0", Transport: stdio},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "oci localhost:5000/weather:1.0.0", Transport: "stdio",
+				Command: "docker", Args: []string{"run", "-i", "--rm", "localhost:5000/weather:1.0.0"},
+			},
+		},
+		{
+			name: "runtime hint replaces the default runtime and its arguments",
+			pkg:  model.Package{RegistryType: model.RegistryTypeNPM, Identifier: "weather", Version: "1.0.0", RunTimeHint: "bunx", Transport: stdio},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "npm weather", Transport: "stdio",
+				Command: "bunx", Args: []string{"weather@1.0.0"},
+			},
+		},
+		{
+			name: "runtime and package arguments surround the package",
+			pkg: model.Package{
+				RegistryType: model.RegistryTypeNPM, Identifier: "snyk", Version: "1.1298.0", RunTimeHint: "npx", Transport: stdio,
+				RuntimeArguments: []model.Argument{{Type: model.ArgumentTypeNamed, Name: "--node-options", InputWithVariables: input(model.Input{Value: "--max-old-space-size=4096"})}},
+				PackageArguments: []model.Argument{
+					{Type: model.ArgumentTypePositional, InputWithVariables: input(model.Input{Value: "mcp"})},
+					{Type: model.ArgumentTypeNamed, Name: "-t", InputWithVariables: input(model.Input{Default: "stdio", Choices: []string{"stdio", "sse"}})},
+					{Type: model.ArgumentTypeNamed, Name: "--verbose", InputWithVariables: input(model.Input{Format: model.FormatBoolean})},
+				},
+			},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "npm snyk", Transport: "stdio",
+				Command: "npx", Args: []string{"-y", "--node-options", "--max-old-space-size=4096", "snyk@1.1298.0", "mcp", "-t", "stdio"},
+			},
+		},
+		{
+			name: "required values become placeholders",
+			pkg: model.Package{
+				RegistryType: model.RegistryTypePyPI, Identifier: "db-mcp", Version: "1.0.0", Transport: stdio,
+				PackageArguments: []model.Argument{
+					{Type: model.ArgumentTypeNamed, Name: "--host", InputWithVariables: input(model.Input{Default: "localhost", IsRequired: true})},
+					{Type: model.ArgumentTypeNamed, Name: "--port", InputWithVariables: input(model.Input{Format: model.FormatNumber})},
+					{Type: model.ArgumentTypePositional, ValueHint: "database_name", InputWithVariables: input(model.Input{Description: "Database", IsRequired: true})},
+					{Type: model.ArgumentTypePositional, InputWithVariables: input(model.Input{IsRequired: true})},
+				},
+				EnvironmentVariables: []model.KeyValueInput{
+					{Name: "DB_PASSWORD", InputWithVariables: input(model.Input{Description: "Password", IsRequired: true, IsSecret: true})},
+					{Name: "SSL_MODE", InputWithVariables: input(model.Input{Default: "prefer"})},
+					{Name: "DEBUG", InputWithVariables: input(model.Input{})},
+				},
+			},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "pypi db-mcp", Transport: "stdio",
+				Command: "uvx", Args: []string{"db-mcp==1.0.0", "--host", "localhost", "{database_name}", "{package_argument_4}"},
+				Env: map[string]string{"DB_PASSWORD": "{DB_PASSWORD}", "SSL_MODE": "prefer"},
+				Inputs: []launch.PlanInput{
+					{Name: "DB_PASSWORD", Location: "environment_variables", Description: "Password", IsSecret: true},
+					{Name: "database_name", Location: "package_arguments", Description: "Database"},
+					{Name: "package_argument_4", Location: "package_arguments"},
+				},
+			},
+		},
+		{
+			name: "templated values fill in variable defaults",
+			pkg: model.Package{
+				RegistryType: model.RegistryTypeOCI, Identifier: "mcp/filesystem", Version: "1.0.2", Transport: stdio,
+				RuntimeArguments: []model.Argument{{
+					Type: model.ArgumentTypeNamed, Name: "--mount", IsRepeated: true,
+					InputWithVariables: model.InputWithVariables{
+						Input: model.Input{Value: "type=bind,src={source_path},dst={target_path}", IsRequired: true},
+						Variables: map[string]model.Input{
+							"source_path": {Description: "Source path on host", Format: model.FormatFilePath, IsRequired: true},
+							"target_path": {Default: "/project"},
+						},
+					},
+				}},
+				EnvironmentVariables: []model.KeyValueInput{
+					{Name: "LOG_LEVEL", InputWithVariables: input(model.Input{Default: "info"})},
+					{Name: "TOKEN", InputWithVariables: model.InputWithVariables{
+						Input:     model.Input{Value: "Bearer {token}"},
+						Variables: map[string]model.Input{"token": {Description: "Optional token"}},
+					}},
+				},
+			},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "oci mcp/filesystem", Transport: "stdio",
+				Command: "docker",
+				Args:    []string{"run", "-i", "--rm", "-e", "LOG_LEVEL", "--mount", "type=bind,src={source_path},dst=/project", "mcp/filesystem:1.0.2"},
+				Env:     map[string]string{"LOG_LEVEL": "info"},
+				Inputs: []launch.PlanInput{
+					{Name: "source_path", Location: "runtime_arguments", Description: "Source path on host", Format: model.FormatFilePath},
+				},
+			},
+		},
+		{
+			name: "package transport URLs take the package's values",
+			pkg: model.Package{
+				RegistryType: model.RegistryTypeNPM, Identifier: "weather", Version: "1.0.0",
+				Transport: model.Transport{
+					Type: model.TransportTypeStreamableHTTP, URL: "http://{host}:{port}/mcp",
+					Headers: []model.KeyValueInput{{Name: "X-Region", InputWithVariables: input(model.Input{Default: "eu"})}},
+				},
+				PackageArguments: []model.Argument{{Type: model.ArgumentTypeNamed, Name: "--port", InputWithVariables: input(model.Input{Default: "8080"})}},
+				EnvironmentVariables: []model.KeyValueInput{
+					{Name: "host", InputWithVariables: input(model.Input{Description: "Host to listen on", IsRequired: true})},
+				},
+			},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "npm weather", Transport: "streamable-http",
+				Command: "npx", Args: []string{"-y", "weather@1.0.0", "--port", "8080"},
+				Env:     map[string]string{"host": "{host}"},
+				URL:     "http://{host}:8080/mcp",
+				Headers: map[string]string{"X-Region": "eu"},
+				Inputs:  []launch.PlanInput{{Name: "host", Location: "environment_variables", Description: "Host to listen on"}},
+			},
+		},
+		{
+			name: "mcpb bundles are downloaded rather than run",
+			pkg: model.Package{
+				RegistryType: model.RegistryTypeMCPB, Identifier: "https://github.com/example/weather/releases/download/v1.0.0/weather.mcpb",
+				Version: "1.0.0", FileSHA256: "fe333e598595000ae021bd27117db32ec69af6987f507ba7a63c90638ff633ce", Transport: stdio,
+				EnvironmentVariables: []model.KeyValueInput{{Name: "UNITS", InputWithVariables: input(model.Input{Default: "metric"})}},
+			},
+			expected: launch.Plan{
+				Kind: launch.KindPackage, Option: "mcpb https://github.com/example/weather/releases/download/v1.0.0/weather.mcpb", Transport: "stdio",
+				Env:        map[string]string{"UNITS": "metric"},
+				Download:   "https://github.com/example/weather/releases/download/v1.0.0/weather.mcpb",
+				FileSHA256: "fe333e598595000ae021bd27117db32ec69af6987f507ba7a63c90638ff633ce",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolution := launch.Resolve(apiv0.ServerJSON{Packages: []model.Package{tt.pkg}}, launch.Capabilities{})
+			require.Empty(t, resolution.Skipped)
+			require.Len(t, resolution.Plans, 1)
+			assert.Equal(t, tt.expected, resolution.Plans[0])
+		})
+	}
+}
+
+func TestResolve_Remotes(t *testing.T) {
+	remote := model.Transport{
+		Type: model.TransportTypeStreamableHTTP,
+		URL:  "https://{tenant}.example.com/mcp",
+		Headers: []model.KeyValueInput{
+			{Name: "Authorization", InputWithVariables: model.InputWithVariables{
+				Input:     model.Input{Value: "Bearer {api_key}", IsRequired: true},
+				Variables: map[string]model.Input{"api_key": {Description: "API key", IsSecret: true, IsRequired: true}},
+			}},
+			{Name: "X-Trace", InputWithVariables: input(model.Input{})},
+		},
+		Authentication: &model.Authentication{Scheme: model.AuthenticationSchemeBearer},
+	}
+	resolution := launch.Resolve(apiv0.ServerJSON{Remotes: []model.Transport{remote}}, launch.Capabilities{})
+	require.Len(t, resolution.Plans, 1)
+	assert.Equal(t, launch.Plan{
+		Kind: launch.KindRemote, Option: "streamable-http https://{tenant}.example.com/mcp", Transport: "streamable-http",
+		URL:            "https://{tenant}.example.com/mcp",
+		Headers:        map[string]string{"Authorization": "Bearer {api_key}"},
+		Authentication: &model.Authentication{Scheme: model.AuthenticationSchemeBearer},
+		Inputs: []launch.PlanInput{
+			{Name: "tenant", Location: "url_variables"},
+			{Name: "api_key", Location: "headers", Description: "API key", IsSecret: true},
+		},
+	}, resolution.Plans[0])
+}
+
+func TestResolve_Capabilities(t *testing.T) {
+	stdio := model.Transport{Type: model.TransportTypeStdio}
+	bundle := func(file string) model.Package {
+		return model.Package{RegistryType: model.RegistryTypeMCPB, Identifier: "https://example.com/releases/" + file, Version: "1.0.0", Transport: stdio}
+	}
+	server := apiv0.ServerJSON{
+		Packages: []model.Package{
+			{RegistryType: model.RegistryTypeNPM, Identifier: "weather", Version: "1.0.0", Transport: stdio},
+			{RegistryType: model.RegistryTypePyPI, Identifier: "weather", Version: "1.0.0", Transport: stdio},
+			bundle("weather-linux-x86_64.mcpb"),
+			bundle("weather-darwin-arm64.mcpb"),
+			bundle("weather-win32-x64.mcpb"),
+			bundle("weather.mcpb"),
+			{RegistryType: "cargo", Identifier: "weather", Version: "1.0.0", Transport: stdio},
+		},
+		Remotes: []model.Transport{{Type: model.TransportTypeSSE, URL: "https://weather.example.com/sse"}},
+	}
+
+	tests := []struct {
+		name         string
+		capabilities launch.Capabilities
+		plans        []string
+		skipped      map[string]string
+	}{
+		{
+			name:         "anything",
+			capabilities: launch.Capabilities{},
+			plans: []string{
+				"npm weather", "pypi weather",
+				"mcpb https://example.com/releases/weather-linux-x86_64.mcpb", "mcpb https://example.com/releases/weather-darwin-arm64.mcpb",
+				"mcpb https://example.com/releases/weather-win32-x64.mcpb", "mcpb https://example.com/releases/weather.mcpb",
+				"sse https://weather.example.com/sse",
+			},
+			skipped: map[string]string{"cargo weather": "registry type cargo is not supported"},
+		},
+		{
+			name:         "only some runtimes",
+			capabilities: launch.Capabilities{Runtimes: []string{"uvx"}},
+			plans:        []string{"pypi weather", "sse https://weather.example.com/sse"},
+			skipped: map[string]string{
+				"npm weather":   "runtime npx is not available",
+				"cargo weather": "registry type cargo is not supported",
+				"mcpb https://example.com/releases/weather-linux-x86_64.mcpb": "runtime mcpb is not available",
+				"mcpb https://example.com/releases/weather-darwin-arm64.mcpb": "runtime mcpb is not available",
+				"mcpb https://example.com/releases/weather-win32-x64.mcpb":    "runtime mcpb is not available",
+				"mcpb https://example.com/releases/weather.mcpb":              "runtime mcpb is not available",
+			},
+		},
+		{
+			name:         "platform bundles first, others skipped",
+			capabilities: launch.Capabilities{Runtimes: []string{"npx", "mcpb"}, OS: "linux", Arch: "amd64"},
+			plans: []string{
+				"mcpb https://example.com/releases/weather-linux-x86_64.mcpb",
+				"npm weather", "mcpb https://example.com/releases/weather.mcpb",
+				"sse https://weather.example.com/sse",
+			},
+			skipped: map[string]string{
+				"pypi weather":  "runtime uvx is not available",
+				"cargo weather": "registry type cargo is not supported",
+				"mcpb https://example.com/releases/weather-darwin-arm64.mcpb": "built for darwin/arm64",
+				"mcpb https://example.com/releases/weather-win32-x64.mcpb":    "built for windows/amd64",
+			},
+		},
+		{
+			name:         "architecture alone",
+			capabilities: launch.Capabilities{Runtimes: []string{"mcpb"}, Arch: "arm64", ExcludeRemotes: true},
+			plans: []string{
+				"mcpb https://example.com/releases/weather-darwin-arm64.mcpb",
+				"mcpb https://example.com/releases/weather.mcpb",
+			},
+			skipped: map[string]string{
+				"npm weather":   "runtime npx is not available",
+				"pypi weather":  "runtime uvx is not available",
+				"cargo weather": "registry type cargo is not supported",
+				"mcpb https://example.com/releases/weather-linux-x86_64.mcpb": "built for linux/amd64",
+				"mcpb https://example.com/releases/weather-win32-x64.mcpb":    "built for windows/amd64",
+				"sse https://weather.example.com/sse":                         "remotes are excluded",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolution := launch.Resolve(server, tt.capabilities)
+			plans := []string{}
+			for _, plan := range resolution.Plans {
+				plans = append(plans, plan.Option)
+			}
+			assert.Equal(t, tt.plans, plans)
+			skipped := map[string]string{}
+			for _, s := range resolution.Skipped {
+				skipped[s.Option] = s.Reason
+			}
+			assert.Equal(t, tt.skipped, skipped)
+		})
+	}
+}
+
+func TestResolve_NoPackagesOrRemotes(t *testing.T) {
+	resolution := launch.Resolve(apiv0.ServerJSON{}, launch.Capabilities{})
+	assert.Equal(t, launch.Resolution{Plans: []launch.Plan{}}, resolution)
+}
@@ -14,6 +14,8 @@ const (
 	RegistryURLNPM    = "https://registry.npmjs.org"
 	RegistryURLPyPI   = "https://pypi.org"
 	RegistryURLDocker = "https://docker.io"
+	RegistryURLGHCR   = "https://ghcr.io"
+	RegistryURLQuay   = "https://quay.io"
 	RegistryURLNuGet  = "https://api.nuget.org"
 	RegistryURLGitHub = "https://github.com"
 	RegistryURLGitLab = "https://gitlab.com"
@@ -26,6 +28,14 @@ const (
 	TransportTypeStdio          = "stdio"
 )
 
+// Authentication Schemes - how clients authenticate to remote servers
+const (
+	AuthenticationSchemeNone   = "none"
+	AuthenticationSchemeBearer = "bearer"
+	AuthenticationSchemeOAuth2 = "oauth2"
+	AuthenticationSchemeHeader = "header"
+)
+
 // Runtime Hints - supported package runtime hints
 const (
 	RuntimeHintNPX    = "npx"
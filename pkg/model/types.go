@@ -7,13 +7,41 @@ const (
 	StatusActive     Status = "active"
 	StatusDeprecated Status = "deprecated"
 	StatusDeleted    Status = "deleted"
+	// StatusPending holds a version for admin approval; it is set by the registry, never by publishers
+	StatusPending Status = "pending"
+	// StatusRejected marks a held version an admin declined; it is set by the registry, never by publishers
+	StatusRejected Status = "rejected"
 )
 
+// Hidden reports whether versions with this status are held back by admin review, and so
+// hidden from public endpoints
+func (s Status) Hidden() bool {
+	return s == StatusPending || s == StatusRejected
+}
+
 // Transport represents transport configuration with optional URL templating
 type Transport struct {
 	Type    string          `json:"type"`
 	URL     string          `json:"url,omitempty"`
 	Headers []KeyValueInput `json:"headers,omitempty"`
+	// Authentication tells clients how to authenticate to a remote. Package transports cannot have it.
+	Authentication *Authentication `json:"authentication,omitempty"`
+}
+
+// Authentication describes how clients authenticate to a remote server. It only names the
+// scheme and where to get a credential; the credential itself is never part of a server.json.
+type Authentication struct {
+	// Scheme is one of AuthenticationSchemeNone, AuthenticationSchemeBearer,
+	// AuthenticationSchemeOAuth2 or AuthenticationSchemeHeader
+	Scheme string `json:"scheme"`
+	// AuthorizationURL and TokenURL are the OAuth 2.0 endpoints, for the oauth2 scheme
+	AuthorizationURL string `json:"authorization_url,omitempty"`
+	TokenURL         string `json:"token_url,omitempty"`
+	// Scopes are the OAuth 2.0 scopes to request, for the oauth2 scheme
+	Scopes []string `json:"scopes,omitempty"`
+	// HeaderName is the header carrying the credential, for the header scheme. The remote's
+	// headers must declare it.
+	HeaderName string `json:"header_name,omitempty"`
 }
 
 // Package represents a package configuration
@@ -23,14 +51,17 @@ type Package struct {
 	// RegistryBaseURL is the base URL of the package registry
 	RegistryBaseURL string `json:"registry_base_url,omitempty"`
 	// Identifier is the package identifier - either a package name (for registries) or URL (for direct downloads)
-	Identifier           string          `json:"identifier" minLength:"1"`
-	Version              string          `json:"version" minLength:"1"`
-	FileSHA256           string          `json:"file_sha256,omitempty"`
-	RunTimeHint          string          `json:"runtime_hint,omitempty"`
-	Transport            Transport       `json:"transport,omitempty"`
-	RuntimeArguments     []Argument      `json:"runtime_arguments,omitempty"`
-	PackageArguments     []Argument      `json:"package_arguments,omitempty"`
-	EnvironmentVariables []KeyValueInput `json:"environment_variables,omitempty"`
+	Identifier string `json:"identifier" minLength:"1"`
+	Version    string `json:"version" minLength:"1"`
+	FileSHA256 string `json:"file_sha256,omitempty"`
+	// UnversionedReleaseTag opts an MCPB package out of checking that the release tag in its URL
+	// matches Version, for projects whose release tags are not versions
+	UnversionedReleaseTag bool            `json:"unversioned_release_tag,omitempty"`
+	RunTimeHint           string          `json:"runtime_hint,omitempty"`
+	Transport             Transport       `json:"transport,omitempty"`
+	RuntimeArguments      []Argument      `json:"runtime_arguments,omitempty"`
+	PackageArguments      []Argument      `json:"package_arguments,omitempty"`
+	EnvironmentVariables  []KeyValueInput `json:"environment_variables,omitempty"`
 }
 
 // Repository represents a source code repository as defined in the spec
@@ -41,6 +72,13 @@ type Repository struct {
 	Subfolder string `json:"subfolder,omitempty"`
 }
 
+// Icon represents an image that client UIs can display for a server
+type Icon struct {
+	Src      string   `json:"src" minLength:"1"`
+	MimeType string   `json:"mimeType" minLength:"1"`
+	Sizes    []string `json:"sizes,omitempty"`
+}
+
 // Format represents the input format type
 type Format string
 
@@ -60,6 +98,8 @@ type Input struct {
 	IsSecret    bool     `json:"is_secret,omitempty"`
 	Default     string   `json:"default,omitempty"`
 	Choices     []string `json:"choices,omitempty"`
+	// AllowAbsolute permits absolute paths in the value and default of a file_path input
+	AllowAbsolute bool `json:"allow_absolute,omitempty"`
 }
 
 // InputWithVariables represents an input that can contain variables
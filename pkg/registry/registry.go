@@ -0,0 +1,379 @@
+// Package registry embeds an MCP registry in another program.
+//
+// New assembles the same HTTP API that cmd/registry serves, with storage, auth
+// methods and telemetry supplied by the embedder where the defaults do not fit:
+//
+//	reg, err := registry.New(ctx,
+//		registry.WithConfig(cfg),
+//		registry.WithDatabase(myDatabase),
+//		registry.WithAuthProviders(mySSOProvider),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	reg.Start()
+//	defer reg.Shutdown(context.Background())
+//	http.ListenAndServe(":8080", reg)
+package registry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+
+	v0auth "github.com/modelcontextprotocol/registry/internal/api/handlers/v0/auth"
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/importer"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+)
+
+// Registry is an assembled registry. It serves the registry API as an http.Handler;
+// Start runs its background jobs and Shutdown releases what New acquired.
+type Registry struct {
+	handler   http.Handler
+	startJobs func()
+	stopJobs  context.CancelFunc
+	jobs      sync.WaitGroup
+	closers   []func(context.Context) error
+	changes   *service.ChangeFeed
+}
+
+type options struct {
+	config        *Config
+	db            Database
+	authProviders []AuthProvider
+	meterProvider metric.MeterProvider
+}
+
+// Option customizes a Registry built by New
+type Option func(*options)
+
+// WithConfig uses cfg instead of reading the configuration from the environment
+func WithConfig(cfg *Config) Option {
+	return func(o *options) {
+		o.config = cfg
+	}
+}
+
+// WithDatabase stores servers in db instead of the database named by the configuration.
+// The caller keeps ownership: Shutdown does not close db.
+func WithDatabase(db Database) Option {
+	return func(o *options) {
+		o.db = db
+	}
+}
+
+// WithAuthProviders serves each provider as an additional auth method alongside the built-in ones
+func WithAuthProviders(providers ...AuthProvider) Option {
+	return func(o *options) {
+		o.authProviders = append(o.authProviders, providers...)
+	}
+}
+
+// WithTelemetry records metrics with provider instead of the registry's own Prometheus exporter
+func WithTelemetry(provider metric.MeterProvider) Option {
+	return func(o *options) {
+		o.meterProvider = provider
+	}
+}
+
+// New assembles a registry. ctx bounds setup only: connecting to the database,
+// applying migrations and importing seed data.
+func New(ctx context.Context, opts ...Option) (*Registry, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.config == nil {
+		o.config = config.NewConfig()
+	}
+	cfg := o.config
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	// Already checked by Validate
+	repositorySources, _ := config.ParseRepositorySources(cfg.RepositorySources)
+	validators.SetRepositorySources(repositorySources)
+
+	providers := make([]v0auth.Provider, 0, len(o.authProviders))
+	for _, provider := range o.authProviders {
+		providers = append(providers, providerAdapter{provider: provider})
+	}
+	if err := v0auth.ValidateProviders(providers); err != nil {
+		return nil, err
+	}
+
+	r := &Registry{}
+	ok := false
+	defer func() {
+		// Release whatever was acquired if assembly fails part way
+		if !ok {
+			_ = r.Shutdown(context.Background())
+		}
+	}()
+
+	db := o.db
+	var pgDB *database.PostgreSQL
+	if db == nil {
+		var err error
+		db, pgDB, err = openDatabase(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		r.closers = append(r.closers, func(context.Context) error { return db.Close() })
+	}
+
+	// Import seed data if seed source is provided
+	if cfg.SeedFrom != "" {
+		log.Printf("Importing data from %s...", cfg.SeedFrom)
+		seedCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		err := importer.NewService(db,
+			importer.WithConflictPolicy(importer.ConflictPolicy(cfg.SeedConflictPolicy)),
+			importer.WithSignatureVerification(cfg.SeedSignatureFrom, seedVerificationKeys(cfg)),
+			importer.WithFilter(importer.Filter{Only: cfg.SeedOnly, Exclude: cfg.SeedExclude, Limit: cfg.SeedLimit}),
+		).ImportFromPath(seedCtx, cfg.SeedFrom)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to import seed data: %v", err)
+		} else {
+			log.Println("Data import completed successfully")
+		}
+	}
+
+	metrics, err := newMetrics(cfg, o.meterProvider)
+	if err != nil {
+		return nil, err
+	}
+	if o.meterProvider == nil {
+		r.closers = append(r.closers, metrics.shutdown)
+	}
+	if pgDB != nil {
+		if err := metrics.ObserveDatabasePool(func() telemetry.PoolStats {
+			stat := pgDB.PoolStats()
+			return telemetry.PoolStats{
+				InUse:    int64(stat.AcquiredConns()),
+				Idle:     int64(stat.IdleConns()),
+				Waits:    stat.EmptyAcquireCount(),
+				WaitTime: stat.EmptyAcquireWaitTime(),
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if replicated, ok := db.(*database.ReadReplicaDatabase); ok {
+		if err := metrics.ObserveReplicaFallbacks(replicated.Fallbacks); err != nil {
+			return nil, err
+		}
+	}
+
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	r.stopJobs = stopJobs
+
+	notifications := service.NewNotificationDispatcher(db, cfg)
+	serviceOpts := []service.Option{service.WithMetrics(metrics.Metrics), service.WithNotifications(notifications)}
+	var fetches *service.FetchRecorder
+	if cfg.FetchMetricsFlushInterval > 0 {
+		fetches = service.NewFetchRecorder(db, cfg.FetchMetricsFlushInterval, metrics.Metrics)
+		serviceOpts = append(serviceOpts, service.WithFetchRecorder(fetches))
+	}
+	if cfg.EventsMaxConnections > 0 {
+		r.changes = service.NewChangeFeed(cfg.EventsBufferSize)
+		serviceOpts = append(serviceOpts, service.WithChangeFeed(r.changes))
+	}
+	if pgDB != nil && cfg.CacheInvalidationChannel != "" {
+		serviceOpts = append(serviceOpts, service.WithCacheInvalidator(jobCtx, pgDB.Notifier(cfg.CacheInvalidationChannel)))
+	}
+	registryService := service.NewRegistryService(db, cfg, serviceOpts...)
+
+	mux := http.NewServeMux()
+	if _, err := router.NewHumaAPI(cfg, registryService, db, mux, metrics.Metrics, providers...); err != nil {
+		return nil, err
+	}
+	r.handler = router.SecurityHeaders(cfg, mux)
+
+	r.startJobs = func() {
+		r.runJob(jobCtx, notifications.Run)
+
+		// Count fetches of server details for the namespace metrics, writing them in batches
+		if fetches != nil {
+			r.runJob(jobCtx, fetches.Run)
+		}
+
+		// Start the retention job if a policy is configured
+		if cfg.RetentionKeepVersions > 0 {
+			policy := service.RetentionPolicyFromConfig(cfg)
+			log.Printf("Retention enabled: keeping %d versions per server and anything newer than %d days, every %s",
+				policy.KeepVersions, cfg.RetentionKeepDays, cfg.RetentionInterval)
+			r.runJob(jobCtx, service.NewRetentionJob(registryService, policy, cfg.RetentionInterval).Run)
+		}
+
+		// Start the remote health job if enabled
+		if cfg.RemoteHealthInterval > 0 {
+			log.Printf("Remote health checks enabled: probing remotes every %s, flagging after %d failed checks",
+				cfg.RemoteHealthInterval, cfg.RemoteHealthFailureThreshold)
+			r.runJob(jobCtx, service.NewRemoteHealthJobFromConfig(registryService, cfg).Run)
+		}
+
+		// Start the package link job if enabled
+		if cfg.LinkCheckInterval > 0 {
+			log.Printf("Package link checks enabled: checking MCPB downloads every %s, flagging links broken for %s",
+				cfg.LinkCheckInterval, cfg.LinkCheckBrokenAfter)
+			r.runJob(jobCtx, service.NewPackageLinkJobFromConfig(registryService, cfg).Run)
+		}
+
+		// Start the field usage job if enabled
+		if cfg.FieldUsageInterval > 0 {
+			log.Printf("Field usage reports enabled: counting server.json fields every %s", cfg.FieldUsageInterval)
+			r.runJob(jobCtx, service.NewFieldUsageJob(registryService, cfg.FieldUsageInterval).Run)
+		}
+
+		// Start the maintenance loop purging expired rows if enabled
+		if cfg.MaintenanceInterval > 0 {
+			scheduler := service.NewMaintenanceScheduler(cfg.MaintenanceTaskTimeout, metrics.Metrics)
+			for _, task := range service.DatabaseMaintenanceTasks(db, cfg.MaintenanceInterval) {
+				scheduler.Register(task)
+			}
+			r.runJob(jobCtx, scheduler.Run)
+		}
+	}
+
+	ok = true
+	return r, nil
+}
+
+// ServeHTTP serves the registry API
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.handler.ServeHTTP(w, req)
+}
+
+// Start runs publish notification delivery and the background jobs enabled by the
+// configuration, such as version retention, remote health and package link checks, until Shutdown is called
+func (r *Registry) Start() {
+	r.startJobs()
+}
+
+// CloseStreams ends the open streams of GET /v0/events, which would otherwise keep an HTTP
+// server's shutdown waiting until its deadline. Servers built with api.NewServer call it when
+// they start shutting down; new streams are refused from then on.
+func (r *Registry) CloseStreams() {
+	if r.changes != nil {
+		r.changes.Close()
+	}
+}
+
+// Shutdown stops background jobs, including notification delivery, and waits until ctx is done
+// for the runs in progress to return. It then closes the database and telemetry if New opened
+// them. It does not wait for in-flight requests; shut down the HTTP server serving the registry first.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.CloseStreams()
+
+	var errs []error
+	if r.stopJobs != nil {
+		r.stopJobs()
+		stopped := make(chan struct{})
+		go func() {
+			r.jobs.Wait()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("background jobs did not stop: %w", ctx.Err()))
+		}
+	}
+
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if err := r.closers[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	r.closers = nil
+	return errors.Join(errs...)
+}
+
+// runJob runs job in the background until ctx is cancelled, counting it among the jobs Shutdown waits for
+func (r *Registry) runJob(ctx context.Context, job func(context.Context)) {
+	r.jobs.Add(1)
+	go func() {
+		defer r.jobs.Done()
+		job(ctx)
+	}()
+}
+
+// openDatabase connects to the database named by the configuration, applying any pending migrations
+func openDatabase(ctx context.Context, cfg *Config) (Database, *database.PostgreSQL, error) {
+	switch cfg.DatabaseType {
+	case config.DatabaseTypeMemory:
+		return database.NewMemoryDB(), nil, nil
+	case config.DatabaseTypePostgreSQL:
+		connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		pgDB, err := database.NewPostgreSQL(connectCtx, cfg.DatabaseURL, database.PoolOptionsFromConfig(cfg))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		}
+		if cfg.DatabaseReadURL == "" {
+			return pgDB, pgDB, nil
+		}
+		replica, err := database.NewPostgreSQLReplica(connectCtx, cfg.DatabaseReadURL, database.PoolOptionsFromConfig(cfg))
+		if err != nil {
+			_ = pgDB.Close()
+			return nil, nil, fmt.Errorf("failed to connect to PostgreSQL read replica: %w", err)
+		}
+		return database.NewReadReplicaDatabase(pgDB, replica), pgDB, nil
+	case config.DatabaseTypeSQLite:
+		sqliteDB, err := database.NewSQLite(ctx, cfg.SQLitePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open SQLite database: %w", err)
+		}
+		return sqliteDB, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid database type: %s; supported types: %s, %s, %s",
+			cfg.DatabaseType, config.DatabaseTypeMemory, config.DatabaseTypePostgreSQL, config.DatabaseTypeSQLite)
+	}
+}
+
+type registryMetrics struct {
+	*telemetry.Metrics
+	shutdown telemetry.ShutdownFunc
+}
+
+// newMetrics creates the registry's instruments on provider, or on its own Prometheus exporter if provider is nil
+// seedVerificationKeys decodes the keys seeds must be signed by; Config.Validate has checked them
+func seedVerificationKeys(cfg *Config) []ed25519.PublicKey {
+	keys := make([]ed25519.PublicKey, 0, len(cfg.SeedVerificationKeys))
+	for _, value := range cfg.SeedVerificationKeys {
+		if key, err := config.ParseVerificationKey(value); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func newMetrics(cfg *Config, provider metric.MeterProvider) (registryMetrics, error) {
+	if provider == nil {
+		shutdown, metrics, err := telemetry.InitMetrics(cfg.Version)
+		if err != nil {
+			return registryMetrics{}, fmt.Errorf("failed to initialize metrics: %w", err)
+		}
+		return registryMetrics{Metrics: metrics, shutdown: shutdown}, nil
+	}
+
+	metrics, err := telemetry.NewMetrics(provider.Meter(telemetry.Namespace))
+	if err != nil {
+		return registryMetrics{}, fmt.Errorf("failed to initialize metrics: %w", err)
+	}
+	return registryMetrics{Metrics: metrics}, nil
+}
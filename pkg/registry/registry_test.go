@@ -0,0 +1,141 @@
+package registry_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/registry"
+)
+
+// staticProvider grants publish access to com.example/* for a single shared secret
+type staticProvider struct {
+	name   string
+	secret string
+}
+
+func (p staticProvider) Name() string {
+	return p.name
+}
+
+func (p staticProvider) Authenticate(_ context.Context, credential string) (*registry.Identity, error) {
+	if credential != p.secret {
+		return nil, errors.New("unknown credential")
+	}
+	return &registry.Identity{
+		Subject: "ci-bot",
+		Permissions: []registry.Permission{
+			{Action: registry.PermissionActionPublish, ResourcePattern: "com.example/*"},
+		},
+	}, nil
+}
+
+func testConfig() *registry.Config {
+	cfg := registry.NewConfig()
+	cfg.JWTPrivateKey = "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"
+	cfg.EnableRegistryValidation = false
+	return cfg
+}
+
+func TestEmbeddedRegistry(t *testing.T) {
+	db := registry.NewMemoryDB()
+	reg, err := registry.New(context.Background(),
+		registry.WithConfig(testConfig()),
+		registry.WithDatabase(db),
+		registry.WithAuthProviders(staticProvider{name: "internal-sso", secret: "s3cret"}),
+	)
+	require.NoError(t, err)
+	reg.Start()
+	t.Cleanup(func() { assert.NoError(t, reg.Shutdown(context.Background())) })
+
+	server := httptest.NewServer(reg)
+	t.Cleanup(server.Close)
+
+	post := func(path, token string, body any) *http.Response {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL+path, bytes.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("custom auth provider rejects unknown credentials", func(t *testing.T) {
+		resp := post("/v0/auth/internal-sso", "", map[string]string{"credential": "wrong"})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	var token string
+	t.Run("custom auth provider issues a registry token", func(t *testing.T) {
+		resp := post("/v0/auth/internal-sso", "", map[string]string{"credential": "s3cret"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var tokenResponse struct {
+			RegistryToken string `json:"registry_token"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&tokenResponse))
+		require.NotEmpty(t, tokenResponse.RegistryToken)
+		token = tokenResponse.RegistryToken
+	})
+
+	t.Run("publish and list", func(t *testing.T) {
+		require.NotEmpty(t, token)
+		resp := post("/v0/publish", token, apiv0.ServerJSON{
+			Name:        "com.example/embedded",
+			Description: "Published to an embedded registry",
+			Version:     "1.0.0",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = post("/v0/publish", token, apiv0.ServerJSON{
+			Name:        "org.other/embedded",
+			Description: "Outside the provider's grant",
+			Version:     "1.0.0",
+		})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/v0/servers", nil)
+		require.NoError(t, err)
+		listResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer listResp.Body.Close()
+		require.Equal(t, http.StatusOK, listResp.StatusCode)
+		var list apiv0.ServerListResponse
+		require.NoError(t, json.NewDecoder(listResp.Body).Decode(&list))
+		require.Len(t, list.Servers, 1)
+		assert.Equal(t, "com.example/embedded", list.Servers[0].Name)
+
+		// The server landed in the embedder's database
+		stored, _, err := db.List(context.Background(), nil, "", 10)
+		require.NoError(t, err)
+		assert.Len(t, stored, 1)
+	})
+}
+
+func TestNew_RejectsInvalidAuthProviders(t *testing.T) {
+	for _, providers := range [][]registry.AuthProvider{
+		{staticProvider{name: "github-at"}},
+		{staticProvider{name: "Not Valid"}},
+		{staticProvider{name: "sso"}, staticProvider{name: "sso"}},
+	} {
+		_, err := registry.New(context.Background(),
+			registry.WithConfig(testConfig()),
+			registry.WithDatabase(registry.NewMemoryDB()),
+			registry.WithAuthProviders(providers...),
+		)
+		assert.Error(t, err, providers[0].Name())
+	}
+}
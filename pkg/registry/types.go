@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// Config is the registry configuration. NewConfig reads it from MCP_REGISTRY_* environment variables.
+type Config = config.Config
+
+// Database types, for embedders supplying their own storage
+type (
+	// Database is the storage interface the registry runs on
+	Database = database.Database
+	// ServerFilter selects the servers Database.List and Database.Count return
+	ServerFilter = database.ServerFilter
+	// Projection selects which parts of each server Database.List loads
+	Projection = database.Projection
+	// AuthChallenge is a single-use nonce issued for DNS authentication
+	AuthChallenge = database.AuthChallenge
+)
+
+// Projections a Database may be asked for
+const (
+	ProjectionFull    = database.ProjectionFull
+	ProjectionSummary = database.ProjectionSummary
+)
+
+// Errors a Database reports; the registry maps them to HTTP status codes
+var (
+	ErrNotFound          = database.ErrNotFound
+	ErrAlreadyExists     = database.ErrAlreadyExists
+	ErrInvalidInput      = database.ErrInvalidInput
+	ErrInvalidCursor     = database.ErrInvalidCursor
+	ErrDatabase          = database.ErrDatabase
+	ErrInvalidVersion    = database.ErrInvalidVersion
+	ErrMaxServersReached = database.ErrMaxServersReached
+)
+
+// Permission types, for embedders supplying their own auth providers
+type (
+	// Permission allows an action on the servers matching a resource pattern such as "com.example/*"
+	Permission = auth.Permission
+	// PermissionAction is what a Permission allows
+	PermissionAction = auth.PermissionAction
+)
+
+// Actions a Permission may allow
+const (
+	PermissionActionPublish        = auth.PermissionActionPublish
+	PermissionActionPublishVersion = auth.PermissionActionPublishVersion
+	PermissionActionEdit           = auth.PermissionActionEdit
+)
+
+// Identity is who an AuthProvider authenticated and what they may do
+type Identity struct {
+	// Subject names the authenticated principal in the issued token and audit logs
+	Subject     string
+	Permissions []Permission
+}
+
+// AuthProvider exchanges credentials of the embedder's choosing for Registry JWTs.
+// Each provider is served at POST /v0/auth/{name} with a body of {"credential": "..."}.
+type AuthProvider interface {
+	// Name identifies the auth method: lowercase letters, digits and hyphens, not
+	// shadowing a built-in method such as "github-at" or "dns"
+	Name() string
+	// Authenticate checks a credential; an error is reported to the client as 401
+	Authenticate(ctx context.Context, credential string) (*Identity, error)
+}
+
+// NewConfig reads the registry configuration from the environment
+func NewConfig() *Config {
+	return config.NewConfig()
+}
+
+// NewMemoryDB returns an empty in-memory Database, suitable for tests and ephemeral registries
+func NewMemoryDB() Database {
+	return database.NewMemoryDB()
+}
+
+// providerAdapter presents an AuthProvider to the auth handlers
+type providerAdapter struct {
+	provider AuthProvider
+}
+
+func (a providerAdapter) Name() string {
+	return a.provider.Name()
+}
+
+func (a providerAdapter) Authenticate(ctx context.Context, credential string) (string, []auth.Permission, error) {
+	identity, err := a.provider.Authenticate(ctx, credential)
+	if err != nil {
+		return "", nil, err
+	}
+	if identity == nil {
+		return "", nil, nil
+	}
+	return identity.Subject, identity.Permissions, nil
+}
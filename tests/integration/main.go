@@ -1,348 +1,100 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
+	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"reflect"
-	"regexp"
-	"strings"
-	"time"
 
-	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/pkg/conformance"
+	"github.com/modelcontextprotocol/registry/pkg/registry"
 )
 
 const registryURL = "http://localhost:8080"
 
 func main() {
 	log.SetFlags(0)
-	if err := run(); err != nil {
-		log.Fatal(err)
-	}
-}
+	embedded := flag.Bool("embedded", false, "Run against an in-process registry on a temporary SQLite database instead of the one at "+registryURL)
+	flag.Parse()
 
-func run() error {
 	examplesPath := filepath.Join("docs", "reference", "server-json", "generic-server-json.md")
-	examples, err := getExamples(examplesPath)
-	if err != nil {
-		log.Fatalf("failed to extract examples: %v", err)
-	}
-	log.Printf("Found %d examples in %q\n", len(examples), examplesPath)
-
-	// Set up authentication using the new login workflow
-	err = setupPublisherAuth()
-	if err != nil {
-		log.Fatalf("failed to set up publisher auth: %v", err)
-	}
-	defer cleanupPublisherAuth()
-
-	return publish(examples)
-}
-
-func setupPublisherAuth() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "./bin/publisher", "login", "none", "--registry", registryURL)
-	cmd.WaitDelay = 100 * time.Millisecond
-
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("login failed: %s", string(out))
-	}
-
-	log.Printf("Publisher login successful: %s", strings.TrimSpace(string(out)))
-	return nil
-}
-
-func cleanupPublisherAuth() {
-	// Clean up the token file
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return
-	}
-	tokenPath := filepath.Join(homeDir, ".mcp_publisher_token")
-	os.Remove(tokenPath)
-}
-
-func publish(examples []example) error {
-	published := 0
-	for _, example := range examples {
-		if err := publishExample(example); err != nil {
-			log.Printf("  ⛔ %v", err)
-			continue
-		}
-		published++
-	}
-
-	msg := fmt.Sprintf("published %d/%d examples", published, len(examples))
-	if published != len(examples) {
-		return errors.New(msg)
-	}
-	log.Println(msg)
-	return nil
-}
-
-func publishExample(example example) error {
-	log.Printf("Publishing example starting on line %d", example.line)
-
-	expected, err := parseExample(example)
-	if err != nil {
-		return err
-	}
-
-	if err := publishToRegistry(expected, example.line); err != nil {
-		return err
-	}
-
-	log.Print("  ✅ registry response matches example\n\n")
-	return nil
-}
-
-func parseExample(example example) (*apiv0.ServerJSON, error) {
-	expected := &apiv0.ServerJSON{}
-	if err := json.Unmarshal(example.content, expected); err != nil {
-		return nil, fmt.Errorf("example isn't valid JSON: %w", err)
-	}
-
-	// Remove any existing namespace prefix and add anonymous prefix
-	if !strings.HasPrefix(expected.Name, "io.modelcontextprotocol.anonymous/") {
-		parts := strings.SplitN(expected.Name, "/", 2)
-		serverName := parts[len(parts)-1]
-		expected.Name = "io.modelcontextprotocol.anonymous/" + serverName
-	}
-
-	return expected, nil
-}
-
-func publishToRegistry(expected *apiv0.ServerJSON, line int) error {
-	content, _ := json.Marshal(expected)
-	p := filepath.Join("bin", fmt.Sprintf("example-line-%d.json", line))
-	if err := os.WriteFile(p, content, 0600); err != nil {
-		return fmt.Errorf("failed to write example JSON to %s: %w", p, err)
-	}
-	defer os.Remove(p)
-
-	id, err := runPublisher(p)
+	examples, err := conformance.ExamplesFromMarkdown(examplesPath)
 	if err != nil {
-		log.Printf("  ⛔ Failed to get server ID: %v", err)
-		return err
+		log.Fatalf("failed to extract examples (run this test from the repo root): %v", err)
 	}
-	log.Printf("  📋 Got server ID: %s", id)
-
-	return verifyPublishedServer(id, expected)
-}
+	log.Printf("Found %d examples in %q", len(examples), examplesPath)
 
-func runPublisher(filePath string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "./bin/publisher", "publish", filePath)
-	cmd.WaitDelay = 100 * time.Millisecond
-
-	out, err := cmd.CombinedOutput()
-	output := strings.TrimSpace("publisher output:\n\t" + strings.ReplaceAll(string(out), "\n", "\n  \t"))
-	if err != nil {
-		return "", errors.New(output)
-	}
-	log.Println("  ✅", output)
-
-	// Get the server name from the file to look up the ID
-	serverName, err := getServerNameFromFile(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to get server name from file: %w", err)
+	baseURL := registryURL
+	if *embedded {
+		var stop func()
+		baseURL, stop = startEmbeddedRegistry()
+		defer stop()
 	}
 
-	// Add a small delay to ensure database consistency
-	time.Sleep(100 * time.Millisecond)
+	report := conformance.Suite{
+		BaseURL:     baseURL,
+		Credentials: conformance.AnonymousAuth(baseURL),
+		Namespace:   "io.modelcontextprotocol.anonymous",
+		Examples:    examples,
+	}.Run(context.Background())
 
-	// Find the server in the registry by name
-	return findServerIDByName(serverName)
-}
-
-func getServerNameFromFile(filePath string) (string, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return "", err
+	if err := report.WriteText(os.Stdout); err != nil {
+		log.Fatal(err)
 	}
-
-	var serverData map[string]any
-	if err := json.Unmarshal(data, &serverData); err != nil {
-		return "", err
+	if !report.Passed() {
+		// Exiting skips the deferred shutdown, which leaves nothing behind but the temporary directory
+		os.Exit(1)
 	}
-
-	// Handle both old ServerDetail format and new PublishRequest format
-	if server, exists := serverData["server"]; exists {
-		// New PublishRequest format
-		if serverMap, ok := server.(map[string]any); ok {
-			if name, ok := serverMap["name"].(string); ok {
-				return name, nil
-			}
-		}
-	} else if name, ok := serverData["name"].(string); ok {
-		// Old ServerDetail format
-		return name, nil
-	}
-
-	return "", errors.New("could not find server name in file")
 }
 
-func findServerIDByName(serverName string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registryURL+"/v0/servers", nil)
-	if err != nil {
-		return "", err
-	}
-
-	resp, err := http.DefaultClient.Do(req)
+// startEmbeddedRegistry serves a registry on a free local port, storing servers in a SQLite
+// database in a temporary directory, so the suite runs without Docker or PostgreSQL. It returns
+// the registry's base URL and a function that stops it and removes the database.
+func startEmbeddedRegistry() (string, func()) {
+	dir, err := os.MkdirTemp("", "registry-integration-")
 	if err != nil {
-		return "", err
+		log.Fatalf("failed to create database directory: %v", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("registry responded %d: %s", resp.StatusCode, string(body))
+	// The same settings as docker-compose.integration-test.yml
+	cfg := registry.NewConfig()
+	cfg.DatabaseType = config.DatabaseTypeSQLite
+	cfg.SQLitePath = filepath.Join(dir, "registry.db")
+	cfg.SeedFrom = ""
+	cfg.EnableRegistryValidation = false
+	cfg.EnableAnonymousAuth = true
+	if cfg.JWTPrivateKey == "" {
+		cfg.JWTPrivateKey = "bb2c6b424005acd5df47a9e2c87f446def86dd740c888ea3efb825b23f7ef47c"
 	}
 
-	var serverList *apiv0.ServerListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&serverList); err != nil {
-		return "", fmt.Errorf("failed to decode server list: %w", err)
-	}
-
-	// Find the server with matching name
-	var foundServers []string
-	for _, server := range serverList.Servers {
-		if server.Name == serverName {
-			foundServers = append(foundServers, fmt.Sprintf("ID:%s IsLatest:%t", server.Meta.Official.ID, server.Meta.Official.IsLatest))
-			if server.Meta.Official.IsLatest {
-				return server.Meta.Official.ID, nil
-			}
-		}
-	}
-
-	if len(foundServers) > 0 {
-		return "", fmt.Errorf("found server %s but none marked as latest: %v", serverName, foundServers)
-	}
-	return "", fmt.Errorf("could not find any server with name %s", serverName)
-}
-
-func verifyPublishedServer(id string, expected *apiv0.ServerJSON) error {
-	log.Printf("  🔍 Verifying server with ID: %s", id)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registryURL+"/v0/servers/"+id, nil)
-	if err != nil {
-		return err
-	}
-	res, err := http.DefaultClient.Do(req)
+	reg, err := registry.New(context.Background(), registry.WithConfig(cfg))
 	if err != nil {
-		return err
-	}
-	content, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read registry response: %w", err)
-	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("registry responded %d: %s", res.StatusCode, string(content))
+		log.Fatalf("failed to start embedded registry: %v", err)
 	}
+	reg.Start()
 
-	var actual *apiv0.ServerJSON
-	if err := json.Unmarshal(content, &actual); err != nil {
-		return fmt.Errorf("failed to unmarshal registry response: %w", err)
-	}
-
-	if err := compareServerJSON(expected, actual); err != nil {
-		return fmt.Errorf(`example "%s": %w`, expected.Name, err)
-	}
-	return nil
-}
-
-type example struct {
-	content []byte
-	line    int
-}
-
-func getExamples(path string) ([]example, error) {
-	b, err := os.ReadFile(path)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
-		log.Fatalf("examples not found; run this test from the repo root")
-		return nil, err
+		log.Fatalf("failed to listen: %v", err)
 	}
-
-	// matches JSON code blocks in markdown,
-	// captures everything between ```json and ```
-	re := regexp.MustCompile("(?s)```json\n(.*?)\n```")
-	matches := re.FindAllSubmatchIndex(b, -1)
-
-	examples := make([]example, len(matches))
-	for i, match := range matches {
-		if len(match) < 4 {
-			// should never happen
-			return nil, fmt.Errorf("invalid match: expected 4 indices but got %d", len(match))
-		}
-		start, end := match[2], match[3]
-		// line numbers start at 1
-		line := 1 + bytes.Count(b[:start], []byte{'\n'})
-		examples[i] = example{
-			content: b[start:end],
-			line:    line,
+	server := &http.Server{Handler: reg} //nolint:gosec // test server on a loopback address
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("embedded registry stopped: %v", err)
 		}
-	}
-
-	return examples, nil
-}
+	}()
+	log.Printf("Started embedded registry on %s with SQLite database %s", listener.Addr(), cfg.SQLitePath)
 
-func compareServerJSON(expected, actual *apiv0.ServerJSON) error {
-	// Compare core fields (ignore Meta as it contains registry-generated data)
-	if expected.Name != actual.Name {
-		return fmt.Errorf("name mismatch: expected %q, got %q", expected.Name, actual.Name)
-	}
-	if expected.Description != actual.Description {
-		return fmt.Errorf("description mismatch: expected %q, got %q", expected.Description, actual.Description)
-	}
-	if expected.Status != actual.Status {
-		return fmt.Errorf("status mismatch: expected %q, got %q", expected.Status, actual.Status)
-	}
-	if expected.Version != actual.Version {
-		return fmt.Errorf("version mismatch: expected %+v, got %+v", expected.Version, actual.Version)
-	}
-
-	// Compare repository
-	if !reflect.DeepEqual(expected.Repository, actual.Repository) {
-		return fmt.Errorf("repository mismatch: expected %+v, got %+v", expected.Repository, actual.Repository)
-	}
-
-	// Compare packages
-	if !reflect.DeepEqual(expected.Packages, actual.Packages) {
-		return fmt.Errorf("packages mismatch: expected %+v, got %+v", expected.Packages, actual.Packages)
-	}
-
-	// Compare remotes
-	if !reflect.DeepEqual(expected.Remotes, actual.Remotes) {
-		return fmt.Errorf("remotes mismatch: expected %+v, got %+v", expected.Remotes, actual.Remotes)
-	}
-
-	// Compare only publisher metadata if present
-	if expected.Meta != nil && expected.Meta.PublisherProvided != nil {
-		if actual.Meta == nil || actual.Meta.PublisherProvided == nil {
-			return fmt.Errorf("expected publisher metadata, but got none")
-		}
-		if !reflect.DeepEqual(expected.Meta.PublisherProvided, actual.Meta.PublisherProvided) {
-			return fmt.Errorf("publisher metadata mismatch: expected %+v, got %+v", expected.Meta.PublisherProvided, actual.Meta.PublisherProvided)
+	return "http://" + listener.Addr().String(), func() {
+		_ = server.Shutdown(context.Background())
+		if err := reg.Shutdown(context.Background()); err != nil {
+			log.Printf("failed to shut down embedded registry: %v", err)
 		}
+		_ = os.RemoveAll(dir)
 	}
-
-	return nil
 }
@@ -9,8 +9,6 @@ cleanup() {
     docker compose down -v
 }
 
-go build -o ./bin/publisher ./cmd/publisher
-
 docker build -t registry .
 
 trap cleanup EXIT
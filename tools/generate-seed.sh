@@ -0,0 +1,7 @@
+#!/bin/bash
+# Generate synthetic seed data for load and pagination testing
+
+set -e
+
+cd "$(dirname "$0")/.."
+exec go run tools/generate-seed/main.go "$@"
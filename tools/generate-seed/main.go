@@ -0,0 +1,481 @@
+// generate-seed writes synthetic server.json records in the seed format the registry
+// imports (MCP_REGISTRY_SEED_FROM), for load and pagination testing without copying
+// production data around.
+//
+// Output is reproducible: the same flags and -seed always produce the same records, down
+// to their IDs and timestamps. Every record passes validators.ValidateServerJSON.
+//
+// Usage:
+//
+//	./tools/generate-seed.sh -count 50000 -seed 42 -o /tmp/seed.json
+//	MCP_REGISTRY_SEED_FROM=/tmp/seed.json make dev-local
+//
+// With -sign-key, a detached signature is written next to the output as <output>.sig, for
+// registries that only import signed seeds (MCP_REGISTRY_SEED_VERIFICATION_KEYS).
+package main
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/importer"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+
+	// remoteType is the package type weight for servers that run as a remote instead of a package
+	remoteType = "remote"
+
+	// maxDescriptionLength is the longest description server.json allows
+	maxDescriptionLength = 100
+)
+
+// githubPrefix is the prefix of namespaces for GitHub accounts
+const githubPrefix = "io.github"
+
+// namespacePrefixes are prefixed to an org's name for its namespaces, in the order the org gets them
+var namespacePrefixes = []string{"com", githubPrefix, "dev", "io", "ai"}
+
+// baseTime is when the first generated version was published
+var baseTime = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	orgWords    = []string{"acme", "globex", "initech", "umbrella", "hooli", "stark", "wayne", "tyrell", "cyberdyne", "soylent", "vandelay", "wonka"}
+	serverWords = []string{"weather", "calendar", "github", "postgres", "slack", "search", "files", "notes", "maps", "billing", "tickets", "metrics", "browser", "docs", "crm", "email"}
+	suffixWords = []string{"mcp", "server", "tools", "bridge", "connector", "gateway"}
+	loremWords  = []string{"lookup", "tools", "for", "querying", "and", "managing", "records", "with", "an", "MCP", "server", "that", "exposes", "search", "updates", "reports", "across", "your", "workspace", "data"}
+)
+
+// options are the distributions the generated records are drawn from
+type options struct {
+	Count             int
+	Seed              uint64
+	Orgs              int
+	NamespacesPerOrg  int
+	MaxVersions       int
+	PackageTypes      map[string]int // relative weights by registry type, or remoteType
+	MinDescription    int
+	MaxDescription    int
+	DeprecatedPercent float64
+	PrereleasePercent float64
+}
+
+func main() {
+	log.SetFlags(0) // Remove timestamp from logs
+
+	opts := options{}
+	var packageTypes, format, output, signKey string
+	flag.IntVar(&opts.Count, "count", 1000, "number of server versions to generate")
+	flag.Uint64Var(&opts.Seed, "seed", 1, "random seed; the same seed and flags produce the same output")
+	flag.IntVar(&opts.Orgs, "orgs", 50, "number of publishing organizations")
+	flag.IntVar(&opts.NamespacesPerOrg, "namespaces-per-org", 2, fmt.Sprintf("namespaces each organization publishes under (1-%d)", len(namespacePrefixes)))
+	flag.IntVar(&opts.MaxVersions, "max-versions", 5, "most versions of a single server; each server gets between 1 and this many")
+	flag.StringVar(&packageTypes, "package-types", "npm:40,pypi:30,oci:15,nuget:5,mcpb:5,remote:5", "relative weights of package types, as type:weight pairs ('remote' is a server without packages)")
+	flag.IntVar(&opts.MinDescription, "min-description", 20, "shortest description, in characters")
+	flag.IntVar(&opts.MaxDescription, "max-description", maxDescriptionLength, "longest description, in characters")
+	flag.Float64Var(&opts.DeprecatedPercent, "deprecated-percent", 5, "percentage of versions marked deprecated")
+	flag.Float64Var(&opts.PrereleasePercent, "prerelease-percent", 10, "percentage of versions with a pre-release version")
+	flag.StringVar(&format, "format", formatJSON, "output format: json (an array) or ndjson (one server per line)")
+	flag.StringVar(&output, "o", "", "file to write to (default stdout)")
+	flag.StringVar(&signKey, "sign-key", "", "hex-encoded Ed25519 seed to sign the output with, writing the signature to <output>.sig (requires -o)")
+	flag.Parse()
+
+	weights, err := parseWeights(packageTypes)
+	if err != nil {
+		log.Fatalf("Error: invalid -package-types: %v", err)
+	}
+	opts.PackageTypes = weights
+
+	if err := run(opts, format, output, signKey); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func run(opts options, format, output, signKey string) error {
+	var key ed25519.PrivateKey
+	if signKey != "" {
+		if output == "" {
+			return errors.New("-sign-key requires -o")
+		}
+		seed, err := hex.DecodeString(signKey)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return fmt.Errorf("-sign-key must be a hex-encoded %d-byte Ed25519 seed", ed25519.SeedSize)
+		}
+		key = ed25519.NewKeyFromSeed(seed)
+	}
+
+	servers, err := generate(opts)
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	buffered := bufio.NewWriter(out)
+	if err := write(buffered, servers, format); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	if output != "" {
+		log.Printf("Wrote %d servers to %s", len(servers), output)
+	}
+	if key != nil {
+		return sign(output, key)
+	}
+	return nil
+}
+
+// sign writes the detached signature of the seed file at path to <path>.sig
+func sign(path string, key ed25519.PrivateKey) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	signature, err := importer.SignSeed(file, key)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(signature, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".sig", append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	log.Printf("Signed %s with key %s", path, signature.KeyID)
+	return nil
+}
+
+// parseWeights parses "type:weight" pairs separated by commas
+func parseWeights(value string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for pair := range strings.SplitSeq(value, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a type:weight pair", pair)
+		}
+		n, err := strconv.Atoi(weight)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%q has an invalid weight", pair)
+		}
+		if _, ok := packageTemplates[name]; !ok && name != remoteType {
+			return nil, fmt.Errorf("unknown package type %q", name)
+		}
+		weights[name] = n
+	}
+	return weights, nil
+}
+
+func (o options) validate() error {
+	switch {
+	case o.Count < 0:
+		return errors.New("-count must not be negative")
+	case o.Orgs < 1:
+		return errors.New("-orgs must be at least 1")
+	case o.NamespacesPerOrg < 1 || o.NamespacesPerOrg > len(namespacePrefixes):
+		return fmt.Errorf("-namespaces-per-org must be between 1 and %d", len(namespacePrefixes))
+	case o.MaxVersions < 1:
+		return errors.New("-max-versions must be at least 1")
+	case o.MinDescription < 1 || o.MinDescription > o.MaxDescription || o.MaxDescription > maxDescriptionLength:
+		return fmt.Errorf("descriptions must be between 1 and %d characters, with -min-description at most -max-description", maxDescriptionLength)
+	case o.DeprecatedPercent < 0 || o.DeprecatedPercent > 100 || o.PrereleasePercent < 0 || o.PrereleasePercent > 100:
+		return errors.New("percentages must be between 0 and 100")
+	}
+	total := 0
+	for _, weight := range o.PackageTypes {
+		total += weight
+	}
+	if total == 0 {
+		return errors.New("-package-types needs at least one type with a positive weight")
+	}
+	return nil
+}
+
+// generator draws records from a single random source, so output depends only on the options
+type generator struct {
+	opts     options
+	rng      *rand.Rand
+	types    []string // package types in a fixed order, for reproducible weighted picks
+	names    map[string]bool
+	released time.Time
+}
+
+// generate returns opts.Count server versions. Each server's versions are consecutive, in
+// increasing version order, and the last one is marked latest.
+func generate(opts options) ([]apiv0.ServerJSON, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		opts:     opts,
+		rng:      rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15)), //nolint:gosec // reproducible test data, not security sensitive
+		names:    make(map[string]bool),
+		released: baseTime,
+	}
+	for name := range opts.PackageTypes {
+		g.types = append(g.types, name)
+	}
+	slices.Sort(g.types)
+
+	servers := make([]apiv0.ServerJSON, 0, opts.Count)
+	for len(servers) < opts.Count {
+		versions := min(1+g.rng.IntN(opts.MaxVersions), opts.Count-len(servers))
+		servers = append(servers, g.server(versions)...)
+	}
+
+	for i := range servers {
+		if err := validators.ValidateServerJSON(&servers[i]); err != nil {
+			return nil, fmt.Errorf("generated an invalid server %s %s: %w", servers[i].Name, servers[i].Version, err)
+		}
+	}
+	return servers, nil
+}
+
+// server returns the versions of one new server
+func (g *generator) server(versions int) []apiv0.ServerJSON {
+	org := g.org()
+	packageType := g.packageType()
+	namespace := g.namespace(org, packageType == remoteType)
+	name := g.name(namespace)
+	slug := name[strings.Index(name, "/")+1:]
+
+	base := apiv0.ServerJSON{
+		Name: name,
+		Repository: model.Repository{
+			URL:    fmt.Sprintf("https://github.com/%s/%s", org, slug),
+			Source: "github",
+			ID:     strconv.Itoa(100000000 + g.rng.IntN(900000000)),
+		},
+	}
+
+	result := make([]apiv0.ServerJSON, 0, versions)
+	major, minor, patch := g.rng.IntN(2), g.rng.IntN(5), 0
+	for i := range versions {
+		server := base
+		server.Description = g.description()
+		server.Status = model.StatusActive
+		if g.percent(g.opts.DeprecatedPercent) {
+			server.Status = model.StatusDeprecated
+		}
+
+		// Each version is above the last; a pre-release sorts below its own release only
+		patch++
+		if g.rng.IntN(4) == 0 {
+			minor, patch = minor+1, 0
+		}
+		server.Version = fmt.Sprintf("%d.%d.%d", major, minor, patch)
+		if g.percent(g.opts.PrereleasePercent) {
+			server.Version += fmt.Sprintf("-%s.%d", []string{"alpha", "beta", "rc"}[g.rng.IntN(3)], 1+g.rng.IntN(3))
+		}
+
+		if packageType == remoteType {
+			server.Remotes = []model.Transport{g.remote(namespace, slug)}
+		} else {
+			server.Packages = []model.Package{g.pkg(packageType, org, slug, server.Version)}
+		}
+
+		g.released = g.released.Add(time.Duration(1+g.rng.IntN(3600)) * time.Second)
+		server.Meta = &apiv0.ServerMeta{Official: &apiv0.RegistryExtensions{
+			ID:          g.uuid(),
+			PublishedAt: g.released,
+			UpdatedAt:   g.released,
+			IsLatest:    i == versions-1,
+		}}
+		result = append(result, server)
+	}
+	return result
+}
+
+// org returns the name of a random organization
+func (g *generator) org() string {
+	n := g.rng.IntN(g.opts.Orgs)
+	return fmt.Sprintf("%s-%d", orgWords[n%len(orgWords)], n)
+}
+
+// namespace returns one of org's namespaces. Servers with remotes are published under one of
+// the org's domains, since a remote must be hosted under its namespace's domain.
+func (g *generator) namespace(org string, remote bool) string {
+	namespaces := make([]string, 0, g.opts.NamespacesPerOrg)
+	for _, prefix := range namespacePrefixes[:g.opts.NamespacesPerOrg] {
+		if !remote || prefix != githubPrefix {
+			namespaces = append(namespaces, prefix+"."+org)
+		}
+	}
+	return namespaces[g.rng.IntN(len(namespaces))]
+}
+
+// name returns a server name in namespace that has not been generated before
+func (g *generator) name(namespace string) string {
+	slug := serverWords[g.rng.IntN(len(serverWords))] + "-" + suffixWords[g.rng.IntN(len(suffixWords))]
+	name := namespace + "/" + slug
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s/%s-%d", namespace, slug, i)
+	}
+	g.names[name] = true
+	return name
+}
+
+// description returns lorem text with a length drawn between the configured bounds
+func (g *generator) description() string {
+	length := g.opts.MinDescription + g.rng.IntN(g.opts.MaxDescription-g.opts.MinDescription+1)
+	var b strings.Builder
+	for b.Len() < length {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(loremWords[g.rng.IntN(len(loremWords))])
+	}
+	return strings.TrimSpace(b.String()[:length])
+}
+
+// packageType picks a package type by weight
+func (g *generator) packageType() string {
+	total := 0
+	for _, name := range g.types {
+		total += g.opts.PackageTypes[name]
+	}
+	n := g.rng.IntN(total)
+	for _, name := range g.types {
+		if n < g.opts.PackageTypes[name] {
+			return name
+		}
+		n -= g.opts.PackageTypes[name]
+	}
+	return g.types[len(g.types)-1]
+}
+
+// packageTemplate describes how a package of one registry type is identified and run
+type packageTemplate struct {
+	baseURL     string
+	runtimeHint string
+	identifier  func(org, slug, version string) string
+}
+
+var packageTemplates = map[string]packageTemplate{
+	"npm":  {"https://registry.npmjs.org", "npx", func(org, slug, _ string) string { return "@" + org + "/" + slug }},
+	"pypi": {"https://pypi.org", "uvx", func(org, slug, _ string) string { return org + "-" + slug }},
+	"oci":  {"https://docker.io", "docker", func(org, slug, _ string) string { return org + "/" + slug }},
+	"nuget": {"https://api.nuget.org", "dnx", func(org, slug, _ string) string {
+		return strings.ToUpper(org[:1]) + org[1:] + "." + strings.ReplaceAll(slug, "-", ".")
+	}},
+	"mcpb": {"", "", func(org, slug, version string) string {
+		return fmt.Sprintf("https://github.com/%s/%s/releases/download/v%s/%s.mcpb", org, slug, version, slug)
+	}},
+}
+
+// pkg returns a package of the given registry type, with an environment variable now and then
+func (g *generator) pkg(registryType, org, slug, version string) model.Package {
+	template := packageTemplates[registryType]
+	pkg := model.Package{
+		RegistryType:    registryType,
+		RegistryBaseURL: template.baseURL,
+		Identifier:      template.identifier(org, slug, version),
+		Version:         version,
+		RunTimeHint:     template.runtimeHint,
+		Transport:       model.Transport{Type: "stdio"},
+	}
+	if registryType == "mcpb" {
+		pkg.FileSHA256 = g.hex(32)
+	}
+	if g.rng.IntN(2) == 0 {
+		pkg.EnvironmentVariables = []model.KeyValueInput{{
+			Name: strings.ToUpper(strings.ReplaceAll(slug, "-", "_")) + "_API_KEY",
+			InputWithVariables: model.InputWithVariables{Input: model.Input{
+				Description: "API key for " + slug,
+				IsRequired:  true,
+				IsSecret:    true,
+			}},
+		}}
+	}
+	return pkg
+}
+
+// remote returns a remote hosted under namespace's domain
+func (g *generator) remote(namespace, slug string) model.Transport {
+	transport, path := "streamable-http", "/mcp"
+	if g.rng.IntN(2) == 0 {
+		transport, path = "sse", "/sse"
+	}
+	parts := strings.Split(namespace, ".")
+	slices.Reverse(parts)
+	return model.Transport{Type: transport, URL: fmt.Sprintf("https://%s.%s%s", slug, strings.Join(parts, "."), path)}
+}
+
+// percent reports true with the given probability, in percent
+func (g *generator) percent(p float64) bool {
+	return g.rng.Float64()*100 < p
+}
+
+// uuid returns a random version 4 UUID drawn from the generator's source
+func (g *generator) uuid() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.rng.UintN(256))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// hex returns n random bytes in hex
+func (g *generator) hex(n int) string {
+	var b strings.Builder
+	for range n {
+		fmt.Fprintf(&b, "%02x", g.rng.UintN(256))
+	}
+	return b.String()
+}
+
+// write encodes servers as a JSON array or as NDJSON, the two seed formats the importer reads
+func write(w io.Writer, servers []apiv0.ServerJSON, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if servers == nil {
+			servers = []apiv0.ServerJSON{}
+		}
+		return enc.Encode(servers)
+	case formatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, server := range servers {
+			if err := enc.Encode(server); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (use %s or %s)", format, formatJSON, formatNDJSON)
+	}
+}
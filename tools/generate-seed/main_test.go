@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/importer"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func testOptions() options {
+	return options{
+		Count:             200,
+		Seed:              7,
+		Orgs:              10,
+		NamespacesPerOrg:  5,
+		MaxVersions:       4,
+		PackageTypes:      map[string]int{"npm": 1, "pypi": 1, "oci": 1, "nuget": 1, "mcpb": 1, remoteType: 1},
+		MinDescription:    10,
+		MaxDescription:    100,
+		DeprecatedPercent: 10,
+		PrereleasePercent: 20,
+	}
+}
+
+func TestGenerate_Valid(t *testing.T) {
+	servers, err := generate(testOptions())
+	require.NoError(t, err)
+	require.Len(t, servers, 200)
+
+	schemaPath := filepath.Join("..", "..", "docs", "reference", "server-json", "server.schema.json")
+	schemaData, err := os.ReadFile(schemaPath)
+	require.NoError(t, err)
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource("server.schema.json", bytes.NewReader(schemaData)))
+	schema, err := compiler.Compile("server.schema.json")
+	require.NoError(t, err)
+
+	types := make(map[string]bool)
+	latest := make(map[string]int)
+	for _, server := range servers {
+		require.NoError(t, validators.ValidateServerJSON(&server), server.Name)
+
+		data, err := json.Marshal(server)
+		require.NoError(t, err)
+		var document any
+		require.NoError(t, json.Unmarshal(data, &document))
+		require.NoError(t, schema.Validate(document), server.Name)
+
+		assert.LessOrEqual(t, len(server.Description), 100)
+		for _, pkg := range server.Packages {
+			types[pkg.RegistryType] = true
+		}
+		if len(server.Remotes) > 0 {
+			types[remoteType] = true
+		}
+		if server.Meta.Official.IsLatest {
+			latest[server.Name]++
+		}
+	}
+
+	assert.Len(t, types, 6, "every package type is generated")
+	for name, count := range latest {
+		assert.Equal(t, 1, count, "%s has one latest version", name)
+	}
+}
+
+func TestGenerate_Reproducible(t *testing.T) {
+	first, err := generate(testOptions())
+	require.NoError(t, err)
+	second, err := generate(testOptions())
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	other := testOptions()
+	other.Seed = 8
+	third, err := generate(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, third)
+}
+
+func TestGenerate_Percentages(t *testing.T) {
+	opts := testOptions()
+	opts.DeprecatedPercent = 100
+	opts.PrereleasePercent = 100
+	servers, err := generate(opts)
+	require.NoError(t, err)
+	for _, server := range servers {
+		assert.Equal(t, model.StatusDeprecated, server.Status)
+		assert.Contains(t, server.Version, "-")
+	}
+
+	opts.DeprecatedPercent = 0
+	opts.PrereleasePercent = 0
+	servers, err = generate(opts)
+	require.NoError(t, err)
+	for _, server := range servers {
+		assert.Equal(t, model.StatusActive, server.Status)
+		assert.NotContains(t, server.Version, "-")
+	}
+}
+
+func TestGenerate_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(o *options)
+	}{
+		{"no orgs", func(o *options) { o.Orgs = 0 }},
+		{"too many namespaces per org", func(o *options) { o.NamespacesPerOrg = 6 }},
+		{"description too long", func(o *options) { o.MaxDescription = 101 }},
+		{"descriptions out of order", func(o *options) { o.MinDescription = 50; o.MaxDescription = 40 }},
+		{"percentage above 100", func(o *options) { o.DeprecatedPercent = 150 }},
+		{"no package types", func(o *options) { o.PackageTypes = map[string]int{"npm": 0} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOptions()
+			tt.mutate(&opts)
+			_, err := generate(opts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	weights, err := parseWeights("npm:3, remote:1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"npm": 3, remoteType: 1}, weights)
+
+	for _, value := range []string{"npm", "npm:x", "npm:-1", "cargo:1"} {
+		_, err := parseWeights(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestWrite_Importable(t *testing.T) {
+	opts := testOptions()
+	opts.Count = 50
+	servers, err := generate(opts)
+	require.NoError(t, err)
+
+	for _, format := range []string{formatJSON, formatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, write(&out, servers, format))
+			if format == formatNDJSON {
+				assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 50)
+			}
+
+			path := filepath.Join(t.TempDir(), "seed.json")
+			require.NoError(t, os.WriteFile(path, out.Bytes(), 0o600))
+
+			db := database.NewMemoryDB()
+			require.NoError(t, importer.NewService(db).ImportFromPath(context.Background(), path))
+
+			ctx := context.Background()
+			for _, server := range servers {
+				imported, err := db.GetByID(ctx, server.Meta.Official.ID)
+				require.NoError(t, err, server.Name)
+				assert.Equal(t, server.Name, imported.Name)
+			}
+		})
+	}
+
+	_, err = generate(options{})
+	require.Error(t, err)
+	require.Error(t, write(&bytes.Buffer{}, []apiv0.ServerJSON{}, "yaml"))
+}
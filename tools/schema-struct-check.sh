@@ -0,0 +1,8 @@
+#!/bin/bash
+# Check that server.schema.json and the Go model structs stay in sync
+# Intentional divergences are listed in tools/schema-struct-check/allowlist.txt
+
+set -e
+
+cd "$(dirname "$0")/.."
+exec go run ./tools/schema-struct-check "$@"
@@ -0,0 +1,371 @@
+// schema-struct-check verifies that docs/reference/server-json/server.schema.json
+// and the Go types in pkg/model and pkg/api/v0 describe the same shape.
+//
+// It reports properties present in one but not the other, type mismatches, and
+// properties the schema marks as required that the Go types do not validate.
+// Intentional divergences are listed in tools/schema-struct-check/allowlist.txt.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// Finding kinds reported by the check.
+const (
+	kindMissingInGo         = "missing-in-go"
+	kindMissingInSchema     = "missing-in-schema"
+	kindTypeMismatch        = "type-mismatch"
+	kindRequiredUnvalidated = "required-unvalidated"
+)
+
+// binding ties one or more schema definitions to the Go type that implements them.
+// When several definitions share a Go type (e.g. the transport variants), the Go
+// type must cover the union of their properties.
+type binding struct {
+	defs   []string
+	goType reflect.Type
+}
+
+// bindings lists the schema definitions checked against the Go model.
+var bindings = []binding{
+	{defs: []string{"ServerDetail"}, goType: reflect.TypeOf(apiv0.ServerJSON{})},
+	{defs: []string{"Repository"}, goType: reflect.TypeOf(model.Repository{})},
+	{defs: []string{"Icon"}, goType: reflect.TypeOf(model.Icon{})},
+	{defs: []string{"Package"}, goType: reflect.TypeOf(model.Package{})},
+	{defs: []string{"Input"}, goType: reflect.TypeOf(model.Input{})},
+	{defs: []string{"InputWithVariables"}, goType: reflect.TypeOf(model.InputWithVariables{})},
+	{defs: []string{"KeyValueInput"}, goType: reflect.TypeOf(model.KeyValueInput{})},
+	{defs: []string{"PositionalArgument", "NamedArgument"}, goType: reflect.TypeOf(model.Argument{})},
+	{defs: []string{"StdioTransport", "StreamableHttpTransport", "SseTransport"}, goType: reflect.TypeOf(model.Transport{})},
+	{defs: []string{"Authentication"}, goType: reflect.TypeOf(model.Authentication{})},
+}
+
+func main() {
+	log.SetFlags(0) // Remove timestamp from logs
+
+	if err := runCheck(); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func runCheck() error {
+	schemaPath := filepath.Join("docs", "reference", "server-json", "server.schema.json")
+	allowlistPath := filepath.Join("tools", "schema-struct-check", "allowlist.txt")
+
+	schema, err := loadSchema(schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to load schema: %w", err)
+	}
+
+	allowed, err := loadAllowlist(allowlistPath)
+	if err != nil {
+		return fmt.Errorf("failed to load allowlist: %w", err)
+	}
+
+	findings, err := check(schema, bindings)
+	if err != nil {
+		return err
+	}
+
+	unexpected, unused := applyAllowlist(findings, allowed)
+	for _, key := range unused {
+		log.Printf("⚠️  Allowlist entry no longer needed: %s", key)
+	}
+	for _, f := range unexpected {
+		log.Printf("❌ %s", f)
+	}
+
+	if len(unexpected) > 0 {
+		return fmt.Errorf("%d divergence(s) between %s and the Go model - fix them or add them to %s",
+			len(unexpected), schemaPath, allowlistPath)
+	}
+
+	log.Printf("✅ %s matches the Go model (%d allowlisted divergence(s))", schemaPath, len(findings))
+	return nil
+}
+
+// schemaNode is the subset of JSON Schema the check understands.
+type schemaNode struct {
+	Ref        string                 `json:"$ref"`
+	Type       string                 `json:"type"`
+	Properties map[string]*schemaNode `json:"properties"`
+	Required   []string               `json:"required"`
+	Items      *schemaNode            `json:"items"`
+	AllOf      []*schemaNode          `json:"allOf"`
+	AnyOf      []*schemaNode          `json:"anyOf"`
+	Defs       map[string]*schemaNode `json:"$defs"`
+}
+
+func loadSchema(path string) (*schemaNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema schemaNode
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &schema, nil
+}
+
+// finding is a single divergence between a schema definition and a Go type.
+type finding struct {
+	def      string
+	property string
+	kind     string
+	detail   string
+}
+
+// key identifies the finding in the allowlist.
+func (f finding) key() string {
+	return fmt.Sprintf("%s.%s %s", f.def, f.property, f.kind)
+}
+
+func (f finding) String() string {
+	if f.detail == "" {
+		return f.key()
+	}
+	return fmt.Sprintf("%s (%s)", f.key(), f.detail)
+}
+
+// check compares every binding against the schema and returns the findings sorted by key.
+func check(schema *schemaNode, bindings []binding) ([]finding, error) {
+	var findings []finding
+
+	for _, b := range bindings {
+		props := map[string]*schemaNode{}
+		required := map[string]bool{}
+		for _, def := range b.defs {
+			node, ok := schema.Defs[def]
+			if !ok {
+				return nil, fmt.Errorf("schema has no definition %q", def)
+			}
+			if err := collectProperties(schema, node, props, required); err != nil {
+				return nil, fmt.Errorf("definition %q: %w", def, err)
+			}
+		}
+
+		fields := goFields(b.goType)
+		name := strings.Join(b.defs, "|")
+
+		for prop, node := range props {
+			field, ok := fields[prop]
+			if !ok {
+				findings = append(findings, finding{def: name, property: prop, kind: kindMissingInGo})
+				continue
+			}
+			if want := schemaType(node); want != "" {
+				if got := jsonKind(field.Type); got != want {
+					findings = append(findings, finding{
+						def: name, property: prop, kind: kindTypeMismatch,
+						detail: fmt.Sprintf("schema %s, Go %s is %s", want, field.Type, got),
+					})
+				}
+			}
+			if required[prop] && !validatesRequired(field) {
+				findings = append(findings, finding{def: name, property: prop, kind: kindRequiredUnvalidated})
+			}
+		}
+
+		for prop := range fields {
+			if _, ok := props[prop]; !ok {
+				findings = append(findings, finding{def: name, property: prop, kind: kindMissingInSchema})
+			}
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].key() < findings[j].key() })
+	return findings, nil
+}
+
+// collectProperties merges the properties of node, following $ref and allOf, into props.
+// Properties are only marked required when every merged definition requires them.
+func collectProperties(root, node *schemaNode, props map[string]*schemaNode, required map[string]bool) error {
+	own := map[string]*schemaNode{}
+	ownRequired := map[string]bool{}
+	if err := flatten(root, node, own, ownRequired); err != nil {
+		return err
+	}
+
+	firstDef := len(props) == 0
+	for prop, n := range own {
+		if _, seen := props[prop]; !seen {
+			props[prop] = n
+			required[prop] = firstDef && ownRequired[prop]
+		}
+	}
+	for prop := range required {
+		required[prop] = required[prop] && ownRequired[prop]
+	}
+	return nil
+}
+
+func flatten(root, node *schemaNode, props map[string]*schemaNode, required map[string]bool) error {
+	if node.Ref != "" {
+		target, err := resolve(root, node.Ref)
+		if err != nil {
+			return err
+		}
+		return flatten(root, target, props, required)
+	}
+	for _, sub := range node.AllOf {
+		if err := flatten(root, sub, props, required); err != nil {
+			return err
+		}
+	}
+	for prop, n := range node.Properties {
+		props[prop] = n
+	}
+	for _, prop := range node.Required {
+		required[prop] = true
+	}
+	return nil
+}
+
+func resolve(root *schemaNode, ref string) (*schemaNode, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	node, ok := root.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %q", ref)
+	}
+	return node, nil
+}
+
+// schemaType returns the JSON type a property expects, or "" when the schema does not pin one.
+func schemaType(node *schemaNode) string {
+	if node.Type != "" {
+		return node.Type
+	}
+	if node.Ref != "" || len(node.AllOf) > 0 {
+		return "object"
+	}
+	return ""
+}
+
+// jsonKind maps a Go type to the JSON type encoding/json produces for it.
+func jsonKind(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// goField is a JSON-visible field of a Go struct.
+type goField struct {
+	reflect.StructField
+	omitempty bool
+}
+
+// goFields returns the JSON-visible fields of t keyed by JSON name, flattening
+// embedded structs the same way encoding/json does.
+func goFields(t reflect.Type) map[string]goField {
+	fields := map[string]goField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for k, v := range goFields(f.Type) {
+				if _, shadowed := fields[k]; !shadowed {
+					fields[k] = v
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = goField{StructField: f, omitempty: strings.Contains(opts, "omitempty")}
+	}
+	return fields
+}
+
+// validatesRequired reports whether the Go field rejects a missing value: either
+// through an explicit huma tag, or by not being omitempty for non-string kinds.
+func validatesRequired(f goField) bool {
+	if f.Tag.Get("required") == "true" {
+		return true
+	}
+	if minLength := f.Tag.Get("minLength"); minLength != "" && minLength != "0" {
+		return true
+	}
+	return !f.omitempty && jsonKind(f.Type) != "string"
+}
+
+// loadAllowlist reads allowlist entries, one finding key per line. Blank lines and
+// text after '#' are ignored.
+func loadAllowlist(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	allowed := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			allowed[line] = true
+		}
+	}
+	return allowed, scanner.Err()
+}
+
+// applyAllowlist splits findings into those not covered by the allowlist, and
+// returns allowlist entries that no longer match any finding.
+func applyAllowlist(findings []finding, allowed map[string]bool) (unexpected []finding, unused []string) {
+	matched := map[string]bool{}
+	for _, f := range findings {
+		if allowed[f.key()] {
+			matched[f.key()] = true
+			continue
+		}
+		unexpected = append(unexpected, f)
+	}
+	for key := range allowed {
+		if !matched[key] {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	return unexpected, unused
+}
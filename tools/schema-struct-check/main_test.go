@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type base struct {
+	ID      string `json:"id" minLength:"1"`
+	Enabled bool   `json:"enabled,omitempty"`
+}
+
+type part struct {
+	Size int `json:"size,omitempty"`
+}
+
+type widget struct {
+	base `json:",inline"`
+	Name string   `json:"name" required:"true"`
+	Tags []string `json:"tags,omitempty"`
+	Part *part    `json:"part,omitempty"`
+}
+
+type wheel struct {
+	Kind   string  `json:"kind" minLength:"1"`
+	Radius float64 `json:"radius,omitempty"`
+	Side   float64 `json:"side,omitempty"`
+}
+
+func loadFixture(t *testing.T, mutate func(defs map[string]any)) *schemaNode {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "fixture.schema.json"))
+	require.NoError(t, err)
+
+	if mutate != nil {
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal(data, &raw))
+		mutate(raw["$defs"].(map[string]any))
+		data, err = json.Marshal(raw)
+		require.NoError(t, err)
+	}
+
+	var schema schemaNode
+	require.NoError(t, json.Unmarshal(data, &schema))
+	return &schema
+}
+
+func properties(defs map[string]any, def string) map[string]any {
+	node := defs[def].(map[string]any)
+	if allOf, ok := node["allOf"].([]any); ok {
+		node = allOf[len(allOf)-1].(map[string]any)
+	}
+	return node["properties"].(map[string]any)
+}
+
+func keys(findings []finding) []string {
+	out := make([]string, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, f.key())
+	}
+	return out
+}
+
+var fixtureBindings = []binding{
+	{defs: []string{"Widget"}, goType: reflect.TypeOf(widget{})},
+	{defs: []string{"Part"}, goType: reflect.TypeOf(part{})},
+	{defs: []string{"RoundWheel", "SquareWheel"}, goType: reflect.TypeOf(wheel{})},
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(defs map[string]any)
+		goType   reflect.Type
+		expected []string
+	}{
+		{
+			name:     "in sync",
+			expected: []string{},
+		},
+		{
+			name: "new schema property without struct field",
+			mutate: func(defs map[string]any) {
+				properties(defs, "Widget")["color"] = map[string]any{"type": "string"}
+			},
+			expected: []string{"Widget.color missing-in-go"},
+		},
+		{
+			name: "new property on referenced base definition",
+			mutate: func(defs map[string]any) {
+				properties(defs, "Base")["owner"] = map[string]any{"type": "string"}
+			},
+			expected: []string{"Widget.owner missing-in-go"},
+		},
+		{
+			name: "struct field missing from schema",
+			mutate: func(defs map[string]any) {
+				delete(properties(defs, "Widget"), "tags")
+			},
+			expected: []string{"Widget.tags missing-in-schema"},
+		},
+		{
+			name: "type mismatch",
+			mutate: func(defs map[string]any) {
+				properties(defs, "Part")["size"] = map[string]any{"type": "string"}
+			},
+			expected: []string{"Part.size type-mismatch"},
+		},
+		{
+			name: "required property without validation",
+			mutate: func(defs map[string]any) {
+				defs["Part"].(map[string]any)["required"] = []any{"size"}
+			},
+			expected: []string{"Part.size required-unvalidated"},
+		},
+		{
+			name: "required only in one variant is not required",
+			mutate: func(defs map[string]any) {
+				defs["SquareWheel"].(map[string]any)["required"] = []any{"kind", "side"}
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := loadFixture(t, tt.mutate)
+
+			findings, err := check(schema, fixtureBindings)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, keys(findings))
+		})
+	}
+}
+
+func TestCheck_UnknownDefinition(t *testing.T) {
+	schema := loadFixture(t, nil)
+
+	_, err := check(schema, []binding{{defs: []string{"Gadget"}, goType: reflect.TypeOf(part{})}})
+	assert.ErrorContains(t, err, `schema has no definition "Gadget"`)
+}
+
+func TestApplyAllowlist(t *testing.T) {
+	findings := []finding{
+		{def: "Widget", property: "color", kind: kindMissingInGo},
+		{def: "Part", property: "size", kind: kindTypeMismatch},
+	}
+
+	unexpected, unused := applyAllowlist(findings, map[string]bool{
+		"Widget.color missing-in-go":     true,
+		"Widget.shape missing-in-schema": true,
+	})
+
+	assert.Equal(t, []string{"Part.size type-mismatch"}, keys(unexpected))
+	assert.Equal(t, []string{"Widget.shape missing-in-schema"}, unused)
+}
+
+func TestLoadAllowlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowlist.txt")
+	content := "# comment\n\nWidget.color   missing-in-go  # reason\n  Part.size type-mismatch\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	allowed, err := loadAllowlist(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{
+		"Widget.color missing-in-go": true,
+		"Part.size type-mismatch":    true,
+	}, allowed)
+}
+
+// TestRepositorySchemaMatchesModel runs the check against the real schema so that
+// drift is caught by go test as well as by make validate.
+func TestRepositorySchemaMatchesModel(t *testing.T) {
+	schema, err := loadSchema(filepath.Join("..", "..", "docs", "reference", "server-json", "server.schema.json"))
+	require.NoError(t, err)
+
+	allowed, err := loadAllowlist("allowlist.txt")
+	require.NoError(t, err)
+
+	findings, err := check(schema, bindings)
+	require.NoError(t, err)
+
+	unexpected, unused := applyAllowlist(findings, allowed)
+	assert.Empty(t, unexpected, "schema and Go model diverge")
+	assert.Empty(t, unused, "stale allowlist entries")
+}
@@ -23,7 +23,7 @@ const (
 	// expectedExampleCount is the number of JSON examples we expect to find in generic-server-json.md
 	// IMPORTANT: Only change this count if you have intentionally added or removed examples. This
 	// check prevents accidental formatting changes from causing examples to be skipped during validation.
-	expectedExampleCount = 12
+	expectedExampleCount = 13
 )
 
 func main() {
@@ -5,4 +5,4 @@
 set -e
 
 cd "$(dirname "$0")/.."
-exec go run tools/validate-schemas/main.go "$@"
+exec go run ./tools/validate-schemas "$@"
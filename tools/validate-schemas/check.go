@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Keywords whose value is a schema, a list of schemas, or a map from names to schemas
+var (
+	schemaKeywords = []string{
+		"additionalItems", "additionalProperties", "contains", "contentSchema", "else", "if",
+		"items", "not", "propertyNames", "then", "unevaluatedItems", "unevaluatedProperties",
+	}
+	schemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"}
+	schemaMapKeywords  = []string{"$defs", "definitions", "dependentSchemas", "dependencies", "patternProperties", "properties"}
+	definitionKeywords = []string{"$defs", "definitions"}
+)
+
+// problem is something wrong at a JSON pointer within a schema document
+type problem struct {
+	Pointer string
+	Message string
+}
+
+func (p problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Pointer, p.Message)
+}
+
+// subschema is a schema object found in a document, with its JSON pointer
+type subschema struct {
+	pointer string
+	schema  map[string]any
+}
+
+// checkSchemaContents reports problems that compiling a schema does not catch:
+// examples and defaults that violate their own subschema, $refs that resolve to
+// nothing, and definitions nothing refers to
+func checkSchemaContents(path string, data []byte) ([]problem, error) {
+	// Numbers stay json.Number, which is what the validator expects
+	var doc any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var problems []problem
+	problems = append(problems, checkRefs(doc)...)
+	problems = append(problems, checkReachability(doc)...)
+
+	// Only validate examples once every reference resolves, since compiling needs them
+	if len(problems) == 0 {
+		exampleProblems, err := checkExamples(path, data, doc)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, exampleProblems...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Pointer < problems[j].Pointer })
+	return problems, nil
+}
+
+// subschemas returns every schema object in the document, in document order.
+// Definitions are included only when withDefinitions is set.
+func subschemas(node any, pointer string, withDefinitions bool) []subschema {
+	schema, ok := node.(map[string]any)
+	if !ok {
+		return nil // boolean schemas have nothing to check
+	}
+
+	found := []subschema{{pointer: pointer, schema: schema}}
+	for _, keyword := range schemaKeywords {
+		if child, ok := schema[keyword].(map[string]any); ok {
+			found = append(found, subschemas(child, pointer+"/"+escapePointer(keyword), withDefinitions)...)
+		}
+	}
+	for _, keyword := range schemaListKeywords {
+		if children, ok := schema[keyword].([]any); ok {
+			for i, child := range children {
+				found = append(found, subschemas(child, pointer+"/"+keyword+"/"+strconv.Itoa(i), withDefinitions)...)
+			}
+		}
+	}
+	for _, keyword := range schemaMapKeywords {
+		children, ok := schema[keyword].(map[string]any)
+		if !ok || (!withDefinitions && isDefinitionKeyword(keyword)) {
+			continue
+		}
+		for _, name := range sortedKeys(children) {
+			found = append(found, subschemas(children[name], pointer+"/"+escapePointer(keyword)+"/"+escapePointer(name), withDefinitions)...)
+		}
+	}
+	return found
+}
+
+// checkRefs reports $refs within the document that do not resolve to a schema
+func checkRefs(doc any) []problem {
+	var problems []problem
+	for _, sub := range subschemas(doc, "", true) {
+		ref, ok := sub.schema["$ref"].(string)
+		if !ok {
+			continue
+		}
+		target, local := localRef(doc, ref)
+		if !local {
+			continue // references to other documents are resolved by the compiler
+		}
+		if _, ok := resolvePointer(doc, target); !ok {
+			problems = append(problems, problem{Pointer: sub.pointer + "/$ref", Message: fmt.Sprintf("%q does not resolve to anything in this document", ref)})
+		}
+	}
+	return problems
+}
+
+// checkReachability reports definitions that cannot be reached by following $refs from the root schema
+func checkReachability(doc any) []problem {
+	reached := make(map[string]bool)
+	pending := []string{""}
+	for len(pending) > 0 {
+		pointer := pending[0]
+		pending = pending[1:]
+		if reached[pointer] {
+			continue
+		}
+		reached[pointer] = true
+
+		node, ok := resolvePointer(doc, pointer)
+		if !ok {
+			continue
+		}
+		for _, sub := range subschemas(node, pointer, false) {
+			if ref, ok := sub.schema["$ref"].(string); ok {
+				if target, local := localRef(doc, ref); local {
+					pending = append(pending, target)
+				}
+			}
+		}
+	}
+
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var problems []problem
+	for _, keyword := range definitionKeywords {
+		definitions, _ := root[keyword].(map[string]any)
+		for _, name := range sortedKeys(definitions) {
+			pointer := "/" + escapePointer(keyword) + "/" + escapePointer(name)
+			if !reachedWithin(reached, pointer) {
+				problems = append(problems, problem{Pointer: pointer, Message: "definition is never referenced from the root schema"})
+			}
+		}
+	}
+	return problems
+}
+
+// reachedWithin reports whether pointer, or anything inside it, was reached
+func reachedWithin(reached map[string]bool, pointer string) bool {
+	for target := range reached {
+		if target == pointer || strings.HasPrefix(target, pointer+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkExamples validates each example and default value against the subschema it annotates
+func checkExamples(path string, data []byte, doc any) ([]problem, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	resourceURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat = true
+	if err := compiler.AddResource(resourceURL, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to load schema: %w", err)
+	}
+
+	var problems []problem
+	for _, sub := range subschemas(doc, "", true) {
+		var values []any
+		var pointers []string
+		if examples, ok := sub.schema["examples"].([]any); ok {
+			for i, example := range examples {
+				values = append(values, example)
+				pointers = append(pointers, sub.pointer+"/examples/"+strconv.Itoa(i))
+			}
+		}
+		// "example" is an OpenAPI annotation rather than JSON Schema, but readers treat it the same way
+		for _, keyword := range []string{"example", "default"} {
+			if value, ok := sub.schema[keyword]; ok {
+				values = append(values, value)
+				pointers = append(pointers, sub.pointer+"/"+keyword)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		schema, err := compiler.Compile(resourceURL + (&url.URL{Fragment: sub.pointer}).String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile %s: %w", sub.pointer, err)
+		}
+		for i, value := range values {
+			if err := schema.Validate(value); err != nil {
+				problems = append(problems, problem{Pointer: pointers[i], Message: validationMessage(err)})
+			}
+		}
+	}
+	return problems, nil
+}
+
+// validationMessage flattens a validation error to its innermost causes
+func validationMessage(err error) string {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err.Error()
+	}
+	var messages []string
+	var collect func(e *jsonschema.ValidationError)
+	collect = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			if e.InstanceLocation == "" {
+				messages = append(messages, e.Message)
+			} else {
+				messages = append(messages, fmt.Sprintf("at %s: %s", e.InstanceLocation, e.Message))
+			}
+			return
+		}
+		for _, cause := range e.Causes {
+			collect(cause)
+		}
+	}
+	collect(validationErr)
+	return "invalid against its schema: " + strings.Join(messages, "; ")
+}
+
+// localRef returns the JSON pointer a $ref names within this document, if it names one
+func localRef(doc any, ref string) (string, bool) {
+	base, fragment, _ := strings.Cut(ref, "#")
+	if base != "" {
+		root, _ := doc.(map[string]any)
+		if id, _ := root["$id"].(string); base != id {
+			return "", false
+		}
+	}
+	pointer, err := url.PathUnescape(fragment)
+	if err != nil {
+		return fragment, true
+	}
+	return pointer, true
+}
+
+// resolvePointer returns the value a JSON pointer names within doc
+func resolvePointer(doc any, pointer string) (any, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false // anchors are not used by our schemas
+	}
+
+	node := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch current := node.(type) {
+		case map[string]any:
+			child, ok := current[token]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(current) {
+				return nil, false
+			}
+			node = current[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func isDefinitionKeyword(keyword string) bool {
+	for _, definitions := range definitionKeywords {
+		if keyword == definitions {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
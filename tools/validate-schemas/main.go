@@ -1,5 +1,7 @@
 // validate-schemas validates that server.schema.json and registry-schema.json
-// are valid JSON Schema documents.
+// are valid JSON Schema documents, that every example and default value in them
+// is valid against the subschema it annotates, that every $ref resolves, and
+// that every definition is used.
 //
 // For more information, see docs/server-json/README.md
 package main
@@ -7,6 +9,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -89,5 +92,18 @@ func validateSchema(path string) error {
 		return fmt.Errorf("invalid schema: %w", err)
 	}
 
+	problems, err := checkSchemaContents(path, data)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%d problem(s) in schema contents:", len(problems))
+		for _, p := range problems {
+			b.WriteString("\n    - " + p.String())
+		}
+		return errors.New(b.String())
+	}
+
 	return nil
 }
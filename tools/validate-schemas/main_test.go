@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checkFixture(t *testing.T, name string) []problem {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	problems, err := checkSchemaContents(path, data)
+	require.NoError(t, err)
+	return problems
+}
+
+func pointers(problems []problem) []string {
+	var result []string
+	for _, p := range problems {
+		result = append(result, p.Pointer)
+	}
+	return result
+}
+
+func TestCheckSchemaContents_Valid(t *testing.T) {
+	assert.Empty(t, checkFixture(t, "valid.schema.json"))
+}
+
+func TestCheckSchemaContents_BadExamples(t *testing.T) {
+	problems := checkFixture(t, "bad-examples.schema.json")
+	assert.Equal(t, []string{
+		"/examples/0",
+		"/properties/name/examples/1",
+		"/properties/size/default",
+		"/properties/status/default",
+		"/properties/url/example",
+	}, pointers(problems))
+
+	for _, p := range problems {
+		assert.Contains(t, p.Message, "invalid against its schema", p.Pointer)
+	}
+	assert.Contains(t, problems[0].Message, "name")
+}
+
+func TestCheckSchemaContents_DanglingRefs(t *testing.T) {
+	problems := checkFixture(t, "dangling-ref.schema.json")
+	assert.Equal(t, []string{
+		"/properties/part/$ref",
+		"/properties/wheel/$ref",
+	}, pointers(problems))
+	assert.Contains(t, problems[0].Message, `"#/$defs/Part"`)
+}
+
+func TestCheckSchemaContents_OrphanedDefinitions(t *testing.T) {
+	problems := checkFixture(t, "orphaned-def.schema.json")
+	assert.Equal(t, []string{
+		"/$defs/AlsoUnused",
+		"/$defs/Unused",
+	}, pointers(problems))
+}
+
+func TestValidateSchema_ServerSchema(t *testing.T) {
+	assert.NoError(t, validateSchema(filepath.Join("..", "..", "docs", "reference", "server-json", "server.schema.json")))
+}